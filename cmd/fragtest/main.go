@@ -5,13 +5,14 @@ import (
 	"log"
 
 	"github.com/elee1766/gobtr/pkg/fragmap"
+	"github.com/elee1766/gobtr/pkg/privsep"
 )
 
 func main() {
 	fsPath := "/mnt/btrfs"
 
 	fmt.Println("Creating scanner for", fsPath)
-	scanner, err := fragmap.NewScanner(fsPath)
+	scanner, err := fragmap.NewScanner(privsep.Direct{}, fsPath)
 	if err != nil {
 		log.Fatalf("Failed to create scanner: %v", err)
 	}
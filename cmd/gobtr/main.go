@@ -1,22 +1,49 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
+	"os/signal"
+	"os/user"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/dustin/go-humanize"
+	"github.com/elee1766/gobtr/pkg/allocsim"
 	"github.com/elee1766/gobtr/pkg/api"
+	"github.com/elee1766/gobtr/pkg/audit"
+	"github.com/elee1766/gobtr/pkg/auth"
 	"github.com/elee1766/gobtr/pkg/btrfs"
+	"github.com/elee1766/gobtr/pkg/collector"
 	"github.com/elee1766/gobtr/pkg/config"
 	"github.com/elee1766/gobtr/pkg/db"
+	"github.com/elee1766/gobtr/pkg/defrag"
+	"github.com/elee1766/gobtr/pkg/doctor"
 	"github.com/elee1766/gobtr/pkg/fragmap"
+	"github.com/elee1766/gobtr/pkg/hub"
+	"github.com/elee1766/gobtr/pkg/privsep"
+	"github.com/elee1766/gobtr/pkg/reconcile"
+	"github.com/elee1766/gobtr/pkg/scheduler"
+	"github.com/elee1766/gobtr/pkg/settings"
+	"github.com/elee1766/gobtr/pkg/support"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"go.uber.org/fx"
 	"go.uber.org/fx/fxevent"
+	"golang.org/x/term"
 )
 
 // CLI is the root command structure
@@ -26,23 +53,49 @@ type CLI struct {
 
 	// Subcommands
 	WebUI      WebUICmd      `cmd:"" help:"Run the web UI server"`
+	Agent      AgentCmd      `cmd:"" help:"Run the server without the web UI, for a hub to collect from"`
 	Subvolumes SubvolumesCmd `cmd:"" name:"subvol" help:"Subvolume operations"`
 	Frag       FragCmd       `cmd:"" help:"Fragmentation analysis"`
+	Doctor     DoctorCmd     `cmd:"" help:"Diagnose filesystem health"`
+	Space      SpaceCmd      `cmd:"" help:"Simulate chunk allocation to find stranded space"`
+	Compsize   CompsizeCmd   `cmd:"" help:"Report disk usage per compression algorithm"`
+	Config     ConfigCmd     `cmd:"" help:"Config file operations"`
+	User       UserCmd       `cmd:"" help:"Manage web UI users"`
+	Token      TokenCmd      `cmd:"" help:"Generate a bearer token for the config file"`
+	Helper     HelperCmd     `cmd:"" help:"Run the privileged helper for an unprivileged web UI server"`
+	Support    SupportCmd    `cmd:"" name:"support-bundle" help:"Write a support bundle to attach to a bug report"`
 }
 
 // WebUICmd runs the web server with UI
 type WebUICmd struct {
-	Address string `short:"a" default:":8147" help:"API server address"`
+	Address  string `short:"a" help:"API server address (default from config file, or :8147)"`
+	ReadOnly bool   `help:"Refuse every RPC that changes anything, and don't run schedules"`
+	Headless bool   `help:"Serve the API only, without the web UI"`
+	Pprof    bool   `help:"Serve /debug/pprof/ to admins"`
 }
 
 func (c *WebUICmd) Run(cli *CLI) error {
 	app := fx.New(
 		fx.Provide(
-			func() *config.Config {
-				cfg := config.New()
-				cfg.APIAddress = c.Address
+			func() (*config.Config, error) {
+				cfg, err := config.New()
+				if err != nil {
+					return nil, err
+				}
+				if c.Address != "" {
+					cfg.APIAddress = c.Address
+				}
+				if c.ReadOnly {
+					cfg.ReadOnly = true
+				}
+				if c.Headless {
+					cfg.Headless = true
+				}
+				if c.Pprof {
+					cfg.Pprof = true
+				}
 				cfg.LogLevel = cli.LogLevel
-				return cfg
+				return cfg, nil
 			},
 			provideLogger,
 		),
@@ -50,15 +103,430 @@ func (c *WebUICmd) Run(cli *CLI) error {
 			return &fxevent.SlogLogger{Logger: log}
 		}),
 		db.Module,
+		settings.Module,
+		privsep.Module,
 		btrfs.Module,
+		defrag.Module,
+		reconcile.Module,
+		auth.Module,
+		audit.Module,
+		hub.Module,
+		support.Module,
+		collector.Module,
 		api.Module,
+		scheduler.Module,
 	)
 
 	app.Run()
 	return nil
 }
 
+// AgentCmd is web-ui without the web UI. A hub lists it in its config file
+// and collects from its API.
+type AgentCmd struct {
+	WebUICmd `embed:""`
+}
+
+func (c *AgentCmd) Run(cli *CLI) error {
+	c.Headless = true
+	return c.WebUICmd.Run(cli)
+}
+
+// ConfigCmd works with the config file
+type ConfigCmd struct {
+	Validate ConfigValidateCmd `cmd:"" help:"Check a config file and the filesystems it declares"`
+}
+
+// ConfigValidateCmd checks a config file without starting the server
+type ConfigValidateCmd struct {
+	File    string `arg:"" optional:"" help:"Config file (default $GOBTR_CONFIG or the one in the config dir)"`
+	Offline bool   `help:"Don't check that declared filesystems are mounted btrfs"`
+}
+
+func (c *ConfigValidateCmd) Run(cli *CLI) error {
+	path := c.File
+	if path == "" {
+		path = config.DefaultConfigFile()
+	}
+	if path == "" {
+		return fmt.Errorf("no config file found (looked for %s)", strings.Join(config.ConfigFileNames, ", "))
+	}
+
+	f, err := config.LoadFile(path)
+	if err != nil {
+		return err
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleRounded)
+	t.SetTitle(path)
+	t.AppendHeader(table.Row{"Path", "Label", "UUID", "Scrub", "Balance"})
+
+	var problems int
+	for _, fs := range f.Filesystems {
+		uuid := "-"
+		if !c.Offline {
+			info, err := btrfs.GetFilesystemInfo(fs.Path)
+			if err != nil {
+				uuid = "error: " + err.Error()
+				problems++
+			} else {
+				uuid = info.UUID
+			}
+		}
+		scrub, balance := "-", "-"
+		if fs.ScrubEvery > 0 {
+			scrub = "every " + fs.ScrubEvery.String()
+		}
+		if fs.BalanceEvery > 0 {
+			balance = fmt.Sprintf("every %s, usage=%d", fs.BalanceEvery, fs.BalanceUsagePercent())
+		}
+		t.AppendRow(table.Row{fs.Path, fs.Label, uuid, scrub, balance})
+	}
+	t.Render()
+
+	if problems > 0 {
+		return fmt.Errorf("%d declared filesystems can't be opened", problems)
+	}
+	fmt.Println("config is valid")
+	return nil
+}
+
+// UserCmd manages local web UI users. It works on the database directly,
+// so it also recovers a server where every admin is locked out.
+type UserCmd struct {
+	Add    UserAddCmd    `cmd:"" help:"Add a user"`
+	List   UserListCmd   `cmd:"" help:"List users"`
+	Passwd UserPasswdCmd `cmd:"" help:"Change a user's password"`
+	Role   UserRoleCmd   `cmd:"" help:"Change a user's role"`
+	Remove UserRemoveCmd `cmd:"" help:"Remove a user"`
+}
+
+// UserAddCmd adds a user, prompting for the password
+type UserAddCmd struct {
+	Username string `arg:"" help:"Username"`
+	Role     string `short:"r" default:"viewer" enum:"viewer,operator,admin" help:"Role (viewer, operator, admin)"`
+}
+
+func (c *UserAddCmd) Run(cli *CLI) error {
+	role, err := auth.ParseRole(c.Role)
+	if err != nil {
+		return err
+	}
+	password, err := readNewPassword()
+	if err != nil {
+		return err
+	}
+	return withAuthenticator(cli, func(a *auth.Authenticator) error {
+		if _, err := a.CreateUser(c.Username, password, role); err != nil {
+			return err
+		}
+		fmt.Printf("added %s (%s)\n", c.Username, role)
+		return nil
+	})
+}
+
+// UserListCmd lists users
+type UserListCmd struct{}
+
+func (c *UserListCmd) Run(cli *CLI) error {
+	return withAuthenticator(cli, func(a *auth.Authenticator) error {
+		users, err := a.ListUsers()
+		if err != nil {
+			return err
+		}
+
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(table.StyleRounded)
+		t.AppendHeader(table.Row{"User", "Role", "Created", "Updated"})
+		for _, u := range users {
+			t.AppendRow(table.Row{u.Username, u.Role, humanize.Time(u.CreatedAt), humanize.Time(u.UpdatedAt)})
+		}
+		t.Render()
+		return nil
+	})
+}
+
+// UserPasswdCmd changes a password, prompting for the new one
+type UserPasswdCmd struct {
+	Username string `arg:"" help:"Username"`
+}
+
+func (c *UserPasswdCmd) Run(cli *CLI) error {
+	password, err := readNewPassword()
+	if err != nil {
+		return err
+	}
+	return withAuthenticator(cli, func(a *auth.Authenticator) error {
+		if err := a.SetPassword(c.Username, password); err != nil {
+			return err
+		}
+		fmt.Printf("changed password for %s and ended their sessions\n", c.Username)
+		return nil
+	})
+}
+
+// UserRoleCmd changes a user's role
+type UserRoleCmd struct {
+	Username string `arg:"" help:"Username"`
+	Role     string `arg:"" enum:"viewer,operator,admin" help:"New role (viewer, operator, admin)"`
+}
+
+func (c *UserRoleCmd) Run(cli *CLI) error {
+	role, err := auth.ParseRole(c.Role)
+	if err != nil {
+		return err
+	}
+	return withAuthenticator(cli, func(a *auth.Authenticator) error {
+		if err := a.SetRole(c.Username, role); err != nil {
+			return err
+		}
+		fmt.Printf("%s is now %s\n", c.Username, role)
+		return nil
+	})
+}
+
+// UserRemoveCmd removes a user
+type UserRemoveCmd struct {
+	Username string `arg:"" help:"Username"`
+}
+
+func (c *UserRemoveCmd) Run(cli *CLI) error {
+	return withAuthenticator(cli, func(a *auth.Authenticator) error {
+		if err := a.DeleteUser(c.Username); err != nil {
+			return err
+		}
+		fmt.Printf("removed %s\n", c.Username)
+		return nil
+	})
+}
+
+// withAuthenticator opens the database and runs fn with an Authenticator
+// on it, without starting the server
+func withAuthenticator(cli *CLI, fn func(*auth.Authenticator) error) error {
+	var a *auth.Authenticator
+	app := fx.New(
+		fx.Provide(
+			func() (*config.Config, error) {
+				cfg, err := config.New()
+				if err != nil {
+					return nil, err
+				}
+				return cfg, nil
+			},
+			// Only warnings, on stderr, so they don't mix with the output
+			func() *slog.Logger {
+				level := slog.LevelWarn
+				if cli.LogLevel == "debug" {
+					level = slog.LevelDebug
+				}
+				return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+			},
+			auth.New,
+		),
+		fx.NopLogger,
+		db.Module,
+		fx.Populate(&a),
+	)
+	if err := app.Err(); err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if err := app.Start(ctx); err != nil {
+		return err
+	}
+	defer app.Stop(ctx)
+	return fn(a)
+}
+
+// SupportCmd writes a support bundle from the config, database and
+// filesystems, without a running server
+type SupportCmd struct {
+	Output  string `short:"o" help:"File to write, - for stdout (default gobtr-support-<host>-<time>.tar.gz)"`
+	History int    `default:"20" help:"Balance records and errors to include"`
+}
+
+func (c *SupportCmd) Run(cli *CLI) error {
+	var b *support.Bundler
+	app := fx.New(
+		fx.Provide(
+			config.New,
+			// Only warnings, on stderr, so they don't mix with a bundle on stdout
+			func() *slog.Logger {
+				level := slog.LevelWarn
+				if cli.LogLevel == "debug" {
+					level = slog.LevelDebug
+				}
+				return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+			},
+			cliBackend,
+			support.New,
+		),
+		fx.NopLogger,
+		db.Module,
+		btrfs.Module,
+		fx.Populate(&b),
+	)
+	if err := app.Err(); err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if err := app.Start(ctx); err != nil {
+		return err
+	}
+	defer app.Stop(ctx)
+
+	opts := support.Options{History: c.History}
+	if c.Output == "-" {
+		return b.Write(os.Stdout, opts)
+	}
+	name := c.Output
+	if name == "" {
+		name = support.Filename(time.Now())
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := b.Write(f, opts); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "wrote %s\n", name)
+	return nil
+}
+
+// readNewPassword reads a password twice from the terminal, or once from
+// stdin when it isn't a terminal so scripts can pipe it in
+func readNewPassword() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("read password: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fmt.Fprint(os.Stderr, "password: ")
+	first, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprint(os.Stderr, "again: ")
+	second, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if string(first) != string(second) {
+		return "", fmt.Errorf("passwords don't match")
+	}
+	return string(first), nil
+}
+
+// TokenCmd generates a random bearer token and the config entry for it
+type TokenCmd struct {
+	Name string `arg:"" help:"Name the token is logged as"`
+	Role string `short:"r" default:"viewer" enum:"viewer,operator,admin" help:"Role (viewer, operator, admin)"`
+}
+
+func (c *TokenCmd) Run(cli *CLI) error {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	sum := sha256.Sum256([]byte(token))
+
+	fmt.Fprintf(os.Stderr, "token (shown once, send it as \"Authorization: Bearer <token>\"):\n")
+	fmt.Println(token)
+	fmt.Fprintf(os.Stderr, "\nadd to the config file:\n\n")
+	fmt.Fprintf(os.Stderr, "[[auth.token]]\nname = %q\nrole = %q\ntoken_sha256 = %q\n", c.Name, c.Role, hex.EncodeToString(sum[:]))
+	return nil
+}
+
 // SubvolumesCmd contains subvolume subcommands
+// HelperCmd runs as root and performs the privileged operations of a web UI
+// server running as another user
+type HelperCmd struct {
+	Socket string `default:"${helper_socket}" help:"Socket to listen on"`
+	User   string `required:"" help:"User (name or uid) the web UI server runs as"`
+	DB     string `help:"Database of the web UI server; only its tracked filesystems are served (default: the user's ~/.local/share/gobtr/gobtr.db)"`
+}
+
+func (c *HelperCmd) Run(cli *CLI) error {
+	uid, err := lookupUID(c.User)
+	if err != nil {
+		return err
+	}
+
+	dbPath := c.DB
+	if dbPath == "" {
+		u, err := user.LookupId(strconv.Itoa(uid))
+		if err != nil {
+			return err
+		}
+		dbPath = filepath.Join(u.HomeDir, ".local", "share", config.AppName, "gobtr.db")
+	}
+
+	logger := makeLogger(cli.LogLevel)
+	database, err := db.OpenReadOnly(logger, dbPath)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", dbPath, err)
+	}
+	defer database.Close()
+
+	h := privsep.NewHelper(logger, uid, func() ([]string, error) {
+		filesystems, err := database.ListFilesystems()
+		if err != nil {
+			return nil, err
+		}
+		paths := make([]string, 0, len(filesystems))
+		for _, fs := range filesystems {
+			paths = append(paths, fs.Path)
+		}
+		return paths, nil
+	})
+	l, err := h.Listen(c.Socket)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", c.Socket, err)
+	}
+	defer os.Remove(c.Socket)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("helper listening", "socket", c.Socket, "uid", uid, "db", dbPath)
+	return h.Serve(ctx, l)
+}
+
+// lookupUID resolves a user name or numeric uid
+func lookupUID(name string) (int, error) {
+	u, err := user.Lookup(name)
+	if err != nil {
+		if u, err = user.LookupId(name); err != nil {
+			return 0, fmt.Errorf("unknown user %q", name)
+		}
+	}
+	return strconv.Atoi(u.Uid)
+}
+
+// cliBackend is how commands do privileged operations: through the helper
+// when GOBTR_HELPER names its socket, and in process otherwise
+func cliBackend() privsep.Backend {
+	if path := os.Getenv("GOBTR_HELPER"); path != "" {
+		return privsep.NewClient(path)
+	}
+	return privsep.Direct{}
+}
+
 type SubvolumesCmd struct {
 	List SubvolListCmd `cmd:"" help:"List subvolumes"`
 	Show SubvolShowCmd `cmd:"" help:"Show subvolume details"`
@@ -70,7 +538,7 @@ type SubvolListCmd struct {
 }
 
 func (c *SubvolListCmd) Run(cli *CLI) error {
-	mgr := btrfs.New(makeLogger(cli.LogLevel))
+	mgr := btrfs.New(makeLogger(cli.LogLevel), cliBackend())
 	subvols, err := mgr.ListSubvolumes(c.Path)
 	if err != nil {
 		return fmt.Errorf("failed to list subvolumes: %w", err)
@@ -98,7 +566,7 @@ type SubvolShowCmd struct {
 }
 
 func (c *SubvolShowCmd) Run(cli *CLI) error {
-	mgr := btrfs.New(makeLogger(cli.LogLevel))
+	mgr := btrfs.New(makeLogger(cli.LogLevel), cliBackend())
 	sv, err := mgr.GetSubvolumeInfo(c.Path)
 	if err != nil {
 		return fmt.Errorf("failed to get subvolume info: %w", err)
@@ -125,15 +593,20 @@ func (c *SubvolShowCmd) Run(cli *CLI) error {
 
 // FragCmd contains fragmentation analysis subcommands
 type FragCmd struct {
-	File FragFileCmd `cmd:"" help:"Analyze file fragmentation"`
-	FS   FragFSCmd   `cmd:"" name:"fs" help:"Analyze filesystem free-space fragmentation"`
+	File   FragFileCmd   `cmd:"" help:"Analyze file fragmentation"`
+	FS     FragFSCmd     `cmd:"" name:"fs" help:"Analyze filesystem free-space fragmentation"`
+	Defrag FragDefragCmd `cmd:"" help:"Defragment the most fragmented files"`
 }
 
 // FragFileCmd analyzes file fragmentation
 type FragFileCmd struct {
-	Path    string `arg:"" help:"File or directory path to analyze"`
-	Recurse bool   `short:"r" help:"Recursively analyze directory"`
-	Top     int    `short:"n" default:"20" help:"Show top N most fragmented files"`
+	Path          string   `arg:"" help:"File or directory path to analyze"`
+	Recurse       bool     `short:"r" help:"Recursively analyze directory"`
+	Top           int      `short:"n" default:"20" help:"Show top N most fragmented files"`
+	Workers       int      `short:"j" help:"Files to analyze in parallel (default: number of CPUs)"`
+	Exclude       []string `short:"e" help:"Skip files and directories matching this glob (repeatable)"`
+	OneFileSystem bool     `short:"x" help:"Don't descend into other filesystems or subvolumes"`
+	Checkpoint    string   `help:"Save progress to this file and resume from it if it exists"`
 }
 
 func (c *FragFileCmd) Run(cli *CLI) error {
@@ -144,7 +617,7 @@ func (c *FragFileCmd) Run(cli *CLI) error {
 
 	if !info.IsDir() {
 		// Single file analysis
-		frag, err := fragmap.AnalyzeFileFragmentation(c.Path)
+		frag, err := fragmap.AnalyzeFileFragmentation(cliBackend(), c.Path)
 		if err != nil {
 			return fmt.Errorf("analyze file: %w", err)
 		}
@@ -152,41 +625,45 @@ func (c *FragFileCmd) Run(cli *CLI) error {
 		return nil
 	}
 
-	// Directory analysis
-	var files []*fragmap.FileFragInfo
-	walkFn := func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil // Skip errors
-		}
-		if d.IsDir() {
-			if !c.Recurse && path != c.Path {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-		if !d.Type().IsRegular() {
-			return nil
-		}
+	// Directory analysis. Ctrl-C stops the scan, saving the checkpoint.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-		frag, err := fragmap.AnalyzeFileFragmentation(path)
-		if err != nil {
-			return nil // Skip files we can't analyze
-		}
-		files = append(files, frag)
-		return nil
+	opts := fragmap.DirScanOptions{
+		Workers:       c.Workers,
+		Exclude:       c.Exclude,
+		OneFileSystem: c.OneFileSystem,
+		Top:           c.Top,
+		Checkpoint:    c.Checkpoint,
+		Backend:       cliBackend(),
+		Progress: func(p fragmap.DirScanProgress) {
+			fmt.Fprintf(os.Stderr, "\r\033[K%d files, %s, %d errors",
+				p.FilesScanned, humanize.IBytes(uint64(p.BytesScanned)), p.Errors)
+		},
+	}
+	if !c.Recurse {
+		opts.MaxDepth = 1
 	}
 
-	if err := filepath.WalkDir(c.Path, walkFn); err != nil {
-		return fmt.Errorf("walk directory: %w", err)
+	res, err := fragmap.ScanDirectory(ctx, c.Path, opts)
+	fmt.Fprint(os.Stderr, "\r\033[K")
+	if err != nil {
+		if ctx.Err() != nil && c.Checkpoint != "" {
+			return fmt.Errorf("scan interrupted, run again with --checkpoint %s to resume", c.Checkpoint)
+		}
+		return fmt.Errorf("scan directory: %w", err)
 	}
 
-	if len(files) == 0 {
+	if res.Stats.TotalFiles == 0 {
 		fmt.Println("No files found to analyze")
 		return nil
 	}
+	if res.Resumed {
+		fmt.Printf("Resumed from %s\n\n", c.Checkpoint)
+	}
 
 	// Print aggregate stats
-	stats := fragmap.AggregateFileFragmentation(files)
+	stats := res.Stats
 
 	t := table.NewWriter()
 	t.SetOutputMirror(os.Stdout)
@@ -203,6 +680,15 @@ func (c *FragFileCmd) Run(cli *CLI) error {
 	t.AppendRow(table.Row{"Avg Out-of-Order%", fmt.Sprintf("%.1f%%", stats.AvgOutOfOrderPct)})
 	t.AppendRow(table.Row{"Max DoF", fmt.Sprintf("%.2f", stats.MaxDoF)})
 	t.AppendRow(table.Row{"Max extents", stats.MaxExtents})
+	t.AppendSeparator()
+	t.AppendRow(table.Row{"Compressed files", stats.CompressedFiles})
+	t.AppendRow(table.Row{"Compressed extents", fmt.Sprintf("%d (%s)", stats.CompressedExtents, humanize.IBytes(uint64(stats.CompressedBytes)))})
+	t.AppendRow(table.Row{"Shared extents", fmt.Sprintf("%d (%s)", stats.SharedExtents, humanize.IBytes(uint64(stats.SharedBytes)))})
+	t.AppendRow(table.Row{"Inline extents", stats.InlineExtents})
+	t.AppendRow(table.Row{"Preallocated extents", stats.UnwrittenExtents})
+	if stats.Usage.ReferencedBytes > 0 {
+		appendUsageRows(t, stats.Usage)
+	}
 	t.Render()
 
 	fmt.Println()
@@ -223,15 +709,7 @@ func (c *FragFileCmd) Run(cli *CLI) error {
 	fmt.Println()
 
 	// Print top fragmented files (only those with DoF > 1.0)
-	fragmap.SortFilesByDoF(files)
-
-	// Filter to only fragmented files
-	var fragmented []*fragmap.FileFragInfo
-	for _, f := range files {
-		if f.DoF > 1.0 {
-			fragmented = append(fragmented, f)
-		}
-	}
+	fragmented := res.Top
 
 	if len(fragmented) == 0 {
 		fmt.Println("\nNo fragmented files found (all files have ideal DoF = 1.0)")
@@ -265,9 +743,31 @@ func (c *FragFileCmd) Run(cli *CLI) error {
 		top.Render()
 	}
 
+	if res.Errors > 0 {
+		fmt.Printf("\n%d files could not be analyzed:\n", res.Errors)
+		for i, e := range res.ErrorSamples {
+			if i >= 10 {
+				fmt.Printf("  ... and %d more\n", res.Errors-i)
+				break
+			}
+			fmt.Printf("  %s: %s\n", e.Path, e.Err)
+		}
+	}
+
 	return nil
 }
 
+// appendUsageRows adds on-disk usage and compression rows to a table
+func appendUsageRows(t table.Writer, u *fragmap.ExtentUsage) {
+	t.AppendSeparator()
+	t.AppendRow(table.Row{"On disk", humanize.IBytes(u.DiskBytes)})
+	t.AppendRow(table.Row{"Uncompressed", humanize.IBytes(u.UncompressedBytes)})
+	if ratio := u.CompressionRatio(); ratio > 0 {
+		t.AppendRow(table.Row{"Compressed data", fmt.Sprintf("%s -> %s on disk (%.2fx)",
+			humanize.IBytes(u.CompressedUncompressedBytes), humanize.IBytes(u.CompressedDiskBytes), ratio)})
+	}
+}
+
 func printFileFragInfo(f *fragmap.FileFragInfo) {
 	t := table.NewWriter()
 	t.SetOutputMirror(os.Stdout)
@@ -277,6 +777,14 @@ func printFileFragInfo(f *fragmap.FileFragInfo) {
 	t.AppendRow(table.Row{"Size", humanize.IBytes(uint64(f.Size))})
 	t.AppendRow(table.Row{"Extents", f.ExtentCount})
 	t.AppendRow(table.Row{"Ideal extents", f.IdealExtents})
+	t.AppendSeparator()
+	t.AppendRow(table.Row{"Compressed extents", fmt.Sprintf("%d (%s)", f.CompressedExtents, humanize.IBytes(uint64(f.CompressedBytes)))})
+	t.AppendRow(table.Row{"Shared extents", fmt.Sprintf("%d (%s)", f.SharedExtents, humanize.IBytes(uint64(f.SharedBytes)))})
+	t.AppendRow(table.Row{"Inline extents", f.InlineExtents})
+	t.AppendRow(table.Row{"Preallocated extents", f.UnwrittenExtents})
+	if f.Usage != nil {
+		appendUsageRows(t, f.Usage)
+	}
 	t.Render()
 
 	fmt.Println()
@@ -298,11 +806,44 @@ func printFileFragInfo(f *fragmap.FileFragInfo) {
 
 // FragFSCmd analyzes filesystem free-space fragmentation
 type FragFSCmd struct {
-	Path string `arg:"" help:"Path to btrfs filesystem mount point"`
+	Path      string `arg:"" help:"Path to btrfs filesystem mount point"`
+	FreeSpace bool   `help:"Also analyze free space inside block groups (slow without a free space tree)"`
+
+	Render           string `help:"Also render the layout to this .png or .svg file"`
+	RenderKind       string `default:"layout" enum:"layout,heatmap" help:"What to render: layout or heatmap"`
+	RenderWidth      int    `default:"1024" help:"Rendered image width in pixels"`
+	RenderResolution int    `default:"1024" help:"Heat map cells per device"`
+	RenderLayout     string `default:"linear" enum:"linear,row_major,hilbert" help:"Heat map cell layout: linear, row_major or hilbert"`
+}
+
+func (c *FragFSCmd) renderImage(fm *fragmap.FragMap) error {
+	format, err := fragmap.RenderFormatFromPath(c.Render)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(c.Render)
+	if err != nil {
+		return fmt.Errorf("create image: %w", err)
+	}
+	err = fragmap.Render(f, fm, fragmap.RenderOptions{
+		Kind:       fragmap.RenderKind(c.RenderKind),
+		Format:     format,
+		Width:      c.RenderWidth,
+		Resolution: c.RenderResolution,
+		Layout:     fragmap.HeatMapLayout(c.RenderLayout),
+	})
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("render: %w", err)
+	}
+	fmt.Printf("Wrote %s\n\n", c.Render)
+	return nil
 }
 
 func (c *FragFSCmd) Run(cli *CLI) error {
-	scanner, err := fragmap.NewScanner(c.Path)
+	scanner, err := fragmap.NewScanner(cliBackend(), c.Path)
 	if err != nil {
 		return fmt.Errorf("create scanner: %w", err)
 	}
@@ -460,6 +1001,71 @@ func (c *FragFSCmd) Run(cli *CLI) error {
 		fmt.Println()
 	}
 
+	if c.Render != "" {
+		if err := c.renderImage(fm); err != nil {
+			return err
+		}
+	}
+
+	if c.FreeSpace {
+		return printFreeSpace(scanner, fm)
+	}
+
+	return nil
+}
+
+// printFreeSpace prints free-run histograms for the space inside block groups
+func printFreeSpace(scanner *fragmap.Scanner, fm *fragmap.FragMap) error {
+	blockGroups, source, err := scanner.ScanFreeSpace(fm.Chunks)
+	if err != nil {
+		return fmt.Errorf("scan free space: %w", err)
+	}
+
+	for _, bt := range []struct {
+		typ  fragmap.BlockType
+		name string
+	}{
+		{fragmap.BlockTypeData, "Data"},
+		{fragmap.BlockTypeMetadata, "Metadata"},
+	} {
+		sum := fragmap.SummarizeFreeSpace(blockGroups, bt.typ)
+		if sum.BlockGroups == 0 {
+			continue
+		}
+
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(table.StyleRounded)
+		t.SetTitle(fmt.Sprintf("Free Space Inside %s Block Groups (%s)", bt.name, source))
+		t.AppendHeader(table.Row{"Run Size", "Runs", "Bytes", "% of Free"})
+		t.SetColumnConfigs([]table.ColumnConfig{
+			{Number: 2, Align: text.AlignRight},
+			{Number: 3, Align: text.AlignRight},
+			{Number: 4, Align: text.AlignRight},
+		})
+		lower := "0"
+		for i := range sum.Histogram.Counts {
+			label := "≥" + lower
+			if i < len(fragmap.FreeSpaceBucketBounds) {
+				upper := humanize.IBytes(fragmap.FreeSpaceBucketBounds[i])
+				label = lower + " - " + upper
+				lower = upper
+			}
+			var pct float64
+			if sum.FreeBytes > 0 {
+				pct = float64(sum.Histogram.Bytes[i]) / float64(sum.FreeBytes) * 100
+			}
+			t.AppendRow(table.Row{label, sum.Histogram.Counts[i], humanize.IBytes(sum.Histogram.Bytes[i]), fmt.Sprintf("%.1f%%", pct)})
+		}
+		t.AppendSeparator()
+		t.AppendRow(table.Row{"Block groups", sum.BlockGroups})
+		t.AppendRow(table.Row{"Free", fmt.Sprintf("%s in %d runs", humanize.IBytes(sum.FreeBytes), sum.FreeRuns)})
+		t.AppendRow(table.Row{"Largest free run", humanize.IBytes(sum.LargestFree)})
+		t.AppendRow(table.Row{"Fragmented score", fmt.Sprintf("%.0f/100", sum.FragScore)})
+		t.Render()
+		fmt.Println()
+	}
+
 	return nil
 }
 
@@ -471,12 +1077,335 @@ func statusWarn() string {
 	return "WARNING"
 }
 
+// FragDefragCmd defragments files picked by fragmentation analysis
+type FragDefragCmd struct {
+	Paths           []string `arg:"" optional:"" help:"Files or directories (searched recursively)"`
+	From            string   `help:"Read paths from this file, one per line (- for stdin)"`
+	MinDoF          float64  `name:"min-dof" help:"Only files with at least this degree of fragmentation"`
+	MinExtents      int      `help:"Only files with at least this many extents"`
+	ExtentThreshold string   `short:"t" help:"Leave extents at least this large alone (e.g. 32M)"`
+	Compress        string   `short:"c" enum:",zlib,lzo,zstd" default:"" help:"Recompress with zlib, lzo or zstd"`
+	Limit           string   `help:"Throughput limit per second (e.g. 50M)"`
+	AllowShared     bool     `help:"Defragment files with shared extents (unshares them from snapshots and reflinks)"`
+	DryRun          bool     `short:"n" help:"Select files without defragmenting them"`
+}
+
+func (c *FragDefragCmd) Run(cli *CLI) error {
+	paths := c.Paths
+	if c.From != "" {
+		from, err := readPathList(c.From)
+		if err != nil {
+			return fmt.Errorf("read paths: %w", err)
+		}
+		paths = append(paths, from...)
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no paths given")
+	}
+
+	opts := defrag.Options{
+		Paths:       paths,
+		MinDoF:      c.MinDoF,
+		MinExtents:  c.MinExtents,
+		Compress:    c.Compress,
+		AllowShared: c.AllowShared,
+		DryRun:      c.DryRun,
+	}
+	if c.ExtentThreshold != "" {
+		thresh, err := humanize.ParseBytes(c.ExtentThreshold)
+		if err != nil {
+			return fmt.Errorf("parse extent threshold: %w", err)
+		}
+		opts.ExtentThreshold = uint32(min(thresh, 1<<32-1))
+	}
+	if c.Limit != "" {
+		limit, err := humanize.ParseBytes(c.Limit)
+		if err != nil {
+			return fmt.Errorf("parse limit: %w", err)
+		}
+		opts.LimitBytesPerSec = int64(limit)
+	}
+
+	mgr := defrag.New(makeLogger(cli.LogLevel), cliBackend())
+	id, err := mgr.Start(opts)
+	if err != nil {
+		return err
+	}
+
+	// Ctrl-C cancels the job after the current range
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+	defer signal.Stop(sigs)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var p *defrag.Progress
+	for {
+		p, err = mgr.Get(id)
+		if err != nil {
+			return err
+		}
+		if !p.IsRunning() {
+			break
+		}
+		fmt.Fprintf(os.Stderr, "\r\033[K%s: %d scanned, %d/%d files, %s/%s",
+			p.Status, p.FilesScanned, p.FilesDone, p.FilesSelected,
+			humanize.IBytes(uint64(p.BytesDone)), humanize.IBytes(uint64(p.BytesTotal)))
+		select {
+		case <-sigs:
+			mgr.Cancel(id)
+		case <-ticker.C:
+		}
+	}
+	fmt.Fprint(os.Stderr, "\r\033[K")
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleRounded)
+	t.SetTitle("Defrag")
+	t.AppendRow(table.Row{"Status", p.Status})
+	if p.Error != "" {
+		t.AppendRow(table.Row{"Error", p.Error})
+	}
+	t.AppendRow(table.Row{"Files Scanned", p.FilesScanned})
+	t.AppendRow(table.Row{"Files Selected", fmt.Sprintf("%d (%s)", p.FilesSelected, humanize.IBytes(uint64(p.BytesTotal)))})
+	if p.FilesSkippedShared > 0 {
+		t.AppendRow(table.Row{"Skipped (shared)", fmt.Sprintf("%d (use --allow-shared to include)", p.FilesSkippedShared)})
+	}
+	if !c.DryRun {
+		t.AppendRow(table.Row{"Files Defragmented", p.FilesDone})
+		t.AppendRow(table.Row{"Files Failed", p.FilesFailed})
+		t.AppendRow(table.Row{"Extents", fmt.Sprintf("%d -> %d", p.ExtentsBefore, p.ExtentsAfter)})
+		t.AppendRow(table.Row{"Duration", p.FinishedAt.Sub(p.StartedAt).Round(time.Second)})
+	}
+	t.Render()
+
+	if p.Status == defrag.StatusFailed {
+		return fmt.Errorf("defrag failed: %s", p.Error)
+	}
+	return nil
+}
+
+// readPathList reads one path per line, skipping blank lines
+func readPathList(name string) ([]string, error) {
+	f := os.Stdin
+	if name != "-" {
+		var err error
+		f, err = os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+	}
+
+	var paths []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if line := strings.TrimSpace(sc.Text()); line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths, sc.Err()
+}
+
+// DoctorCmd runs the diagnostics rule set against a filesystem
+type DoctorCmd struct {
+	Path             string  `arg:"" help:"Path to btrfs filesystem mount point"`
+	ScrubMaxAge      int     `default:"30" help:"Warn when the last scrub is older than this many days"`
+	SlackPercent     float64 `default:"25" help:"Warn when unused space inside data chunks exceeds this percent"`
+	ImbalancePercent float64 `default:"25" help:"Warn when device allocation differs by more than this many percent"`
+}
+
+func (c *DoctorCmd) Run(cli *CLI) error {
+	mgr := btrfs.New(makeLogger(cli.LogLevel), cliBackend())
+	report, err := doctor.Diagnose(mgr, c.Path, doctor.Thresholds{
+		ScrubMaxAge:      time.Duration(c.ScrubMaxAge) * 24 * time.Hour,
+		SlackPercent:     c.SlackPercent,
+		ImbalancePercent: c.ImbalancePercent,
+	})
+	if err != nil {
+		return fmt.Errorf("run diagnostics: %w", err)
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleRounded)
+	t.SetTitle("Filesystem Health")
+	t.AppendRow(table.Row{"Path", report.Path})
+	t.AppendRow(table.Row{"UUID", report.UUID})
+	t.AppendRow(table.Row{"Score", fmt.Sprintf("%d/100", report.Score)})
+	t.AppendRow(table.Row{"Findings", len(report.Findings)})
+	t.Render()
+
+	if len(report.Findings) == 0 {
+		fmt.Println("\nNo problems found")
+		return nil
+	}
+
+	fmt.Println()
+
+	ft := table.NewWriter()
+	ft.SetOutputMirror(os.Stdout)
+	ft.SetStyle(table.StyleRounded)
+	ft.SetTitle("Findings")
+	ft.AppendHeader(table.Row{"Severity", "Rule", "Finding"})
+	ft.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 3, WidthMax: 80, WidthMaxEnforcer: text.WrapSoft},
+	})
+	for i, f := range report.Findings {
+		if i > 0 {
+			ft.AppendSeparator()
+		}
+		msg := f.Title
+		if f.Detail != "" {
+			msg += "\n" + f.Detail
+		}
+		if f.Action != "" {
+			msg += "\n-> " + f.Action
+		}
+		ft.AppendRow(table.Row{f.Severity, f.Rule, msg})
+	}
+	ft.Render()
+
+	return nil
+}
+
+// SpaceCmd simulates the chunk allocator over the current device layout
+type SpaceCmd struct {
+	Path    string `arg:"" help:"Path to btrfs filesystem mount point"`
+	Type    string `default:"data" enum:"data,metadata" help:"Chunk type to analyze (data, metadata)"`
+	Profile string `help:"Simulate this profile instead of the current one (e.g. raid1c3)"`
+}
+
+func (c *SpaceCmd) Run(cli *CLI) error {
+	in, err := allocsim.Gather(cliBackend(), c.Path, c.Type, c.Profile)
+	if err != nil {
+		return fmt.Errorf("gather allocation info: %w", err)
+	}
+	res := allocsim.Analyze(in)
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleRounded)
+	t.SetTitle(fmt.Sprintf("%s as %s", res.Type, res.Profile))
+	t.AppendRow(table.Row{"Unallocated", humanize.IBytes(res.Unallocated)})
+	t.AppendRow(table.Row{"Usable now", humanize.IBytes(res.Usable)})
+	t.AppendRow(table.Row{"Stranded now", humanize.IBytes(res.Stranded)})
+	t.AppendRow(table.Row{"Usable after full balance", humanize.IBytes(res.BalancedUsable)})
+	t.AppendRow(table.Row{"Stranded after full balance", humanize.IBytes(res.BalancedStranded)})
+	t.AppendRow(table.Row{"Recoverable", humanize.IBytes(res.Recoverable)})
+	t.Render()
+
+	fmt.Println()
+
+	dt := table.NewWriter()
+	dt.SetOutputMirror(os.Stdout)
+	dt.SetStyle(table.StyleRounded)
+	dt.SetTitle("Devices")
+	dt.AppendHeader(table.Row{"Devid", "Path", "Size", "Allocated", "Unallocated", "Stranded", "Stranded (balanced)"})
+	for _, d := range res.Devices {
+		dt.AppendRow(table.Row{
+			d.DevID, d.Path,
+			humanize.IBytes(d.Size), humanize.IBytes(d.Allocated), humanize.IBytes(d.Unallocated),
+			humanize.IBytes(d.Stranded), humanize.IBytes(d.BalancedStranded),
+		})
+	}
+	dt.Render()
+
+	if len(res.Suggestions) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+
+	flag := "-d"
+	if res.Type == "Metadata" {
+		flag = "-m"
+	}
+
+	st := table.NewWriter()
+	st.SetOutputMirror(os.Stdout)
+	st.SetStyle(table.StyleRounded)
+	st.SetTitle("Suggested Balances")
+	st.AppendHeader(table.Row{"Recovers", "Command", "Alternative"})
+	for _, sg := range res.Suggestions {
+		st.AppendRow(table.Row{
+			humanize.IBytes(sg.Recovers),
+			fmt.Sprintf("btrfs balance start %sdevid=%d,limit=%d %s", flag, sg.DevID, sg.Limit, c.Path),
+			fmt.Sprintf("%sdevid=%d,drange=%d..%d (%d chunks)", flag, sg.DevID, sg.DrangeStart, sg.DrangeEnd, sg.DrangeChunks),
+		})
+	}
+	st.Render()
+
+	return nil
+}
+
+// CompsizeCmd reports how well files under a path compress, like compsize
+type CompsizeCmd struct {
+	Path          string `arg:"" help:"File or directory to analyze"`
+	OneFileSystem bool   `short:"x" help:"Don't descend into other filesystems or subvolumes"`
+}
+
+func (c *CompsizeCmd) Run(cli *CLI) error {
+	report, err := fragmap.Compsize(context.Background(), c.Path, fragmap.CompsizeOptions{
+		OneFileSystem: c.OneFileSystem,
+		Backend:       cliBackend(),
+	})
+	if err != nil {
+		return fmt.Errorf("compsize: %w", err)
+	}
+	if report.Files == 0 && report.Errors > 0 {
+		return fmt.Errorf("could not read extents of any file (not on btrfs, or not running as root?)")
+	}
+
+	perc := func(disk, uncompressed uint64) string {
+		if uncompressed == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.0f%%", float64(disk)/float64(uncompressed)*100)
+	}
+
+	u := report.Usage
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleRounded)
+	t.SetTitle(fmt.Sprintf("%s (%d files)", report.Path, report.Files))
+	t.AppendHeader(table.Row{"Type", "Perc", "Disk Usage", "Uncompressed", "Referenced"})
+	t.AppendRow(table.Row{
+		"TOTAL", perc(u.DiskBytes, u.UncompressedBytes),
+		humanize.IBytes(u.DiskBytes), humanize.IBytes(u.UncompressedBytes), humanize.IBytes(u.ReferencedBytes),
+	})
+	t.AppendSeparator()
+	for _, comp := range slices.Sorted(maps.Keys(u.ByCompression)) {
+		cu := u.ByCompression[comp]
+		t.AppendRow(table.Row{
+			fragmap.CompressionName(comp), perc(cu.DiskBytes, cu.UncompressedBytes),
+			humanize.IBytes(cu.DiskBytes), humanize.IBytes(cu.UncompressedBytes), humanize.IBytes(cu.ReferencedBytes),
+		})
+	}
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 2, Align: text.AlignRight},
+		{Number: 3, Align: text.AlignRight},
+		{Number: 4, Align: text.AlignRight},
+		{Number: 5, Align: text.AlignRight},
+	})
+	t.Render()
+
+	if report.Errors > 0 {
+		fmt.Printf("%d files could not be read\n", report.Errors)
+	}
+
+	return nil
+}
+
 func main() {
 	cli := &CLI{}
 	ctx := kong.Parse(cli,
 		kong.Name("gobtr"),
 		kong.Description("BTRFS management tool"),
 		kong.UsageOnError(),
+		kong.Vars{"helper_socket": privsep.DefaultSocket},
 	)
 	err := ctx.Run(cli)
 	ctx.FatalIfErrorf(err)
@@ -48,6 +48,18 @@ const (
 	// BalanceServiceListBalanceHistoryProcedure is the fully-qualified name of the BalanceService's
 	// ListBalanceHistory RPC.
 	BalanceServiceListBalanceHistoryProcedure = "/api.v1.BalanceService/ListBalanceHistory"
+	// BalanceServiceGetProfileStatusProcedure is the fully-qualified name of the BalanceService's
+	// GetProfileStatus RPC.
+	BalanceServiceGetProfileStatusProcedure = "/api.v1.BalanceService/GetProfileStatus"
+	// BalanceServiceFinishConversionProcedure is the fully-qualified name of the BalanceService's
+	// FinishConversion RPC.
+	BalanceServiceFinishConversionProcedure = "/api.v1.BalanceService/FinishConversion"
+	// BalanceServiceAnalyzeAllocationProcedure is the fully-qualified name of the BalanceService's
+	// AnalyzeAllocation RPC.
+	BalanceServiceAnalyzeAllocationProcedure = "/api.v1.BalanceService/AnalyzeAllocation"
+	// BalanceServicePlanBalanceProcedure is the fully-qualified name of the BalanceService's
+	// PlanBalance RPC.
+	BalanceServicePlanBalanceProcedure = "/api.v1.BalanceService/PlanBalance"
 )
 
 // BalanceServiceClient is a client for the api.v1.BalanceService service.
@@ -57,6 +69,18 @@ type BalanceServiceClient interface {
 	GetBalanceStatus(context.Context, *connect.Request[v1.GetBalanceStatusRequest]) (*connect.Response[v1.GetBalanceStatusResponse], error)
 	GetAllBalanceStatus(context.Context, *connect.Request[v1.GetAllBalanceStatusRequest]) (*connect.Response[v1.GetAllBalanceStatusResponse], error)
 	ListBalanceHistory(context.Context, *connect.Request[v1.ListBalanceHistoryRequest]) (*connect.Response[v1.ListBalanceHistoryResponse], error)
+	// GetProfileStatus reports chunk counts per profile and any stale profiles
+	// left over from an interrupted conversion
+	GetProfileStatus(context.Context, *connect.Request[v1.GetProfileStatusRequest]) (*connect.Response[v1.GetProfileStatusResponse], error)
+	// FinishConversion starts a soft convert balance that relocates only the stale chunks
+	FinishConversion(context.Context, *connect.Request[v1.FinishConversionRequest]) (*connect.Response[v1.FinishConversionResponse], error)
+	// AnalyzeAllocation simulates the chunk allocator over the current device
+	// sizes and allocations to find stranded space and the balances that free it
+	AnalyzeAllocation(context.Context, *connect.Request[v1.AnalyzeAllocationRequest]) (*connect.Response[v1.AnalyzeAllocationResponse], error)
+	// PlanBalance simulates usage= balances from block group usage and
+	// recommends the cheapest one that reaches an unallocated space goal.
+	// With start set, the recommended balance is started right away.
+	PlanBalance(context.Context, *connect.Request[v1.PlanBalanceRequest]) (*connect.Response[v1.PlanBalanceResponse], error)
 }
 
 // NewBalanceServiceClient constructs a client for the api.v1.BalanceService service. By default, it
@@ -100,6 +124,30 @@ func NewBalanceServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(balanceServiceMethods.ByName("ListBalanceHistory")),
 			connect.WithClientOptions(opts...),
 		),
+		getProfileStatus: connect.NewClient[v1.GetProfileStatusRequest, v1.GetProfileStatusResponse](
+			httpClient,
+			baseURL+BalanceServiceGetProfileStatusProcedure,
+			connect.WithSchema(balanceServiceMethods.ByName("GetProfileStatus")),
+			connect.WithClientOptions(opts...),
+		),
+		finishConversion: connect.NewClient[v1.FinishConversionRequest, v1.FinishConversionResponse](
+			httpClient,
+			baseURL+BalanceServiceFinishConversionProcedure,
+			connect.WithSchema(balanceServiceMethods.ByName("FinishConversion")),
+			connect.WithClientOptions(opts...),
+		),
+		analyzeAllocation: connect.NewClient[v1.AnalyzeAllocationRequest, v1.AnalyzeAllocationResponse](
+			httpClient,
+			baseURL+BalanceServiceAnalyzeAllocationProcedure,
+			connect.WithSchema(balanceServiceMethods.ByName("AnalyzeAllocation")),
+			connect.WithClientOptions(opts...),
+		),
+		planBalance: connect.NewClient[v1.PlanBalanceRequest, v1.PlanBalanceResponse](
+			httpClient,
+			baseURL+BalanceServicePlanBalanceProcedure,
+			connect.WithSchema(balanceServiceMethods.ByName("PlanBalance")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
@@ -110,6 +158,10 @@ type balanceServiceClient struct {
 	getBalanceStatus    *connect.Client[v1.GetBalanceStatusRequest, v1.GetBalanceStatusResponse]
 	getAllBalanceStatus *connect.Client[v1.GetAllBalanceStatusRequest, v1.GetAllBalanceStatusResponse]
 	listBalanceHistory  *connect.Client[v1.ListBalanceHistoryRequest, v1.ListBalanceHistoryResponse]
+	getProfileStatus    *connect.Client[v1.GetProfileStatusRequest, v1.GetProfileStatusResponse]
+	finishConversion    *connect.Client[v1.FinishConversionRequest, v1.FinishConversionResponse]
+	analyzeAllocation   *connect.Client[v1.AnalyzeAllocationRequest, v1.AnalyzeAllocationResponse]
+	planBalance         *connect.Client[v1.PlanBalanceRequest, v1.PlanBalanceResponse]
 }
 
 // StartBalance calls api.v1.BalanceService.StartBalance.
@@ -137,6 +189,26 @@ func (c *balanceServiceClient) ListBalanceHistory(ctx context.Context, req *conn
 	return c.listBalanceHistory.CallUnary(ctx, req)
 }
 
+// GetProfileStatus calls api.v1.BalanceService.GetProfileStatus.
+func (c *balanceServiceClient) GetProfileStatus(ctx context.Context, req *connect.Request[v1.GetProfileStatusRequest]) (*connect.Response[v1.GetProfileStatusResponse], error) {
+	return c.getProfileStatus.CallUnary(ctx, req)
+}
+
+// FinishConversion calls api.v1.BalanceService.FinishConversion.
+func (c *balanceServiceClient) FinishConversion(ctx context.Context, req *connect.Request[v1.FinishConversionRequest]) (*connect.Response[v1.FinishConversionResponse], error) {
+	return c.finishConversion.CallUnary(ctx, req)
+}
+
+// AnalyzeAllocation calls api.v1.BalanceService.AnalyzeAllocation.
+func (c *balanceServiceClient) AnalyzeAllocation(ctx context.Context, req *connect.Request[v1.AnalyzeAllocationRequest]) (*connect.Response[v1.AnalyzeAllocationResponse], error) {
+	return c.analyzeAllocation.CallUnary(ctx, req)
+}
+
+// PlanBalance calls api.v1.BalanceService.PlanBalance.
+func (c *balanceServiceClient) PlanBalance(ctx context.Context, req *connect.Request[v1.PlanBalanceRequest]) (*connect.Response[v1.PlanBalanceResponse], error) {
+	return c.planBalance.CallUnary(ctx, req)
+}
+
 // BalanceServiceHandler is an implementation of the api.v1.BalanceService service.
 type BalanceServiceHandler interface {
 	StartBalance(context.Context, *connect.Request[v1.StartBalanceRequest]) (*connect.Response[v1.StartBalanceResponse], error)
@@ -144,6 +216,18 @@ type BalanceServiceHandler interface {
 	GetBalanceStatus(context.Context, *connect.Request[v1.GetBalanceStatusRequest]) (*connect.Response[v1.GetBalanceStatusResponse], error)
 	GetAllBalanceStatus(context.Context, *connect.Request[v1.GetAllBalanceStatusRequest]) (*connect.Response[v1.GetAllBalanceStatusResponse], error)
 	ListBalanceHistory(context.Context, *connect.Request[v1.ListBalanceHistoryRequest]) (*connect.Response[v1.ListBalanceHistoryResponse], error)
+	// GetProfileStatus reports chunk counts per profile and any stale profiles
+	// left over from an interrupted conversion
+	GetProfileStatus(context.Context, *connect.Request[v1.GetProfileStatusRequest]) (*connect.Response[v1.GetProfileStatusResponse], error)
+	// FinishConversion starts a soft convert balance that relocates only the stale chunks
+	FinishConversion(context.Context, *connect.Request[v1.FinishConversionRequest]) (*connect.Response[v1.FinishConversionResponse], error)
+	// AnalyzeAllocation simulates the chunk allocator over the current device
+	// sizes and allocations to find stranded space and the balances that free it
+	AnalyzeAllocation(context.Context, *connect.Request[v1.AnalyzeAllocationRequest]) (*connect.Response[v1.AnalyzeAllocationResponse], error)
+	// PlanBalance simulates usage= balances from block group usage and
+	// recommends the cheapest one that reaches an unallocated space goal.
+	// With start set, the recommended balance is started right away.
+	PlanBalance(context.Context, *connect.Request[v1.PlanBalanceRequest]) (*connect.Response[v1.PlanBalanceResponse], error)
 }
 
 // NewBalanceServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -183,6 +267,30 @@ func NewBalanceServiceHandler(svc BalanceServiceHandler, opts ...connect.Handler
 		connect.WithSchema(balanceServiceMethods.ByName("ListBalanceHistory")),
 		connect.WithHandlerOptions(opts...),
 	)
+	balanceServiceGetProfileStatusHandler := connect.NewUnaryHandler(
+		BalanceServiceGetProfileStatusProcedure,
+		svc.GetProfileStatus,
+		connect.WithSchema(balanceServiceMethods.ByName("GetProfileStatus")),
+		connect.WithHandlerOptions(opts...),
+	)
+	balanceServiceFinishConversionHandler := connect.NewUnaryHandler(
+		BalanceServiceFinishConversionProcedure,
+		svc.FinishConversion,
+		connect.WithSchema(balanceServiceMethods.ByName("FinishConversion")),
+		connect.WithHandlerOptions(opts...),
+	)
+	balanceServiceAnalyzeAllocationHandler := connect.NewUnaryHandler(
+		BalanceServiceAnalyzeAllocationProcedure,
+		svc.AnalyzeAllocation,
+		connect.WithSchema(balanceServiceMethods.ByName("AnalyzeAllocation")),
+		connect.WithHandlerOptions(opts...),
+	)
+	balanceServicePlanBalanceHandler := connect.NewUnaryHandler(
+		BalanceServicePlanBalanceProcedure,
+		svc.PlanBalance,
+		connect.WithSchema(balanceServiceMethods.ByName("PlanBalance")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/api.v1.BalanceService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case BalanceServiceStartBalanceProcedure:
@@ -195,6 +303,14 @@ func NewBalanceServiceHandler(svc BalanceServiceHandler, opts ...connect.Handler
 			balanceServiceGetAllBalanceStatusHandler.ServeHTTP(w, r)
 		case BalanceServiceListBalanceHistoryProcedure:
 			balanceServiceListBalanceHistoryHandler.ServeHTTP(w, r)
+		case BalanceServiceGetProfileStatusProcedure:
+			balanceServiceGetProfileStatusHandler.ServeHTTP(w, r)
+		case BalanceServiceFinishConversionProcedure:
+			balanceServiceFinishConversionHandler.ServeHTTP(w, r)
+		case BalanceServiceAnalyzeAllocationProcedure:
+			balanceServiceAnalyzeAllocationHandler.ServeHTTP(w, r)
+		case BalanceServicePlanBalanceProcedure:
+			balanceServicePlanBalanceHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -223,3 +339,19 @@ func (UnimplementedBalanceServiceHandler) GetAllBalanceStatus(context.Context, *
 func (UnimplementedBalanceServiceHandler) ListBalanceHistory(context.Context, *connect.Request[v1.ListBalanceHistoryRequest]) (*connect.Response[v1.ListBalanceHistoryResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.BalanceService.ListBalanceHistory is not implemented"))
 }
+
+func (UnimplementedBalanceServiceHandler) GetProfileStatus(context.Context, *connect.Request[v1.GetProfileStatusRequest]) (*connect.Response[v1.GetProfileStatusResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.BalanceService.GetProfileStatus is not implemented"))
+}
+
+func (UnimplementedBalanceServiceHandler) FinishConversion(context.Context, *connect.Request[v1.FinishConversionRequest]) (*connect.Response[v1.FinishConversionResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.BalanceService.FinishConversion is not implemented"))
+}
+
+func (UnimplementedBalanceServiceHandler) AnalyzeAllocation(context.Context, *connect.Request[v1.AnalyzeAllocationRequest]) (*connect.Response[v1.AnalyzeAllocationResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.BalanceService.AnalyzeAllocation is not implemented"))
+}
+
+func (UnimplementedBalanceServiceHandler) PlanBalance(context.Context, *connect.Request[v1.PlanBalanceRequest]) (*connect.Response[v1.PlanBalanceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.BalanceService.PlanBalance is not implemented"))
+}
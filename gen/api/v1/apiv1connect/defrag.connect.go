@@ -0,0 +1,225 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: api/v1/defrag.proto
+
+package apiv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/elee1766/gobtr/gen/api/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// DefragServiceName is the fully-qualified name of the DefragService service.
+	DefragServiceName = "api.v1.DefragService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// DefragServiceStartDefragProcedure is the fully-qualified name of the DefragService's StartDefrag
+	// RPC.
+	DefragServiceStartDefragProcedure = "/api.v1.DefragService/StartDefrag"
+	// DefragServiceCancelDefragProcedure is the fully-qualified name of the DefragService's
+	// CancelDefrag RPC.
+	DefragServiceCancelDefragProcedure = "/api.v1.DefragService/CancelDefrag"
+	// DefragServiceGetDefragStatusProcedure is the fully-qualified name of the DefragService's
+	// GetDefragStatus RPC.
+	DefragServiceGetDefragStatusProcedure = "/api.v1.DefragService/GetDefragStatus"
+	// DefragServiceListDefragJobsProcedure is the fully-qualified name of the DefragService's
+	// ListDefragJobs RPC.
+	DefragServiceListDefragJobsProcedure = "/api.v1.DefragService/ListDefragJobs"
+	// DefragServiceStreamDefragProgressProcedure is the fully-qualified name of the DefragService's
+	// StreamDefragProgress RPC.
+	DefragServiceStreamDefragProgressProcedure = "/api.v1.DefragService/StreamDefragProgress"
+)
+
+// DefragServiceClient is a client for the api.v1.DefragService service.
+type DefragServiceClient interface {
+	StartDefrag(context.Context, *connect.Request[v1.StartDefragRequest]) (*connect.Response[v1.StartDefragResponse], error)
+	CancelDefrag(context.Context, *connect.Request[v1.CancelDefragRequest]) (*connect.Response[v1.CancelDefragResponse], error)
+	GetDefragStatus(context.Context, *connect.Request[v1.GetDefragStatusRequest]) (*connect.Response[v1.GetDefragStatusResponse], error)
+	ListDefragJobs(context.Context, *connect.Request[v1.ListDefragJobsRequest]) (*connect.Response[v1.ListDefragJobsResponse], error)
+	StreamDefragProgress(context.Context, *connect.Request[v1.StreamDefragProgressRequest]) (*connect.ServerStreamForClient[v1.DefragProgress], error)
+}
+
+// NewDefragServiceClient constructs a client for the api.v1.DefragService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewDefragServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) DefragServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	defragServiceMethods := v1.File_api_v1_defrag_proto.Services().ByName("DefragService").Methods()
+	return &defragServiceClient{
+		startDefrag: connect.NewClient[v1.StartDefragRequest, v1.StartDefragResponse](
+			httpClient,
+			baseURL+DefragServiceStartDefragProcedure,
+			connect.WithSchema(defragServiceMethods.ByName("StartDefrag")),
+			connect.WithClientOptions(opts...),
+		),
+		cancelDefrag: connect.NewClient[v1.CancelDefragRequest, v1.CancelDefragResponse](
+			httpClient,
+			baseURL+DefragServiceCancelDefragProcedure,
+			connect.WithSchema(defragServiceMethods.ByName("CancelDefrag")),
+			connect.WithClientOptions(opts...),
+		),
+		getDefragStatus: connect.NewClient[v1.GetDefragStatusRequest, v1.GetDefragStatusResponse](
+			httpClient,
+			baseURL+DefragServiceGetDefragStatusProcedure,
+			connect.WithSchema(defragServiceMethods.ByName("GetDefragStatus")),
+			connect.WithClientOptions(opts...),
+		),
+		listDefragJobs: connect.NewClient[v1.ListDefragJobsRequest, v1.ListDefragJobsResponse](
+			httpClient,
+			baseURL+DefragServiceListDefragJobsProcedure,
+			connect.WithSchema(defragServiceMethods.ByName("ListDefragJobs")),
+			connect.WithClientOptions(opts...),
+		),
+		streamDefragProgress: connect.NewClient[v1.StreamDefragProgressRequest, v1.DefragProgress](
+			httpClient,
+			baseURL+DefragServiceStreamDefragProgressProcedure,
+			connect.WithSchema(defragServiceMethods.ByName("StreamDefragProgress")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// defragServiceClient implements DefragServiceClient.
+type defragServiceClient struct {
+	startDefrag          *connect.Client[v1.StartDefragRequest, v1.StartDefragResponse]
+	cancelDefrag         *connect.Client[v1.CancelDefragRequest, v1.CancelDefragResponse]
+	getDefragStatus      *connect.Client[v1.GetDefragStatusRequest, v1.GetDefragStatusResponse]
+	listDefragJobs       *connect.Client[v1.ListDefragJobsRequest, v1.ListDefragJobsResponse]
+	streamDefragProgress *connect.Client[v1.StreamDefragProgressRequest, v1.DefragProgress]
+}
+
+// StartDefrag calls api.v1.DefragService.StartDefrag.
+func (c *defragServiceClient) StartDefrag(ctx context.Context, req *connect.Request[v1.StartDefragRequest]) (*connect.Response[v1.StartDefragResponse], error) {
+	return c.startDefrag.CallUnary(ctx, req)
+}
+
+// CancelDefrag calls api.v1.DefragService.CancelDefrag.
+func (c *defragServiceClient) CancelDefrag(ctx context.Context, req *connect.Request[v1.CancelDefragRequest]) (*connect.Response[v1.CancelDefragResponse], error) {
+	return c.cancelDefrag.CallUnary(ctx, req)
+}
+
+// GetDefragStatus calls api.v1.DefragService.GetDefragStatus.
+func (c *defragServiceClient) GetDefragStatus(ctx context.Context, req *connect.Request[v1.GetDefragStatusRequest]) (*connect.Response[v1.GetDefragStatusResponse], error) {
+	return c.getDefragStatus.CallUnary(ctx, req)
+}
+
+// ListDefragJobs calls api.v1.DefragService.ListDefragJobs.
+func (c *defragServiceClient) ListDefragJobs(ctx context.Context, req *connect.Request[v1.ListDefragJobsRequest]) (*connect.Response[v1.ListDefragJobsResponse], error) {
+	return c.listDefragJobs.CallUnary(ctx, req)
+}
+
+// StreamDefragProgress calls api.v1.DefragService.StreamDefragProgress.
+func (c *defragServiceClient) StreamDefragProgress(ctx context.Context, req *connect.Request[v1.StreamDefragProgressRequest]) (*connect.ServerStreamForClient[v1.DefragProgress], error) {
+	return c.streamDefragProgress.CallServerStream(ctx, req)
+}
+
+// DefragServiceHandler is an implementation of the api.v1.DefragService service.
+type DefragServiceHandler interface {
+	StartDefrag(context.Context, *connect.Request[v1.StartDefragRequest]) (*connect.Response[v1.StartDefragResponse], error)
+	CancelDefrag(context.Context, *connect.Request[v1.CancelDefragRequest]) (*connect.Response[v1.CancelDefragResponse], error)
+	GetDefragStatus(context.Context, *connect.Request[v1.GetDefragStatusRequest]) (*connect.Response[v1.GetDefragStatusResponse], error)
+	ListDefragJobs(context.Context, *connect.Request[v1.ListDefragJobsRequest]) (*connect.Response[v1.ListDefragJobsResponse], error)
+	StreamDefragProgress(context.Context, *connect.Request[v1.StreamDefragProgressRequest], *connect.ServerStream[v1.DefragProgress]) error
+}
+
+// NewDefragServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewDefragServiceHandler(svc DefragServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	defragServiceMethods := v1.File_api_v1_defrag_proto.Services().ByName("DefragService").Methods()
+	defragServiceStartDefragHandler := connect.NewUnaryHandler(
+		DefragServiceStartDefragProcedure,
+		svc.StartDefrag,
+		connect.WithSchema(defragServiceMethods.ByName("StartDefrag")),
+		connect.WithHandlerOptions(opts...),
+	)
+	defragServiceCancelDefragHandler := connect.NewUnaryHandler(
+		DefragServiceCancelDefragProcedure,
+		svc.CancelDefrag,
+		connect.WithSchema(defragServiceMethods.ByName("CancelDefrag")),
+		connect.WithHandlerOptions(opts...),
+	)
+	defragServiceGetDefragStatusHandler := connect.NewUnaryHandler(
+		DefragServiceGetDefragStatusProcedure,
+		svc.GetDefragStatus,
+		connect.WithSchema(defragServiceMethods.ByName("GetDefragStatus")),
+		connect.WithHandlerOptions(opts...),
+	)
+	defragServiceListDefragJobsHandler := connect.NewUnaryHandler(
+		DefragServiceListDefragJobsProcedure,
+		svc.ListDefragJobs,
+		connect.WithSchema(defragServiceMethods.ByName("ListDefragJobs")),
+		connect.WithHandlerOptions(opts...),
+	)
+	defragServiceStreamDefragProgressHandler := connect.NewServerStreamHandler(
+		DefragServiceStreamDefragProgressProcedure,
+		svc.StreamDefragProgress,
+		connect.WithSchema(defragServiceMethods.ByName("StreamDefragProgress")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/api.v1.DefragService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case DefragServiceStartDefragProcedure:
+			defragServiceStartDefragHandler.ServeHTTP(w, r)
+		case DefragServiceCancelDefragProcedure:
+			defragServiceCancelDefragHandler.ServeHTTP(w, r)
+		case DefragServiceGetDefragStatusProcedure:
+			defragServiceGetDefragStatusHandler.ServeHTTP(w, r)
+		case DefragServiceListDefragJobsProcedure:
+			defragServiceListDefragJobsHandler.ServeHTTP(w, r)
+		case DefragServiceStreamDefragProgressProcedure:
+			defragServiceStreamDefragProgressHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedDefragServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedDefragServiceHandler struct{}
+
+func (UnimplementedDefragServiceHandler) StartDefrag(context.Context, *connect.Request[v1.StartDefragRequest]) (*connect.Response[v1.StartDefragResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.DefragService.StartDefrag is not implemented"))
+}
+
+func (UnimplementedDefragServiceHandler) CancelDefrag(context.Context, *connect.Request[v1.CancelDefragRequest]) (*connect.Response[v1.CancelDefragResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.DefragService.CancelDefrag is not implemented"))
+}
+
+func (UnimplementedDefragServiceHandler) GetDefragStatus(context.Context, *connect.Request[v1.GetDefragStatusRequest]) (*connect.Response[v1.GetDefragStatusResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.DefragService.GetDefragStatus is not implemented"))
+}
+
+func (UnimplementedDefragServiceHandler) ListDefragJobs(context.Context, *connect.Request[v1.ListDefragJobsRequest]) (*connect.Response[v1.ListDefragJobsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.DefragService.ListDefragJobs is not implemented"))
+}
+
+func (UnimplementedDefragServiceHandler) StreamDefragProgress(context.Context, *connect.Request[v1.StreamDefragProgressRequest], *connect.ServerStream[v1.DefragProgress]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.DefragService.StreamDefragProgress is not implemented"))
+}
@@ -0,0 +1,171 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: api/v1/forecast.proto
+
+package apiv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/elee1766/gobtr/gen/api/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// ForecastServiceName is the fully-qualified name of the ForecastService service.
+	ForecastServiceName = "api.v1.ForecastService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// ForecastServiceGetUsageHistoryProcedure is the fully-qualified name of the ForecastService's
+	// GetUsageHistory RPC.
+	ForecastServiceGetUsageHistoryProcedure = "/api.v1.ForecastService/GetUsageHistory"
+	// ForecastServiceGetUsageForecastProcedure is the fully-qualified name of the ForecastService's
+	// GetUsageForecast RPC.
+	ForecastServiceGetUsageForecastProcedure = "/api.v1.ForecastService/GetUsageForecast"
+	// ForecastServiceGetAllUsageForecastsProcedure is the fully-qualified name of the ForecastService's
+	// GetAllUsageForecasts RPC.
+	ForecastServiceGetAllUsageForecastsProcedure = "/api.v1.ForecastService/GetAllUsageForecasts"
+)
+
+// ForecastServiceClient is a client for the api.v1.ForecastService service.
+type ForecastServiceClient interface {
+	// GetUsageHistory returns the periodically recorded usage samples for a filesystem
+	GetUsageHistory(context.Context, *connect.Request[v1.GetUsageHistoryRequest]) (*connect.Response[v1.GetUsageHistoryResponse], error)
+	// GetUsageForecast fits allocation growth and predicts when space runs out
+	GetUsageForecast(context.Context, *connect.Request[v1.GetUsageForecastRequest]) (*connect.Response[v1.GetUsageForecastResponse], error)
+	GetAllUsageForecasts(context.Context, *connect.Request[v1.GetAllUsageForecastsRequest]) (*connect.Response[v1.GetAllUsageForecastsResponse], error)
+}
+
+// NewForecastServiceClient constructs a client for the api.v1.ForecastService service. By default,
+// it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and
+// sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC()
+// or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewForecastServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) ForecastServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	forecastServiceMethods := v1.File_api_v1_forecast_proto.Services().ByName("ForecastService").Methods()
+	return &forecastServiceClient{
+		getUsageHistory: connect.NewClient[v1.GetUsageHistoryRequest, v1.GetUsageHistoryResponse](
+			httpClient,
+			baseURL+ForecastServiceGetUsageHistoryProcedure,
+			connect.WithSchema(forecastServiceMethods.ByName("GetUsageHistory")),
+			connect.WithClientOptions(opts...),
+		),
+		getUsageForecast: connect.NewClient[v1.GetUsageForecastRequest, v1.GetUsageForecastResponse](
+			httpClient,
+			baseURL+ForecastServiceGetUsageForecastProcedure,
+			connect.WithSchema(forecastServiceMethods.ByName("GetUsageForecast")),
+			connect.WithClientOptions(opts...),
+		),
+		getAllUsageForecasts: connect.NewClient[v1.GetAllUsageForecastsRequest, v1.GetAllUsageForecastsResponse](
+			httpClient,
+			baseURL+ForecastServiceGetAllUsageForecastsProcedure,
+			connect.WithSchema(forecastServiceMethods.ByName("GetAllUsageForecasts")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// forecastServiceClient implements ForecastServiceClient.
+type forecastServiceClient struct {
+	getUsageHistory      *connect.Client[v1.GetUsageHistoryRequest, v1.GetUsageHistoryResponse]
+	getUsageForecast     *connect.Client[v1.GetUsageForecastRequest, v1.GetUsageForecastResponse]
+	getAllUsageForecasts *connect.Client[v1.GetAllUsageForecastsRequest, v1.GetAllUsageForecastsResponse]
+}
+
+// GetUsageHistory calls api.v1.ForecastService.GetUsageHistory.
+func (c *forecastServiceClient) GetUsageHistory(ctx context.Context, req *connect.Request[v1.GetUsageHistoryRequest]) (*connect.Response[v1.GetUsageHistoryResponse], error) {
+	return c.getUsageHistory.CallUnary(ctx, req)
+}
+
+// GetUsageForecast calls api.v1.ForecastService.GetUsageForecast.
+func (c *forecastServiceClient) GetUsageForecast(ctx context.Context, req *connect.Request[v1.GetUsageForecastRequest]) (*connect.Response[v1.GetUsageForecastResponse], error) {
+	return c.getUsageForecast.CallUnary(ctx, req)
+}
+
+// GetAllUsageForecasts calls api.v1.ForecastService.GetAllUsageForecasts.
+func (c *forecastServiceClient) GetAllUsageForecasts(ctx context.Context, req *connect.Request[v1.GetAllUsageForecastsRequest]) (*connect.Response[v1.GetAllUsageForecastsResponse], error) {
+	return c.getAllUsageForecasts.CallUnary(ctx, req)
+}
+
+// ForecastServiceHandler is an implementation of the api.v1.ForecastService service.
+type ForecastServiceHandler interface {
+	// GetUsageHistory returns the periodically recorded usage samples for a filesystem
+	GetUsageHistory(context.Context, *connect.Request[v1.GetUsageHistoryRequest]) (*connect.Response[v1.GetUsageHistoryResponse], error)
+	// GetUsageForecast fits allocation growth and predicts when space runs out
+	GetUsageForecast(context.Context, *connect.Request[v1.GetUsageForecastRequest]) (*connect.Response[v1.GetUsageForecastResponse], error)
+	GetAllUsageForecasts(context.Context, *connect.Request[v1.GetAllUsageForecastsRequest]) (*connect.Response[v1.GetAllUsageForecastsResponse], error)
+}
+
+// NewForecastServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewForecastServiceHandler(svc ForecastServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	forecastServiceMethods := v1.File_api_v1_forecast_proto.Services().ByName("ForecastService").Methods()
+	forecastServiceGetUsageHistoryHandler := connect.NewUnaryHandler(
+		ForecastServiceGetUsageHistoryProcedure,
+		svc.GetUsageHistory,
+		connect.WithSchema(forecastServiceMethods.ByName("GetUsageHistory")),
+		connect.WithHandlerOptions(opts...),
+	)
+	forecastServiceGetUsageForecastHandler := connect.NewUnaryHandler(
+		ForecastServiceGetUsageForecastProcedure,
+		svc.GetUsageForecast,
+		connect.WithSchema(forecastServiceMethods.ByName("GetUsageForecast")),
+		connect.WithHandlerOptions(opts...),
+	)
+	forecastServiceGetAllUsageForecastsHandler := connect.NewUnaryHandler(
+		ForecastServiceGetAllUsageForecastsProcedure,
+		svc.GetAllUsageForecasts,
+		connect.WithSchema(forecastServiceMethods.ByName("GetAllUsageForecasts")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/api.v1.ForecastService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case ForecastServiceGetUsageHistoryProcedure:
+			forecastServiceGetUsageHistoryHandler.ServeHTTP(w, r)
+		case ForecastServiceGetUsageForecastProcedure:
+			forecastServiceGetUsageForecastHandler.ServeHTTP(w, r)
+		case ForecastServiceGetAllUsageForecastsProcedure:
+			forecastServiceGetAllUsageForecastsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedForecastServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedForecastServiceHandler struct{}
+
+func (UnimplementedForecastServiceHandler) GetUsageHistory(context.Context, *connect.Request[v1.GetUsageHistoryRequest]) (*connect.Response[v1.GetUsageHistoryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.ForecastService.GetUsageHistory is not implemented"))
+}
+
+func (UnimplementedForecastServiceHandler) GetUsageForecast(context.Context, *connect.Request[v1.GetUsageForecastRequest]) (*connect.Response[v1.GetUsageForecastResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.ForecastService.GetUsageForecast is not implemented"))
+}
+
+func (UnimplementedForecastServiceHandler) GetAllUsageForecasts(context.Context, *connect.Request[v1.GetAllUsageForecastsRequest]) (*connect.Response[v1.GetAllUsageForecastsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.ForecastService.GetAllUsageForecasts is not implemented"))
+}
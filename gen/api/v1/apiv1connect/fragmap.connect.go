@@ -48,6 +48,24 @@ const (
 	// FragMapServiceGetFragStatsProcedure is the fully-qualified name of the FragMapService's
 	// GetFragStats RPC.
 	FragMapServiceGetFragStatsProcedure = "/api.v1.FragMapService/GetFragStats"
+	// FragMapServiceGetFreeSpaceStatsProcedure is the fully-qualified name of the FragMapService's
+	// GetFreeSpaceStats RPC.
+	FragMapServiceGetFreeSpaceStatsProcedure = "/api.v1.FragMapService/GetFreeSpaceStats"
+	// FragMapServiceGetCompressionStatsProcedure is the fully-qualified name of the FragMapService's
+	// GetCompressionStats RPC.
+	FragMapServiceGetCompressionStatsProcedure = "/api.v1.FragMapService/GetCompressionStats"
+	// FragMapServiceScanFilesProcedure is the fully-qualified name of the FragMapService's ScanFiles
+	// RPC.
+	FragMapServiceScanFilesProcedure = "/api.v1.FragMapService/ScanFiles"
+	// FragMapServiceListFragMapScansProcedure is the fully-qualified name of the FragMapService's
+	// ListFragMapScans RPC.
+	FragMapServiceListFragMapScansProcedure = "/api.v1.FragMapService/ListFragMapScans"
+	// FragMapServiceDiffFragMapsProcedure is the fully-qualified name of the FragMapService's
+	// DiffFragMaps RPC.
+	FragMapServiceDiffFragMapsProcedure = "/api.v1.FragMapService/DiffFragMaps"
+	// FragMapServiceResolveLogicalRangeProcedure is the fully-qualified name of the FragMapService's
+	// ResolveLogicalRange RPC.
+	FragMapServiceResolveLogicalRangeProcedure = "/api.v1.FragMapService/ResolveLogicalRange"
 )
 
 // FragMapServiceClient is a client for the api.v1.FragMapService service.
@@ -62,6 +80,19 @@ type FragMapServiceClient interface {
 	GetHeatMap(context.Context, *connect.Request[v1.GetHeatMapRequest]) (*connect.Response[v1.GetHeatMapResponse], error)
 	// Get fragmentation statistics
 	GetFragStats(context.Context, *connect.Request[v1.GetFragStatsRequest]) (*connect.Response[v1.GetFragStatsResponse], error)
+	// Get free space fragmentation inside block groups
+	GetFreeSpaceStats(context.Context, *connect.Request[v1.GetFreeSpaceStatsRequest]) (*connect.Response[v1.GetFreeSpaceStatsResponse], error)
+	// Get disk usage per compression algorithm under a path (like compsize)
+	GetCompressionStats(context.Context, *connect.Request[v1.GetCompressionStatsRequest]) (*connect.Response[v1.GetCompressionStatsResponse], error)
+	// Scan file fragmentation under a directory, streaming progress and then the result
+	ScanFiles(context.Context, *connect.Request[v1.ScanFilesRequest]) (*connect.ServerStreamForClient[v1.ScanFilesUpdate], error)
+	// List saved scans of a filesystem. A scan is saved whenever the chunk layout changes.
+	ListFragMapScans(context.Context, *connect.Request[v1.ListFragMapScansRequest]) (*connect.Response[v1.ListFragMapScansResponse], error)
+	// Show which chunks were allocated, freed or relocated between two scans
+	DiffFragMaps(context.Context, *connect.Request[v1.DiffFragMapsRequest]) (*connect.Response[v1.DiffFragMapsResponse], error)
+	// List the extents in a logical range or chunk and the files that reference
+	// them, i.e. what a balance of that chunk would move
+	ResolveLogicalRange(context.Context, *connect.Request[v1.ResolveLogicalRangeRequest]) (*connect.Response[v1.ResolveLogicalRangeResponse], error)
 }
 
 // NewFragMapServiceClient constructs a client for the api.v1.FragMapService service. By default, it
@@ -105,16 +136,58 @@ func NewFragMapServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(fragMapServiceMethods.ByName("GetFragStats")),
 			connect.WithClientOptions(opts...),
 		),
+		getFreeSpaceStats: connect.NewClient[v1.GetFreeSpaceStatsRequest, v1.GetFreeSpaceStatsResponse](
+			httpClient,
+			baseURL+FragMapServiceGetFreeSpaceStatsProcedure,
+			connect.WithSchema(fragMapServiceMethods.ByName("GetFreeSpaceStats")),
+			connect.WithClientOptions(opts...),
+		),
+		getCompressionStats: connect.NewClient[v1.GetCompressionStatsRequest, v1.GetCompressionStatsResponse](
+			httpClient,
+			baseURL+FragMapServiceGetCompressionStatsProcedure,
+			connect.WithSchema(fragMapServiceMethods.ByName("GetCompressionStats")),
+			connect.WithClientOptions(opts...),
+		),
+		scanFiles: connect.NewClient[v1.ScanFilesRequest, v1.ScanFilesUpdate](
+			httpClient,
+			baseURL+FragMapServiceScanFilesProcedure,
+			connect.WithSchema(fragMapServiceMethods.ByName("ScanFiles")),
+			connect.WithClientOptions(opts...),
+		),
+		listFragMapScans: connect.NewClient[v1.ListFragMapScansRequest, v1.ListFragMapScansResponse](
+			httpClient,
+			baseURL+FragMapServiceListFragMapScansProcedure,
+			connect.WithSchema(fragMapServiceMethods.ByName("ListFragMapScans")),
+			connect.WithClientOptions(opts...),
+		),
+		diffFragMaps: connect.NewClient[v1.DiffFragMapsRequest, v1.DiffFragMapsResponse](
+			httpClient,
+			baseURL+FragMapServiceDiffFragMapsProcedure,
+			connect.WithSchema(fragMapServiceMethods.ByName("DiffFragMaps")),
+			connect.WithClientOptions(opts...),
+		),
+		resolveLogicalRange: connect.NewClient[v1.ResolveLogicalRangeRequest, v1.ResolveLogicalRangeResponse](
+			httpClient,
+			baseURL+FragMapServiceResolveLogicalRangeProcedure,
+			connect.WithSchema(fragMapServiceMethods.ByName("ResolveLogicalRange")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // fragMapServiceClient implements FragMapServiceClient.
 type fragMapServiceClient struct {
-	getFragMap         *connect.Client[v1.GetFragMapRequest, v1.GetFragMapResponse]
-	getDeviceBlockMap  *connect.Client[v1.GetDeviceBlockMapRequest, v1.GetDeviceBlockMapResponse]
-	getDeviceBlockMaps *connect.Client[v1.GetDeviceBlockMapsRequest, v1.GetDeviceBlockMapsResponse]
-	getHeatMap         *connect.Client[v1.GetHeatMapRequest, v1.GetHeatMapResponse]
-	getFragStats       *connect.Client[v1.GetFragStatsRequest, v1.GetFragStatsResponse]
+	getFragMap          *connect.Client[v1.GetFragMapRequest, v1.GetFragMapResponse]
+	getDeviceBlockMap   *connect.Client[v1.GetDeviceBlockMapRequest, v1.GetDeviceBlockMapResponse]
+	getDeviceBlockMaps  *connect.Client[v1.GetDeviceBlockMapsRequest, v1.GetDeviceBlockMapsResponse]
+	getHeatMap          *connect.Client[v1.GetHeatMapRequest, v1.GetHeatMapResponse]
+	getFragStats        *connect.Client[v1.GetFragStatsRequest, v1.GetFragStatsResponse]
+	getFreeSpaceStats   *connect.Client[v1.GetFreeSpaceStatsRequest, v1.GetFreeSpaceStatsResponse]
+	getCompressionStats *connect.Client[v1.GetCompressionStatsRequest, v1.GetCompressionStatsResponse]
+	scanFiles           *connect.Client[v1.ScanFilesRequest, v1.ScanFilesUpdate]
+	listFragMapScans    *connect.Client[v1.ListFragMapScansRequest, v1.ListFragMapScansResponse]
+	diffFragMaps        *connect.Client[v1.DiffFragMapsRequest, v1.DiffFragMapsResponse]
+	resolveLogicalRange *connect.Client[v1.ResolveLogicalRangeRequest, v1.ResolveLogicalRangeResponse]
 }
 
 // GetFragMap calls api.v1.FragMapService.GetFragMap.
@@ -142,6 +215,36 @@ func (c *fragMapServiceClient) GetFragStats(ctx context.Context, req *connect.Re
 	return c.getFragStats.CallUnary(ctx, req)
 }
 
+// GetFreeSpaceStats calls api.v1.FragMapService.GetFreeSpaceStats.
+func (c *fragMapServiceClient) GetFreeSpaceStats(ctx context.Context, req *connect.Request[v1.GetFreeSpaceStatsRequest]) (*connect.Response[v1.GetFreeSpaceStatsResponse], error) {
+	return c.getFreeSpaceStats.CallUnary(ctx, req)
+}
+
+// GetCompressionStats calls api.v1.FragMapService.GetCompressionStats.
+func (c *fragMapServiceClient) GetCompressionStats(ctx context.Context, req *connect.Request[v1.GetCompressionStatsRequest]) (*connect.Response[v1.GetCompressionStatsResponse], error) {
+	return c.getCompressionStats.CallUnary(ctx, req)
+}
+
+// ScanFiles calls api.v1.FragMapService.ScanFiles.
+func (c *fragMapServiceClient) ScanFiles(ctx context.Context, req *connect.Request[v1.ScanFilesRequest]) (*connect.ServerStreamForClient[v1.ScanFilesUpdate], error) {
+	return c.scanFiles.CallServerStream(ctx, req)
+}
+
+// ListFragMapScans calls api.v1.FragMapService.ListFragMapScans.
+func (c *fragMapServiceClient) ListFragMapScans(ctx context.Context, req *connect.Request[v1.ListFragMapScansRequest]) (*connect.Response[v1.ListFragMapScansResponse], error) {
+	return c.listFragMapScans.CallUnary(ctx, req)
+}
+
+// DiffFragMaps calls api.v1.FragMapService.DiffFragMaps.
+func (c *fragMapServiceClient) DiffFragMaps(ctx context.Context, req *connect.Request[v1.DiffFragMapsRequest]) (*connect.Response[v1.DiffFragMapsResponse], error) {
+	return c.diffFragMaps.CallUnary(ctx, req)
+}
+
+// ResolveLogicalRange calls api.v1.FragMapService.ResolveLogicalRange.
+func (c *fragMapServiceClient) ResolveLogicalRange(ctx context.Context, req *connect.Request[v1.ResolveLogicalRangeRequest]) (*connect.Response[v1.ResolveLogicalRangeResponse], error) {
+	return c.resolveLogicalRange.CallUnary(ctx, req)
+}
+
 // FragMapServiceHandler is an implementation of the api.v1.FragMapService service.
 type FragMapServiceHandler interface {
 	// Get the complete fragmentation map for a filesystem
@@ -154,6 +257,19 @@ type FragMapServiceHandler interface {
 	GetHeatMap(context.Context, *connect.Request[v1.GetHeatMapRequest]) (*connect.Response[v1.GetHeatMapResponse], error)
 	// Get fragmentation statistics
 	GetFragStats(context.Context, *connect.Request[v1.GetFragStatsRequest]) (*connect.Response[v1.GetFragStatsResponse], error)
+	// Get free space fragmentation inside block groups
+	GetFreeSpaceStats(context.Context, *connect.Request[v1.GetFreeSpaceStatsRequest]) (*connect.Response[v1.GetFreeSpaceStatsResponse], error)
+	// Get disk usage per compression algorithm under a path (like compsize)
+	GetCompressionStats(context.Context, *connect.Request[v1.GetCompressionStatsRequest]) (*connect.Response[v1.GetCompressionStatsResponse], error)
+	// Scan file fragmentation under a directory, streaming progress and then the result
+	ScanFiles(context.Context, *connect.Request[v1.ScanFilesRequest], *connect.ServerStream[v1.ScanFilesUpdate]) error
+	// List saved scans of a filesystem. A scan is saved whenever the chunk layout changes.
+	ListFragMapScans(context.Context, *connect.Request[v1.ListFragMapScansRequest]) (*connect.Response[v1.ListFragMapScansResponse], error)
+	// Show which chunks were allocated, freed or relocated between two scans
+	DiffFragMaps(context.Context, *connect.Request[v1.DiffFragMapsRequest]) (*connect.Response[v1.DiffFragMapsResponse], error)
+	// List the extents in a logical range or chunk and the files that reference
+	// them, i.e. what a balance of that chunk would move
+	ResolveLogicalRange(context.Context, *connect.Request[v1.ResolveLogicalRangeRequest]) (*connect.Response[v1.ResolveLogicalRangeResponse], error)
 }
 
 // NewFragMapServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -193,6 +309,42 @@ func NewFragMapServiceHandler(svc FragMapServiceHandler, opts ...connect.Handler
 		connect.WithSchema(fragMapServiceMethods.ByName("GetFragStats")),
 		connect.WithHandlerOptions(opts...),
 	)
+	fragMapServiceGetFreeSpaceStatsHandler := connect.NewUnaryHandler(
+		FragMapServiceGetFreeSpaceStatsProcedure,
+		svc.GetFreeSpaceStats,
+		connect.WithSchema(fragMapServiceMethods.ByName("GetFreeSpaceStats")),
+		connect.WithHandlerOptions(opts...),
+	)
+	fragMapServiceGetCompressionStatsHandler := connect.NewUnaryHandler(
+		FragMapServiceGetCompressionStatsProcedure,
+		svc.GetCompressionStats,
+		connect.WithSchema(fragMapServiceMethods.ByName("GetCompressionStats")),
+		connect.WithHandlerOptions(opts...),
+	)
+	fragMapServiceScanFilesHandler := connect.NewServerStreamHandler(
+		FragMapServiceScanFilesProcedure,
+		svc.ScanFiles,
+		connect.WithSchema(fragMapServiceMethods.ByName("ScanFiles")),
+		connect.WithHandlerOptions(opts...),
+	)
+	fragMapServiceListFragMapScansHandler := connect.NewUnaryHandler(
+		FragMapServiceListFragMapScansProcedure,
+		svc.ListFragMapScans,
+		connect.WithSchema(fragMapServiceMethods.ByName("ListFragMapScans")),
+		connect.WithHandlerOptions(opts...),
+	)
+	fragMapServiceDiffFragMapsHandler := connect.NewUnaryHandler(
+		FragMapServiceDiffFragMapsProcedure,
+		svc.DiffFragMaps,
+		connect.WithSchema(fragMapServiceMethods.ByName("DiffFragMaps")),
+		connect.WithHandlerOptions(opts...),
+	)
+	fragMapServiceResolveLogicalRangeHandler := connect.NewUnaryHandler(
+		FragMapServiceResolveLogicalRangeProcedure,
+		svc.ResolveLogicalRange,
+		connect.WithSchema(fragMapServiceMethods.ByName("ResolveLogicalRange")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/api.v1.FragMapService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case FragMapServiceGetFragMapProcedure:
@@ -205,6 +357,18 @@ func NewFragMapServiceHandler(svc FragMapServiceHandler, opts ...connect.Handler
 			fragMapServiceGetHeatMapHandler.ServeHTTP(w, r)
 		case FragMapServiceGetFragStatsProcedure:
 			fragMapServiceGetFragStatsHandler.ServeHTTP(w, r)
+		case FragMapServiceGetFreeSpaceStatsProcedure:
+			fragMapServiceGetFreeSpaceStatsHandler.ServeHTTP(w, r)
+		case FragMapServiceGetCompressionStatsProcedure:
+			fragMapServiceGetCompressionStatsHandler.ServeHTTP(w, r)
+		case FragMapServiceScanFilesProcedure:
+			fragMapServiceScanFilesHandler.ServeHTTP(w, r)
+		case FragMapServiceListFragMapScansProcedure:
+			fragMapServiceListFragMapScansHandler.ServeHTTP(w, r)
+		case FragMapServiceDiffFragMapsProcedure:
+			fragMapServiceDiffFragMapsHandler.ServeHTTP(w, r)
+		case FragMapServiceResolveLogicalRangeProcedure:
+			fragMapServiceResolveLogicalRangeHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -233,3 +397,27 @@ func (UnimplementedFragMapServiceHandler) GetHeatMap(context.Context, *connect.R
 func (UnimplementedFragMapServiceHandler) GetFragStats(context.Context, *connect.Request[v1.GetFragStatsRequest]) (*connect.Response[v1.GetFragStatsResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.FragMapService.GetFragStats is not implemented"))
 }
+
+func (UnimplementedFragMapServiceHandler) GetFreeSpaceStats(context.Context, *connect.Request[v1.GetFreeSpaceStatsRequest]) (*connect.Response[v1.GetFreeSpaceStatsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.FragMapService.GetFreeSpaceStats is not implemented"))
+}
+
+func (UnimplementedFragMapServiceHandler) GetCompressionStats(context.Context, *connect.Request[v1.GetCompressionStatsRequest]) (*connect.Response[v1.GetCompressionStatsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.FragMapService.GetCompressionStats is not implemented"))
+}
+
+func (UnimplementedFragMapServiceHandler) ScanFiles(context.Context, *connect.Request[v1.ScanFilesRequest], *connect.ServerStream[v1.ScanFilesUpdate]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.FragMapService.ScanFiles is not implemented"))
+}
+
+func (UnimplementedFragMapServiceHandler) ListFragMapScans(context.Context, *connect.Request[v1.ListFragMapScansRequest]) (*connect.Response[v1.ListFragMapScansResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.FragMapService.ListFragMapScans is not implemented"))
+}
+
+func (UnimplementedFragMapServiceHandler) DiffFragMaps(context.Context, *connect.Request[v1.DiffFragMapsRequest]) (*connect.Response[v1.DiffFragMapsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.FragMapService.DiffFragMaps is not implemented"))
+}
+
+func (UnimplementedFragMapServiceHandler) ResolveLogicalRange(context.Context, *connect.Request[v1.ResolveLogicalRangeRequest]) (*connect.Response[v1.ResolveLogicalRangeResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.FragMapService.ResolveLogicalRange is not implemented"))
+}
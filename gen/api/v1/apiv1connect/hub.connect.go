@@ -0,0 +1,255 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: api/v1/hub.proto
+
+package apiv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/elee1766/gobtr/gen/api/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// HubServiceName is the fully-qualified name of the HubService service.
+	HubServiceName = "api.v1.HubService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// HubServiceListAgentsProcedure is the fully-qualified name of the HubService's ListAgents RPC.
+	HubServiceListAgentsProcedure = "/api.v1.HubService/ListAgents"
+	// HubServiceListHostFilesystemsProcedure is the fully-qualified name of the HubService's
+	// ListHostFilesystems RPC.
+	HubServiceListHostFilesystemsProcedure = "/api.v1.HubService/ListHostFilesystems"
+	// HubServiceGetHostScrubStatusProcedure is the fully-qualified name of the HubService's
+	// GetHostScrubStatus RPC.
+	HubServiceGetHostScrubStatusProcedure = "/api.v1.HubService/GetHostScrubStatus"
+	// HubServiceGetHostBalanceStatusProcedure is the fully-qualified name of the HubService's
+	// GetHostBalanceStatus RPC.
+	HubServiceGetHostBalanceStatusProcedure = "/api.v1.HubService/GetHostBalanceStatus"
+	// HubServiceGetHostFilesystemUsageProcedure is the fully-qualified name of the HubService's
+	// GetHostFilesystemUsage RPC.
+	HubServiceGetHostFilesystemUsageProcedure = "/api.v1.HubService/GetHostFilesystemUsage"
+	// HubServiceGetHostDiagnosticsProcedure is the fully-qualified name of the HubService's
+	// GetHostDiagnostics RPC.
+	HubServiceGetHostDiagnosticsProcedure = "/api.v1.HubService/GetHostDiagnostics"
+)
+
+// HubServiceClient is a client for the api.v1.HubService service.
+type HubServiceClient interface {
+	ListAgents(context.Context, *connect.Request[v1.ListAgentsRequest]) (*connect.Response[v1.ListAgentsResponse], error)
+	ListHostFilesystems(context.Context, *connect.Request[v1.ListHostFilesystemsRequest]) (*connect.Response[v1.ListHostFilesystemsResponse], error)
+	GetHostScrubStatus(context.Context, *connect.Request[v1.GetHostScrubStatusRequest]) (*connect.Response[v1.GetHostScrubStatusResponse], error)
+	GetHostBalanceStatus(context.Context, *connect.Request[v1.GetHostBalanceStatusRequest]) (*connect.Response[v1.GetHostBalanceStatusResponse], error)
+	GetHostFilesystemUsage(context.Context, *connect.Request[v1.GetHostFilesystemUsageRequest]) (*connect.Response[v1.GetHostFilesystemUsageResponse], error)
+	// GetHostDiagnostics runs each host's diagnostics with its own thresholds
+	GetHostDiagnostics(context.Context, *connect.Request[v1.GetHostDiagnosticsRequest]) (*connect.Response[v1.GetHostDiagnosticsResponse], error)
+}
+
+// NewHubServiceClient constructs a client for the api.v1.HubService service. By default, it uses
+// the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewHubServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) HubServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	hubServiceMethods := v1.File_api_v1_hub_proto.Services().ByName("HubService").Methods()
+	return &hubServiceClient{
+		listAgents: connect.NewClient[v1.ListAgentsRequest, v1.ListAgentsResponse](
+			httpClient,
+			baseURL+HubServiceListAgentsProcedure,
+			connect.WithSchema(hubServiceMethods.ByName("ListAgents")),
+			connect.WithClientOptions(opts...),
+		),
+		listHostFilesystems: connect.NewClient[v1.ListHostFilesystemsRequest, v1.ListHostFilesystemsResponse](
+			httpClient,
+			baseURL+HubServiceListHostFilesystemsProcedure,
+			connect.WithSchema(hubServiceMethods.ByName("ListHostFilesystems")),
+			connect.WithClientOptions(opts...),
+		),
+		getHostScrubStatus: connect.NewClient[v1.GetHostScrubStatusRequest, v1.GetHostScrubStatusResponse](
+			httpClient,
+			baseURL+HubServiceGetHostScrubStatusProcedure,
+			connect.WithSchema(hubServiceMethods.ByName("GetHostScrubStatus")),
+			connect.WithClientOptions(opts...),
+		),
+		getHostBalanceStatus: connect.NewClient[v1.GetHostBalanceStatusRequest, v1.GetHostBalanceStatusResponse](
+			httpClient,
+			baseURL+HubServiceGetHostBalanceStatusProcedure,
+			connect.WithSchema(hubServiceMethods.ByName("GetHostBalanceStatus")),
+			connect.WithClientOptions(opts...),
+		),
+		getHostFilesystemUsage: connect.NewClient[v1.GetHostFilesystemUsageRequest, v1.GetHostFilesystemUsageResponse](
+			httpClient,
+			baseURL+HubServiceGetHostFilesystemUsageProcedure,
+			connect.WithSchema(hubServiceMethods.ByName("GetHostFilesystemUsage")),
+			connect.WithClientOptions(opts...),
+		),
+		getHostDiagnostics: connect.NewClient[v1.GetHostDiagnosticsRequest, v1.GetHostDiagnosticsResponse](
+			httpClient,
+			baseURL+HubServiceGetHostDiagnosticsProcedure,
+			connect.WithSchema(hubServiceMethods.ByName("GetHostDiagnostics")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// hubServiceClient implements HubServiceClient.
+type hubServiceClient struct {
+	listAgents             *connect.Client[v1.ListAgentsRequest, v1.ListAgentsResponse]
+	listHostFilesystems    *connect.Client[v1.ListHostFilesystemsRequest, v1.ListHostFilesystemsResponse]
+	getHostScrubStatus     *connect.Client[v1.GetHostScrubStatusRequest, v1.GetHostScrubStatusResponse]
+	getHostBalanceStatus   *connect.Client[v1.GetHostBalanceStatusRequest, v1.GetHostBalanceStatusResponse]
+	getHostFilesystemUsage *connect.Client[v1.GetHostFilesystemUsageRequest, v1.GetHostFilesystemUsageResponse]
+	getHostDiagnostics     *connect.Client[v1.GetHostDiagnosticsRequest, v1.GetHostDiagnosticsResponse]
+}
+
+// ListAgents calls api.v1.HubService.ListAgents.
+func (c *hubServiceClient) ListAgents(ctx context.Context, req *connect.Request[v1.ListAgentsRequest]) (*connect.Response[v1.ListAgentsResponse], error) {
+	return c.listAgents.CallUnary(ctx, req)
+}
+
+// ListHostFilesystems calls api.v1.HubService.ListHostFilesystems.
+func (c *hubServiceClient) ListHostFilesystems(ctx context.Context, req *connect.Request[v1.ListHostFilesystemsRequest]) (*connect.Response[v1.ListHostFilesystemsResponse], error) {
+	return c.listHostFilesystems.CallUnary(ctx, req)
+}
+
+// GetHostScrubStatus calls api.v1.HubService.GetHostScrubStatus.
+func (c *hubServiceClient) GetHostScrubStatus(ctx context.Context, req *connect.Request[v1.GetHostScrubStatusRequest]) (*connect.Response[v1.GetHostScrubStatusResponse], error) {
+	return c.getHostScrubStatus.CallUnary(ctx, req)
+}
+
+// GetHostBalanceStatus calls api.v1.HubService.GetHostBalanceStatus.
+func (c *hubServiceClient) GetHostBalanceStatus(ctx context.Context, req *connect.Request[v1.GetHostBalanceStatusRequest]) (*connect.Response[v1.GetHostBalanceStatusResponse], error) {
+	return c.getHostBalanceStatus.CallUnary(ctx, req)
+}
+
+// GetHostFilesystemUsage calls api.v1.HubService.GetHostFilesystemUsage.
+func (c *hubServiceClient) GetHostFilesystemUsage(ctx context.Context, req *connect.Request[v1.GetHostFilesystemUsageRequest]) (*connect.Response[v1.GetHostFilesystemUsageResponse], error) {
+	return c.getHostFilesystemUsage.CallUnary(ctx, req)
+}
+
+// GetHostDiagnostics calls api.v1.HubService.GetHostDiagnostics.
+func (c *hubServiceClient) GetHostDiagnostics(ctx context.Context, req *connect.Request[v1.GetHostDiagnosticsRequest]) (*connect.Response[v1.GetHostDiagnosticsResponse], error) {
+	return c.getHostDiagnostics.CallUnary(ctx, req)
+}
+
+// HubServiceHandler is an implementation of the api.v1.HubService service.
+type HubServiceHandler interface {
+	ListAgents(context.Context, *connect.Request[v1.ListAgentsRequest]) (*connect.Response[v1.ListAgentsResponse], error)
+	ListHostFilesystems(context.Context, *connect.Request[v1.ListHostFilesystemsRequest]) (*connect.Response[v1.ListHostFilesystemsResponse], error)
+	GetHostScrubStatus(context.Context, *connect.Request[v1.GetHostScrubStatusRequest]) (*connect.Response[v1.GetHostScrubStatusResponse], error)
+	GetHostBalanceStatus(context.Context, *connect.Request[v1.GetHostBalanceStatusRequest]) (*connect.Response[v1.GetHostBalanceStatusResponse], error)
+	GetHostFilesystemUsage(context.Context, *connect.Request[v1.GetHostFilesystemUsageRequest]) (*connect.Response[v1.GetHostFilesystemUsageResponse], error)
+	// GetHostDiagnostics runs each host's diagnostics with its own thresholds
+	GetHostDiagnostics(context.Context, *connect.Request[v1.GetHostDiagnosticsRequest]) (*connect.Response[v1.GetHostDiagnosticsResponse], error)
+}
+
+// NewHubServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewHubServiceHandler(svc HubServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	hubServiceMethods := v1.File_api_v1_hub_proto.Services().ByName("HubService").Methods()
+	hubServiceListAgentsHandler := connect.NewUnaryHandler(
+		HubServiceListAgentsProcedure,
+		svc.ListAgents,
+		connect.WithSchema(hubServiceMethods.ByName("ListAgents")),
+		connect.WithHandlerOptions(opts...),
+	)
+	hubServiceListHostFilesystemsHandler := connect.NewUnaryHandler(
+		HubServiceListHostFilesystemsProcedure,
+		svc.ListHostFilesystems,
+		connect.WithSchema(hubServiceMethods.ByName("ListHostFilesystems")),
+		connect.WithHandlerOptions(opts...),
+	)
+	hubServiceGetHostScrubStatusHandler := connect.NewUnaryHandler(
+		HubServiceGetHostScrubStatusProcedure,
+		svc.GetHostScrubStatus,
+		connect.WithSchema(hubServiceMethods.ByName("GetHostScrubStatus")),
+		connect.WithHandlerOptions(opts...),
+	)
+	hubServiceGetHostBalanceStatusHandler := connect.NewUnaryHandler(
+		HubServiceGetHostBalanceStatusProcedure,
+		svc.GetHostBalanceStatus,
+		connect.WithSchema(hubServiceMethods.ByName("GetHostBalanceStatus")),
+		connect.WithHandlerOptions(opts...),
+	)
+	hubServiceGetHostFilesystemUsageHandler := connect.NewUnaryHandler(
+		HubServiceGetHostFilesystemUsageProcedure,
+		svc.GetHostFilesystemUsage,
+		connect.WithSchema(hubServiceMethods.ByName("GetHostFilesystemUsage")),
+		connect.WithHandlerOptions(opts...),
+	)
+	hubServiceGetHostDiagnosticsHandler := connect.NewUnaryHandler(
+		HubServiceGetHostDiagnosticsProcedure,
+		svc.GetHostDiagnostics,
+		connect.WithSchema(hubServiceMethods.ByName("GetHostDiagnostics")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/api.v1.HubService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case HubServiceListAgentsProcedure:
+			hubServiceListAgentsHandler.ServeHTTP(w, r)
+		case HubServiceListHostFilesystemsProcedure:
+			hubServiceListHostFilesystemsHandler.ServeHTTP(w, r)
+		case HubServiceGetHostScrubStatusProcedure:
+			hubServiceGetHostScrubStatusHandler.ServeHTTP(w, r)
+		case HubServiceGetHostBalanceStatusProcedure:
+			hubServiceGetHostBalanceStatusHandler.ServeHTTP(w, r)
+		case HubServiceGetHostFilesystemUsageProcedure:
+			hubServiceGetHostFilesystemUsageHandler.ServeHTTP(w, r)
+		case HubServiceGetHostDiagnosticsProcedure:
+			hubServiceGetHostDiagnosticsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedHubServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedHubServiceHandler struct{}
+
+func (UnimplementedHubServiceHandler) ListAgents(context.Context, *connect.Request[v1.ListAgentsRequest]) (*connect.Response[v1.ListAgentsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.HubService.ListAgents is not implemented"))
+}
+
+func (UnimplementedHubServiceHandler) ListHostFilesystems(context.Context, *connect.Request[v1.ListHostFilesystemsRequest]) (*connect.Response[v1.ListHostFilesystemsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.HubService.ListHostFilesystems is not implemented"))
+}
+
+func (UnimplementedHubServiceHandler) GetHostScrubStatus(context.Context, *connect.Request[v1.GetHostScrubStatusRequest]) (*connect.Response[v1.GetHostScrubStatusResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.HubService.GetHostScrubStatus is not implemented"))
+}
+
+func (UnimplementedHubServiceHandler) GetHostBalanceStatus(context.Context, *connect.Request[v1.GetHostBalanceStatusRequest]) (*connect.Response[v1.GetHostBalanceStatusResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.HubService.GetHostBalanceStatus is not implemented"))
+}
+
+func (UnimplementedHubServiceHandler) GetHostFilesystemUsage(context.Context, *connect.Request[v1.GetHostFilesystemUsageRequest]) (*connect.Response[v1.GetHostFilesystemUsageResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.HubService.GetHostFilesystemUsage is not implemented"))
+}
+
+func (UnimplementedHubServiceHandler) GetHostDiagnostics(context.Context, *connect.Request[v1.GetHostDiagnosticsRequest]) (*connect.Response[v1.GetHostDiagnosticsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.HubService.GetHostDiagnostics is not implemented"))
+}
@@ -45,7 +45,8 @@ const (
 type SettingsServiceClient interface {
 	// GetSettings returns the current server settings
 	GetSettings(context.Context, *connect.Request[v1.GetSettingsRequest]) (*connect.Response[v1.GetSettingsResponse], error)
-	// UpdateSettings updates server settings
+	// UpdateSettings updates server settings. Unset (zero) fields are left
+	// unchanged.
 	UpdateSettings(context.Context, *connect.Request[v1.UpdateSettingsRequest]) (*connect.Response[v1.UpdateSettingsResponse], error)
 }
 
@@ -95,7 +96,8 @@ func (c *settingsServiceClient) UpdateSettings(ctx context.Context, req *connect
 type SettingsServiceHandler interface {
 	// GetSettings returns the current server settings
 	GetSettings(context.Context, *connect.Request[v1.GetSettingsRequest]) (*connect.Response[v1.GetSettingsResponse], error)
-	// UpdateSettings updates server settings
+	// UpdateSettings updates server settings. Unset (zero) fields are left
+	// unchanged.
 	UpdateSettings(context.Context, *connect.Request[v1.UpdateSettingsRequest]) (*connect.Response[v1.UpdateSettingsResponse], error)
 }
 
@@ -0,0 +1,117 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: api/v1/support.proto
+
+package apiv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/elee1766/gobtr/gen/api/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// SupportServiceName is the fully-qualified name of the SupportService service.
+	SupportServiceName = "api.v1.SupportService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// SupportServiceCreateSupportBundleProcedure is the fully-qualified name of the SupportService's
+	// CreateSupportBundle RPC.
+	SupportServiceCreateSupportBundleProcedure = "/api.v1.SupportService/CreateSupportBundle"
+)
+
+// SupportServiceClient is a client for the api.v1.SupportService service.
+type SupportServiceClient interface {
+	// CreateSupportBundle returns a tar.gz with the version, the config with
+	// its secrets redacted, the tracked filesystems with their sysfs
+	// allocation, device error counters, last scrub and recent balances, the
+	// recorded errors and a goroutine dump. Also at GET /support/bundle.
+	CreateSupportBundle(context.Context, *connect.Request[v1.CreateSupportBundleRequest]) (*connect.Response[v1.CreateSupportBundleResponse], error)
+}
+
+// NewSupportServiceClient constructs a client for the api.v1.SupportService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewSupportServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) SupportServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	supportServiceMethods := v1.File_api_v1_support_proto.Services().ByName("SupportService").Methods()
+	return &supportServiceClient{
+		createSupportBundle: connect.NewClient[v1.CreateSupportBundleRequest, v1.CreateSupportBundleResponse](
+			httpClient,
+			baseURL+SupportServiceCreateSupportBundleProcedure,
+			connect.WithSchema(supportServiceMethods.ByName("CreateSupportBundle")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// supportServiceClient implements SupportServiceClient.
+type supportServiceClient struct {
+	createSupportBundle *connect.Client[v1.CreateSupportBundleRequest, v1.CreateSupportBundleResponse]
+}
+
+// CreateSupportBundle calls api.v1.SupportService.CreateSupportBundle.
+func (c *supportServiceClient) CreateSupportBundle(ctx context.Context, req *connect.Request[v1.CreateSupportBundleRequest]) (*connect.Response[v1.CreateSupportBundleResponse], error) {
+	return c.createSupportBundle.CallUnary(ctx, req)
+}
+
+// SupportServiceHandler is an implementation of the api.v1.SupportService service.
+type SupportServiceHandler interface {
+	// CreateSupportBundle returns a tar.gz with the version, the config with
+	// its secrets redacted, the tracked filesystems with their sysfs
+	// allocation, device error counters, last scrub and recent balances, the
+	// recorded errors and a goroutine dump. Also at GET /support/bundle.
+	CreateSupportBundle(context.Context, *connect.Request[v1.CreateSupportBundleRequest]) (*connect.Response[v1.CreateSupportBundleResponse], error)
+}
+
+// NewSupportServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewSupportServiceHandler(svc SupportServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	supportServiceMethods := v1.File_api_v1_support_proto.Services().ByName("SupportService").Methods()
+	supportServiceCreateSupportBundleHandler := connect.NewUnaryHandler(
+		SupportServiceCreateSupportBundleProcedure,
+		svc.CreateSupportBundle,
+		connect.WithSchema(supportServiceMethods.ByName("CreateSupportBundle")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/api.v1.SupportService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case SupportServiceCreateSupportBundleProcedure:
+			supportServiceCreateSupportBundleHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedSupportServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedSupportServiceHandler struct{}
+
+func (UnimplementedSupportServiceHandler) CreateSupportBundle(context.Context, *connect.Request[v1.CreateSupportBundleRequest]) (*connect.Response[v1.CreateSupportBundleResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.SupportService.CreateSupportBundle is not implemented"))
+}
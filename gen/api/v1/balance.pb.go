@@ -119,7 +119,18 @@ type BalanceFilters struct {
 	// Usage-based filtering (balance chunks with usage <= this percent)
 	UsagePercent int32 `protobuf:"varint,4,opt,name=usage_percent,json=usagePercent,proto3" json:"usage_percent,omitempty"`
 	// Limit number of chunks to process (0 = no limit)
-	LimitChunks   int64 `protobuf:"varint,5,opt,name=limit_chunks,json=limitChunks,proto3" json:"limit_chunks,omitempty"`
+	LimitChunks int64 `protobuf:"varint,5,opt,name=limit_chunks,json=limitChunks,proto3" json:"limit_chunks,omitempty"`
+	// Profile conversion targets, e.g. "raid1" (empty = no conversion)
+	DataConvert     string `protobuf:"bytes,6,opt,name=data_convert,json=dataConvert,proto3" json:"data_convert,omitempty"`
+	MetadataConvert string `protobuf:"bytes,7,opt,name=metadata_convert,json=metadataConvert,proto3" json:"metadata_convert,omitempty"`
+	SystemConvert   string `protobuf:"bytes,8,opt,name=system_convert,json=systemConvert,proto3" json:"system_convert,omitempty"`
+	// Skip chunks that already have the target profile
+	Soft bool `protobuf:"varint,9,opt,name=soft,proto3" json:"soft,omitempty"`
+	// Only chunks with a stripe on this device (0 = any device)
+	Devid uint64 `protobuf:"varint,10,opt,name=devid,proto3" json:"devid,omitempty"`
+	// Only chunks overlapping this physical byte range on devid (end 0 = no range)
+	DrangeStart   uint64 `protobuf:"varint,11,opt,name=drange_start,json=drangeStart,proto3" json:"drange_start,omitempty"`
+	DrangeEnd     uint64 `protobuf:"varint,12,opt,name=drange_end,json=drangeEnd,proto3" json:"drange_end,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -189,6 +200,55 @@ func (x *BalanceFilters) GetLimitChunks() int64 {
 	return 0
 }
 
+func (x *BalanceFilters) GetDataConvert() string {
+	if x != nil {
+		return x.DataConvert
+	}
+	return ""
+}
+
+func (x *BalanceFilters) GetMetadataConvert() string {
+	if x != nil {
+		return x.MetadataConvert
+	}
+	return ""
+}
+
+func (x *BalanceFilters) GetSystemConvert() string {
+	if x != nil {
+		return x.SystemConvert
+	}
+	return ""
+}
+
+func (x *BalanceFilters) GetSoft() bool {
+	if x != nil {
+		return x.Soft
+	}
+	return false
+}
+
+func (x *BalanceFilters) GetDevid() uint64 {
+	if x != nil {
+		return x.Devid
+	}
+	return 0
+}
+
+func (x *BalanceFilters) GetDrangeStart() uint64 {
+	if x != nil {
+		return x.DrangeStart
+	}
+	return 0
+}
+
+func (x *BalanceFilters) GetDrangeEnd() uint64 {
+	if x != nil {
+		return x.DrangeEnd
+	}
+	return 0
+}
+
 // Flags used when starting a balance, stored in history
 type BalanceFlags struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1008,110 +1068,1308 @@ func (x *ListBalanceHistoryResponse) GetEntries() []*BalanceHistoryEntry {
 	return nil
 }
 
-var File_api_v1_balance_proto protoreflect.FileDescriptor
+// Chunks of one block group type and profile
+type ChunkProfileUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`       // "Data", "Metadata", "System"
+	Profile       string                 `protobuf:"bytes,2,opt,name=profile,proto3" json:"profile,omitempty"` // "single", "DUP", "RAID1", etc.
+	Chunks        int64                  `protobuf:"varint,3,opt,name=chunks,proto3" json:"chunks,omitempty"`
+	Bytes         int64                  `protobuf:"varint,4,opt,name=bytes,proto3" json:"bytes,omitempty"` // Logical size of the chunks
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_api_v1_balance_proto_rawDesc = "" +
-	"\n" +
-	"\x14api/v1/balance.proto\x12\x06api.v1\"\xdc\x01\n" +
-	"\x13StartBalanceRequest\x12\x1f\n" +
-	"\vdevice_path\x18\x01 \x01(\tR\n" +
-	"devicePath\x120\n" +
-	"\afilters\x18\x02 \x01(\v2\x16.api.v1.BalanceFiltersR\afilters\x12#\n" +
-	"\rlimit_percent\x18\x03 \x01(\x05R\flimitPercent\x12\x1e\n" +
-	"\n" +
-	"background\x18\x04 \x01(\bR\n" +
-	"background\x12\x17\n" +
-	"\adry_run\x18\x05 \x01(\bR\x06dryRun\x12\x14\n" +
-	"\x05force\x18\x06 \x01(\bR\x05force\"\xa0\x01\n" +
-	"\x0eBalanceFilters\x12\x12\n" +
-	"\x04data\x18\x01 \x01(\bR\x04data\x12\x1a\n" +
-	"\bmetadata\x18\x02 \x01(\bR\bmetadata\x12\x16\n" +
-	"\x06system\x18\x03 \x01(\bR\x06system\x12#\n" +
-	"\rusage_percent\x18\x04 \x01(\x05R\fusagePercent\x12!\n" +
-	"\flimit_chunks\x18\x05 \x01(\x03R\vlimitChunks\"\xb4\x01\n" +
-	"\fBalanceFlags\x120\n" +
-	"\afilters\x18\x01 \x01(\v2\x16.api.v1.BalanceFiltersR\afilters\x12#\n" +
-	"\rlimit_percent\x18\x02 \x01(\x05R\flimitPercent\x12\x1e\n" +
-	"\n" +
-	"background\x18\x03 \x01(\bR\n" +
-	"background\x12\x17\n" +
-	"\adry_run\x18\x04 \x01(\bR\x06dryRun\x12\x14\n" +
-	"\x05force\x18\x05 \x01(\bR\x05force\"O\n" +
-	"\x14StartBalanceResponse\x12\x1d\n" +
-	"\n" +
-	"balance_id\x18\x01 \x01(\tR\tbalanceId\x12\x18\n" +
-	"\astarted\x18\x02 \x01(\bR\astarted\"7\n" +
-	"\x14CancelBalanceRequest\x12\x1f\n" +
-	"\vdevice_path\x18\x01 \x01(\tR\n" +
-	"devicePath\"1\n" +
-	"\x15CancelBalanceResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\":\n" +
-	"\x17GetBalanceStatusRequest\x12\x1f\n" +
-	"\vdevice_path\x18\x01 \x01(\tR\n" +
-	"devicePath\"\xd6\x03\n" +
-	"\x0fBalanceProgress\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1d\n" +
-	"\n" +
-	"is_running\x18\x02 \x01(\bR\tisRunning\x12!\n" +
-	"\ftotal_chunks\x18\x03 \x01(\x03R\vtotalChunks\x12\x1e\n" +
-	"\n" +
-	"considered\x18\x04 \x01(\x03R\n" +
-	"considered\x12\x1c\n" +
-	"\trelocated\x18\x05 \x01(\x03R\trelocated\x12\x12\n" +
-	"\x04left\x18\x06 \x01(\x03R\x04left\x12\x1d\n" +
-	"\n" +
-	"size_total\x18\a \x01(\x03R\tsizeTotal\x12%\n" +
-	"\x0esize_relocated\x18\b \x01(\x03R\rsizeRelocated\x12\x1d\n" +
-	"\n" +
-	"started_at\x18\t \x01(\x03R\tstartedAt\x12\x1f\n" +
-	"\vfinished_at\x18\n" +
-	" \x01(\x03R\n" +
-	"finishedAt\x12\x1a\n" +
-	"\bduration\x18\v \x01(\tR\bduration\x12)\n" +
-	"\x10duration_seconds\x18\f \x01(\x03R\x0fdurationSeconds\x12\x1f\n" +
-	"\vsoft_errors\x18\r \x01(\x05R\n" +
-	"softErrors\x12)\n" +
-	"\x10progress_percent\x18\x0e \x01(\x01R\x0fprogressPercent\"n\n" +
-	"\x18GetBalanceStatusResponse\x123\n" +
-	"\bprogress\x18\x01 \x01(\v2\x17.api.v1.BalanceProgressR\bprogress\x12\x1d\n" +
-	"\n" +
-	"is_running\x18\x02 \x01(\bR\tisRunning\"\x1c\n" +
-	"\x1aGetAllBalanceStatusRequest\"\xa6\x01\n" +
-	"\x17FilesystemBalanceStatus\x12\x12\n" +
-	"\x04path\x18\x01 \x01(\tR\x04path\x123\n" +
-	"\bprogress\x18\x02 \x01(\v2\x17.api.v1.BalanceProgressR\bprogress\x12\x1d\n" +
-	"\n" +
-	"is_running\x18\x03 \x01(\bR\tisRunning\x12#\n" +
-	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\"`\n" +
-	"\x1bGetAllBalanceStatusResponse\x12A\n" +
-	"\vfilesystems\x18\x01 \x03(\v2\x1f.api.v1.FilesystemBalanceStatusR\vfilesystems\"\xcc\x02\n" +
-	"\x13BalanceHistoryEntry\x12\x1d\n" +
-	"\n" +
-	"balance_id\x18\x01 \x01(\tR\tbalanceId\x12\x1f\n" +
-	"\vdevice_path\x18\x02 \x01(\tR\n" +
-	"devicePath\x12\x1d\n" +
-	"\n" +
-	"started_at\x18\x03 \x01(\x03R\tstartedAt\x12\x1f\n" +
-	"\vfinished_at\x18\x04 \x01(\x03R\n" +
-	"finishedAt\x12\x16\n" +
-	"\x06status\x18\x05 \x01(\tR\x06status\x12)\n" +
-	"\x10chunks_relocated\x18\x06 \x01(\x03R\x0fchunksRelocated\x12%\n" +
-	"\x0esize_relocated\x18\a \x01(\x03R\rsizeRelocated\x12\x1f\n" +
-	"\vsoft_errors\x18\b \x01(\x05R\n" +
-	"softErrors\x12*\n" +
-	"\x05flags\x18\t \x01(\v2\x14.api.v1.BalanceFlagsR\x05flags\"R\n" +
-	"\x19ListBalanceHistoryRequest\x12\x1f\n" +
-	"\vdevice_path\x18\x01 \x01(\tR\n" +
-	"devicePath\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit\"S\n" +
-	"\x1aListBalanceHistoryResponse\x125\n" +
-	"\aentries\x18\x01 \x03(\v2\x1b.api.v1.BalanceHistoryEntryR\aentries2\xc7\x03\n" +
+func (x *ChunkProfileUsage) Reset() {
+	*x = ChunkProfileUsage{}
+	mi := &file_api_v1_balance_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChunkProfileUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChunkProfileUsage) ProtoMessage() {}
+
+func (x *ChunkProfileUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_balance_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChunkProfileUsage.ProtoReflect.Descriptor instead.
+func (*ChunkProfileUsage) Descriptor() ([]byte, []int) {
+	return file_api_v1_balance_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ChunkProfileUsage) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ChunkProfileUsage) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *ChunkProfileUsage) GetChunks() int64 {
+	if x != nil {
+		return x.Chunks
+	}
+	return 0
+}
+
+func (x *ChunkProfileUsage) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+// A block group type with chunks in more than one profile
+type StaleProfiles struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Target        string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`                                 // Profile the remaining chunks should be converted to
+	TargetSource  string                 `protobuf:"bytes,3,opt,name=target_source,json=targetSource,proto3" json:"target_source,omitempty"` // "balance" (from a paused balance) or "kernel" (allocation preference, a guess)
+	Current       *ChunkProfileUsage     `protobuf:"bytes,4,opt,name=current,proto3" json:"current,omitempty"`                               // Chunks already in the target profile
+	Stale         []*ChunkProfileUsage   `protobuf:"bytes,5,rep,name=stale,proto3" json:"stale,omitempty"`                                   // Chunks in any other profile
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StaleProfiles) Reset() {
+	*x = StaleProfiles{}
+	mi := &file_api_v1_balance_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StaleProfiles) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StaleProfiles) ProtoMessage() {}
+
+func (x *StaleProfiles) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_balance_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StaleProfiles.ProtoReflect.Descriptor instead.
+func (*StaleProfiles) Descriptor() ([]byte, []int) {
+	return file_api_v1_balance_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StaleProfiles) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *StaleProfiles) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *StaleProfiles) GetTargetSource() string {
+	if x != nil {
+		return x.TargetSource
+	}
+	return ""
+}
+
+func (x *StaleProfiles) GetCurrent() *ChunkProfileUsage {
+	if x != nil {
+		return x.Current
+	}
+	return nil
+}
+
+func (x *StaleProfiles) GetStale() []*ChunkProfileUsage {
+	if x != nil {
+		return x.Stale
+	}
+	return nil
+}
+
+type GetProfileStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DevicePath    string                 `protobuf:"bytes,1,opt,name=device_path,json=devicePath,proto3" json:"device_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProfileStatusRequest) Reset() {
+	*x = GetProfileStatusRequest{}
+	mi := &file_api_v1_balance_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProfileStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProfileStatusRequest) ProtoMessage() {}
+
+func (x *GetProfileStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_balance_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProfileStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetProfileStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_balance_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetProfileStatusRequest) GetDevicePath() string {
+	if x != nil {
+		return x.DevicePath
+	}
+	return ""
+}
+
+type GetProfileStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profiles      []*ChunkProfileUsage   `protobuf:"bytes,1,rep,name=profiles,proto3" json:"profiles,omitempty"`
+	Stale         []*StaleProfiles       `protobuf:"bytes,2,rep,name=stale,proto3" json:"stale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProfileStatusResponse) Reset() {
+	*x = GetProfileStatusResponse{}
+	mi := &file_api_v1_balance_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProfileStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProfileStatusResponse) ProtoMessage() {}
+
+func (x *GetProfileStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_balance_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProfileStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetProfileStatusResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_balance_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetProfileStatusResponse) GetProfiles() []*ChunkProfileUsage {
+	if x != nil {
+		return x.Profiles
+	}
+	return nil
+}
+
+func (x *GetProfileStatusResponse) GetStale() []*StaleProfiles {
+	if x != nil {
+		return x.Stale
+	}
+	return nil
+}
+
+type FinishConversionRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	DevicePath string                 `protobuf:"bytes,1,opt,name=device_path,json=devicePath,proto3" json:"device_path,omitempty"`
+	// Override the detected targets (empty = use detected target). Required
+	// for types whose target_source is "kernel", since that is only a guess
+	DataTarget     string `protobuf:"bytes,2,opt,name=data_target,json=dataTarget,proto3" json:"data_target,omitempty"`
+	MetadataTarget string `protobuf:"bytes,3,opt,name=metadata_target,json=metadataTarget,proto3" json:"metadata_target,omitempty"`
+	SystemTarget   string `protobuf:"bytes,4,opt,name=system_target,json=systemTarget,proto3" json:"system_target,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *FinishConversionRequest) Reset() {
+	*x = FinishConversionRequest{}
+	mi := &file_api_v1_balance_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishConversionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishConversionRequest) ProtoMessage() {}
+
+func (x *FinishConversionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_balance_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishConversionRequest.ProtoReflect.Descriptor instead.
+func (*FinishConversionRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_balance_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *FinishConversionRequest) GetDevicePath() string {
+	if x != nil {
+		return x.DevicePath
+	}
+	return ""
+}
+
+func (x *FinishConversionRequest) GetDataTarget() string {
+	if x != nil {
+		return x.DataTarget
+	}
+	return ""
+}
+
+func (x *FinishConversionRequest) GetMetadataTarget() string {
+	if x != nil {
+		return x.MetadataTarget
+	}
+	return ""
+}
+
+func (x *FinishConversionRequest) GetSystemTarget() string {
+	if x != nil {
+		return x.SystemTarget
+	}
+	return ""
+}
+
+type FinishConversionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BalanceId     string                 `protobuf:"bytes,1,opt,name=balance_id,json=balanceId,proto3" json:"balance_id,omitempty"`
+	Started       bool                   `protobuf:"varint,2,opt,name=started,proto3" json:"started,omitempty"`
+	Filters       *BalanceFilters        `protobuf:"bytes,3,opt,name=filters,proto3" json:"filters,omitempty"` // Filters the balance was started with
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FinishConversionResponse) Reset() {
+	*x = FinishConversionResponse{}
+	mi := &file_api_v1_balance_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishConversionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishConversionResponse) ProtoMessage() {}
+
+func (x *FinishConversionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_balance_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishConversionResponse.ProtoReflect.Descriptor instead.
+func (*FinishConversionResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_balance_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *FinishConversionResponse) GetBalanceId() string {
+	if x != nil {
+		return x.BalanceId
+	}
+	return ""
+}
+
+func (x *FinishConversionResponse) GetStarted() bool {
+	if x != nil {
+		return x.Started
+	}
+	return false
+}
+
+func (x *FinishConversionResponse) GetFilters() *BalanceFilters {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+type AnalyzeAllocationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DevicePath    string                 `protobuf:"bytes,1,opt,name=device_path,json=devicePath,proto3" json:"device_path,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`       // "data" (default) or "metadata"
+	Profile       string                 `protobuf:"bytes,3,opt,name=profile,proto3" json:"profile,omitempty"` // Simulate this profile instead of the current one, e.g. "raid1c3"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeAllocationRequest) Reset() {
+	*x = AnalyzeAllocationRequest{}
+	mi := &file_api_v1_balance_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeAllocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeAllocationRequest) ProtoMessage() {}
+
+func (x *AnalyzeAllocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_balance_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeAllocationRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzeAllocationRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_balance_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AnalyzeAllocationRequest) GetDevicePath() string {
+	if x != nil {
+		return x.DevicePath
+	}
+	return ""
+}
+
+func (x *AnalyzeAllocationRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *AnalyzeAllocationRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+// Simulated outcome for one device
+type DeviceAllocationAnalysis struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Devid            uint64                 `protobuf:"varint,1,opt,name=devid,proto3" json:"devid,omitempty"`
+	Path             string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Size             int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Allocated        int64                  `protobuf:"varint,4,opt,name=allocated,proto3" json:"allocated,omitempty"`
+	Unallocated      int64                  `protobuf:"varint,5,opt,name=unallocated,proto3" json:"unallocated,omitempty"`
+	Stranded         int64                  `protobuf:"varint,6,opt,name=stranded,proto3" json:"stranded,omitempty"`                                         // Unallocated bytes no chunk can use with the current layout
+	BalancedStranded int64                  `protobuf:"varint,7,opt,name=balanced_stranded,json=balancedStranded,proto3" json:"balanced_stranded,omitempty"` // Unallocated bytes no chunk could use after a full balance
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DeviceAllocationAnalysis) Reset() {
+	*x = DeviceAllocationAnalysis{}
+	mi := &file_api_v1_balance_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceAllocationAnalysis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceAllocationAnalysis) ProtoMessage() {}
+
+func (x *DeviceAllocationAnalysis) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_balance_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceAllocationAnalysis.ProtoReflect.Descriptor instead.
+func (*DeviceAllocationAnalysis) Descriptor() ([]byte, []int) {
+	return file_api_v1_balance_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DeviceAllocationAnalysis) GetDevid() uint64 {
+	if x != nil {
+		return x.Devid
+	}
+	return 0
+}
+
+func (x *DeviceAllocationAnalysis) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DeviceAllocationAnalysis) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DeviceAllocationAnalysis) GetAllocated() int64 {
+	if x != nil {
+		return x.Allocated
+	}
+	return 0
+}
+
+func (x *DeviceAllocationAnalysis) GetUnallocated() int64 {
+	if x != nil {
+		return x.Unallocated
+	}
+	return 0
+}
+
+func (x *DeviceAllocationAnalysis) GetStranded() int64 {
+	if x != nil {
+		return x.Stranded
+	}
+	return 0
+}
+
+func (x *DeviceAllocationAnalysis) GetBalancedStranded() int64 {
+	if x != nil {
+		return x.BalancedStranded
+	}
+	return 0
+}
+
+// A devid balance that recovers stranded space
+type AllocationSuggestion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Devid         uint64                 `protobuf:"varint,1,opt,name=devid,proto3" json:"devid,omitempty"`
+	Limit         int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                                // Chunks to relocate with devid=N,limit=N
+	Usable        int64                  `protobuf:"varint,3,opt,name=usable,proto3" json:"usable,omitempty"`                              // Logical bytes allocatable afterwards
+	Recovers      int64                  `protobuf:"varint,4,opt,name=recovers,proto3" json:"recovers,omitempty"`                          // Additional logical bytes made allocatable
+	DrangeStart   uint64                 `protobuf:"varint,5,opt,name=drange_start,json=drangeStart,proto3" json:"drange_start,omitempty"` // Physical range on devid covering the same chunks
+	DrangeEnd     uint64                 `protobuf:"varint,6,opt,name=drange_end,json=drangeEnd,proto3" json:"drange_end,omitempty"`
+	DrangeChunks  int64                  `protobuf:"varint,7,opt,name=drange_chunks,json=drangeChunks,proto3" json:"drange_chunks,omitempty"` // Chunks matched by devid=N,drange=... alone
+	Filters       *BalanceFilters        `protobuf:"bytes,8,opt,name=filters,proto3" json:"filters,omitempty"`                                // Ready to pass to StartBalance
+	Command       string                 `protobuf:"bytes,9,opt,name=command,proto3" json:"command,omitempty"`                                // Equivalent btrfs-progs command
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AllocationSuggestion) Reset() {
+	*x = AllocationSuggestion{}
+	mi := &file_api_v1_balance_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AllocationSuggestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllocationSuggestion) ProtoMessage() {}
+
+func (x *AllocationSuggestion) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_balance_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllocationSuggestion.ProtoReflect.Descriptor instead.
+func (*AllocationSuggestion) Descriptor() ([]byte, []int) {
+	return file_api_v1_balance_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AllocationSuggestion) GetDevid() uint64 {
+	if x != nil {
+		return x.Devid
+	}
+	return 0
+}
+
+func (x *AllocationSuggestion) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *AllocationSuggestion) GetUsable() int64 {
+	if x != nil {
+		return x.Usable
+	}
+	return 0
+}
+
+func (x *AllocationSuggestion) GetRecovers() int64 {
+	if x != nil {
+		return x.Recovers
+	}
+	return 0
+}
+
+func (x *AllocationSuggestion) GetDrangeStart() uint64 {
+	if x != nil {
+		return x.DrangeStart
+	}
+	return 0
+}
+
+func (x *AllocationSuggestion) GetDrangeEnd() uint64 {
+	if x != nil {
+		return x.DrangeEnd
+	}
+	return 0
+}
+
+func (x *AllocationSuggestion) GetDrangeChunks() int64 {
+	if x != nil {
+		return x.DrangeChunks
+	}
+	return 0
+}
+
+func (x *AllocationSuggestion) GetFilters() *BalanceFilters {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *AllocationSuggestion) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+type AnalyzeAllocationResponse struct {
+	state            protoimpl.MessageState      `protogen:"open.v1"`
+	Type             string                      `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Profile          string                      `protobuf:"bytes,2,opt,name=profile,proto3" json:"profile,omitempty"`
+	Devices          []*DeviceAllocationAnalysis `protobuf:"bytes,3,rep,name=devices,proto3" json:"devices,omitempty"`
+	Unallocated      int64                       `protobuf:"varint,4,opt,name=unallocated,proto3" json:"unallocated,omitempty"`                                   // Raw unallocated bytes over all devices
+	Usable           int64                       `protobuf:"varint,5,opt,name=usable,proto3" json:"usable,omitempty"`                                             // Logical bytes that can still be allocated
+	Stranded         int64                       `protobuf:"varint,6,opt,name=stranded,proto3" json:"stranded,omitempty"`                                         // Raw bytes left unallocatable with the current layout
+	BalancedUsable   int64                       `protobuf:"varint,7,opt,name=balanced_usable,json=balancedUsable,proto3" json:"balanced_usable,omitempty"`       // Logical bytes allocatable after a full balance
+	BalancedStranded int64                       `protobuf:"varint,8,opt,name=balanced_stranded,json=balancedStranded,proto3" json:"balanced_stranded,omitempty"` // Raw bytes unallocatable even after a full balance
+	Recoverable      int64                       `protobuf:"varint,9,opt,name=recoverable,proto3" json:"recoverable,omitempty"`                                   // Logical space a balance would make allocatable
+	Suggestions      []*AllocationSuggestion     `protobuf:"bytes,10,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AnalyzeAllocationResponse) Reset() {
+	*x = AnalyzeAllocationResponse{}
+	mi := &file_api_v1_balance_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeAllocationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeAllocationResponse) ProtoMessage() {}
+
+func (x *AnalyzeAllocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_balance_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeAllocationResponse.ProtoReflect.Descriptor instead.
+func (*AnalyzeAllocationResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_balance_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *AnalyzeAllocationResponse) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *AnalyzeAllocationResponse) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *AnalyzeAllocationResponse) GetDevices() []*DeviceAllocationAnalysis {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *AnalyzeAllocationResponse) GetUnallocated() int64 {
+	if x != nil {
+		return x.Unallocated
+	}
+	return 0
+}
+
+func (x *AnalyzeAllocationResponse) GetUsable() int64 {
+	if x != nil {
+		return x.Usable
+	}
+	return 0
+}
+
+func (x *AnalyzeAllocationResponse) GetStranded() int64 {
+	if x != nil {
+		return x.Stranded
+	}
+	return 0
+}
+
+func (x *AnalyzeAllocationResponse) GetBalancedUsable() int64 {
+	if x != nil {
+		return x.BalancedUsable
+	}
+	return 0
+}
+
+func (x *AnalyzeAllocationResponse) GetBalancedStranded() int64 {
+	if x != nil {
+		return x.BalancedStranded
+	}
+	return 0
+}
+
+func (x *AnalyzeAllocationResponse) GetRecoverable() int64 {
+	if x != nil {
+		return x.Recoverable
+	}
+	return 0
+}
+
+func (x *AnalyzeAllocationResponse) GetSuggestions() []*AllocationSuggestion {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+type PlanBalanceRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	DevicePath string                 `protobuf:"bytes,1,opt,name=device_path,json=devicePath,proto3" json:"device_path,omitempty"`
+	Type       string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // "data" (default) or "metadata"
+	// Raw unallocated bytes to end up with (0 = 10% of the device size)
+	GoalUnallocated int64 `protobuf:"varint,3,opt,name=goal_unallocated,json=goalUnallocated,proto3" json:"goal_unallocated,omitempty"`
+	// usage= filters to simulate (empty = 1, 5, 10, 15, ... 100)
+	Thresholds []int32 `protobuf:"varint,4,rep,packed,name=thresholds,proto3" json:"thresholds,omitempty"`
+	// Start the recommended balance
+	Start         bool  `protobuf:"varint,5,opt,name=start,proto3" json:"start,omitempty"`
+	LimitPercent  int32 `protobuf:"varint,6,opt,name=limit_percent,json=limitPercent,proto3" json:"limit_percent,omitempty"` // Passed on to the balance when starting
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlanBalanceRequest) Reset() {
+	*x = PlanBalanceRequest{}
+	mi := &file_api_v1_balance_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlanBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanBalanceRequest) ProtoMessage() {}
+
+func (x *PlanBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_balance_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanBalanceRequest.ProtoReflect.Descriptor instead.
+func (*PlanBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_balance_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PlanBalanceRequest) GetDevicePath() string {
+	if x != nil {
+		return x.DevicePath
+	}
+	return ""
+}
+
+func (x *PlanBalanceRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PlanBalanceRequest) GetGoalUnallocated() int64 {
+	if x != nil {
+		return x.GoalUnallocated
+	}
+	return 0
+}
+
+func (x *PlanBalanceRequest) GetThresholds() []int32 {
+	if x != nil {
+		return x.Thresholds
+	}
+	return nil
+}
+
+func (x *PlanBalanceRequest) GetStart() bool {
+	if x != nil {
+		return x.Start
+	}
+	return false
+}
+
+func (x *PlanBalanceRequest) GetLimitPercent() int32 {
+	if x != nil {
+		return x.LimitPercent
+	}
+	return 0
+}
+
+// Simulated outcome of one usage= balance
+type BalancePlanRun struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Usage            int32                  `protobuf:"varint,1,opt,name=usage,proto3" json:"usage,omitempty"`
+	Chunks           int64                  `protobuf:"varint,2,opt,name=chunks,proto3" json:"chunks,omitempty"`                           // Chunks the filter selects
+	ChunkBytes       int64                  `protobuf:"varint,3,opt,name=chunk_bytes,json=chunkBytes,proto3" json:"chunk_bytes,omitempty"` // Their logical size
+	MovedBytes       int64                  `protobuf:"varint,4,opt,name=moved_bytes,json=movedBytes,proto3" json:"moved_bytes,omitempty"` // Used bytes rewritten
+	NewChunks        int64                  `protobuf:"varint,5,opt,name=new_chunks,json=newChunks,proto3" json:"new_chunks,omitempty"`    // Chunks allocated for data that doesn't fit elsewhere
+	FreedBytes       int64                  `protobuf:"varint,6,opt,name=freed_bytes,json=freedBytes,proto3" json:"freed_bytes,omitempty"` // Raw bytes returned to unallocated
+	UnallocatedAfter int64                  `protobuf:"varint,7,opt,name=unallocated_after,json=unallocatedAfter,proto3" json:"unallocated_after,omitempty"`
+	ReachesGoal      bool                   `protobuf:"varint,8,opt,name=reaches_goal,json=reachesGoal,proto3" json:"reaches_goal,omitempty"`
+	Filters          *BalanceFilters        `protobuf:"bytes,9,opt,name=filters,proto3" json:"filters,omitempty"`  // Ready to pass to StartBalance
+	Command          string                 `protobuf:"bytes,10,opt,name=command,proto3" json:"command,omitempty"` // Equivalent btrfs-progs command
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *BalancePlanRun) Reset() {
+	*x = BalancePlanRun{}
+	mi := &file_api_v1_balance_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BalancePlanRun) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BalancePlanRun) ProtoMessage() {}
+
+func (x *BalancePlanRun) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_balance_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BalancePlanRun.ProtoReflect.Descriptor instead.
+func (*BalancePlanRun) Descriptor() ([]byte, []int) {
+	return file_api_v1_balance_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *BalancePlanRun) GetUsage() int32 {
+	if x != nil {
+		return x.Usage
+	}
+	return 0
+}
+
+func (x *BalancePlanRun) GetChunks() int64 {
+	if x != nil {
+		return x.Chunks
+	}
+	return 0
+}
+
+func (x *BalancePlanRun) GetChunkBytes() int64 {
+	if x != nil {
+		return x.ChunkBytes
+	}
+	return 0
+}
+
+func (x *BalancePlanRun) GetMovedBytes() int64 {
+	if x != nil {
+		return x.MovedBytes
+	}
+	return 0
+}
+
+func (x *BalancePlanRun) GetNewChunks() int64 {
+	if x != nil {
+		return x.NewChunks
+	}
+	return 0
+}
+
+func (x *BalancePlanRun) GetFreedBytes() int64 {
+	if x != nil {
+		return x.FreedBytes
+	}
+	return 0
+}
+
+func (x *BalancePlanRun) GetUnallocatedAfter() int64 {
+	if x != nil {
+		return x.UnallocatedAfter
+	}
+	return 0
+}
+
+func (x *BalancePlanRun) GetReachesGoal() bool {
+	if x != nil {
+		return x.ReachesGoal
+	}
+	return false
+}
+
+func (x *BalancePlanRun) GetFilters() *BalanceFilters {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *BalancePlanRun) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+type PlanBalanceResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Type        string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Chunks      int64                  `protobuf:"varint,2,opt,name=chunks,proto3" json:"chunks,omitempty"` // All chunks of the type
+	Length      int64                  `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`
+	Used        int64                  `protobuf:"varint,4,opt,name=used,proto3" json:"used,omitempty"`
+	ChunkSize   int64                  `protobuf:"varint,5,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"` // Assumed size of new chunks
+	Unallocated int64                  `protobuf:"varint,6,opt,name=unallocated,proto3" json:"unallocated,omitempty"`              // Raw unallocated bytes now
+	Goal        int64                  `protobuf:"varint,7,opt,name=goal,proto3" json:"goal,omitempty"`
+	Runs        []*BalancePlanRun      `protobuf:"bytes,8,rep,name=runs,proto3" json:"runs,omitempty"`
+	Recommended int32                  `protobuf:"varint,9,opt,name=recommended,proto3" json:"recommended,omitempty"` // Index into runs, -1 if no balance is needed or helps
+	// Set when start was requested
+	BalanceId     string `protobuf:"bytes,10,opt,name=balance_id,json=balanceId,proto3" json:"balance_id,omitempty"`
+	Started       bool   `protobuf:"varint,11,opt,name=started,proto3" json:"started,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlanBalanceResponse) Reset() {
+	*x = PlanBalanceResponse{}
+	mi := &file_api_v1_balance_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlanBalanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanBalanceResponse) ProtoMessage() {}
+
+func (x *PlanBalanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_balance_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanBalanceResponse.ProtoReflect.Descriptor instead.
+func (*PlanBalanceResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_balance_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *PlanBalanceResponse) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PlanBalanceResponse) GetChunks() int64 {
+	if x != nil {
+		return x.Chunks
+	}
+	return 0
+}
+
+func (x *PlanBalanceResponse) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *PlanBalanceResponse) GetUsed() int64 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *PlanBalanceResponse) GetChunkSize() int64 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+func (x *PlanBalanceResponse) GetUnallocated() int64 {
+	if x != nil {
+		return x.Unallocated
+	}
+	return 0
+}
+
+func (x *PlanBalanceResponse) GetGoal() int64 {
+	if x != nil {
+		return x.Goal
+	}
+	return 0
+}
+
+func (x *PlanBalanceResponse) GetRuns() []*BalancePlanRun {
+	if x != nil {
+		return x.Runs
+	}
+	return nil
+}
+
+func (x *PlanBalanceResponse) GetRecommended() int32 {
+	if x != nil {
+		return x.Recommended
+	}
+	return 0
+}
+
+func (x *PlanBalanceResponse) GetBalanceId() string {
+	if x != nil {
+		return x.BalanceId
+	}
+	return ""
+}
+
+func (x *PlanBalanceResponse) GetStarted() bool {
+	if x != nil {
+		return x.Started
+	}
+	return false
+}
+
+var File_api_v1_balance_proto protoreflect.FileDescriptor
+
+const file_api_v1_balance_proto_rawDesc = "" +
+	"\n" +
+	"\x14api/v1/balance.proto\x12\x06api.v1\"\xdc\x01\n" +
+	"\x13StartBalanceRequest\x12\x1f\n" +
+	"\vdevice_path\x18\x01 \x01(\tR\n" +
+	"devicePath\x120\n" +
+	"\afilters\x18\x02 \x01(\v2\x16.api.v1.BalanceFiltersR\afilters\x12#\n" +
+	"\rlimit_percent\x18\x03 \x01(\x05R\flimitPercent\x12\x1e\n" +
+	"\n" +
+	"background\x18\x04 \x01(\bR\n" +
+	"background\x12\x17\n" +
+	"\adry_run\x18\x05 \x01(\bR\x06dryRun\x12\x14\n" +
+	"\x05force\x18\x06 \x01(\bR\x05force\"\x81\x03\n" +
+	"\x0eBalanceFilters\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\bR\x04data\x12\x1a\n" +
+	"\bmetadata\x18\x02 \x01(\bR\bmetadata\x12\x16\n" +
+	"\x06system\x18\x03 \x01(\bR\x06system\x12#\n" +
+	"\rusage_percent\x18\x04 \x01(\x05R\fusagePercent\x12!\n" +
+	"\flimit_chunks\x18\x05 \x01(\x03R\vlimitChunks\x12!\n" +
+	"\fdata_convert\x18\x06 \x01(\tR\vdataConvert\x12)\n" +
+	"\x10metadata_convert\x18\a \x01(\tR\x0fmetadataConvert\x12%\n" +
+	"\x0esystem_convert\x18\b \x01(\tR\rsystemConvert\x12\x12\n" +
+	"\x04soft\x18\t \x01(\bR\x04soft\x12\x14\n" +
+	"\x05devid\x18\n" +
+	" \x01(\x04R\x05devid\x12!\n" +
+	"\fdrange_start\x18\v \x01(\x04R\vdrangeStart\x12\x1d\n" +
+	"\n" +
+	"drange_end\x18\f \x01(\x04R\tdrangeEnd\"\xb4\x01\n" +
+	"\fBalanceFlags\x120\n" +
+	"\afilters\x18\x01 \x01(\v2\x16.api.v1.BalanceFiltersR\afilters\x12#\n" +
+	"\rlimit_percent\x18\x02 \x01(\x05R\flimitPercent\x12\x1e\n" +
+	"\n" +
+	"background\x18\x03 \x01(\bR\n" +
+	"background\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\x12\x14\n" +
+	"\x05force\x18\x05 \x01(\bR\x05force\"O\n" +
+	"\x14StartBalanceResponse\x12\x1d\n" +
+	"\n" +
+	"balance_id\x18\x01 \x01(\tR\tbalanceId\x12\x18\n" +
+	"\astarted\x18\x02 \x01(\bR\astarted\"7\n" +
+	"\x14CancelBalanceRequest\x12\x1f\n" +
+	"\vdevice_path\x18\x01 \x01(\tR\n" +
+	"devicePath\"1\n" +
+	"\x15CancelBalanceResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\":\n" +
+	"\x17GetBalanceStatusRequest\x12\x1f\n" +
+	"\vdevice_path\x18\x01 \x01(\tR\n" +
+	"devicePath\"\xd6\x03\n" +
+	"\x0fBalanceProgress\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"is_running\x18\x02 \x01(\bR\tisRunning\x12!\n" +
+	"\ftotal_chunks\x18\x03 \x01(\x03R\vtotalChunks\x12\x1e\n" +
+	"\n" +
+	"considered\x18\x04 \x01(\x03R\n" +
+	"considered\x12\x1c\n" +
+	"\trelocated\x18\x05 \x01(\x03R\trelocated\x12\x12\n" +
+	"\x04left\x18\x06 \x01(\x03R\x04left\x12\x1d\n" +
+	"\n" +
+	"size_total\x18\a \x01(\x03R\tsizeTotal\x12%\n" +
+	"\x0esize_relocated\x18\b \x01(\x03R\rsizeRelocated\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\t \x01(\x03R\tstartedAt\x12\x1f\n" +
+	"\vfinished_at\x18\n" +
+	" \x01(\x03R\n" +
+	"finishedAt\x12\x1a\n" +
+	"\bduration\x18\v \x01(\tR\bduration\x12)\n" +
+	"\x10duration_seconds\x18\f \x01(\x03R\x0fdurationSeconds\x12\x1f\n" +
+	"\vsoft_errors\x18\r \x01(\x05R\n" +
+	"softErrors\x12)\n" +
+	"\x10progress_percent\x18\x0e \x01(\x01R\x0fprogressPercent\"n\n" +
+	"\x18GetBalanceStatusResponse\x123\n" +
+	"\bprogress\x18\x01 \x01(\v2\x17.api.v1.BalanceProgressR\bprogress\x12\x1d\n" +
+	"\n" +
+	"is_running\x18\x02 \x01(\bR\tisRunning\"\x1c\n" +
+	"\x1aGetAllBalanceStatusRequest\"\xa6\x01\n" +
+	"\x17FilesystemBalanceStatus\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x123\n" +
+	"\bprogress\x18\x02 \x01(\v2\x17.api.v1.BalanceProgressR\bprogress\x12\x1d\n" +
+	"\n" +
+	"is_running\x18\x03 \x01(\bR\tisRunning\x12#\n" +
+	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\"`\n" +
+	"\x1bGetAllBalanceStatusResponse\x12A\n" +
+	"\vfilesystems\x18\x01 \x03(\v2\x1f.api.v1.FilesystemBalanceStatusR\vfilesystems\"\xcc\x02\n" +
+	"\x13BalanceHistoryEntry\x12\x1d\n" +
+	"\n" +
+	"balance_id\x18\x01 \x01(\tR\tbalanceId\x12\x1f\n" +
+	"\vdevice_path\x18\x02 \x01(\tR\n" +
+	"devicePath\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x03 \x01(\x03R\tstartedAt\x12\x1f\n" +
+	"\vfinished_at\x18\x04 \x01(\x03R\n" +
+	"finishedAt\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12)\n" +
+	"\x10chunks_relocated\x18\x06 \x01(\x03R\x0fchunksRelocated\x12%\n" +
+	"\x0esize_relocated\x18\a \x01(\x03R\rsizeRelocated\x12\x1f\n" +
+	"\vsoft_errors\x18\b \x01(\x05R\n" +
+	"softErrors\x12*\n" +
+	"\x05flags\x18\t \x01(\v2\x14.api.v1.BalanceFlagsR\x05flags\"R\n" +
+	"\x19ListBalanceHistoryRequest\x12\x1f\n" +
+	"\vdevice_path\x18\x01 \x01(\tR\n" +
+	"devicePath\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"S\n" +
+	"\x1aListBalanceHistoryResponse\x125\n" +
+	"\aentries\x18\x01 \x03(\v2\x1b.api.v1.BalanceHistoryEntryR\aentries\"o\n" +
+	"\x11ChunkProfileUsage\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
+	"\aprofile\x18\x02 \x01(\tR\aprofile\x12\x16\n" +
+	"\x06chunks\x18\x03 \x01(\x03R\x06chunks\x12\x14\n" +
+	"\x05bytes\x18\x04 \x01(\x03R\x05bytes\"\xc6\x01\n" +
+	"\rStaleProfiles\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12#\n" +
+	"\rtarget_source\x18\x03 \x01(\tR\ftargetSource\x123\n" +
+	"\acurrent\x18\x04 \x01(\v2\x19.api.v1.ChunkProfileUsageR\acurrent\x12/\n" +
+	"\x05stale\x18\x05 \x03(\v2\x19.api.v1.ChunkProfileUsageR\x05stale\":\n" +
+	"\x17GetProfileStatusRequest\x12\x1f\n" +
+	"\vdevice_path\x18\x01 \x01(\tR\n" +
+	"devicePath\"~\n" +
+	"\x18GetProfileStatusResponse\x125\n" +
+	"\bprofiles\x18\x01 \x03(\v2\x19.api.v1.ChunkProfileUsageR\bprofiles\x12+\n" +
+	"\x05stale\x18\x02 \x03(\v2\x15.api.v1.StaleProfilesR\x05stale\"\xa9\x01\n" +
+	"\x17FinishConversionRequest\x12\x1f\n" +
+	"\vdevice_path\x18\x01 \x01(\tR\n" +
+	"devicePath\x12\x1f\n" +
+	"\vdata_target\x18\x02 \x01(\tR\n" +
+	"dataTarget\x12'\n" +
+	"\x0fmetadata_target\x18\x03 \x01(\tR\x0emetadataTarget\x12#\n" +
+	"\rsystem_target\x18\x04 \x01(\tR\fsystemTarget\"\x85\x01\n" +
+	"\x18FinishConversionResponse\x12\x1d\n" +
+	"\n" +
+	"balance_id\x18\x01 \x01(\tR\tbalanceId\x12\x18\n" +
+	"\astarted\x18\x02 \x01(\bR\astarted\x120\n" +
+	"\afilters\x18\x03 \x01(\v2\x16.api.v1.BalanceFiltersR\afilters\"i\n" +
+	"\x18AnalyzeAllocationRequest\x12\x1f\n" +
+	"\vdevice_path\x18\x01 \x01(\tR\n" +
+	"devicePath\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x18\n" +
+	"\aprofile\x18\x03 \x01(\tR\aprofile\"\xe1\x01\n" +
+	"\x18DeviceAllocationAnalysis\x12\x14\n" +
+	"\x05devid\x18\x01 \x01(\x04R\x05devid\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x1c\n" +
+	"\tallocated\x18\x04 \x01(\x03R\tallocated\x12 \n" +
+	"\vunallocated\x18\x05 \x01(\x03R\vunallocated\x12\x1a\n" +
+	"\bstranded\x18\x06 \x01(\x03R\bstranded\x12+\n" +
+	"\x11balanced_stranded\x18\a \x01(\x03R\x10balancedStranded\"\xa9\x02\n" +
+	"\x14AllocationSuggestion\x12\x14\n" +
+	"\x05devid\x18\x01 \x01(\x04R\x05devid\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x03R\x05limit\x12\x16\n" +
+	"\x06usable\x18\x03 \x01(\x03R\x06usable\x12\x1a\n" +
+	"\brecovers\x18\x04 \x01(\x03R\brecovers\x12!\n" +
+	"\fdrange_start\x18\x05 \x01(\x04R\vdrangeStart\x12\x1d\n" +
+	"\n" +
+	"drange_end\x18\x06 \x01(\x04R\tdrangeEnd\x12#\n" +
+	"\rdrange_chunks\x18\a \x01(\x03R\fdrangeChunks\x120\n" +
+	"\afilters\x18\b \x01(\v2\x16.api.v1.BalanceFiltersR\afilters\x12\x18\n" +
+	"\acommand\x18\t \x01(\tR\acommand\"\x93\x03\n" +
+	"\x19AnalyzeAllocationResponse\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
+	"\aprofile\x18\x02 \x01(\tR\aprofile\x12:\n" +
+	"\adevices\x18\x03 \x03(\v2 .api.v1.DeviceAllocationAnalysisR\adevices\x12 \n" +
+	"\vunallocated\x18\x04 \x01(\x03R\vunallocated\x12\x16\n" +
+	"\x06usable\x18\x05 \x01(\x03R\x06usable\x12\x1a\n" +
+	"\bstranded\x18\x06 \x01(\x03R\bstranded\x12'\n" +
+	"\x0fbalanced_usable\x18\a \x01(\x03R\x0ebalancedUsable\x12+\n" +
+	"\x11balanced_stranded\x18\b \x01(\x03R\x10balancedStranded\x12 \n" +
+	"\vrecoverable\x18\t \x01(\x03R\vrecoverable\x12>\n" +
+	"\vsuggestions\x18\n" +
+	" \x03(\v2\x1c.api.v1.AllocationSuggestionR\vsuggestions\"\xcf\x01\n" +
+	"\x12PlanBalanceRequest\x12\x1f\n" +
+	"\vdevice_path\x18\x01 \x01(\tR\n" +
+	"devicePath\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12)\n" +
+	"\x10goal_unallocated\x18\x03 \x01(\x03R\x0fgoalUnallocated\x12\x1e\n" +
+	"\n" +
+	"thresholds\x18\x04 \x03(\x05R\n" +
+	"thresholds\x12\x14\n" +
+	"\x05start\x18\x05 \x01(\bR\x05start\x12#\n" +
+	"\rlimit_percent\x18\x06 \x01(\x05R\flimitPercent\"\xdc\x02\n" +
+	"\x0eBalancePlanRun\x12\x14\n" +
+	"\x05usage\x18\x01 \x01(\x05R\x05usage\x12\x16\n" +
+	"\x06chunks\x18\x02 \x01(\x03R\x06chunks\x12\x1f\n" +
+	"\vchunk_bytes\x18\x03 \x01(\x03R\n" +
+	"chunkBytes\x12\x1f\n" +
+	"\vmoved_bytes\x18\x04 \x01(\x03R\n" +
+	"movedBytes\x12\x1d\n" +
+	"\n" +
+	"new_chunks\x18\x05 \x01(\x03R\tnewChunks\x12\x1f\n" +
+	"\vfreed_bytes\x18\x06 \x01(\x03R\n" +
+	"freedBytes\x12+\n" +
+	"\x11unallocated_after\x18\a \x01(\x03R\x10unallocatedAfter\x12!\n" +
+	"\freaches_goal\x18\b \x01(\bR\vreachesGoal\x120\n" +
+	"\afilters\x18\t \x01(\v2\x16.api.v1.BalanceFiltersR\afilters\x12\x18\n" +
+	"\acommand\x18\n" +
+	" \x01(\tR\acommand\"\xc9\x02\n" +
+	"\x13PlanBalanceResponse\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x16\n" +
+	"\x06chunks\x18\x02 \x01(\x03R\x06chunks\x12\x16\n" +
+	"\x06length\x18\x03 \x01(\x03R\x06length\x12\x12\n" +
+	"\x04used\x18\x04 \x01(\x03R\x04used\x12\x1d\n" +
+	"\n" +
+	"chunk_size\x18\x05 \x01(\x03R\tchunkSize\x12 \n" +
+	"\vunallocated\x18\x06 \x01(\x03R\vunallocated\x12\x12\n" +
+	"\x04goal\x18\a \x01(\x03R\x04goal\x12*\n" +
+	"\x04runs\x18\b \x03(\v2\x16.api.v1.BalancePlanRunR\x04runs\x12 \n" +
+	"\vrecommended\x18\t \x01(\x05R\vrecommended\x12\x1d\n" +
+	"\n" +
+	"balance_id\x18\n" +
+	" \x01(\tR\tbalanceId\x12\x18\n" +
+	"\astarted\x18\v \x01(\bR\astarted2\x9f\x06\n" +
 	"\x0eBalanceService\x12K\n" +
 	"\fStartBalance\x12\x1b.api.v1.StartBalanceRequest\x1a\x1c.api.v1.StartBalanceResponse\"\x00\x12N\n" +
 	"\rCancelBalance\x12\x1c.api.v1.CancelBalanceRequest\x1a\x1d.api.v1.CancelBalanceResponse\"\x00\x12W\n" +
 	"\x10GetBalanceStatus\x12\x1f.api.v1.GetBalanceStatusRequest\x1a .api.v1.GetBalanceStatusResponse\"\x00\x12`\n" +
 	"\x13GetAllBalanceStatus\x12\".api.v1.GetAllBalanceStatusRequest\x1a#.api.v1.GetAllBalanceStatusResponse\"\x00\x12]\n" +
-	"\x12ListBalanceHistory\x12!.api.v1.ListBalanceHistoryRequest\x1a\".api.v1.ListBalanceHistoryResponse\"\x00B\x7f\n" +
+	"\x12ListBalanceHistory\x12!.api.v1.ListBalanceHistoryRequest\x1a\".api.v1.ListBalanceHistoryResponse\"\x00\x12W\n" +
+	"\x10GetProfileStatus\x12\x1f.api.v1.GetProfileStatusRequest\x1a .api.v1.GetProfileStatusResponse\"\x00\x12W\n" +
+	"\x10FinishConversion\x12\x1f.api.v1.FinishConversionRequest\x1a .api.v1.FinishConversionResponse\"\x00\x12Z\n" +
+	"\x11AnalyzeAllocation\x12 .api.v1.AnalyzeAllocationRequest\x1a!.api.v1.AnalyzeAllocationResponse\"\x00\x12H\n" +
+	"\vPlanBalance\x12\x1a.api.v1.PlanBalanceRequest\x1a\x1b.api.v1.PlanBalanceResponse\"\x00B\x7f\n" +
 	"\n" +
 	"com.api.v1B\fBalanceProtoP\x01Z*github.com/elee1766/gobtr/gen/api/v1;apiv1\xa2\x02\x03AXX\xaa\x02\x06Api.V1\xca\x02\x06Api\\V1\xe2\x02\x12Api\\V1\\GPBMetadata\xea\x02\aApi::V1b\x06proto3"
 
@@ -1127,7 +2385,7 @@ func file_api_v1_balance_proto_rawDescGZIP() []byte {
 	return file_api_v1_balance_proto_rawDescData
 }
 
-var file_api_v1_balance_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_api_v1_balance_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
 var file_api_v1_balance_proto_goTypes = []any{
 	(*StartBalanceRequest)(nil),         // 0: api.v1.StartBalanceRequest
 	(*BalanceFilters)(nil),              // 1: api.v1.BalanceFilters
@@ -1144,6 +2402,19 @@ var file_api_v1_balance_proto_goTypes = []any{
 	(*BalanceHistoryEntry)(nil),         // 12: api.v1.BalanceHistoryEntry
 	(*ListBalanceHistoryRequest)(nil),   // 13: api.v1.ListBalanceHistoryRequest
 	(*ListBalanceHistoryResponse)(nil),  // 14: api.v1.ListBalanceHistoryResponse
+	(*ChunkProfileUsage)(nil),           // 15: api.v1.ChunkProfileUsage
+	(*StaleProfiles)(nil),               // 16: api.v1.StaleProfiles
+	(*GetProfileStatusRequest)(nil),     // 17: api.v1.GetProfileStatusRequest
+	(*GetProfileStatusResponse)(nil),    // 18: api.v1.GetProfileStatusResponse
+	(*FinishConversionRequest)(nil),     // 19: api.v1.FinishConversionRequest
+	(*FinishConversionResponse)(nil),    // 20: api.v1.FinishConversionResponse
+	(*AnalyzeAllocationRequest)(nil),    // 21: api.v1.AnalyzeAllocationRequest
+	(*DeviceAllocationAnalysis)(nil),    // 22: api.v1.DeviceAllocationAnalysis
+	(*AllocationSuggestion)(nil),        // 23: api.v1.AllocationSuggestion
+	(*AnalyzeAllocationResponse)(nil),   // 24: api.v1.AnalyzeAllocationResponse
+	(*PlanBalanceRequest)(nil),          // 25: api.v1.PlanBalanceRequest
+	(*BalancePlanRun)(nil),              // 26: api.v1.BalancePlanRun
+	(*PlanBalanceResponse)(nil),         // 27: api.v1.PlanBalanceResponse
 }
 var file_api_v1_balance_proto_depIdxs = []int32{
 	1,  // 0: api.v1.StartBalanceRequest.filters:type_name -> api.v1.BalanceFilters
@@ -1153,21 +2424,39 @@ var file_api_v1_balance_proto_depIdxs = []int32{
 	10, // 4: api.v1.GetAllBalanceStatusResponse.filesystems:type_name -> api.v1.FilesystemBalanceStatus
 	2,  // 5: api.v1.BalanceHistoryEntry.flags:type_name -> api.v1.BalanceFlags
 	12, // 6: api.v1.ListBalanceHistoryResponse.entries:type_name -> api.v1.BalanceHistoryEntry
-	0,  // 7: api.v1.BalanceService.StartBalance:input_type -> api.v1.StartBalanceRequest
-	4,  // 8: api.v1.BalanceService.CancelBalance:input_type -> api.v1.CancelBalanceRequest
-	6,  // 9: api.v1.BalanceService.GetBalanceStatus:input_type -> api.v1.GetBalanceStatusRequest
-	9,  // 10: api.v1.BalanceService.GetAllBalanceStatus:input_type -> api.v1.GetAllBalanceStatusRequest
-	13, // 11: api.v1.BalanceService.ListBalanceHistory:input_type -> api.v1.ListBalanceHistoryRequest
-	3,  // 12: api.v1.BalanceService.StartBalance:output_type -> api.v1.StartBalanceResponse
-	5,  // 13: api.v1.BalanceService.CancelBalance:output_type -> api.v1.CancelBalanceResponse
-	8,  // 14: api.v1.BalanceService.GetBalanceStatus:output_type -> api.v1.GetBalanceStatusResponse
-	11, // 15: api.v1.BalanceService.GetAllBalanceStatus:output_type -> api.v1.GetAllBalanceStatusResponse
-	14, // 16: api.v1.BalanceService.ListBalanceHistory:output_type -> api.v1.ListBalanceHistoryResponse
-	12, // [12:17] is the sub-list for method output_type
-	7,  // [7:12] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	15, // 7: api.v1.StaleProfiles.current:type_name -> api.v1.ChunkProfileUsage
+	15, // 8: api.v1.StaleProfiles.stale:type_name -> api.v1.ChunkProfileUsage
+	15, // 9: api.v1.GetProfileStatusResponse.profiles:type_name -> api.v1.ChunkProfileUsage
+	16, // 10: api.v1.GetProfileStatusResponse.stale:type_name -> api.v1.StaleProfiles
+	1,  // 11: api.v1.FinishConversionResponse.filters:type_name -> api.v1.BalanceFilters
+	1,  // 12: api.v1.AllocationSuggestion.filters:type_name -> api.v1.BalanceFilters
+	22, // 13: api.v1.AnalyzeAllocationResponse.devices:type_name -> api.v1.DeviceAllocationAnalysis
+	23, // 14: api.v1.AnalyzeAllocationResponse.suggestions:type_name -> api.v1.AllocationSuggestion
+	1,  // 15: api.v1.BalancePlanRun.filters:type_name -> api.v1.BalanceFilters
+	26, // 16: api.v1.PlanBalanceResponse.runs:type_name -> api.v1.BalancePlanRun
+	0,  // 17: api.v1.BalanceService.StartBalance:input_type -> api.v1.StartBalanceRequest
+	4,  // 18: api.v1.BalanceService.CancelBalance:input_type -> api.v1.CancelBalanceRequest
+	6,  // 19: api.v1.BalanceService.GetBalanceStatus:input_type -> api.v1.GetBalanceStatusRequest
+	9,  // 20: api.v1.BalanceService.GetAllBalanceStatus:input_type -> api.v1.GetAllBalanceStatusRequest
+	13, // 21: api.v1.BalanceService.ListBalanceHistory:input_type -> api.v1.ListBalanceHistoryRequest
+	17, // 22: api.v1.BalanceService.GetProfileStatus:input_type -> api.v1.GetProfileStatusRequest
+	19, // 23: api.v1.BalanceService.FinishConversion:input_type -> api.v1.FinishConversionRequest
+	21, // 24: api.v1.BalanceService.AnalyzeAllocation:input_type -> api.v1.AnalyzeAllocationRequest
+	25, // 25: api.v1.BalanceService.PlanBalance:input_type -> api.v1.PlanBalanceRequest
+	3,  // 26: api.v1.BalanceService.StartBalance:output_type -> api.v1.StartBalanceResponse
+	5,  // 27: api.v1.BalanceService.CancelBalance:output_type -> api.v1.CancelBalanceResponse
+	8,  // 28: api.v1.BalanceService.GetBalanceStatus:output_type -> api.v1.GetBalanceStatusResponse
+	11, // 29: api.v1.BalanceService.GetAllBalanceStatus:output_type -> api.v1.GetAllBalanceStatusResponse
+	14, // 30: api.v1.BalanceService.ListBalanceHistory:output_type -> api.v1.ListBalanceHistoryResponse
+	18, // 31: api.v1.BalanceService.GetProfileStatus:output_type -> api.v1.GetProfileStatusResponse
+	20, // 32: api.v1.BalanceService.FinishConversion:output_type -> api.v1.FinishConversionResponse
+	24, // 33: api.v1.BalanceService.AnalyzeAllocation:output_type -> api.v1.AnalyzeAllocationResponse
+	27, // 34: api.v1.BalanceService.PlanBalance:output_type -> api.v1.PlanBalanceResponse
+	26, // [26:35] is the sub-list for method output_type
+	17, // [17:26] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_api_v1_balance_proto_init() }
@@ -1181,7 +2470,7 @@ func file_api_v1_balance_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_balance_proto_rawDesc), len(file_api_v1_balance_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   15,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
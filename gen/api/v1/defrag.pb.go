@@ -0,0 +1,839 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: api/v1/defrag.proto
+
+package apiv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Which files a defrag job picks and how they are defragmented
+type DefragOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Files, or directories searched recursively. Paths from fragmentation
+	// analysis can be passed as-is.
+	Paths []string `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
+	// Only files with at least this degree of fragmentation / extent count.
+	// With neither set, any file with more extents than ideal is picked.
+	MinDof     float64 `protobuf:"fixed64,2,opt,name=min_dof,json=minDof,proto3" json:"min_dof,omitempty"`
+	MinExtents int32   `protobuf:"varint,3,opt,name=min_extents,json=minExtents,proto3" json:"min_extents,omitempty"`
+	// Extents at least this long are left alone (bytes, 0 = kernel default)
+	ExtentThreshold uint32 `protobuf:"varint,4,opt,name=extent_threshold,json=extentThreshold,proto3" json:"extent_threshold,omitempty"`
+	// Recompress with "zlib", "lzo" or "zstd" (empty = mount setting)
+	Compress string `protobuf:"bytes,5,opt,name=compress,proto3" json:"compress,omitempty"`
+	// Throughput limit (bytes/sec), 0 = unlimited
+	LimitBytesPerSec int64 `protobuf:"varint,6,opt,name=limit_bytes_per_sec,json=limitBytesPerSec,proto3" json:"limit_bytes_per_sec,omitempty"`
+	// Defragment files with shared extents, unsharing them from snapshots
+	AllowShared bool `protobuf:"varint,7,opt,name=allow_shared,json=allowShared,proto3" json:"allow_shared,omitempty"`
+	// Only select files, don't defragment them
+	DryRun        bool `protobuf:"varint,8,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DefragOptions) Reset() {
+	*x = DefragOptions{}
+	mi := &file_api_v1_defrag_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DefragOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DefragOptions) ProtoMessage() {}
+
+func (x *DefragOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_defrag_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DefragOptions.ProtoReflect.Descriptor instead.
+func (*DefragOptions) Descriptor() ([]byte, []int) {
+	return file_api_v1_defrag_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DefragOptions) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+func (x *DefragOptions) GetMinDof() float64 {
+	if x != nil {
+		return x.MinDof
+	}
+	return 0
+}
+
+func (x *DefragOptions) GetMinExtents() int32 {
+	if x != nil {
+		return x.MinExtents
+	}
+	return 0
+}
+
+func (x *DefragOptions) GetExtentThreshold() uint32 {
+	if x != nil {
+		return x.ExtentThreshold
+	}
+	return 0
+}
+
+func (x *DefragOptions) GetCompress() string {
+	if x != nil {
+		return x.Compress
+	}
+	return ""
+}
+
+func (x *DefragOptions) GetLimitBytesPerSec() int64 {
+	if x != nil {
+		return x.LimitBytesPerSec
+	}
+	return 0
+}
+
+func (x *DefragOptions) GetAllowShared() bool {
+	if x != nil {
+		return x.AllowShared
+	}
+	return false
+}
+
+func (x *DefragOptions) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type StartDefragRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Options       *DefragOptions         `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartDefragRequest) Reset() {
+	*x = StartDefragRequest{}
+	mi := &file_api_v1_defrag_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartDefragRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartDefragRequest) ProtoMessage() {}
+
+func (x *StartDefragRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_defrag_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartDefragRequest.ProtoReflect.Descriptor instead.
+func (*StartDefragRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_defrag_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StartDefragRequest) GetOptions() *DefragOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type StartDefragResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartDefragResponse) Reset() {
+	*x = StartDefragResponse{}
+	mi := &file_api_v1_defrag_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartDefragResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartDefragResponse) ProtoMessage() {}
+
+func (x *StartDefragResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_defrag_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartDefragResponse.ProtoReflect.Descriptor instead.
+func (*StartDefragResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_defrag_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StartDefragResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type CancelDefragRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelDefragRequest) Reset() {
+	*x = CancelDefragRequest{}
+	mi := &file_api_v1_defrag_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelDefragRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelDefragRequest) ProtoMessage() {}
+
+func (x *CancelDefragRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_defrag_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelDefragRequest.ProtoReflect.Descriptor instead.
+func (*CancelDefragRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_defrag_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CancelDefragRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type CancelDefragResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelDefragResponse) Reset() {
+	*x = CancelDefragResponse{}
+	mi := &file_api_v1_defrag_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelDefragResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelDefragResponse) ProtoMessage() {}
+
+func (x *CancelDefragResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_defrag_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelDefragResponse.ProtoReflect.Descriptor instead.
+func (*CancelDefragResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_defrag_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CancelDefragResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type DefragProgress struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	JobId              string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Options            *DefragOptions         `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	Status             string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // scanning, running, finished, cancelled, failed
+	Error              string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	StartedAt          int64                  `protobuf:"varint,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt         int64                  `protobuf:"varint,6,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	FilesScanned       int32                  `protobuf:"varint,7,opt,name=files_scanned,json=filesScanned,proto3" json:"files_scanned,omitempty"`
+	FilesSelected      int32                  `protobuf:"varint,8,opt,name=files_selected,json=filesSelected,proto3" json:"files_selected,omitempty"`
+	FilesDone          int32                  `protobuf:"varint,9,opt,name=files_done,json=filesDone,proto3" json:"files_done,omitempty"`
+	FilesFailed        int32                  `protobuf:"varint,10,opt,name=files_failed,json=filesFailed,proto3" json:"files_failed,omitempty"`
+	FilesSkippedShared int32                  `protobuf:"varint,11,opt,name=files_skipped_shared,json=filesSkippedShared,proto3" json:"files_skipped_shared,omitempty"`
+	BytesTotal         int64                  `protobuf:"varint,12,opt,name=bytes_total,json=bytesTotal,proto3" json:"bytes_total,omitempty"`
+	BytesDone          int64                  `protobuf:"varint,13,opt,name=bytes_done,json=bytesDone,proto3" json:"bytes_done,omitempty"`
+	ExtentsBefore      int32                  `protobuf:"varint,14,opt,name=extents_before,json=extentsBefore,proto3" json:"extents_before,omitempty"`
+	ExtentsAfter       int32                  `protobuf:"varint,15,opt,name=extents_after,json=extentsAfter,proto3" json:"extents_after,omitempty"`
+	CurrentFile        string                 `protobuf:"bytes,16,opt,name=current_file,json=currentFile,proto3" json:"current_file,omitempty"`
+	ProgressPercent    float64                `protobuf:"fixed64,17,opt,name=progress_percent,json=progressPercent,proto3" json:"progress_percent,omitempty"`
+	IsRunning          bool                   `protobuf:"varint,18,opt,name=is_running,json=isRunning,proto3" json:"is_running,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *DefragProgress) Reset() {
+	*x = DefragProgress{}
+	mi := &file_api_v1_defrag_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DefragProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DefragProgress) ProtoMessage() {}
+
+func (x *DefragProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_defrag_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DefragProgress.ProtoReflect.Descriptor instead.
+func (*DefragProgress) Descriptor() ([]byte, []int) {
+	return file_api_v1_defrag_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DefragProgress) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *DefragProgress) GetOptions() *DefragOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *DefragProgress) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DefragProgress) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DefragProgress) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *DefragProgress) GetFinishedAt() int64 {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return 0
+}
+
+func (x *DefragProgress) GetFilesScanned() int32 {
+	if x != nil {
+		return x.FilesScanned
+	}
+	return 0
+}
+
+func (x *DefragProgress) GetFilesSelected() int32 {
+	if x != nil {
+		return x.FilesSelected
+	}
+	return 0
+}
+
+func (x *DefragProgress) GetFilesDone() int32 {
+	if x != nil {
+		return x.FilesDone
+	}
+	return 0
+}
+
+func (x *DefragProgress) GetFilesFailed() int32 {
+	if x != nil {
+		return x.FilesFailed
+	}
+	return 0
+}
+
+func (x *DefragProgress) GetFilesSkippedShared() int32 {
+	if x != nil {
+		return x.FilesSkippedShared
+	}
+	return 0
+}
+
+func (x *DefragProgress) GetBytesTotal() int64 {
+	if x != nil {
+		return x.BytesTotal
+	}
+	return 0
+}
+
+func (x *DefragProgress) GetBytesDone() int64 {
+	if x != nil {
+		return x.BytesDone
+	}
+	return 0
+}
+
+func (x *DefragProgress) GetExtentsBefore() int32 {
+	if x != nil {
+		return x.ExtentsBefore
+	}
+	return 0
+}
+
+func (x *DefragProgress) GetExtentsAfter() int32 {
+	if x != nil {
+		return x.ExtentsAfter
+	}
+	return 0
+}
+
+func (x *DefragProgress) GetCurrentFile() string {
+	if x != nil {
+		return x.CurrentFile
+	}
+	return ""
+}
+
+func (x *DefragProgress) GetProgressPercent() float64 {
+	if x != nil {
+		return x.ProgressPercent
+	}
+	return 0
+}
+
+func (x *DefragProgress) GetIsRunning() bool {
+	if x != nil {
+		return x.IsRunning
+	}
+	return false
+}
+
+type GetDefragStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDefragStatusRequest) Reset() {
+	*x = GetDefragStatusRequest{}
+	mi := &file_api_v1_defrag_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDefragStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDefragStatusRequest) ProtoMessage() {}
+
+func (x *GetDefragStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_defrag_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDefragStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetDefragStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_defrag_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetDefragStatusRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type GetDefragStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Progress      *DefragProgress        `protobuf:"bytes,1,opt,name=progress,proto3" json:"progress,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDefragStatusResponse) Reset() {
+	*x = GetDefragStatusResponse{}
+	mi := &file_api_v1_defrag_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDefragStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDefragStatusResponse) ProtoMessage() {}
+
+func (x *GetDefragStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_defrag_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDefragStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetDefragStatusResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_defrag_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetDefragStatusResponse) GetProgress() *DefragProgress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+type ListDefragJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDefragJobsRequest) Reset() {
+	*x = ListDefragJobsRequest{}
+	mi := &file_api_v1_defrag_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDefragJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDefragJobsRequest) ProtoMessage() {}
+
+func (x *ListDefragJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_defrag_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDefragJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListDefragJobsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_defrag_proto_rawDescGZIP(), []int{8}
+}
+
+type ListDefragJobsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*DefragProgress      `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"` // Running job first, then finished jobs newest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDefragJobsResponse) Reset() {
+	*x = ListDefragJobsResponse{}
+	mi := &file_api_v1_defrag_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDefragJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDefragJobsResponse) ProtoMessage() {}
+
+func (x *ListDefragJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_defrag_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDefragJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListDefragJobsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_defrag_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListDefragJobsResponse) GetJobs() []*DefragProgress {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+type StreamDefragProgressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamDefragProgressRequest) Reset() {
+	*x = StreamDefragProgressRequest{}
+	mi := &file_api_v1_defrag_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamDefragProgressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamDefragProgressRequest) ProtoMessage() {}
+
+func (x *StreamDefragProgressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_defrag_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamDefragProgressRequest.ProtoReflect.Descriptor instead.
+func (*StreamDefragProgressRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_defrag_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StreamDefragProgressRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+var File_api_v1_defrag_proto protoreflect.FileDescriptor
+
+const file_api_v1_defrag_proto_rawDesc = "" +
+	"\n" +
+	"\x13api/v1/defrag.proto\x12\x06api.v1\"\x91\x02\n" +
+	"\rDefragOptions\x12\x14\n" +
+	"\x05paths\x18\x01 \x03(\tR\x05paths\x12\x17\n" +
+	"\amin_dof\x18\x02 \x01(\x01R\x06minDof\x12\x1f\n" +
+	"\vmin_extents\x18\x03 \x01(\x05R\n" +
+	"minExtents\x12)\n" +
+	"\x10extent_threshold\x18\x04 \x01(\rR\x0fextentThreshold\x12\x1a\n" +
+	"\bcompress\x18\x05 \x01(\tR\bcompress\x12-\n" +
+	"\x13limit_bytes_per_sec\x18\x06 \x01(\x03R\x10limitBytesPerSec\x12!\n" +
+	"\fallow_shared\x18\a \x01(\bR\vallowShared\x12\x17\n" +
+	"\adry_run\x18\b \x01(\bR\x06dryRun\"E\n" +
+	"\x12StartDefragRequest\x12/\n" +
+	"\aoptions\x18\x01 \x01(\v2\x15.api.v1.DefragOptionsR\aoptions\",\n" +
+	"\x13StartDefragResponse\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\",\n" +
+	"\x13CancelDefragRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"0\n" +
+	"\x14CancelDefragResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xff\x04\n" +
+	"\x0eDefragProgress\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12/\n" +
+	"\aoptions\x18\x02 \x01(\v2\x15.api.v1.DefragOptionsR\aoptions\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x05 \x01(\x03R\tstartedAt\x12\x1f\n" +
+	"\vfinished_at\x18\x06 \x01(\x03R\n" +
+	"finishedAt\x12#\n" +
+	"\rfiles_scanned\x18\a \x01(\x05R\ffilesScanned\x12%\n" +
+	"\x0efiles_selected\x18\b \x01(\x05R\rfilesSelected\x12\x1d\n" +
+	"\n" +
+	"files_done\x18\t \x01(\x05R\tfilesDone\x12!\n" +
+	"\ffiles_failed\x18\n" +
+	" \x01(\x05R\vfilesFailed\x120\n" +
+	"\x14files_skipped_shared\x18\v \x01(\x05R\x12filesSkippedShared\x12\x1f\n" +
+	"\vbytes_total\x18\f \x01(\x03R\n" +
+	"bytesTotal\x12\x1d\n" +
+	"\n" +
+	"bytes_done\x18\r \x01(\x03R\tbytesDone\x12%\n" +
+	"\x0eextents_before\x18\x0e \x01(\x05R\rextentsBefore\x12#\n" +
+	"\rextents_after\x18\x0f \x01(\x05R\fextentsAfter\x12!\n" +
+	"\fcurrent_file\x18\x10 \x01(\tR\vcurrentFile\x12)\n" +
+	"\x10progress_percent\x18\x11 \x01(\x01R\x0fprogressPercent\x12\x1d\n" +
+	"\n" +
+	"is_running\x18\x12 \x01(\bR\tisRunning\"/\n" +
+	"\x16GetDefragStatusRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"M\n" +
+	"\x17GetDefragStatusResponse\x122\n" +
+	"\bprogress\x18\x01 \x01(\v2\x16.api.v1.DefragProgressR\bprogress\"\x17\n" +
+	"\x15ListDefragJobsRequest\"D\n" +
+	"\x16ListDefragJobsResponse\x12*\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x16.api.v1.DefragProgressR\x04jobs\"4\n" +
+	"\x1bStreamDefragProgressRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId2\xa8\x03\n" +
+	"\rDefragService\x12H\n" +
+	"\vStartDefrag\x12\x1a.api.v1.StartDefragRequest\x1a\x1b.api.v1.StartDefragResponse\"\x00\x12K\n" +
+	"\fCancelDefrag\x12\x1b.api.v1.CancelDefragRequest\x1a\x1c.api.v1.CancelDefragResponse\"\x00\x12T\n" +
+	"\x0fGetDefragStatus\x12\x1e.api.v1.GetDefragStatusRequest\x1a\x1f.api.v1.GetDefragStatusResponse\"\x00\x12Q\n" +
+	"\x0eListDefragJobs\x12\x1d.api.v1.ListDefragJobsRequest\x1a\x1e.api.v1.ListDefragJobsResponse\"\x00\x12W\n" +
+	"\x14StreamDefragProgress\x12#.api.v1.StreamDefragProgressRequest\x1a\x16.api.v1.DefragProgress\"\x000\x01B~\n" +
+	"\n" +
+	"com.api.v1B\vDefragProtoP\x01Z*github.com/elee1766/gobtr/gen/api/v1;apiv1\xa2\x02\x03AXX\xaa\x02\x06Api.V1\xca\x02\x06Api\\V1\xe2\x02\x12Api\\V1\\GPBMetadata\xea\x02\aApi::V1b\x06proto3"
+
+var (
+	file_api_v1_defrag_proto_rawDescOnce sync.Once
+	file_api_v1_defrag_proto_rawDescData []byte
+)
+
+func file_api_v1_defrag_proto_rawDescGZIP() []byte {
+	file_api_v1_defrag_proto_rawDescOnce.Do(func() {
+		file_api_v1_defrag_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_v1_defrag_proto_rawDesc), len(file_api_v1_defrag_proto_rawDesc)))
+	})
+	return file_api_v1_defrag_proto_rawDescData
+}
+
+var file_api_v1_defrag_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_api_v1_defrag_proto_goTypes = []any{
+	(*DefragOptions)(nil),               // 0: api.v1.DefragOptions
+	(*StartDefragRequest)(nil),          // 1: api.v1.StartDefragRequest
+	(*StartDefragResponse)(nil),         // 2: api.v1.StartDefragResponse
+	(*CancelDefragRequest)(nil),         // 3: api.v1.CancelDefragRequest
+	(*CancelDefragResponse)(nil),        // 4: api.v1.CancelDefragResponse
+	(*DefragProgress)(nil),              // 5: api.v1.DefragProgress
+	(*GetDefragStatusRequest)(nil),      // 6: api.v1.GetDefragStatusRequest
+	(*GetDefragStatusResponse)(nil),     // 7: api.v1.GetDefragStatusResponse
+	(*ListDefragJobsRequest)(nil),       // 8: api.v1.ListDefragJobsRequest
+	(*ListDefragJobsResponse)(nil),      // 9: api.v1.ListDefragJobsResponse
+	(*StreamDefragProgressRequest)(nil), // 10: api.v1.StreamDefragProgressRequest
+}
+var file_api_v1_defrag_proto_depIdxs = []int32{
+	0,  // 0: api.v1.StartDefragRequest.options:type_name -> api.v1.DefragOptions
+	0,  // 1: api.v1.DefragProgress.options:type_name -> api.v1.DefragOptions
+	5,  // 2: api.v1.GetDefragStatusResponse.progress:type_name -> api.v1.DefragProgress
+	5,  // 3: api.v1.ListDefragJobsResponse.jobs:type_name -> api.v1.DefragProgress
+	1,  // 4: api.v1.DefragService.StartDefrag:input_type -> api.v1.StartDefragRequest
+	3,  // 5: api.v1.DefragService.CancelDefrag:input_type -> api.v1.CancelDefragRequest
+	6,  // 6: api.v1.DefragService.GetDefragStatus:input_type -> api.v1.GetDefragStatusRequest
+	8,  // 7: api.v1.DefragService.ListDefragJobs:input_type -> api.v1.ListDefragJobsRequest
+	10, // 8: api.v1.DefragService.StreamDefragProgress:input_type -> api.v1.StreamDefragProgressRequest
+	2,  // 9: api.v1.DefragService.StartDefrag:output_type -> api.v1.StartDefragResponse
+	4,  // 10: api.v1.DefragService.CancelDefrag:output_type -> api.v1.CancelDefragResponse
+	7,  // 11: api.v1.DefragService.GetDefragStatus:output_type -> api.v1.GetDefragStatusResponse
+	9,  // 12: api.v1.DefragService.ListDefragJobs:output_type -> api.v1.ListDefragJobsResponse
+	5,  // 13: api.v1.DefragService.StreamDefragProgress:output_type -> api.v1.DefragProgress
+	9,  // [9:14] is the sub-list for method output_type
+	4,  // [4:9] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_api_v1_defrag_proto_init() }
+func file_api_v1_defrag_proto_init() {
+	if File_api_v1_defrag_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_defrag_proto_rawDesc), len(file_api_v1_defrag_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_v1_defrag_proto_goTypes,
+		DependencyIndexes: file_api_v1_defrag_proto_depIdxs,
+		MessageInfos:      file_api_v1_defrag_proto_msgTypes,
+	}.Build()
+	File_api_v1_defrag_proto = out.File
+	file_api_v1_defrag_proto_goTypes = nil
+	file_api_v1_defrag_proto_depIdxs = nil
+}
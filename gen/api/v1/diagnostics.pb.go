@@ -0,0 +1,600 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: api/v1/diagnostics.proto
+
+package apiv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A problem detected by a diagnostics rule
+type DiagnosticFinding struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          string                 `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`         // Rule ID, e.g. "mixed_profiles"
+	Severity      string                 `protobuf:"bytes,2,opt,name=severity,proto3" json:"severity,omitempty"` // "info", "warning", "critical"
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Detail        string                 `protobuf:"bytes,4,opt,name=detail,proto3" json:"detail,omitempty"`
+	Action        string                 `protobuf:"bytes,5,opt,name=action,proto3" json:"action,omitempty"` // Recommended action
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiagnosticFinding) Reset() {
+	*x = DiagnosticFinding{}
+	mi := &file_api_v1_diagnostics_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiagnosticFinding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiagnosticFinding) ProtoMessage() {}
+
+func (x *DiagnosticFinding) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_diagnostics_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiagnosticFinding.ProtoReflect.Descriptor instead.
+func (*DiagnosticFinding) Descriptor() ([]byte, []int) {
+	return file_api_v1_diagnostics_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DiagnosticFinding) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *DiagnosticFinding) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *DiagnosticFinding) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *DiagnosticFinding) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *DiagnosticFinding) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+// Tunable rule thresholds (0 = default)
+type DiagnosticThresholds struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ScrubMaxAgeDays  int32                  `protobuf:"varint,1,opt,name=scrub_max_age_days,json=scrubMaxAgeDays,proto3" json:"scrub_max_age_days,omitempty"` // Default 30
+	SlackPercent     float64                `protobuf:"fixed64,2,opt,name=slack_percent,json=slackPercent,proto3" json:"slack_percent,omitempty"`             // Default 25
+	MinSlackBytes    int64                  `protobuf:"varint,3,opt,name=min_slack_bytes,json=minSlackBytes,proto3" json:"min_slack_bytes,omitempty"`         // Default 10 GiB
+	ImbalancePercent float64                `protobuf:"fixed64,4,opt,name=imbalance_percent,json=imbalancePercent,proto3" json:"imbalance_percent,omitempty"` // Default 25
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DiagnosticThresholds) Reset() {
+	*x = DiagnosticThresholds{}
+	mi := &file_api_v1_diagnostics_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiagnosticThresholds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiagnosticThresholds) ProtoMessage() {}
+
+func (x *DiagnosticThresholds) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_diagnostics_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiagnosticThresholds.ProtoReflect.Descriptor instead.
+func (*DiagnosticThresholds) Descriptor() ([]byte, []int) {
+	return file_api_v1_diagnostics_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DiagnosticThresholds) GetScrubMaxAgeDays() int32 {
+	if x != nil {
+		return x.ScrubMaxAgeDays
+	}
+	return 0
+}
+
+func (x *DiagnosticThresholds) GetSlackPercent() float64 {
+	if x != nil {
+		return x.SlackPercent
+	}
+	return 0
+}
+
+func (x *DiagnosticThresholds) GetMinSlackBytes() int64 {
+	if x != nil {
+		return x.MinSlackBytes
+	}
+	return 0
+}
+
+func (x *DiagnosticThresholds) GetImbalancePercent() float64 {
+	if x != nil {
+		return x.ImbalancePercent
+	}
+	return 0
+}
+
+type DiagnosticsReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Uuid          string                 `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	CheckedAt     int64                  `protobuf:"varint,3,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	Score         int32                  `protobuf:"varint,4,opt,name=score,proto3" json:"score,omitempty"` // 0-100, higher is healthier
+	Findings      []*DiagnosticFinding   `protobuf:"bytes,5,rep,name=findings,proto3" json:"findings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiagnosticsReport) Reset() {
+	*x = DiagnosticsReport{}
+	mi := &file_api_v1_diagnostics_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiagnosticsReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiagnosticsReport) ProtoMessage() {}
+
+func (x *DiagnosticsReport) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_diagnostics_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiagnosticsReport.ProtoReflect.Descriptor instead.
+func (*DiagnosticsReport) Descriptor() ([]byte, []int) {
+	return file_api_v1_diagnostics_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DiagnosticsReport) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DiagnosticsReport) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *DiagnosticsReport) GetCheckedAt() int64 {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return 0
+}
+
+func (x *DiagnosticsReport) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *DiagnosticsReport) GetFindings() []*DiagnosticFinding {
+	if x != nil {
+		return x.Findings
+	}
+	return nil
+}
+
+type RunDiagnosticsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DevicePath    string                 `protobuf:"bytes,1,opt,name=device_path,json=devicePath,proto3" json:"device_path,omitempty"`
+	Thresholds    *DiagnosticThresholds  `protobuf:"bytes,2,opt,name=thresholds,proto3" json:"thresholds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunDiagnosticsRequest) Reset() {
+	*x = RunDiagnosticsRequest{}
+	mi := &file_api_v1_diagnostics_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunDiagnosticsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunDiagnosticsRequest) ProtoMessage() {}
+
+func (x *RunDiagnosticsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_diagnostics_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunDiagnosticsRequest.ProtoReflect.Descriptor instead.
+func (*RunDiagnosticsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_diagnostics_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RunDiagnosticsRequest) GetDevicePath() string {
+	if x != nil {
+		return x.DevicePath
+	}
+	return ""
+}
+
+func (x *RunDiagnosticsRequest) GetThresholds() *DiagnosticThresholds {
+	if x != nil {
+		return x.Thresholds
+	}
+	return nil
+}
+
+type RunDiagnosticsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Report        *DiagnosticsReport     `protobuf:"bytes,1,opt,name=report,proto3" json:"report,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunDiagnosticsResponse) Reset() {
+	*x = RunDiagnosticsResponse{}
+	mi := &file_api_v1_diagnostics_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunDiagnosticsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunDiagnosticsResponse) ProtoMessage() {}
+
+func (x *RunDiagnosticsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_diagnostics_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunDiagnosticsResponse.ProtoReflect.Descriptor instead.
+func (*RunDiagnosticsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_diagnostics_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RunDiagnosticsResponse) GetReport() *DiagnosticsReport {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+type RunAllDiagnosticsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Thresholds    *DiagnosticThresholds  `protobuf:"bytes,1,opt,name=thresholds,proto3" json:"thresholds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunAllDiagnosticsRequest) Reset() {
+	*x = RunAllDiagnosticsRequest{}
+	mi := &file_api_v1_diagnostics_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunAllDiagnosticsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunAllDiagnosticsRequest) ProtoMessage() {}
+
+func (x *RunAllDiagnosticsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_diagnostics_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunAllDiagnosticsRequest.ProtoReflect.Descriptor instead.
+func (*RunAllDiagnosticsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_diagnostics_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RunAllDiagnosticsRequest) GetThresholds() *DiagnosticThresholds {
+	if x != nil {
+		return x.Thresholds
+	}
+	return nil
+}
+
+type FilesystemDiagnostics struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Report        *DiagnosticsReport     `protobuf:"bytes,2,opt,name=report,proto3" json:"report,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"` // If there was an error fetching
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FilesystemDiagnostics) Reset() {
+	*x = FilesystemDiagnostics{}
+	mi := &file_api_v1_diagnostics_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FilesystemDiagnostics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilesystemDiagnostics) ProtoMessage() {}
+
+func (x *FilesystemDiagnostics) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_diagnostics_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilesystemDiagnostics.ProtoReflect.Descriptor instead.
+func (*FilesystemDiagnostics) Descriptor() ([]byte, []int) {
+	return file_api_v1_diagnostics_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FilesystemDiagnostics) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FilesystemDiagnostics) GetReport() *DiagnosticsReport {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+func (x *FilesystemDiagnostics) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type RunAllDiagnosticsResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Filesystems   []*FilesystemDiagnostics `protobuf:"bytes,1,rep,name=filesystems,proto3" json:"filesystems,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunAllDiagnosticsResponse) Reset() {
+	*x = RunAllDiagnosticsResponse{}
+	mi := &file_api_v1_diagnostics_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunAllDiagnosticsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunAllDiagnosticsResponse) ProtoMessage() {}
+
+func (x *RunAllDiagnosticsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_diagnostics_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunAllDiagnosticsResponse.ProtoReflect.Descriptor instead.
+func (*RunAllDiagnosticsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_diagnostics_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RunAllDiagnosticsResponse) GetFilesystems() []*FilesystemDiagnostics {
+	if x != nil {
+		return x.Filesystems
+	}
+	return nil
+}
+
+var File_api_v1_diagnostics_proto protoreflect.FileDescriptor
+
+const file_api_v1_diagnostics_proto_rawDesc = "" +
+	"\n" +
+	"\x18api/v1/diagnostics.proto\x12\x06api.v1\"\x89\x01\n" +
+	"\x11DiagnosticFinding\x12\x12\n" +
+	"\x04rule\x18\x01 \x01(\tR\x04rule\x12\x1a\n" +
+	"\bseverity\x18\x02 \x01(\tR\bseverity\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x16\n" +
+	"\x06detail\x18\x04 \x01(\tR\x06detail\x12\x16\n" +
+	"\x06action\x18\x05 \x01(\tR\x06action\"\xbd\x01\n" +
+	"\x14DiagnosticThresholds\x12+\n" +
+	"\x12scrub_max_age_days\x18\x01 \x01(\x05R\x0fscrubMaxAgeDays\x12#\n" +
+	"\rslack_percent\x18\x02 \x01(\x01R\fslackPercent\x12&\n" +
+	"\x0fmin_slack_bytes\x18\x03 \x01(\x03R\rminSlackBytes\x12+\n" +
+	"\x11imbalance_percent\x18\x04 \x01(\x01R\x10imbalancePercent\"\xa7\x01\n" +
+	"\x11DiagnosticsReport\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\tR\x04uuid\x12\x1d\n" +
+	"\n" +
+	"checked_at\x18\x03 \x01(\x03R\tcheckedAt\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x05R\x05score\x125\n" +
+	"\bfindings\x18\x05 \x03(\v2\x19.api.v1.DiagnosticFindingR\bfindings\"v\n" +
+	"\x15RunDiagnosticsRequest\x12\x1f\n" +
+	"\vdevice_path\x18\x01 \x01(\tR\n" +
+	"devicePath\x12<\n" +
+	"\n" +
+	"thresholds\x18\x02 \x01(\v2\x1c.api.v1.DiagnosticThresholdsR\n" +
+	"thresholds\"K\n" +
+	"\x16RunDiagnosticsResponse\x121\n" +
+	"\x06report\x18\x01 \x01(\v2\x19.api.v1.DiagnosticsReportR\x06report\"X\n" +
+	"\x18RunAllDiagnosticsRequest\x12<\n" +
+	"\n" +
+	"thresholds\x18\x01 \x01(\v2\x1c.api.v1.DiagnosticThresholdsR\n" +
+	"thresholds\"\x83\x01\n" +
+	"\x15FilesystemDiagnostics\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x121\n" +
+	"\x06report\x18\x02 \x01(\v2\x19.api.v1.DiagnosticsReportR\x06report\x12#\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\"\\\n" +
+	"\x19RunAllDiagnosticsResponse\x12?\n" +
+	"\vfilesystems\x18\x01 \x03(\v2\x1d.api.v1.FilesystemDiagnosticsR\vfilesystems2\xc3\x01\n" +
+	"\x12DiagnosticsService\x12Q\n" +
+	"\x0eRunDiagnostics\x12\x1d.api.v1.RunDiagnosticsRequest\x1a\x1e.api.v1.RunDiagnosticsResponse\"\x00\x12Z\n" +
+	"\x11RunAllDiagnostics\x12 .api.v1.RunAllDiagnosticsRequest\x1a!.api.v1.RunAllDiagnosticsResponse\"\x00B\x83\x01\n" +
+	"\n" +
+	"com.api.v1B\x10DiagnosticsProtoP\x01Z*github.com/elee1766/gobtr/gen/api/v1;apiv1\xa2\x02\x03AXX\xaa\x02\x06Api.V1\xca\x02\x06Api\\V1\xe2\x02\x12Api\\V1\\GPBMetadata\xea\x02\aApi::V1b\x06proto3"
+
+var (
+	file_api_v1_diagnostics_proto_rawDescOnce sync.Once
+	file_api_v1_diagnostics_proto_rawDescData []byte
+)
+
+func file_api_v1_diagnostics_proto_rawDescGZIP() []byte {
+	file_api_v1_diagnostics_proto_rawDescOnce.Do(func() {
+		file_api_v1_diagnostics_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_v1_diagnostics_proto_rawDesc), len(file_api_v1_diagnostics_proto_rawDesc)))
+	})
+	return file_api_v1_diagnostics_proto_rawDescData
+}
+
+var file_api_v1_diagnostics_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_api_v1_diagnostics_proto_goTypes = []any{
+	(*DiagnosticFinding)(nil),         // 0: api.v1.DiagnosticFinding
+	(*DiagnosticThresholds)(nil),      // 1: api.v1.DiagnosticThresholds
+	(*DiagnosticsReport)(nil),         // 2: api.v1.DiagnosticsReport
+	(*RunDiagnosticsRequest)(nil),     // 3: api.v1.RunDiagnosticsRequest
+	(*RunDiagnosticsResponse)(nil),    // 4: api.v1.RunDiagnosticsResponse
+	(*RunAllDiagnosticsRequest)(nil),  // 5: api.v1.RunAllDiagnosticsRequest
+	(*FilesystemDiagnostics)(nil),     // 6: api.v1.FilesystemDiagnostics
+	(*RunAllDiagnosticsResponse)(nil), // 7: api.v1.RunAllDiagnosticsResponse
+}
+var file_api_v1_diagnostics_proto_depIdxs = []int32{
+	0, // 0: api.v1.DiagnosticsReport.findings:type_name -> api.v1.DiagnosticFinding
+	1, // 1: api.v1.RunDiagnosticsRequest.thresholds:type_name -> api.v1.DiagnosticThresholds
+	2, // 2: api.v1.RunDiagnosticsResponse.report:type_name -> api.v1.DiagnosticsReport
+	1, // 3: api.v1.RunAllDiagnosticsRequest.thresholds:type_name -> api.v1.DiagnosticThresholds
+	2, // 4: api.v1.FilesystemDiagnostics.report:type_name -> api.v1.DiagnosticsReport
+	6, // 5: api.v1.RunAllDiagnosticsResponse.filesystems:type_name -> api.v1.FilesystemDiagnostics
+	3, // 6: api.v1.DiagnosticsService.RunDiagnostics:input_type -> api.v1.RunDiagnosticsRequest
+	5, // 7: api.v1.DiagnosticsService.RunAllDiagnostics:input_type -> api.v1.RunAllDiagnosticsRequest
+	4, // 8: api.v1.DiagnosticsService.RunDiagnostics:output_type -> api.v1.RunDiagnosticsResponse
+	7, // 9: api.v1.DiagnosticsService.RunAllDiagnostics:output_type -> api.v1.RunAllDiagnosticsResponse
+	8, // [8:10] is the sub-list for method output_type
+	6, // [6:8] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_api_v1_diagnostics_proto_init() }
+func file_api_v1_diagnostics_proto_init() {
+	if File_api_v1_diagnostics_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_diagnostics_proto_rawDesc), len(file_api_v1_diagnostics_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_v1_diagnostics_proto_goTypes,
+		DependencyIndexes: file_api_v1_diagnostics_proto_depIdxs,
+		MessageInfos:      file_api_v1_diagnostics_proto_msgTypes,
+	}.Build()
+	File_api_v1_diagnostics_proto = out.File
+	file_api_v1_diagnostics_proto_goTypes = nil
+	file_api_v1_diagnostics_proto_depIdxs = nil
+}
@@ -0,0 +1,954 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: api/v1/forecast.proto
+
+package apiv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// One recorded usage sample
+type UsageSample struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp         int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	DeviceSize        int64                  `protobuf:"varint,2,opt,name=device_size,json=deviceSize,proto3" json:"device_size,omitempty"`
+	DeviceAllocated   int64                  `protobuf:"varint,3,opt,name=device_allocated,json=deviceAllocated,proto3" json:"device_allocated,omitempty"`
+	DeviceUnallocated int64                  `protobuf:"varint,4,opt,name=device_unallocated,json=deviceUnallocated,proto3" json:"device_unallocated,omitempty"`
+	DataTotal         int64                  `protobuf:"varint,5,opt,name=data_total,json=dataTotal,proto3" json:"data_total,omitempty"`
+	DataUsed          int64                  `protobuf:"varint,6,opt,name=data_used,json=dataUsed,proto3" json:"data_used,omitempty"`
+	DataProfile       string                 `protobuf:"bytes,7,opt,name=data_profile,json=dataProfile,proto3" json:"data_profile,omitempty"`
+	MetadataTotal     int64                  `protobuf:"varint,8,opt,name=metadata_total,json=metadataTotal,proto3" json:"metadata_total,omitempty"`
+	MetadataUsed      int64                  `protobuf:"varint,9,opt,name=metadata_used,json=metadataUsed,proto3" json:"metadata_used,omitempty"`
+	MetadataProfile   string                 `protobuf:"bytes,10,opt,name=metadata_profile,json=metadataProfile,proto3" json:"metadata_profile,omitempty"`
+	SystemTotal       int64                  `protobuf:"varint,11,opt,name=system_total,json=systemTotal,proto3" json:"system_total,omitempty"`
+	SystemUsed        int64                  `protobuf:"varint,12,opt,name=system_used,json=systemUsed,proto3" json:"system_used,omitempty"`
+	GlobalReserve     int64                  `protobuf:"varint,13,opt,name=global_reserve,json=globalReserve,proto3" json:"global_reserve,omitempty"`
+	GlobalReserveUsed int64                  `protobuf:"varint,14,opt,name=global_reserve_used,json=globalReserveUsed,proto3" json:"global_reserve_used,omitempty"`
+	FreeEstimated     int64                  `protobuf:"varint,15,opt,name=free_estimated,json=freeEstimated,proto3" json:"free_estimated,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *UsageSample) Reset() {
+	*x = UsageSample{}
+	mi := &file_api_v1_forecast_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageSample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageSample) ProtoMessage() {}
+
+func (x *UsageSample) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_forecast_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageSample.ProtoReflect.Descriptor instead.
+func (*UsageSample) Descriptor() ([]byte, []int) {
+	return file_api_v1_forecast_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UsageSample) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *UsageSample) GetDeviceSize() int64 {
+	if x != nil {
+		return x.DeviceSize
+	}
+	return 0
+}
+
+func (x *UsageSample) GetDeviceAllocated() int64 {
+	if x != nil {
+		return x.DeviceAllocated
+	}
+	return 0
+}
+
+func (x *UsageSample) GetDeviceUnallocated() int64 {
+	if x != nil {
+		return x.DeviceUnallocated
+	}
+	return 0
+}
+
+func (x *UsageSample) GetDataTotal() int64 {
+	if x != nil {
+		return x.DataTotal
+	}
+	return 0
+}
+
+func (x *UsageSample) GetDataUsed() int64 {
+	if x != nil {
+		return x.DataUsed
+	}
+	return 0
+}
+
+func (x *UsageSample) GetDataProfile() string {
+	if x != nil {
+		return x.DataProfile
+	}
+	return ""
+}
+
+func (x *UsageSample) GetMetadataTotal() int64 {
+	if x != nil {
+		return x.MetadataTotal
+	}
+	return 0
+}
+
+func (x *UsageSample) GetMetadataUsed() int64 {
+	if x != nil {
+		return x.MetadataUsed
+	}
+	return 0
+}
+
+func (x *UsageSample) GetMetadataProfile() string {
+	if x != nil {
+		return x.MetadataProfile
+	}
+	return ""
+}
+
+func (x *UsageSample) GetSystemTotal() int64 {
+	if x != nil {
+		return x.SystemTotal
+	}
+	return 0
+}
+
+func (x *UsageSample) GetSystemUsed() int64 {
+	if x != nil {
+		return x.SystemUsed
+	}
+	return 0
+}
+
+func (x *UsageSample) GetGlobalReserve() int64 {
+	if x != nil {
+		return x.GlobalReserve
+	}
+	return 0
+}
+
+func (x *UsageSample) GetGlobalReserveUsed() int64 {
+	if x != nil {
+		return x.GlobalReserveUsed
+	}
+	return 0
+}
+
+func (x *UsageSample) GetFreeEstimated() int64 {
+	if x != nil {
+		return x.FreeEstimated
+	}
+	return 0
+}
+
+type GetUsageHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DevicePath    string                 `protobuf:"bytes,1,opt,name=device_path,json=devicePath,proto3" json:"device_path,omitempty"`
+	Since         int64                  `protobuf:"varint,2,opt,name=since,proto3" json:"since,omitempty"` // Unix timestamp, 0 = all
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"` // Most recent N samples, 0 = no limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageHistoryRequest) Reset() {
+	*x = GetUsageHistoryRequest{}
+	mi := &file_api_v1_forecast_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageHistoryRequest) ProtoMessage() {}
+
+func (x *GetUsageHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_forecast_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_forecast_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetUsageHistoryRequest) GetDevicePath() string {
+	if x != nil {
+		return x.DevicePath
+	}
+	return ""
+}
+
+func (x *GetUsageHistoryRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *GetUsageHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetUsageHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Samples       []*UsageSample         `protobuf:"bytes,1,rep,name=samples,proto3" json:"samples,omitempty"` // Oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageHistoryResponse) Reset() {
+	*x = GetUsageHistoryResponse{}
+	mi := &file_api_v1_forecast_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageHistoryResponse) ProtoMessage() {}
+
+func (x *GetUsageHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_forecast_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetUsageHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_forecast_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetUsageHistoryResponse) GetSamples() []*UsageSample {
+	if x != nil {
+		return x.Samples
+	}
+	return nil
+}
+
+// Linear growth trend
+type UsageTrend struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BytesPerDay   float64                `protobuf:"fixed64,1,opt,name=bytes_per_day,json=bytesPerDay,proto3" json:"bytes_per_day,omitempty"` // Negative when shrinking
+	RSquared      float64                `protobuf:"fixed64,2,opt,name=r_squared,json=rSquared,proto3" json:"r_squared,omitempty"`            // Goodness of fit (0-1)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UsageTrend) Reset() {
+	*x = UsageTrend{}
+	mi := &file_api_v1_forecast_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageTrend) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageTrend) ProtoMessage() {}
+
+func (x *UsageTrend) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_forecast_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageTrend.ProtoReflect.Descriptor instead.
+func (*UsageTrend) Descriptor() ([]byte, []int) {
+	return file_api_v1_forecast_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UsageTrend) GetBytesPerDay() float64 {
+	if x != nil {
+		return x.BytesPerDay
+	}
+	return 0
+}
+
+func (x *UsageTrend) GetRSquared() float64 {
+	if x != nil {
+		return x.RSquared
+	}
+	return 0
+}
+
+// Condition that may lead to ENOSPC
+type EnospcRisk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`         // e.g. "metadata_full_no_unallocated"
+	Severity      string                 `protobuf:"bytes,2,opt,name=severity,proto3" json:"severity,omitempty"` // "warning", "critical"
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnospcRisk) Reset() {
+	*x = EnospcRisk{}
+	mi := &file_api_v1_forecast_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnospcRisk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnospcRisk) ProtoMessage() {}
+
+func (x *EnospcRisk) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_forecast_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnospcRisk.ProtoReflect.Descriptor instead.
+func (*EnospcRisk) Descriptor() ([]byte, []int) {
+	return file_api_v1_forecast_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EnospcRisk) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *EnospcRisk) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *EnospcRisk) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type UsageForecast struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Samples                  int32                  `protobuf:"varint,1,opt,name=samples,proto3" json:"samples,omitempty"`                            // Number of samples the trends were fitted to
+	WindowStart              int64                  `protobuf:"varint,2,opt,name=window_start,json=windowStart,proto3" json:"window_start,omitempty"` // Unix timestamp of the oldest sample
+	WindowEnd                int64                  `protobuf:"varint,3,opt,name=window_end,json=windowEnd,proto3" json:"window_end,omitempty"`       // Unix timestamp of the newest sample
+	Latest                   *UsageSample           `protobuf:"bytes,4,opt,name=latest,proto3" json:"latest,omitempty"`
+	Unallocated              *UsageTrend            `protobuf:"bytes,5,opt,name=unallocated,proto3" json:"unallocated,omitempty"`
+	DataAllocated            *UsageTrend            `protobuf:"bytes,6,opt,name=data_allocated,json=dataAllocated,proto3" json:"data_allocated,omitempty"`
+	DataUsed                 *UsageTrend            `protobuf:"bytes,7,opt,name=data_used,json=dataUsed,proto3" json:"data_used,omitempty"`
+	MetadataAllocated        *UsageTrend            `protobuf:"bytes,8,opt,name=metadata_allocated,json=metadataAllocated,proto3" json:"metadata_allocated,omitempty"`
+	MetadataUsed             *UsageTrend            `protobuf:"bytes,9,opt,name=metadata_used,json=metadataUsed,proto3" json:"metadata_used,omitempty"`
+	UnallocatedExhaustedAt   int64                  `protobuf:"varint,10,opt,name=unallocated_exhausted_at,json=unallocatedExhaustedAt,proto3" json:"unallocated_exhausted_at,omitempty"`         // Unix timestamp, 0 = not predicted
+	MetadataExhaustedAt      int64                  `protobuf:"varint,11,opt,name=metadata_exhausted_at,json=metadataExhaustedAt,proto3" json:"metadata_exhausted_at,omitempty"`                  // Unix timestamp, 0 = not predicted
+	MetadataChunkExhaustedAt int64                  `protobuf:"varint,15,opt,name=metadata_chunk_exhausted_at,json=metadataChunkExhaustedAt,proto3" json:"metadata_chunk_exhausted_at,omitempty"` // Unix timestamp a new metadata chunk no longer fits, 0 = not predicted
+	DataChunkBytes           int64                  `protobuf:"varint,12,opt,name=data_chunk_bytes,json=dataChunkBytes,proto3" json:"data_chunk_bytes,omitempty"`                                 // Raw bytes needed for a new data chunk
+	MetadataChunkBytes       int64                  `protobuf:"varint,13,opt,name=metadata_chunk_bytes,json=metadataChunkBytes,proto3" json:"metadata_chunk_bytes,omitempty"`                     // Raw bytes needed for a new metadata chunk
+	Risks                    []*EnospcRisk          `protobuf:"bytes,14,rep,name=risks,proto3" json:"risks,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *UsageForecast) Reset() {
+	*x = UsageForecast{}
+	mi := &file_api_v1_forecast_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageForecast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageForecast) ProtoMessage() {}
+
+func (x *UsageForecast) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_forecast_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageForecast.ProtoReflect.Descriptor instead.
+func (*UsageForecast) Descriptor() ([]byte, []int) {
+	return file_api_v1_forecast_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UsageForecast) GetSamples() int32 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
+}
+
+func (x *UsageForecast) GetWindowStart() int64 {
+	if x != nil {
+		return x.WindowStart
+	}
+	return 0
+}
+
+func (x *UsageForecast) GetWindowEnd() int64 {
+	if x != nil {
+		return x.WindowEnd
+	}
+	return 0
+}
+
+func (x *UsageForecast) GetLatest() *UsageSample {
+	if x != nil {
+		return x.Latest
+	}
+	return nil
+}
+
+func (x *UsageForecast) GetUnallocated() *UsageTrend {
+	if x != nil {
+		return x.Unallocated
+	}
+	return nil
+}
+
+func (x *UsageForecast) GetDataAllocated() *UsageTrend {
+	if x != nil {
+		return x.DataAllocated
+	}
+	return nil
+}
+
+func (x *UsageForecast) GetDataUsed() *UsageTrend {
+	if x != nil {
+		return x.DataUsed
+	}
+	return nil
+}
+
+func (x *UsageForecast) GetMetadataAllocated() *UsageTrend {
+	if x != nil {
+		return x.MetadataAllocated
+	}
+	return nil
+}
+
+func (x *UsageForecast) GetMetadataUsed() *UsageTrend {
+	if x != nil {
+		return x.MetadataUsed
+	}
+	return nil
+}
+
+func (x *UsageForecast) GetUnallocatedExhaustedAt() int64 {
+	if x != nil {
+		return x.UnallocatedExhaustedAt
+	}
+	return 0
+}
+
+func (x *UsageForecast) GetMetadataExhaustedAt() int64 {
+	if x != nil {
+		return x.MetadataExhaustedAt
+	}
+	return 0
+}
+
+func (x *UsageForecast) GetMetadataChunkExhaustedAt() int64 {
+	if x != nil {
+		return x.MetadataChunkExhaustedAt
+	}
+	return 0
+}
+
+func (x *UsageForecast) GetDataChunkBytes() int64 {
+	if x != nil {
+		return x.DataChunkBytes
+	}
+	return 0
+}
+
+func (x *UsageForecast) GetMetadataChunkBytes() int64 {
+	if x != nil {
+		return x.MetadataChunkBytes
+	}
+	return 0
+}
+
+func (x *UsageForecast) GetRisks() []*EnospcRisk {
+	if x != nil {
+		return x.Risks
+	}
+	return nil
+}
+
+type GetUsageForecastRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DevicePath    string                 `protobuf:"bytes,1,opt,name=device_path,json=devicePath,proto3" json:"device_path,omitempty"`
+	WindowDays    int32                  `protobuf:"varint,2,opt,name=window_days,json=windowDays,proto3" json:"window_days,omitempty"` // How much history to fit (default 30)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageForecastRequest) Reset() {
+	*x = GetUsageForecastRequest{}
+	mi := &file_api_v1_forecast_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageForecastRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageForecastRequest) ProtoMessage() {}
+
+func (x *GetUsageForecastRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_forecast_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageForecastRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageForecastRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_forecast_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetUsageForecastRequest) GetDevicePath() string {
+	if x != nil {
+		return x.DevicePath
+	}
+	return ""
+}
+
+func (x *GetUsageForecastRequest) GetWindowDays() int32 {
+	if x != nil {
+		return x.WindowDays
+	}
+	return 0
+}
+
+type GetUsageForecastResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Forecast      *UsageForecast         `protobuf:"bytes,1,opt,name=forecast,proto3" json:"forecast,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageForecastResponse) Reset() {
+	*x = GetUsageForecastResponse{}
+	mi := &file_api_v1_forecast_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageForecastResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageForecastResponse) ProtoMessage() {}
+
+func (x *GetUsageForecastResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_forecast_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageForecastResponse.ProtoReflect.Descriptor instead.
+func (*GetUsageForecastResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_forecast_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetUsageForecastResponse) GetForecast() *UsageForecast {
+	if x != nil {
+		return x.Forecast
+	}
+	return nil
+}
+
+type GetAllUsageForecastsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WindowDays    int32                  `protobuf:"varint,1,opt,name=window_days,json=windowDays,proto3" json:"window_days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllUsageForecastsRequest) Reset() {
+	*x = GetAllUsageForecastsRequest{}
+	mi := &file_api_v1_forecast_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllUsageForecastsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllUsageForecastsRequest) ProtoMessage() {}
+
+func (x *GetAllUsageForecastsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_forecast_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllUsageForecastsRequest.ProtoReflect.Descriptor instead.
+func (*GetAllUsageForecastsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_forecast_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetAllUsageForecastsRequest) GetWindowDays() int32 {
+	if x != nil {
+		return x.WindowDays
+	}
+	return 0
+}
+
+type FilesystemUsageForecast struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Forecast      *UsageForecast         `protobuf:"bytes,2,opt,name=forecast,proto3" json:"forecast,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"` // If there was an error fetching
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FilesystemUsageForecast) Reset() {
+	*x = FilesystemUsageForecast{}
+	mi := &file_api_v1_forecast_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FilesystemUsageForecast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilesystemUsageForecast) ProtoMessage() {}
+
+func (x *FilesystemUsageForecast) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_forecast_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilesystemUsageForecast.ProtoReflect.Descriptor instead.
+func (*FilesystemUsageForecast) Descriptor() ([]byte, []int) {
+	return file_api_v1_forecast_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *FilesystemUsageForecast) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FilesystemUsageForecast) GetForecast() *UsageForecast {
+	if x != nil {
+		return x.Forecast
+	}
+	return nil
+}
+
+func (x *FilesystemUsageForecast) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type GetAllUsageForecastsResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Filesystems   []*FilesystemUsageForecast `protobuf:"bytes,1,rep,name=filesystems,proto3" json:"filesystems,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllUsageForecastsResponse) Reset() {
+	*x = GetAllUsageForecastsResponse{}
+	mi := &file_api_v1_forecast_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllUsageForecastsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllUsageForecastsResponse) ProtoMessage() {}
+
+func (x *GetAllUsageForecastsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_forecast_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllUsageForecastsResponse.ProtoReflect.Descriptor instead.
+func (*GetAllUsageForecastsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_forecast_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetAllUsageForecastsResponse) GetFilesystems() []*FilesystemUsageForecast {
+	if x != nil {
+		return x.Filesystems
+	}
+	return nil
+}
+
+var File_api_v1_forecast_proto protoreflect.FileDescriptor
+
+const file_api_v1_forecast_proto_rawDesc = "" +
+	"\n" +
+	"\x15api/v1/forecast.proto\x12\x06api.v1\"\xbe\x04\n" +
+	"\vUsageSample\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x1f\n" +
+	"\vdevice_size\x18\x02 \x01(\x03R\n" +
+	"deviceSize\x12)\n" +
+	"\x10device_allocated\x18\x03 \x01(\x03R\x0fdeviceAllocated\x12-\n" +
+	"\x12device_unallocated\x18\x04 \x01(\x03R\x11deviceUnallocated\x12\x1d\n" +
+	"\n" +
+	"data_total\x18\x05 \x01(\x03R\tdataTotal\x12\x1b\n" +
+	"\tdata_used\x18\x06 \x01(\x03R\bdataUsed\x12!\n" +
+	"\fdata_profile\x18\a \x01(\tR\vdataProfile\x12%\n" +
+	"\x0emetadata_total\x18\b \x01(\x03R\rmetadataTotal\x12#\n" +
+	"\rmetadata_used\x18\t \x01(\x03R\fmetadataUsed\x12)\n" +
+	"\x10metadata_profile\x18\n" +
+	" \x01(\tR\x0fmetadataProfile\x12!\n" +
+	"\fsystem_total\x18\v \x01(\x03R\vsystemTotal\x12\x1f\n" +
+	"\vsystem_used\x18\f \x01(\x03R\n" +
+	"systemUsed\x12%\n" +
+	"\x0eglobal_reserve\x18\r \x01(\x03R\rglobalReserve\x12.\n" +
+	"\x13global_reserve_used\x18\x0e \x01(\x03R\x11globalReserveUsed\x12%\n" +
+	"\x0efree_estimated\x18\x0f \x01(\x03R\rfreeEstimated\"e\n" +
+	"\x16GetUsageHistoryRequest\x12\x1f\n" +
+	"\vdevice_path\x18\x01 \x01(\tR\n" +
+	"devicePath\x12\x14\n" +
+	"\x05since\x18\x02 \x01(\x03R\x05since\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"H\n" +
+	"\x17GetUsageHistoryResponse\x12-\n" +
+	"\asamples\x18\x01 \x03(\v2\x13.api.v1.UsageSampleR\asamples\"M\n" +
+	"\n" +
+	"UsageTrend\x12\"\n" +
+	"\rbytes_per_day\x18\x01 \x01(\x01R\vbytesPerDay\x12\x1b\n" +
+	"\tr_squared\x18\x02 \x01(\x01R\brSquared\"V\n" +
+	"\n" +
+	"EnospcRisk\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x1a\n" +
+	"\bseverity\x18\x02 \x01(\tR\bseverity\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xe9\x05\n" +
+	"\rUsageForecast\x12\x18\n" +
+	"\asamples\x18\x01 \x01(\x05R\asamples\x12!\n" +
+	"\fwindow_start\x18\x02 \x01(\x03R\vwindowStart\x12\x1d\n" +
+	"\n" +
+	"window_end\x18\x03 \x01(\x03R\twindowEnd\x12+\n" +
+	"\x06latest\x18\x04 \x01(\v2\x13.api.v1.UsageSampleR\x06latest\x124\n" +
+	"\vunallocated\x18\x05 \x01(\v2\x12.api.v1.UsageTrendR\vunallocated\x129\n" +
+	"\x0edata_allocated\x18\x06 \x01(\v2\x12.api.v1.UsageTrendR\rdataAllocated\x12/\n" +
+	"\tdata_used\x18\a \x01(\v2\x12.api.v1.UsageTrendR\bdataUsed\x12A\n" +
+	"\x12metadata_allocated\x18\b \x01(\v2\x12.api.v1.UsageTrendR\x11metadataAllocated\x127\n" +
+	"\rmetadata_used\x18\t \x01(\v2\x12.api.v1.UsageTrendR\fmetadataUsed\x128\n" +
+	"\x18unallocated_exhausted_at\x18\n" +
+	" \x01(\x03R\x16unallocatedExhaustedAt\x122\n" +
+	"\x15metadata_exhausted_at\x18\v \x01(\x03R\x13metadataExhaustedAt\x12=\n" +
+	"\x1bmetadata_chunk_exhausted_at\x18\x0f \x01(\x03R\x18metadataChunkExhaustedAt\x12(\n" +
+	"\x10data_chunk_bytes\x18\f \x01(\x03R\x0edataChunkBytes\x120\n" +
+	"\x14metadata_chunk_bytes\x18\r \x01(\x03R\x12metadataChunkBytes\x12(\n" +
+	"\x05risks\x18\x0e \x03(\v2\x12.api.v1.EnospcRiskR\x05risks\"[\n" +
+	"\x17GetUsageForecastRequest\x12\x1f\n" +
+	"\vdevice_path\x18\x01 \x01(\tR\n" +
+	"devicePath\x12\x1f\n" +
+	"\vwindow_days\x18\x02 \x01(\x05R\n" +
+	"windowDays\"M\n" +
+	"\x18GetUsageForecastResponse\x121\n" +
+	"\bforecast\x18\x01 \x01(\v2\x15.api.v1.UsageForecastR\bforecast\">\n" +
+	"\x1bGetAllUsageForecastsRequest\x12\x1f\n" +
+	"\vwindow_days\x18\x01 \x01(\x05R\n" +
+	"windowDays\"\x85\x01\n" +
+	"\x17FilesystemUsageForecast\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x121\n" +
+	"\bforecast\x18\x02 \x01(\v2\x15.api.v1.UsageForecastR\bforecast\x12#\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\"a\n" +
+	"\x1cGetAllUsageForecastsResponse\x12A\n" +
+	"\vfilesystems\x18\x01 \x03(\v2\x1f.api.v1.FilesystemUsageForecastR\vfilesystems2\xa5\x02\n" +
+	"\x0fForecastService\x12T\n" +
+	"\x0fGetUsageHistory\x12\x1e.api.v1.GetUsageHistoryRequest\x1a\x1f.api.v1.GetUsageHistoryResponse\"\x00\x12W\n" +
+	"\x10GetUsageForecast\x12\x1f.api.v1.GetUsageForecastRequest\x1a .api.v1.GetUsageForecastResponse\"\x00\x12c\n" +
+	"\x14GetAllUsageForecasts\x12#.api.v1.GetAllUsageForecastsRequest\x1a$.api.v1.GetAllUsageForecastsResponse\"\x00B\x80\x01\n" +
+	"\n" +
+	"com.api.v1B\rForecastProtoP\x01Z*github.com/elee1766/gobtr/gen/api/v1;apiv1\xa2\x02\x03AXX\xaa\x02\x06Api.V1\xca\x02\x06Api\\V1\xe2\x02\x12Api\\V1\\GPBMetadata\xea\x02\aApi::V1b\x06proto3"
+
+var (
+	file_api_v1_forecast_proto_rawDescOnce sync.Once
+	file_api_v1_forecast_proto_rawDescData []byte
+)
+
+func file_api_v1_forecast_proto_rawDescGZIP() []byte {
+	file_api_v1_forecast_proto_rawDescOnce.Do(func() {
+		file_api_v1_forecast_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_v1_forecast_proto_rawDesc), len(file_api_v1_forecast_proto_rawDesc)))
+	})
+	return file_api_v1_forecast_proto_rawDescData
+}
+
+var file_api_v1_forecast_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_api_v1_forecast_proto_goTypes = []any{
+	(*UsageSample)(nil),                  // 0: api.v1.UsageSample
+	(*GetUsageHistoryRequest)(nil),       // 1: api.v1.GetUsageHistoryRequest
+	(*GetUsageHistoryResponse)(nil),      // 2: api.v1.GetUsageHistoryResponse
+	(*UsageTrend)(nil),                   // 3: api.v1.UsageTrend
+	(*EnospcRisk)(nil),                   // 4: api.v1.EnospcRisk
+	(*UsageForecast)(nil),                // 5: api.v1.UsageForecast
+	(*GetUsageForecastRequest)(nil),      // 6: api.v1.GetUsageForecastRequest
+	(*GetUsageForecastResponse)(nil),     // 7: api.v1.GetUsageForecastResponse
+	(*GetAllUsageForecastsRequest)(nil),  // 8: api.v1.GetAllUsageForecastsRequest
+	(*FilesystemUsageForecast)(nil),      // 9: api.v1.FilesystemUsageForecast
+	(*GetAllUsageForecastsResponse)(nil), // 10: api.v1.GetAllUsageForecastsResponse
+}
+var file_api_v1_forecast_proto_depIdxs = []int32{
+	0,  // 0: api.v1.GetUsageHistoryResponse.samples:type_name -> api.v1.UsageSample
+	0,  // 1: api.v1.UsageForecast.latest:type_name -> api.v1.UsageSample
+	3,  // 2: api.v1.UsageForecast.unallocated:type_name -> api.v1.UsageTrend
+	3,  // 3: api.v1.UsageForecast.data_allocated:type_name -> api.v1.UsageTrend
+	3,  // 4: api.v1.UsageForecast.data_used:type_name -> api.v1.UsageTrend
+	3,  // 5: api.v1.UsageForecast.metadata_allocated:type_name -> api.v1.UsageTrend
+	3,  // 6: api.v1.UsageForecast.metadata_used:type_name -> api.v1.UsageTrend
+	4,  // 7: api.v1.UsageForecast.risks:type_name -> api.v1.EnospcRisk
+	5,  // 8: api.v1.GetUsageForecastResponse.forecast:type_name -> api.v1.UsageForecast
+	5,  // 9: api.v1.FilesystemUsageForecast.forecast:type_name -> api.v1.UsageForecast
+	9,  // 10: api.v1.GetAllUsageForecastsResponse.filesystems:type_name -> api.v1.FilesystemUsageForecast
+	1,  // 11: api.v1.ForecastService.GetUsageHistory:input_type -> api.v1.GetUsageHistoryRequest
+	6,  // 12: api.v1.ForecastService.GetUsageForecast:input_type -> api.v1.GetUsageForecastRequest
+	8,  // 13: api.v1.ForecastService.GetAllUsageForecasts:input_type -> api.v1.GetAllUsageForecastsRequest
+	2,  // 14: api.v1.ForecastService.GetUsageHistory:output_type -> api.v1.GetUsageHistoryResponse
+	7,  // 15: api.v1.ForecastService.GetUsageForecast:output_type -> api.v1.GetUsageForecastResponse
+	10, // 16: api.v1.ForecastService.GetAllUsageForecasts:output_type -> api.v1.GetAllUsageForecastsResponse
+	14, // [14:17] is the sub-list for method output_type
+	11, // [11:14] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_api_v1_forecast_proto_init() }
+func file_api_v1_forecast_proto_init() {
+	if File_api_v1_forecast_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_forecast_proto_rawDesc), len(file_api_v1_forecast_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_v1_forecast_proto_goTypes,
+		DependencyIndexes: file_api_v1_forecast_proto_depIdxs,
+		MessageInfos:      file_api_v1_forecast_proto_msgTypes,
+	}.Build()
+	File_api_v1_forecast_proto = out.File
+	file_api_v1_forecast_proto_goTypes = nil
+	file_api_v1_forecast_proto_depIdxs = nil
+}
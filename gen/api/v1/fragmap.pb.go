@@ -620,10 +620,18 @@ func (x *GetDeviceBlockMapResponse) GetEntries() []*BlockMapEntry {
 }
 
 type GetHeatMapRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	FsPath        string                 `protobuf:"bytes,1,opt,name=fs_path,json=fsPath,proto3" json:"fs_path,omitempty"`
-	DeviceId      uint64                 `protobuf:"varint,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
-	Resolution    int32                  `protobuf:"varint,3,opt,name=resolution,proto3" json:"resolution,omitempty"` // Number of cells (default 256)
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	FsPath     string                 `protobuf:"bytes,1,opt,name=fs_path,json=fsPath,proto3" json:"fs_path,omitempty"`
+	DeviceId   uint64                 `protobuf:"varint,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Resolution int32                  `protobuf:"varint,3,opt,name=resolution,proto3" json:"resolution,omitempty"`                // Number of cells (default 256)
+	FreeSpace  bool                   `protobuf:"varint,4,opt,name=free_space,json=freeSpace,proto3" json:"free_space,omitempty"` // Also scan free space inside block groups (slow without a free space tree)
+	// "linear" (default), "row_major" or "hilbert". Hilbert rounds resolution
+	// up to a power of four.
+	Layout string `protobuf:"bytes,5,opt,name=layout,proto3" json:"layout,omitempty"`
+	// Zoom into [start_offset, end_offset) of the device. end_offset 0 means
+	// the end of the device.
+	StartOffset   uint64 `protobuf:"varint,6,opt,name=start_offset,json=startOffset,proto3" json:"start_offset,omitempty"`
+	EndOffset     uint64 `protobuf:"varint,7,opt,name=end_offset,json=endOffset,proto3" json:"end_offset,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -679,6 +687,34 @@ func (x *GetHeatMapRequest) GetResolution() int32 {
 	return 0
 }
 
+func (x *GetHeatMapRequest) GetFreeSpace() bool {
+	if x != nil {
+		return x.FreeSpace
+	}
+	return false
+}
+
+func (x *GetHeatMapRequest) GetLayout() string {
+	if x != nil {
+		return x.Layout
+	}
+	return ""
+}
+
+func (x *GetHeatMapRequest) GetStartOffset() uint64 {
+	if x != nil {
+		return x.StartOffset
+	}
+	return 0
+}
+
+func (x *GetHeatMapRequest) GetEndOffset() uint64 {
+	if x != nil {
+		return x.EndOffset
+	}
+	return 0
+}
+
 type HeatMapCell struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Index          int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
@@ -691,8 +727,16 @@ type HeatMapCell struct {
 	SystemBytes    uint64                 `protobuf:"varint,8,opt,name=system_bytes,json=systemBytes,proto3" json:"system_bytes,omitempty"`
 	ExtentCount    int32                  `protobuf:"varint,9,opt,name=extent_count,json=extentCount,proto3" json:"extent_count,omitempty"`
 	Utilization    float64                `protobuf:"fixed64,10,opt,name=utilization,proto3" json:"utilization,omitempty"` // 0.0 to 1.0
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Only set when free_space was requested
+	ChunkFreeBytes     uint64  `protobuf:"varint,11,opt,name=chunk_free_bytes,json=chunkFreeBytes,proto3" json:"chunk_free_bytes,omitempty"`                // Free bytes inside the chunks in this cell
+	FreeSpaceFragScore float64 `protobuf:"fixed64,12,opt,name=free_space_frag_score,json=freeSpaceFragScore,proto3" json:"free_space_frag_score,omitempty"` // Weighted block group frag score (0-100)
+	DominantProfile    uint64  `protobuf:"varint,13,opt,name=dominant_profile,json=dominantProfile,proto3" json:"dominant_profile,omitempty"`               // Profile with the most allocated bytes
+	ChunkUsedBytes     uint64  `protobuf:"varint,14,opt,name=chunk_used_bytes,json=chunkUsedBytes,proto3" json:"chunk_used_bytes,omitempty"`                // Used bytes of the chunks here, spread evenly per chunk
+	BlockGroupUsage    float64 `protobuf:"fixed64,15,opt,name=block_group_usage,json=blockGroupUsage,proto3" json:"block_group_usage,omitempty"`            // chunk_used_bytes / allocated_bytes (0.0 to 1.0)
+	X                  int32   `protobuf:"varint,16,opt,name=x,proto3" json:"x,omitempty"`                                                                  // Grid position for the requested layout
+	Y                  int32   `protobuf:"varint,17,opt,name=y,proto3" json:"y,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *HeatMapCell) Reset() {
@@ -795,14 +839,71 @@ func (x *HeatMapCell) GetUtilization() float64 {
 	return 0
 }
 
+func (x *HeatMapCell) GetChunkFreeBytes() uint64 {
+	if x != nil {
+		return x.ChunkFreeBytes
+	}
+	return 0
+}
+
+func (x *HeatMapCell) GetFreeSpaceFragScore() float64 {
+	if x != nil {
+		return x.FreeSpaceFragScore
+	}
+	return 0
+}
+
+func (x *HeatMapCell) GetDominantProfile() uint64 {
+	if x != nil {
+		return x.DominantProfile
+	}
+	return 0
+}
+
+func (x *HeatMapCell) GetChunkUsedBytes() uint64 {
+	if x != nil {
+		return x.ChunkUsedBytes
+	}
+	return 0
+}
+
+func (x *HeatMapCell) GetBlockGroupUsage() float64 {
+	if x != nil {
+		return x.BlockGroupUsage
+	}
+	return 0
+}
+
+func (x *HeatMapCell) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *HeatMapCell) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
 type GetHeatMapResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	DeviceId      uint64                 `protobuf:"varint,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
-	TotalSize     uint64                 `protobuf:"varint,2,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
-	Resolution    int32                  `protobuf:"varint,3,opt,name=resolution,proto3" json:"resolution,omitempty"`
-	Cells         []*HeatMapCell         `protobuf:"bytes,4,rep,name=cells,proto3" json:"cells,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId   uint64                 `protobuf:"varint,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	TotalSize  uint64                 `protobuf:"varint,2,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	Resolution int32                  `protobuf:"varint,3,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	Cells      []*HeatMapCell         `protobuf:"bytes,4,rep,name=cells,proto3" json:"cells,omitempty"`
+	// Only set when free_space was requested
+	FreeSpaceSource string                 `protobuf:"bytes,5,opt,name=free_space_source,json=freeSpaceSource,proto3" json:"free_space_source,omitempty"` // "free_space_tree" or "extent_tree"
+	BlockGroups     []*BlockGroupFreeSpace `protobuf:"bytes,6,rep,name=block_groups,json=blockGroups,proto3" json:"block_groups,omitempty"`               // Block groups with a stripe on this device
+	Layout          string                 `protobuf:"bytes,7,opt,name=layout,proto3" json:"layout,omitempty"`
+	Width           int32                  `protobuf:"varint,8,opt,name=width,proto3" json:"width,omitempty"` // Grid size in cells
+	Height          int32                  `protobuf:"varint,9,opt,name=height,proto3" json:"height,omitempty"`
+	StartOffset     uint64                 `protobuf:"varint,10,opt,name=start_offset,json=startOffset,proto3" json:"start_offset,omitempty"` // Range covered by the cells
+	EndOffset       uint64                 `protobuf:"varint,11,opt,name=end_offset,json=endOffset,proto3" json:"end_offset,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *GetHeatMapResponse) Reset() {
@@ -863,6 +964,55 @@ func (x *GetHeatMapResponse) GetCells() []*HeatMapCell {
 	return nil
 }
 
+func (x *GetHeatMapResponse) GetFreeSpaceSource() string {
+	if x != nil {
+		return x.FreeSpaceSource
+	}
+	return ""
+}
+
+func (x *GetHeatMapResponse) GetBlockGroups() []*BlockGroupFreeSpace {
+	if x != nil {
+		return x.BlockGroups
+	}
+	return nil
+}
+
+func (x *GetHeatMapResponse) GetLayout() string {
+	if x != nil {
+		return x.Layout
+	}
+	return ""
+}
+
+func (x *GetHeatMapResponse) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *GetHeatMapResponse) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *GetHeatMapResponse) GetStartOffset() uint64 {
+	if x != nil {
+		return x.StartOffset
+	}
+	return 0
+}
+
+func (x *GetHeatMapResponse) GetEndOffset() uint64 {
+	if x != nil {
+		return x.EndOffset
+	}
+	return 0
+}
+
 type GetFragStatsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	FsPath        string                 `protobuf:"bytes,1,opt,name=fs_path,json=fsPath,proto3" json:"fs_path,omitempty"`
@@ -1263,122 +1413,2576 @@ func (x *GetDeviceBlockMapsResponse) GetMaps() []*DeviceBlockMap {
 	return nil
 }
 
-var File_api_v1_fragmap_proto protoreflect.FileDescriptor
+// Free runs by size. Bucket i holds runs smaller than max_size; the last
+// bucket has max_size 0 and holds everything larger.
+type FreeSpaceBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaxSize       uint64                 `protobuf:"varint,1,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
+	Count         uint64                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Bytes         uint64                 `protobuf:"varint,3,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_api_v1_fragmap_proto_rawDesc = "" +
-	"\n" +
-	"\x14api/v1/fragmap.proto\x12\x06api.v1\",\n" +
-	"\x11GetFragMapRequest\x12\x17\n" +
-	"\afs_path\x18\x01 \x01(\tR\x06fsPath\"_\n" +
-	"\x06Device\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
-	"\x04uuid\x18\x02 \x01(\fR\x04uuid\x12\x1d\n" +
-	"\n" +
-	"total_size\x18\x03 \x01(\x04R\ttotalSize\x12\x12\n" +
-	"\x04path\x18\x04 \x01(\tR\x04path\"=\n" +
-	"\x06Stripe\x12\x1b\n" +
-	"\tdevice_id\x18\x01 \x01(\x04R\bdeviceId\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x04R\x06offset\"\xb2\x01\n" +
-	"\x05Chunk\x12%\n" +
-	"\x0elogical_offset\x18\x01 \x01(\x04R\rlogicalOffset\x12\x16\n" +
-	"\x06length\x18\x02 \x01(\x04R\x06length\x12\x12\n" +
-	"\x04type\x18\x03 \x01(\x04R\x04type\x12\x18\n" +
-	"\aprofile\x18\x04 \x01(\x04R\aprofile\x12(\n" +
-	"\astripes\x18\x05 \x03(\v2\x0e.api.v1.StripeR\astripes\x12\x12\n" +
-	"\x04used\x18\x06 \x01(\x04R\x04used\"\x8f\x01\n" +
-	"\fDeviceExtent\x12\x1b\n" +
-	"\tdevice_id\x18\x01 \x01(\x04R\bdeviceId\x12'\n" +
-	"\x0fphysical_offset\x18\x02 \x01(\x04R\x0ephysicalOffset\x12\x16\n" +
-	"\x06length\x18\x03 \x01(\x04R\x06length\x12!\n" +
-	"\fchunk_offset\x18\x04 \x01(\x04R\vchunkOffset\"\xc1\x01\n" +
-	"\x12GetFragMapResponse\x12\x1d\n" +
-	"\n" +
-	"total_size\x18\x01 \x01(\x04R\ttotalSize\x12(\n" +
-	"\adevices\x18\x02 \x03(\v2\x0e.api.v1.DeviceR\adevices\x12%\n" +
-	"\x06chunks\x18\x03 \x03(\v2\r.api.v1.ChunkR\x06chunks\x12;\n" +
-	"\x0edevice_extents\x18\x04 \x03(\v2\x14.api.v1.DeviceExtentR\rdeviceExtents\"P\n" +
-	"\x18GetDeviceBlockMapRequest\x12\x17\n" +
-	"\afs_path\x18\x01 \x01(\tR\x06fsPath\x12\x1b\n" +
-	"\tdevice_id\x18\x02 \x01(\x04R\bdeviceId\"\xf0\x01\n" +
-	"\rBlockMapEntry\x12\x16\n" +
-	"\x06offset\x18\x01 \x01(\x04R\x06offset\x12\x16\n" +
-	"\x06length\x18\x02 \x01(\x04R\x06length\x12\x12\n" +
-	"\x04type\x18\x03 \x01(\x04R\x04type\x12\x18\n" +
-	"\aprofile\x18\x04 \x01(\x04R\aprofile\x12\x1c\n" +
-	"\tallocated\x18\x05 \x01(\bR\tallocated\x12!\n" +
-	"\fchunk_offset\x18\x06 \x01(\x04R\vchunkOffset\x12\x1d\n" +
-	"\n" +
-	"chunk_used\x18\a \x01(\x04R\tchunkUsed\x12!\n" +
-	"\fchunk_length\x18\b \x01(\x04R\vchunkLength\"\x88\x01\n" +
-	"\x19GetDeviceBlockMapResponse\x12\x1b\n" +
-	"\tdevice_id\x18\x01 \x01(\x04R\bdeviceId\x12\x1d\n" +
-	"\n" +
-	"total_size\x18\x02 \x01(\x04R\ttotalSize\x12/\n" +
-	"\aentries\x18\x03 \x03(\v2\x15.api.v1.BlockMapEntryR\aentries\"i\n" +
-	"\x11GetHeatMapRequest\x12\x17\n" +
-	"\afs_path\x18\x01 \x01(\tR\x06fsPath\x12\x1b\n" +
-	"\tdevice_id\x18\x02 \x01(\x04R\bdeviceId\x12\x1e\n" +
-	"\n" +
-	"resolution\x18\x03 \x01(\x05R\n" +
-	"resolution\"\xdb\x02\n" +
-	"\vHeatMapCell\x12\x14\n" +
-	"\x05index\x18\x01 \x01(\x05R\x05index\x12!\n" +
-	"\fstart_offset\x18\x02 \x01(\x04R\vstartOffset\x12\x1d\n" +
-	"\n" +
-	"end_offset\x18\x03 \x01(\x04R\tendOffset\x12'\n" +
-	"\x0fallocated_bytes\x18\x04 \x01(\x04R\x0eallocatedBytes\x12\x1d\n" +
-	"\n" +
-	"free_bytes\x18\x05 \x01(\x04R\tfreeBytes\x12\x1d\n" +
-	"\n" +
-	"data_bytes\x18\x06 \x01(\x04R\tdataBytes\x12%\n" +
-	"\x0emetadata_bytes\x18\a \x01(\x04R\rmetadataBytes\x12!\n" +
-	"\fsystem_bytes\x18\b \x01(\x04R\vsystemBytes\x12!\n" +
-	"\fextent_count\x18\t \x01(\x05R\vextentCount\x12 \n" +
-	"\vutilization\x18\n" +
-	" \x01(\x01R\vutilization\"\x9b\x01\n" +
-	"\x12GetHeatMapResponse\x12\x1b\n" +
-	"\tdevice_id\x18\x01 \x01(\x04R\bdeviceId\x12\x1d\n" +
-	"\n" +
-	"total_size\x18\x02 \x01(\x04R\ttotalSize\x12\x1e\n" +
-	"\n" +
-	"resolution\x18\x03 \x01(\x05R\n" +
-	"resolution\x12)\n" +
-	"\x05cells\x18\x04 \x03(\v2\x13.api.v1.HeatMapCellR\x05cells\"K\n" +
-	"\x13GetFragStatsRequest\x12\x17\n" +
-	"\afs_path\x18\x01 \x01(\tR\x06fsPath\x12\x1b\n" +
-	"\tdevice_id\x18\x02 \x01(\x04R\bdeviceId\"\xcd\x03\n" +
-	"\tFragStats\x12\x1b\n" +
-	"\tdevice_id\x18\x01 \x01(\x04R\bdeviceId\x12\x1d\n" +
-	"\n" +
-	"total_size\x18\x02 \x01(\x04R\ttotalSize\x12%\n" +
-	"\x0eallocated_size\x18\x03 \x01(\x04R\rallocatedSize\x12\x1b\n" +
-	"\tfree_size\x18\x04 \x01(\x04R\bfreeSize\x12\x1b\n" +
-	"\tdata_size\x18\x05 \x01(\x04R\bdataSize\x12#\n" +
-	"\rmetadata_size\x18\x06 \x01(\x04R\fmetadataSize\x12\x1f\n" +
-	"\vsystem_size\x18\a \x01(\x04R\n" +
-	"systemSize\x12\x1f\n" +
-	"\vnum_extents\x18\b \x01(\x05R\n" +
-	"numExtents\x12(\n" +
-	"\x10num_free_regions\x18\t \x01(\x05R\x0enumFreeRegions\x12!\n" +
-	"\flargest_free\x18\n" +
-	" \x01(\x04R\vlargestFree\x12#\n" +
-	"\rsmallest_free\x18\v \x01(\x04R\fsmallestFree\x12&\n" +
-	"\x0favg_extent_size\x18\f \x01(\x04R\ravgExtentSize\x12\"\n" +
-	"\ravg_free_size\x18\r \x01(\x04R\vavgFreeSize\"?\n" +
-	"\x14GetFragStatsResponse\x12'\n" +
-	"\x05stats\x18\x01 \x03(\v2\x11.api.v1.FragStatsR\x05stats\"S\n" +
-	"\x19GetDeviceBlockMapsRequest\x12\x17\n" +
-	"\afs_path\x18\x01 \x01(\tR\x06fsPath\x12\x1d\n" +
-	"\n" +
-	"device_ids\x18\x02 \x03(\x04R\tdeviceIds\"\xb1\x01\n" +
-	"\x0eDeviceBlockMap\x12&\n" +
-	"\x06device\x18\x01 \x01(\v2\x0e.api.v1.DeviceR\x06device\x12\x1d\n" +
-	"\n" +
-	"total_size\x18\x02 \x01(\x04R\ttotalSize\x12/\n" +
-	"\aentries\x18\x03 \x03(\v2\x15.api.v1.BlockMapEntryR\aentries\x12'\n" +
-	"\x05stats\x18\x04 \x01(\v2\x11.api.v1.FragStatsR\x05stats\"H\n" +
-	"\x1aGetDeviceBlockMapsResponse\x12*\n" +
-	"\x04maps\x18\x01 \x03(\v2\x16.api.v1.DeviceBlockMapR\x04maps2\xa6\x03\n" +
+func (x *FreeSpaceBucket) Reset() {
+	*x = FreeSpaceBucket{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreeSpaceBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreeSpaceBucket) ProtoMessage() {}
+
+func (x *FreeSpaceBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreeSpaceBucket.ProtoReflect.Descriptor instead.
+func (*FreeSpaceBucket) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *FreeSpaceBucket) GetMaxSize() uint64 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+func (x *FreeSpaceBucket) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *FreeSpaceBucket) GetBytes() uint64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+type BlockGroupFreeSpace struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LogicalOffset uint64                 `protobuf:"varint,1,opt,name=logical_offset,json=logicalOffset,proto3" json:"logical_offset,omitempty"`
+	Length        uint64                 `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`
+	Type          uint64                 `protobuf:"varint,3,opt,name=type,proto3" json:"type,omitempty"`
+	Profile       uint64                 `protobuf:"varint,4,opt,name=profile,proto3" json:"profile,omitempty"`
+	FreeBytes     uint64                 `protobuf:"varint,5,opt,name=free_bytes,json=freeBytes,proto3" json:"free_bytes,omitempty"`
+	FreeRuns      int32                  `protobuf:"varint,6,opt,name=free_runs,json=freeRuns,proto3" json:"free_runs,omitempty"`
+	LargestFree   uint64                 `protobuf:"varint,7,opt,name=largest_free,json=largestFree,proto3" json:"largest_free,omitempty"`
+	Histogram     []*FreeSpaceBucket     `protobuf:"bytes,8,rep,name=histogram,proto3" json:"histogram,omitempty"`
+	FragScore     float64                `protobuf:"fixed64,9,opt,name=frag_score,json=fragScore,proto3" json:"frag_score,omitempty"` // Percent of free bytes in runs under 1MiB (0-100)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockGroupFreeSpace) Reset() {
+	*x = BlockGroupFreeSpace{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockGroupFreeSpace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockGroupFreeSpace) ProtoMessage() {}
+
+func (x *BlockGroupFreeSpace) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockGroupFreeSpace.ProtoReflect.Descriptor instead.
+func (*BlockGroupFreeSpace) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *BlockGroupFreeSpace) GetLogicalOffset() uint64 {
+	if x != nil {
+		return x.LogicalOffset
+	}
+	return 0
+}
+
+func (x *BlockGroupFreeSpace) GetLength() uint64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *BlockGroupFreeSpace) GetType() uint64 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *BlockGroupFreeSpace) GetProfile() uint64 {
+	if x != nil {
+		return x.Profile
+	}
+	return 0
+}
+
+func (x *BlockGroupFreeSpace) GetFreeBytes() uint64 {
+	if x != nil {
+		return x.FreeBytes
+	}
+	return 0
+}
+
+func (x *BlockGroupFreeSpace) GetFreeRuns() int32 {
+	if x != nil {
+		return x.FreeRuns
+	}
+	return 0
+}
+
+func (x *BlockGroupFreeSpace) GetLargestFree() uint64 {
+	if x != nil {
+		return x.LargestFree
+	}
+	return 0
+}
+
+func (x *BlockGroupFreeSpace) GetHistogram() []*FreeSpaceBucket {
+	if x != nil {
+		return x.Histogram
+	}
+	return nil
+}
+
+func (x *BlockGroupFreeSpace) GetFragScore() float64 {
+	if x != nil {
+		return x.FragScore
+	}
+	return 0
+}
+
+type FreeSpaceSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          uint64                 `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	BlockGroups   int32                  `protobuf:"varint,2,opt,name=block_groups,json=blockGroups,proto3" json:"block_groups,omitempty"`
+	Length        uint64                 `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`
+	FreeBytes     uint64                 `protobuf:"varint,4,opt,name=free_bytes,json=freeBytes,proto3" json:"free_bytes,omitempty"`
+	FreeRuns      int32                  `protobuf:"varint,5,opt,name=free_runs,json=freeRuns,proto3" json:"free_runs,omitempty"`
+	LargestFree   uint64                 `protobuf:"varint,6,opt,name=largest_free,json=largestFree,proto3" json:"largest_free,omitempty"`
+	Histogram     []*FreeSpaceBucket     `protobuf:"bytes,7,rep,name=histogram,proto3" json:"histogram,omitempty"`
+	FragScore     float64                `protobuf:"fixed64,8,opt,name=frag_score,json=fragScore,proto3" json:"frag_score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreeSpaceSummary) Reset() {
+	*x = FreeSpaceSummary{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreeSpaceSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreeSpaceSummary) ProtoMessage() {}
+
+func (x *FreeSpaceSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreeSpaceSummary.ProtoReflect.Descriptor instead.
+func (*FreeSpaceSummary) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *FreeSpaceSummary) GetType() uint64 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *FreeSpaceSummary) GetBlockGroups() int32 {
+	if x != nil {
+		return x.BlockGroups
+	}
+	return 0
+}
+
+func (x *FreeSpaceSummary) GetLength() uint64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *FreeSpaceSummary) GetFreeBytes() uint64 {
+	if x != nil {
+		return x.FreeBytes
+	}
+	return 0
+}
+
+func (x *FreeSpaceSummary) GetFreeRuns() int32 {
+	if x != nil {
+		return x.FreeRuns
+	}
+	return 0
+}
+
+func (x *FreeSpaceSummary) GetLargestFree() uint64 {
+	if x != nil {
+		return x.LargestFree
+	}
+	return 0
+}
+
+func (x *FreeSpaceSummary) GetHistogram() []*FreeSpaceBucket {
+	if x != nil {
+		return x.Histogram
+	}
+	return nil
+}
+
+func (x *FreeSpaceSummary) GetFragScore() float64 {
+	if x != nil {
+		return x.FragScore
+	}
+	return 0
+}
+
+type GetFreeSpaceStatsRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	FsPath             string                 `protobuf:"bytes,1,opt,name=fs_path,json=fsPath,proto3" json:"fs_path,omitempty"`
+	IncludeBlockGroups bool                   `protobuf:"varint,2,opt,name=include_block_groups,json=includeBlockGroups,proto3" json:"include_block_groups,omitempty"` // Also return every block group, not just the summaries
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GetFreeSpaceStatsRequest) Reset() {
+	*x = GetFreeSpaceStatsRequest{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFreeSpaceStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFreeSpaceStatsRequest) ProtoMessage() {}
+
+func (x *GetFreeSpaceStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFreeSpaceStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetFreeSpaceStatsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetFreeSpaceStatsRequest) GetFsPath() string {
+	if x != nil {
+		return x.FsPath
+	}
+	return ""
+}
+
+func (x *GetFreeSpaceStatsRequest) GetIncludeBlockGroups() bool {
+	if x != nil {
+		return x.IncludeBlockGroups
+	}
+	return false
+}
+
+type GetFreeSpaceStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`       // "free_space_tree" or "extent_tree"
+	Summaries     []*FreeSpaceSummary    `protobuf:"bytes,2,rep,name=summaries,proto3" json:"summaries,omitempty"` // Data, metadata, system
+	BlockGroups   []*BlockGroupFreeSpace `protobuf:"bytes,3,rep,name=block_groups,json=blockGroups,proto3" json:"block_groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFreeSpaceStatsResponse) Reset() {
+	*x = GetFreeSpaceStatsResponse{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFreeSpaceStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFreeSpaceStatsResponse) ProtoMessage() {}
+
+func (x *GetFreeSpaceStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFreeSpaceStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetFreeSpaceStatsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetFreeSpaceStatsResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *GetFreeSpaceStatsResponse) GetSummaries() []*FreeSpaceSummary {
+	if x != nil {
+		return x.Summaries
+	}
+	return nil
+}
+
+func (x *GetFreeSpaceStatsResponse) GetBlockGroups() []*BlockGroupFreeSpace {
+	if x != nil {
+		return x.BlockGroups
+	}
+	return nil
+}
+
+type GetCompressionStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	OneFileSystem bool                   `protobuf:"varint,2,opt,name=one_file_system,json=oneFileSystem,proto3" json:"one_file_system,omitempty"` // Don't descend into other filesystems or subvolumes
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCompressionStatsRequest) Reset() {
+	*x = GetCompressionStatsRequest{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCompressionStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCompressionStatsRequest) ProtoMessage() {}
+
+func (x *GetCompressionStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCompressionStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetCompressionStatsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetCompressionStatsRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GetCompressionStatsRequest) GetOneFileSystem() bool {
+	if x != nil {
+		return x.OneFileSystem
+	}
+	return false
+}
+
+type CompressionUsage struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Compression       string                 `protobuf:"bytes,1,opt,name=compression,proto3" json:"compression,omitempty"`               // "none", "zlib", "lzo" or "zstd"
+	DiskBytes         uint64                 `protobuf:"varint,2,opt,name=disk_bytes,json=diskBytes,proto3" json:"disk_bytes,omitempty"` // On-disk bytes, shared extents counted once
+	UncompressedBytes uint64                 `protobuf:"varint,3,opt,name=uncompressed_bytes,json=uncompressedBytes,proto3" json:"uncompressed_bytes,omitempty"`
+	ReferencedBytes   uint64                 `protobuf:"varint,4,opt,name=referenced_bytes,json=referencedBytes,proto3" json:"referenced_bytes,omitempty"` // File bytes pointing at the extents
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CompressionUsage) Reset() {
+	*x = CompressionUsage{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompressionUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompressionUsage) ProtoMessage() {}
+
+func (x *CompressionUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompressionUsage.ProtoReflect.Descriptor instead.
+func (*CompressionUsage) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CompressionUsage) GetCompression() string {
+	if x != nil {
+		return x.Compression
+	}
+	return ""
+}
+
+func (x *CompressionUsage) GetDiskBytes() uint64 {
+	if x != nil {
+		return x.DiskBytes
+	}
+	return 0
+}
+
+func (x *CompressionUsage) GetUncompressedBytes() uint64 {
+	if x != nil {
+		return x.UncompressedBytes
+	}
+	return 0
+}
+
+func (x *CompressionUsage) GetReferencedBytes() uint64 {
+	if x != nil {
+		return x.ReferencedBytes
+	}
+	return 0
+}
+
+type GetCompressionStatsResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Files             int32                  `protobuf:"varint,1,opt,name=files,proto3" json:"files,omitempty"`
+	Errors            int32                  `protobuf:"varint,2,opt,name=errors,proto3" json:"errors,omitempty"` // Files that could not be read
+	DiskBytes         uint64                 `protobuf:"varint,3,opt,name=disk_bytes,json=diskBytes,proto3" json:"disk_bytes,omitempty"`
+	UncompressedBytes uint64                 `protobuf:"varint,4,opt,name=uncompressed_bytes,json=uncompressedBytes,proto3" json:"uncompressed_bytes,omitempty"`
+	ReferencedBytes   uint64                 `protobuf:"varint,5,opt,name=referenced_bytes,json=referencedBytes,proto3" json:"referenced_bytes,omitempty"`
+	CompressionRatio  float64                `protobuf:"fixed64,6,opt,name=compression_ratio,json=compressionRatio,proto3" json:"compression_ratio,omitempty"` // Uncompressed/on-disk bytes of compressed extents
+	ByCompression     []*CompressionUsage    `protobuf:"bytes,7,rep,name=by_compression,json=byCompression,proto3" json:"by_compression,omitempty"`
+	DurationMs        int64                  `protobuf:"varint,8,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GetCompressionStatsResponse) Reset() {
+	*x = GetCompressionStatsResponse{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCompressionStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCompressionStatsResponse) ProtoMessage() {}
+
+func (x *GetCompressionStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCompressionStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetCompressionStatsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetCompressionStatsResponse) GetFiles() int32 {
+	if x != nil {
+		return x.Files
+	}
+	return 0
+}
+
+func (x *GetCompressionStatsResponse) GetErrors() int32 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *GetCompressionStatsResponse) GetDiskBytes() uint64 {
+	if x != nil {
+		return x.DiskBytes
+	}
+	return 0
+}
+
+func (x *GetCompressionStatsResponse) GetUncompressedBytes() uint64 {
+	if x != nil {
+		return x.UncompressedBytes
+	}
+	return 0
+}
+
+func (x *GetCompressionStatsResponse) GetReferencedBytes() uint64 {
+	if x != nil {
+		return x.ReferencedBytes
+	}
+	return 0
+}
+
+func (x *GetCompressionStatsResponse) GetCompressionRatio() float64 {
+	if x != nil {
+		return x.CompressionRatio
+	}
+	return 0
+}
+
+func (x *GetCompressionStatsResponse) GetByCompression() []*CompressionUsage {
+	if x != nil {
+		return x.ByCompression
+	}
+	return nil
+}
+
+func (x *GetCompressionStatsResponse) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+type ScanFilesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Workers       int32                  `protobuf:"varint,2,opt,name=workers,proto3" json:"workers,omitempty"`                                    // 0 = number of CPUs
+	MaxDepth      int32                  `protobuf:"varint,3,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`                  // 0 = unlimited, 1 = only files directly in path
+	Exclude       []string               `protobuf:"bytes,4,rep,name=exclude,proto3" json:"exclude,omitempty"`                                     // Globs matched against base names and relative paths
+	OneFileSystem bool                   `protobuf:"varint,5,opt,name=one_file_system,json=oneFileSystem,proto3" json:"one_file_system,omitempty"` // Don't descend into other filesystems or subvolumes
+	Top           int32                  `protobuf:"varint,6,opt,name=top,proto3" json:"top,omitempty"`                                            // Most fragmented files to return (0 = 20)
+	// Checkpoint on the server so an interrupted scan of the same path and
+	// options picks up where it left off
+	Resumable     bool `protobuf:"varint,7,opt,name=resumable,proto3" json:"resumable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanFilesRequest) Reset() {
+	*x = ScanFilesRequest{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanFilesRequest) ProtoMessage() {}
+
+func (x *ScanFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanFilesRequest.ProtoReflect.Descriptor instead.
+func (*ScanFilesRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ScanFilesRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ScanFilesRequest) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *ScanFilesRequest) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+func (x *ScanFilesRequest) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+func (x *ScanFilesRequest) GetOneFileSystem() bool {
+	if x != nil {
+		return x.OneFileSystem
+	}
+	return false
+}
+
+func (x *ScanFilesRequest) GetTop() int32 {
+	if x != nil {
+		return x.Top
+	}
+	return 0
+}
+
+func (x *ScanFilesRequest) GetResumable() bool {
+	if x != nil {
+		return x.Resumable
+	}
+	return false
+}
+
+type ScanFilesProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FilesScanned  int32                  `protobuf:"varint,1,opt,name=files_scanned,json=filesScanned,proto3" json:"files_scanned,omitempty"`
+	BytesScanned  int64                  `protobuf:"varint,2,opt,name=bytes_scanned,json=bytesScanned,proto3" json:"bytes_scanned,omitempty"`
+	Errors        int32                  `protobuf:"varint,3,opt,name=errors,proto3" json:"errors,omitempty"`
+	CurrentPath   string                 `protobuf:"bytes,4,opt,name=current_path,json=currentPath,proto3" json:"current_path,omitempty"`
+	ElapsedMs     int64                  `protobuf:"varint,5,opt,name=elapsed_ms,json=elapsedMs,proto3" json:"elapsed_ms,omitempty"`
+	Resumed       bool                   `protobuf:"varint,6,opt,name=resumed,proto3" json:"resumed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanFilesProgress) Reset() {
+	*x = ScanFilesProgress{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanFilesProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanFilesProgress) ProtoMessage() {}
+
+func (x *ScanFilesProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanFilesProgress.ProtoReflect.Descriptor instead.
+func (*ScanFilesProgress) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ScanFilesProgress) GetFilesScanned() int32 {
+	if x != nil {
+		return x.FilesScanned
+	}
+	return 0
+}
+
+func (x *ScanFilesProgress) GetBytesScanned() int64 {
+	if x != nil {
+		return x.BytesScanned
+	}
+	return 0
+}
+
+func (x *ScanFilesProgress) GetErrors() int32 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *ScanFilesProgress) GetCurrentPath() string {
+	if x != nil {
+		return x.CurrentPath
+	}
+	return ""
+}
+
+func (x *ScanFilesProgress) GetElapsedMs() int64 {
+	if x != nil {
+		return x.ElapsedMs
+	}
+	return 0
+}
+
+func (x *ScanFilesProgress) GetResumed() bool {
+	if x != nil {
+		return x.Resumed
+	}
+	return false
+}
+
+type FileFragSummary struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Path              string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Size              int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	ExtentCount       int32                  `protobuf:"varint,3,opt,name=extent_count,json=extentCount,proto3" json:"extent_count,omitempty"`
+	IdealExtents      int32                  `protobuf:"varint,4,opt,name=ideal_extents,json=idealExtents,proto3" json:"ideal_extents,omitempty"`
+	Dof               float64                `protobuf:"fixed64,5,opt,name=dof,proto3" json:"dof,omitempty"`
+	FragmentationPct  float64                `protobuf:"fixed64,6,opt,name=fragmentation_pct,json=fragmentationPct,proto3" json:"fragmentation_pct,omitempty"`
+	OutOfOrderPct     float64                `protobuf:"fixed64,7,opt,name=out_of_order_pct,json=outOfOrderPct,proto3" json:"out_of_order_pct,omitempty"`
+	CompressedExtents int32                  `protobuf:"varint,8,opt,name=compressed_extents,json=compressedExtents,proto3" json:"compressed_extents,omitempty"`
+	SharedExtents     int32                  `protobuf:"varint,9,opt,name=shared_extents,json=sharedExtents,proto3" json:"shared_extents,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *FileFragSummary) Reset() {
+	*x = FileFragSummary{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileFragSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileFragSummary) ProtoMessage() {}
+
+func (x *FileFragSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileFragSummary.ProtoReflect.Descriptor instead.
+func (*FileFragSummary) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *FileFragSummary) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileFragSummary) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileFragSummary) GetExtentCount() int32 {
+	if x != nil {
+		return x.ExtentCount
+	}
+	return 0
+}
+
+func (x *FileFragSummary) GetIdealExtents() int32 {
+	if x != nil {
+		return x.IdealExtents
+	}
+	return 0
+}
+
+func (x *FileFragSummary) GetDof() float64 {
+	if x != nil {
+		return x.Dof
+	}
+	return 0
+}
+
+func (x *FileFragSummary) GetFragmentationPct() float64 {
+	if x != nil {
+		return x.FragmentationPct
+	}
+	return 0
+}
+
+func (x *FileFragSummary) GetOutOfOrderPct() float64 {
+	if x != nil {
+		return x.OutOfOrderPct
+	}
+	return 0
+}
+
+func (x *FileFragSummary) GetCompressedExtents() int32 {
+	if x != nil {
+		return x.CompressedExtents
+	}
+	return 0
+}
+
+func (x *FileFragSummary) GetSharedExtents() int32 {
+	if x != nil {
+		return x.SharedExtents
+	}
+	return 0
+}
+
+type DoFBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Range         string                 `protobuf:"bytes,1,opt,name=range,proto3" json:"range,omitempty"` // "1", "1-2", "2-5", "5-10", "10+"
+	Files         int32                  `protobuf:"varint,2,opt,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DoFBucket) Reset() {
+	*x = DoFBucket{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DoFBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DoFBucket) ProtoMessage() {}
+
+func (x *DoFBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DoFBucket.ProtoReflect.Descriptor instead.
+func (*DoFBucket) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DoFBucket) GetRange() string {
+	if x != nil {
+		return x.Range
+	}
+	return ""
+}
+
+func (x *DoFBucket) GetFiles() int32 {
+	if x != nil {
+		return x.Files
+	}
+	return 0
+}
+
+type FileFragStats struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	TotalFiles        int32                  `protobuf:"varint,1,opt,name=total_files,json=totalFiles,proto3" json:"total_files,omitempty"`
+	TotalExtents      int32                  `protobuf:"varint,2,opt,name=total_extents,json=totalExtents,proto3" json:"total_extents,omitempty"`
+	TotalBytes        int64                  `protobuf:"varint,3,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	FragmentedFiles   int32                  `protobuf:"varint,4,opt,name=fragmented_files,json=fragmentedFiles,proto3" json:"fragmented_files,omitempty"`
+	AvgDof            float64                `protobuf:"fixed64,5,opt,name=avg_dof,json=avgDof,proto3" json:"avg_dof,omitempty"`
+	AvgFragPct        float64                `protobuf:"fixed64,6,opt,name=avg_frag_pct,json=avgFragPct,proto3" json:"avg_frag_pct,omitempty"`
+	AvgOutOfOrderPct  float64                `protobuf:"fixed64,7,opt,name=avg_out_of_order_pct,json=avgOutOfOrderPct,proto3" json:"avg_out_of_order_pct,omitempty"`
+	MaxDof            float64                `protobuf:"fixed64,8,opt,name=max_dof,json=maxDof,proto3" json:"max_dof,omitempty"`
+	MaxExtents        int32                  `protobuf:"varint,9,opt,name=max_extents,json=maxExtents,proto3" json:"max_extents,omitempty"`
+	CompressedFiles   int32                  `protobuf:"varint,10,opt,name=compressed_files,json=compressedFiles,proto3" json:"compressed_files,omitempty"`
+	CompressedExtents int32                  `protobuf:"varint,11,opt,name=compressed_extents,json=compressedExtents,proto3" json:"compressed_extents,omitempty"`
+	InlineExtents     int32                  `protobuf:"varint,12,opt,name=inline_extents,json=inlineExtents,proto3" json:"inline_extents,omitempty"`
+	SharedExtents     int32                  `protobuf:"varint,13,opt,name=shared_extents,json=sharedExtents,proto3" json:"shared_extents,omitempty"`
+	UnwrittenExtents  int32                  `protobuf:"varint,14,opt,name=unwritten_extents,json=unwrittenExtents,proto3" json:"unwritten_extents,omitempty"`
+	CompressedBytes   int64                  `protobuf:"varint,15,opt,name=compressed_bytes,json=compressedBytes,proto3" json:"compressed_bytes,omitempty"`
+	SharedBytes       int64                  `protobuf:"varint,16,opt,name=shared_bytes,json=sharedBytes,proto3" json:"shared_bytes,omitempty"`
+	DofHistogram      []*DoFBucket           `protobuf:"bytes,17,rep,name=dof_histogram,json=dofHistogram,proto3" json:"dof_histogram,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *FileFragStats) Reset() {
+	*x = FileFragStats{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileFragStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileFragStats) ProtoMessage() {}
+
+func (x *FileFragStats) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileFragStats.ProtoReflect.Descriptor instead.
+func (*FileFragStats) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *FileFragStats) GetTotalFiles() int32 {
+	if x != nil {
+		return x.TotalFiles
+	}
+	return 0
+}
+
+func (x *FileFragStats) GetTotalExtents() int32 {
+	if x != nil {
+		return x.TotalExtents
+	}
+	return 0
+}
+
+func (x *FileFragStats) GetTotalBytes() int64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *FileFragStats) GetFragmentedFiles() int32 {
+	if x != nil {
+		return x.FragmentedFiles
+	}
+	return 0
+}
+
+func (x *FileFragStats) GetAvgDof() float64 {
+	if x != nil {
+		return x.AvgDof
+	}
+	return 0
+}
+
+func (x *FileFragStats) GetAvgFragPct() float64 {
+	if x != nil {
+		return x.AvgFragPct
+	}
+	return 0
+}
+
+func (x *FileFragStats) GetAvgOutOfOrderPct() float64 {
+	if x != nil {
+		return x.AvgOutOfOrderPct
+	}
+	return 0
+}
+
+func (x *FileFragStats) GetMaxDof() float64 {
+	if x != nil {
+		return x.MaxDof
+	}
+	return 0
+}
+
+func (x *FileFragStats) GetMaxExtents() int32 {
+	if x != nil {
+		return x.MaxExtents
+	}
+	return 0
+}
+
+func (x *FileFragStats) GetCompressedFiles() int32 {
+	if x != nil {
+		return x.CompressedFiles
+	}
+	return 0
+}
+
+func (x *FileFragStats) GetCompressedExtents() int32 {
+	if x != nil {
+		return x.CompressedExtents
+	}
+	return 0
+}
+
+func (x *FileFragStats) GetInlineExtents() int32 {
+	if x != nil {
+		return x.InlineExtents
+	}
+	return 0
+}
+
+func (x *FileFragStats) GetSharedExtents() int32 {
+	if x != nil {
+		return x.SharedExtents
+	}
+	return 0
+}
+
+func (x *FileFragStats) GetUnwrittenExtents() int32 {
+	if x != nil {
+		return x.UnwrittenExtents
+	}
+	return 0
+}
+
+func (x *FileFragStats) GetCompressedBytes() int64 {
+	if x != nil {
+		return x.CompressedBytes
+	}
+	return 0
+}
+
+func (x *FileFragStats) GetSharedBytes() int64 {
+	if x != nil {
+		return x.SharedBytes
+	}
+	return 0
+}
+
+func (x *FileFragStats) GetDofHistogram() []*DoFBucket {
+	if x != nil {
+		return x.DofHistogram
+	}
+	return nil
+}
+
+type ScanFileError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanFileError) Reset() {
+	*x = ScanFileError{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanFileError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanFileError) ProtoMessage() {}
+
+func (x *ScanFileError) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanFileError.ProtoReflect.Descriptor instead.
+func (*ScanFileError) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ScanFileError) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ScanFileError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ScanFilesResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         *FileFragStats         `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	Top           []*FileFragSummary     `protobuf:"bytes,2,rep,name=top,proto3" json:"top,omitempty"` // Most fragmented first
+	Errors        int32                  `protobuf:"varint,3,opt,name=errors,proto3" json:"errors,omitempty"`
+	ErrorSamples  []*ScanFileError       `protobuf:"bytes,4,rep,name=error_samples,json=errorSamples,proto3" json:"error_samples,omitempty"`
+	Resumed       bool                   `protobuf:"varint,5,opt,name=resumed,proto3" json:"resumed,omitempty"`
+	ElapsedMs     int64                  `protobuf:"varint,6,opt,name=elapsed_ms,json=elapsedMs,proto3" json:"elapsed_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanFilesResult) Reset() {
+	*x = ScanFilesResult{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanFilesResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanFilesResult) ProtoMessage() {}
+
+func (x *ScanFilesResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanFilesResult.ProtoReflect.Descriptor instead.
+func (*ScanFilesResult) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ScanFilesResult) GetStats() *FileFragStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *ScanFilesResult) GetTop() []*FileFragSummary {
+	if x != nil {
+		return x.Top
+	}
+	return nil
+}
+
+func (x *ScanFilesResult) GetErrors() int32 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *ScanFilesResult) GetErrorSamples() []*ScanFileError {
+	if x != nil {
+		return x.ErrorSamples
+	}
+	return nil
+}
+
+func (x *ScanFilesResult) GetResumed() bool {
+	if x != nil {
+		return x.Resumed
+	}
+	return false
+}
+
+func (x *ScanFilesResult) GetElapsedMs() int64 {
+	if x != nil {
+		return x.ElapsedMs
+	}
+	return 0
+}
+
+// Progress updates are sent while scanning; the last message carries the result
+type ScanFilesUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Progress      *ScanFilesProgress     `protobuf:"bytes,1,opt,name=progress,proto3" json:"progress,omitempty"`
+	Result        *ScanFilesResult       `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanFilesUpdate) Reset() {
+	*x = ScanFilesUpdate{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanFilesUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanFilesUpdate) ProtoMessage() {}
+
+func (x *ScanFilesUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanFilesUpdate.ProtoReflect.Descriptor instead.
+func (*ScanFilesUpdate) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ScanFilesUpdate) GetProgress() *ScanFilesProgress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *ScanFilesUpdate) GetResult() *ScanFilesResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type ListFragMapScansRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FsPath        string                 `protobuf:"bytes,1,opt,name=fs_path,json=fsPath,proto3" json:"fs_path,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // 0 = all
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFragMapScansRequest) Reset() {
+	*x = ListFragMapScansRequest{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFragMapScansRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFragMapScansRequest) ProtoMessage() {}
+
+func (x *ListFragMapScansRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFragMapScansRequest.ProtoReflect.Descriptor instead.
+func (*ListFragMapScansRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListFragMapScansRequest) GetFsPath() string {
+	if x != nil {
+		return x.FsPath
+	}
+	return ""
+}
+
+func (x *ListFragMapScansRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type FragMapScanInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	FsUuid        string                 `protobuf:"bytes,2,opt,name=fs_uuid,json=fsUuid,proto3" json:"fs_uuid,omitempty"`
+	Generation    uint64                 `protobuf:"varint,3,opt,name=generation,proto3" json:"generation,omitempty"`
+	ScannedAt     int64                  `protobuf:"varint,4,opt,name=scanned_at,json=scannedAt,proto3" json:"scanned_at,omitempty"`
+	TotalSize     uint64                 `protobuf:"varint,5,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	ChunkCount    int32                  `protobuf:"varint,6,opt,name=chunk_count,json=chunkCount,proto3" json:"chunk_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FragMapScanInfo) Reset() {
+	*x = FragMapScanInfo{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FragMapScanInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FragMapScanInfo) ProtoMessage() {}
+
+func (x *FragMapScanInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FragMapScanInfo.ProtoReflect.Descriptor instead.
+func (*FragMapScanInfo) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *FragMapScanInfo) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *FragMapScanInfo) GetFsUuid() string {
+	if x != nil {
+		return x.FsUuid
+	}
+	return ""
+}
+
+func (x *FragMapScanInfo) GetGeneration() uint64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *FragMapScanInfo) GetScannedAt() int64 {
+	if x != nil {
+		return x.ScannedAt
+	}
+	return 0
+}
+
+func (x *FragMapScanInfo) GetTotalSize() uint64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *FragMapScanInfo) GetChunkCount() int32 {
+	if x != nil {
+		return x.ChunkCount
+	}
+	return 0
+}
+
+type ListFragMapScansResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scans         []*FragMapScanInfo     `protobuf:"bytes,1,rep,name=scans,proto3" json:"scans,omitempty"` // Newest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFragMapScansResponse) Reset() {
+	*x = ListFragMapScansResponse{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFragMapScansResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFragMapScansResponse) ProtoMessage() {}
+
+func (x *ListFragMapScansResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFragMapScansResponse.ProtoReflect.Descriptor instead.
+func (*ListFragMapScansResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListFragMapScansResponse) GetScans() []*FragMapScanInfo {
+	if x != nil {
+		return x.Scans
+	}
+	return nil
+}
+
+type DiffFragMapsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FsPath        string                 `protobuf:"bytes,1,opt,name=fs_path,json=fsPath,proto3" json:"fs_path,omitempty"`
+	FromScanId    int64                  `protobuf:"varint,2,opt,name=from_scan_id,json=fromScanId,proto3" json:"from_scan_id,omitempty"` // 0 = the latest saved scan before to_scan_id
+	ToScanId      int64                  `protobuf:"varint,3,opt,name=to_scan_id,json=toScanId,proto3" json:"to_scan_id,omitempty"`       // 0 = the current state of the filesystem
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffFragMapsRequest) Reset() {
+	*x = DiffFragMapsRequest{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffFragMapsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffFragMapsRequest) ProtoMessage() {}
+
+func (x *DiffFragMapsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffFragMapsRequest.ProtoReflect.Descriptor instead.
+func (*DiffFragMapsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *DiffFragMapsRequest) GetFsPath() string {
+	if x != nil {
+		return x.FsPath
+	}
+	return ""
+}
+
+func (x *DiffFragMapsRequest) GetFromScanId() int64 {
+	if x != nil {
+		return x.FromScanId
+	}
+	return 0
+}
+
+func (x *DiffFragMapsRequest) GetToScanId() int64 {
+	if x != nil {
+		return x.ToScanId
+	}
+	return 0
+}
+
+type ChunkChange struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Kind            string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`                                              // "allocated", "freed" or "relocated"
+	Chunk           *Chunk                 `protobuf:"bytes,2,opt,name=chunk,proto3" json:"chunk,omitempty"`                                            // As in the newer scan (the older one for freed chunks)
+	PreviousStripes []*Stripe              `protobuf:"bytes,3,rep,name=previous_stripes,json=previousStripes,proto3" json:"previous_stripes,omitempty"` // Relocated chunks only
+	PreviousUsed    uint64                 `protobuf:"varint,4,opt,name=previous_used,json=previousUsed,proto3" json:"previous_used,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChunkChange) Reset() {
+	*x = ChunkChange{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChunkChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChunkChange) ProtoMessage() {}
+
+func (x *ChunkChange) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChunkChange.ProtoReflect.Descriptor instead.
+func (*ChunkChange) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ChunkChange) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ChunkChange) GetChunk() *Chunk {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+func (x *ChunkChange) GetPreviousStripes() []*Stripe {
+	if x != nil {
+		return x.PreviousStripes
+	}
+	return nil
+}
+
+func (x *ChunkChange) GetPreviousUsed() uint64 {
+	if x != nil {
+		return x.PreviousUsed
+	}
+	return 0
+}
+
+type TypeDiff struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Type            uint64                 `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"` // data=1, system=2, metadata=4
+	AllocatedChunks int32                  `protobuf:"varint,2,opt,name=allocated_chunks,json=allocatedChunks,proto3" json:"allocated_chunks,omitempty"`
+	AllocatedBytes  uint64                 `protobuf:"varint,3,opt,name=allocated_bytes,json=allocatedBytes,proto3" json:"allocated_bytes,omitempty"`
+	FreedChunks     int32                  `protobuf:"varint,4,opt,name=freed_chunks,json=freedChunks,proto3" json:"freed_chunks,omitempty"`
+	FreedBytes      uint64                 `protobuf:"varint,5,opt,name=freed_bytes,json=freedBytes,proto3" json:"freed_bytes,omitempty"`
+	RelocatedChunks int32                  `protobuf:"varint,6,opt,name=relocated_chunks,json=relocatedChunks,proto3" json:"relocated_chunks,omitempty"`
+	RelocatedBytes  uint64                 `protobuf:"varint,7,opt,name=relocated_bytes,json=relocatedBytes,proto3" json:"relocated_bytes,omitempty"`
+	ResizedChunks   int32                  `protobuf:"varint,8,opt,name=resized_chunks,json=resizedChunks,proto3" json:"resized_chunks,omitempty"` // Chunks in both scans whose used bytes changed
+	UsedBefore      uint64                 `protobuf:"varint,9,opt,name=used_before,json=usedBefore,proto3" json:"used_before,omitempty"`
+	UsedAfter       uint64                 `protobuf:"varint,10,opt,name=used_after,json=usedAfter,proto3" json:"used_after,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TypeDiff) Reset() {
+	*x = TypeDiff{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TypeDiff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeDiff) ProtoMessage() {}
+
+func (x *TypeDiff) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeDiff.ProtoReflect.Descriptor instead.
+func (*TypeDiff) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *TypeDiff) GetType() uint64 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *TypeDiff) GetAllocatedChunks() int32 {
+	if x != nil {
+		return x.AllocatedChunks
+	}
+	return 0
+}
+
+func (x *TypeDiff) GetAllocatedBytes() uint64 {
+	if x != nil {
+		return x.AllocatedBytes
+	}
+	return 0
+}
+
+func (x *TypeDiff) GetFreedChunks() int32 {
+	if x != nil {
+		return x.FreedChunks
+	}
+	return 0
+}
+
+func (x *TypeDiff) GetFreedBytes() uint64 {
+	if x != nil {
+		return x.FreedBytes
+	}
+	return 0
+}
+
+func (x *TypeDiff) GetRelocatedChunks() int32 {
+	if x != nil {
+		return x.RelocatedChunks
+	}
+	return 0
+}
+
+func (x *TypeDiff) GetRelocatedBytes() uint64 {
+	if x != nil {
+		return x.RelocatedBytes
+	}
+	return 0
+}
+
+func (x *TypeDiff) GetResizedChunks() int32 {
+	if x != nil {
+		return x.ResizedChunks
+	}
+	return 0
+}
+
+func (x *TypeDiff) GetUsedBefore() uint64 {
+	if x != nil {
+		return x.UsedBefore
+	}
+	return 0
+}
+
+func (x *TypeDiff) GetUsedAfter() uint64 {
+	if x != nil {
+		return x.UsedAfter
+	}
+	return 0
+}
+
+type DiffFragMapsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *FragMapScanInfo       `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            *FragMapScanInfo       `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`           // id 0 when diffing against the current state
+	Changes       []*ChunkChange         `protobuf:"bytes,3,rep,name=changes,proto3" json:"changes,omitempty"` // Sorted by logical address
+	ByType        []*TypeDiff            `protobuf:"bytes,4,rep,name=by_type,json=byType,proto3" json:"by_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffFragMapsResponse) Reset() {
+	*x = DiffFragMapsResponse{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffFragMapsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffFragMapsResponse) ProtoMessage() {}
+
+func (x *DiffFragMapsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffFragMapsResponse.ProtoReflect.Descriptor instead.
+func (*DiffFragMapsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DiffFragMapsResponse) GetFrom() *FragMapScanInfo {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *DiffFragMapsResponse) GetTo() *FragMapScanInfo {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *DiffFragMapsResponse) GetChanges() []*ChunkChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *DiffFragMapsResponse) GetByType() []*TypeDiff {
+	if x != nil {
+		return x.ByType
+	}
+	return nil
+}
+
+type ResolveLogicalRangeRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	FsPath string                 `protobuf:"bytes,1,opt,name=fs_path,json=fsPath,proto3" json:"fs_path,omitempty"`
+	// A logical range. With logical_end 0, the chunk containing logical_start.
+	LogicalStart uint64 `protobuf:"varint,2,opt,name=logical_start,json=logicalStart,proto3" json:"logical_start,omitempty"`
+	LogicalEnd   uint64 `protobuf:"varint,3,opt,name=logical_end,json=logicalEnd,proto3" json:"logical_end,omitempty"`
+	// Or, when device_id is set, the chunk stored at this device offset
+	DeviceId      uint64 `protobuf:"varint,4,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	DeviceOffset  uint64 `protobuf:"varint,5,opt,name=device_offset,json=deviceOffset,proto3" json:"device_offset,omitempty"`
+	MaxExtents    int32  `protobuf:"varint,6,opt,name=max_extents,json=maxExtents,proto3" json:"max_extents,omitempty"` // Data extents to resolve to files (default 1000)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveLogicalRangeRequest) Reset() {
+	*x = ResolveLogicalRangeRequest{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveLogicalRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveLogicalRangeRequest) ProtoMessage() {}
+
+func (x *ResolveLogicalRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveLogicalRangeRequest.ProtoReflect.Descriptor instead.
+func (*ResolveLogicalRangeRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ResolveLogicalRangeRequest) GetFsPath() string {
+	if x != nil {
+		return x.FsPath
+	}
+	return ""
+}
+
+func (x *ResolveLogicalRangeRequest) GetLogicalStart() uint64 {
+	if x != nil {
+		return x.LogicalStart
+	}
+	return 0
+}
+
+func (x *ResolveLogicalRangeRequest) GetLogicalEnd() uint64 {
+	if x != nil {
+		return x.LogicalEnd
+	}
+	return 0
+}
+
+func (x *ResolveLogicalRangeRequest) GetDeviceId() uint64 {
+	if x != nil {
+		return x.DeviceId
+	}
+	return 0
+}
+
+func (x *ResolveLogicalRangeRequest) GetDeviceOffset() uint64 {
+	if x != nil {
+		return x.DeviceOffset
+	}
+	return 0
+}
+
+func (x *ResolveLogicalRangeRequest) GetMaxExtents() int32 {
+	if x != nil {
+		return x.MaxExtents
+	}
+	return 0
+}
+
+type ExtentOwner struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Root          uint64                 `protobuf:"varint,1,opt,name=root,proto3" json:"root,omitempty"` // Subvolume ID
+	Inode         uint64                 `protobuf:"varint,2,opt,name=inode,proto3" json:"inode,omitempty"`
+	Offset        uint64                 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"` // File offset where the reference starts
+	Path          string                 `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`      // Relative to the top-level subvolume, empty if unresolved
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtentOwner) Reset() {
+	*x = ExtentOwner{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtentOwner) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtentOwner) ProtoMessage() {}
+
+func (x *ExtentOwner) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtentOwner.ProtoReflect.Descriptor instead.
+func (*ExtentOwner) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ExtentOwner) GetRoot() uint64 {
+	if x != nil {
+		return x.Root
+	}
+	return 0
+}
+
+func (x *ExtentOwner) GetInode() uint64 {
+	if x != nil {
+		return x.Inode
+	}
+	return 0
+}
+
+func (x *ExtentOwner) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ExtentOwner) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type LogicalExtent struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Logical         uint64                 `protobuf:"varint,1,opt,name=logical,proto3" json:"logical,omitempty"`
+	Length          uint64                 `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`
+	Refs            uint64                 `protobuf:"varint,3,opt,name=refs,proto3" json:"refs,omitempty"`
+	Owners          []*ExtentOwner         `protobuf:"bytes,4,rep,name=owners,proto3" json:"owners,omitempty"`
+	OwnersTruncated bool                   `protobuf:"varint,5,opt,name=owners_truncated,json=ownersTruncated,proto3" json:"owners_truncated,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *LogicalExtent) Reset() {
+	*x = LogicalExtent{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogicalExtent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogicalExtent) ProtoMessage() {}
+
+func (x *LogicalExtent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogicalExtent.ProtoReflect.Descriptor instead.
+func (*LogicalExtent) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *LogicalExtent) GetLogical() uint64 {
+	if x != nil {
+		return x.Logical
+	}
+	return 0
+}
+
+func (x *LogicalExtent) GetLength() uint64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *LogicalExtent) GetRefs() uint64 {
+	if x != nil {
+		return x.Refs
+	}
+	return 0
+}
+
+func (x *LogicalExtent) GetOwners() []*ExtentOwner {
+	if x != nil {
+		return x.Owners
+	}
+	return nil
+}
+
+func (x *LogicalExtent) GetOwnersTruncated() bool {
+	if x != nil {
+		return x.OwnersTruncated
+	}
+	return false
+}
+
+type LogicalFileUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Root          uint64                 `protobuf:"varint,2,opt,name=root,proto3" json:"root,omitempty"`
+	Inode         uint64                 `protobuf:"varint,3,opt,name=inode,proto3" json:"inode,omitempty"`
+	Extents       int32                  `protobuf:"varint,4,opt,name=extents,proto3" json:"extents,omitempty"`
+	Bytes         uint64                 `protobuf:"varint,5,opt,name=bytes,proto3" json:"bytes,omitempty"`                                      // Length of the extents it references
+	SharedExtents int32                  `protobuf:"varint,6,opt,name=shared_extents,json=sharedExtents,proto3" json:"shared_extents,omitempty"` // Extents also referenced by other files or snapshots
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogicalFileUsage) Reset() {
+	*x = LogicalFileUsage{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogicalFileUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogicalFileUsage) ProtoMessage() {}
+
+func (x *LogicalFileUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogicalFileUsage.ProtoReflect.Descriptor instead.
+func (*LogicalFileUsage) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *LogicalFileUsage) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *LogicalFileUsage) GetRoot() uint64 {
+	if x != nil {
+		return x.Root
+	}
+	return 0
+}
+
+func (x *LogicalFileUsage) GetInode() uint64 {
+	if x != nil {
+		return x.Inode
+	}
+	return 0
+}
+
+func (x *LogicalFileUsage) GetExtents() int32 {
+	if x != nil {
+		return x.Extents
+	}
+	return 0
+}
+
+func (x *LogicalFileUsage) GetBytes() uint64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *LogicalFileUsage) GetSharedExtents() int32 {
+	if x != nil {
+		return x.SharedExtents
+	}
+	return 0
+}
+
+type ResolveLogicalRangeResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	LogicalStart    uint64                 `protobuf:"varint,1,opt,name=logical_start,json=logicalStart,proto3" json:"logical_start,omitempty"`
+	LogicalEnd      uint64                 `protobuf:"varint,2,opt,name=logical_end,json=logicalEnd,proto3" json:"logical_end,omitempty"`
+	Chunk           *Chunk                 `protobuf:"bytes,3,opt,name=chunk,proto3" json:"chunk,omitempty"` // Set when a chunk was looked up
+	DataExtents     int32                  `protobuf:"varint,4,opt,name=data_extents,json=dataExtents,proto3" json:"data_extents,omitempty"`
+	DataBytes       uint64                 `protobuf:"varint,5,opt,name=data_bytes,json=dataBytes,proto3" json:"data_bytes,omitempty"`
+	MetadataExtents int32                  `protobuf:"varint,6,opt,name=metadata_extents,json=metadataExtents,proto3" json:"metadata_extents,omitempty"`
+	MetadataBytes   uint64                 `protobuf:"varint,7,opt,name=metadata_bytes,json=metadataBytes,proto3" json:"metadata_bytes,omitempty"`
+	Extents         []*LogicalExtent       `protobuf:"bytes,8,rep,name=extents,proto3" json:"extents,omitempty"`       // Address order
+	Files           []*LogicalFileUsage    `protobuf:"bytes,9,rep,name=files,proto3" json:"files,omitempty"`           // Most bytes first
+	Truncated       bool                   `protobuf:"varint,10,opt,name=truncated,proto3" json:"truncated,omitempty"` // Only max_extents data extents were resolved
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ResolveLogicalRangeResponse) Reset() {
+	*x = ResolveLogicalRangeResponse{}
+	mi := &file_api_v1_fragmap_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveLogicalRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveLogicalRangeResponse) ProtoMessage() {}
+
+func (x *ResolveLogicalRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_fragmap_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveLogicalRangeResponse.ProtoReflect.Descriptor instead.
+func (*ResolveLogicalRangeResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_fragmap_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ResolveLogicalRangeResponse) GetLogicalStart() uint64 {
+	if x != nil {
+		return x.LogicalStart
+	}
+	return 0
+}
+
+func (x *ResolveLogicalRangeResponse) GetLogicalEnd() uint64 {
+	if x != nil {
+		return x.LogicalEnd
+	}
+	return 0
+}
+
+func (x *ResolveLogicalRangeResponse) GetChunk() *Chunk {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+func (x *ResolveLogicalRangeResponse) GetDataExtents() int32 {
+	if x != nil {
+		return x.DataExtents
+	}
+	return 0
+}
+
+func (x *ResolveLogicalRangeResponse) GetDataBytes() uint64 {
+	if x != nil {
+		return x.DataBytes
+	}
+	return 0
+}
+
+func (x *ResolveLogicalRangeResponse) GetMetadataExtents() int32 {
+	if x != nil {
+		return x.MetadataExtents
+	}
+	return 0
+}
+
+func (x *ResolveLogicalRangeResponse) GetMetadataBytes() uint64 {
+	if x != nil {
+		return x.MetadataBytes
+	}
+	return 0
+}
+
+func (x *ResolveLogicalRangeResponse) GetExtents() []*LogicalExtent {
+	if x != nil {
+		return x.Extents
+	}
+	return nil
+}
+
+func (x *ResolveLogicalRangeResponse) GetFiles() []*LogicalFileUsage {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *ResolveLogicalRangeResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+var File_api_v1_fragmap_proto protoreflect.FileDescriptor
+
+const file_api_v1_fragmap_proto_rawDesc = "" +
+	"\n" +
+	"\x14api/v1/fragmap.proto\x12\x06api.v1\",\n" +
+	"\x11GetFragMapRequest\x12\x17\n" +
+	"\afs_path\x18\x01 \x01(\tR\x06fsPath\"_\n" +
+	"\x06Device\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\fR\x04uuid\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x04R\ttotalSize\x12\x12\n" +
+	"\x04path\x18\x04 \x01(\tR\x04path\"=\n" +
+	"\x06Stripe\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\x04R\bdeviceId\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x04R\x06offset\"\xb2\x01\n" +
+	"\x05Chunk\x12%\n" +
+	"\x0elogical_offset\x18\x01 \x01(\x04R\rlogicalOffset\x12\x16\n" +
+	"\x06length\x18\x02 \x01(\x04R\x06length\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\x04R\x04type\x12\x18\n" +
+	"\aprofile\x18\x04 \x01(\x04R\aprofile\x12(\n" +
+	"\astripes\x18\x05 \x03(\v2\x0e.api.v1.StripeR\astripes\x12\x12\n" +
+	"\x04used\x18\x06 \x01(\x04R\x04used\"\x8f\x01\n" +
+	"\fDeviceExtent\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\x04R\bdeviceId\x12'\n" +
+	"\x0fphysical_offset\x18\x02 \x01(\x04R\x0ephysicalOffset\x12\x16\n" +
+	"\x06length\x18\x03 \x01(\x04R\x06length\x12!\n" +
+	"\fchunk_offset\x18\x04 \x01(\x04R\vchunkOffset\"\xc1\x01\n" +
+	"\x12GetFragMapResponse\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x01 \x01(\x04R\ttotalSize\x12(\n" +
+	"\adevices\x18\x02 \x03(\v2\x0e.api.v1.DeviceR\adevices\x12%\n" +
+	"\x06chunks\x18\x03 \x03(\v2\r.api.v1.ChunkR\x06chunks\x12;\n" +
+	"\x0edevice_extents\x18\x04 \x03(\v2\x14.api.v1.DeviceExtentR\rdeviceExtents\"P\n" +
+	"\x18GetDeviceBlockMapRequest\x12\x17\n" +
+	"\afs_path\x18\x01 \x01(\tR\x06fsPath\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\x04R\bdeviceId\"\xf0\x01\n" +
+	"\rBlockMapEntry\x12\x16\n" +
+	"\x06offset\x18\x01 \x01(\x04R\x06offset\x12\x16\n" +
+	"\x06length\x18\x02 \x01(\x04R\x06length\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\x04R\x04type\x12\x18\n" +
+	"\aprofile\x18\x04 \x01(\x04R\aprofile\x12\x1c\n" +
+	"\tallocated\x18\x05 \x01(\bR\tallocated\x12!\n" +
+	"\fchunk_offset\x18\x06 \x01(\x04R\vchunkOffset\x12\x1d\n" +
+	"\n" +
+	"chunk_used\x18\a \x01(\x04R\tchunkUsed\x12!\n" +
+	"\fchunk_length\x18\b \x01(\x04R\vchunkLength\"\x88\x01\n" +
+	"\x19GetDeviceBlockMapResponse\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\x04R\bdeviceId\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x02 \x01(\x04R\ttotalSize\x12/\n" +
+	"\aentries\x18\x03 \x03(\v2\x15.api.v1.BlockMapEntryR\aentries\"\xe2\x01\n" +
+	"\x11GetHeatMapRequest\x12\x17\n" +
+	"\afs_path\x18\x01 \x01(\tR\x06fsPath\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\x04R\bdeviceId\x12\x1e\n" +
+	"\n" +
+	"resolution\x18\x03 \x01(\x05R\n" +
+	"resolution\x12\x1d\n" +
+	"\n" +
+	"free_space\x18\x04 \x01(\bR\tfreeSpace\x12\x16\n" +
+	"\x06layout\x18\x05 \x01(\tR\x06layout\x12!\n" +
+	"\fstart_offset\x18\x06 \x01(\x04R\vstartOffset\x12\x1d\n" +
+	"\n" +
+	"end_offset\x18\a \x01(\x04R\tendOffset\"\xd5\x04\n" +
+	"\vHeatMapCell\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12!\n" +
+	"\fstart_offset\x18\x02 \x01(\x04R\vstartOffset\x12\x1d\n" +
+	"\n" +
+	"end_offset\x18\x03 \x01(\x04R\tendOffset\x12'\n" +
+	"\x0fallocated_bytes\x18\x04 \x01(\x04R\x0eallocatedBytes\x12\x1d\n" +
+	"\n" +
+	"free_bytes\x18\x05 \x01(\x04R\tfreeBytes\x12\x1d\n" +
+	"\n" +
+	"data_bytes\x18\x06 \x01(\x04R\tdataBytes\x12%\n" +
+	"\x0emetadata_bytes\x18\a \x01(\x04R\rmetadataBytes\x12!\n" +
+	"\fsystem_bytes\x18\b \x01(\x04R\vsystemBytes\x12!\n" +
+	"\fextent_count\x18\t \x01(\x05R\vextentCount\x12 \n" +
+	"\vutilization\x18\n" +
+	" \x01(\x01R\vutilization\x12(\n" +
+	"\x10chunk_free_bytes\x18\v \x01(\x04R\x0echunkFreeBytes\x121\n" +
+	"\x15free_space_frag_score\x18\f \x01(\x01R\x12freeSpaceFragScore\x12)\n" +
+	"\x10dominant_profile\x18\r \x01(\x04R\x0fdominantProfile\x12(\n" +
+	"\x10chunk_used_bytes\x18\x0e \x01(\x04R\x0echunkUsedBytes\x12*\n" +
+	"\x11block_group_usage\x18\x0f \x01(\x01R\x0fblockGroupUsage\x12\f\n" +
+	"\x01x\x18\x10 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x11 \x01(\x05R\x01y\"\x8f\x03\n" +
+	"\x12GetHeatMapResponse\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\x04R\bdeviceId\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x02 \x01(\x04R\ttotalSize\x12\x1e\n" +
+	"\n" +
+	"resolution\x18\x03 \x01(\x05R\n" +
+	"resolution\x12)\n" +
+	"\x05cells\x18\x04 \x03(\v2\x13.api.v1.HeatMapCellR\x05cells\x12*\n" +
+	"\x11free_space_source\x18\x05 \x01(\tR\x0ffreeSpaceSource\x12>\n" +
+	"\fblock_groups\x18\x06 \x03(\v2\x1b.api.v1.BlockGroupFreeSpaceR\vblockGroups\x12\x16\n" +
+	"\x06layout\x18\a \x01(\tR\x06layout\x12\x14\n" +
+	"\x05width\x18\b \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\t \x01(\x05R\x06height\x12!\n" +
+	"\fstart_offset\x18\n" +
+	" \x01(\x04R\vstartOffset\x12\x1d\n" +
+	"\n" +
+	"end_offset\x18\v \x01(\x04R\tendOffset\"K\n" +
+	"\x13GetFragStatsRequest\x12\x17\n" +
+	"\afs_path\x18\x01 \x01(\tR\x06fsPath\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\x04R\bdeviceId\"\xcd\x03\n" +
+	"\tFragStats\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\x04R\bdeviceId\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x02 \x01(\x04R\ttotalSize\x12%\n" +
+	"\x0eallocated_size\x18\x03 \x01(\x04R\rallocatedSize\x12\x1b\n" +
+	"\tfree_size\x18\x04 \x01(\x04R\bfreeSize\x12\x1b\n" +
+	"\tdata_size\x18\x05 \x01(\x04R\bdataSize\x12#\n" +
+	"\rmetadata_size\x18\x06 \x01(\x04R\fmetadataSize\x12\x1f\n" +
+	"\vsystem_size\x18\a \x01(\x04R\n" +
+	"systemSize\x12\x1f\n" +
+	"\vnum_extents\x18\b \x01(\x05R\n" +
+	"numExtents\x12(\n" +
+	"\x10num_free_regions\x18\t \x01(\x05R\x0enumFreeRegions\x12!\n" +
+	"\flargest_free\x18\n" +
+	" \x01(\x04R\vlargestFree\x12#\n" +
+	"\rsmallest_free\x18\v \x01(\x04R\fsmallestFree\x12&\n" +
+	"\x0favg_extent_size\x18\f \x01(\x04R\ravgExtentSize\x12\"\n" +
+	"\ravg_free_size\x18\r \x01(\x04R\vavgFreeSize\"?\n" +
+	"\x14GetFragStatsResponse\x12'\n" +
+	"\x05stats\x18\x01 \x03(\v2\x11.api.v1.FragStatsR\x05stats\"S\n" +
+	"\x19GetDeviceBlockMapsRequest\x12\x17\n" +
+	"\afs_path\x18\x01 \x01(\tR\x06fsPath\x12\x1d\n" +
+	"\n" +
+	"device_ids\x18\x02 \x03(\x04R\tdeviceIds\"\xb1\x01\n" +
+	"\x0eDeviceBlockMap\x12&\n" +
+	"\x06device\x18\x01 \x01(\v2\x0e.api.v1.DeviceR\x06device\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x02 \x01(\x04R\ttotalSize\x12/\n" +
+	"\aentries\x18\x03 \x03(\v2\x15.api.v1.BlockMapEntryR\aentries\x12'\n" +
+	"\x05stats\x18\x04 \x01(\v2\x11.api.v1.FragStatsR\x05stats\"H\n" +
+	"\x1aGetDeviceBlockMapsResponse\x12*\n" +
+	"\x04maps\x18\x01 \x03(\v2\x16.api.v1.DeviceBlockMapR\x04maps\"X\n" +
+	"\x0fFreeSpaceBucket\x12\x19\n" +
+	"\bmax_size\x18\x01 \x01(\x04R\amaxSize\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x04R\x05count\x12\x14\n" +
+	"\x05bytes\x18\x03 \x01(\x04R\x05bytes\"\xb7\x02\n" +
+	"\x13BlockGroupFreeSpace\x12%\n" +
+	"\x0elogical_offset\x18\x01 \x01(\x04R\rlogicalOffset\x12\x16\n" +
+	"\x06length\x18\x02 \x01(\x04R\x06length\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\x04R\x04type\x12\x18\n" +
+	"\aprofile\x18\x04 \x01(\x04R\aprofile\x12\x1d\n" +
+	"\n" +
+	"free_bytes\x18\x05 \x01(\x04R\tfreeBytes\x12\x1b\n" +
+	"\tfree_runs\x18\x06 \x01(\x05R\bfreeRuns\x12!\n" +
+	"\flargest_free\x18\a \x01(\x04R\vlargestFree\x125\n" +
+	"\thistogram\x18\b \x03(\v2\x17.api.v1.FreeSpaceBucketR\thistogram\x12\x1d\n" +
+	"\n" +
+	"frag_score\x18\t \x01(\x01R\tfragScore\"\x96\x02\n" +
+	"\x10FreeSpaceSummary\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\x04R\x04type\x12!\n" +
+	"\fblock_groups\x18\x02 \x01(\x05R\vblockGroups\x12\x16\n" +
+	"\x06length\x18\x03 \x01(\x04R\x06length\x12\x1d\n" +
+	"\n" +
+	"free_bytes\x18\x04 \x01(\x04R\tfreeBytes\x12\x1b\n" +
+	"\tfree_runs\x18\x05 \x01(\x05R\bfreeRuns\x12!\n" +
+	"\flargest_free\x18\x06 \x01(\x04R\vlargestFree\x125\n" +
+	"\thistogram\x18\a \x03(\v2\x17.api.v1.FreeSpaceBucketR\thistogram\x12\x1d\n" +
+	"\n" +
+	"frag_score\x18\b \x01(\x01R\tfragScore\"e\n" +
+	"\x18GetFreeSpaceStatsRequest\x12\x17\n" +
+	"\afs_path\x18\x01 \x01(\tR\x06fsPath\x120\n" +
+	"\x14include_block_groups\x18\x02 \x01(\bR\x12includeBlockGroups\"\xab\x01\n" +
+	"\x19GetFreeSpaceStatsResponse\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x126\n" +
+	"\tsummaries\x18\x02 \x03(\v2\x18.api.v1.FreeSpaceSummaryR\tsummaries\x12>\n" +
+	"\fblock_groups\x18\x03 \x03(\v2\x1b.api.v1.BlockGroupFreeSpaceR\vblockGroups\"X\n" +
+	"\x1aGetCompressionStatsRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12&\n" +
+	"\x0fone_file_system\x18\x02 \x01(\bR\roneFileSystem\"\xad\x01\n" +
+	"\x10CompressionUsage\x12 \n" +
+	"\vcompression\x18\x01 \x01(\tR\vcompression\x12\x1d\n" +
+	"\n" +
+	"disk_bytes\x18\x02 \x01(\x04R\tdiskBytes\x12-\n" +
+	"\x12uncompressed_bytes\x18\x03 \x01(\x04R\x11uncompressedBytes\x12)\n" +
+	"\x10referenced_bytes\x18\x04 \x01(\x04R\x0freferencedBytes\"\xd3\x02\n" +
+	"\x1bGetCompressionStatsResponse\x12\x14\n" +
+	"\x05files\x18\x01 \x01(\x05R\x05files\x12\x16\n" +
+	"\x06errors\x18\x02 \x01(\x05R\x06errors\x12\x1d\n" +
+	"\n" +
+	"disk_bytes\x18\x03 \x01(\x04R\tdiskBytes\x12-\n" +
+	"\x12uncompressed_bytes\x18\x04 \x01(\x04R\x11uncompressedBytes\x12)\n" +
+	"\x10referenced_bytes\x18\x05 \x01(\x04R\x0freferencedBytes\x12+\n" +
+	"\x11compression_ratio\x18\x06 \x01(\x01R\x10compressionRatio\x12?\n" +
+	"\x0eby_compression\x18\a \x03(\v2\x18.api.v1.CompressionUsageR\rbyCompression\x12\x1f\n" +
+	"\vduration_ms\x18\b \x01(\x03R\n" +
+	"durationMs\"\xcf\x01\n" +
+	"\x10ScanFilesRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x18\n" +
+	"\aworkers\x18\x02 \x01(\x05R\aworkers\x12\x1b\n" +
+	"\tmax_depth\x18\x03 \x01(\x05R\bmaxDepth\x12\x18\n" +
+	"\aexclude\x18\x04 \x03(\tR\aexclude\x12&\n" +
+	"\x0fone_file_system\x18\x05 \x01(\bR\roneFileSystem\x12\x10\n" +
+	"\x03top\x18\x06 \x01(\x05R\x03top\x12\x1c\n" +
+	"\tresumable\x18\a \x01(\bR\tresumable\"\xd1\x01\n" +
+	"\x11ScanFilesProgress\x12#\n" +
+	"\rfiles_scanned\x18\x01 \x01(\x05R\ffilesScanned\x12#\n" +
+	"\rbytes_scanned\x18\x02 \x01(\x03R\fbytesScanned\x12\x16\n" +
+	"\x06errors\x18\x03 \x01(\x05R\x06errors\x12!\n" +
+	"\fcurrent_path\x18\x04 \x01(\tR\vcurrentPath\x12\x1d\n" +
+	"\n" +
+	"elapsed_ms\x18\x05 \x01(\x03R\telapsedMs\x12\x18\n" +
+	"\aresumed\x18\x06 \x01(\bR\aresumed\"\xbf\x02\n" +
+	"\x0fFileFragSummary\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12!\n" +
+	"\fextent_count\x18\x03 \x01(\x05R\vextentCount\x12#\n" +
+	"\rideal_extents\x18\x04 \x01(\x05R\fidealExtents\x12\x10\n" +
+	"\x03dof\x18\x05 \x01(\x01R\x03dof\x12+\n" +
+	"\x11fragmentation_pct\x18\x06 \x01(\x01R\x10fragmentationPct\x12'\n" +
+	"\x10out_of_order_pct\x18\a \x01(\x01R\routOfOrderPct\x12-\n" +
+	"\x12compressed_extents\x18\b \x01(\x05R\x11compressedExtents\x12%\n" +
+	"\x0eshared_extents\x18\t \x01(\x05R\rsharedExtents\"7\n" +
+	"\tDoFBucket\x12\x14\n" +
+	"\x05range\x18\x01 \x01(\tR\x05range\x12\x14\n" +
+	"\x05files\x18\x02 \x01(\x05R\x05files\"\xa1\x05\n" +
+	"\rFileFragStats\x12\x1f\n" +
+	"\vtotal_files\x18\x01 \x01(\x05R\n" +
+	"totalFiles\x12#\n" +
+	"\rtotal_extents\x18\x02 \x01(\x05R\ftotalExtents\x12\x1f\n" +
+	"\vtotal_bytes\x18\x03 \x01(\x03R\n" +
+	"totalBytes\x12)\n" +
+	"\x10fragmented_files\x18\x04 \x01(\x05R\x0ffragmentedFiles\x12\x17\n" +
+	"\aavg_dof\x18\x05 \x01(\x01R\x06avgDof\x12 \n" +
+	"\favg_frag_pct\x18\x06 \x01(\x01R\n" +
+	"avgFragPct\x12.\n" +
+	"\x14avg_out_of_order_pct\x18\a \x01(\x01R\x10avgOutOfOrderPct\x12\x17\n" +
+	"\amax_dof\x18\b \x01(\x01R\x06maxDof\x12\x1f\n" +
+	"\vmax_extents\x18\t \x01(\x05R\n" +
+	"maxExtents\x12)\n" +
+	"\x10compressed_files\x18\n" +
+	" \x01(\x05R\x0fcompressedFiles\x12-\n" +
+	"\x12compressed_extents\x18\v \x01(\x05R\x11compressedExtents\x12%\n" +
+	"\x0einline_extents\x18\f \x01(\x05R\rinlineExtents\x12%\n" +
+	"\x0eshared_extents\x18\r \x01(\x05R\rsharedExtents\x12+\n" +
+	"\x11unwritten_extents\x18\x0e \x01(\x05R\x10unwrittenExtents\x12)\n" +
+	"\x10compressed_bytes\x18\x0f \x01(\x03R\x0fcompressedBytes\x12!\n" +
+	"\fshared_bytes\x18\x10 \x01(\x03R\vsharedBytes\x126\n" +
+	"\rdof_histogram\x18\x11 \x03(\v2\x11.api.v1.DoFBucketR\fdofHistogram\"9\n" +
+	"\rScanFileError\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\xf6\x01\n" +
+	"\x0fScanFilesResult\x12+\n" +
+	"\x05stats\x18\x01 \x01(\v2\x15.api.v1.FileFragStatsR\x05stats\x12)\n" +
+	"\x03top\x18\x02 \x03(\v2\x17.api.v1.FileFragSummaryR\x03top\x12\x16\n" +
+	"\x06errors\x18\x03 \x01(\x05R\x06errors\x12:\n" +
+	"\rerror_samples\x18\x04 \x03(\v2\x15.api.v1.ScanFileErrorR\ferrorSamples\x12\x18\n" +
+	"\aresumed\x18\x05 \x01(\bR\aresumed\x12\x1d\n" +
+	"\n" +
+	"elapsed_ms\x18\x06 \x01(\x03R\telapsedMs\"y\n" +
+	"\x0fScanFilesUpdate\x125\n" +
+	"\bprogress\x18\x01 \x01(\v2\x19.api.v1.ScanFilesProgressR\bprogress\x12/\n" +
+	"\x06result\x18\x02 \x01(\v2\x17.api.v1.ScanFilesResultR\x06result\"H\n" +
+	"\x17ListFragMapScansRequest\x12\x17\n" +
+	"\afs_path\x18\x01 \x01(\tR\x06fsPath\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"\xb9\x01\n" +
+	"\x0fFragMapScanInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\afs_uuid\x18\x02 \x01(\tR\x06fsUuid\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x03 \x01(\x04R\n" +
+	"generation\x12\x1d\n" +
+	"\n" +
+	"scanned_at\x18\x04 \x01(\x03R\tscannedAt\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x05 \x01(\x04R\ttotalSize\x12\x1f\n" +
+	"\vchunk_count\x18\x06 \x01(\x05R\n" +
+	"chunkCount\"I\n" +
+	"\x18ListFragMapScansResponse\x12-\n" +
+	"\x05scans\x18\x01 \x03(\v2\x17.api.v1.FragMapScanInfoR\x05scans\"n\n" +
+	"\x13DiffFragMapsRequest\x12\x17\n" +
+	"\afs_path\x18\x01 \x01(\tR\x06fsPath\x12 \n" +
+	"\ffrom_scan_id\x18\x02 \x01(\x03R\n" +
+	"fromScanId\x12\x1c\n" +
+	"\n" +
+	"to_scan_id\x18\x03 \x01(\x03R\btoScanId\"\xa6\x01\n" +
+	"\vChunkChange\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12#\n" +
+	"\x05chunk\x18\x02 \x01(\v2\r.api.v1.ChunkR\x05chunk\x129\n" +
+	"\x10previous_stripes\x18\x03 \x03(\v2\x0e.api.v1.StripeR\x0fpreviousStripes\x12#\n" +
+	"\rprevious_used\x18\x04 \x01(\x04R\fpreviousUsed\"\xf1\x02\n" +
+	"\bTypeDiff\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\x04R\x04type\x12)\n" +
+	"\x10allocated_chunks\x18\x02 \x01(\x05R\x0fallocatedChunks\x12'\n" +
+	"\x0fallocated_bytes\x18\x03 \x01(\x04R\x0eallocatedBytes\x12!\n" +
+	"\ffreed_chunks\x18\x04 \x01(\x05R\vfreedChunks\x12\x1f\n" +
+	"\vfreed_bytes\x18\x05 \x01(\x04R\n" +
+	"freedBytes\x12)\n" +
+	"\x10relocated_chunks\x18\x06 \x01(\x05R\x0frelocatedChunks\x12'\n" +
+	"\x0frelocated_bytes\x18\a \x01(\x04R\x0erelocatedBytes\x12%\n" +
+	"\x0eresized_chunks\x18\b \x01(\x05R\rresizedChunks\x12\x1f\n" +
+	"\vused_before\x18\t \x01(\x04R\n" +
+	"usedBefore\x12\x1d\n" +
+	"\n" +
+	"used_after\x18\n" +
+	" \x01(\x04R\tusedAfter\"\xc6\x01\n" +
+	"\x14DiffFragMapsResponse\x12+\n" +
+	"\x04from\x18\x01 \x01(\v2\x17.api.v1.FragMapScanInfoR\x04from\x12'\n" +
+	"\x02to\x18\x02 \x01(\v2\x17.api.v1.FragMapScanInfoR\x02to\x12-\n" +
+	"\achanges\x18\x03 \x03(\v2\x13.api.v1.ChunkChangeR\achanges\x12)\n" +
+	"\aby_type\x18\x04 \x03(\v2\x10.api.v1.TypeDiffR\x06byType\"\xde\x01\n" +
+	"\x1aResolveLogicalRangeRequest\x12\x17\n" +
+	"\afs_path\x18\x01 \x01(\tR\x06fsPath\x12#\n" +
+	"\rlogical_start\x18\x02 \x01(\x04R\flogicalStart\x12\x1f\n" +
+	"\vlogical_end\x18\x03 \x01(\x04R\n" +
+	"logicalEnd\x12\x1b\n" +
+	"\tdevice_id\x18\x04 \x01(\x04R\bdeviceId\x12#\n" +
+	"\rdevice_offset\x18\x05 \x01(\x04R\fdeviceOffset\x12\x1f\n" +
+	"\vmax_extents\x18\x06 \x01(\x05R\n" +
+	"maxExtents\"c\n" +
+	"\vExtentOwner\x12\x12\n" +
+	"\x04root\x18\x01 \x01(\x04R\x04root\x12\x14\n" +
+	"\x05inode\x18\x02 \x01(\x04R\x05inode\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x04R\x06offset\x12\x12\n" +
+	"\x04path\x18\x04 \x01(\tR\x04path\"\xad\x01\n" +
+	"\rLogicalExtent\x12\x18\n" +
+	"\alogical\x18\x01 \x01(\x04R\alogical\x12\x16\n" +
+	"\x06length\x18\x02 \x01(\x04R\x06length\x12\x12\n" +
+	"\x04refs\x18\x03 \x01(\x04R\x04refs\x12+\n" +
+	"\x06owners\x18\x04 \x03(\v2\x13.api.v1.ExtentOwnerR\x06owners\x12)\n" +
+	"\x10owners_truncated\x18\x05 \x01(\bR\x0fownersTruncated\"\xa7\x01\n" +
+	"\x10LogicalFileUsage\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04root\x18\x02 \x01(\x04R\x04root\x12\x14\n" +
+	"\x05inode\x18\x03 \x01(\x04R\x05inode\x12\x18\n" +
+	"\aextents\x18\x04 \x01(\x05R\aextents\x12\x14\n" +
+	"\x05bytes\x18\x05 \x01(\x04R\x05bytes\x12%\n" +
+	"\x0eshared_extents\x18\x06 \x01(\x05R\rsharedExtents\"\x9b\x03\n" +
+	"\x1bResolveLogicalRangeResponse\x12#\n" +
+	"\rlogical_start\x18\x01 \x01(\x04R\flogicalStart\x12\x1f\n" +
+	"\vlogical_end\x18\x02 \x01(\x04R\n" +
+	"logicalEnd\x12#\n" +
+	"\x05chunk\x18\x03 \x01(\v2\r.api.v1.ChunkR\x05chunk\x12!\n" +
+	"\fdata_extents\x18\x04 \x01(\x05R\vdataExtents\x12\x1d\n" +
+	"\n" +
+	"data_bytes\x18\x05 \x01(\x04R\tdataBytes\x12)\n" +
+	"\x10metadata_extents\x18\x06 \x01(\x05R\x0fmetadataExtents\x12%\n" +
+	"\x0emetadata_bytes\x18\a \x01(\x04R\rmetadataBytes\x12/\n" +
+	"\aextents\x18\b \x03(\v2\x15.api.v1.LogicalExtentR\aextents\x12.\n" +
+	"\x05files\x18\t \x03(\v2\x18.api.v1.LogicalFileUsageR\x05files\x12\x1c\n" +
+	"\ttruncated\x18\n" +
+	" \x01(\bR\ttruncated2\xb0\a\n" +
 	"\x0eFragMapService\x12E\n" +
 	"\n" +
 	"GetFragMap\x12\x19.api.v1.GetFragMapRequest\x1a\x1a.api.v1.GetFragMapResponse\"\x00\x12Z\n" +
@@ -1386,7 +3990,13 @@ const file_api_v1_fragmap_proto_rawDesc = "" +
 	"\x12GetDeviceBlockMaps\x12!.api.v1.GetDeviceBlockMapsRequest\x1a\".api.v1.GetDeviceBlockMapsResponse\"\x00\x12E\n" +
 	"\n" +
 	"GetHeatMap\x12\x19.api.v1.GetHeatMapRequest\x1a\x1a.api.v1.GetHeatMapResponse\"\x00\x12K\n" +
-	"\fGetFragStats\x12\x1b.api.v1.GetFragStatsRequest\x1a\x1c.api.v1.GetFragStatsResponse\"\x00B\x7f\n" +
+	"\fGetFragStats\x12\x1b.api.v1.GetFragStatsRequest\x1a\x1c.api.v1.GetFragStatsResponse\"\x00\x12Z\n" +
+	"\x11GetFreeSpaceStats\x12 .api.v1.GetFreeSpaceStatsRequest\x1a!.api.v1.GetFreeSpaceStatsResponse\"\x00\x12`\n" +
+	"\x13GetCompressionStats\x12\".api.v1.GetCompressionStatsRequest\x1a#.api.v1.GetCompressionStatsResponse\"\x00\x12B\n" +
+	"\tScanFiles\x12\x18.api.v1.ScanFilesRequest\x1a\x17.api.v1.ScanFilesUpdate\"\x000\x01\x12W\n" +
+	"\x10ListFragMapScans\x12\x1f.api.v1.ListFragMapScansRequest\x1a .api.v1.ListFragMapScansResponse\"\x00\x12K\n" +
+	"\fDiffFragMaps\x12\x1b.api.v1.DiffFragMapsRequest\x1a\x1c.api.v1.DiffFragMapsResponse\"\x00\x12`\n" +
+	"\x13ResolveLogicalRange\x12\".api.v1.ResolveLogicalRangeRequest\x1a#.api.v1.ResolveLogicalRangeResponse\"\x00B\x7f\n" +
 	"\n" +
 	"com.api.v1B\fFragmapProtoP\x01Z*github.com/elee1766/gobtr/gen/api/v1;apiv1\xa2\x02\x03AXX\xaa\x02\x06Api.V1\xca\x02\x06Api\\V1\xe2\x02\x12Api\\V1\\GPBMetadata\xea\x02\aApi::V1b\x06proto3"
 
@@ -1402,26 +4012,54 @@ func file_api_v1_fragmap_proto_rawDescGZIP() []byte {
 	return file_api_v1_fragmap_proto_rawDescData
 }
 
-var file_api_v1_fragmap_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_api_v1_fragmap_proto_msgTypes = make([]protoimpl.MessageInfo, 46)
 var file_api_v1_fragmap_proto_goTypes = []any{
-	(*GetFragMapRequest)(nil),          // 0: api.v1.GetFragMapRequest
-	(*Device)(nil),                     // 1: api.v1.Device
-	(*Stripe)(nil),                     // 2: api.v1.Stripe
-	(*Chunk)(nil),                      // 3: api.v1.Chunk
-	(*DeviceExtent)(nil),               // 4: api.v1.DeviceExtent
-	(*GetFragMapResponse)(nil),         // 5: api.v1.GetFragMapResponse
-	(*GetDeviceBlockMapRequest)(nil),   // 6: api.v1.GetDeviceBlockMapRequest
-	(*BlockMapEntry)(nil),              // 7: api.v1.BlockMapEntry
-	(*GetDeviceBlockMapResponse)(nil),  // 8: api.v1.GetDeviceBlockMapResponse
-	(*GetHeatMapRequest)(nil),          // 9: api.v1.GetHeatMapRequest
-	(*HeatMapCell)(nil),                // 10: api.v1.HeatMapCell
-	(*GetHeatMapResponse)(nil),         // 11: api.v1.GetHeatMapResponse
-	(*GetFragStatsRequest)(nil),        // 12: api.v1.GetFragStatsRequest
-	(*FragStats)(nil),                  // 13: api.v1.FragStats
-	(*GetFragStatsResponse)(nil),       // 14: api.v1.GetFragStatsResponse
-	(*GetDeviceBlockMapsRequest)(nil),  // 15: api.v1.GetDeviceBlockMapsRequest
-	(*DeviceBlockMap)(nil),             // 16: api.v1.DeviceBlockMap
-	(*GetDeviceBlockMapsResponse)(nil), // 17: api.v1.GetDeviceBlockMapsResponse
+	(*GetFragMapRequest)(nil),           // 0: api.v1.GetFragMapRequest
+	(*Device)(nil),                      // 1: api.v1.Device
+	(*Stripe)(nil),                      // 2: api.v1.Stripe
+	(*Chunk)(nil),                       // 3: api.v1.Chunk
+	(*DeviceExtent)(nil),                // 4: api.v1.DeviceExtent
+	(*GetFragMapResponse)(nil),          // 5: api.v1.GetFragMapResponse
+	(*GetDeviceBlockMapRequest)(nil),    // 6: api.v1.GetDeviceBlockMapRequest
+	(*BlockMapEntry)(nil),               // 7: api.v1.BlockMapEntry
+	(*GetDeviceBlockMapResponse)(nil),   // 8: api.v1.GetDeviceBlockMapResponse
+	(*GetHeatMapRequest)(nil),           // 9: api.v1.GetHeatMapRequest
+	(*HeatMapCell)(nil),                 // 10: api.v1.HeatMapCell
+	(*GetHeatMapResponse)(nil),          // 11: api.v1.GetHeatMapResponse
+	(*GetFragStatsRequest)(nil),         // 12: api.v1.GetFragStatsRequest
+	(*FragStats)(nil),                   // 13: api.v1.FragStats
+	(*GetFragStatsResponse)(nil),        // 14: api.v1.GetFragStatsResponse
+	(*GetDeviceBlockMapsRequest)(nil),   // 15: api.v1.GetDeviceBlockMapsRequest
+	(*DeviceBlockMap)(nil),              // 16: api.v1.DeviceBlockMap
+	(*GetDeviceBlockMapsResponse)(nil),  // 17: api.v1.GetDeviceBlockMapsResponse
+	(*FreeSpaceBucket)(nil),             // 18: api.v1.FreeSpaceBucket
+	(*BlockGroupFreeSpace)(nil),         // 19: api.v1.BlockGroupFreeSpace
+	(*FreeSpaceSummary)(nil),            // 20: api.v1.FreeSpaceSummary
+	(*GetFreeSpaceStatsRequest)(nil),    // 21: api.v1.GetFreeSpaceStatsRequest
+	(*GetFreeSpaceStatsResponse)(nil),   // 22: api.v1.GetFreeSpaceStatsResponse
+	(*GetCompressionStatsRequest)(nil),  // 23: api.v1.GetCompressionStatsRequest
+	(*CompressionUsage)(nil),            // 24: api.v1.CompressionUsage
+	(*GetCompressionStatsResponse)(nil), // 25: api.v1.GetCompressionStatsResponse
+	(*ScanFilesRequest)(nil),            // 26: api.v1.ScanFilesRequest
+	(*ScanFilesProgress)(nil),           // 27: api.v1.ScanFilesProgress
+	(*FileFragSummary)(nil),             // 28: api.v1.FileFragSummary
+	(*DoFBucket)(nil),                   // 29: api.v1.DoFBucket
+	(*FileFragStats)(nil),               // 30: api.v1.FileFragStats
+	(*ScanFileError)(nil),               // 31: api.v1.ScanFileError
+	(*ScanFilesResult)(nil),             // 32: api.v1.ScanFilesResult
+	(*ScanFilesUpdate)(nil),             // 33: api.v1.ScanFilesUpdate
+	(*ListFragMapScansRequest)(nil),     // 34: api.v1.ListFragMapScansRequest
+	(*FragMapScanInfo)(nil),             // 35: api.v1.FragMapScanInfo
+	(*ListFragMapScansResponse)(nil),    // 36: api.v1.ListFragMapScansResponse
+	(*DiffFragMapsRequest)(nil),         // 37: api.v1.DiffFragMapsRequest
+	(*ChunkChange)(nil),                 // 38: api.v1.ChunkChange
+	(*TypeDiff)(nil),                    // 39: api.v1.TypeDiff
+	(*DiffFragMapsResponse)(nil),        // 40: api.v1.DiffFragMapsResponse
+	(*ResolveLogicalRangeRequest)(nil),  // 41: api.v1.ResolveLogicalRangeRequest
+	(*ExtentOwner)(nil),                 // 42: api.v1.ExtentOwner
+	(*LogicalExtent)(nil),               // 43: api.v1.LogicalExtent
+	(*LogicalFileUsage)(nil),            // 44: api.v1.LogicalFileUsage
+	(*ResolveLogicalRangeResponse)(nil), // 45: api.v1.ResolveLogicalRangeResponse
 }
 var file_api_v1_fragmap_proto_depIdxs = []int32{
 	2,  // 0: api.v1.Chunk.stripes:type_name -> api.v1.Stripe
@@ -1430,26 +4068,61 @@ var file_api_v1_fragmap_proto_depIdxs = []int32{
 	4,  // 3: api.v1.GetFragMapResponse.device_extents:type_name -> api.v1.DeviceExtent
 	7,  // 4: api.v1.GetDeviceBlockMapResponse.entries:type_name -> api.v1.BlockMapEntry
 	10, // 5: api.v1.GetHeatMapResponse.cells:type_name -> api.v1.HeatMapCell
-	13, // 6: api.v1.GetFragStatsResponse.stats:type_name -> api.v1.FragStats
-	1,  // 7: api.v1.DeviceBlockMap.device:type_name -> api.v1.Device
-	7,  // 8: api.v1.DeviceBlockMap.entries:type_name -> api.v1.BlockMapEntry
-	13, // 9: api.v1.DeviceBlockMap.stats:type_name -> api.v1.FragStats
-	16, // 10: api.v1.GetDeviceBlockMapsResponse.maps:type_name -> api.v1.DeviceBlockMap
-	0,  // 11: api.v1.FragMapService.GetFragMap:input_type -> api.v1.GetFragMapRequest
-	6,  // 12: api.v1.FragMapService.GetDeviceBlockMap:input_type -> api.v1.GetDeviceBlockMapRequest
-	15, // 13: api.v1.FragMapService.GetDeviceBlockMaps:input_type -> api.v1.GetDeviceBlockMapsRequest
-	9,  // 14: api.v1.FragMapService.GetHeatMap:input_type -> api.v1.GetHeatMapRequest
-	12, // 15: api.v1.FragMapService.GetFragStats:input_type -> api.v1.GetFragStatsRequest
-	5,  // 16: api.v1.FragMapService.GetFragMap:output_type -> api.v1.GetFragMapResponse
-	8,  // 17: api.v1.FragMapService.GetDeviceBlockMap:output_type -> api.v1.GetDeviceBlockMapResponse
-	17, // 18: api.v1.FragMapService.GetDeviceBlockMaps:output_type -> api.v1.GetDeviceBlockMapsResponse
-	11, // 19: api.v1.FragMapService.GetHeatMap:output_type -> api.v1.GetHeatMapResponse
-	14, // 20: api.v1.FragMapService.GetFragStats:output_type -> api.v1.GetFragStatsResponse
-	16, // [16:21] is the sub-list for method output_type
-	11, // [11:16] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	19, // 6: api.v1.GetHeatMapResponse.block_groups:type_name -> api.v1.BlockGroupFreeSpace
+	13, // 7: api.v1.GetFragStatsResponse.stats:type_name -> api.v1.FragStats
+	1,  // 8: api.v1.DeviceBlockMap.device:type_name -> api.v1.Device
+	7,  // 9: api.v1.DeviceBlockMap.entries:type_name -> api.v1.BlockMapEntry
+	13, // 10: api.v1.DeviceBlockMap.stats:type_name -> api.v1.FragStats
+	16, // 11: api.v1.GetDeviceBlockMapsResponse.maps:type_name -> api.v1.DeviceBlockMap
+	18, // 12: api.v1.BlockGroupFreeSpace.histogram:type_name -> api.v1.FreeSpaceBucket
+	18, // 13: api.v1.FreeSpaceSummary.histogram:type_name -> api.v1.FreeSpaceBucket
+	20, // 14: api.v1.GetFreeSpaceStatsResponse.summaries:type_name -> api.v1.FreeSpaceSummary
+	19, // 15: api.v1.GetFreeSpaceStatsResponse.block_groups:type_name -> api.v1.BlockGroupFreeSpace
+	24, // 16: api.v1.GetCompressionStatsResponse.by_compression:type_name -> api.v1.CompressionUsage
+	29, // 17: api.v1.FileFragStats.dof_histogram:type_name -> api.v1.DoFBucket
+	30, // 18: api.v1.ScanFilesResult.stats:type_name -> api.v1.FileFragStats
+	28, // 19: api.v1.ScanFilesResult.top:type_name -> api.v1.FileFragSummary
+	31, // 20: api.v1.ScanFilesResult.error_samples:type_name -> api.v1.ScanFileError
+	27, // 21: api.v1.ScanFilesUpdate.progress:type_name -> api.v1.ScanFilesProgress
+	32, // 22: api.v1.ScanFilesUpdate.result:type_name -> api.v1.ScanFilesResult
+	35, // 23: api.v1.ListFragMapScansResponse.scans:type_name -> api.v1.FragMapScanInfo
+	3,  // 24: api.v1.ChunkChange.chunk:type_name -> api.v1.Chunk
+	2,  // 25: api.v1.ChunkChange.previous_stripes:type_name -> api.v1.Stripe
+	35, // 26: api.v1.DiffFragMapsResponse.from:type_name -> api.v1.FragMapScanInfo
+	35, // 27: api.v1.DiffFragMapsResponse.to:type_name -> api.v1.FragMapScanInfo
+	38, // 28: api.v1.DiffFragMapsResponse.changes:type_name -> api.v1.ChunkChange
+	39, // 29: api.v1.DiffFragMapsResponse.by_type:type_name -> api.v1.TypeDiff
+	42, // 30: api.v1.LogicalExtent.owners:type_name -> api.v1.ExtentOwner
+	3,  // 31: api.v1.ResolveLogicalRangeResponse.chunk:type_name -> api.v1.Chunk
+	43, // 32: api.v1.ResolveLogicalRangeResponse.extents:type_name -> api.v1.LogicalExtent
+	44, // 33: api.v1.ResolveLogicalRangeResponse.files:type_name -> api.v1.LogicalFileUsage
+	0,  // 34: api.v1.FragMapService.GetFragMap:input_type -> api.v1.GetFragMapRequest
+	6,  // 35: api.v1.FragMapService.GetDeviceBlockMap:input_type -> api.v1.GetDeviceBlockMapRequest
+	15, // 36: api.v1.FragMapService.GetDeviceBlockMaps:input_type -> api.v1.GetDeviceBlockMapsRequest
+	9,  // 37: api.v1.FragMapService.GetHeatMap:input_type -> api.v1.GetHeatMapRequest
+	12, // 38: api.v1.FragMapService.GetFragStats:input_type -> api.v1.GetFragStatsRequest
+	21, // 39: api.v1.FragMapService.GetFreeSpaceStats:input_type -> api.v1.GetFreeSpaceStatsRequest
+	23, // 40: api.v1.FragMapService.GetCompressionStats:input_type -> api.v1.GetCompressionStatsRequest
+	26, // 41: api.v1.FragMapService.ScanFiles:input_type -> api.v1.ScanFilesRequest
+	34, // 42: api.v1.FragMapService.ListFragMapScans:input_type -> api.v1.ListFragMapScansRequest
+	37, // 43: api.v1.FragMapService.DiffFragMaps:input_type -> api.v1.DiffFragMapsRequest
+	41, // 44: api.v1.FragMapService.ResolveLogicalRange:input_type -> api.v1.ResolveLogicalRangeRequest
+	5,  // 45: api.v1.FragMapService.GetFragMap:output_type -> api.v1.GetFragMapResponse
+	8,  // 46: api.v1.FragMapService.GetDeviceBlockMap:output_type -> api.v1.GetDeviceBlockMapResponse
+	17, // 47: api.v1.FragMapService.GetDeviceBlockMaps:output_type -> api.v1.GetDeviceBlockMapsResponse
+	11, // 48: api.v1.FragMapService.GetHeatMap:output_type -> api.v1.GetHeatMapResponse
+	14, // 49: api.v1.FragMapService.GetFragStats:output_type -> api.v1.GetFragStatsResponse
+	22, // 50: api.v1.FragMapService.GetFreeSpaceStats:output_type -> api.v1.GetFreeSpaceStatsResponse
+	25, // 51: api.v1.FragMapService.GetCompressionStats:output_type -> api.v1.GetCompressionStatsResponse
+	33, // 52: api.v1.FragMapService.ScanFiles:output_type -> api.v1.ScanFilesUpdate
+	36, // 53: api.v1.FragMapService.ListFragMapScans:output_type -> api.v1.ListFragMapScansResponse
+	40, // 54: api.v1.FragMapService.DiffFragMaps:output_type -> api.v1.DiffFragMapsResponse
+	45, // 55: api.v1.FragMapService.ResolveLogicalRange:output_type -> api.v1.ResolveLogicalRangeResponse
+	45, // [45:56] is the sub-list for method output_type
+	34, // [34:45] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
 }
 
 func init() { file_api_v1_fragmap_proto_init() }
@@ -1463,7 +4136,7 @@ func file_api_v1_fragmap_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_fragmap_proto_rawDesc), len(file_api_v1_fragmap_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   18,
+			NumMessages:   46,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -115,9 +115,11 @@ func (x *HealthCheckRequest) GetService() string {
 }
 
 type HealthCheckResponse struct {
-	state         protoimpl.MessageState     `protogen:"open.v1"`
-	Status        HealthCheckResponse_Status `protobuf:"varint,1,opt,name=status,proto3,enum=api.v1.HealthCheckResponse_Status" json:"status,omitempty"`
-	Message       string                     `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	state   protoimpl.MessageState     `protogen:"open.v1"`
+	Status  HealthCheckResponse_Status `protobuf:"varint,1,opt,name=status,proto3,enum=api.v1.HealthCheckResponse_Status" json:"status,omitempty"`
+	Message string                     `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// The server refuses every mutating RPC (--read-only)
+	ReadOnly      bool `protobuf:"varint,3,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -166,16 +168,24 @@ func (x *HealthCheckResponse) GetMessage() string {
 	return ""
 }
 
+func (x *HealthCheckResponse) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
 var File_api_v1_health_proto protoreflect.FileDescriptor
 
 const file_api_v1_health_proto_rawDesc = "" +
 	"\n" +
 	"\x13api/v1/health.proto\x12\x06api.v1\".\n" +
 	"\x12HealthCheckRequest\x12\x18\n" +
-	"\aservice\x18\x01 \x01(\tR\aservice\"\xa0\x01\n" +
+	"\aservice\x18\x01 \x01(\tR\aservice\"\xbd\x01\n" +
 	"\x13HealthCheckResponse\x12:\n" +
 	"\x06status\x18\x01 \x01(\x0e2\".api.v1.HealthCheckResponse.StatusR\x06status\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"3\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1b\n" +
+	"\tread_only\x18\x03 \x01(\bR\breadOnly\"3\n" +
 	"\x06Status\x12\v\n" +
 	"\aUNKNOWN\x10\x00\x12\v\n" +
 	"\aSERVING\x10\x01\x12\x0f\n" +
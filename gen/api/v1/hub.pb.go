@@ -0,0 +1,1091 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: api/v1/hub.proto
+
+package apiv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Agent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Healthy       bool                   `protobuf:"varint,3,opt,name=healthy,proto3" json:"healthy,omitempty"`                   // The last check or call worked
+	LastSeen      int64                  `protobuf:"varint,4,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"` // Unix seconds of the last success (0 = never)
+	LastChecked   int64                  `protobuf:"varint,5,opt,name=last_checked,json=lastChecked,proto3" json:"last_checked,omitempty"`
+	LastError     string                 `protobuf:"bytes,6,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	LatencyMs     int64                  `protobuf:"varint,7,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"` // Of the last health check
+	ReadOnly      bool                   `protobuf:"varint,8,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`    // The agent refuses mutating RPCs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Agent) Reset() {
+	*x = Agent{}
+	mi := &file_api_v1_hub_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Agent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Agent) ProtoMessage() {}
+
+func (x *Agent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Agent.ProtoReflect.Descriptor instead.
+func (*Agent) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Agent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Agent) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Agent) GetHealthy() bool {
+	if x != nil {
+		return x.Healthy
+	}
+	return false
+}
+
+func (x *Agent) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
+func (x *Agent) GetLastChecked() int64 {
+	if x != nil {
+		return x.LastChecked
+	}
+	return 0
+}
+
+func (x *Agent) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *Agent) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *Agent) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+// Which host an entry is from. This server is local, with its hostname.
+type Host struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Local         bool                   `protobuf:"varint,2,opt,name=local,proto3" json:"local,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"` // If the host couldn't be reached
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Host) Reset() {
+	*x = Host{}
+	mi := &file_api_v1_hub_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Host) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Host) ProtoMessage() {}
+
+func (x *Host) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Host.ProtoReflect.Descriptor instead.
+func (*Host) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Host) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Host) GetLocal() bool {
+	if x != nil {
+		return x.Local
+	}
+	return false
+}
+
+func (x *Host) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type ListAgentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentsRequest) Reset() {
+	*x = ListAgentsRequest{}
+	mi := &file_api_v1_hub_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentsRequest) ProtoMessage() {}
+
+func (x *ListAgentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentsRequest.ProtoReflect.Descriptor instead.
+func (*ListAgentsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{2}
+}
+
+type ListAgentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agents        []*Agent               `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentsResponse) Reset() {
+	*x = ListAgentsResponse{}
+	mi := &file_api_v1_hub_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentsResponse) ProtoMessage() {}
+
+func (x *ListAgentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentsResponse.ProtoReflect.Descriptor instead.
+func (*ListAgentsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListAgentsResponse) GetAgents() []*Agent {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+type ListHostFilesystemsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHostFilesystemsRequest) Reset() {
+	*x = ListHostFilesystemsRequest{}
+	mi := &file_api_v1_hub_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHostFilesystemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHostFilesystemsRequest) ProtoMessage() {}
+
+func (x *ListHostFilesystemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHostFilesystemsRequest.ProtoReflect.Descriptor instead.
+func (*ListHostFilesystemsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{4}
+}
+
+type HostFilesystems struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Host          *Host                  `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Filesystems   []*TrackedFilesystem   `protobuf:"bytes,2,rep,name=filesystems,proto3" json:"filesystems,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostFilesystems) Reset() {
+	*x = HostFilesystems{}
+	mi := &file_api_v1_hub_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostFilesystems) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostFilesystems) ProtoMessage() {}
+
+func (x *HostFilesystems) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostFilesystems.ProtoReflect.Descriptor instead.
+func (*HostFilesystems) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *HostFilesystems) GetHost() *Host {
+	if x != nil {
+		return x.Host
+	}
+	return nil
+}
+
+func (x *HostFilesystems) GetFilesystems() []*TrackedFilesystem {
+	if x != nil {
+		return x.Filesystems
+	}
+	return nil
+}
+
+type ListHostFilesystemsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hosts         []*HostFilesystems     `protobuf:"bytes,1,rep,name=hosts,proto3" json:"hosts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHostFilesystemsResponse) Reset() {
+	*x = ListHostFilesystemsResponse{}
+	mi := &file_api_v1_hub_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHostFilesystemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHostFilesystemsResponse) ProtoMessage() {}
+
+func (x *ListHostFilesystemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHostFilesystemsResponse.ProtoReflect.Descriptor instead.
+func (*ListHostFilesystemsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListHostFilesystemsResponse) GetHosts() []*HostFilesystems {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+type GetHostScrubStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHostScrubStatusRequest) Reset() {
+	*x = GetHostScrubStatusRequest{}
+	mi := &file_api_v1_hub_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHostScrubStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHostScrubStatusRequest) ProtoMessage() {}
+
+func (x *GetHostScrubStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHostScrubStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetHostScrubStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{7}
+}
+
+type HostScrubStatus struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Host          *Host                    `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Filesystems   []*FilesystemScrubStatus `protobuf:"bytes,2,rep,name=filesystems,proto3" json:"filesystems,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostScrubStatus) Reset() {
+	*x = HostScrubStatus{}
+	mi := &file_api_v1_hub_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostScrubStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostScrubStatus) ProtoMessage() {}
+
+func (x *HostScrubStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostScrubStatus.ProtoReflect.Descriptor instead.
+func (*HostScrubStatus) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *HostScrubStatus) GetHost() *Host {
+	if x != nil {
+		return x.Host
+	}
+	return nil
+}
+
+func (x *HostScrubStatus) GetFilesystems() []*FilesystemScrubStatus {
+	if x != nil {
+		return x.Filesystems
+	}
+	return nil
+}
+
+type GetHostScrubStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hosts         []*HostScrubStatus     `protobuf:"bytes,1,rep,name=hosts,proto3" json:"hosts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHostScrubStatusResponse) Reset() {
+	*x = GetHostScrubStatusResponse{}
+	mi := &file_api_v1_hub_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHostScrubStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHostScrubStatusResponse) ProtoMessage() {}
+
+func (x *GetHostScrubStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHostScrubStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetHostScrubStatusResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetHostScrubStatusResponse) GetHosts() []*HostScrubStatus {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+type GetHostBalanceStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHostBalanceStatusRequest) Reset() {
+	*x = GetHostBalanceStatusRequest{}
+	mi := &file_api_v1_hub_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHostBalanceStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHostBalanceStatusRequest) ProtoMessage() {}
+
+func (x *GetHostBalanceStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHostBalanceStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetHostBalanceStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{10}
+}
+
+type HostBalanceStatus struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Host          *Host                      `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Filesystems   []*FilesystemBalanceStatus `protobuf:"bytes,2,rep,name=filesystems,proto3" json:"filesystems,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostBalanceStatus) Reset() {
+	*x = HostBalanceStatus{}
+	mi := &file_api_v1_hub_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostBalanceStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostBalanceStatus) ProtoMessage() {}
+
+func (x *HostBalanceStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostBalanceStatus.ProtoReflect.Descriptor instead.
+func (*HostBalanceStatus) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *HostBalanceStatus) GetHost() *Host {
+	if x != nil {
+		return x.Host
+	}
+	return nil
+}
+
+func (x *HostBalanceStatus) GetFilesystems() []*FilesystemBalanceStatus {
+	if x != nil {
+		return x.Filesystems
+	}
+	return nil
+}
+
+type GetHostBalanceStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hosts         []*HostBalanceStatus   `protobuf:"bytes,1,rep,name=hosts,proto3" json:"hosts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHostBalanceStatusResponse) Reset() {
+	*x = GetHostBalanceStatusResponse{}
+	mi := &file_api_v1_hub_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHostBalanceStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHostBalanceStatusResponse) ProtoMessage() {}
+
+func (x *GetHostBalanceStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHostBalanceStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetHostBalanceStatusResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetHostBalanceStatusResponse) GetHosts() []*HostBalanceStatus {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+type GetHostFilesystemUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHostFilesystemUsageRequest) Reset() {
+	*x = GetHostFilesystemUsageRequest{}
+	mi := &file_api_v1_hub_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHostFilesystemUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHostFilesystemUsageRequest) ProtoMessage() {}
+
+func (x *GetHostFilesystemUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHostFilesystemUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetHostFilesystemUsageRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{13}
+}
+
+type HostFilesystemUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Host          *Host                  `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Filesystems   []*FilesystemUsageInfo `protobuf:"bytes,2,rep,name=filesystems,proto3" json:"filesystems,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostFilesystemUsage) Reset() {
+	*x = HostFilesystemUsage{}
+	mi := &file_api_v1_hub_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostFilesystemUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostFilesystemUsage) ProtoMessage() {}
+
+func (x *HostFilesystemUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostFilesystemUsage.ProtoReflect.Descriptor instead.
+func (*HostFilesystemUsage) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *HostFilesystemUsage) GetHost() *Host {
+	if x != nil {
+		return x.Host
+	}
+	return nil
+}
+
+func (x *HostFilesystemUsage) GetFilesystems() []*FilesystemUsageInfo {
+	if x != nil {
+		return x.Filesystems
+	}
+	return nil
+}
+
+type GetHostFilesystemUsageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hosts         []*HostFilesystemUsage `protobuf:"bytes,1,rep,name=hosts,proto3" json:"hosts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHostFilesystemUsageResponse) Reset() {
+	*x = GetHostFilesystemUsageResponse{}
+	mi := &file_api_v1_hub_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHostFilesystemUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHostFilesystemUsageResponse) ProtoMessage() {}
+
+func (x *GetHostFilesystemUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHostFilesystemUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetHostFilesystemUsageResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetHostFilesystemUsageResponse) GetHosts() []*HostFilesystemUsage {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+type GetHostDiagnosticsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHostDiagnosticsRequest) Reset() {
+	*x = GetHostDiagnosticsRequest{}
+	mi := &file_api_v1_hub_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHostDiagnosticsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHostDiagnosticsRequest) ProtoMessage() {}
+
+func (x *GetHostDiagnosticsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHostDiagnosticsRequest.ProtoReflect.Descriptor instead.
+func (*GetHostDiagnosticsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{16}
+}
+
+type HostDiagnostics struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Host          *Host                    `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Filesystems   []*FilesystemDiagnostics `protobuf:"bytes,2,rep,name=filesystems,proto3" json:"filesystems,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostDiagnostics) Reset() {
+	*x = HostDiagnostics{}
+	mi := &file_api_v1_hub_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostDiagnostics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostDiagnostics) ProtoMessage() {}
+
+func (x *HostDiagnostics) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostDiagnostics.ProtoReflect.Descriptor instead.
+func (*HostDiagnostics) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *HostDiagnostics) GetHost() *Host {
+	if x != nil {
+		return x.Host
+	}
+	return nil
+}
+
+func (x *HostDiagnostics) GetFilesystems() []*FilesystemDiagnostics {
+	if x != nil {
+		return x.Filesystems
+	}
+	return nil
+}
+
+type GetHostDiagnosticsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hosts         []*HostDiagnostics     `protobuf:"bytes,1,rep,name=hosts,proto3" json:"hosts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHostDiagnosticsResponse) Reset() {
+	*x = GetHostDiagnosticsResponse{}
+	mi := &file_api_v1_hub_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHostDiagnosticsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHostDiagnosticsResponse) ProtoMessage() {}
+
+func (x *GetHostDiagnosticsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_hub_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHostDiagnosticsResponse.ProtoReflect.Descriptor instead.
+func (*GetHostDiagnosticsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_hub_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetHostDiagnosticsResponse) GetHosts() []*HostDiagnostics {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+var File_api_v1_hub_proto protoreflect.FileDescriptor
+
+const file_api_v1_hub_proto_rawDesc = "" +
+	"\n" +
+	"\x10api/v1/hub.proto\x12\x06api.v1\x1a\x14api/v1/balance.proto\x1a\x18api/v1/diagnostics.proto\x1a\x17api/v1/filesystem.proto\x1a\x12api/v1/scrub.proto\"\xe2\x01\n" +
+	"\x05Agent\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x18\n" +
+	"\ahealthy\x18\x03 \x01(\bR\ahealthy\x12\x1b\n" +
+	"\tlast_seen\x18\x04 \x01(\x03R\blastSeen\x12!\n" +
+	"\flast_checked\x18\x05 \x01(\x03R\vlastChecked\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x06 \x01(\tR\tlastError\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\a \x01(\x03R\tlatencyMs\x12\x1b\n" +
+	"\tread_only\x18\b \x01(\bR\breadOnly\"U\n" +
+	"\x04Host\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05local\x18\x02 \x01(\bR\x05local\x12#\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\"\x13\n" +
+	"\x11ListAgentsRequest\";\n" +
+	"\x12ListAgentsResponse\x12%\n" +
+	"\x06agents\x18\x01 \x03(\v2\r.api.v1.AgentR\x06agents\"\x1c\n" +
+	"\x1aListHostFilesystemsRequest\"p\n" +
+	"\x0fHostFilesystems\x12 \n" +
+	"\x04host\x18\x01 \x01(\v2\f.api.v1.HostR\x04host\x12;\n" +
+	"\vfilesystems\x18\x02 \x03(\v2\x19.api.v1.TrackedFilesystemR\vfilesystems\"L\n" +
+	"\x1bListHostFilesystemsResponse\x12-\n" +
+	"\x05hosts\x18\x01 \x03(\v2\x17.api.v1.HostFilesystemsR\x05hosts\"\x1b\n" +
+	"\x19GetHostScrubStatusRequest\"t\n" +
+	"\x0fHostScrubStatus\x12 \n" +
+	"\x04host\x18\x01 \x01(\v2\f.api.v1.HostR\x04host\x12?\n" +
+	"\vfilesystems\x18\x02 \x03(\v2\x1d.api.v1.FilesystemScrubStatusR\vfilesystems\"K\n" +
+	"\x1aGetHostScrubStatusResponse\x12-\n" +
+	"\x05hosts\x18\x01 \x03(\v2\x17.api.v1.HostScrubStatusR\x05hosts\"\x1d\n" +
+	"\x1bGetHostBalanceStatusRequest\"x\n" +
+	"\x11HostBalanceStatus\x12 \n" +
+	"\x04host\x18\x01 \x01(\v2\f.api.v1.HostR\x04host\x12A\n" +
+	"\vfilesystems\x18\x02 \x03(\v2\x1f.api.v1.FilesystemBalanceStatusR\vfilesystems\"O\n" +
+	"\x1cGetHostBalanceStatusResponse\x12/\n" +
+	"\x05hosts\x18\x01 \x03(\v2\x19.api.v1.HostBalanceStatusR\x05hosts\"\x1f\n" +
+	"\x1dGetHostFilesystemUsageRequest\"v\n" +
+	"\x13HostFilesystemUsage\x12 \n" +
+	"\x04host\x18\x01 \x01(\v2\f.api.v1.HostR\x04host\x12=\n" +
+	"\vfilesystems\x18\x02 \x03(\v2\x1b.api.v1.FilesystemUsageInfoR\vfilesystems\"S\n" +
+	"\x1eGetHostFilesystemUsageResponse\x121\n" +
+	"\x05hosts\x18\x01 \x03(\v2\x1b.api.v1.HostFilesystemUsageR\x05hosts\"\x1b\n" +
+	"\x19GetHostDiagnosticsRequest\"t\n" +
+	"\x0fHostDiagnostics\x12 \n" +
+	"\x04host\x18\x01 \x01(\v2\f.api.v1.HostR\x04host\x12?\n" +
+	"\vfilesystems\x18\x02 \x03(\v2\x1d.api.v1.FilesystemDiagnosticsR\vfilesystems\"K\n" +
+	"\x1aGetHostDiagnosticsResponse\x12-\n" +
+	"\x05hosts\x18\x01 \x03(\v2\x17.api.v1.HostDiagnosticsR\x05hosts2\xc3\x04\n" +
+	"\n" +
+	"HubService\x12E\n" +
+	"\n" +
+	"ListAgents\x12\x19.api.v1.ListAgentsRequest\x1a\x1a.api.v1.ListAgentsResponse\"\x00\x12`\n" +
+	"\x13ListHostFilesystems\x12\".api.v1.ListHostFilesystemsRequest\x1a#.api.v1.ListHostFilesystemsResponse\"\x00\x12]\n" +
+	"\x12GetHostScrubStatus\x12!.api.v1.GetHostScrubStatusRequest\x1a\".api.v1.GetHostScrubStatusResponse\"\x00\x12c\n" +
+	"\x14GetHostBalanceStatus\x12#.api.v1.GetHostBalanceStatusRequest\x1a$.api.v1.GetHostBalanceStatusResponse\"\x00\x12i\n" +
+	"\x16GetHostFilesystemUsage\x12%.api.v1.GetHostFilesystemUsageRequest\x1a&.api.v1.GetHostFilesystemUsageResponse\"\x00\x12]\n" +
+	"\x12GetHostDiagnostics\x12!.api.v1.GetHostDiagnosticsRequest\x1a\".api.v1.GetHostDiagnosticsResponse\"\x00B{\n" +
+	"\n" +
+	"com.api.v1B\bHubProtoP\x01Z*github.com/elee1766/gobtr/gen/api/v1;apiv1\xa2\x02\x03AXX\xaa\x02\x06Api.V1\xca\x02\x06Api\\V1\xe2\x02\x12Api\\V1\\GPBMetadata\xea\x02\aApi::V1b\x06proto3"
+
+var (
+	file_api_v1_hub_proto_rawDescOnce sync.Once
+	file_api_v1_hub_proto_rawDescData []byte
+)
+
+func file_api_v1_hub_proto_rawDescGZIP() []byte {
+	file_api_v1_hub_proto_rawDescOnce.Do(func() {
+		file_api_v1_hub_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_v1_hub_proto_rawDesc), len(file_api_v1_hub_proto_rawDesc)))
+	})
+	return file_api_v1_hub_proto_rawDescData
+}
+
+var file_api_v1_hub_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_api_v1_hub_proto_goTypes = []any{
+	(*Agent)(nil),                          // 0: api.v1.Agent
+	(*Host)(nil),                           // 1: api.v1.Host
+	(*ListAgentsRequest)(nil),              // 2: api.v1.ListAgentsRequest
+	(*ListAgentsResponse)(nil),             // 3: api.v1.ListAgentsResponse
+	(*ListHostFilesystemsRequest)(nil),     // 4: api.v1.ListHostFilesystemsRequest
+	(*HostFilesystems)(nil),                // 5: api.v1.HostFilesystems
+	(*ListHostFilesystemsResponse)(nil),    // 6: api.v1.ListHostFilesystemsResponse
+	(*GetHostScrubStatusRequest)(nil),      // 7: api.v1.GetHostScrubStatusRequest
+	(*HostScrubStatus)(nil),                // 8: api.v1.HostScrubStatus
+	(*GetHostScrubStatusResponse)(nil),     // 9: api.v1.GetHostScrubStatusResponse
+	(*GetHostBalanceStatusRequest)(nil),    // 10: api.v1.GetHostBalanceStatusRequest
+	(*HostBalanceStatus)(nil),              // 11: api.v1.HostBalanceStatus
+	(*GetHostBalanceStatusResponse)(nil),   // 12: api.v1.GetHostBalanceStatusResponse
+	(*GetHostFilesystemUsageRequest)(nil),  // 13: api.v1.GetHostFilesystemUsageRequest
+	(*HostFilesystemUsage)(nil),            // 14: api.v1.HostFilesystemUsage
+	(*GetHostFilesystemUsageResponse)(nil), // 15: api.v1.GetHostFilesystemUsageResponse
+	(*GetHostDiagnosticsRequest)(nil),      // 16: api.v1.GetHostDiagnosticsRequest
+	(*HostDiagnostics)(nil),                // 17: api.v1.HostDiagnostics
+	(*GetHostDiagnosticsResponse)(nil),     // 18: api.v1.GetHostDiagnosticsResponse
+	(*TrackedFilesystem)(nil),              // 19: api.v1.TrackedFilesystem
+	(*FilesystemScrubStatus)(nil),          // 20: api.v1.FilesystemScrubStatus
+	(*FilesystemBalanceStatus)(nil),        // 21: api.v1.FilesystemBalanceStatus
+	(*FilesystemUsageInfo)(nil),            // 22: api.v1.FilesystemUsageInfo
+	(*FilesystemDiagnostics)(nil),          // 23: api.v1.FilesystemDiagnostics
+}
+var file_api_v1_hub_proto_depIdxs = []int32{
+	0,  // 0: api.v1.ListAgentsResponse.agents:type_name -> api.v1.Agent
+	1,  // 1: api.v1.HostFilesystems.host:type_name -> api.v1.Host
+	19, // 2: api.v1.HostFilesystems.filesystems:type_name -> api.v1.TrackedFilesystem
+	5,  // 3: api.v1.ListHostFilesystemsResponse.hosts:type_name -> api.v1.HostFilesystems
+	1,  // 4: api.v1.HostScrubStatus.host:type_name -> api.v1.Host
+	20, // 5: api.v1.HostScrubStatus.filesystems:type_name -> api.v1.FilesystemScrubStatus
+	8,  // 6: api.v1.GetHostScrubStatusResponse.hosts:type_name -> api.v1.HostScrubStatus
+	1,  // 7: api.v1.HostBalanceStatus.host:type_name -> api.v1.Host
+	21, // 8: api.v1.HostBalanceStatus.filesystems:type_name -> api.v1.FilesystemBalanceStatus
+	11, // 9: api.v1.GetHostBalanceStatusResponse.hosts:type_name -> api.v1.HostBalanceStatus
+	1,  // 10: api.v1.HostFilesystemUsage.host:type_name -> api.v1.Host
+	22, // 11: api.v1.HostFilesystemUsage.filesystems:type_name -> api.v1.FilesystemUsageInfo
+	14, // 12: api.v1.GetHostFilesystemUsageResponse.hosts:type_name -> api.v1.HostFilesystemUsage
+	1,  // 13: api.v1.HostDiagnostics.host:type_name -> api.v1.Host
+	23, // 14: api.v1.HostDiagnostics.filesystems:type_name -> api.v1.FilesystemDiagnostics
+	17, // 15: api.v1.GetHostDiagnosticsResponse.hosts:type_name -> api.v1.HostDiagnostics
+	2,  // 16: api.v1.HubService.ListAgents:input_type -> api.v1.ListAgentsRequest
+	4,  // 17: api.v1.HubService.ListHostFilesystems:input_type -> api.v1.ListHostFilesystemsRequest
+	7,  // 18: api.v1.HubService.GetHostScrubStatus:input_type -> api.v1.GetHostScrubStatusRequest
+	10, // 19: api.v1.HubService.GetHostBalanceStatus:input_type -> api.v1.GetHostBalanceStatusRequest
+	13, // 20: api.v1.HubService.GetHostFilesystemUsage:input_type -> api.v1.GetHostFilesystemUsageRequest
+	16, // 21: api.v1.HubService.GetHostDiagnostics:input_type -> api.v1.GetHostDiagnosticsRequest
+	3,  // 22: api.v1.HubService.ListAgents:output_type -> api.v1.ListAgentsResponse
+	6,  // 23: api.v1.HubService.ListHostFilesystems:output_type -> api.v1.ListHostFilesystemsResponse
+	9,  // 24: api.v1.HubService.GetHostScrubStatus:output_type -> api.v1.GetHostScrubStatusResponse
+	12, // 25: api.v1.HubService.GetHostBalanceStatus:output_type -> api.v1.GetHostBalanceStatusResponse
+	15, // 26: api.v1.HubService.GetHostFilesystemUsage:output_type -> api.v1.GetHostFilesystemUsageResponse
+	18, // 27: api.v1.HubService.GetHostDiagnostics:output_type -> api.v1.GetHostDiagnosticsResponse
+	22, // [22:28] is the sub-list for method output_type
+	16, // [16:22] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_api_v1_hub_proto_init() }
+func file_api_v1_hub_proto_init() {
+	if File_api_v1_hub_proto != nil {
+		return
+	}
+	file_api_v1_balance_proto_init()
+	file_api_v1_diagnostics_proto_init()
+	file_api_v1_filesystem_proto_init()
+	file_api_v1_scrub_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_hub_proto_rawDesc), len(file_api_v1_hub_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_v1_hub_proto_goTypes,
+		DependencyIndexes: file_api_v1_hub_proto_depIdxs,
+		MessageInfos:      file_api_v1_hub_proto_msgTypes,
+	}.Build()
+	File_api_v1_hub_proto = out.File
+	file_api_v1_hub_proto_goTypes = nil
+	file_api_v1_hub_proto_depIdxs = nil
+}
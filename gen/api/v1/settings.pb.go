@@ -25,8 +25,15 @@ type ServerSettings struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Default target number of samples for usage sampling (default: 500000)
 	DefaultSampleTarget uint64 `protobuf:"varint,1,opt,name=default_sample_target,json=defaultSampleTarget,proto3" json:"default_sample_target,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+	// How often usage history is recorded, in seconds (default: 900)
+	CollectIntervalSeconds uint64 `protobuf:"varint,2,opt,name=collect_interval_seconds,json=collectIntervalSeconds,proto3" json:"collect_interval_seconds,omitempty"`
+	// Diagnostic thresholds used when a request leaves them unset
+	ScrubMaxAgeDays  int32   `protobuf:"varint,3,opt,name=scrub_max_age_days,json=scrubMaxAgeDays,proto3" json:"scrub_max_age_days,omitempty"` // Warn when the last scrub is older (default: 30)
+	SlackPercent     float64 `protobuf:"fixed64,4,opt,name=slack_percent,json=slackPercent,proto3" json:"slack_percent,omitempty"`             // Warn when unused space inside data chunks exceeds this (default: 25)
+	MinSlackBytes    int64   `protobuf:"varint,5,opt,name=min_slack_bytes,json=minSlackBytes,proto3" json:"min_slack_bytes,omitempty"`         // Ignore slack below this size (default: 10 GiB)
+	ImbalancePercent float64 `protobuf:"fixed64,6,opt,name=imbalance_percent,json=imbalancePercent,proto3" json:"imbalance_percent,omitempty"` // Warn when device allocation ratios differ by more (default: 25)
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *ServerSettings) Reset() {
@@ -66,6 +73,41 @@ func (x *ServerSettings) GetDefaultSampleTarget() uint64 {
 	return 0
 }
 
+func (x *ServerSettings) GetCollectIntervalSeconds() uint64 {
+	if x != nil {
+		return x.CollectIntervalSeconds
+	}
+	return 0
+}
+
+func (x *ServerSettings) GetScrubMaxAgeDays() int32 {
+	if x != nil {
+		return x.ScrubMaxAgeDays
+	}
+	return 0
+}
+
+func (x *ServerSettings) GetSlackPercent() float64 {
+	if x != nil {
+		return x.SlackPercent
+	}
+	return 0
+}
+
+func (x *ServerSettings) GetMinSlackBytes() int64 {
+	if x != nil {
+		return x.MinSlackBytes
+	}
+	return 0
+}
+
+func (x *ServerSettings) GetImbalancePercent() float64 {
+	if x != nil {
+		return x.ImbalancePercent
+	}
+	return 0
+}
+
 type GetSettingsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -105,6 +147,8 @@ func (*GetSettingsRequest) Descriptor() ([]byte, []int) {
 type GetSettingsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Settings      *ServerSettings        `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
+	Defaults      *ServerSettings        `protobuf:"bytes,2,opt,name=defaults,proto3" json:"defaults,omitempty"`     // Values from env and config, before any update
+	Overridden    []string               `protobuf:"bytes,3,rep,name=overridden,proto3" json:"overridden,omitempty"` // Fields that differ from their defaults
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -146,9 +190,24 @@ func (x *GetSettingsResponse) GetSettings() *ServerSettings {
 	return nil
 }
 
+func (x *GetSettingsResponse) GetDefaults() *ServerSettings {
+	if x != nil {
+		return x.Defaults
+	}
+	return nil
+}
+
+func (x *GetSettingsResponse) GetOverridden() []string {
+	if x != nil {
+		return x.Overridden
+	}
+	return nil
+}
+
 type UpdateSettingsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Settings      *ServerSettings        `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
+	ResetFields   []string               `protobuf:"bytes,2,rep,name=reset_fields,json=resetFields,proto3" json:"reset_fields,omitempty"` // Fields to return to their defaults
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -190,9 +249,17 @@ func (x *UpdateSettingsRequest) GetSettings() *ServerSettings {
 	return nil
 }
 
+func (x *UpdateSettingsRequest) GetResetFields() []string {
+	if x != nil {
+		return x.ResetFields
+	}
+	return nil
+}
+
 type UpdateSettingsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Settings      *ServerSettings        `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
+	Overridden    []string               `protobuf:"bytes,2,rep,name=overridden,proto3" json:"overridden,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -234,20 +301,40 @@ func (x *UpdateSettingsResponse) GetSettings() *ServerSettings {
 	return nil
 }
 
+func (x *UpdateSettingsResponse) GetOverridden() []string {
+	if x != nil {
+		return x.Overridden
+	}
+	return nil
+}
+
 var File_api_v1_settings_proto protoreflect.FileDescriptor
 
 const file_api_v1_settings_proto_rawDesc = "" +
 	"\n" +
-	"\x15api/v1/settings.proto\x12\x06api.v1\"D\n" +
+	"\x15api/v1/settings.proto\x12\x06api.v1\"\xa5\x02\n" +
 	"\x0eServerSettings\x122\n" +
-	"\x15default_sample_target\x18\x01 \x01(\x04R\x13defaultSampleTarget\"\x14\n" +
-	"\x12GetSettingsRequest\"I\n" +
+	"\x15default_sample_target\x18\x01 \x01(\x04R\x13defaultSampleTarget\x128\n" +
+	"\x18collect_interval_seconds\x18\x02 \x01(\x04R\x16collectIntervalSeconds\x12+\n" +
+	"\x12scrub_max_age_days\x18\x03 \x01(\x05R\x0fscrubMaxAgeDays\x12#\n" +
+	"\rslack_percent\x18\x04 \x01(\x01R\fslackPercent\x12&\n" +
+	"\x0fmin_slack_bytes\x18\x05 \x01(\x03R\rminSlackBytes\x12+\n" +
+	"\x11imbalance_percent\x18\x06 \x01(\x01R\x10imbalancePercent\"\x14\n" +
+	"\x12GetSettingsRequest\"\x9d\x01\n" +
 	"\x13GetSettingsResponse\x122\n" +
-	"\bsettings\x18\x01 \x01(\v2\x16.api.v1.ServerSettingsR\bsettings\"K\n" +
+	"\bsettings\x18\x01 \x01(\v2\x16.api.v1.ServerSettingsR\bsettings\x122\n" +
+	"\bdefaults\x18\x02 \x01(\v2\x16.api.v1.ServerSettingsR\bdefaults\x12\x1e\n" +
+	"\n" +
+	"overridden\x18\x03 \x03(\tR\n" +
+	"overridden\"n\n" +
 	"\x15UpdateSettingsRequest\x122\n" +
-	"\bsettings\x18\x01 \x01(\v2\x16.api.v1.ServerSettingsR\bsettings\"L\n" +
+	"\bsettings\x18\x01 \x01(\v2\x16.api.v1.ServerSettingsR\bsettings\x12!\n" +
+	"\freset_fields\x18\x02 \x03(\tR\vresetFields\"l\n" +
 	"\x16UpdateSettingsResponse\x122\n" +
-	"\bsettings\x18\x01 \x01(\v2\x16.api.v1.ServerSettingsR\bsettings2\xae\x01\n" +
+	"\bsettings\x18\x01 \x01(\v2\x16.api.v1.ServerSettingsR\bsettings\x12\x1e\n" +
+	"\n" +
+	"overridden\x18\x02 \x03(\tR\n" +
+	"overridden2\xae\x01\n" +
 	"\x0fSettingsService\x12H\n" +
 	"\vGetSettings\x12\x1a.api.v1.GetSettingsRequest\x1a\x1b.api.v1.GetSettingsResponse\"\x00\x12Q\n" +
 	"\x0eUpdateSettings\x12\x1d.api.v1.UpdateSettingsRequest\x1a\x1e.api.v1.UpdateSettingsResponse\"\x00B\x80\x01\n" +
@@ -276,17 +363,18 @@ var file_api_v1_settings_proto_goTypes = []any{
 }
 var file_api_v1_settings_proto_depIdxs = []int32{
 	0, // 0: api.v1.GetSettingsResponse.settings:type_name -> api.v1.ServerSettings
-	0, // 1: api.v1.UpdateSettingsRequest.settings:type_name -> api.v1.ServerSettings
-	0, // 2: api.v1.UpdateSettingsResponse.settings:type_name -> api.v1.ServerSettings
-	1, // 3: api.v1.SettingsService.GetSettings:input_type -> api.v1.GetSettingsRequest
-	3, // 4: api.v1.SettingsService.UpdateSettings:input_type -> api.v1.UpdateSettingsRequest
-	2, // 5: api.v1.SettingsService.GetSettings:output_type -> api.v1.GetSettingsResponse
-	4, // 6: api.v1.SettingsService.UpdateSettings:output_type -> api.v1.UpdateSettingsResponse
-	5, // [5:7] is the sub-list for method output_type
-	3, // [3:5] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	0, // 1: api.v1.GetSettingsResponse.defaults:type_name -> api.v1.ServerSettings
+	0, // 2: api.v1.UpdateSettingsRequest.settings:type_name -> api.v1.ServerSettings
+	0, // 3: api.v1.UpdateSettingsResponse.settings:type_name -> api.v1.ServerSettings
+	1, // 4: api.v1.SettingsService.GetSettings:input_type -> api.v1.GetSettingsRequest
+	3, // 5: api.v1.SettingsService.UpdateSettings:input_type -> api.v1.UpdateSettingsRequest
+	2, // 6: api.v1.SettingsService.GetSettings:output_type -> api.v1.GetSettingsResponse
+	4, // 7: api.v1.SettingsService.UpdateSettings:output_type -> api.v1.UpdateSettingsResponse
+	6, // [6:8] is the sub-list for method output_type
+	4, // [4:6] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_api_v1_settings_proto_init() }
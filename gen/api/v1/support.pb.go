@@ -0,0 +1,184 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: api/v1/support.proto
+
+package apiv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateSupportBundleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	History       int32                  `protobuf:"varint,1,opt,name=history,proto3" json:"history,omitempty"` // Balance records and errors to include (0 = 20)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSupportBundleRequest) Reset() {
+	*x = CreateSupportBundleRequest{}
+	mi := &file_api_v1_support_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSupportBundleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSupportBundleRequest) ProtoMessage() {}
+
+func (x *CreateSupportBundleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_support_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSupportBundleRequest.ProtoReflect.Descriptor instead.
+func (*CreateSupportBundleRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_support_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateSupportBundleRequest) GetHistory() int32 {
+	if x != nil {
+		return x.History
+	}
+	return 0
+}
+
+type CreateSupportBundleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Bundle        []byte                 `protobuf:"bytes,2,opt,name=bundle,proto3" json:"bundle,omitempty"` // tar.gz
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSupportBundleResponse) Reset() {
+	*x = CreateSupportBundleResponse{}
+	mi := &file_api_v1_support_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSupportBundleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSupportBundleResponse) ProtoMessage() {}
+
+func (x *CreateSupportBundleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_support_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSupportBundleResponse.ProtoReflect.Descriptor instead.
+func (*CreateSupportBundleResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_support_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateSupportBundleResponse) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *CreateSupportBundleResponse) GetBundle() []byte {
+	if x != nil {
+		return x.Bundle
+	}
+	return nil
+}
+
+var File_api_v1_support_proto protoreflect.FileDescriptor
+
+const file_api_v1_support_proto_rawDesc = "" +
+	"\n" +
+	"\x14api/v1/support.proto\x12\x06api.v1\"6\n" +
+	"\x1aCreateSupportBundleRequest\x12\x18\n" +
+	"\ahistory\x18\x01 \x01(\x05R\ahistory\"Q\n" +
+	"\x1bCreateSupportBundleResponse\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x16\n" +
+	"\x06bundle\x18\x02 \x01(\fR\x06bundle2r\n" +
+	"\x0eSupportService\x12`\n" +
+	"\x13CreateSupportBundle\x12\".api.v1.CreateSupportBundleRequest\x1a#.api.v1.CreateSupportBundleResponse\"\x00B\x7f\n" +
+	"\n" +
+	"com.api.v1B\fSupportProtoP\x01Z*github.com/elee1766/gobtr/gen/api/v1;apiv1\xa2\x02\x03AXX\xaa\x02\x06Api.V1\xca\x02\x06Api\\V1\xe2\x02\x12Api\\V1\\GPBMetadata\xea\x02\aApi::V1b\x06proto3"
+
+var (
+	file_api_v1_support_proto_rawDescOnce sync.Once
+	file_api_v1_support_proto_rawDescData []byte
+)
+
+func file_api_v1_support_proto_rawDescGZIP() []byte {
+	file_api_v1_support_proto_rawDescOnce.Do(func() {
+		file_api_v1_support_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_v1_support_proto_rawDesc), len(file_api_v1_support_proto_rawDesc)))
+	})
+	return file_api_v1_support_proto_rawDescData
+}
+
+var file_api_v1_support_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_v1_support_proto_goTypes = []any{
+	(*CreateSupportBundleRequest)(nil),  // 0: api.v1.CreateSupportBundleRequest
+	(*CreateSupportBundleResponse)(nil), // 1: api.v1.CreateSupportBundleResponse
+}
+var file_api_v1_support_proto_depIdxs = []int32{
+	0, // 0: api.v1.SupportService.CreateSupportBundle:input_type -> api.v1.CreateSupportBundleRequest
+	1, // 1: api.v1.SupportService.CreateSupportBundle:output_type -> api.v1.CreateSupportBundleResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_api_v1_support_proto_init() }
+func file_api_v1_support_proto_init() {
+	if File_api_v1_support_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_support_proto_rawDesc), len(file_api_v1_support_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_v1_support_proto_goTypes,
+		DependencyIndexes: file_api_v1_support_proto_depIdxs,
+		MessageInfos:      file_api_v1_support_proto_msgTypes,
+	}.Build()
+	File_api_v1_support_proto = out.File
+	file_api_v1_support_proto_goTypes = nil
+	file_api_v1_support_proto_depIdxs = nil
+}
@@ -292,6 +292,7 @@ type SamplingProgress struct {
 	CurrentPath        string                 `protobuf:"bytes,7,opt,name=current_path,json=currentPath,proto3" json:"current_path,omitempty"`                         // Current path being sampled (for UI feedback)
 	RecentPaths        []string               `protobuf:"bytes,8,rep,name=recent_paths,json=recentPaths,proto3" json:"recent_paths,omitempty"`                         // Recent sampled paths for animation
 	RunningTimeSeconds int64                  `protobuf:"varint,9,opt,name=running_time_seconds,json=runningTimeSeconds,proto3" json:"running_time_seconds,omitempty"` // Cumulative running time in seconds
+	TargetSamples      uint64                 `protobuf:"varint,10,opt,name=target_samples,json=targetSamples,proto3" json:"target_samples,omitempty"`                 // Sampling stops at this many samples
 	unknownFields      protoimpl.UnknownFields
 	sizeCache          protoimpl.SizeCache
 }
@@ -389,6 +390,13 @@ func (x *SamplingProgress) GetRunningTimeSeconds() int64 {
 	return 0
 }
 
+func (x *SamplingProgress) GetTargetSamples() uint64 {
+	if x != nil {
+		return x.TargetSamples
+	}
+	return 0
+}
+
 type GetSamplingStatusResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Progress      *SamplingProgress      `protobuf:"bytes,1,opt,name=progress,proto3" json:"progress,omitempty"`
@@ -836,7 +844,7 @@ const file_api_v1_usage_proto_rawDesc = "" +
 	"\astopped\x18\x01 \x01(\bR\astopped\x12#\n" +
 	"\rtotal_samples\x18\x02 \x01(\x04R\ftotalSamples\"3\n" +
 	"\x18GetSamplingStatusRequest\x12\x17\n" +
-	"\afs_path\x18\x01 \x01(\tR\x06fsPath\"\xdb\x02\n" +
+	"\afs_path\x18\x01 \x01(\tR\x06fsPath\"\x82\x03\n" +
 	"\x10SamplingProgress\x12\x1d\n" +
 	"\n" +
 	"is_running\x18\x01 \x01(\bR\tisRunning\x12!\n" +
@@ -849,7 +857,9 @@ const file_api_v1_usage_proto_rawDesc = "" +
 	"\x12samples_per_second\x18\x06 \x01(\x01R\x10samplesPerSecond\x12!\n" +
 	"\fcurrent_path\x18\a \x01(\tR\vcurrentPath\x12!\n" +
 	"\frecent_paths\x18\b \x03(\tR\vrecentPaths\x120\n" +
-	"\x14running_time_seconds\x18\t \x01(\x03R\x12runningTimeSeconds\"\x91\x01\n" +
+	"\x14running_time_seconds\x18\t \x01(\x03R\x12runningTimeSeconds\x12%\n" +
+	"\x0etarget_samples\x18\n" +
+	" \x01(\x04R\rtargetSamples\"\x91\x01\n" +
 	"\x19GetSamplingStatusResponse\x124\n" +
 	"\bprogress\x18\x01 \x01(\v2\x18.api.v1.SamplingProgressR\bprogress\x12\x1f\n" +
 	"\vhas_session\x18\x02 \x01(\bR\n" +
@@ -1,9 +1,10 @@
 module github.com/elee1766/gobtr
 
-go 1.25.2
+go 1.26.0
 
 require (
 	connectrpc.com/connect v1.19.1
+	github.com/BurntSushi/toml v1.4.0
 	github.com/alecthomas/kong v1.13.0
 	github.com/cockroachdb/pebble v1.1.5
 	github.com/dennwc/btrfs v0.0.0-20241002142654-12ae127e0bf6
@@ -13,9 +14,14 @@ require (
 	github.com/jedib0t/go-pretty/v6 v6.7.7
 	github.com/ncruces/go-sqlite3 v0.30.3
 	github.com/pressly/goose/v3 v3.26.0
+	github.com/prometheus/client_golang v1.15.0
 	go.uber.org/fx v1.24.0
-	golang.org/x/net v0.47.0
+	golang.org/x/crypto v0.57.0
+	golang.org/x/net v0.58.0
+	golang.org/x/sys v0.48.0
+	golang.org/x/term v0.46.0
 	google.golang.org/protobuf v1.36.10
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -39,7 +45,6 @@ require (
 	github.com/mfridman/interpolate v0.0.2 // indirect
 	github.com/ncruces/julianday v1.0.0 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	github.com/prometheus/client_golang v1.15.0 // indirect
 	github.com/prometheus/client_model v0.3.0 // indirect
 	github.com/prometheus/common v0.42.0 // indirect
 	github.com/prometheus/procfs v0.15.1 // indirect
@@ -51,7 +56,6 @@ require (
 	go.uber.org/multierr v1.11.0 // indirect
 	go.uber.org/zap v1.27.1 // indirect
 	golang.org/x/exp v0.0.0-20250620022241-b7579e27df2b // indirect
-	golang.org/x/sync v0.18.0 // indirect
-	golang.org/x/sys v0.38.0 // indirect
-	golang.org/x/text v0.31.0 // indirect
+	golang.org/x/sync v0.23.0 // indirect
+	golang.org/x/text v0.42.0 // indirect
 )
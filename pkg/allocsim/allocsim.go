@@ -0,0 +1,491 @@
+// Package allocsim simulates the btrfs chunk allocator against the current
+// device sizes and allocations, to find space that can't be used because of
+// how chunks are spread over the devices and which balance filters free it.
+package allocsim
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/elee1766/gobtr/pkg/btrfs"
+	"github.com/elee1766/gobtr/pkg/privsep"
+)
+
+const (
+	// stripeAlign is the granularity stripes are sized in
+	stripeAlign = 1 << 20
+
+	// Chunk size limits from init_alloc_chunk_ctl_policy_regular
+	dataMaxStripe     = 1 << 30
+	dataMaxChunk      = 10 << 30
+	metadataMaxStripe = 1 << 30
+	smallFsThreshold  = 50 << 30
+	smallFsMaxStripe  = 256 << 20
+
+	// maxSuggestions caps how many balance suggestions are returned
+	maxSuggestions = 3
+)
+
+// Device is one device as seen by the allocator
+type Device struct {
+	DevID     uint64
+	Path      string
+	Size      uint64
+	Allocated uint64 // Bytes allocated to chunks of every type
+}
+
+// Stripe is the part of a chunk that lives on one device
+type Stripe struct {
+	DevID  uint64
+	Offset uint64 // Physical offset on the device
+	Length uint64
+}
+
+// Chunk is one existing chunk of the analyzed type
+type Chunk struct {
+	Start   uint64 // Logical address
+	Profile string
+	Stripes []Stripe
+}
+
+// logical returns the chunk's logical size
+func (c *Chunk) logical() uint64 {
+	if len(c.Stripes) == 0 {
+		return 0
+	}
+	p, ok := LookupProfile(c.Profile)
+	if !ok {
+		return 0
+	}
+	return c.Stripes[0].Length * uint64(p.dataStripes(len(c.Stripes)))
+}
+
+// hasDevice reports whether the chunk has a stripe on devid
+func (c *Chunk) hasDevice(devid uint64) bool {
+	for _, s := range c.Stripes {
+		if s.DevID == devid {
+			return true
+		}
+	}
+	return false
+}
+
+// Input is everything Analyze needs
+type Input struct {
+	Type    string // "Data" or "Metadata"
+	Profile Profile
+	Devices []Device
+	Chunks  []*Chunk // Existing chunks of Type
+}
+
+// DeviceResult is the simulated outcome for one device
+type DeviceResult struct {
+	DevID            uint64
+	Path             string
+	Size             uint64
+	Allocated        uint64
+	Unallocated      uint64
+	Stranded         uint64 // Unallocated bytes no chunk can use with the current layout
+	BalancedStranded uint64 // Unallocated bytes no chunk could use after a full balance
+}
+
+// Suggestion is a targeted balance that recovers stranded space
+type Suggestion struct {
+	DevID  uint64
+	Limit  int    // Chunks to relocate with devid=DevID,limit=Limit
+	Usable uint64 // Logical bytes allocatable afterwards
+	// Recovers is how many more logical bytes become allocatable
+	Recovers uint64
+	// Physical range on DevID covering the same chunks, for a drange filter
+	DrangeStart uint64
+	DrangeEnd   uint64
+	// DrangeChunks is how many chunks devid=DevID,drange=... matches, which can
+	// be more than Limit since other chunks may sit in the same range
+	DrangeChunks int
+}
+
+// Result is the outcome of an allocation analysis
+type Result struct {
+	Type    string
+	Profile string
+	Devices []DeviceResult
+
+	Unallocated uint64 // Raw unallocated bytes over all devices
+	Usable      uint64 // Logical bytes that can still be allocated as Profile
+	Stranded    uint64 // Raw bytes left unallocatable with the current layout
+
+	BalancedUsable   uint64 // Logical bytes allocatable after a full balance
+	BalancedStranded uint64 // Raw bytes unallocatable even after a full balance
+
+	// Recoverable is the logical space a balance would make allocatable
+	Recoverable uint64
+
+	Suggestions []Suggestion
+}
+
+// simulator tracks unallocated space per device while chunks are allocated and freed
+type simulator struct {
+	profile   Profile
+	maxStripe uint64
+	maxChunk  uint64
+	free      map[uint64]uint64
+	devids    []uint64
+}
+
+func newSimulator(in *Input) *simulator {
+	var total uint64
+	for _, d := range in.Devices {
+		total += d.Size
+	}
+
+	s := &simulator{
+		profile:   in.Profile,
+		maxStripe: dataMaxStripe,
+		maxChunk:  dataMaxChunk,
+		free:      make(map[uint64]uint64, len(in.Devices)),
+	}
+	if strings.EqualFold(in.Type, "metadata") {
+		s.maxStripe = metadataMaxStripe
+		if total <= smallFsThreshold {
+			s.maxStripe = smallFsMaxStripe
+		}
+		s.maxChunk = s.maxStripe
+	}
+
+	for _, d := range in.Devices {
+		var free uint64
+		if d.Size > d.Allocated {
+			free = d.Size - d.Allocated
+		}
+		s.free[d.DevID] = free
+		s.devids = append(s.devids, d.DevID)
+	}
+	return s
+}
+
+func (s *simulator) clone() *simulator {
+	c := *s
+	c.free = make(map[uint64]uint64, len(s.free))
+	for k, v := range s.free {
+		c.free[k] = v
+	}
+	return &c
+}
+
+// alloc allocates one chunk of at most maxLogical bytes the way
+// btrfs_create_chunk does and returns its logical size, or 0 if it doesn't fit
+func (s *simulator) alloc(maxLogical uint64) uint64 {
+	p := s.profile
+
+	var devs []uint64
+	for _, id := range s.devids {
+		if s.free[id] >= stripeAlign*uint64(p.DevStripes) {
+			devs = append(devs, id)
+		}
+	}
+	sort.Slice(devs, func(i, j int) bool {
+		if s.free[devs[i]] != s.free[devs[j]] {
+			return s.free[devs[i]] > s.free[devs[j]]
+		}
+		return devs[i] < devs[j]
+	})
+
+	ndevs := len(devs)
+	if p.DevsMax > 0 {
+		ndevs = min(ndevs, p.DevsMax)
+	}
+	ndevs -= ndevs % p.DevsIncrement
+	if ndevs < p.DevsMin || ndevs == 0 {
+		return 0
+	}
+
+	dataStripes := uint64(p.dataStripes(ndevs * p.DevStripes))
+	if dataStripes == 0 {
+		return 0
+	}
+
+	stripe := min(s.free[devs[ndevs-1]]/uint64(p.DevStripes), s.maxStripe)
+	if limit := min(s.maxChunk, maxLogical); stripe*dataStripes > limit {
+		stripe = limit / dataStripes
+	}
+	stripe -= stripe % stripeAlign
+	if stripe == 0 {
+		return 0
+	}
+
+	for _, id := range devs[:ndevs] {
+		s.free[id] -= stripe * uint64(p.DevStripes)
+	}
+	return stripe * dataStripes
+}
+
+// fill allocates chunks until nothing fits and returns the logical bytes allocated
+func (s *simulator) fill() uint64 {
+	var total uint64
+	for {
+		n := s.alloc(^uint64(0))
+		if n == 0 {
+			return total
+		}
+		total += n
+	}
+}
+
+// relocate frees a chunk and allocates replacement chunks for its logical
+// size. The old chunk is freed first, which is optimistic: the kernel packs
+// relocated extents into existing free space before it needs new chunks.
+// It returns false if the replacement didn't fit.
+func (s *simulator) relocate(c *Chunk) bool {
+	for _, st := range c.Stripes {
+		s.free[st.DevID] += st.Length
+	}
+	need := c.logical()
+	for need > 0 {
+		n := s.alloc(need)
+		if n == 0 {
+			return false
+		}
+		need -= min(n, need)
+	}
+	return true
+}
+
+func (s *simulator) totalFree() uint64 {
+	var total uint64
+	for _, v := range s.free {
+		total += v
+	}
+	return total
+}
+
+// Analyze simulates the allocator over the current layout, after a full
+// balance, and after targeted devid balances. Suggestions are only made when
+// every existing chunk already has the analyzed profile, since otherwise a
+// convert is needed rather than a rebalance.
+func Analyze(in *Input) *Result {
+	res := &Result{
+		Type:    in.Type,
+		Profile: in.Profile.Name,
+	}
+
+	// Current layout
+	base := newSimulator(in)
+	res.Unallocated = base.totalFree()
+	current := base.clone()
+	res.Usable = current.fill()
+	res.Stranded = current.totalFree()
+
+	// Full balance: every chunk of the type is reallocated from scratch
+	var logicalInUse uint64
+	balanced := base.clone()
+	for _, c := range in.Chunks {
+		logicalInUse += c.logical()
+		for _, st := range c.Stripes {
+			balanced.free[st.DevID] += st.Length
+		}
+	}
+	if capacity := balanced.fill(); capacity > logicalInUse {
+		res.BalancedUsable = capacity - logicalInUse
+	}
+	res.BalancedStranded = balanced.totalFree()
+	if res.BalancedUsable > res.Usable {
+		res.Recoverable = res.BalancedUsable - res.Usable
+	}
+
+	for _, d := range in.Devices {
+		dr := DeviceResult{
+			DevID:            d.DevID,
+			Path:             d.Path,
+			Size:             d.Size,
+			Allocated:        d.Allocated,
+			Unallocated:      base.free[d.DevID],
+			Stranded:         current.free[d.DevID],
+			BalancedStranded: balanced.free[d.DevID],
+		}
+		res.Devices = append(res.Devices, dr)
+	}
+
+	// Don't bother suggesting a balance for less than one chunk's worth
+	if res.Recoverable < base.maxStripe {
+		return res
+	}
+	for _, c := range in.Chunks {
+		if !strings.EqualFold(c.Profile, in.Profile.Name) {
+			return res
+		}
+	}
+
+	// Balance walks chunks from the highest logical address down
+	chunks := make([]*Chunk, len(in.Chunks))
+	copy(chunks, in.Chunks)
+	sort.Slice(chunks, func(i, j int) bool { return chunks[i].Start > chunks[j].Start })
+
+	target := res.BalancedUsable - min(res.BalancedUsable, base.maxStripe)
+	for _, d := range in.Devices {
+		if sg := suggest(base, chunks, d.DevID, res.Usable, target); sg != nil {
+			res.Suggestions = append(res.Suggestions, *sg)
+		}
+	}
+	sort.Slice(res.Suggestions, func(i, j int) bool {
+		a, b := res.Suggestions[i], res.Suggestions[j]
+		if a.Recovers != b.Recovers {
+			return a.Recovers > b.Recovers
+		}
+		return a.Limit < b.Limit
+	})
+	if len(res.Suggestions) > maxSuggestions {
+		res.Suggestions = res.Suggestions[:maxSuggestions]
+	}
+
+	return res
+}
+
+// usableAfter relocates the first k chunks and returns the logical bytes
+// allocatable afterwards, and whether every relocation fit
+func usableAfter(base *simulator, matched []*Chunk, k int) (uint64, bool) {
+	s := base.clone()
+	for _, c := range matched[:k] {
+		if !s.relocate(c) {
+			return s.fill(), false
+		}
+	}
+	return s.fill(), true
+}
+
+// suggest finds the smallest devid=devid,limit=N balance that reaches target,
+// or the one relocating every matching chunk if none does
+func suggest(base *simulator, chunks []*Chunk, devid uint64, usable, target uint64) *Suggestion {
+	var matched []*Chunk
+	for _, c := range chunks {
+		if c.hasDevice(devid) {
+			matched = append(matched, c)
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	// Binary search for the smallest limit that reaches target. Usable space
+	// isn't strictly monotonic in the limit, but close enough for a suggestion.
+	lo, hi := 1, len(matched)
+	best, ok := usableAfter(base, matched, hi)
+	if !ok {
+		return nil
+	}
+	if best >= target {
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if u, ok := usableAfter(base, matched, mid); ok && u >= target {
+				hi = mid
+				best = u
+			} else {
+				lo = mid + 1
+			}
+		}
+	}
+	if best <= usable {
+		return nil
+	}
+
+	sg := &Suggestion{
+		DevID:    devid,
+		Limit:    hi,
+		Usable:   best,
+		Recovers: best - usable,
+	}
+
+	sg.DrangeStart = ^uint64(0)
+	for _, c := range matched[:hi] {
+		for _, st := range c.Stripes {
+			if st.DevID == devid {
+				sg.DrangeStart = min(sg.DrangeStart, st.Offset)
+				sg.DrangeEnd = max(sg.DrangeEnd, st.Offset+st.Length)
+			}
+		}
+	}
+	for _, c := range matched {
+		for _, st := range c.Stripes {
+			if st.DevID == devid && st.Offset < sg.DrangeEnd && st.Offset+st.Length > sg.DrangeStart {
+				sg.DrangeChunks++
+				break
+			}
+		}
+	}
+
+	return sg
+}
+
+// Gather reads the devices and chunks of typ ("Data" or "Metadata") for the
+// filesystem mounted at path. An empty profile analyzes the profile holding
+// the most bytes; any other profile is a what-if for converting to it. The
+// chunk tree is read through b.
+func Gather(b privsep.Backend, path, typ, profile string) (*Input, error) {
+	var canonical string
+	for _, t := range []string{"Data", "Metadata"} {
+		if strings.EqualFold(t, typ) {
+			canonical = t
+		}
+	}
+	if canonical == "" {
+		return nil, fmt.Errorf("unsupported chunk type %q", typ)
+	}
+
+	_, devInfos, err := btrfs.GetFilesystemAndDeviceInfo(path)
+	if err != nil {
+		return nil, fmt.Errorf("get filesystem/device info: %w", err)
+	}
+
+	allocs, err := btrfs.GetDeviceChunkAllocations(b, path)
+	if err != nil {
+		return nil, fmt.Errorf("get device chunk allocations: %w", err)
+	}
+
+	in := &Input{Type: canonical}
+	for _, d := range devInfos {
+		in.Devices = append(in.Devices, Device{
+			DevID:     d.DevID,
+			Path:      d.Path,
+			Size:      d.TotalBytes,
+			Allocated: d.BytesUsed,
+		})
+	}
+
+	byStart := make(map[uint64]*Chunk)
+	for _, a := range allocs {
+		if a.Type != canonical {
+			continue
+		}
+		c, ok := byStart[a.ChunkStart]
+		if !ok {
+			c = &Chunk{Start: a.ChunkStart, Profile: a.Profile}
+			byStart[a.ChunkStart] = c
+			in.Chunks = append(in.Chunks, c)
+		}
+		c.Stripes = append(c.Stripes, Stripe{DevID: a.DevID, Offset: a.Offset, Length: a.Length})
+	}
+
+	if profile == "" {
+		bytesByProfile := make(map[string]uint64)
+		for _, c := range in.Chunks {
+			bytesByProfile[c.Profile] += c.logical()
+		}
+		var best uint64
+		for p, n := range bytesByProfile {
+			if n > best || (n == best && p < profile) {
+				profile, best = p, n
+			}
+		}
+		if profile == "" {
+			return nil, fmt.Errorf("no %s chunks found", strings.ToLower(canonical))
+		}
+	}
+
+	p, ok := LookupProfile(profile)
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q", profile)
+	}
+	in.Profile = p
+
+	return in, nil
+}
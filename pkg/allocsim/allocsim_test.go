@@ -0,0 +1,191 @@
+package allocsim
+
+import "testing"
+
+const gib = 1 << 30
+
+func mustProfile(t *testing.T, name string) Profile {
+	t.Helper()
+	p, ok := LookupProfile(name)
+	if !ok {
+		t.Fatalf("no profile %q", name)
+	}
+	return p
+}
+
+// mirrored returns n 1GiB chunks of profile with a stripe on each device,
+// packed from the start of every device
+func mirrored(profile string, n int, devids ...uint64) []*Chunk {
+	chunks := make([]*Chunk, n)
+	for i := range chunks {
+		c := &Chunk{Start: uint64(i+1) * gib, Profile: profile}
+		for _, id := range devids {
+			c.Stripes = append(c.Stripes, Stripe{DevID: id, Offset: uint64(i) * gib, Length: gib})
+		}
+		chunks[i] = c
+	}
+	return chunks
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		typ     string
+		devices []Device
+		chunks  []*Chunk
+
+		usable, stranded            uint64
+		balancedUsable, recoverable uint64
+		suggestions                 bool
+	}{
+		{
+			name:    "no devices",
+			profile: "single",
+			typ:     "Data",
+		},
+		{
+			name:    "one empty device",
+			profile: "single",
+			typ:     "Data",
+			devices: []Device{{DevID: 1, Size: 10 * gib}},
+			usable:  10 * gib, balancedUsable: 10 * gib,
+		},
+		{
+			name:    "full",
+			profile: "single",
+			typ:     "Data",
+			devices: []Device{{DevID: 1, Size: 10 * gib, Allocated: 10 * gib}},
+			chunks:  mirrored("single", 10, 1),
+		},
+		{
+			name:    "allocated beyond size",
+			profile: "single",
+			typ:     "Data",
+			devices: []Device{{DevID: 1, Size: 10 * gib, Allocated: 11 * gib}},
+		},
+		{
+			name:     "raid1 on one device",
+			profile:  "RAID1",
+			typ:      "Data",
+			devices:  []Device{{DevID: 1, Size: 10 * gib}},
+			stranded: 10 * gib,
+		},
+		{
+			name:    "lopsided raid1",
+			profile: "RAID1",
+			typ:     "Data",
+			devices: []Device{{DevID: 1, Size: 10 * gib}, {DevID: 2, Size: 2 * gib}},
+			usable:  2 * gib, stranded: 8 * gib, balancedUsable: 2 * gib,
+		},
+		{
+			name:    "dup halves the device",
+			profile: "DUP",
+			typ:     "Metadata",
+			devices: []Device{{DevID: 1, Size: 4 * gib}},
+			usable:  2 * gib, balancedUsable: 2 * gib,
+		},
+		{
+			// Two full devices and a new empty one: nothing fits until
+			// chunks move off the full pair
+			name:    "raid1 after adding a device",
+			profile: "RAID1",
+			typ:     "Data",
+			devices: []Device{
+				{DevID: 1, Size: 10 * gib, Allocated: 10 * gib},
+				{DevID: 2, Size: 10 * gib, Allocated: 10 * gib},
+				{DevID: 3, Size: 10 * gib},
+			},
+			chunks:   mirrored("RAID1", 10, 1, 2),
+			stranded: 10 * gib, balancedUsable: 5 * gib, recoverable: 5 * gib,
+			suggestions: true,
+		},
+		{
+			// A convert is needed, not a rebalance
+			name:    "mixed profiles get no suggestions",
+			profile: "RAID1",
+			typ:     "Data",
+			devices: []Device{
+				{DevID: 1, Size: 10 * gib, Allocated: 10 * gib},
+				{DevID: 2, Size: 10 * gib, Allocated: 10 * gib},
+				{DevID: 3, Size: 10 * gib},
+			},
+			chunks: append(mirrored("RAID1", 9, 1, 2), &Chunk{Start: 100 * gib, Profile: "single", Stripes: []Stripe{{DevID: 1, Offset: 9 * gib, Length: gib}}}),
+			// 29GiB free after a full balance holds 14GiB of raid1, 10 of
+			// it already in use
+			stranded: 10 * gib, balancedUsable: 4 * gib, recoverable: 4 * gib,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Analyze(&Input{
+				Type:    tt.typ,
+				Profile: mustProfile(t, tt.profile),
+				Devices: tt.devices,
+				Chunks:  tt.chunks,
+			})
+			if res.Usable != tt.usable {
+				t.Errorf("Usable = %d, want %d", res.Usable, tt.usable)
+			}
+			if res.Stranded != tt.stranded {
+				t.Errorf("Stranded = %d, want %d", res.Stranded, tt.stranded)
+			}
+			if res.BalancedUsable != tt.balancedUsable {
+				t.Errorf("BalancedUsable = %d, want %d", res.BalancedUsable, tt.balancedUsable)
+			}
+			if res.Recoverable != tt.recoverable {
+				t.Errorf("Recoverable = %d, want %d", res.Recoverable, tt.recoverable)
+			}
+			if len(res.Devices) != len(tt.devices) {
+				t.Errorf("got %d devices, want %d", len(res.Devices), len(tt.devices))
+			}
+			if got := len(res.Suggestions) > 0; got != tt.suggestions {
+				t.Fatalf("suggestions = %v, want %v", res.Suggestions, tt.suggestions)
+			}
+			for _, sg := range res.Suggestions {
+				if sg.Limit < 1 || sg.Limit > len(tt.chunks) {
+					t.Errorf("devid %d: limit %d out of range", sg.DevID, sg.Limit)
+				}
+				if sg.Recovers == 0 || sg.Usable != res.Usable+sg.Recovers {
+					t.Errorf("devid %d: recovers %d, usable %d", sg.DevID, sg.Recovers, sg.Usable)
+				}
+				if sg.DrangeEnd <= sg.DrangeStart || sg.DrangeChunks < sg.Limit {
+					t.Errorf("devid %d: drange %d..%d matching %d chunks", sg.DevID, sg.DrangeStart, sg.DrangeEnd, sg.DrangeChunks)
+				}
+			}
+		})
+	}
+}
+
+func TestDataStripes(t *testing.T) {
+	tests := []struct {
+		profile string
+		stripes int
+		want    int
+	}{
+		{"single", 1, 1},
+		{"DUP", 2, 1},
+		{"RAID0", 4, 4},
+		{"RAID1", 2, 1},
+		{"RAID1C3", 3, 1},
+		{"RAID10", 4, 2},
+		{"RAID5", 3, 2},
+		{"RAID6", 3, 1},
+		{"RAID6", 6, 4},
+	}
+	for _, tt := range tests {
+		if got := mustProfile(t, tt.profile).dataStripes(tt.stripes); got != tt.want {
+			t.Errorf("%s with %d stripes: %d data stripes, want %d", tt.profile, tt.stripes, got, tt.want)
+		}
+	}
+}
+
+func TestLookupProfile(t *testing.T) {
+	if p, ok := LookupProfile("raid1c3"); !ok || p.Name != "RAID1C3" {
+		t.Errorf("LookupProfile(raid1c3) = %v, %v", p, ok)
+	}
+	if _, ok := LookupProfile("raid7"); ok {
+		t.Error("LookupProfile(raid7) found a profile")
+	}
+}
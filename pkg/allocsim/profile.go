@@ -0,0 +1,42 @@
+package allocsim
+
+import "strings"
+
+// Profile describes how the kernel lays out chunks of one RAID profile
+// (btrfs_raid_array in fs/btrfs/volumes.c)
+type Profile struct {
+	Name          string
+	DevsMin       int // Fewest devices a chunk can be allocated on
+	DevsMax       int // Most devices a chunk is striped over (0 = all)
+	DevsIncrement int // Device count is rounded down to a multiple of this
+	DevStripes    int // Stripes per device (2 for DUP)
+	NCopies       int
+	NParity       int
+}
+
+var profiles = []Profile{
+	{Name: "single", DevsMin: 1, DevsMax: 1, DevsIncrement: 1, DevStripes: 1, NCopies: 1},
+	{Name: "DUP", DevsMin: 1, DevsMax: 1, DevsIncrement: 1, DevStripes: 2, NCopies: 2},
+	{Name: "RAID0", DevsMin: 1, DevsMax: 0, DevsIncrement: 1, DevStripes: 1, NCopies: 1},
+	{Name: "RAID1", DevsMin: 2, DevsMax: 2, DevsIncrement: 2, DevStripes: 1, NCopies: 2},
+	{Name: "RAID1C3", DevsMin: 3, DevsMax: 3, DevsIncrement: 3, DevStripes: 1, NCopies: 3},
+	{Name: "RAID1C4", DevsMin: 4, DevsMax: 4, DevsIncrement: 4, DevStripes: 1, NCopies: 4},
+	{Name: "RAID10", DevsMin: 2, DevsMax: 0, DevsIncrement: 2, DevStripes: 1, NCopies: 2},
+	{Name: "RAID5", DevsMin: 2, DevsMax: 0, DevsIncrement: 1, DevStripes: 1, NCopies: 1, NParity: 1},
+	{Name: "RAID6", DevsMin: 3, DevsMax: 0, DevsIncrement: 1, DevStripes: 1, NCopies: 1, NParity: 2},
+}
+
+// LookupProfile finds a profile by name, ignoring case
+func LookupProfile(name string) (Profile, bool) {
+	for _, p := range profiles {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// dataStripes is how many stripes of a chunk with numStripes stripes hold distinct data
+func (p Profile) dataStripes(numStripes int) int {
+	return (numStripes - p.NParity) / p.NCopies
+}
@@ -10,9 +10,14 @@ import (
 	"path/filepath"
 	"strings"
 
+	"connectrpc.com/connect"
 	"github.com/elee1766/gobtr/gen/api/v1/apiv1connect"
+	"github.com/elee1766/gobtr/pkg/audit"
+	"github.com/elee1766/gobtr/pkg/auth"
 	"github.com/elee1766/gobtr/pkg/config"
+	"github.com/elee1766/gobtr/pkg/db"
 	"github.com/elee1766/gobtr/pkg/handlers"
+	"github.com/elee1766/gobtr/pkg/metrics"
 	"go.uber.org/fx"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
@@ -35,27 +40,46 @@ var Module = fx.Module("api",
 		handlers.NewBalanceHandler,
 		handlers.NewSubvolumeHandler,
 		handlers.NewUsageHandler,
+		handlers.NewFragMapCache,
 		handlers.NewFragMapHandler,
+		handlers.NewForecastHandler,
+		handlers.NewDiagnosticsHandler,
+		handlers.NewDefragHandler,
+		handlers.NewSettingsHandler,
+		handlers.NewAuthHandler,
+		handlers.NewAuditHandler,
+		handlers.NewHubHandler,
+		handlers.NewSupportHandler,
+		metrics.NewCollector,
 	),
 	fx.Invoke(registerHooks),
 )
 
 type Server struct {
 	http   *http.Server
+	cfg    *config.Config
 	logger *slog.Logger
 }
 
 type HandlerParams struct {
 	fx.In
 
-	Health     *handlers.HealthHandler
-	Snapshot   *handlers.SnapshotHandler
-	Filesystem *handlers.FilesystemHandler
-	Scrub      *handlers.ScrubHandler
-	Balance    *handlers.BalanceHandler
-	Subvolume  *handlers.SubvolumeHandler
-	Usage      *handlers.UsageHandler
-	FragMap    *handlers.FragMapHandler
+	Health      *handlers.HealthHandler
+	Snapshot    *handlers.SnapshotHandler
+	Filesystem  *handlers.FilesystemHandler
+	Scrub       *handlers.ScrubHandler
+	Balance     *handlers.BalanceHandler
+	Subvolume   *handlers.SubvolumeHandler
+	Usage       *handlers.UsageHandler
+	FragMap     *handlers.FragMapHandler
+	Forecast    *handlers.ForecastHandler
+	Diagnostics *handlers.DiagnosticsHandler
+	Defrag      *handlers.DefragHandler
+	Settings    *handlers.SettingsHandler
+	Auth        *handlers.AuthHandler
+	Audit       *handlers.AuditHandler
+	Hub         *handlers.HubHandler
+	Support     *handlers.SupportHandler
 }
 
 type ServerParams struct {
@@ -64,6 +88,10 @@ type ServerParams struct {
 	Config   *config.Config
 	Logger   *slog.Logger
 	Handlers HandlerParams
+	Metrics  *metrics.Collector
+	Auth     *auth.Authenticator
+	AuditLog *audit.Log
+	DB       *db.DB
 }
 
 func NewServer(p ServerParams) *Server {
@@ -72,29 +100,68 @@ func NewServer(p ServerParams) *Server {
 
 	mux := http.NewServeMux()
 
-	// Register all Connect handlers
+	// Register all Connect handlers. Mutating calls are audited, refused in
+	// read-only mode, then checked against procedureRoles; with auth disabled
+	// everyone is an admin.
+	interceptors := []connect.Interceptor{p.AuditLog.Interceptor(audited)}
+	if p.Config.ReadOnly {
+		interceptors = append(interceptors, readOnlyInterceptor{})
+		logger.Info("read-only mode, mutating rpcs are refused")
+	}
+	authz := auth.NewInterceptor(procedureRoles)
+	interceptors = append(interceptors, authz)
+	opts := connect.WithInterceptors(interceptors...)
 	register := func(path string, handler http.Handler) {
 		mux.Handle(path, handler)
 	}
-	register(apiv1connect.NewHealthServiceHandler(h.Health))
-	register(apiv1connect.NewSnapshotServiceHandler(h.Snapshot))
-	register(apiv1connect.NewFilesystemServiceHandler(h.Filesystem))
-	register(apiv1connect.NewScrubServiceHandler(h.Scrub))
-	register(apiv1connect.NewBalanceServiceHandler(h.Balance))
-	register(apiv1connect.NewSubvolumeServiceHandler(h.Subvolume))
-	register(apiv1connect.NewUsageServiceHandler(h.Usage))
-	register(apiv1connect.NewFragMapServiceHandler(h.FragMap))
-
-	// Register pprof handlers for profiling
-	mux.HandleFunc("/debug/pprof/", pprof.Index)
-	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
-	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
-	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
-	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
-	logger.Info("pprof endpoints enabled at /debug/pprof/")
-
-	// Serve static files with SPA fallback
-	if EmbeddedFS != nil {
+	register(apiv1connect.NewHealthServiceHandler(h.Health, opts))
+	register(apiv1connect.NewSnapshotServiceHandler(h.Snapshot, opts))
+	register(apiv1connect.NewFilesystemServiceHandler(h.Filesystem, opts))
+	register(apiv1connect.NewScrubServiceHandler(h.Scrub, opts))
+	register(apiv1connect.NewBalanceServiceHandler(h.Balance, opts))
+	register(apiv1connect.NewSubvolumeServiceHandler(h.Subvolume, opts))
+	register(apiv1connect.NewUsageServiceHandler(h.Usage, opts))
+	register(apiv1connect.NewFragMapServiceHandler(h.FragMap, opts))
+	register(apiv1connect.NewForecastServiceHandler(h.Forecast, opts))
+	register(apiv1connect.NewDiagnosticsServiceHandler(h.Diagnostics, opts))
+	register(apiv1connect.NewDefragServiceHandler(h.Defrag, opts))
+	register(apiv1connect.NewSettingsServiceHandler(h.Settings, opts))
+	register(apiv1connect.NewAuthServiceHandler(h.Auth, opts))
+	register(apiv1connect.NewAuditServiceHandler(h.Audit, opts))
+	register(apiv1connect.NewHubServiceHandler(h.Hub, opts))
+	register(apiv1connect.NewSupportServiceHandler(h.Support, opts))
+
+	// Plain REST routes onto the same handlers, and their OpenAPI document
+	mux.Handle(restPrefix+"/", newRESTHandler(p.DB, authz, mux, openAPIHandler()))
+
+	// Audit log as JSON lines, same filters as ListAuditEvents
+	mux.Handle("/audit/export", p.Auth.Require(auth.RoleOperator, p.AuditLog.ExportHandler()))
+
+	// Fragmap images for tickets and reports
+	mux.Handle("/render/fragmap", p.Auth.Require(auth.RoleViewer, h.FragMap.RenderHandler()))
+
+	// Prometheus metrics
+	mux.Handle("/metrics", p.Auth.Require(auth.RoleViewer, p.Metrics.Handler()))
+	logger.Info("metrics endpoint enabled at /metrics")
+
+	// Support bundles for bug reports; they hold the config, so admin only
+	mux.Handle("/support/bundle", p.Auth.Require(auth.RoleAdmin, h.Support.BundleHandler()))
+
+	// pprof handlers for profiling, only when asked for
+	if p.Config.Pprof {
+		admin := func(h http.HandlerFunc) http.Handler { return p.Auth.Require(auth.RoleAdmin, h) }
+		mux.Handle("/debug/pprof/", admin(pprof.Index))
+		mux.Handle("/debug/pprof/cmdline", admin(pprof.Cmdline))
+		mux.Handle("/debug/pprof/profile", admin(pprof.Profile))
+		mux.Handle("/debug/pprof/symbol", admin(pprof.Symbol))
+		mux.Handle("/debug/pprof/trace", admin(pprof.Trace))
+		logger.Info("pprof endpoints enabled at /debug/pprof/")
+	}
+
+	// Serve static files with SPA fallback, unless headless
+	if p.Config.Headless {
+		logger.Info("headless, not serving the web ui")
+	} else if EmbeddedFS != nil {
 		logger.Info("serving frontend from embedded filesystem")
 		mux.Handle("/", spaHandlerFS(EmbeddedFS))
 	} else {
@@ -102,14 +169,16 @@ func NewServer(p ServerParams) *Server {
 		mux.Handle("/", spaHandlerDir(StaticDir))
 	}
 
-	// Use h2c for HTTP/2 without TLS
-	h2cHandler := h2c.NewHandler(mux, &http2.Server{})
+	// Use h2c for HTTP/2 without TLS; TLS listeners negotiate h2 themselves
+	h2cHandler := h2c.NewHandler(p.Auth.Middleware(mux), &http2.Server{})
 
 	return &Server{
 		http: &http.Server{
-			Addr:    p.Config.APIAddress,
 			Handler: h2cHandler,
+			// Lets auth see unix socket peers
+			ConnContext: auth.ConnContext,
 		},
+		cfg:    p.Config,
 		logger: logger,
 	}
 }
@@ -173,12 +242,20 @@ func spaHandlerFS(fsys fs.FS) http.Handler {
 func registerHooks(lc fx.Lifecycle, s *Server) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			go func() {
-				s.logger.Info("starting api server", "address", s.http.Addr)
-				if err := s.http.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-					s.logger.Error("api server error", "error", err)
-				}
-			}()
+			// Listen before returning so a taken port or bad certificate
+			// fails startup instead of leaving a server that serves nothing
+			listeners, err := s.listen(s.cfg)
+			if err != nil {
+				return err
+			}
+			s.logger.Info("starting api server")
+			for _, l := range listeners {
+				go func() {
+					if err := s.http.Serve(l); err != nil && err != http.ErrServerClosed {
+						s.logger.Error("api server error", "address", l.Addr().String(), "error", err)
+					}
+				}()
+			}
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+
+	"github.com/elee1766/gobtr/pkg/config"
+)
+
+// listenFDsStart is the first file descriptor systemd passes
+const listenFDsStart = 3
+
+// listen opens the listeners to serve on: the sockets systemd passed if
+// started by socket activation, otherwise the TCP address and the unix
+// socket from the config. TCP listeners get TLS when it is configured;
+// unix sockets never do.
+func (s *Server) listen(cfg *config.Config) ([]net.Listener, error) {
+	var tlsConfig *tls.Config
+	if cfg.TLS.Enabled() {
+		reloader, err := newTLSReloader(s.logger, cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig = reloader.Config()
+	}
+	wrap := func(l net.Listener) net.Listener {
+		if tlsConfig != nil && l.Addr().Network() == "tcp" {
+			return tls.NewListener(l, tlsConfig)
+		}
+		return l
+	}
+
+	activated, err := systemdListeners()
+	if err != nil {
+		return nil, fmt.Errorf("socket activation: %w", err)
+	}
+	if len(activated) > 0 {
+		listeners := make([]net.Listener, len(activated))
+		for i, l := range activated {
+			listeners[i] = wrap(l)
+			s.logger.Info("using socket from systemd", "network", l.Addr().Network(), "address", l.Addr().String(), "tls", listeners[i] != l)
+		}
+		return listeners, nil
+	}
+
+	var listeners []net.Listener
+	closeAll := func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}
+	if cfg.APIAddress != "none" {
+		l, err := net.Listen("tcp", cfg.APIAddress)
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, wrap(l))
+		s.logger.Info("listening", "address", l.Addr().String(), "tls", tlsConfig != nil)
+	}
+	if cfg.Socket.Path != "" {
+		l, err := listenUnix(cfg.Socket)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		listeners = append(listeners, l)
+		s.logger.Info("listening on unix socket", "path", cfg.Socket.Path, "role", cfg.Socket.Role)
+	}
+	if len(listeners) == 0 {
+		return nil, errors.New("nothing to listen on: listen is \"none\" and there is no socket")
+	}
+	return listeners, nil
+}
+
+// systemdListeners returns the sockets passed by systemd socket activation,
+// or nil if the process wasn't socket activated
+func systemdListeners() ([]net.Listener, error) {
+	if os.Getenv("LISTEN_PID") != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return nil, nil
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	// Children must not think the sockets are theirs
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	listeners := make([]net.Listener, 0, n)
+	for i := range n {
+		name := "LISTEN_FD_" + strconv.Itoa(listenFDsStart+i)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		f := os.NewFile(uintptr(listenFDsStart+i), name)
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+// listenUnix listens on a unix socket with the configured mode and group.
+// A stale socket from a previous run is removed first; any other file at
+// the path is an error.
+func listenUnix(cfg config.SocketFile) (net.Listener, error) {
+	mode, err := cfg.FileMode()
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Lstat(cfg.Path); err == nil {
+		if info.Mode().Type() != fs.ModeSocket {
+			return nil, fmt.Errorf("%s exists and is not a socket", cfg.Path)
+		}
+		if err := os.Remove(cfg.Path); err != nil {
+			return nil, err
+		}
+	}
+
+	l, err := net.Listen("unix", cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(cfg.Path, mode); err != nil {
+		l.Close()
+		return nil, err
+	}
+	if cfg.Group != "" {
+		gid, err := lookupGroup(cfg.Group)
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+		if err := os.Chown(cfg.Path, -1, gid); err != nil {
+			l.Close()
+			return nil, fmt.Errorf("chown %s: %w", cfg.Path, err)
+		}
+	}
+	return l, nil
+}
+
+// lookupGroup resolves a group name or numeric id
+func lookupGroup(group string) (int, error) {
+	if gid, err := strconv.Atoi(group); err == nil {
+		return gid, nil
+	}
+	g, err := user.LookupGroup(group)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(g.Gid)
+}
@@ -0,0 +1,212 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// openAPI describes routes as an OpenAPI 3 document. Schemas follow the
+// protobuf JSON mapping the routes speak: camelCase names, 64-bit integers
+// as strings and enums by name.
+func openAPI() map[string]any {
+	schemas := map[string]any{
+		"Error": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"code":    map[string]any{"type": "string", "description": "Connect error code, e.g. not_found"},
+				"message": map[string]any{"type": "string"},
+			},
+		},
+	}
+	paths := map[string]map[string]any{}
+	operationIDs := map[string]int{}
+	for _, rt := range routes {
+		operationIDs[string(methodFor(rt).Name())]++
+	}
+
+	for _, rt := range routes {
+		md := methodFor(rt)
+		addSchema(schemas, md.Input())
+		addSchema(schemas, md.Output())
+
+		operationID := string(md.Name())
+		if operationIDs[operationID] > 1 {
+			operationID += strings.ToUpper(rt.method[:1]) + strings.ToLower(rt.method[1:])
+		}
+		op := map[string]any{
+			"operationId": operationID,
+			"summary":     rt.summary,
+			"tags":        []string{string(md.Parent().Name())},
+			"responses": map[string]any{
+				"200": map[string]any{
+					"description": "OK",
+					"content":     jsonContent(md.Output()),
+				},
+				"default": map[string]any{
+					"description": "Error",
+					"content": map[string]any{
+						"application/json": map[string]any{"schema": ref("Error")},
+					},
+				},
+			},
+		}
+
+		fields := md.Input().Fields()
+		fromPath := map[protoreflect.Name]bool{}
+		var params []any
+		for _, name := range pathParams(rt.path) {
+			param := map[string]any{
+				"name":     name,
+				"in":       "path",
+				"required": true,
+				"schema":   map[string]any{"type": "string"},
+			}
+			if name == "uuid" {
+				param["description"] = "Tracked filesystem UUID"
+				fromPath[protoreflect.Name(rt.fsField)] = true
+			} else {
+				fromPath[protoreflect.Name(name)] = true
+			}
+			params = append(params, param)
+		}
+		if rt.method == "GET" || rt.method == "DELETE" {
+			for i := 0; i < fields.Len(); i++ {
+				fd := fields.Get(i)
+				if fromPath[fd.Name()] || !rt.takes(fd) {
+					continue
+				}
+				params = append(params, map[string]any{
+					"name":   fd.JSONName(),
+					"in":     "query",
+					"schema": fieldSchema(fd),
+				})
+			}
+		} else {
+			op["requestBody"] = map[string]any{"content": jsonContent(md.Input())}
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+
+		path := restPrefix + rt.path
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		paths[path][strings.ToLower(rt.method)] = op
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "gobtr",
+			"version":     "v1",
+			"description": "REST routes onto the gobtr Connect API. Authenticate with a bearer token or session cookie, like the RPCs.",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"bearer": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+		"security": []any{map[string]any{"bearer": []string{}}},
+	}
+}
+
+// openAPIHandler serves the document, built once. It only describes the
+// API, so anyone may read it.
+func openAPIHandler() http.Handler {
+	doc, err := json.MarshalIndent(openAPI(), "", "  ")
+	if err != nil {
+		panic(fmt.Sprintf("openapi: %v", err))
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(doc)
+	})
+}
+
+func ref(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+func schemaName(md protoreflect.MessageDescriptor) string {
+	return string(md.Name())
+}
+
+func jsonContent(md protoreflect.MessageDescriptor) map[string]any {
+	return map[string]any{
+		"application/json": map[string]any{"schema": ref(schemaName(md))},
+	}
+}
+
+// addSchema adds md and every message it uses to schemas
+func addSchema(schemas map[string]any, md protoreflect.MessageDescriptor) {
+	name := schemaName(md)
+	if _, ok := schemas[name]; ok {
+		return
+	}
+	props := map[string]any{}
+	schema := map[string]any{"type": "object", "properties": props}
+	// Set before recursing, so messages that refer to themselves end
+	schemas[name] = schema
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		props[fd.JSONName()] = fieldSchema(fd)
+		if fd.IsMap() {
+			if v := fd.MapValue(); v.Kind() == protoreflect.MessageKind {
+				addSchema(schemas, v.Message())
+			}
+		} else if fd.Kind() == protoreflect.MessageKind {
+			addSchema(schemas, fd.Message())
+		}
+	}
+}
+
+func fieldSchema(fd protoreflect.FieldDescriptor) map[string]any {
+	if fd.IsMap() {
+		return map[string]any{"type": "object", "additionalProperties": scalarSchema(fd.MapValue())}
+	}
+	if fd.IsList() {
+		return map[string]any{"type": "array", "items": scalarSchema(fd)}
+	}
+	return scalarSchema(fd)
+}
+
+// scalarSchema is the schema of one value of fd, ignoring repetition
+func scalarSchema(fd protoreflect.FieldDescriptor) map[string]any {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]any{"type": "integer", "format": "int64", "minimum": 0}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return map[string]any{"type": "string", "format": "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]any{"type": "string", "format": "uint64"}
+	case protoreflect.FloatKind:
+		return map[string]any{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]any{"type": "number", "format": "double"}
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		var names []string
+		values := fd.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		return map[string]any{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return ref(schemaName(fd.Message()))
+	}
+	return map[string]any{"type": "string"}
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+)
+
+// readOnlyInterceptor refuses every mutating call, streams included. It
+// runs inside the audit interceptor so refusals are recorded, and before
+// auth so even admins get the same answer.
+type readOnlyInterceptor struct{}
+
+func refuse(procedure string) error {
+	return connect.NewError(connect.CodePermissionDenied, fmt.Errorf("server is read-only: %s is refused", procedure))
+}
+
+func (readOnlyInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		procedure := req.Spec().Procedure
+		if mutating(procedure, req.Any()) {
+			return nil, refuse(procedure)
+		}
+		return next(ctx, req)
+	}
+}
+
+func (readOnlyInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (readOnlyInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		procedure := conn.Spec().Procedure
+		if mutating(procedure, nil) {
+			return refuse(procedure)
+		}
+		return next(ctx, &readOnlyConn{StreamingHandlerConn: conn})
+	}
+}
+
+// readOnlyConn refuses requests that make a stream mutating, for calls
+// where that depends on the request
+type readOnlyConn struct {
+	connect.StreamingHandlerConn
+}
+
+func (c *readOnlyConn) Receive(msg any) error {
+	if err := c.StreamingHandlerConn.Receive(msg); err != nil {
+		return err
+	}
+	if procedure := c.Spec().Procedure; mutating(procedure, msg) {
+		return refuse(procedure)
+	}
+	return nil
+}
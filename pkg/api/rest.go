@@ -0,0 +1,355 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/elee1766/gobtr/gen/api/v1/apiv1connect"
+	"github.com/elee1766/gobtr/pkg/auth"
+	"github.com/elee1766/gobtr/pkg/db"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// restPrefix is where the REST routes live
+const restPrefix = "/api/v1"
+
+// route maps a REST endpoint onto a Connect procedure, so tools that only
+// speak HTTP and JSON get the same handlers, roles, read-only mode and audit
+// log as the RPC clients. The request message is built from the JSON body,
+// then the query string, then the path: {uuid} is a tracked filesystem and
+// goes in fsField as its mount path (or its id, for int64 fields), any other
+// path value goes in the field of the same name.
+type route struct {
+	method    string
+	path      string // Under restPrefix
+	procedure string
+	fsField   string
+	summary   string
+}
+
+var routes = []route{
+	{"GET", "/health", apiv1connect.HealthServiceCheckProcedure, "", "Check the server is up"},
+
+	{"GET", "/filesystems", apiv1connect.FilesystemServiceListTrackedFilesystemsProcedure, "", "List tracked filesystems"},
+	{"POST", "/filesystems", apiv1connect.FilesystemServiceAddFilesystemProcedure, "", "Track a filesystem"},
+	{"PATCH", "/filesystems/{uuid}", apiv1connect.FilesystemServiceUpdateFilesystemProcedure, "id", "Change a filesystem's label or btrbk snapshot dir"},
+	{"DELETE", "/filesystems/{uuid}", apiv1connect.FilesystemServiceRemoveFilesystemProcedure, "id", "Stop tracking a filesystem"},
+	{"GET", "/filesystems/usage", apiv1connect.FilesystemServiceGetAllFilesystemUsageProcedure, "", "Usage of every tracked filesystem"},
+	{"GET", "/filesystems/device-stats", apiv1connect.FilesystemServiceGetAllDeviceStatsProcedure, "", "Device stats of every tracked filesystem"},
+	{"GET", "/filesystems/errors", apiv1connect.FilesystemServiceGetAllErrorsProcedure, "", "Recorded errors of every tracked filesystem"},
+	{"GET", "/filesystems/scrub", apiv1connect.ScrubServiceGetAllScrubStatusProcedure, "", "Scrub status of every tracked filesystem"},
+	{"GET", "/filesystems/balance", apiv1connect.BalanceServiceGetAllBalanceStatusProcedure, "", "Balance status of every tracked filesystem"},
+	{"GET", "/filesystems/diagnostics", apiv1connect.DiagnosticsServiceRunAllDiagnosticsProcedure, "", "Diagnose every tracked filesystem"},
+	{"GET", "/filesystems/forecast", apiv1connect.ForecastServiceGetAllUsageForecastsProcedure, "", "Usage forecast of every tracked filesystem"},
+
+	{"GET", "/filesystems/{uuid}/usage", apiv1connect.FilesystemServiceGetFilesystemUsageProcedure, "device_path", "Filesystem usage"},
+	{"GET", "/filesystems/{uuid}/usage/history", apiv1connect.ForecastServiceGetUsageHistoryProcedure, "device_path", "Recorded usage history"},
+	{"GET", "/filesystems/{uuid}/forecast", apiv1connect.ForecastServiceGetUsageForecastProcedure, "device_path", "When the filesystem will fill up"},
+	{"GET", "/filesystems/{uuid}/device-stats", apiv1connect.FilesystemServiceGetDeviceStatsProcedure, "device_path", "Device error counters and sizes"},
+	{"GET", "/filesystems/{uuid}/diagnostics", apiv1connect.DiagnosticsServiceRunDiagnosticsProcedure, "device_path", "Diagnose the filesystem"},
+	{"GET", "/filesystems/{uuid}/subvolumes", apiv1connect.SubvolumeServiceListSubvolumesProcedure, "mount_path", "List subvolumes"},
+	{"GET", "/filesystems/{uuid}/free-space", apiv1connect.FragMapServiceGetFreeSpaceStatsProcedure, "fs_path", "Free space fragmentation"},
+
+	{"GET", "/filesystems/{uuid}/scrub", apiv1connect.ScrubServiceGetScrubStatusProcedure, "device_path", "Scrub status"},
+	{"POST", "/filesystems/{uuid}/scrub", apiv1connect.ScrubServiceStartScrubProcedure, "device_path", "Start a scrub"},
+	{"DELETE", "/filesystems/{uuid}/scrub", apiv1connect.ScrubServiceCancelScrubProcedure, "device_path", "Cancel the running scrub"},
+
+	{"GET", "/filesystems/{uuid}/balance", apiv1connect.BalanceServiceGetBalanceStatusProcedure, "device_path", "Balance status"},
+	{"POST", "/filesystems/{uuid}/balance", apiv1connect.BalanceServiceStartBalanceProcedure, "device_path", "Start a balance"},
+	{"DELETE", "/filesystems/{uuid}/balance", apiv1connect.BalanceServiceCancelBalanceProcedure, "device_path", "Cancel the running balance"},
+	{"GET", "/filesystems/{uuid}/balance/history", apiv1connect.BalanceServiceListBalanceHistoryProcedure, "device_path", "Balances run by gobtr"},
+	{"GET", "/filesystems/{uuid}/balance/plan", apiv1connect.BalanceServicePlanBalanceProcedure, "device_path", "Plan a balance to free unallocated space"},
+	{"POST", "/filesystems/{uuid}/balance/plan", apiv1connect.BalanceServicePlanBalanceProcedure, "device_path", "Plan a balance, and start it with start=true"},
+	{"GET", "/filesystems/{uuid}/profiles", apiv1connect.BalanceServiceGetProfileStatusProcedure, "device_path", "RAID profiles in use and interrupted conversions"},
+	{"GET", "/filesystems/{uuid}/allocation", apiv1connect.BalanceServiceAnalyzeAllocationProcedure, "device_path", "Simulate chunk allocation to find stranded space"},
+
+	{"GET", "/filesystems/{uuid}/sampling", apiv1connect.UsageServiceGetSamplingStatusProcedure, "fs_path", "Usage sampler status"},
+	{"POST", "/filesystems/{uuid}/sampling", apiv1connect.UsageServiceStartSamplingProcedure, "fs_path", "Start the usage sampler"},
+	{"DELETE", "/filesystems/{uuid}/sampling", apiv1connect.UsageServiceStopSamplingProcedure, "fs_path", "Stop the usage sampler"},
+	{"GET", "/filesystems/{uuid}/usage-tree", apiv1connect.UsageServiceGetUsageTreeProcedure, "fs_path", "Sampled disk usage by path"},
+
+	{"GET", "/defrag", apiv1connect.DefragServiceListDefragJobsProcedure, "", "List defrag jobs"},
+	{"POST", "/defrag", apiv1connect.DefragServiceStartDefragProcedure, "", "Start a defrag job"},
+	{"GET", "/defrag/{job_id}", apiv1connect.DefragServiceGetDefragStatusProcedure, "", "Defrag job status"},
+	{"DELETE", "/defrag/{job_id}", apiv1connect.DefragServiceCancelDefragProcedure, "", "Cancel a defrag job"},
+
+	{"GET", "/agents", apiv1connect.HubServiceListAgentsProcedure, "", "Hub agents and their health"},
+	{"GET", "/hosts/filesystems", apiv1connect.HubServiceListHostFilesystemsProcedure, "", "Tracked filesystems of every host"},
+	{"GET", "/hosts/usage", apiv1connect.HubServiceGetHostFilesystemUsageProcedure, "", "Filesystem usage of every host"},
+	{"GET", "/hosts/scrub", apiv1connect.HubServiceGetHostScrubStatusProcedure, "", "Scrub status of every host"},
+	{"GET", "/hosts/balance", apiv1connect.HubServiceGetHostBalanceStatusProcedure, "", "Balance status of every host"},
+	{"GET", "/hosts/diagnostics", apiv1connect.HubServiceGetHostDiagnosticsProcedure, "", "Diagnostics of every host"},
+
+	{"GET", "/settings", apiv1connect.SettingsServiceGetSettingsProcedure, "", "Server settings"},
+	{"PATCH", "/settings", apiv1connect.SettingsServiceUpdateSettingsProcedure, "", "Change server settings"},
+	{"GET", "/audit", apiv1connect.AuditServiceListAuditEventsProcedure, "", "Audit log"},
+}
+
+// startFields are request fields that make a read start something. GET
+// routes don't take them, so a followed link or a prefetch can't change
+// anything; the POST route for the same procedure does.
+var startFields = map[string][]protoreflect.Name{
+	apiv1connect.BalanceServicePlanBalanceProcedure: {"start", "limit_percent"},
+}
+
+// takes reports whether rt accepts fd from the query string
+func (rt route) takes(fd protoreflect.FieldDescriptor) bool {
+	if !queryable(fd) {
+		return false
+	}
+	if rt.method == http.MethodGet {
+		for _, name := range startFields[rt.procedure] {
+			if fd.Name() == name {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// restHandler serves routes by turning each request into a Connect JSON
+// call to rpc, which holds the Connect handlers
+type restHandler struct {
+	db     *db.DB
+	authz  *auth.Interceptor
+	rpc    http.Handler
+	errors *connect.ErrorWriter
+}
+
+func newRESTHandler(db *db.DB, authz *auth.Interceptor, rpc http.Handler, openapi http.Handler) http.Handler {
+	h := &restHandler{
+		db:     db,
+		authz:  authz,
+		rpc:    rpc,
+		errors: connect.NewErrorWriter(),
+	}
+	mux := http.NewServeMux()
+	for _, rt := range routes {
+		method := methodFor(rt)
+		mux.HandleFunc(rt.method+" "+restPrefix+rt.path, func(w http.ResponseWriter, r *http.Request) {
+			h.serve(w, r, rt, method)
+		})
+	}
+	mux.Handle("GET "+restPrefix+"/openapi.json", openapi)
+	return mux
+}
+
+// methodFor finds the descriptor of a route's procedure and checks the route
+// only fills fields the request has. Routes are fixed at build time, so a bad
+// one is a bug.
+func methodFor(rt route) protoreflect.MethodDescriptor {
+	md, err := findMethod(rt.procedure)
+	if err != nil {
+		panic(fmt.Sprintf("rest route %s %s: %v", rt.method, rt.path, err))
+	}
+	fields := md.Input().Fields()
+	for _, name := range pathParams(rt.path) {
+		if name == "uuid" {
+			name = rt.fsField
+		}
+		if fields.ByName(protoreflect.Name(name)) == nil {
+			panic(fmt.Sprintf("rest route %s %s: %s has no field %q", rt.method, rt.path, md.Input().FullName(), name))
+		}
+	}
+	return md
+}
+
+// findMethod finds the descriptor of a unary procedure
+func findMethod(procedure string) (protoreflect.MethodDescriptor, error) {
+	service, name, _ := strings.Cut(strings.TrimPrefix(procedure, "/"), "/")
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, err
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", service)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(name))
+	if md == nil {
+		return nil, fmt.Errorf("%s has no method %s", service, name)
+	}
+	if md.IsStreamingClient() || md.IsStreamingServer() {
+		return nil, fmt.Errorf("%s is streaming", procedure)
+	}
+	return md, nil
+}
+
+func (h *restHandler) serve(w http.ResponseWriter, r *http.Request, rt route, method protoreflect.MethodDescriptor) {
+	// Roles are left to the interceptors, like for any other call, so
+	// denials get audited the same way
+	body, err := h.buildRequest(r, rt, method)
+	if err != nil {
+		h.errors.Write(w, r, err)
+		return
+	}
+
+	call := r.Clone(r.Context())
+	call.Method = http.MethodPost
+	call.URL.Path = rt.procedure
+	call.URL.RawPath = ""
+	call.URL.RawQuery = ""
+	call.RequestURI = ""
+	call.Header.Set("Content-Type", "application/json")
+	call.Header.Del("Content-Encoding")
+	call.Body = io.NopCloser(bytes.NewReader(body))
+	call.ContentLength = int64(len(body))
+	h.rpc.ServeHTTP(w, call)
+}
+
+// buildRequest returns the JSON for the procedure's request message
+func (h *restHandler) buildRequest(r *http.Request, rt route, method protoreflect.MethodDescriptor) ([]byte, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(method.Input().FullName())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	msg := mt.New().Interface()
+	fields := method.Input().Fields()
+
+	if r.Body != nil && r.Method != http.MethodGet {
+		data, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("read body: %w", err))
+		}
+		if len(bytes.TrimSpace(data)) > 0 {
+			if err := protojson.Unmarshal(data, msg); err != nil {
+				return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("body: %w", err))
+			}
+		}
+	}
+
+	for key, values := range r.URL.Query() {
+		fd := fields.ByJSONName(key)
+		if fd == nil {
+			fd = fields.ByName(protoreflect.Name(key))
+		}
+		if fd == nil || !rt.takes(fd) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown query parameter %q", key))
+		}
+		if err := setField(msg.ProtoReflect(), fd, values); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+
+	for _, name := range pathParams(rt.path) {
+		value := r.PathValue(name)
+		if name == "uuid" {
+			fd := fields.ByName(protoreflect.Name(rt.fsField))
+			fs, err := h.db.GetFilesystemByUUID(value)
+			if errors.Is(err, sql.ErrNoRows) {
+				// Deny first, so a caller who couldn't make the call can't
+				// tell which filesystems exist either
+				if role := h.authz.RequiredRole(rt.procedure); role != auth.RoleNone {
+					if err := auth.RequireRole(r.Context(), role, rt.procedure); err != nil {
+						return nil, err
+					}
+				}
+				return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no tracked filesystem with uuid %s", value))
+			}
+			if err != nil {
+				return nil, connect.NewError(connect.CodeInternal, err)
+			}
+			if fd.Kind() == protoreflect.Int64Kind {
+				msg.ProtoReflect().Set(fd, protoreflect.ValueOfInt64(fs.ID))
+			} else {
+				msg.ProtoReflect().Set(fd, protoreflect.ValueOfString(fs.Path))
+			}
+			continue
+		}
+		if err := setField(msg.ProtoReflect(), fields.ByName(protoreflect.Name(name)), []string{value}); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	// Whatever a GET route is given, it must stay a read
+	if rt.method == http.MethodGet && mutating(rt.procedure, msg) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("GET %s%s can't change anything, use POST", restPrefix, rt.path))
+	}
+
+	return protojson.Marshal(msg)
+}
+
+// pathParams returns the names of the {values} in a route path
+func pathParams(path string) []string {
+	var names []string
+	for _, seg := range strings.Split(path, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			names = append(names, seg[1:len(seg)-1])
+		}
+	}
+	return names
+}
+
+// queryable reports whether a field can be given in the query string:
+// scalars, enums and lists of them
+func queryable(fd protoreflect.FieldDescriptor) bool {
+	return !fd.IsMap() && fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind
+}
+
+// setField parses values into fd, appending them for lists
+func setField(m protoreflect.Message, fd protoreflect.FieldDescriptor, values []string) error {
+	if !fd.IsList() && len(values) > 1 {
+		return fmt.Errorf("given more than once")
+	}
+	for _, s := range values {
+		v, err := parseScalar(fd, s)
+		if err != nil {
+			return err
+		}
+		if fd.IsList() {
+			m.Mutable(fd).List().Append(v)
+		} else {
+			m.Set(fd, v)
+		}
+	}
+	return nil
+}
+
+func parseScalar(fd protoreflect.FieldDescriptor, s string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		return protoreflect.ValueOfBool(b), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(s, 10, 32)
+		return protoreflect.ValueOfInt32(int32(n)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(s, 10, 64)
+		return protoreflect.ValueOfInt64(n), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(s, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(n)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(s, 10, 64)
+		return protoreflect.ValueOfUint64(n), err
+	case protoreflect.FloatKind:
+		n, err := strconv.ParseFloat(s, 32)
+		return protoreflect.ValueOfFloat32(float32(n)), err
+	case protoreflect.DoubleKind:
+		n, err := strconv.ParseFloat(s, 64)
+		return protoreflect.ValueOfFloat64(n), err
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(s)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("unknown value %q", s)
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(s)), nil
+	}
+	return protoreflect.Value{}, fmt.Errorf("can't be given as text")
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	apiv1 "github.com/elee1766/gobtr/gen/api/v1"
+	"github.com/elee1766/gobtr/gen/api/v1/apiv1connect"
+	"github.com/elee1766/gobtr/pkg/auth"
+)
+
+// procedureRoles is the role each RPC requires when auth is enabled. Reads
+// need viewer, anything that starts, stops or changes something needs
+// operator, and server settings and users need admin. RPCs missing here
+// need admin, so add new ones.
+var procedureRoles = map[string]auth.Role{
+	// Anyone, so the UI can show a login form and load balancers can probe
+	apiv1connect.HealthServiceCheckProcedure:    auth.RoleNone,
+	apiv1connect.AuthServiceGetSessionProcedure: auth.RoleNone,
+	apiv1connect.AuthServiceLoginProcedure:      auth.RoleNone,
+	apiv1connect.AuthServiceLogoutProcedure:     auth.RoleNone,
+
+	apiv1connect.AuthServiceListUsersProcedure:          auth.RoleAdmin,
+	apiv1connect.AuthServiceCreateUserProcedure:         auth.RoleAdmin,
+	apiv1connect.AuthServiceUpdateUserProcedure:         auth.RoleAdmin,
+	apiv1connect.AuthServiceDeleteUserProcedure:         auth.RoleAdmin,
+	apiv1connect.AuditServiceListAuditEventsProcedure:   auth.RoleOperator,
+	apiv1connect.SettingsServiceGetSettingsProcedure:    auth.RoleViewer,
+	apiv1connect.SettingsServiceUpdateSettingsProcedure: auth.RoleAdmin,
+
+	// Holds the config, if redacted
+	apiv1connect.SupportServiceCreateSupportBundleProcedure: auth.RoleAdmin,
+
+	apiv1connect.HubServiceListAgentsProcedure:             auth.RoleViewer,
+	apiv1connect.HubServiceListHostFilesystemsProcedure:    auth.RoleViewer,
+	apiv1connect.HubServiceGetHostScrubStatusProcedure:     auth.RoleViewer,
+	apiv1connect.HubServiceGetHostBalanceStatusProcedure:   auth.RoleViewer,
+	apiv1connect.HubServiceGetHostFilesystemUsageProcedure: auth.RoleViewer,
+	apiv1connect.HubServiceGetHostDiagnosticsProcedure:     auth.RoleViewer,
+
+	apiv1connect.BalanceServiceStartBalanceProcedure:        auth.RoleOperator,
+	apiv1connect.BalanceServiceCancelBalanceProcedure:       auth.RoleOperator,
+	apiv1connect.BalanceServiceFinishConversionProcedure:    auth.RoleOperator,
+	apiv1connect.BalanceServiceGetBalanceStatusProcedure:    auth.RoleViewer,
+	apiv1connect.BalanceServiceGetAllBalanceStatusProcedure: auth.RoleViewer,
+	apiv1connect.BalanceServiceListBalanceHistoryProcedure:  auth.RoleViewer,
+	apiv1connect.BalanceServiceGetProfileStatusProcedure:    auth.RoleViewer,
+	apiv1connect.BalanceServiceAnalyzeAllocationProcedure:   auth.RoleViewer,
+	apiv1connect.BalanceServicePlanBalanceProcedure:         auth.RoleViewer,
+
+	apiv1connect.DefragServiceStartDefragProcedure:          auth.RoleOperator,
+	apiv1connect.DefragServiceCancelDefragProcedure:         auth.RoleOperator,
+	apiv1connect.DefragServiceGetDefragStatusProcedure:      auth.RoleViewer,
+	apiv1connect.DefragServiceListDefragJobsProcedure:       auth.RoleViewer,
+	apiv1connect.DefragServiceStreamDefragProgressProcedure: auth.RoleViewer,
+
+	apiv1connect.DiagnosticsServiceRunDiagnosticsProcedure:    auth.RoleViewer,
+	apiv1connect.DiagnosticsServiceRunAllDiagnosticsProcedure: auth.RoleViewer,
+
+	apiv1connect.FilesystemServiceAddFilesystemProcedure:          auth.RoleOperator,
+	apiv1connect.FilesystemServiceRemoveFilesystemProcedure:       auth.RoleOperator,
+	apiv1connect.FilesystemServiceUpdateFilesystemProcedure:       auth.RoleOperator,
+	apiv1connect.FilesystemServiceListTrackedFilesystemsProcedure: auth.RoleViewer,
+	apiv1connect.FilesystemServiceGetErrorsProcedure:              auth.RoleViewer,
+	apiv1connect.FilesystemServiceGetAllErrorsProcedure:           auth.RoleViewer,
+	apiv1connect.FilesystemServiceStreamErrorsProcedure:           auth.RoleViewer,
+	apiv1connect.FilesystemServiceGetDeviceStatsProcedure:         auth.RoleViewer,
+	apiv1connect.FilesystemServiceGetAllDeviceStatsProcedure:      auth.RoleViewer,
+	apiv1connect.FilesystemServiceGetFilesystemUsageProcedure:     auth.RoleViewer,
+	apiv1connect.FilesystemServiceGetAllFilesystemUsageProcedure:  auth.RoleViewer,
+
+	apiv1connect.ForecastServiceGetUsageHistoryProcedure:      auth.RoleViewer,
+	apiv1connect.ForecastServiceGetUsageForecastProcedure:     auth.RoleViewer,
+	apiv1connect.ForecastServiceGetAllUsageForecastsProcedure: auth.RoleViewer,
+
+	apiv1connect.FragMapServiceGetFragMapProcedure:          auth.RoleViewer,
+	apiv1connect.FragMapServiceGetDeviceBlockMapProcedure:   auth.RoleViewer,
+	apiv1connect.FragMapServiceGetDeviceBlockMapsProcedure:  auth.RoleViewer,
+	apiv1connect.FragMapServiceGetHeatMapProcedure:          auth.RoleViewer,
+	apiv1connect.FragMapServiceGetFragStatsProcedure:        auth.RoleViewer,
+	apiv1connect.FragMapServiceGetFreeSpaceStatsProcedure:   auth.RoleViewer,
+	apiv1connect.FragMapServiceGetCompressionStatsProcedure: auth.RoleViewer,
+	apiv1connect.FragMapServiceScanFilesProcedure:           auth.RoleViewer,
+	apiv1connect.FragMapServiceListFragMapScansProcedure:    auth.RoleViewer,
+	apiv1connect.FragMapServiceDiffFragMapsProcedure:        auth.RoleViewer,
+	apiv1connect.FragMapServiceResolveLogicalRangeProcedure: auth.RoleViewer,
+
+	apiv1connect.ScrubServiceStartScrubProcedure:          auth.RoleOperator,
+	apiv1connect.ScrubServiceCancelScrubProcedure:         auth.RoleOperator,
+	apiv1connect.ScrubServiceGetScrubStatusProcedure:      auth.RoleViewer,
+	apiv1connect.ScrubServiceGetAllScrubStatusProcedure:   auth.RoleViewer,
+	apiv1connect.ScrubServiceStreamScrubProgressProcedure: auth.RoleViewer,
+	apiv1connect.ScrubServiceListScrubHistoryProcedure:    auth.RoleViewer,
+
+	apiv1connect.SnapshotServiceCreateSnapshotProcedure:   auth.RoleOperator,
+	apiv1connect.SnapshotServiceDeleteSnapshotProcedure:   auth.RoleOperator,
+	apiv1connect.SnapshotServiceListSnapshotsProcedure:    auth.RoleViewer,
+	apiv1connect.SnapshotServiceListAllSnapshotsProcedure: auth.RoleViewer,
+
+	apiv1connect.SubvolumeServiceListSubvolumesProcedure:    auth.RoleViewer,
+	apiv1connect.SubvolumeServiceListAllSubvolumesProcedure: auth.RoleViewer,
+
+	// Sampling writes nothing to the filesystem, but it is long running
+	// and clearing throws away collected samples
+	apiv1connect.UsageServiceStartSamplingProcedure:          auth.RoleOperator,
+	apiv1connect.UsageServiceStopSamplingProcedure:           auth.RoleOperator,
+	apiv1connect.UsageServiceClearSamplingProcedure:          auth.RoleOperator,
+	apiv1connect.UsageServiceGetSamplingStatusProcedure:      auth.RoleViewer,
+	apiv1connect.UsageServiceGetUsageTreeProcedure:           auth.RoleViewer,
+	apiv1connect.UsageServiceStreamSamplingProgressProcedure: auth.RoleViewer,
+}
+
+// mutating reports whether a call changes anything: everything that needs
+// operator or admin except reads, and PlanBalance when it starts the balance
+// it recommends. Logins and logouts only touch sessions and don't count.
+func mutating(procedure string, req any) bool {
+	switch procedure {
+	case apiv1connect.AuthServiceListUsersProcedure, apiv1connect.AuditServiceListAuditEventsProcedure,
+		apiv1connect.SupportServiceCreateSupportBundleProcedure:
+		return false
+	case apiv1connect.BalanceServicePlanBalanceProcedure:
+		plan, ok := req.(*apiv1.PlanBalanceRequest)
+		return ok && plan.Start
+	}
+	role, ok := procedureRoles[procedure]
+	return !ok || role >= auth.RoleOperator
+}
+
+// audited reports whether a call goes in the audit log: every mutating call,
+// plus logins and logouts
+func audited(procedure string, req any) bool {
+	switch procedure {
+	case apiv1connect.AuthServiceLoginProcedure, apiv1connect.AuthServiceLogoutProcedure:
+		return true
+	}
+	return mutating(procedure, req)
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/elee1766/gobtr/pkg/config"
+)
+
+// tlsCheckInterval is how often handshakes look for rotated files
+const tlsCheckInterval = 30 * time.Second
+
+// tlsReloader serves the certificate and client CA from disk, reloading them
+// when their modification times change. A rotation that leaves a bad pair
+// behind keeps the previous one and logs.
+type tlsReloader struct {
+	logger *slog.Logger
+	cfg    config.TLSFile
+
+	mu        sync.Mutex
+	checked   time.Time
+	modTimes  [3]time.Time // cert, key, client CA
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+}
+
+func newTLSReloader(logger *slog.Logger, cfg config.TLSFile) (*tlsReloader, error) {
+	r := &tlsReloader{
+		logger: logger,
+		cfg:    cfg,
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *tlsReloader) files() []string {
+	return []string{r.cfg.Cert, r.cfg.Key, r.cfg.ClientCA}
+}
+
+// load reads every file. Callers hold mu, or own r exclusively.
+func (r *tlsReloader) load() error {
+	var modTimes [3]time.Time
+	for i, path := range r.files() {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		modTimes[i] = info.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.Cert, r.cfg.Key)
+	if err != nil {
+		return fmt.Errorf("load tls key pair: %w", err)
+	}
+	var pool *x509.CertPool
+	if r.cfg.ClientCA != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCA)
+		if err != nil {
+			return err
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("%s: no certificates found", r.cfg.ClientCA)
+		}
+	}
+
+	r.cert = &cert
+	r.clientCAs = pool
+	r.modTimes = modTimes
+	r.checked = time.Now()
+	return nil
+}
+
+// maybeReload reloads if a file changed since the last load
+func (r *tlsReloader) maybeReload() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.checked) < tlsCheckInterval {
+		return
+	}
+	r.checked = time.Now()
+
+	changed := false
+	for i, path := range r.files() {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			r.logger.Warn("can't check tls file, keeping the loaded one", "path", path, "error", err)
+			return
+		}
+		if !info.ModTime().Equal(r.modTimes[i]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	prevCert, prevCAs, prevMod := r.cert, r.clientCAs, r.modTimes
+	if err := r.load(); err != nil {
+		r.cert, r.clientCAs, r.modTimes = prevCert, prevCAs, prevMod
+		r.logger.Error("failed to reload tls files, keeping the loaded ones", "error", err)
+		return
+	}
+	r.logger.Info("reloaded tls files", "cert", r.cfg.Cert)
+}
+
+// Config returns the tls.Config for the listener
+func (r *tlsReloader) Config() *tls.Config {
+	clientAuth := tls.NoClientCert
+	if r.cfg.ClientCA != "" {
+		clientAuth = tls.RequireAndVerifyClientCert
+		if r.cfg.ClientOptional {
+			clientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.maybeReload()
+			r.mu.Lock()
+			cert, pool := r.cert, r.clientCAs
+			r.mu.Unlock()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   []string{"h2", "http/1.1"},
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   clientAuth,
+				ClientCAs:    pool,
+			}, nil
+		},
+	}
+}
@@ -0,0 +1,388 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+	apiv1 "github.com/elee1766/gobtr/gen/api/v1"
+	"github.com/elee1766/gobtr/pkg/auth"
+	"github.com/elee1766/gobtr/pkg/db"
+	"github.com/elee1766/gobtr/pkg/db/queries"
+	"go.uber.org/fx"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var Module = fx.Module("audit",
+	fx.Provide(New),
+)
+
+// exportBatch is how many events Export reads at a time
+const exportBatch = 1000
+
+// Log records mutating RPCs to the audit_log table
+type Log struct {
+	logger *slog.Logger
+	db     *db.DB
+}
+
+func New(logger *slog.Logger, db *db.DB) *Log {
+	return &Log{
+		logger: logger.With("component", "audit"),
+		db:     db,
+	}
+}
+
+// Interceptor returns a Connect interceptor recording every call, unary or
+// streaming, for which audited returns true. It should run outside the auth
+// interceptor so denied calls are recorded too.
+func (l *Log) Interceptor(audited func(procedure string, req any) bool) connect.Interceptor {
+	return &interceptor{log: l, audited: audited}
+}
+
+type interceptor struct {
+	log     *Log
+	audited func(procedure string, req any) bool
+}
+
+func (i *interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		procedure := req.Spec().Procedure
+		if !i.audited(procedure, req.Any()) {
+			return next(ctx, req)
+		}
+
+		// Resolve the filesystem first; RemoveFilesystem makes its id
+		// unresolvable
+		event := i.log.newEvent(ctx, procedure, req.Peer().Addr, req.Any())
+		resp, err := next(ctx, req)
+		i.log.record(event, err)
+		return resp, err
+	}
+}
+
+func (i *interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler records a stream once it ends. Whether it is audited
+// can depend on the request, which only arrives with the first message, so
+// the event is built from that; a stream denied before it, by auth or
+// read-only mode, is recorded without one.
+func (i *interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		procedure := conn.Spec().Procedure
+		start := time.Now()
+		sc := &streamConn{StreamingHandlerConn: conn}
+		sc.event = func(req any) *queries.AuditEvent {
+			event := i.log.newEvent(ctx, procedure, conn.Peer().Addr, req)
+			event.Timestamp = start
+			return event
+		}
+
+		err := next(ctx, sc)
+
+		if !i.audited(procedure, sc.received) {
+			return err
+		}
+		event := sc.started
+		if event == nil {
+			event = sc.event(nil)
+		}
+		i.log.record(event, err)
+		return err
+	}
+}
+
+// streamConn keeps the first request of a stream, and the event started
+// when it arrived
+type streamConn struct {
+	connect.StreamingHandlerConn
+	event    func(req any) *queries.AuditEvent
+	received any
+	started  *queries.AuditEvent
+}
+
+func (c *streamConn) Receive(msg any) error {
+	err := c.StreamingHandlerConn.Receive(msg)
+	if err == nil && c.received == nil {
+		c.received = msg
+		c.started = c.event(msg)
+	}
+	return err
+}
+
+// newEvent starts the event for a call by whoever is in ctx
+func (l *Log) newEvent(ctx context.Context, procedure, peer string, req any) *queries.AuditEvent {
+	event := &queries.AuditEvent{
+		Timestamp:  time.Now(),
+		Actor:      "anonymous",
+		AuthMethod: "none",
+		Role:       auth.RoleNone.String(),
+		Peer:       peer,
+		Procedure:  procedure,
+	}
+	if id := auth.FromContext(ctx); id != nil {
+		event.Actor, event.AuthMethod, event.Role = id.Name, id.Method, id.Role.String()
+	}
+	if msg, ok := req.(proto.Message); ok {
+		event.Filesystem = l.filesystemOf(msg)
+		event.Request = requestJSON(msg)
+	}
+	return event
+}
+
+// record finishes event with the call's outcome and stores it
+func (l *Log) record(event *queries.AuditEvent, err error) {
+	event.Duration = time.Since(event.Timestamp)
+	event.Outcome = "ok"
+	if err != nil {
+		event.Outcome = connect.CodeOf(err).String()
+		event.Error = err.Error()
+		var connectErr *connect.Error
+		if errors.As(err, &connectErr) {
+			event.Error = connectErr.Message()
+		}
+	}
+	// The call already happened, so a failed write only gets logged
+	if err := queries.InsertAuditEvent(l.db.Conn(), event); err != nil {
+		l.logger.Error("failed to record audit event", "procedure", event.Procedure, "actor", event.Actor, "error", err)
+	}
+}
+
+// filesystemOf returns the path a request acts on, so events can be found
+// by filesystem
+func (l *Log) filesystemOf(msg proto.Message) string {
+	switch m := msg.(type) {
+	case *apiv1.RemoveFilesystemRequest:
+		return l.trackedPath(m.Id)
+	case *apiv1.UpdateFilesystemRequest:
+		return l.trackedPath(m.Id)
+	case *apiv1.StartDefragRequest:
+		if paths := m.GetOptions().GetPaths(); len(paths) > 0 {
+			return filepath.Clean(paths[0])
+		}
+		return ""
+	}
+
+	fields := msg.ProtoReflect().Descriptor().Fields()
+	for _, name := range []protoreflect.Name{"device_path", "fs_path", "path", "source_path", "snapshot_path"} {
+		fd := fields.ByName(name)
+		if fd == nil || fd.Kind() != protoreflect.StringKind || fd.IsList() {
+			continue
+		}
+		if v := msg.ProtoReflect().Get(fd).String(); v != "" {
+			return filepath.Clean(v)
+		}
+	}
+	return ""
+}
+
+func (l *Log) trackedPath(id int64) string {
+	fs, err := l.db.GetFilesystem(id)
+	if err != nil {
+		return ""
+	}
+	return fs.Path
+}
+
+// sensitiveFields are request fields whose values never go in the log.
+// Match by name, so a new field holding a secret must be added here.
+var sensitiveFields = map[protoreflect.Name]bool{
+	"password":      true,
+	"new_password":  true,
+	"old_password":  true,
+	"token":         true,
+	"token_sha256":  true,
+	"access_token":  true,
+	"refresh_token": true,
+	"bearer":        true,
+	"secret":        true,
+	"client_secret": true,
+	"api_key":       true,
+	"private_key":   true,
+	"session":       true,
+	"session_token": true,
+	"cookie":        true,
+}
+
+// requestJSON encodes a request with its sensitive fields redacted
+func requestJSON(msg proto.Message) string {
+	msg = proto.Clone(msg)
+	redact(msg.ProtoReflect())
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return "{}"
+	}
+	// protojson output isn't stable; compact it so rows are one line
+	var v any
+	if json.Unmarshal(b, &v) == nil {
+		if compact, err := json.Marshal(v); err == nil {
+			return string(compact)
+		}
+	}
+	return string(b)
+}
+
+func redact(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case sensitiveFields[fd.Name()]:
+			redactField(m, fd, v)
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					redact(mv.Message())
+					return true
+				})
+			}
+		case fd.Kind() == protoreflect.MessageKind && fd.IsList():
+			for i := 0; i < v.List().Len(); i++ {
+				redact(v.List().Get(i).Message())
+			}
+		case fd.Kind() == protoreflect.MessageKind:
+			redact(v.Message())
+		}
+		return true
+	})
+}
+
+// redactField replaces a sensitive field's value, whatever its type, with
+// a marker where it can hold one and clears it otherwise
+func redactField(m protoreflect.Message, fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+	switch {
+	case fd.Kind() == protoreflect.StringKind && fd.IsList():
+		for i := 0; i < v.List().Len(); i++ {
+			v.List().Set(i, protoreflect.ValueOfString("[redacted]"))
+		}
+	case fd.Kind() == protoreflect.StringKind && !fd.IsMap():
+		m.Set(fd, protoreflect.ValueOfString("[redacted]"))
+	default:
+		m.Clear(fd)
+	}
+}
+
+// List returns events matching f, newest first
+func (l *Log) List(f queries.AuditFilter) ([]*queries.AuditEvent, error) {
+	return queries.ListAuditEvents(l.db.Conn(), f)
+}
+
+// exportedEvent is one line of the JSON lines export
+type exportedEvent struct {
+	ID         int64           `json:"id"`
+	Time       time.Time       `json:"time"`
+	Actor      string          `json:"actor"`
+	AuthMethod string          `json:"auth_method"`
+	Role       string          `json:"role"`
+	Peer       string          `json:"peer"`
+	Procedure  string          `json:"procedure"`
+	Filesystem string          `json:"filesystem,omitempty"`
+	Request    json.RawMessage `json:"request"`
+	Outcome    string          `json:"outcome"`
+	Error      string          `json:"error,omitempty"`
+	DurationMs int64           `json:"duration_ms"`
+}
+
+// Export writes events matching f to w as JSON lines, newest first. Limit
+// caps the total; zero exports everything.
+func (l *Log) Export(w io.Writer, f queries.AuditFilter) error {
+	enc := json.NewEncoder(w)
+	remaining := f.Limit
+	for {
+		batch := f
+		batch.Limit = exportBatch
+		if remaining > 0 && remaining < exportBatch {
+			batch.Limit = remaining
+		}
+		events, err := queries.ListAuditEvents(l.db.Conn(), batch)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			request := json.RawMessage(e.Request)
+			if !json.Valid(request) {
+				request = json.RawMessage("{}")
+			}
+			if err := enc.Encode(exportedEvent{
+				ID:         e.ID,
+				Time:       e.Timestamp.UTC(),
+				Actor:      e.Actor,
+				AuthMethod: e.AuthMethod,
+				Role:       e.Role,
+				Peer:       e.Peer,
+				Procedure:  e.Procedure,
+				Filesystem: e.Filesystem,
+				Request:    request,
+				Outcome:    e.Outcome,
+				Error:      e.Error,
+				DurationMs: e.Duration.Milliseconds(),
+			}); err != nil {
+				return err
+			}
+		}
+		if remaining > 0 {
+			remaining -= len(events)
+			if remaining <= 0 {
+				return nil
+			}
+		}
+		if len(events) < batch.Limit {
+			return nil
+		}
+		f.BeforeID = events[len(events)-1].ID
+	}
+}
+
+// ExportHandler serves the audit log as JSON lines. Query parameters:
+// fs, actor, procedure, since and until (RFC 3339 or unix seconds), limit.
+func (l *Log) ExportHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		f := queries.AuditFilter{
+			Filesystem: q.Get("fs"),
+			Actor:      q.Get("actor"),
+			Procedure:  q.Get("procedure"),
+		}
+		var err error
+		if f.Since, err = parseTime(q.Get("since")); err != nil {
+			http.Error(w, fmt.Sprintf("since: %v", err), http.StatusBadRequest)
+			return
+		}
+		if f.Until, err = parseTime(q.Get("until")); err != nil {
+			http.Error(w, fmt.Sprintf("until: %v", err), http.StatusBadRequest)
+			return
+		}
+		if s := q.Get("limit"); s != "" {
+			if f.Limit, err = strconv.Atoi(s); err != nil || f.Limit < 0 {
+				http.Error(w, "limit must be a non-negative integer", http.StatusBadRequest)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="gobtr-audit.jsonl"`)
+		if err := l.Export(w, f); err != nil {
+			l.logger.Error("failed to export audit log", "error", err)
+		}
+	})
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(n, 0), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,657 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"os/user"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/elee1766/gobtr/pkg/config"
+	"github.com/elee1766/gobtr/pkg/db"
+	"github.com/elee1766/gobtr/pkg/db/queries"
+	"github.com/elee1766/gobtr/pkg/reconcile"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module("auth",
+	fx.Provide(New),
+	fx.Invoke(registerHooks),
+)
+
+// SessionCookie is the cookie holding a web UI login
+const SessionCookie = "gobtr_session"
+
+// sessionCleanupInterval is how often expired sessions are deleted
+const sessionCleanupInterval = time.Hour
+
+var (
+	// ErrInvalidCredentials is returned by Login for a wrong username or
+	// password, without saying which
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	// ErrInvalid is returned for bad user management requests
+	ErrInvalid = errors.New("invalid user")
+	// ErrNotFound is returned for users that don't exist
+	ErrNotFound = errors.New("user not found")
+)
+
+// Role decides what an identity may do. Each role can do everything the
+// roles below it can.
+type Role int
+
+const (
+	RoleNone Role = iota
+	// RoleViewer can read everything
+	RoleViewer
+	// RoleOperator can also start and cancel maintenance and change tracked
+	// filesystems
+	RoleOperator
+	// RoleAdmin can also change server settings and manage users
+	RoleAdmin
+)
+
+var roleNames = map[Role]string{
+	RoleViewer:   "viewer",
+	RoleOperator: "operator",
+	RoleAdmin:    "admin",
+}
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return "none"
+}
+
+// ParseRole parses a role name
+func ParseRole(s string) (Role, error) {
+	for role, name := range roleNames {
+		if strings.EqualFold(s, name) {
+			return role, nil
+		}
+	}
+	return RoleNone, fmt.Errorf("unknown role %q (want viewer, operator or admin)", s)
+}
+
+// Identity is who made a request
+type Identity struct {
+	Name string
+	Role Role
+	// How they authenticated: "session", "token", "cert", "socket", "proxy",
+	// or "none" when auth is disabled
+	Method string
+}
+
+type identityKey struct{}
+
+type secureKey struct{}
+
+// FromContext returns the identity set by Middleware, or nil if the request
+// is anonymous
+func FromContext(ctx context.Context) *Identity {
+	id, _ := ctx.Value(identityKey{}).(*Identity)
+	return id
+}
+
+// WithIdentity returns ctx carrying id
+func WithIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// IsSecure reports whether the request in ctx came over TLS, so cookies set
+// in reply can be marked Secure
+func IsSecure(ctx context.Context) bool {
+	secure, _ := ctx.Value(secureKey{}).(bool)
+	return secure
+}
+
+// anonymousAdmin is everyone when auth is disabled
+var anonymousAdmin = &Identity{Name: "anonymous", Role: RoleAdmin, Method: "none"}
+
+type staticToken struct {
+	name string
+	sum  [sha256.Size]byte
+	role Role
+}
+
+// Authenticator identifies requests from a session cookie, a static bearer
+// token, a client certificate, the unix socket or a trusted proxy header, in
+// that order. Its config comes from the
+// [auth] section of the config file and follows reloads.
+type Authenticator struct {
+	logger *slog.Logger
+	db     *db.DB
+	// Roles for verified client certificates and unix socket peers. These
+	// come with the listeners, so they don't change on reload.
+	certRole   Role
+	socketRole Role
+
+	mu          sync.RWMutex
+	enabled     bool
+	sessionTTL  time.Duration
+	tokens      []staticToken
+	proxy       config.ProxyFile
+	proxyRole   Role
+	trustedNets []netip.Prefix
+}
+
+func New(logger *slog.Logger, cfg *config.Config, db *db.DB) (*Authenticator, error) {
+	a := &Authenticator{
+		logger: logger.With("component", "auth"),
+		db:     db,
+	}
+	if err := a.configure(cfg.Auth); err != nil {
+		return nil, err
+	}
+	if cfg.TLS.ClientCA != "" {
+		a.certRole = RoleViewer
+		if cfg.TLS.ClientRole != "" {
+			role, err := ParseRole(cfg.TLS.ClientRole)
+			if err != nil {
+				return nil, fmt.Errorf("tls.client_role: %w", err)
+			}
+			a.certRole = role
+		}
+	}
+	if cfg.Socket.Role != "" {
+		role, err := ParseRole(cfg.Socket.Role)
+		if err != nil {
+			return nil, fmt.Errorf("socket.role: %w", err)
+		}
+		a.socketRole = role
+	}
+	return a, nil
+}
+
+// configure applies the [auth] section. The file was validated when loaded,
+// so errors here mean a bug rather than a bad file.
+func (a *Authenticator) configure(c config.AuthFile) error {
+	tokens := make([]staticToken, 0, len(c.Tokens))
+	for _, t := range c.Tokens {
+		role, err := ParseRole(t.Role)
+		if err != nil {
+			return fmt.Errorf("auth token %q: %w", t.Name, err)
+		}
+		tok := staticToken{name: t.Name, role: role}
+		if t.Token != "" {
+			tok.sum = sha256.Sum256([]byte(t.Token))
+		} else {
+			b, err := hex.DecodeString(t.TokenSHA256)
+			if err != nil || len(b) != sha256.Size {
+				return fmt.Errorf("auth token %q: bad token_sha256", t.Name)
+			}
+			copy(tok.sum[:], b)
+		}
+		tokens = append(tokens, tok)
+	}
+
+	proxyRole := RoleViewer
+	if c.Proxy.DefaultRole != "" {
+		role, err := ParseRole(c.Proxy.DefaultRole)
+		if err != nil {
+			return fmt.Errorf("auth.proxy.default_role: %w", err)
+		}
+		proxyRole = role
+	}
+	trusted, err := c.Proxy.TrustedPrefixes()
+	if err != nil {
+		return fmt.Errorf("auth.proxy.trusted: %w", err)
+	}
+
+	a.mu.Lock()
+	a.enabled = c.Enabled
+	a.sessionTTL = c.SessionTTLOrDefault()
+	a.tokens = tokens
+	a.proxy = c.Proxy
+	a.proxyRole = proxyRole
+	a.trustedNets = trusted
+	a.mu.Unlock()
+	return nil
+}
+
+// Enabled reports whether requests need to authenticate
+func (a *Authenticator) Enabled() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.enabled
+}
+
+// Middleware identifies each request and stores the identity in its
+// context for the interceptor and handlers. It never rejects a request;
+// that is left to whatever the route requires.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), secureKey{}, r.TLS != nil)
+		if id := a.Authenticate(r); id != nil {
+			ctx = WithIdentity(ctx, id)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Require wraps a plain HTTP handler so only identities with at least role
+// can use it
+func (a *Authenticator) Require(role Role, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := FromContext(r.Context())
+		if id == nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gobtr"`)
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+		if id.Role < role {
+			http.Error(w, fmt.Sprintf("requires %s role", role), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Authenticate returns who made r, or nil if they didn't authenticate. A
+// bearer token that doesn't match is not an error here; the request is just
+// anonymous.
+func (a *Authenticator) Authenticate(r *http.Request) *Identity {
+	if !a.Enabled() {
+		return anonymousAdmin
+	}
+
+	if cookie, err := r.Cookie(SessionCookie); err == nil && cookie.Value != "" {
+		user, err := queries.GetSessionUser(a.db.Conn(), hashToken(cookie.Value), time.Now())
+		if err == nil {
+			if role, err := ParseRole(user.Role); err == nil {
+				return &Identity{Name: user.Username, Role: role, Method: "session"}
+			}
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			a.logger.Error("failed to look up session", "error", err)
+		}
+	}
+
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		if id := a.checkToken(strings.TrimSpace(token)); id != nil {
+			return id
+		}
+		a.logger.Debug("rejected bearer token", "remote", r.RemoteAddr)
+	}
+
+	// Only verified chains count; with client_optional an unverified
+	// certificate never gets this far
+	if a.certRole != RoleNone && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		cert := r.TLS.VerifiedChains[0][0]
+		return &Identity{Name: "cert:" + cert.Subject.CommonName, Role: a.certRole, Method: "cert"}
+	}
+
+	if a.socketRole != RoleNone {
+		if c, ok := r.Context().Value(connKey{}).(*net.UnixConn); ok {
+			return &Identity{Name: socketPeer(c), Role: a.socketRole, Method: "socket"}
+		}
+	}
+
+	return a.checkProxy(r)
+}
+
+type connKey struct{}
+
+// ConnContext keeps unix socket connections in their requests' context so
+// Authenticate can tell who is on the other end. Use it as the
+// http.Server's ConnContext.
+func ConnContext(ctx context.Context, c net.Conn) context.Context {
+	if uc, ok := c.(*net.UnixConn); ok {
+		return context.WithValue(ctx, connKey{}, uc)
+	}
+	return ctx
+}
+
+// socketPeer names the process on the other end of a unix socket by its
+// user, from SO_PEERCRED
+func socketPeer(c *net.UnixConn) string {
+	raw, err := c.SyscallConn()
+	if err != nil {
+		return "unix"
+	}
+	var cred *syscall.Ucred
+	raw.Control(func(fd uintptr) {
+		cred, err = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err != nil || cred == nil {
+		return "unix"
+	}
+	uid := strconv.FormatUint(uint64(cred.Uid), 10)
+	if u, err := user.LookupId(uid); err == nil {
+		return "unix:" + u.Username
+	}
+	return "unix:uid=" + uid
+}
+
+func (a *Authenticator) checkToken(token string) *Identity {
+	sum := sha256.Sum256([]byte(token))
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	// Compare against every token so the time taken doesn't say which matched
+	var match *staticToken
+	for i := range a.tokens {
+		if subtle.ConstantTimeCompare(sum[:], a.tokens[i].sum[:]) == 1 {
+			match = &a.tokens[i]
+		}
+	}
+	if match == nil {
+		return nil
+	}
+	return &Identity{Name: "token:" + match.name, Role: match.role, Method: "token"}
+}
+
+func (a *Authenticator) checkProxy(r *http.Request) *Identity {
+	a.mu.RLock()
+	proxy, proxyRole, trusted := a.proxy, a.proxyRole, a.trustedNets
+	a.mu.RUnlock()
+
+	if proxy.UserHeader == "" {
+		return nil
+	}
+	user := r.Header.Get(proxy.UserHeader)
+	if user == "" {
+		return nil
+	}
+	if !isTrusted(r.RemoteAddr, trusted) {
+		a.logger.Warn("ignoring proxy user header from untrusted address", "remote", r.RemoteAddr, "header", proxy.UserHeader)
+		return nil
+	}
+
+	role := proxyRole
+	if proxy.RoleHeader != "" {
+		if value := r.Header.Get(proxy.RoleHeader); value != "" {
+			parsed, err := ParseRole(value)
+			if err != nil {
+				a.logger.Warn("rejecting proxy user with unknown role", "user", user, "role", value)
+				return nil
+			}
+			role = parsed
+		}
+	}
+	return &Identity{Name: user, Role: role, Method: "proxy"}
+}
+
+func isTrusted(remoteAddr string, trusted []netip.Prefix) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Login checks a local user's password and starts a session. It returns the
+// session token to put in the cookie.
+func (a *Authenticator) Login(username, password string) (string, *Identity, time.Time, error) {
+	user, err := queries.GetUser(a.db.Conn(), username)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Hash anyway so a missing user takes as long as a wrong password
+		HashPassword(password)
+		return "", nil, time.Time{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return "", nil, time.Time{}, err
+	}
+	ok, err := VerifyPassword(password, user.PasswordHash)
+	if err != nil {
+		return "", nil, time.Time{}, fmt.Errorf("user %s: %w", username, err)
+	}
+	if !ok {
+		return "", nil, time.Time{}, ErrInvalidCredentials
+	}
+	role, err := ParseRole(user.Role)
+	if err != nil {
+		return "", nil, time.Time{}, fmt.Errorf("user %s: %w", username, err)
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, time.Time{}, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	a.mu.RLock()
+	ttl := a.sessionTTL
+	a.mu.RUnlock()
+	now := time.Now()
+	expires := now.Add(ttl)
+	if err := queries.InsertSession(a.db.Conn(), hashToken(token), user.ID, now, expires); err != nil {
+		return "", nil, time.Time{}, fmt.Errorf("store session: %w", err)
+	}
+
+	a.logger.Info("user logged in", "user", username)
+	return token, &Identity{Name: user.Username, Role: role, Method: "session"}, expires, nil
+}
+
+// Logout ends the session with the given token
+func (a *Authenticator) Logout(token string) error {
+	return queries.DeleteSession(a.db.Conn(), hashToken(token))
+}
+
+// NewSessionCookie returns the cookie that carries a session token. An empty
+// token clears it.
+func NewSessionCookie(token string, expires time.Time, secure bool) *http.Cookie {
+	c := &http.Cookie{
+		Name:     SessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteStrictMode,
+	}
+	if token == "" {
+		c.MaxAge = -1
+	}
+	return c
+}
+
+// ListUsers returns the local users
+func (a *Authenticator) ListUsers() ([]*queries.User, error) {
+	return queries.ListUsers(a.db.Conn())
+}
+
+// CreateUser adds a local user
+func (a *Authenticator) CreateUser(username, password string, role Role) (*queries.User, error) {
+	if username == "" || strings.ContainsAny(username, " \t\r\n") {
+		return nil, fmt.Errorf("%w: username must be non-empty and without spaces", ErrInvalid)
+	}
+	if role == RoleNone {
+		return nil, fmt.Errorf("%w: role is required", ErrInvalid)
+	}
+	if len(password) < MinPasswordLength {
+		return nil, fmt.Errorf("%w: password must be at least %d characters", ErrInvalid, MinPasswordLength)
+	}
+	if _, err := queries.GetUser(a.db.Conn(), username); err == nil {
+		return nil, fmt.Errorf("%w: user %s already exists", ErrInvalid, username)
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	user := &queries.User{
+		Username:     username,
+		PasswordHash: hash,
+		Role:         role.String(),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := queries.InsertUser(a.db.Conn(), user); err != nil {
+		return nil, err
+	}
+	a.logger.Info("user created", "user", username, "role", role.String())
+	return user, nil
+}
+
+// SetRole changes a user's role. The last admin can't be demoted.
+func (a *Authenticator) SetRole(username string, role Role) error {
+	if role == RoleNone {
+		return fmt.Errorf("%w: role is required", ErrInvalid)
+	}
+	if role != RoleAdmin {
+		if err := a.checkNotLastAdmin(username); err != nil {
+			return err
+		}
+	}
+	err := queries.UpdateUserRole(a.db.Conn(), username, role.String(), time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", ErrNotFound, username)
+	}
+	if err != nil {
+		return err
+	}
+	a.logger.Info("user role changed", "user", username, "role", role.String())
+	return nil
+}
+
+// SetPassword changes a user's password and signs them out everywhere
+func (a *Authenticator) SetPassword(username, password string) error {
+	if len(password) < MinPasswordLength {
+		return fmt.Errorf("%w: password must be at least %d characters", ErrInvalid, MinPasswordLength)
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	err = queries.UpdateUserPassword(a.db.Conn(), username, hash, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", ErrNotFound, username)
+	}
+	if err != nil {
+		return err
+	}
+	a.logger.Info("user password changed", "user", username)
+	return nil
+}
+
+// DeleteUser removes a user and their sessions. The last admin can't be
+// removed.
+func (a *Authenticator) DeleteUser(username string) error {
+	if err := a.checkNotLastAdmin(username); err != nil {
+		return err
+	}
+	err := queries.DeleteUser(a.db.Conn(), username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", ErrNotFound, username)
+	}
+	if err != nil {
+		return err
+	}
+	a.logger.Info("user deleted", "user", username)
+	return nil
+}
+
+// checkNotLastAdmin keeps the UI from locking everyone out. Tokens and the
+// proxy can still be admins, but they live in the config file.
+func (a *Authenticator) checkNotLastAdmin(username string) error {
+	user, err := queries.GetUser(a.db.Conn(), username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", ErrNotFound, username)
+	}
+	if err != nil {
+		return err
+	}
+	if user.Role != RoleAdmin.String() {
+		return nil
+	}
+	admins, err := queries.CountUsersWithRole(a.db.Conn(), RoleAdmin.String())
+	if err != nil {
+		return err
+	}
+	if admins <= 1 {
+		return fmt.Errorf("%w: %s is the last admin", ErrInvalid, username)
+	}
+	return nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// warnIfLockedOut logs when auth is on but nothing can authenticate
+func (a *Authenticator) warnIfLockedOut() {
+	a.mu.RLock()
+	enabled, tokens, proxy := a.enabled, len(a.tokens), a.proxy.UserHeader
+	a.mu.RUnlock()
+	if !enabled {
+		a.logger.Warn("auth is disabled, anyone who can reach the server is an admin")
+		return
+	}
+	if tokens > 0 || proxy != "" || a.certRole != RoleNone || a.socketRole != RoleNone {
+		return
+	}
+	users, err := queries.ListUsers(a.db.Conn())
+	if err == nil && len(users) == 0 {
+		a.logger.Warn("auth is enabled but there are no users, tokens or proxy; add a user with `gobtr user add`")
+	}
+}
+
+func registerHooks(lc fx.Lifecycle, a *Authenticator, reconciler *reconcile.Reconciler) {
+	reconciler.Subscribe(func(file *config.File) {
+		if err := a.configure(file.Auth); err != nil {
+			a.logger.Error("failed to apply auth config", "error", err)
+			return
+		}
+		a.warnIfLockedOut()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			a.warnIfLockedOut()
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(sessionCleanupInterval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+					}
+					if n, err := queries.DeleteExpiredSessions(a.db.Conn(), time.Now()); err != nil {
+						a.logger.Warn("failed to delete expired sessions", "error", err)
+					} else if n > 0 {
+						a.logger.Debug("deleted expired sessions", "count", n)
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
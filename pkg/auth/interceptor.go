@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+)
+
+// Interceptor enforces the role each RPC requires. The identity comes from
+// Middleware, which must wrap the handlers.
+type Interceptor struct {
+	// Role required per procedure; procedures not listed need RoleAdmin so
+	// a new RPC is closed until it is given a role
+	roles map[string]Role
+}
+
+// NewInterceptor returns an interceptor requiring roles[procedure] for each
+// RPC. RoleNone marks a procedure anyone can call.
+func NewInterceptor(roles map[string]Role) *Interceptor {
+	return &Interceptor{roles: roles}
+}
+
+// RequiredRole returns the role needed to call procedure
+func (i *Interceptor) RequiredRole(procedure string) Role {
+	if role, ok := i.roles[procedure]; ok {
+		return role
+	}
+	return RoleAdmin
+}
+
+func (i *Interceptor) authorize(ctx context.Context, procedure string) error {
+	required := i.RequiredRole(procedure)
+	if required == RoleNone {
+		return nil
+	}
+	return requireRole(ctx, required, procedure)
+}
+
+// RequireRole is for handlers where part of a request needs more than the
+// RPC itself, e.g. an RPC viewers can call that can also start something
+func RequireRole(ctx context.Context, role Role, what string) error {
+	return requireRole(ctx, role, what)
+}
+
+func requireRole(ctx context.Context, required Role, what string) error {
+	id := FromContext(ctx)
+	if id == nil {
+		return connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("sign in to use %s", what))
+	}
+	if id.Role < required {
+		return connect.NewError(connect.CodePermissionDenied, fmt.Errorf("%s requires the %s role (%s has %s)", what, required, id.Name, id.Role))
+	}
+	return nil
+}
+
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := i.authorize(ctx, req.Spec().Procedure); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.authorize(ctx, conn.Spec().Procedure); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
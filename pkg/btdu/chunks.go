@@ -7,6 +7,7 @@ import (
 	"unsafe"
 
 	"github.com/dennwc/ioctl"
+	"github.com/elee1766/gobtr/pkg/privsep"
 )
 
 // Chunk represents an allocated chunk in the filesystem.
@@ -91,16 +92,16 @@ const (
 
 // EnumerateChunks reads all chunks from the filesystem's chunk tree.
 // If dataOnly is true, only DATA chunks are included (for sampling).
-func EnumerateChunks(fsFile *os.File) (*ChunkList, error) {
-	return enumerateChunksFiltered(fsFile, false)
+func EnumerateChunks(b privsep.Backend, fsFile *os.File) (*ChunkList, error) {
+	return enumerateChunksFiltered(b, fsFile, false)
 }
 
 // EnumerateDataChunks reads only DATA chunks from the filesystem's chunk tree.
-func EnumerateDataChunks(fsFile *os.File) (*ChunkList, error) {
-	return enumerateChunksFiltered(fsFile, true)
+func EnumerateDataChunks(b privsep.Backend, fsFile *os.File) (*ChunkList, error) {
+	return enumerateChunksFiltered(b, fsFile, true)
 }
 
-func enumerateChunksFiltered(fsFile *os.File, dataOnly bool) (*ChunkList, error) {
+func enumerateChunksFiltered(b privsep.Backend, fsFile *os.File, dataOnly bool) (*ChunkList, error) {
 	chunks := &ChunkList{}
 
 	args := btrfsIoctlSearchArgs{}
@@ -116,7 +117,7 @@ func enumerateChunksFiltered(fsFile *os.File, dataOnly bool) (*ChunkList, error)
 	args.Key.NrItems = 4096
 
 	for {
-		err := ioctl.Do(fsFile, ioctlTreeSearch, &args)
+		err := b.Ioctl(fsFile, ioctlTreeSearch, &args)
 		if err != nil {
 			return nil, fmt.Errorf("tree search ioctl: %w", err)
 		}
@@ -8,23 +8,14 @@ import (
 	"unsafe"
 
 	"github.com/dennwc/ioctl"
+	"github.com/elee1766/gobtr/pkg/privsep"
 )
 
 // ioctl numbers for BTRFS operations
 var (
-	ioctlLogicalIno = ioctl.IOWR(btrfsIoctlMagic, 36, unsafe.Sizeof(btrfsIoctlLogicalInoArgs{}))
 	ioctlInoLookup  = ioctl.IOWR(btrfsIoctlMagic, 18, unsafe.Sizeof(btrfsIoctlInoLookupArgs{}))
 )
 
-// btrfsIoctlLogicalInoArgs matches struct btrfs_ioctl_logical_ino_args
-type btrfsIoctlLogicalInoArgs struct {
-	Logical   uint64
-	Size      uint64
-	Reserved  [3]uint64
-	Flags     uint64
-	Inodes    uint64
-}
-
 // btrfsIoctlInoLookupArgs matches struct btrfs_ioctl_ino_lookup_args
 type btrfsIoctlInoLookupArgs struct {
 	TreeID   uint64
@@ -46,20 +37,12 @@ type btrfsDataContainer struct {
 }
 
 // logicalInoImpl performs LOGICAL_INO ioctl to find inodes for a logical address.
-func logicalInoImpl(f *os.File, logical uint64) ([]InodeResult, error) {
+func logicalInoImpl(b privsep.Backend, f *os.File, logical uint64) ([]InodeResult, error) {
 	// Result buffer - needs to be separate from args struct
 	resultBufSize := logicalInoArgsSize - 56
 	resultBuf := make([]byte, resultBufSize)
 
-	// Set up args struct
-	args := btrfsIoctlLogicalInoArgs{
-		Logical: logical,
-		Size:    uint64(resultBufSize),
-		Flags:   0,
-		Inodes:  uint64(uintptr(unsafe.Pointer(&resultBuf[0]))),
-	}
-
-	err := ioctl.Do(f, ioctlLogicalIno, &args)
+	err := b.LogicalIno(f, logical, 0, resultBuf)
 	if err != nil {
 		return nil, fmt.Errorf("logical_ino ioctl: %w", err)
 	}
@@ -87,13 +70,13 @@ func logicalInoImpl(f *os.File, logical uint64) ([]InodeResult, error) {
 }
 
 // inodeLookupImpl performs INO_LOOKUP ioctl to resolve inode to path.
-func inodeLookupImpl(f *os.File, treeID, objectID uint64) (string, error) {
+func inodeLookupImpl(b privsep.Backend, f *os.File, treeID, objectID uint64) (string, error) {
 	args := btrfsIoctlInoLookupArgs{
 		TreeID:   treeID,
 		ObjectID: objectID,
 	}
 
-	err := ioctl.Do(f, ioctlInoLookup, &args)
+	err := b.Ioctl(f, ioctlInoLookup, &args)
 	if err != nil {
 		return "", fmt.Errorf("ino_lookup ioctl: %w", err)
 	}
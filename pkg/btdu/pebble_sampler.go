@@ -10,13 +10,14 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/dennwc/btrfs"
+	"github.com/elee1766/gobtr/pkg/btrfs"
+	"github.com/elee1766/gobtr/pkg/privsep"
 )
 
 // PebbleSampler performs disk usage sampling with PebbleDB-backed storage.
 type PebbleSampler struct {
 	fsPath  string
-	fs      *btrfs.FS
+	backend privsep.Backend
 	fsFile  *os.File
 	session *PebbleSession
 	store   *PebbleStore
@@ -31,37 +32,33 @@ type PebbleSampler struct {
 	// Root ID to subvolume path lookup
 	rootPaths sync.Map
 
+	// Sampling stops once the session has this many samples (0 = never)
+	target atomic.Uint64
+
 	// Stats
 	samplesPerSec   atomic.Int64
 	lastSampleCount uint64
 	lastSampleTime  time.Time
 }
 
-// NewPebbleSampler creates a new sampler with PebbleDB-backed storage.
-func NewPebbleSampler(fsPath string, store *PebbleStore, resume bool) (*PebbleSampler, error) {
-	fs, err := btrfs.Open(fsPath, true)
+// NewPebbleSampler creates a new sampler with PebbleDB-backed storage. Its
+// ioctls go through b.
+func NewPebbleSampler(b privsep.Backend, fsPath string, store *PebbleStore, resume bool) (*PebbleSampler, error) {
+	fsFile, err := b.Open(fsPath)
 	if err != nil {
-		return nil, fmt.Errorf("open btrfs filesystem: %w", err)
-	}
-
-	fsFile, err := os.OpenFile(fsPath, os.O_RDONLY, 0)
-	if err != nil {
-		fs.Close()
 		return nil, fmt.Errorf("open fs for ioctl: %w", err)
 	}
 
 	// Only enumerate DATA chunks for sampling - metadata/system chunks
 	// don't have file inodes so LOGICAL_INO won't find anything
-	chunks, err := EnumerateDataChunks(fsFile)
+	chunks, err := EnumerateDataChunks(b, fsFile)
 	if err != nil {
-		fs.Close()
 		fsFile.Close()
 		return nil, fmt.Errorf("enumerate chunks: %w", err)
 	}
 
 	totalSize := chunks.TotalSize
 	if totalSize == 0 {
-		fs.Close()
 		fsFile.Close()
 		return nil, fmt.Errorf("no chunks found in filesystem")
 	}
@@ -72,7 +69,6 @@ func NewPebbleSampler(fsPath string, store *PebbleStore, resume bool) (*PebbleSa
 		if err != nil {
 			session, _, err = store.OpenOrCreate(fsPath, totalSize)
 			if err != nil {
-				fs.Close()
 				fsFile.Close()
 				return nil, fmt.Errorf("create session: %w", err)
 			}
@@ -88,19 +84,17 @@ func NewPebbleSampler(fsPath string, store *PebbleStore, resume bool) (*PebbleSa
 	} else if store != nil {
 		session, _, err = store.OpenOrCreate(fsPath, totalSize)
 		if err != nil {
-			fs.Close()
 			fsFile.Close()
 			return nil, fmt.Errorf("create session: %w", err)
 		}
 	} else {
-		fs.Close()
 		fsFile.Close()
 		return nil, fmt.Errorf("store is required for PebbleSampler")
 	}
 
 	s := &PebbleSampler{
 		fsPath:  fsPath,
-		fs:      fs,
+		backend: b,
 		fsFile:  fsFile,
 		store:   store,
 		session: session,
@@ -128,15 +122,15 @@ func (s *PebbleSampler) Session() *PebbleSession {
 }
 
 func (s *PebbleSampler) refreshRootPaths() {
-	subvols, err := s.fs.ListSubvolumes(nil)
+	subvols, err := btrfs.SubvolumePaths(s.backend, s.fsPath)
 	if err != nil {
 		return
 	}
 
 	s.rootPaths.Store(uint64(5), "")
 
-	for _, sv := range subvols {
-		s.rootPaths.Store(sv.RootID, sv.Path)
+	for rootID, path := range subvols {
+		s.rootPaths.Store(rootID, path)
 	}
 }
 
@@ -161,10 +155,7 @@ func (s *PebbleSampler) Close() error {
 		s.session.Close()
 	}
 	if s.fsFile != nil {
-		s.fsFile.Close()
-	}
-	if s.fs != nil {
-		return s.fs.Close()
+		return s.fsFile.Close()
 	}
 	return nil
 }
@@ -213,6 +204,17 @@ func (s *PebbleSampler) Stop() {
 	s.session.Flush()
 }
 
+// SetTarget sets the sample count at which sampling stops. It can be changed
+// while running; 0 samples until stopped.
+func (s *PebbleSampler) SetTarget(n uint64) {
+	s.target.Store(n)
+}
+
+// Target returns the sample count at which sampling stops.
+func (s *PebbleSampler) Target() uint64 {
+	return s.target.Load()
+}
+
 // IsRunning returns whether the sampler is running.
 func (s *PebbleSampler) IsRunning() bool {
 	return s.running.Load()
@@ -341,6 +343,10 @@ func (s *PebbleSampler) sampleLoop(ctx context.Context, totalSize uint64) {
 			}
 			s.lastSampleCount = currentCount
 			s.lastSampleTime = time.Now()
+			if target := s.target.Load(); target > 0 && currentCount >= target {
+				s.Stop()
+				return
+			}
 		case <-flushTicker.C:
 			// Periodic flush to disk
 			s.session.FlushAccumulator()
@@ -435,9 +441,9 @@ func (s *PebbleSampler) resolveLogicalAddress(logicalAddr uint64) (string, Sampl
 }
 
 func (s *PebbleSampler) logicalIno(logical uint64) ([]InodeResult, error) {
-	return logicalInoImpl(s.fsFile, logical)
+	return logicalInoImpl(s.backend, s.fsFile, logical)
 }
 
 func (s *PebbleSampler) inodeLookup(treeID, objectID uint64) (string, error) {
-	return inodeLookupImpl(s.fsFile, treeID, objectID)
+	return inodeLookupImpl(s.backend, s.fsFile, treeID, objectID)
 }
@@ -4,8 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"os/exec"
-	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -34,6 +33,8 @@ type activeBalance struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 	id     string
+	done   chan struct{}
+	err    error
 }
 
 // BalanceOptions contains options for starting a balance
@@ -47,12 +48,35 @@ type BalanceOptions struct {
 	Background   bool
 	DryRun       bool
 	Force        bool
+
+	// Profile conversion targets, e.g. "raid1" (empty = no conversion).
+	// Setting one selects that chunk type even if its flag above is false.
+	DataConvert     string
+	MetadataConvert string
+	SystemConvert   string
+	Soft            bool // Skip chunks that already have the target profile
+
+	// Only balance chunks with a stripe on this device (0 = any device)
+	Devid uint64
+	// Only balance chunks overlapping this physical byte range on Devid
+	// (DrangeEnd 0 = no range filter). Requires Devid.
+	DrangeStart uint64
+	DrangeEnd   uint64
 }
 
-// Track active balances per device
+// maxFinishedBalances is how many results of balances nobody waited on are
+// kept for WaitBalance. Most balances are never waited on, so without a cap
+// the results would pile up forever.
+const maxFinishedBalances = 20
+
+// Track active balances per device, and the result of balances that finished
+// before anyone waited on them (keyed by balance ID, oldest first in
+// finishedOrder)
 var (
-	activeBalances = make(map[string]*activeBalance)
-	balanceMutex   sync.Mutex
+	activeBalances   = make(map[string]*activeBalance)
+	finishedBalances = make(map[string]error)
+	finishedOrder    []string
+	balanceMutex     sync.Mutex
 )
 
 // StartBalance starts a balance operation on a device
@@ -65,65 +89,76 @@ func (m *Manager) StartBalance(ctx context.Context, devicePath string, opts Bala
 
 	balanceID := uuid.New().String()
 	balanceCtx, cancel := context.WithCancel(ctx)
-	activeBalances[devicePath] = &activeBalance{
+	active := &activeBalance{
 		ctx:    balanceCtx,
 		cancel: cancel,
 		id:     balanceID,
+		done:   make(chan struct{}),
 	}
+	activeBalances[devicePath] = active
 	balanceMutex.Unlock()
 
 	args := []string{"balance", "start"}
 
 	// Build filter arguments
-	filters := []string{}
-	if opts.Data {
-		filter := "dprofiles=data"
-		if opts.UsagePercent > 0 {
-			filter += fmt.Sprintf(",usage=%d", opts.UsagePercent)
-		}
-		if opts.LimitChunks > 0 {
-			filter += fmt.Sprintf(",limit=%d", opts.LimitChunks)
-		}
-		filters = append(filters, filter)
+	typeFilters := []struct {
+		flag    string
+		enabled bool
+		convert string
+	}{
+		{"-d", opts.Data, opts.DataConvert},
+		{"-m", opts.Metadata, opts.MetadataConvert},
+		{"-s", opts.System, opts.SystemConvert},
 	}
-	if opts.Metadata {
-		filter := "mprofiles=metadata"
-		if opts.UsagePercent > 0 {
-			filter += fmt.Sprintf(",usage=%d", opts.UsagePercent)
-		}
-		if opts.LimitChunks > 0 {
-			filter += fmt.Sprintf(",limit=%d", opts.LimitChunks)
-		}
-		filters = append(filters, filter)
+
+	// Filters shared by every selected chunk type
+	var common []string
+	if opts.UsagePercent > 0 {
+		common = append(common, fmt.Sprintf("usage=%d", opts.UsagePercent))
 	}
-	if opts.System {
-		filter := "sprofiles=system"
-		if opts.UsagePercent > 0 {
-			filter += fmt.Sprintf(",usage=%d", opts.UsagePercent)
-		}
-		if opts.LimitChunks > 0 {
-			filter += fmt.Sprintf(",limit=%d", opts.LimitChunks)
+	if opts.Devid > 0 {
+		common = append(common, fmt.Sprintf("devid=%d", opts.Devid))
+		if opts.DrangeEnd > opts.DrangeStart {
+			common = append(common, fmt.Sprintf("drange=%d..%d", opts.DrangeStart, opts.DrangeEnd))
 		}
-		filters = append(filters, filter)
+	}
+	if opts.LimitChunks > 0 {
+		common = append(common, fmt.Sprintf("limit=%d", opts.LimitChunks))
 	}
 
-	// If no specific type selected, balance all with usage filter if provided
-	if !opts.Data && !opts.Metadata && !opts.System {
-		if opts.UsagePercent > 0 {
-			args = append(args, "-dusage="+strconv.Itoa(int(opts.UsagePercent)))
-			args = append(args, "-musage="+strconv.Itoa(int(opts.UsagePercent)))
+	selected := false
+	for _, tf := range typeFilters {
+		if !tf.enabled && tf.convert == "" {
+			continue
 		}
-		if opts.LimitChunks > 0 {
-			args = append(args, "-dlimit="+strconv.Itoa(int(opts.LimitChunks)))
-			args = append(args, "-mlimit="+strconv.Itoa(int(opts.LimitChunks)))
+		selected = true
+
+		var parts []string
+		if tf.convert != "" {
+			parts = append(parts, "convert="+strings.ToLower(tf.convert))
+			if opts.Soft {
+				parts = append(parts, "soft")
+			}
 		}
-	} else {
-		for _, f := range filters {
-			args = append(args, "-"+f)
+		parts = append(parts, common...)
+
+		if len(parts) == 0 {
+			args = append(args, tf.flag)
+		} else {
+			args = append(args, tf.flag+strings.Join(parts, ","))
 		}
 	}
 
-	if opts.Force {
+	// If no specific type selected, balance data and metadata with the shared filters if provided
+	if !selected && len(common) > 0 {
+		args = append(args, "-d"+strings.Join(common, ","))
+		args = append(args, "-m"+strings.Join(common, ","))
+	}
+
+	// btrfs only wants -f to convert or touch system chunks, and the helper
+	// refuses it anywhere else
+	converting := opts.DataConvert != "" || opts.MetadataConvert != "" || opts.SystemConvert != ""
+	if opts.Force && (converting || opts.System) {
 		args = append(args, "-f")
 	}
 
@@ -132,21 +167,32 @@ func (m *Manager) StartBalance(ctx context.Context, devicePath string, opts Bala
 
 	args = append(args, devicePath)
 
-	cmd := exec.CommandContext(balanceCtx, "btrfs", args...)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
-
 	go func() {
-		err := cmd.Run()
+		out, err := m.backend.Run(balanceCtx, args...)
+		if balanceCtx.Err() != nil {
+			err = balanceCtx.Err()
+		} else if err != nil {
+			err = fmt.Errorf("btrfs balance start failed: %w: %s", err, bytes.TrimSpace(out))
+		}
+
 		balanceMutex.Lock()
 		delete(activeBalances, devicePath)
+		active.err = err
+		finishedBalances[balanceID] = err
+		finishedOrder = append(finishedOrder, balanceID)
+		if len(finishedOrder) > maxFinishedBalances {
+			for _, id := range finishedOrder[:len(finishedOrder)-maxFinishedBalances] {
+				delete(finishedBalances, id)
+			}
+			finishedOrder = finishedOrder[len(finishedOrder)-maxFinishedBalances:]
+		}
+		close(active.done)
 		balanceMutex.Unlock()
 
 		if err != nil && balanceCtx.Err() == nil {
-			m.logger.Error("balance failed", "device", devicePath, "error", err, "output", out.String())
+			m.logger.Error("balance failed", "device", devicePath, "error", err, "output", string(out))
 		} else {
-			m.logger.Info("balance completed", "device", devicePath, "output", out.String())
+			m.logger.Info("balance completed", "device", devicePath, "output", string(out))
 		}
 	}()
 
@@ -168,13 +214,8 @@ func (m *Manager) CancelBalance(devicePath string) error {
 	active.cancel()
 
 	// Also try to cancel via btrfs command
-	cmd := exec.Command("btrfs", "balance", "cancel", devicePath)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
-
-	if err := cmd.Run(); err != nil {
-		m.logger.Warn("btrfs balance cancel command failed", "error", err, "output", out.String())
+	if out, err := m.backend.Run(context.Background(), "balance", "cancel", devicePath); err != nil {
+		m.logger.Warn("btrfs balance cancel command failed", "error", err, "output", string(out))
 		// Don't return error as we already canceled via context
 	}
 
@@ -182,15 +223,22 @@ func (m *Manager) CancelBalance(devicePath string) error {
 	return nil
 }
 
+// CancelPausedBalance cancels a paused balance registered in the kernel. Unlike
+// CancelBalance it doesn't require the balance to have been started by us.
+func (m *Manager) CancelPausedBalance(devicePath string) error {
+	if out, err := m.backend.Run(context.Background(), "balance", "cancel", devicePath); err != nil {
+		m.logger.Error("failed to cancel paused balance", "error", err, "output", string(out))
+		return fmt.Errorf("btrfs balance cancel failed: %w", err)
+	}
+
+	m.logger.Info("paused balance canceled", "device", devicePath)
+	return nil
+}
+
 // PauseBalance pauses a running balance
 func (m *Manager) PauseBalance(devicePath string) error {
-	cmd := exec.Command("btrfs", "balance", "pause", devicePath)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
-
-	if err := cmd.Run(); err != nil {
-		m.logger.Error("failed to pause balance", "error", err, "output", out.String())
+	if out, err := m.backend.Run(context.Background(), "balance", "pause", devicePath); err != nil {
+		m.logger.Error("failed to pause balance", "error", err, "output", string(out))
 		return fmt.Errorf("btrfs balance pause failed: %w", err)
 	}
 
@@ -200,13 +248,8 @@ func (m *Manager) PauseBalance(devicePath string) error {
 
 // ResumeBalance resumes a paused balance
 func (m *Manager) ResumeBalance(ctx context.Context, devicePath string) error {
-	cmd := exec.CommandContext(ctx, "btrfs", "balance", "resume", devicePath)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
-
-	if err := cmd.Run(); err != nil {
-		m.logger.Error("failed to resume balance", "error", err, "output", out.String())
+	if out, err := m.backend.Run(ctx, "balance", "resume", devicePath); err != nil {
+		m.logger.Error("failed to resume balance", "error", err, "output", string(out))
 		return fmt.Errorf("btrfs balance resume failed: %w", err)
 	}
 
@@ -216,14 +259,20 @@ func (m *Manager) ResumeBalance(ctx context.Context, devicePath string) error {
 
 // GetBalanceStatus gets the current balance status using ioctl
 func (m *Manager) GetBalanceStatus(devicePath string) (*BalanceStatus, error) {
-	progress, err := GetBalanceProgress(devicePath)
+	progress, err := GetBalanceProgress(m.backend, devicePath)
 	if err != nil {
 		return nil, fmt.Errorf("get balance progress: %w", err)
 	}
 
 	status := &BalanceStatus{
-		IsRunning: progress.IsRunning,
-		IsPaused:  progress.IsPaused,
+		IsRunning:   progress.IsRunning,
+		IsPaused:    progress.IsPaused,
+		TotalChunks: int64(progress.Expected),
+		Considered:  int64(progress.Considered),
+		Relocated:   int64(progress.Completed),
+	}
+	if status.TotalChunks > status.Relocated {
+		status.Left = status.TotalChunks - status.Relocated
 	}
 
 	if progress.IsRunning {
@@ -254,3 +303,37 @@ func (m *Manager) GetActiveBalanceID(devicePath string) string {
 	}
 	return ""
 }
+
+// WaitBalance blocks until the balance with the given ID finishes and returns
+// its result. A cancelled balance returns context.Canceled.
+func (m *Manager) WaitBalance(ctx context.Context, balanceID string) error {
+	balanceMutex.Lock()
+	if err, ok := finishedBalances[balanceID]; ok {
+		delete(finishedBalances, balanceID)
+		balanceMutex.Unlock()
+		return err
+	}
+	var active *activeBalance
+	for _, a := range activeBalances {
+		if a.id == balanceID {
+			active = a
+			break
+		}
+	}
+	balanceMutex.Unlock()
+
+	if active == nil {
+		return fmt.Errorf("unknown balance %s", balanceID)
+	}
+
+	select {
+	case <-active.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	balanceMutex.Lock()
+	delete(finishedBalances, balanceID)
+	balanceMutex.Unlock()
+	return active.err
+}
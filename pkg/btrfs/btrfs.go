@@ -3,6 +3,7 @@ package btrfs
 import (
 	"log/slog"
 
+	"github.com/elee1766/gobtr/pkg/privsep"
 	"go.uber.org/fx"
 )
 
@@ -17,11 +18,18 @@ var Module = fx.Module("btrfs",
 )
 
 type Manager struct {
-	logger *slog.Logger
+	logger  *slog.Logger
+	backend privsep.Backend
 }
 
-func New(logger *slog.Logger) *Manager {
+func New(logger *slog.Logger, backend privsep.Backend) *Manager {
 	return &Manager{
-		logger: logger.With("component", "btrfs"),
+		logger:  logger.With("component", "btrfs"),
+		backend: backend,
 	}
 }
+
+// Backend returns what the manager does privileged operations through
+func (m *Manager) Backend() privsep.Backend {
+	return m.backend
+}
@@ -145,7 +145,7 @@ func (m *Manager) GetFilesystemUsage(path string) (*FilesystemUsage, error) {
 	)
 
 	// Get per-device chunk allocations
-	chunkAllocs, err := GetDeviceChunkAllocations(path)
+	chunkAllocs, err := GetDeviceChunkAllocations(m.backend, path)
 	if err != nil {
 		m.logger.Warn("failed to get device chunk allocations", "error", err)
 	}
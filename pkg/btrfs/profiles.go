@@ -0,0 +1,150 @@
+package btrfs
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/elee1766/gobtr/pkg/privsep"
+)
+
+// kernelProfilePreference is the order in which the kernel picks a profile for
+// new chunks when more than one is present (btrfs_reduce_alloc_profile)
+var kernelProfilePreference = []string{"RAID6", "RAID5", "RAID1C4", "RAID1C3", "RAID10", "RAID1", "DUP", "RAID0", "single"}
+
+// ChunkProfileUsage counts the chunks of one block group type and profile
+type ChunkProfileUsage struct {
+	Type    string // "Data", "Metadata", "System"
+	Profile string // "single", "DUP", "RAID1", etc.
+	Chunks  int
+	Bytes   uint64 // Logical size of the chunks
+}
+
+// StaleProfiles describes a block group type that has chunks in more than one
+// profile, usually left behind by an interrupted conversion
+type StaleProfiles struct {
+	Type         string
+	Target       string               // Profile the remaining chunks should be converted to
+	TargetSource string               // "balance" if taken from a paused balance, "kernel" if from allocation preference
+	Current      *ChunkProfileUsage   // Chunks already in the target profile
+	Stale        []*ChunkProfileUsage // Chunks in any other profile
+}
+
+// GetChunkProfileUsage walks the chunk tree and counts chunks per type and profile
+func GetChunkProfileUsage(b privsep.Backend, path string) ([]*ChunkProfileUsage, error) {
+	f, err := b.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open path: %w", err)
+	}
+	defer f.Close()
+
+	results, err := treeSearch(b, f, ChunkTreeObjectID, 256, ^uint64(0), ChunkItemKey, ChunkItemKey, 0, ^uint64(0))
+	if err != nil {
+		return nil, fmt.Errorf("tree search for chunks: %w", err)
+	}
+
+	byKey := make(map[string]*ChunkProfileUsage)
+	for _, res := range results {
+		if res.Header.Type != ChunkItemKey || len(res.Data) < 32 {
+			continue
+		}
+
+		// btrfs_chunk: length at 0, type/flags at 24
+		length := binary.LittleEndian.Uint64(res.Data[0:8])
+		flags := binary.LittleEndian.Uint64(res.Data[24:32])
+
+		typ := getBlockGroupType(flags)
+		profile := getBlockGroupProfile(flags)
+		key := typ + ":" + profile
+		u, ok := byKey[key]
+		if !ok {
+			u = &ChunkProfileUsage{Type: typ, Profile: profile}
+			byKey[key] = u
+		}
+		u.Chunks++
+		u.Bytes += length
+	}
+
+	usages := make([]*ChunkProfileUsage, 0, len(byKey))
+	for _, u := range byKey {
+		usages = append(usages, u)
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Type != usages[j].Type {
+			return usages[i].Type < usages[j].Type
+		}
+		return usages[i].Profile < usages[j].Profile
+	})
+
+	return usages, nil
+}
+
+// DetectStaleProfiles finds block group types with chunks in more than one profile.
+//
+// The target profile is taken from a registered (paused) balance if it was
+// converting that type. Otherwise it is the profile the kernel would pick for
+// new chunks, which matches the target of an interrupted conversion to a more
+// redundant profile; for conversions to a less redundant profile, pass the
+// target explicitly when finishing the conversion.
+func DetectStaleProfiles(b privsep.Backend, path string) ([]*StaleProfiles, error) {
+	usages, err := GetChunkProfileUsage(b, path)
+	if err != nil {
+		return nil, err
+	}
+
+	balanceTargets := map[string]string{}
+	if progress, err := GetBalanceProgress(b, path); err == nil {
+		balanceTargets["Data"] = progress.DataConvert
+		balanceTargets["Metadata"] = progress.MetadataConvert
+		balanceTargets["System"] = progress.SystemConvert
+	}
+
+	byType := make(map[string][]*ChunkProfileUsage)
+	for _, u := range usages {
+		byType[u.Type] = append(byType[u.Type], u)
+	}
+
+	var stale []*StaleProfiles
+	for _, typ := range []string{"Data", "Metadata", "System"} {
+		group := byType[typ]
+		if len(group) < 2 {
+			continue
+		}
+
+		sp := &StaleProfiles{Type: typ}
+		if target := balanceTargets[typ]; target != "" {
+			sp.Target = target
+			sp.TargetSource = "balance"
+		} else {
+			sp.Target = preferredProfile(group)
+			sp.TargetSource = "kernel"
+		}
+
+		for _, u := range group {
+			if u.Profile == sp.Target {
+				sp.Current = u
+			} else {
+				sp.Stale = append(sp.Stale, u)
+			}
+		}
+
+		stale = append(stale, sp)
+	}
+
+	return stale, nil
+}
+
+// preferredProfile returns the profile the kernel allocates new chunks with
+// when the given profiles are all present
+func preferredProfile(usages []*ChunkProfileUsage) string {
+	present := make(map[string]bool, len(usages))
+	for _, u := range usages {
+		present[u.Profile] = true
+	}
+	for _, p := range kernelProfilePreference {
+		if present[p] {
+			return p
+		}
+	}
+	return usages[0].Profile
+}
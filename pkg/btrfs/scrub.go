@@ -1,15 +1,12 @@
 package btrfs
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/google/uuid"
@@ -86,40 +83,21 @@ func (m *Manager) StartScrubWithOptions(ctx context.Context, devicePath string,
 	// so it survives if the Go process dies
 	args = append(args, "-B", devicePath)
 
-	cmd := exec.Command("btrfs", args...)
-
-	// Detach the process: create new session so it's independent of Go process
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setsid: true, // Create new session (fully detached from parent)
-	}
-
-	// Redirect stdin/stdout/stderr to /dev/null so process is fully detached
-	cmd.Stdin = nil
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-
-	if err := cmd.Start(); err != nil {
+	// Spawned in its own session with no stdio, so the scrub continues even
+	// if we die
+	if err := m.backend.Spawn(args...); err != nil {
 		m.logger.Error("scrub start failed", "device", devicePath, "error", err)
 		return "", fmt.Errorf("failed to start scrub: %w", err)
 	}
 
-	// Don't wait - let it run independently
-	// The process is fully detached and will continue even if Go process dies
-	go cmd.Wait() // Goroutine to reap zombie when scrub eventually finishes
-
 	m.logger.Info("scrub started", "device", devicePath, "scrub_id", scrubID, "opts", opts)
 	return scrubID, nil
 }
 
 // CancelScrub cancels a running scrub
 func (m *Manager) CancelScrub(devicePath string) error {
-	cmd := exec.Command("btrfs", "scrub", "cancel", devicePath)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
-
-	if err := cmd.Run(); err != nil {
-		m.logger.Warn("btrfs scrub cancel command failed", "error", err, "output", out.String())
+	if out, err := m.backend.Run(context.Background(), "scrub", "cancel", devicePath); err != nil {
+		m.logger.Warn("btrfs scrub cancel command failed", "error", err, "output", string(out))
 		return fmt.Errorf("failed to cancel scrub: %w", err)
 	}
 
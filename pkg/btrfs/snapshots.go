@@ -19,7 +19,7 @@ type SubvolumeInfo struct {
 
 // ListSubvolumes lists all subvolumes for a filesystem using ioctl
 func (m *Manager) ListSubvolumes(mountPoint string) ([]*SubvolumeInfo, error) {
-	ioctlData, err := ListSubvolumesIoctl(mountPoint)
+	ioctlData, err := ListSubvolumesIoctl(m.backend, mountPoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list subvolumes via ioctl: %w", err)
 	}
@@ -4,10 +4,13 @@ import (
 	"encoding/binary"
 	"fmt"
 	"os"
+	"path"
+	"strings"
 	"time"
 	"unsafe"
 
 	"github.com/dennwc/ioctl"
+	"github.com/elee1766/gobtr/pkg/privsep"
 )
 
 // btrfs ioctl magic number
@@ -102,6 +105,9 @@ type btrfsIoctlFsInfoArgs struct {
 	Reserved       [944]byte
 }
 
+// fsInfoFlagGeneration asks BTRFS_IOC_FS_INFO to fill in the generation
+const fsInfoFlagGeneration = 1 << 1
+
 var ioctlFsInfo = ioctl.IOR(btrfsIoctlMagic, 31, unsafe.Sizeof(btrfsIoctlFsInfoArgs{}))
 
 // BTRFS_DEVICE_PATH_NAME_MAX from kernel headers
@@ -370,14 +376,15 @@ func GetSpaceInfo(path string) ([]*SpaceInfoIoctl, error) {
 type DeviceChunkAllocation struct {
 	DevID      uint64
 	ChunkStart uint64 // Logical address of chunk
+	Offset     uint64 // Physical offset of the stripe on this device
 	Length     uint64 // Size on this device
 	Type       string // Data/Metadata/System
 	Profile    string // single/dup/raid1/etc
 }
 
 // GetDeviceChunkAllocations gets per-device chunk allocations by searching the device tree
-func GetDeviceChunkAllocations(path string) ([]*DeviceChunkAllocation, error) {
-	f, err := os.OpenFile(path, os.O_RDONLY, 0)
+func GetDeviceChunkAllocations(b privsep.Backend, path string) ([]*DeviceChunkAllocation, error) {
+	f, err := b.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("open path: %w", err)
 	}
@@ -385,7 +392,7 @@ func GetDeviceChunkAllocations(path string) ([]*DeviceChunkAllocation, error) {
 
 	// First, get chunk info from the chunk tree to map chunk_start -> flags
 	chunkFlags := make(map[uint64]uint64)
-	chunkResults, err := treeSearch(f, ChunkTreeObjectID, 256, ^uint64(0), ChunkItemKey, ChunkItemKey, 0, ^uint64(0))
+	chunkResults, err := treeSearch(b, f, ChunkTreeObjectID, 256, ^uint64(0), ChunkItemKey, ChunkItemKey, 0, ^uint64(0))
 	if err == nil {
 		for _, res := range chunkResults {
 			if res.Header.Type == ChunkItemKey && len(res.Data) >= 32 {
@@ -403,7 +410,7 @@ func GetDeviceChunkAllocations(path string) ([]*DeviceChunkAllocation, error) {
 
 	// Search the device tree for dev extents
 	// Object ID is the device ID, type is DEV_EXTENT_KEY (204), offset is physical offset on device
-	results, err := treeSearch(f, DevTreeObjectID, 1, ^uint64(0), DevExtentKey, DevExtentKey, 0, ^uint64(0))
+	results, err := treeSearch(b, f, DevTreeObjectID, 1, ^uint64(0), DevExtentKey, DevExtentKey, 0, ^uint64(0))
 	if err != nil {
 		return nil, fmt.Errorf("tree search for dev extents: %w", err)
 	}
@@ -433,6 +440,7 @@ func GetDeviceChunkAllocations(path string) ([]*DeviceChunkAllocation, error) {
 		allocs = append(allocs, &DeviceChunkAllocation{
 			DevID:      devID,
 			ChunkStart: chunkStart,
+			Offset:     res.Header.Offset,
 			Length:     length,
 			Type:       getBlockGroupType(flags),
 			Profile:    getBlockGroupProfile(flags),
@@ -458,36 +466,44 @@ func getBlockGroupType(flags uint64) string {
 	return "unknown"
 }
 
-// btrfsIoctlBalanceArgs for BTRFS_IOC_BALANCE_PROGRESS
+// btrfsIoctlBalanceArgs for BTRFS_IOC_BALANCE_PROGRESS (struct btrfs_ioctl_balance_args, 1024 bytes)
 type btrfsIoctlBalanceArgs struct {
-	Flags uint64
-	State uint64
-	Data  btrfsBalanceArgs
-	Meta  btrfsBalanceArgs
-	Sys   btrfsBalanceArgs
+	Flags  uint64
+	State  uint64
+	Data   btrfsBalanceArgs
+	Meta   btrfsBalanceArgs
+	Sys    btrfsBalanceArgs
+	Stat   btrfsBalanceProgress
+	Unused [72]uint64
 }
 
+// btrfsBalanceArgs mirrors struct btrfs_balance_args (136 bytes)
 type btrfsBalanceArgs struct {
-	Profiles    uint64
-	Usage       uint64
-	UsageMin    uint32
-	UsageMax    uint32
-	Devid       uint64
-	PStart      uint64
-	PEnd        uint64
-	VStart      uint64
-	VEnd        uint64
-	Target      uint64
-	Flags       uint64
-	Limit       uint64
-	LimitMin    uint32
-	LimitMax    uint32
-	Stripes     uint32
-	StripesMin  uint32
-	StripesMax  uint32
-	Unused      [6]uint64
+	Profiles   uint64
+	Usage      uint64 // union with usage_min/usage_max
+	Devid      uint64
+	PStart     uint64
+	PEnd       uint64
+	VStart     uint64
+	VEnd       uint64
+	Target     uint64
+	Flags      uint64
+	Limit      uint64 // union with limit_min/limit_max
+	StripesMin uint32
+	StripesMax uint32
+	Unused     [6]uint64
 }
 
+// btrfsBalanceProgress mirrors struct btrfs_balance_progress
+type btrfsBalanceProgress struct {
+	Expected   uint64 // Estimated number of chunks that will be relocated
+	Considered uint64 // Number of chunks considered so far
+	Completed  uint64 // Number of chunks relocated so far
+}
+
+// balanceArgsConvert is BTRFS_BALANCE_ARGS_CONVERT, set when Target holds a convert profile
+const balanceArgsConvert = 1 << 8
+
 // Balance state flags
 const (
 	BalanceStateRunning = 1 << 0
@@ -495,25 +511,33 @@ const (
 	BalanceStateCancelReq = 1 << 2
 )
 
-var ioctlBalanceProgress = ioctl.IOWR(btrfsIoctlMagic, 34, unsafe.Sizeof(btrfsIoctlBalanceArgs{}))
+var ioctlBalanceProgress = ioctl.IOR(btrfsIoctlMagic, 34, unsafe.Sizeof(btrfsIoctlBalanceArgs{}))
 
 // BalanceProgressIoctl contains balance progress from ioctl
 type BalanceProgressIoctl struct {
-	IsRunning bool
-	IsPaused  bool
-	State     uint64
+	IsRunning  bool
+	IsPaused   bool
+	State      uint64
+	Expected   uint64
+	Considered uint64
+	Completed  uint64
+
+	// Convert targets of the registered balance, empty if not converting
+	DataConvert     string
+	MetadataConvert string
+	SystemConvert   string
 }
 
 // GetBalanceProgress gets balance progress via BTRFS_IOC_BALANCE_PROGRESS
-func GetBalanceProgress(path string) (*BalanceProgressIoctl, error) {
-	f, err := os.OpenFile(path, os.O_RDONLY, 0)
+func GetBalanceProgress(b privsep.Backend, path string) (*BalanceProgressIoctl, error) {
+	f, err := b.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("open path: %w", err)
 	}
 	defer f.Close()
 
 	var args btrfsIoctlBalanceArgs
-	if err := ioctl.Do(f, ioctlBalanceProgress, &args); err != nil {
+	if err := b.Ioctl(f, ioctlBalanceProgress, &args); err != nil {
 		// ENOTCONN means no balance running
 		return &BalanceProgressIoctl{
 			IsRunning: false,
@@ -521,13 +545,31 @@ func GetBalanceProgress(path string) (*BalanceProgressIoctl, error) {
 		}, nil
 	}
 
+	// The kernel only answers while a balance is registered, so a balance
+	// that is not running has been paused (or is being paused)
+	running := args.State&BalanceStateRunning != 0
 	return &BalanceProgressIoctl{
-		IsRunning: args.State&BalanceStateRunning != 0,
-		IsPaused:  args.State&BalanceStatePauseReq != 0,
-		State:     args.State,
+		IsRunning:  running,
+		IsPaused:   !running || args.State&BalanceStatePauseReq != 0,
+		State:      args.State,
+		Expected:   args.Stat.Expected,
+		Considered: args.Stat.Considered,
+		Completed:  args.Stat.Completed,
+
+		DataConvert:     convertTarget(args.Data),
+		MetadataConvert: convertTarget(args.Meta),
+		SystemConvert:   convertTarget(args.Sys),
 	}, nil
 }
 
+// convertTarget returns the profile a balance converts to, or "" if it doesn't convert
+func convertTarget(args btrfsBalanceArgs) string {
+	if args.Flags&balanceArgsConvert == 0 {
+		return ""
+	}
+	return getBlockGroupProfile(args.Target)
+}
+
 func getBlockGroupProfile(flags uint64) string {
 	switch {
 	case flags&BlockGroupRaid1C4 != 0:
@@ -569,7 +611,8 @@ func GetFilesystemInfo(path string) (*FilesystemInfo, error) {
 	}
 	defer f.Close()
 
-	var args btrfsIoctlFsInfoArgs
+	// The generation is only filled in when asked for
+	args := btrfsIoctlFsInfoArgs{Flags: fsInfoFlagGeneration}
 	if err := ioctl.Do(f, ioctlFsInfo, &args); err != nil {
 		return nil, fmt.Errorf("not a btrfs filesystem: %w", err)
 	}
@@ -655,20 +698,20 @@ func formatUUID(uuid [16]byte) string {
 }
 
 // ListSubvolumesIoctl lists all subvolumes using the tree search ioctl
-func ListSubvolumesIoctl(fsPath string) ([]SubvolumeIoctl, error) {
-	f, err := os.OpenFile(fsPath, os.O_RDONLY, 0)
+func ListSubvolumesIoctl(b privsep.Backend, fsPath string) ([]SubvolumeIoctl, error) {
+	f, err := b.Open(fsPath)
 	if err != nil {
 		return nil, fmt.Errorf("open filesystem: %w", err)
 	}
 	defer f.Close()
 
-	subvolumes, err := listSubvolumesFromFile(f)
+	subvolumes, err := listSubvolumesFromFile(b, f)
 	if err != nil {
 		return nil, err
 	}
 
 	// Resolve paths for each subvolume using ROOT_BACKREF entries
-	pathMap, err := getSubvolumePaths(f)
+	pathMap, err := getSubvolumePaths(b, f)
 	if err != nil {
 		// Paths are optional, continue without them
 		return subvolumes, nil
@@ -684,9 +727,9 @@ func ListSubvolumesIoctl(fsPath string) ([]SubvolumeIoctl, error) {
 }
 
 // getSubvolumePaths builds a map of subvolume ID to path using ROOT_BACKREF entries
-func getSubvolumePaths(f *os.File) (map[uint64]string, error) {
+func getSubvolumePaths(b privsep.Backend, f *os.File) (map[uint64]string, error) {
 	// Search for ROOT_BACKREF entries which contain the name and parent info
-	results, err := treeSearch(f, RootTreeObjectID, FirstFreeObjectID, ^uint64(0), RootBackrefKey, RootBackrefKey, 0, ^uint64(0))
+	results, err := treeSearch(b, f, RootTreeObjectID, FirstFreeObjectID, ^uint64(0), RootBackrefKey, RootBackrefKey, 0, ^uint64(0))
 	if err != nil {
 		return nil, fmt.Errorf("tree search for backrefs: %w", err)
 	}
@@ -761,10 +804,100 @@ func getSubvolumePaths(f *os.File) (map[uint64]string, error) {
 	return pathMap, nil
 }
 
-func listSubvolumesFromFile(f *os.File) ([]SubvolumeIoctl, error) {
+// btrfsIoctlInoLookupArgs matches struct btrfs_ioctl_ino_lookup_args
+type btrfsIoctlInoLookupArgs struct {
+	TreeID   uint64
+	ObjectID uint64
+	Name     [4080]byte
+}
+
+var ioctlInoLookup = ioctl.IOWR(btrfsIoctlMagic, 18, unsafe.Sizeof(btrfsIoctlInoLookupArgs{}))
+
+// SubvolumePaths maps the ID of every subvolume below the top level to its
+// path from the top-level subvolume, like "home/.snapshots/1". Unlike the
+// paths ListSubvolumesIoctl resolves, these include the directories between
+// a subvolume and its parent.
+func SubvolumePaths(b privsep.Backend, fsPath string) (map[uint64]string, error) {
+	f, err := b.Open(fsPath)
+	if err != nil {
+		return nil, fmt.Errorf("open filesystem: %w", err)
+	}
+	defer f.Close()
+
+	results, err := treeSearch(b, f, RootTreeObjectID, FirstFreeObjectID, ^uint64(0), RootBackrefKey, RootBackrefKey, 0, ^uint64(0))
+	if err != nil {
+		return nil, fmt.Errorf("tree search for backrefs: %w", err)
+	}
+
+	// ROOT_BACKREF: objectid is the subvolume, offset its parent; the item
+	// is the directory holding it, a sequence number and the name
+	type backref struct {
+		parent uint64
+		dirID  uint64
+		name   string
+	}
+	backrefs := make(map[uint64]backref)
+	for _, r := range results {
+		if r.Header.Type != RootBackrefKey || len(r.Data) < 18 {
+			continue
+		}
+		nameLen := int(binary.LittleEndian.Uint16(r.Data[16:18]))
+		if len(r.Data) < 18+nameLen {
+			continue
+		}
+		backrefs[r.Header.ObjectID] = backref{
+			parent: r.Header.Offset,
+			dirID:  binary.LittleEndian.Uint64(r.Data[0:8]),
+			name:   string(r.Data[18 : 18+nameLen]),
+		}
+	}
+
+	paths := make(map[uint64]string)
+	var resolve func(id uint64, depth int) (string, bool)
+	resolve = func(id uint64, depth int) (string, bool) {
+		if id == 5 {
+			return "", true
+		}
+		if p, ok := paths[id]; ok {
+			return p, true
+		}
+		br, ok := backrefs[id]
+		if !ok || depth > len(backrefs) {
+			return "", false
+		}
+		parent, ok := resolve(br.parent, depth+1)
+		if !ok {
+			return "", false
+		}
+
+		// The directory's path inside the parent, with a trailing slash,
+		// or empty for the parent's root directory
+		var dir string
+		if br.dirID != FirstFreeObjectID {
+			args := btrfsIoctlInoLookupArgs{TreeID: br.parent, ObjectID: br.dirID}
+			if err := b.Ioctl(f, ioctlInoLookup, &args); err != nil {
+				return "", false
+			}
+			dir = string(args.Name[:])
+			if n := strings.IndexByte(dir, 0); n >= 0 {
+				dir = dir[:n]
+			}
+		}
+
+		p := path.Join(parent, dir, br.name)
+		paths[id] = p
+		return p, true
+	}
+	for id := range backrefs {
+		resolve(id, 0)
+	}
+	return paths, nil
+}
+
+func listSubvolumesFromFile(b privsep.Backend, f *os.File) ([]SubvolumeIoctl, error) {
 	// Search the root tree for all ROOT_ITEM entries
 	// Subvolume IDs start at 256 (5 is the FS_TREE root, 256+ are user subvolumes)
-	results, err := treeSearch(f, RootTreeObjectID, 5, ^uint64(0), RootItemKey, RootItemKey, 0, ^uint64(0))
+	results, err := treeSearch(b, f, RootTreeObjectID, 5, ^uint64(0), RootItemKey, RootItemKey, 0, ^uint64(0))
 	if err != nil {
 		return nil, fmt.Errorf("tree search: %w", err)
 	}
@@ -862,7 +995,7 @@ func parseTimespec(data []byte) time.Time {
 }
 
 // treeSearch performs a tree search ioctl
-func treeSearch(f *os.File, treeID uint64, minObjID, maxObjID uint64, minType, maxType uint32, minOffset, maxOffset uint64) ([]SearchResult, error) {
+func treeSearch(b privsep.Backend, f *os.File, treeID uint64, minObjID, maxObjID uint64, minType, maxType uint32, minOffset, maxOffset uint64) ([]SearchResult, error) {
 	var results []SearchResult
 
 	args := btrfsIoctlSearchArgs{
@@ -881,7 +1014,7 @@ func treeSearch(f *os.File, treeID uint64, minObjID, maxObjID uint64, minType, m
 	}
 
 	for {
-		err := ioctl.Do(f, ioctlTreeSearch, &args)
+		err := b.Ioctl(f, ioctlTreeSearch, &args)
 		if err != nil {
 			return nil, fmt.Errorf("tree search ioctl: %w", err)
 		}
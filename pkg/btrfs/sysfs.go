@@ -187,3 +187,29 @@ func readSysfsInt64(path string) (int64, error) {
 	}
 	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
 }
+
+// IsRotational reports whether the block device backing devPath is a spinning
+// disk, based on /sys/class/block/<dev>/queue/rotational. Partitions report the
+// value of their parent disk.
+func IsRotational(devPath string) (bool, error) {
+	resolved, err := filepath.EvalSymlinks(devPath)
+	if err != nil {
+		return false, fmt.Errorf("resolve device path: %w", err)
+	}
+
+	sysPath, err := filepath.EvalSymlinks(filepath.Join("/sys/class/block", filepath.Base(resolved)))
+	if err != nil {
+		return false, fmt.Errorf("resolve sysfs path: %w", err)
+	}
+
+	val, err := readSysfsInt64(filepath.Join(sysPath, "queue", "rotational"))
+	if err != nil {
+		// Partitions don't have a queue directory, use the parent disk
+		val, err = readSysfsInt64(filepath.Join(filepath.Dir(sysPath), "queue", "rotational"))
+		if err != nil {
+			return false, fmt.Errorf("read rotational flag: %w", err)
+		}
+	}
+
+	return val != 0, nil
+}
@@ -0,0 +1,214 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/elee1766/gobtr/pkg/btrfs"
+	"github.com/elee1766/gobtr/pkg/db"
+	"github.com/elee1766/gobtr/pkg/db/queries"
+	"github.com/elee1766/gobtr/pkg/settings"
+	"go.uber.org/fx"
+)
+
+// historyRetention is how long usage and device stats samples are kept
+const historyRetention = 2 * 365 * 24 * time.Hour
+
+var Module = fx.Module("collector",
+	fx.Provide(New),
+	fx.Invoke(registerHooks),
+)
+
+// Collector periodically records usage and device stats for every tracked
+// filesystem so that trends can be computed later.
+type Collector struct {
+	logger       *slog.Logger
+	db           *db.DB
+	btrfsManager *btrfs.Manager
+	interval     time.Duration
+	// Set when the collect interval setting changes, and signalled on
+	// intervalChanged
+	nextInterval    atomic.Int64
+	intervalChanged chan struct{}
+}
+
+func New(logger *slog.Logger, db *db.DB, btrfsManager *btrfs.Manager, settingsStore *settings.Store) *Collector {
+	c := &Collector{
+		logger:          logger.With("component", "collector"),
+		db:              db,
+		btrfsManager:    btrfsManager,
+		interval:        settingsStore.Get().CollectInterval,
+		intervalChanged: make(chan struct{}, 1),
+	}
+	settingsStore.Subscribe(func(s settings.Settings) {
+		c.nextInterval.Store(int64(s.CollectInterval))
+		select {
+		case c.intervalChanged <- struct{}{}:
+		default:
+		}
+	})
+	return c
+}
+
+// CollectOnce records one sample for every tracked filesystem
+func (c *Collector) CollectOnce(ctx context.Context) {
+	filesystems, err := c.db.ListFilesystems()
+	if err != nil {
+		c.logger.Error("failed to list filesystems", "error", err)
+		return
+	}
+
+	now := time.Now()
+	for _, fs := range filesystems {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := c.collectUsage(fs.UUID, fs.Path, now); err != nil {
+			c.logger.Warn("failed to record usage", "path", fs.Path, "error", err)
+		}
+		if err := c.collectDeviceStats(fs.Path, now); err != nil {
+			c.logger.Warn("failed to record device stats", "path", fs.Path, "error", err)
+		}
+	}
+
+	cutoff := now.Add(-historyRetention)
+	if _, err := queries.DeleteUsageHistoryBefore(c.db.Conn(), cutoff); err != nil {
+		c.logger.Warn("failed to prune usage history", "error", err)
+	}
+	if _, err := queries.DeleteDeviceStatsBefore(c.db.Conn(), cutoff); err != nil {
+		c.logger.Warn("failed to prune device stats", "error", err)
+	}
+}
+
+func (c *Collector) collectUsage(fsUUID, path string, now time.Time) error {
+	usage, err := c.btrfsManager.GetFilesystemUsage(path)
+	if err != nil {
+		return err
+	}
+
+	sample := NewUsageSample(fsUUID, usage, now)
+	return queries.InsertUsageSample(c.db.Conn(), sample)
+}
+
+// NewUsageSample converts a usage reading into a history row. Each block group
+// type is summed across profiles, and the profile holding the most space is
+// kept as the one new chunks are most likely to use.
+func NewUsageSample(fsUUID string, usage *btrfs.FilesystemUsage, at time.Time) *queries.UsageSample {
+	sample := &queries.UsageSample{
+		FsUUID:            fsUUID,
+		Timestamp:         at,
+		DeviceSize:        usage.DeviceSize,
+		DeviceAllocated:   usage.DeviceAllocated,
+		DeviceUnallocated: usage.DeviceUnallocated,
+		GlobalReserve:     usage.GlobalReserve,
+		GlobalReserveUsed: usage.GlobalReserveUsed,
+		FreeEstimated:     usage.FreeEstimated,
+	}
+
+	var dataProfileSize, metadataProfileSize int64
+	for _, ag := range usage.Allocations {
+		switch strings.ToLower(ag.Type) {
+		case "data":
+			sample.DataTotal += ag.Size
+			sample.DataUsed += ag.Used
+			if ag.Size > dataProfileSize {
+				dataProfileSize = ag.Size
+				sample.DataProfile = ag.Profile
+			}
+		case "metadata":
+			sample.MetadataTotal += ag.Size
+			sample.MetadataUsed += ag.Used
+			if ag.Size > metadataProfileSize {
+				metadataProfileSize = ag.Size
+				sample.MetadataProfile = ag.Profile
+			}
+		case "system":
+			sample.SystemTotal += ag.Size
+			sample.SystemUsed += ag.Used
+		}
+	}
+
+	return sample
+}
+
+func (c *Collector) collectDeviceStats(path string, now time.Time) error {
+	devices, err := c.btrfsManager.GetDeviceStats(path)
+	if err != nil {
+		return err
+	}
+
+	for _, dev := range devices {
+		// Skip the synthetic multi-device total
+		if dev.DevicePath == "total" {
+			continue
+		}
+		err := queries.InsertDeviceStats(c.db.Conn(), &queries.DeviceStatsSnapshot{
+			DevicePath:       dev.DevicePath,
+			Timestamp:        now,
+			TotalBytes:       dev.TotalBytes,
+			UsedBytes:        dev.UsedBytes,
+			FreeBytes:        dev.FreeBytes,
+			WriteErrors:      dev.WriteErrors,
+			ReadErrors:       dev.ReadErrors,
+			FlushErrors:      dev.FlushErrors,
+			CorruptionErrors: dev.CorruptionErrors,
+			GenerationErrors: dev.GenerationErrors,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Collector) run(ctx context.Context) {
+	c.logger.Info("starting usage collector", "interval", c.interval)
+
+	c.CollectOnce(ctx)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.CollectOnce(ctx)
+		case <-c.intervalChanged:
+			if interval := time.Duration(c.nextInterval.Load()); interval != c.interval {
+				c.logger.Info("collect interval changed", "interval", interval)
+				c.interval = interval
+				ticker.Reset(interval)
+			}
+		}
+	}
+}
+
+func registerHooks(lc fx.Lifecycle, c *Collector) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				c.run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			c.logger.Info("stopping usage collector")
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
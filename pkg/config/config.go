@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 )
 
 const (
@@ -20,16 +22,49 @@ type Config struct {
 	// Derived paths
 	DBPath       string // SQLite database path
 	BTDUStoreDir string // btdu usage samples directory
+	ScanStateDir string // Checkpoints of resumable file scans
 
 	// Server
-	APIAddress string
+	APIAddress string // "none" to only listen on the unix socket
+	TLS        TLSFile
+	Socket     SocketFile
+	Headless   bool // Don't serve the web UI
+
+	// Refuse mutating RPCs and skip scheduled maintenance
+	ReadOnly bool
+
+	// Serve /debug/pprof/ to admins
+	Pprof bool
+
+	// Socket of the privileged helper, empty to do everything in process
+	Helper string
+
+	// Background collection
+	CollectInterval time.Duration // How often usage history is recorded
+
+	// Usage sampling
+	SampleTarget uint64 // Samples a usage scan takes before stopping
+
+	// Diagnostic thresholds from the config file (zero = default)
+	Alerts AlertsFile
+
+	// Authentication from the config file (disabled by default)
+	Auth AuthFile
+
+	// Agents to collect from, if this server is a hub
+	Hub HubFile
 
 	// Logging
 	LogLevel string
+
+	// Config file, if there is one, and what it declared
+	ConfigFile string
+	File       *File
 }
 
-// New creates a new Config with values from environment or defaults.
-func New() *Config {
+// New creates a new Config with values from environment, the config file or
+// defaults, in that order.
+func New() (*Config, error) {
 	cfg := &Config{}
 
 	// Base directories (XDG Base Directory Specification)
@@ -42,17 +77,84 @@ func New() *Config {
 	os.MkdirAll(cfg.ConfigDir, 0755)
 	os.MkdirAll(cfg.CacheDir, 0755)
 
+	// Config file
+	cfg.ConfigFile = FindConfigFile(cfg.ConfigDir)
+	if err := cfg.loadFile(); err != nil {
+		return nil, err
+	}
+
 	// Derived paths
 	cfg.DBPath = envOrDefault("GOBTR_DB_PATH", filepath.Join(cfg.DataDir, "gobtr.db"))
 	cfg.BTDUStoreDir = envOrDefault("GOBTR_BTDU_DIR", filepath.Join(cfg.DataDir, "btdu"))
+	cfg.ScanStateDir = envOrDefault("GOBTR_SCAN_STATE_DIR", filepath.Join(cfg.CacheDir, "scans"))
 
 	// Server config
-	cfg.APIAddress = envOrDefault("GOBTR_API_ADDRESS", ":8147")
+	cfg.APIAddress = envOrDefault("GOBTR_API_ADDRESS", fileOrDefault(cfg.File.Listen, ":8147"))
+	cfg.TLS = cfg.File.TLS
+	cfg.TLS.Cert = envOrDefault("GOBTR_TLS_CERT", cfg.TLS.Cert)
+	cfg.TLS.Key = envOrDefault("GOBTR_TLS_KEY", cfg.TLS.Key)
+	cfg.Socket = cfg.File.Socket
+	cfg.Socket.Path = envOrDefault("GOBTR_SOCKET", cfg.Socket.Path)
+	cfg.ReadOnly = envBoolOrDefault("GOBTR_READ_ONLY", cfg.File.ReadOnly)
+	cfg.Headless = envBoolOrDefault("GOBTR_HEADLESS", cfg.File.Headless)
+	cfg.Pprof = envBoolOrDefault("GOBTR_PPROF", cfg.File.Pprof)
+	cfg.Helper = envOrDefault("GOBTR_HELPER", cfg.File.Helper)
+
+	// Runtime settings the config file can change on reload
+	cfg.applyFile()
 
 	// Logging
 	cfg.LogLevel = envOrDefault("GOBTR_LOG_LEVEL", "info")
 
-	return cfg
+	return cfg, nil
+}
+
+// Reload returns a copy of the config with the config file read again. Only
+// the settings that can change while running are updated; the listen address
+// and paths stay as they were.
+func (c *Config) Reload() (*Config, error) {
+	next := *c
+	next.ConfigFile = FindConfigFile(c.ConfigDir)
+	if err := next.loadFile(); err != nil {
+		return nil, err
+	}
+	next.applyFile()
+	return &next, nil
+}
+
+// loadFile reads ConfigFile, or uses an empty File if there is none
+func (c *Config) loadFile() error {
+	c.File = &File{}
+	if c.ConfigFile == "" {
+		return nil
+	}
+	f, err := LoadFile(c.ConfigFile)
+	if err != nil {
+		return err
+	}
+	c.File = f
+	return nil
+}
+
+// applyFile sets the runtime settings from env, the file or defaults
+func (c *Config) applyFile() {
+	// Background collection
+	collectInterval := 15 * time.Minute
+	if c.File.CollectInterval > 0 {
+		collectInterval = c.File.CollectInterval
+	}
+	c.CollectInterval = envDurationOrDefault("GOBTR_COLLECT_INTERVAL", collectInterval)
+
+	// Usage sampling
+	sampleTarget := uint64(500000)
+	if c.File.SampleTarget > 0 {
+		sampleTarget = c.File.SampleTarget
+	}
+	c.SampleTarget = envUintOrDefault("GOBTR_SAMPLE_TARGET", sampleTarget)
+
+	c.Alerts = c.File.Alerts
+	c.Auth = c.File.Auth
+	c.Hub = c.File.Hub
 }
 
 // getDataDir returns the data directory following XDG spec.
@@ -102,6 +204,47 @@ func envOrDefault(key, defaultVal string) string {
 	return defaultVal
 }
 
+// fileOrDefault returns the config file value, or the default if it is unset.
+func fileOrDefault(val, defaultVal string) string {
+	if val != "" {
+		return val
+	}
+	return defaultVal
+}
+
+// envDurationOrDefault returns the environment variable parsed as a duration,
+// or the default if it is unset or invalid.
+func envDurationOrDefault(key string, defaultVal time.Duration) time.Duration {
+	if val := os.Getenv(key); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultVal
+}
+
+// envUintOrDefault returns the environment variable parsed as a positive
+// integer, or the default if it is unset or invalid.
+func envUintOrDefault(key string, defaultVal uint64) uint64 {
+	if val := os.Getenv(key); val != "" {
+		if n, err := strconv.ParseUint(val, 10, 64); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultVal
+}
+
+// envBoolOrDefault returns the environment variable parsed as a bool, or the
+// default if it is unset or invalid.
+func envBoolOrDefault(key string, defaultVal bool) bool {
+	if val := os.Getenv(key); val != "" {
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	}
+	return defaultVal
+}
+
 // SubPath returns a path under the data directory.
 func (c *Config) SubPath(parts ...string) string {
 	return filepath.Join(append([]string{c.DataDir}, parts...)...)
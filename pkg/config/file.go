@@ -0,0 +1,503 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/dustin/go-humanize"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileNames are the names looked for in ConfigDir, in order
+var ConfigFileNames = []string{"config.toml", "config.yaml", "config.yml"}
+
+// File is the config file. Everything in it is optional; env vars still win
+// over it, and it wins over the built-in defaults.
+type File struct {
+	// API server address, or "none" to only listen on the unix socket
+	Listen string `toml:"listen" yaml:"listen"`
+	// Serve TLS on the listen address
+	TLS TLSFile `toml:"tls" yaml:"tls"`
+	// Also listen on a unix socket
+	Socket SocketFile `toml:"socket" yaml:"socket"`
+	// Only serve the API, not the web UI, e.g. on an agent a hub polls
+	Headless bool `toml:"headless" yaml:"headless"`
+	// How often usage history is recorded
+	CollectInterval time.Duration `toml:"collect_interval" yaml:"collect_interval"`
+	// Samples a usage scan takes before stopping
+	SampleTarget uint64 `toml:"sample_target" yaml:"sample_target"`
+
+	// Socket of a privileged `gobtr helper` to do the btrfs operations that
+	// need root, so the server doesn't
+	Helper string `toml:"helper" yaml:"helper"`
+
+	// Refuse every mutating RPC and don't run schedules. Only read at
+	// startup, so a reload can't turn it off.
+	ReadOnly bool `toml:"read_only" yaml:"read_only"`
+
+	// Serve /debug/pprof/ to admins
+	Pprof bool `toml:"pprof" yaml:"pprof"`
+
+	// Untrack filesystems that are not declared here
+	PruneFilesystems bool `toml:"prune_filesystems" yaml:"prune_filesystems"`
+
+	Alerts      AlertsFile       `toml:"alerts" yaml:"alerts"`
+	Auth        AuthFile         `toml:"auth" yaml:"auth"`
+	Hub         HubFile          `toml:"hub" yaml:"hub"`
+	Filesystems []FilesystemFile `toml:"filesystem" yaml:"filesystems"`
+}
+
+// HubFile lists the agents this server collects from, so one UI covers
+// many hosts. Each agent is a gobtr server with a token for the hub.
+type HubFile struct {
+	// How often agents are health checked (default 30s)
+	PollInterval time.Duration `toml:"poll_interval" yaml:"poll_interval"`
+	// How long a call to an agent may take (default 10s)
+	Timeout time.Duration `toml:"timeout" yaml:"timeout"`
+
+	Agents []AgentFile `toml:"agent" yaml:"agents"`
+}
+
+// AgentFile is one agent. Give either its token or a file holding it, so
+// the config file doesn't have to hold the secret.
+type AgentFile struct {
+	Name      string `toml:"name" yaml:"name"`
+	URL       string `toml:"url" yaml:"url"` // e.g. "https://nas01:8147"
+	Token     string `toml:"token" yaml:"token"`
+	TokenFile string `toml:"token_file" yaml:"token_file"`
+	// CA bundle to verify the agent's certificate, if the system roots
+	// don't
+	CA string `toml:"ca" yaml:"ca"`
+}
+
+// AlertsFile sets the diagnostic thresholds. Zero keeps the default.
+type AlertsFile struct {
+	ScrubMaxAgeDays  int     `toml:"scrub_max_age_days" yaml:"scrub_max_age_days"`
+	SlackPercent     float64 `toml:"slack_percent" yaml:"slack_percent"`
+	MinSlack         string  `toml:"min_slack" yaml:"min_slack"` // Size, e.g. "10GiB"
+	ImbalancePercent float64 `toml:"imbalance_percent" yaml:"imbalance_percent"`
+}
+
+// AuthFile configures who can use the web UI and API. With auth disabled
+// everyone who can reach the server is an admin.
+type AuthFile struct {
+	Enabled bool `toml:"enabled" yaml:"enabled"`
+	// How long a web UI login lasts (default 7 days)
+	SessionTTL time.Duration `toml:"session_ttl" yaml:"session_ttl"`
+
+	Tokens []TokenFile `toml:"token" yaml:"tokens"`
+	Proxy  ProxyFile   `toml:"proxy" yaml:"proxy"`
+}
+
+// TokenFile is a static bearer token for automation. Give either the token
+// itself or its hex SHA-256, so the file doesn't have to hold the secret.
+type TokenFile struct {
+	Name        string `toml:"name" yaml:"name"`
+	Token       string `toml:"token" yaml:"token"`
+	TokenSHA256 string `toml:"token_sha256" yaml:"token_sha256"`
+	Role        string `toml:"role" yaml:"role"`
+}
+
+// ProxyFile trusts an authenticating reverse proxy to name the user in a
+// header. Requests from anywhere else can't set it.
+type ProxyFile struct {
+	UserHeader string `toml:"user_header" yaml:"user_header"` // e.g. "X-Forwarded-User"
+	// Header holding the role; if unset or empty, DefaultRole is used
+	RoleHeader  string   `toml:"role_header" yaml:"role_header"`
+	DefaultRole string   `toml:"default_role" yaml:"default_role"` // default "viewer"
+	Trusted     []string `toml:"trusted" yaml:"trusted"`           // Proxy addresses or CIDRs
+}
+
+// roleNames are the roles the auth package knows, lowest first
+var roleNames = []string{"viewer", "operator", "admin"}
+
+// TLSFile turns on TLS for the TCP listener. The files are checked for
+// changes and reloaded, so rotated certificates are picked up without a
+// restart.
+type TLSFile struct {
+	Cert string `toml:"cert" yaml:"cert"` // PEM certificate chain
+	Key  string `toml:"key" yaml:"key"`   // PEM private key
+	// CA bundle for client certificates (mTLS). Clients must present a
+	// certificate it signed unless ClientOptional is set.
+	ClientCA       string `toml:"client_ca" yaml:"client_ca"`
+	ClientOptional bool   `toml:"client_optional" yaml:"client_optional"`
+	// Role of a client authenticated by its certificate (default "viewer")
+	ClientRole string `toml:"client_role" yaml:"client_role"`
+}
+
+// Enabled reports whether TLS is configured
+func (t TLSFile) Enabled() bool {
+	return t.Cert != "" || t.Key != ""
+}
+
+// SocketFile configures a unix socket listener for local use
+type SocketFile struct {
+	Path  string `toml:"path" yaml:"path"`
+	Mode  string `toml:"mode" yaml:"mode"`   // Octal file mode (default "0660")
+	Group string `toml:"group" yaml:"group"` // Group name or id owning the socket
+	// Role given to anyone connecting through the socket, so local tools
+	// don't need a token. Unset means the socket authenticates like TCP.
+	Role string `toml:"role" yaml:"role"`
+}
+
+// FileMode returns the socket's file mode
+func (s SocketFile) FileMode() (os.FileMode, error) {
+	if s.Mode == "" {
+		return 0o660, nil
+	}
+	n, err := strconv.ParseUint(s.Mode, 8, 32)
+	if err != nil || n > 0o777 {
+		return 0, fmt.Errorf("%q is not an octal file mode", s.Mode)
+	}
+	return os.FileMode(n), nil
+}
+
+// FilesystemFile declares a tracked filesystem and its maintenance schedule
+type FilesystemFile struct {
+	Path     string `toml:"path" yaml:"path"` // Mount point
+	Label    string `toml:"label" yaml:"label"`
+	BtrbkDir string `toml:"btrbk_dir" yaml:"btrbk_dir"`
+
+	// Start a scrub when the last one started longer ago than this (0 = never)
+	ScrubEvery    time.Duration `toml:"scrub_every" yaml:"scrub_every"`
+	ScrubReadonly bool          `toml:"scrub_readonly" yaml:"scrub_readonly"`
+	ScrubLimit    string        `toml:"scrub_limit" yaml:"scrub_limit"` // Bytes per second, e.g. "100MiB"
+
+	// Start a usage= balance of data chunks when the last completed one
+	// finished longer ago than this (0 = never)
+	BalanceEvery time.Duration `toml:"balance_every" yaml:"balance_every"`
+	BalanceUsage int32         `toml:"balance_usage" yaml:"balance_usage"` // usage= filter (default 10)
+}
+
+// minScheduleInterval keeps a typo like "30m" from scrubbing all day
+const minScheduleInterval = time.Hour
+
+// FindConfigFile returns the config file to use: GOBTR_CONFIG if set, else
+// the first of ConfigFileNames in dir. It returns "" if there is none.
+func FindConfigFile(dir string) string {
+	if path := os.Getenv("GOBTR_CONFIG"); path != "" {
+		return path
+	}
+	for _, name := range ConfigFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// DefaultConfigFile returns the config file New would use, or "" if there
+// is none
+func DefaultConfigFile() string {
+	return FindConfigFile(getConfigDir())
+}
+
+// LoadFile reads and validates a config file. The format follows the
+// extension: .yaml or .yml for YAML, anything else TOML. Unknown keys are
+// errors, so a misspelled key doesn't silently do nothing.
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(f); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+	default:
+		md, err := toml.Decode(string(data), f)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, k := range undecoded {
+				keys[i] = k.String()
+			}
+			return nil, fmt.Errorf("parse %s: unknown keys: %s", path, strings.Join(keys, ", "))
+		}
+	}
+
+	if err := f.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Redacted returns a copy of the file with its secrets replaced, for
+// showing to someone else
+func (f *File) Redacted() *File {
+	const redacted = "REDACTED"
+	r := *f
+	r.Auth.Tokens = slices.Clone(f.Auth.Tokens)
+	for i := range r.Auth.Tokens {
+		if r.Auth.Tokens[i].Token != "" {
+			r.Auth.Tokens[i].Token = redacted
+		}
+		if r.Auth.Tokens[i].TokenSHA256 != "" {
+			r.Auth.Tokens[i].TokenSHA256 = redacted
+		}
+	}
+	r.Hub.Agents = slices.Clone(f.Hub.Agents)
+	for i := range r.Hub.Agents {
+		if r.Hub.Agents[i].Token != "" {
+			r.Hub.Agents[i].Token = redacted
+		}
+	}
+	return &r
+}
+
+// Validate checks the file without touching any filesystem. It returns every
+// problem found, joined.
+func (f *File) Validate() error {
+	var errs []error
+
+	if f.CollectInterval != 0 && (f.CollectInterval < time.Minute || f.CollectInterval > 24*time.Hour) {
+		errs = append(errs, fmt.Errorf("collect_interval must be between 1m and 24h"))
+	}
+	if f.SampleTarget != 0 && f.SampleTarget < 1000 {
+		errs = append(errs, fmt.Errorf("sample_target must be at least 1000"))
+	}
+
+	a := f.Alerts
+	if a.ScrubMaxAgeDays < 0 {
+		errs = append(errs, fmt.Errorf("alerts.scrub_max_age_days must not be negative"))
+	}
+	if a.SlackPercent < 0 || a.SlackPercent > 100 {
+		errs = append(errs, fmt.Errorf("alerts.slack_percent must be between 0 and 100"))
+	}
+	if a.ImbalancePercent < 0 || a.ImbalancePercent > 100 {
+		errs = append(errs, fmt.Errorf("alerts.imbalance_percent must be between 0 and 100"))
+	}
+	if _, err := parseSize(a.MinSlack); err != nil {
+		errs = append(errs, fmt.Errorf("alerts.min_slack: %w", err))
+	}
+
+	errs = append(errs, f.Auth.validate()...)
+
+	if t := f.TLS; t.Enabled() || t.ClientCA != "" {
+		if t.Cert == "" || t.Key == "" {
+			errs = append(errs, fmt.Errorf("tls: cert and key are both required"))
+		}
+		if t.ClientRole != "" && !slices.Contains(roleNames, t.ClientRole) {
+			errs = append(errs, fmt.Errorf("tls.client_role must be one of %s", strings.Join(roleNames, ", ")))
+		}
+	}
+	if f.Listen == "none" && f.Socket.Path == "" {
+		errs = append(errs, fmt.Errorf("listen = \"none\" needs socket.path"))
+	}
+	if s := f.Socket; s.Path != "" || s.Mode != "" || s.Group != "" || s.Role != "" {
+		if !filepath.IsAbs(s.Path) {
+			errs = append(errs, fmt.Errorf("socket.path must be absolute"))
+		}
+		if _, err := s.FileMode(); err != nil {
+			errs = append(errs, fmt.Errorf("socket.mode: %w", err))
+		}
+		if s.Role != "" && !slices.Contains(roleNames, s.Role) {
+			errs = append(errs, fmt.Errorf("socket.role must be one of %s", strings.Join(roleNames, ", ")))
+		}
+	}
+
+	if f.Helper != "" && !filepath.IsAbs(f.Helper) {
+		errs = append(errs, fmt.Errorf("helper must be an absolute path"))
+	}
+
+	errs = append(errs, f.Hub.validate()...)
+
+	seen := make(map[string]bool)
+	for i, fs := range f.Filesystems {
+		name := fmt.Sprintf("filesystem %d", i+1)
+		if fs.Path != "" {
+			name = fmt.Sprintf("filesystem %q", fs.Path)
+		}
+		switch {
+		case fs.Path == "":
+			errs = append(errs, fmt.Errorf("%s: path is required", name))
+		case !filepath.IsAbs(fs.Path):
+			errs = append(errs, fmt.Errorf("%s: path must be absolute", name))
+		case seen[filepath.Clean(fs.Path)]:
+			errs = append(errs, fmt.Errorf("%s: declared more than once", name))
+		}
+		seen[filepath.Clean(fs.Path)] = true
+
+		if fs.BtrbkDir != "" && !filepath.IsAbs(fs.BtrbkDir) {
+			errs = append(errs, fmt.Errorf("%s: btrbk_dir must be absolute", name))
+		}
+		if fs.ScrubEvery < 0 || (fs.ScrubEvery > 0 && fs.ScrubEvery < minScheduleInterval) {
+			errs = append(errs, fmt.Errorf("%s: scrub_every must be at least %s", name, minScheduleInterval))
+		}
+		if _, err := parseSize(fs.ScrubLimit); err != nil {
+			errs = append(errs, fmt.Errorf("%s: scrub_limit: %w", name, err))
+		}
+		if fs.BalanceEvery < 0 || (fs.BalanceEvery > 0 && fs.BalanceEvery < minScheduleInterval) {
+			errs = append(errs, fmt.Errorf("%s: balance_every must be at least %s", name, minScheduleInterval))
+		}
+		if fs.BalanceUsage < 0 || fs.BalanceUsage > 100 {
+			errs = append(errs, fmt.Errorf("%s: balance_usage must be between 0 and 100", name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (h HubFile) validate() []error {
+	var errs []error
+
+	if h.PollInterval < 0 || (h.PollInterval > 0 && h.PollInterval < 5*time.Second) {
+		errs = append(errs, fmt.Errorf("hub.poll_interval must be at least 5s"))
+	}
+	if h.Timeout < 0 || (h.Timeout > 0 && h.Timeout < time.Second) {
+		errs = append(errs, fmt.Errorf("hub.timeout must be at least 1s"))
+	}
+
+	names := make(map[string]bool)
+	for i, a := range h.Agents {
+		name := fmt.Sprintf("hub agent %d", i+1)
+		if a.Name != "" {
+			name = fmt.Sprintf("hub agent %q", a.Name)
+		}
+		switch {
+		case a.Name == "":
+			errs = append(errs, fmt.Errorf("%s: name is required", name))
+		case names[a.Name]:
+			errs = append(errs, fmt.Errorf("%s: declared more than once", name))
+		}
+		names[a.Name] = true
+
+		if u, err := url.Parse(a.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("%s: url must be an http:// or https:// address", name))
+		}
+		if (a.Token == "") == (a.TokenFile == "") {
+			errs = append(errs, fmt.Errorf("%s: set exactly one of token and token_file", name))
+		}
+		if a.TokenFile != "" && !filepath.IsAbs(a.TokenFile) {
+			errs = append(errs, fmt.Errorf("%s: token_file must be absolute", name))
+		}
+		if a.CA != "" && !filepath.IsAbs(a.CA) {
+			errs = append(errs, fmt.Errorf("%s: ca must be absolute", name))
+		}
+	}
+	return errs
+}
+
+func (a AuthFile) validate() []error {
+	var errs []error
+
+	if a.SessionTTL < 0 || (a.SessionTTL > 0 && a.SessionTTL < time.Minute) {
+		errs = append(errs, fmt.Errorf("auth.session_ttl must be at least 1m"))
+	}
+
+	names := make(map[string]bool)
+	for i, t := range a.Tokens {
+		name := fmt.Sprintf("auth token %d", i+1)
+		if t.Name != "" {
+			name = fmt.Sprintf("auth token %q", t.Name)
+		}
+		switch {
+		case t.Name == "":
+			errs = append(errs, fmt.Errorf("%s: name is required", name))
+		case names[t.Name]:
+			errs = append(errs, fmt.Errorf("%s: declared more than once", name))
+		}
+		names[t.Name] = true
+
+		switch {
+		case (t.Token == "") == (t.TokenSHA256 == ""):
+			errs = append(errs, fmt.Errorf("%s: set exactly one of token and token_sha256", name))
+		case t.Token != "" && len(t.Token) < 16:
+			errs = append(errs, fmt.Errorf("%s: token must be at least 16 characters", name))
+		case t.TokenSHA256 != "":
+			if b, err := hex.DecodeString(t.TokenSHA256); err != nil || len(b) != sha256.Size {
+				errs = append(errs, fmt.Errorf("%s: token_sha256 must be 64 hex characters", name))
+			}
+		}
+		if !slices.Contains(roleNames, t.Role) {
+			errs = append(errs, fmt.Errorf("%s: role must be one of %s", name, strings.Join(roleNames, ", ")))
+		}
+	}
+
+	p := a.Proxy
+	if p.UserHeader != "" && len(p.Trusted) == 0 {
+		errs = append(errs, fmt.Errorf("auth.proxy.trusted is required with user_header"))
+	}
+	if p.DefaultRole != "" && !slices.Contains(roleNames, p.DefaultRole) {
+		errs = append(errs, fmt.Errorf("auth.proxy.default_role must be one of %s", strings.Join(roleNames, ", ")))
+	}
+	if _, err := p.TrustedPrefixes(); err != nil {
+		errs = append(errs, fmt.Errorf("auth.proxy.trusted: %w", err))
+	}
+
+	return errs
+}
+
+// SessionTTLOrDefault returns how long a login lasts
+func (a AuthFile) SessionTTLOrDefault() time.Duration {
+	if a.SessionTTL == 0 {
+		return 7 * 24 * time.Hour
+	}
+	return a.SessionTTL
+}
+
+// TrustedPrefixes parses the trusted proxy addresses. A bare address is
+// trusted on its own.
+func (p ProxyFile) TrustedPrefixes() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(p.Trusted))
+	for _, s := range p.Trusted {
+		if prefix, err := netip.ParsePrefix(s); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an address or CIDR", s)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// MinSlackBytes returns alerts.min_slack in bytes, or 0 if unset
+func (a AlertsFile) MinSlackBytes() int64 {
+	n, _ := parseSize(a.MinSlack)
+	return int64(n)
+}
+
+// ScrubLimitBytes returns scrub_limit in bytes per second, or 0 if unset
+func (fs FilesystemFile) ScrubLimitBytes() int64 {
+	n, _ := parseSize(fs.ScrubLimit)
+	return int64(n)
+}
+
+// BalanceUsagePercent returns the usage= filter for scheduled balances
+func (fs FilesystemFile) BalanceUsagePercent() int32 {
+	if fs.BalanceUsage == 0 {
+		return 10
+	}
+	return fs.BalanceUsage
+}
+
+func parseSize(s string) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return humanize.ParseBytes(s)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"log/slog"
+	"net/url"
 	"os"
 	"path/filepath"
 
@@ -58,6 +59,20 @@ func New(lc fx.Lifecycle, cfg *config.Config, logger *slog.Logger) (*DB, error)
 	return db, nil
 }
 
+// OpenReadOnly opens the database at path without migrating it, for
+// processes that only read what the server wrote
+func OpenReadOnly(logger *slog.Logger, path string) (*DB, error) {
+	uri := &url.URL{Scheme: "file", Path: path, RawQuery: "mode=ro"}
+	conn, err := sql.Open("sqlite3", uri.String())
+	if err != nil {
+		return nil, err
+	}
+	return &DB{
+		conn:   conn,
+		logger: logger.With("component", "db"),
+	}, nil
+}
+
 func (db *DB) init() error {
 	db.logger.Debug("initializing database with migrations")
 
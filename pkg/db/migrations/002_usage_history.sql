@@ -0,0 +1,27 @@
+-- +goose Up
+-- Periodic per-filesystem usage samples, used for growth forecasting
+
+CREATE TABLE IF NOT EXISTS usage_history (
+    fs_uuid TEXT NOT NULL,
+    timestamp INTEGER NOT NULL,
+    device_size INTEGER NOT NULL,
+    device_allocated INTEGER NOT NULL,
+    device_unallocated INTEGER NOT NULL,
+    data_total INTEGER DEFAULT 0,
+    data_used INTEGER DEFAULT 0,
+    data_profile TEXT,
+    metadata_total INTEGER DEFAULT 0,
+    metadata_used INTEGER DEFAULT 0,
+    metadata_profile TEXT,
+    system_total INTEGER DEFAULT 0,
+    system_used INTEGER DEFAULT 0,
+    global_reserve INTEGER DEFAULT 0,
+    global_reserve_used INTEGER DEFAULT 0,
+    free_estimated INTEGER DEFAULT 0,
+    PRIMARY KEY (fs_uuid, timestamp)
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_history_timestamp ON usage_history(timestamp);
+
+-- +goose Down
+DROP TABLE IF EXISTS usage_history;
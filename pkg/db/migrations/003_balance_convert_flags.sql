@@ -0,0 +1,13 @@
+-- +goose Up
+-- Profile conversion filters used when starting a balance
+
+ALTER TABLE balance_history ADD COLUMN flag_data_convert TEXT;
+ALTER TABLE balance_history ADD COLUMN flag_metadata_convert TEXT;
+ALTER TABLE balance_history ADD COLUMN flag_system_convert TEXT;
+ALTER TABLE balance_history ADD COLUMN flag_soft INTEGER DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE balance_history DROP COLUMN flag_soft;
+ALTER TABLE balance_history DROP COLUMN flag_system_convert;
+ALTER TABLE balance_history DROP COLUMN flag_metadata_convert;
+ALTER TABLE balance_history DROP COLUMN flag_data_convert;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Device and physical range filters used when starting a balance
+
+ALTER TABLE balance_history ADD COLUMN flag_devid INTEGER DEFAULT 0;
+ALTER TABLE balance_history ADD COLUMN flag_drange_start INTEGER DEFAULT 0;
+ALTER TABLE balance_history ADD COLUMN flag_drange_end INTEGER DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE balance_history DROP COLUMN flag_drange_end;
+ALTER TABLE balance_history DROP COLUMN flag_drange_start;
+ALTER TABLE balance_history DROP COLUMN flag_devid;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Fragmap scans, saved when the chunk layout changes so scans can be diffed
+
+CREATE TABLE IF NOT EXISTS fragmap_scans (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    fs_uuid TEXT NOT NULL,
+    generation INTEGER NOT NULL,
+    scanned_at INTEGER NOT NULL,
+    total_size INTEGER NOT NULL,
+    chunk_count INTEGER NOT NULL,
+    layout_hash TEXT NOT NULL,
+    data BLOB NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_fragmap_scans_fs ON fragmap_scans(fs_uuid, scanned_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS fragmap_scans;
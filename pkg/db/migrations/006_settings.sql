@@ -0,0 +1,12 @@
+-- +goose Up
+-- Server settings changed at runtime. Only settings that differ from the
+-- env/config defaults are stored.
+
+CREATE TABLE IF NOT EXISTS settings (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS settings;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Every mutating RPC, who made it and how it turned out. Rows are never
+-- updated or deleted by the server.
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    timestamp INTEGER NOT NULL,
+    actor TEXT NOT NULL,
+    auth_method TEXT NOT NULL,
+    role TEXT NOT NULL,
+    peer TEXT NOT NULL,
+    procedure TEXT NOT NULL,
+    filesystem TEXT NOT NULL,
+    request TEXT NOT NULL,        -- Request as JSON, secrets redacted
+    outcome TEXT NOT NULL,        -- "ok" or the Connect error code
+    error TEXT NOT NULL,
+    duration_ms INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_timestamp ON audit_log(timestamp);
+CREATE INDEX IF NOT EXISTS idx_audit_log_filesystem ON audit_log(filesystem, timestamp);
+
+-- +goose Down
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- When a hub last reached each of its agents, so an agent that went away
+-- before a restart still shows how long it has been gone. The agents
+-- themselves are declared in the config file.
+
+CREATE TABLE IF NOT EXISTS agents (
+    name TEXT PRIMARY KEY,
+    last_seen INTEGER NOT NULL,     -- 0 = never
+    last_checked INTEGER NOT NULL,
+    last_error TEXT NOT NULL        -- Empty if the last check worked
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS agents;
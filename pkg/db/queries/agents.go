@@ -0,0 +1,53 @@
+package queries
+
+import (
+	"database/sql"
+	"time"
+)
+
+// AgentState is what a hub last saw of an agent
+type AgentState struct {
+	Name        string
+	LastSeen    time.Time // Zero if never reached
+	LastChecked time.Time
+	LastError   string
+}
+
+// ListAgentStates returns the stored state of every agent by name
+func ListAgentStates(db *sql.DB) (map[string]*AgentState, error) {
+	rows, err := db.Query(`SELECT name, last_seen, last_checked, last_error FROM agents`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := make(map[string]*AgentState)
+	for rows.Next() {
+		var s AgentState
+		var lastSeen, lastChecked int64
+		if err := rows.Scan(&s.Name, &lastSeen, &lastChecked, &s.LastError); err != nil {
+			return nil, err
+		}
+		if lastSeen > 0 {
+			s.LastSeen = time.Unix(lastSeen, 0)
+		}
+		s.LastChecked = time.Unix(lastChecked, 0)
+		states[s.Name] = &s
+	}
+	return states, rows.Err()
+}
+
+func UpsertAgentState(db *sql.DB, s *AgentState) error {
+	var lastSeen int64
+	if !s.LastSeen.IsZero() {
+		lastSeen = s.LastSeen.Unix()
+	}
+	_, err := db.Exec(`
+		INSERT INTO agents (name, last_seen, last_checked, last_error) VALUES (?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			last_seen = excluded.last_seen,
+			last_checked = excluded.last_checked,
+			last_error = excluded.last_error
+	`, s.Name, lastSeen, s.LastChecked.Unix(), s.LastError)
+	return err
+}
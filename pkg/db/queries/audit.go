@@ -0,0 +1,125 @@
+package queries
+
+import (
+	"database/sql"
+	"time"
+)
+
+// AuditEvent is one recorded RPC
+type AuditEvent struct {
+	ID         int64
+	Timestamp  time.Time
+	Actor      string
+	AuthMethod string
+	Role       string
+	Peer       string
+	Procedure  string
+	Filesystem string
+	Request    string
+	Outcome    string
+	Error      string
+	Duration   time.Duration
+}
+
+func InsertAuditEvent(db *sql.DB, e *AuditEvent) error {
+	result, err := db.Exec(`
+		INSERT INTO audit_log (
+			timestamp, actor, auth_method, role, peer, procedure, filesystem,
+			request, outcome, error, duration_ms
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, e.Timestamp.Unix(), e.Actor, e.AuthMethod, e.Role, e.Peer, e.Procedure, e.Filesystem,
+		e.Request, e.Outcome, e.Error, e.Duration.Milliseconds())
+	if err != nil {
+		return err
+	}
+	e.ID, err = result.LastInsertId()
+	return err
+}
+
+// AuditFilter selects audit events. Zero fields match everything.
+type AuditFilter struct {
+	// Mount path; events for paths under it (snapshots, defrag targets)
+	// match too
+	Filesystem string
+	Actor      string
+	Procedure  string
+	Since      time.Time // Inclusive
+	Until      time.Time // Exclusive
+	// Only events with an ID below this, for paging back from the newest
+	BeforeID int64
+	Limit    int
+}
+
+// ListAuditEvents returns matching events, newest first
+func ListAuditEvents(db *sql.DB, f AuditFilter) ([]*AuditEvent, error) {
+	query := `
+		SELECT id, timestamp, actor, auth_method, role, peer, procedure, filesystem,
+			request, outcome, error, duration_ms
+		FROM audit_log
+		WHERE 1=1
+	`
+	args := []interface{}{}
+
+	if f.Filesystem != "" {
+		query += " AND (filesystem = ? OR filesystem LIKE ? ESCAPE '\\')"
+		args = append(args, f.Filesystem, escapeLike(f.Filesystem)+"/%")
+	}
+	if f.Actor != "" {
+		query += " AND actor = ?"
+		args = append(args, f.Actor)
+	}
+	if f.Procedure != "" {
+		query += " AND procedure = ?"
+		args = append(args, f.Procedure)
+	}
+	if !f.Since.IsZero() {
+		query += " AND timestamp >= ?"
+		args = append(args, f.Since.Unix())
+	}
+	if !f.Until.IsZero() {
+		query += " AND timestamp < ?"
+		args = append(args, f.Until.Unix())
+	}
+	if f.BeforeID > 0 {
+		query += " AND id < ?"
+		args = append(args, f.BeforeID)
+	}
+	query += " ORDER BY id DESC"
+	if f.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, f.Limit)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*AuditEvent
+	for rows.Next() {
+		var e AuditEvent
+		var ts, durationMs int64
+		if err := rows.Scan(&e.ID, &ts, &e.Actor, &e.AuthMethod, &e.Role, &e.Peer, &e.Procedure,
+			&e.Filesystem, &e.Request, &e.Outcome, &e.Error, &durationMs); err != nil {
+			return nil, err
+		}
+		e.Timestamp = time.Unix(ts, 0)
+		e.Duration = time.Duration(durationMs) * time.Millisecond
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+// escapeLike escapes the LIKE wildcards in s, for use with ESCAPE '\'
+func escapeLike(s string) string {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '%', '_', '\\':
+			out = append(out, '\\')
+		}
+		out = append(out, s[i])
+	}
+	return string(out)
+}
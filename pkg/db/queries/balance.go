@@ -25,6 +25,14 @@ type BalanceHistory struct {
 	FlagBackground   bool
 	FlagDryRun       bool
 	FlagForce        bool
+	// Conversion targets, empty if the balance didn't convert that type
+	FlagDataConvert     string
+	FlagMetadataConvert string
+	FlagSystemConvert   string
+	FlagSoft            bool
+	FlagDevid           int64
+	FlagDrangeStart     int64
+	FlagDrangeEnd       int64
 }
 
 func InsertBalance(db *sql.DB, b *BalanceHistory) error {
@@ -38,12 +46,16 @@ func InsertBalance(db *sql.DB, b *BalanceHistory) error {
 			balance_id, device_path, started_at, finished_at, status,
 			chunks_considered, chunks_relocated, size_relocated, soft_errors,
 			flag_data, flag_metadata, flag_system, flag_usage_percent,
-			flag_limit_chunks, flag_limit_percent, flag_background, flag_dry_run, flag_force
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			flag_limit_chunks, flag_limit_percent, flag_background, flag_dry_run, flag_force,
+			flag_data_convert, flag_metadata_convert, flag_system_convert, flag_soft,
+			flag_devid, flag_drange_start, flag_drange_end
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, b.BalanceID, b.DevicePath, b.StartedAt.Unix(), finishedAt, b.Status,
 		b.ChunksConsidered, b.ChunksRelocated, b.SizeRelocated, b.SoftErrors,
 		b.FlagData, b.FlagMetadata, b.FlagSystem, b.FlagUsagePercent,
-		b.FlagLimitChunks, b.FlagLimitPercent, b.FlagBackground, b.FlagDryRun, b.FlagForce)
+		b.FlagLimitChunks, b.FlagLimitPercent, b.FlagBackground, b.FlagDryRun, b.FlagForce,
+		b.FlagDataConvert, b.FlagMetadataConvert, b.FlagSystemConvert, b.FlagSoft,
+		b.FlagDevid, b.FlagDrangeStart, b.FlagDrangeEnd)
 	return err
 }
 
@@ -73,7 +85,10 @@ func GetBalance(db *sql.DB, balanceID string) (*BalanceHistory, error) {
 		       COALESCE(flag_data, 0), COALESCE(flag_metadata, 0), COALESCE(flag_system, 0),
 		       COALESCE(flag_usage_percent, 0), COALESCE(flag_limit_chunks, 0),
 		       COALESCE(flag_limit_percent, 0), COALESCE(flag_background, 0),
-		       COALESCE(flag_dry_run, 0), COALESCE(flag_force, 0)
+		       COALESCE(flag_dry_run, 0), COALESCE(flag_force, 0),
+		       COALESCE(flag_data_convert, ''), COALESCE(flag_metadata_convert, ''),
+		       COALESCE(flag_system_convert, ''), COALESCE(flag_soft, 0),
+		       COALESCE(flag_devid, 0), COALESCE(flag_drange_start, 0), COALESCE(flag_drange_end, 0)
 		FROM balance_history
 		WHERE balance_id = ?
 	`, balanceID).Scan(
@@ -81,6 +96,8 @@ func GetBalance(db *sql.DB, balanceID string) (*BalanceHistory, error) {
 		&b.ChunksConsidered, &b.ChunksRelocated, &b.SizeRelocated, &b.SoftErrors,
 		&b.FlagData, &b.FlagMetadata, &b.FlagSystem, &b.FlagUsagePercent,
 		&b.FlagLimitChunks, &b.FlagLimitPercent, &b.FlagBackground, &b.FlagDryRun, &b.FlagForce,
+		&b.FlagDataConvert, &b.FlagMetadataConvert, &b.FlagSystemConvert, &b.FlagSoft,
+		&b.FlagDevid, &b.FlagDrangeStart, &b.FlagDrangeEnd,
 	)
 	if err != nil {
 		return nil, err
@@ -103,7 +120,10 @@ func ListBalanceHistory(db *sql.DB, devicePath string, limit int) ([]*BalanceHis
 		       COALESCE(flag_data, 0), COALESCE(flag_metadata, 0), COALESCE(flag_system, 0),
 		       COALESCE(flag_usage_percent, 0), COALESCE(flag_limit_chunks, 0),
 		       COALESCE(flag_limit_percent, 0), COALESCE(flag_background, 0),
-		       COALESCE(flag_dry_run, 0), COALESCE(flag_force, 0)
+		       COALESCE(flag_dry_run, 0), COALESCE(flag_force, 0),
+		       COALESCE(flag_data_convert, ''), COALESCE(flag_metadata_convert, ''),
+		       COALESCE(flag_system_convert, ''), COALESCE(flag_soft, 0),
+		       COALESCE(flag_devid, 0), COALESCE(flag_drange_start, 0), COALESCE(flag_drange_end, 0)
 		FROM balance_history
 		WHERE 1=1
 	`
@@ -137,6 +157,8 @@ func ListBalanceHistory(db *sql.DB, devicePath string, limit int) ([]*BalanceHis
 			&b.ChunksConsidered, &b.ChunksRelocated, &b.SizeRelocated, &b.SoftErrors,
 			&b.FlagData, &b.FlagMetadata, &b.FlagSystem, &b.FlagUsagePercent,
 			&b.FlagLimitChunks, &b.FlagLimitPercent, &b.FlagBackground, &b.FlagDryRun, &b.FlagForce,
+			&b.FlagDataConvert, &b.FlagMetadataConvert, &b.FlagSystemConvert, &b.FlagSoft,
+			&b.FlagDevid, &b.FlagDrangeStart, &b.FlagDrangeEnd,
 		)
 		if err != nil {
 			return nil, err
@@ -165,7 +187,10 @@ func GetActiveBalance(db *sql.DB, devicePath string) (*BalanceHistory, error) {
 		       COALESCE(flag_data, 0), COALESCE(flag_metadata, 0), COALESCE(flag_system, 0),
 		       COALESCE(flag_usage_percent, 0), COALESCE(flag_limit_chunks, 0),
 		       COALESCE(flag_limit_percent, 0), COALESCE(flag_background, 0),
-		       COALESCE(flag_dry_run, 0), COALESCE(flag_force, 0)
+		       COALESCE(flag_dry_run, 0), COALESCE(flag_force, 0),
+		       COALESCE(flag_data_convert, ''), COALESCE(flag_metadata_convert, ''),
+		       COALESCE(flag_system_convert, ''), COALESCE(flag_soft, 0),
+		       COALESCE(flag_devid, 0), COALESCE(flag_drange_start, 0), COALESCE(flag_drange_end, 0)
 		FROM balance_history
 		WHERE device_path = ? AND status IN ('running', 'starting', 'paused')
 		ORDER BY started_at DESC
@@ -175,6 +200,8 @@ func GetActiveBalance(db *sql.DB, devicePath string) (*BalanceHistory, error) {
 		&b.ChunksConsidered, &b.ChunksRelocated, &b.SizeRelocated, &b.SoftErrors,
 		&b.FlagData, &b.FlagMetadata, &b.FlagSystem, &b.FlagUsagePercent,
 		&b.FlagLimitChunks, &b.FlagLimitPercent, &b.FlagBackground, &b.FlagDryRun, &b.FlagForce,
+		&b.FlagDataConvert, &b.FlagMetadataConvert, &b.FlagSystemConvert, &b.FlagSoft,
+		&b.FlagDevid, &b.FlagDrangeStart, &b.FlagDrangeEnd,
 	)
 	if err != nil {
 		return nil, err
@@ -202,8 +229,10 @@ func UpsertBalance(db *sql.DB, b *BalanceHistory) error {
 			balance_id, device_path, started_at, finished_at, status,
 			chunks_considered, chunks_relocated, size_relocated, soft_errors,
 			flag_data, flag_metadata, flag_system, flag_usage_percent,
-			flag_limit_chunks, flag_limit_percent, flag_background, flag_dry_run, flag_force
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			flag_limit_chunks, flag_limit_percent, flag_background, flag_dry_run, flag_force,
+			flag_data_convert, flag_metadata_convert, flag_system_convert, flag_soft,
+			flag_devid, flag_drange_start, flag_drange_end
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(balance_id) DO UPDATE SET
 			finished_at = excluded.finished_at,
 			status = excluded.status,
@@ -214,6 +243,24 @@ func UpsertBalance(db *sql.DB, b *BalanceHistory) error {
 	`, b.BalanceID, b.DevicePath, b.StartedAt.Unix(), finishedAt, b.Status,
 		b.ChunksConsidered, b.ChunksRelocated, b.SizeRelocated, b.SoftErrors,
 		b.FlagData, b.FlagMetadata, b.FlagSystem, b.FlagUsagePercent,
-		b.FlagLimitChunks, b.FlagLimitPercent, b.FlagBackground, b.FlagDryRun, b.FlagForce)
+		b.FlagLimitChunks, b.FlagLimitPercent, b.FlagBackground, b.FlagDryRun, b.FlagForce,
+		b.FlagDataConvert, b.FlagMetadataConvert, b.FlagSystemConvert, b.FlagSoft,
+		b.FlagDevid, b.FlagDrangeStart, b.FlagDrangeEnd)
 	return err
 }
+
+// GetLastBalanceFinish returns when the most recent balance with the given
+// status finished on a device. It returns sql.ErrNoRows if there is none.
+func GetLastBalanceFinish(db *sql.DB, devicePath string, status string) (time.Time, error) {
+	var finishedAt int64
+	err := db.QueryRow(`
+		SELECT finished_at FROM balance_history
+		WHERE device_path = ? AND status = ? AND finished_at IS NOT NULL
+		ORDER BY finished_at DESC
+		LIMIT 1
+	`, devicePath, status).Scan(&finishedAt)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(finishedAt, 0), nil
+}
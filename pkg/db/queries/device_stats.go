@@ -0,0 +1,40 @@
+package queries
+
+import (
+	"database/sql"
+	"time"
+)
+
+// DeviceStatsSnapshot is one row of the device_stats table
+type DeviceStatsSnapshot struct {
+	DevicePath       string
+	Timestamp        time.Time
+	TotalBytes       int64
+	UsedBytes        int64
+	FreeBytes        int64
+	WriteErrors      int64
+	ReadErrors       int64
+	FlushErrors      int64
+	CorruptionErrors int64
+	GenerationErrors int64
+}
+
+func InsertDeviceStats(db *sql.DB, s *DeviceStatsSnapshot) error {
+	_, err := db.Exec(`
+		INSERT OR REPLACE INTO device_stats (
+			device_path, timestamp, total_bytes, used_bytes, free_bytes,
+			write_errors, read_errors, flush_errors, corruption_errors, generation_errors
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, s.DevicePath, s.Timestamp.Unix(), s.TotalBytes, s.UsedBytes, s.FreeBytes,
+		s.WriteErrors, s.ReadErrors, s.FlushErrors, s.CorruptionErrors, s.GenerationErrors)
+	return err
+}
+
+// DeleteDeviceStatsBefore prunes snapshots older than the given time
+func DeleteDeviceStatsBefore(db *sql.DB, before time.Time) (int64, error) {
+	result, err := db.Exec("DELETE FROM device_stats WHERE timestamp < ?", before.Unix())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
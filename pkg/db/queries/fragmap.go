@@ -0,0 +1,96 @@
+package queries
+
+import (
+	"database/sql"
+	"time"
+)
+
+// FragMapScan is a saved fragmap scan. Data holds the encoded scan and is
+// only loaded by GetFragMapScan.
+type FragMapScan struct {
+	ID         int64
+	FsUUID     string
+	Generation int64
+	ScannedAt  time.Time
+	TotalSize  int64
+	ChunkCount int64
+	LayoutHash string
+	Data       []byte
+}
+
+func InsertFragMapScan(db *sql.DB, s *FragMapScan) (int64, error) {
+	result, err := db.Exec(`
+		INSERT INTO fragmap_scans (
+			fs_uuid, generation, scanned_at, total_size, chunk_count, layout_hash, data
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, s.FsUUID, s.Generation, s.ScannedAt.Unix(), s.TotalSize, s.ChunkCount, s.LayoutHash, s.Data)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// ListFragMapScans returns saved scans of a filesystem without their data,
+// newest first
+func ListFragMapScans(db *sql.DB, fsUUID string, limit int) ([]*FragMapScan, error) {
+	query := `
+		SELECT id, fs_uuid, generation, scanned_at, total_size, chunk_count, layout_hash
+		FROM fragmap_scans
+		WHERE fs_uuid = ?
+		ORDER BY scanned_at DESC, id DESC
+	`
+	args := []interface{}{fsUUID}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scans []*FragMapScan
+	for rows.Next() {
+		var s FragMapScan
+		var scannedAt int64
+		if err := rows.Scan(&s.ID, &s.FsUUID, &s.Generation, &scannedAt, &s.TotalSize, &s.ChunkCount, &s.LayoutHash); err != nil {
+			return nil, err
+		}
+		s.ScannedAt = time.Unix(scannedAt, 0)
+		scans = append(scans, &s)
+	}
+	return scans, rows.Err()
+}
+
+// GetFragMapScan returns a saved scan with its data. It returns
+// sql.ErrNoRows if there is none.
+func GetFragMapScan(db *sql.DB, id int64) (*FragMapScan, error) {
+	var s FragMapScan
+	var scannedAt int64
+	err := db.QueryRow(`
+		SELECT id, fs_uuid, generation, scanned_at, total_size, chunk_count, layout_hash, data
+		FROM fragmap_scans
+		WHERE id = ?
+	`, id).Scan(&s.ID, &s.FsUUID, &s.Generation, &scannedAt, &s.TotalSize, &s.ChunkCount, &s.LayoutHash, &s.Data)
+	if err != nil {
+		return nil, err
+	}
+	s.ScannedAt = time.Unix(scannedAt, 0)
+	return &s, nil
+}
+
+// PruneFragMapScans keeps the newest keep scans of a filesystem
+func PruneFragMapScans(db *sql.DB, fsUUID string, keep int) (int64, error) {
+	result, err := db.Exec(`
+		DELETE FROM fragmap_scans
+		WHERE fs_uuid = ? AND id NOT IN (
+			SELECT id FROM fragmap_scans WHERE fs_uuid = ? ORDER BY scanned_at DESC, id DESC LIMIT ?
+		)
+	`, fsUUID, fsUUID, keep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
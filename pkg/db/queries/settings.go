@@ -0,0 +1,52 @@
+package queries
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ListSettings returns every stored setting by key
+func ListSettings(db *sql.DB) (map[string]string, error) {
+	rows, err := db.Query(`SELECT key, value FROM settings`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settings := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		settings[key] = value
+	}
+	return settings, rows.Err()
+}
+
+// SetSettings stores and deletes settings in one transaction. Keys in del
+// are removed so they fall back to their defaults.
+func SetSettings(db *sql.DB, set map[string]string, del []string, at time.Time) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for key, value := range set {
+		_, err := tx.Exec(`
+			INSERT INTO settings (key, value, updated_at) VALUES (?, ?, ?)
+			ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+		`, key, value, at.Unix())
+		if err != nil {
+			return err
+		}
+	}
+	for _, key := range del {
+		if _, err := tx.Exec(`DELETE FROM settings WHERE key = ?`, key); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,104 @@
+package queries
+
+import (
+	"database/sql"
+	"time"
+)
+
+// UsageSample is one point-in-time allocation snapshot of a filesystem
+type UsageSample struct {
+	FsUUID            string
+	Timestamp         time.Time
+	DeviceSize        int64
+	DeviceAllocated   int64
+	DeviceUnallocated int64
+	DataTotal         int64
+	DataUsed          int64
+	DataProfile       string
+	MetadataTotal     int64
+	MetadataUsed      int64
+	MetadataProfile   string
+	SystemTotal       int64
+	SystemUsed        int64
+	GlobalReserve     int64
+	GlobalReserveUsed int64
+	FreeEstimated     int64
+}
+
+func InsertUsageSample(db *sql.DB, s *UsageSample) error {
+	_, err := db.Exec(`
+		INSERT OR REPLACE INTO usage_history (
+			fs_uuid, timestamp, device_size, device_allocated, device_unallocated,
+			data_total, data_used, data_profile, metadata_total, metadata_used, metadata_profile,
+			system_total, system_used, global_reserve, global_reserve_used, free_estimated
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, s.FsUUID, s.Timestamp.Unix(), s.DeviceSize, s.DeviceAllocated, s.DeviceUnallocated,
+		s.DataTotal, s.DataUsed, s.DataProfile, s.MetadataTotal, s.MetadataUsed, s.MetadataProfile,
+		s.SystemTotal, s.SystemUsed, s.GlobalReserve, s.GlobalReserveUsed, s.FreeEstimated)
+	return err
+}
+
+// ListUsageHistory returns samples for a filesystem in ascending time order
+func ListUsageHistory(db *sql.DB, fsUUID string, since time.Time, limit int) ([]*UsageSample, error) {
+	query := `
+		SELECT fs_uuid, timestamp, device_size, device_allocated, device_unallocated,
+		       data_total, data_used, COALESCE(data_profile, ''),
+		       metadata_total, metadata_used, COALESCE(metadata_profile, ''),
+		       system_total, system_used, global_reserve, global_reserve_used, free_estimated
+		FROM usage_history
+		WHERE fs_uuid = ?
+	`
+	args := []interface{}{fsUUID}
+
+	if !since.IsZero() {
+		query += " AND timestamp >= ?"
+		args = append(args, since.Unix())
+	}
+
+	// Take the newest rows when limited, then return them oldest first
+	query += " ORDER BY timestamp DESC"
+
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var samples []*UsageSample
+	for rows.Next() {
+		var s UsageSample
+		var timestamp int64
+		err := rows.Scan(&s.FsUUID, &timestamp, &s.DeviceSize, &s.DeviceAllocated, &s.DeviceUnallocated,
+			&s.DataTotal, &s.DataUsed, &s.DataProfile,
+			&s.MetadataTotal, &s.MetadataUsed, &s.MetadataProfile,
+			&s.SystemTotal, &s.SystemUsed, &s.GlobalReserve, &s.GlobalReserveUsed, &s.FreeEstimated)
+		if err != nil {
+			return nil, err
+		}
+		s.Timestamp = time.Unix(timestamp, 0)
+		samples = append(samples, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(samples)-1; i < j; i, j = i+1, j-1 {
+		samples[i], samples[j] = samples[j], samples[i]
+	}
+
+	return samples, nil
+}
+
+// DeleteUsageHistoryBefore prunes samples older than the given time
+func DeleteUsageHistoryBefore(db *sql.DB, before time.Time) (int64, error) {
+	result, err := db.Exec("DELETE FROM usage_history WHERE timestamp < ?", before.Unix())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,165 @@
+package queries
+
+import (
+	"database/sql"
+	"time"
+)
+
+// User is a local web UI user
+type User struct {
+	ID           int64
+	Username     string
+	PasswordHash string
+	Role         string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func InsertUser(db *sql.DB, u *User) error {
+	result, err := db.Exec(`
+		INSERT INTO users (username, password_hash, role, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, u.Username, u.PasswordHash, u.Role, u.CreatedAt.Unix(), u.UpdatedAt.Unix())
+	if err != nil {
+		return err
+	}
+	u.ID, err = result.LastInsertId()
+	return err
+}
+
+// GetUser returns sql.ErrNoRows if there is no such user
+func GetUser(db *sql.DB, username string) (*User, error) {
+	row := db.QueryRow(`
+		SELECT id, username, password_hash, role, created_at, updated_at
+		FROM users WHERE username = ?
+	`, username)
+	return scanUser(row)
+}
+
+func ListUsers(db *sql.DB) ([]*User, error) {
+	rows, err := db.Query(`
+		SELECT id, username, password_hash, role, created_at, updated_at
+		FROM users ORDER BY username
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+func UpdateUserRole(db *sql.DB, username, role string, at time.Time) error {
+	return execOne(db, `UPDATE users SET role = ?, updated_at = ? WHERE username = ?`, role, at.Unix(), username)
+}
+
+// UpdateUserPassword sets the password and ends the user's sessions
+func UpdateUserPassword(db *sql.DB, username, passwordHash string, at time.Time) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE users SET password_hash = ?, updated_at = ? WHERE username = ?`,
+		passwordHash, at.Unix(), username)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err := tx.Exec(`
+		DELETE FROM sessions WHERE user_id = (SELECT id FROM users WHERE username = ?)
+	`, username); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteUser removes a user; their sessions go with them
+func DeleteUser(db *sql.DB, username string) error {
+	return execOne(db, `DELETE FROM users WHERE username = ?`, username)
+}
+
+// CountUsersWithRole counts users that have the given role
+func CountUsersWithRole(db *sql.DB, role string) (int, error) {
+	var n int
+	err := db.QueryRow(`SELECT COUNT(*) FROM users WHERE role = ?`, role).Scan(&n)
+	return n, err
+}
+
+func InsertSession(db *sql.DB, tokenHash string, userID int64, createdAt, expiresAt time.Time) error {
+	_, err := db.Exec(`
+		INSERT INTO sessions (token_hash, user_id, created_at, expires_at)
+		VALUES (?, ?, ?, ?)
+	`, tokenHash, userID, createdAt.Unix(), expiresAt.Unix())
+	return err
+}
+
+// GetSessionUser returns the user a session belongs to, or sql.ErrNoRows if
+// the session doesn't exist or has expired
+func GetSessionUser(db *sql.DB, tokenHash string, now time.Time) (*User, error) {
+	row := db.QueryRow(`
+		SELECT u.id, u.username, u.password_hash, u.role, u.created_at, u.updated_at
+		FROM sessions s JOIN users u ON u.id = s.user_id
+		WHERE s.token_hash = ? AND s.expires_at > ?
+	`, tokenHash, now.Unix())
+	return scanUser(row)
+}
+
+func DeleteSession(db *sql.DB, tokenHash string) error {
+	_, err := db.Exec(`DELETE FROM sessions WHERE token_hash = ?`, tokenHash)
+	return err
+}
+
+// DeleteExpiredSessions removes sessions that expired before now
+func DeleteExpiredSessions(db *sql.DB, now time.Time) (int64, error) {
+	result, err := db.Exec(`DELETE FROM sessions WHERE expires_at <= ?`, now.Unix())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row rowScanner) (*User, error) {
+	var u User
+	var createdAt, updatedAt int64
+	if err := row.Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Role, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	u.CreatedAt = time.Unix(createdAt, 0)
+	u.UpdatedAt = time.Unix(updatedAt, 0)
+	return &u, nil
+}
+
+// execOne runs a statement that must change exactly one row, returning
+// sql.ErrNoRows if it changed none
+func execOne(db *sql.DB, query string, args ...any) error {
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
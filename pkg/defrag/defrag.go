@@ -0,0 +1,436 @@
+package defrag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/elee1766/gobtr/pkg/fragmap"
+	"github.com/elee1766/gobtr/pkg/privsep"
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+// Package defrag runs targeted defragmentation jobs. Files are picked by
+// fragmentation analysis and defragmented one range at a time with
+// BTRFS_IOC_DEFRAG_RANGE, so jobs can be rate limited and cancelled.
+
+var Module = fx.Module("defrag",
+	fx.Provide(New),
+)
+
+// Job statuses
+const (
+	StatusScanning  = "scanning"
+	StatusRunning   = "running"
+	StatusFinished  = "finished"
+	StatusCancelled = "cancelled"
+	StatusFailed    = "failed"
+)
+
+// rangeSize is how much of a file is defragmented per ioctl. Smaller ranges
+// make cancel and the rate limit more responsive.
+const rangeSize = 32 << 20
+
+// maxFinishedJobs is how many finished jobs are kept for status queries
+const maxFinishedJobs = 20
+
+// Options selects the files of a defrag job and how they are defragmented
+type Options struct {
+	// Files to defragment, or directories to search recursively. A list of
+	// files from fragmentation analysis can be passed directly.
+	Paths []string
+
+	// Only defragment files at or above these thresholds. With neither set,
+	// any file with more extents than it ideally needs is selected.
+	MinDoF     float64
+	MinExtents int
+
+	// Target extent size: extents at least this long are left alone
+	// (0 = kernel default)
+	ExtentThreshold uint32
+	// Recompress with this algorithm while defragmenting ("zlib", "lzo",
+	// "zstd", empty = keep the mount's compression setting)
+	Compress string
+	// Throughput limit (bytes/sec), 0 = unlimited
+	LimitBytesPerSec int64
+	// Defragment files with shared extents. This unshares them from
+	// snapshots and reflinks and can use a lot of space.
+	AllowShared bool
+	// Select files without defragmenting them
+	DryRun bool
+}
+
+// Progress is a snapshot of a defrag job
+type Progress struct {
+	ID      string
+	Options Options
+	Status  string
+	Error   string
+
+	StartedAt  time.Time
+	FinishedAt time.Time
+
+	FilesScanned       int
+	FilesSelected      int
+	FilesDone          int
+	FilesFailed        int
+	FilesSkippedShared int
+
+	BytesTotal int64 // Size of the selected files
+	BytesDone  int64
+
+	ExtentsBefore int // Extents of the files done so far, before and after
+	ExtentsAfter  int
+
+	CurrentFile string
+}
+
+// IsRunning reports whether the job hasn't finished yet
+func (p *Progress) IsRunning() bool {
+	return p.Status == StatusScanning || p.Status == StatusRunning
+}
+
+type job struct {
+	cancel   context.CancelFunc
+	done     chan struct{}
+	mu       sync.Mutex
+	progress Progress
+}
+
+func (j *job) update(fn func(p *Progress)) {
+	j.mu.Lock()
+	fn(&j.progress)
+	j.mu.Unlock()
+}
+
+func (j *job) snapshot() Progress {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	p := j.progress
+	p.Options.Paths = slices.Clone(p.Options.Paths)
+	return p
+}
+
+// Manager runs defrag jobs, one at a time
+type Manager struct {
+	logger  *slog.Logger
+	backend privsep.Backend
+
+	mu       sync.Mutex
+	active   *job
+	finished []*job // Oldest first
+}
+
+func New(logger *slog.Logger, backend privsep.Backend) *Manager {
+	return &Manager{
+		logger:  logger.With("component", "defrag"),
+		backend: backend,
+	}
+}
+
+// Start validates opts and starts a job in the background, returning its ID
+func (m *Manager) Start(opts Options) (string, error) {
+	if len(opts.Paths) == 0 {
+		return "", fmt.Errorf("no paths given")
+	}
+	if _, ok := compressTypes[opts.Compress]; opts.Compress != "" && !ok {
+		return "", fmt.Errorf("unknown compression %q (zlib, lzo, zstd)", opts.Compress)
+	}
+	if opts.LimitBytesPerSec < 0 {
+		return "", fmt.Errorf("rate limit must not be negative")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.active != nil {
+		return "", fmt.Errorf("defrag job %s is already running", m.active.progress.ID)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &job{
+		cancel: cancel,
+		done:   make(chan struct{}),
+		progress: Progress{
+			ID:        uuid.New().String(),
+			Options:   opts,
+			Status:    StatusScanning,
+			StartedAt: time.Now(),
+		},
+	}
+	m.active = j
+
+	go m.run(ctx, j)
+
+	return j.progress.ID, nil
+}
+
+// Cancel stops the job with the given ID. The file being defragmented is
+// finished up to the current range.
+func (m *Manager) Cancel(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.active == nil || m.active.progress.ID != id {
+		return fmt.Errorf("no running defrag job %s", id)
+	}
+	m.active.cancel()
+	return nil
+}
+
+// Get returns the progress of a running or recently finished job
+func (m *Manager) Get(id string) (*Progress, error) {
+	j := m.find(id)
+	if j == nil {
+		return nil, fmt.Errorf("unknown defrag job %s", id)
+	}
+	p := j.snapshot()
+	return &p, nil
+}
+
+// List returns the running job, if any, followed by finished jobs, newest first
+func (m *Manager) List() []Progress {
+	m.mu.Lock()
+	jobs := make([]*job, 0, len(m.finished)+1)
+	if m.active != nil {
+		jobs = append(jobs, m.active)
+	}
+	for i := len(m.finished) - 1; i >= 0; i-- {
+		jobs = append(jobs, m.finished[i])
+	}
+	m.mu.Unlock()
+
+	out := make([]Progress, len(jobs))
+	for i, j := range jobs {
+		out[i] = j.snapshot()
+	}
+	return out
+}
+
+// Wait blocks until the job finishes and returns its final progress
+func (m *Manager) Wait(ctx context.Context, id string) (*Progress, error) {
+	j := m.find(id)
+	if j == nil {
+		return nil, fmt.Errorf("unknown defrag job %s", id)
+	}
+	select {
+	case <-j.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	p := j.snapshot()
+	return &p, nil
+}
+
+func (m *Manager) find(id string) *job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.active != nil && m.active.progress.ID == id {
+		return m.active
+	}
+	for _, j := range m.finished {
+		if j.progress.ID == id {
+			return j
+		}
+	}
+	return nil
+}
+
+func (m *Manager) run(ctx context.Context, j *job) {
+	opts := j.progress.Options
+	logger := m.logger.With("job", j.progress.ID)
+	logger.Info("defrag job started", "paths", opts.Paths, "dry_run", opts.DryRun)
+
+	files, err := m.selectFiles(ctx, j)
+	if err == nil && !opts.DryRun {
+		j.update(func(p *Progress) { p.Status = StatusRunning })
+		err = m.defragFiles(ctx, j, files, logger)
+	}
+
+	j.update(func(p *Progress) {
+		p.FinishedAt = time.Now()
+		p.CurrentFile = ""
+		switch {
+		case errors.Is(err, context.Canceled):
+			p.Status = StatusCancelled
+		case err != nil:
+			p.Status = StatusFailed
+			p.Error = err.Error()
+		default:
+			p.Status = StatusFinished
+		}
+	})
+	final := j.snapshot()
+	logger.Info("defrag job done", "status", final.Status, "files", final.FilesDone,
+		"failed", final.FilesFailed, "extents_before", final.ExtentsBefore, "extents_after", final.ExtentsAfter)
+
+	m.mu.Lock()
+	m.active = nil
+	m.finished = append(m.finished, j)
+	if len(m.finished) > maxFinishedJobs {
+		m.finished = m.finished[len(m.finished)-maxFinishedJobs:]
+	}
+	m.mu.Unlock()
+
+	j.cancel()
+	close(j.done)
+}
+
+// selectFiles analyzes every file under the job's paths and returns the ones
+// worth defragmenting, most fragmented first
+func (m *Manager) selectFiles(ctx context.Context, j *job) ([]*fragmap.FileFragInfo, error) {
+	opts := j.progress.Options
+	var selected []*fragmap.FileFragInfo
+	seen := make(map[string]struct{})
+
+	consider := func(path string) {
+		if _, ok := seen[path]; ok {
+			return
+		}
+		seen[path] = struct{}{}
+
+		info, err := fragmap.AnalyzeFileFragmentation(m.backend, path)
+		j.update(func(p *Progress) { p.FilesScanned++ })
+		if err != nil {
+			return
+		}
+		if !wanted(info, opts) {
+			return
+		}
+		if info.SharedExtents > 0 && !opts.AllowShared {
+			j.update(func(p *Progress) { p.FilesSkippedShared++ })
+			return
+		}
+		selected = append(selected, info)
+		j.update(func(p *Progress) {
+			p.FilesSelected++
+			p.BytesTotal += info.Size
+		})
+	}
+
+	for _, root := range opts.Paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err != nil {
+				if path == root {
+					return err
+				}
+				return nil // Skip what we can't read
+			}
+			if d.Type().IsRegular() {
+				consider(path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fragmap.SortFilesByDoF(selected)
+	return selected, nil
+}
+
+// wanted reports whether a file passes the job's thresholds
+func wanted(info *fragmap.FileFragInfo, opts Options) bool {
+	if info.Size == 0 || info.InlineExtents == info.ExtentCount {
+		return false
+	}
+	if opts.MinDoF == 0 && opts.MinExtents == 0 {
+		return info.ExtentCount > info.IdealExtents
+	}
+	if opts.MinDoF > 0 && info.DoF < opts.MinDoF {
+		return false
+	}
+	if opts.MinExtents > 0 && info.ExtentCount < opts.MinExtents {
+		return false
+	}
+	return true
+}
+
+func (m *Manager) defragFiles(ctx context.Context, j *job, files []*fragmap.FileFragInfo, logger *slog.Logger) error {
+	opts := j.progress.Options
+	start := time.Now()
+	var written int64
+
+	for _, info := range files {
+		j.update(func(p *Progress) { p.CurrentFile = info.Path })
+
+		err := m.defragFile(ctx, info, opts, func(n int64) error {
+			written += n
+			j.update(func(p *Progress) { p.BytesDone += n })
+			return throttle(ctx, start, written, opts.LimitBytesPerSec)
+		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			logger.Warn("defrag failed", "file", info.Path, "error", err)
+			j.update(func(p *Progress) { p.FilesFailed++ })
+			continue
+		}
+
+		after := info.ExtentCount
+		if a, err := fragmap.AnalyzeFileFragmentation(m.backend, info.Path); err == nil {
+			after = a.ExtentCount
+		}
+		j.update(func(p *Progress) {
+			p.FilesDone++
+			p.ExtentsBefore += info.ExtentCount
+			p.ExtentsAfter += after
+		})
+	}
+	return nil
+}
+
+// defragFile defragments one file range by range, calling done with the
+// bytes covered after each range
+func (m *Manager) defragFile(ctx context.Context, info *fragmap.FileFragInfo, opts Options, done func(n int64) error) error {
+	f, err := m.backend.Open(info.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	size := uint64(info.Size)
+	for off := uint64(0); off < size; off += rangeSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n := min(uint64(rangeSize), size-off)
+		if err := defragRange(m.backend, f, off, n, opts.ExtentThreshold, opts.Compress); err != nil {
+			return err
+		}
+		if err := done(int64(n)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// throttle sleeps until written bytes since start are within limit bytes/sec
+func throttle(ctx context.Context, start time.Time, written, limit int64) error {
+	if limit <= 0 {
+		return nil
+	}
+	due := start.Add(time.Duration(float64(written) / float64(limit) * float64(time.Second)))
+	wait := time.Until(due)
+	if wait <= 0 {
+		return nil
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
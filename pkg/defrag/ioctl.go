@@ -0,0 +1,64 @@
+package defrag
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+
+	"github.com/dennwc/ioctl"
+	"github.com/elee1766/gobtr/pkg/privsep"
+)
+
+// btrfs ioctl magic number
+const btrfsIoctlMagic = 0x94
+
+// Defrag range flags (BTRFS_DEFRAG_RANGE_*)
+const (
+	defragRangeCompress = 1 << 0
+	defragRangeStartIO  = 1 << 1
+)
+
+// Compression types for defrag, same values as btrfs_compression_type
+var compressTypes = map[string]uint32{
+	"zlib": 1,
+	"lzo":  2,
+	"zstd": 3,
+}
+
+// btrfsIoctlDefragRangeArgs matches struct btrfs_ioctl_defrag_range_args
+type btrfsIoctlDefragRangeArgs struct {
+	Start        uint64
+	Len          uint64
+	Flags        uint64
+	ExtentThresh uint32
+	CompressType uint32
+	_unused      [4]uint32
+}
+
+var ioctlDefragRange = ioctl.IOW(btrfsIoctlMagic, 16, unsafe.Sizeof(btrfsIoctlDefragRangeArgs{}))
+
+// defragRange defragments length bytes of f from start. Extents at least
+// extentThresh bytes long are left alone (0 = kernel default). A non-empty
+// compress recompresses the range with that algorithm. The range is written
+// back before returning so the caller can pace the I/O.
+func defragRange(b privsep.Backend, f *os.File, start, length uint64, extentThresh uint32, compress string) error {
+	args := btrfsIoctlDefragRangeArgs{
+		Start:        start,
+		Len:          length,
+		Flags:        defragRangeStartIO,
+		ExtentThresh: extentThresh,
+	}
+	if compress != "" {
+		ct, ok := compressTypes[compress]
+		if !ok {
+			return fmt.Errorf("unknown compression %q", compress)
+		}
+		args.Flags |= defragRangeCompress
+		args.CompressType = ct
+	}
+
+	if err := b.Ioctl(f, ioctlDefragRange, &args); err != nil {
+		return fmt.Errorf("defrag range ioctl: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package doctor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/elee1766/gobtr/pkg/btrfs"
+)
+
+// Severity of a finding
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// rank orders severities for sorting, most severe first
+func (s Severity) rank() int {
+	switch s {
+	case SeverityCritical:
+		return 0
+	case SeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Finding is one problem detected by a rule
+type Finding struct {
+	Rule     string // ID of the rule that produced the finding
+	Severity Severity
+	Title    string
+	Detail   string
+	Action   string // Recommended action
+}
+
+// Thresholds tune the rules. Zero values are replaced by the defaults.
+type Thresholds struct {
+	ScrubMaxAge      time.Duration // Warn when the last scrub is older than this
+	SlackPercent     float64       // Warn when unused space inside data chunks exceeds this percent
+	MinSlackBytes    int64         // Ignore slack below this size
+	ImbalancePercent float64       // Warn when device allocation ratios differ by more than this
+}
+
+// DefaultThresholds returns the thresholds used when none are given
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		ScrubMaxAge:      30 * 24 * time.Hour,
+		SlackPercent:     25,
+		MinSlackBytes:    10 << 30,
+		ImbalancePercent: 25,
+	}
+}
+
+func (t Thresholds) withDefaults() Thresholds {
+	d := DefaultThresholds()
+	if t.ScrubMaxAge <= 0 {
+		t.ScrubMaxAge = d.ScrubMaxAge
+	}
+	if t.SlackPercent <= 0 {
+		t.SlackPercent = d.SlackPercent
+	}
+	if t.MinSlackBytes <= 0 {
+		t.MinSlackBytes = d.MinSlackBytes
+	}
+	if t.ImbalancePercent <= 0 {
+		t.ImbalancePercent = d.ImbalancePercent
+	}
+	return t
+}
+
+// Device holds what the rules need to know about one device
+type Device struct {
+	DevID      uint64
+	Path       string
+	Size       int64
+	Allocated  int64
+	Rotational bool
+	RotKnown   bool // Whether Rotational could be determined
+	Errors     *btrfs.DeviceErrorStats
+}
+
+// Facts is everything the rules evaluate, gathered once per run
+type Facts struct {
+	Path    string
+	UUID    string
+	Now     time.Time
+	Devices []Device
+	Usage   *btrfs.FilesystemUsage
+	Scrub   *btrfs.ScrubStatus     // nil if the status could not be read
+	Stale   []*btrfs.StaleProfiles // nil if the chunk tree could not be read
+}
+
+// Report is the result of running every rule against a filesystem
+type Report struct {
+	Path      string
+	UUID      string
+	CheckedAt time.Time
+	Score     int // 0-100, higher is healthier
+	Findings  []Finding
+}
+
+// Gather reads the current state of the filesystem mounted at path
+func Gather(mgr *btrfs.Manager, path string) (*Facts, error) {
+	fsInfo, devInfos, err := btrfs.GetFilesystemAndDeviceInfo(path)
+	if err != nil {
+		return nil, fmt.Errorf("get filesystem/device info: %w", err)
+	}
+
+	usage, err := mgr.GetFilesystemUsage(path)
+	if err != nil {
+		return nil, fmt.Errorf("get filesystem usage: %w", err)
+	}
+
+	facts := &Facts{
+		Path:  path,
+		UUID:  fsInfo.UUID,
+		Now:   time.Now(),
+		Usage: usage,
+	}
+
+	errorStats, _ := btrfs.GetDeviceErrorStats(fsInfo.UUID)
+	for _, d := range devInfos {
+		dev := Device{
+			DevID:     d.DevID,
+			Path:      d.Path,
+			Size:      int64(d.TotalBytes),
+			Allocated: int64(d.BytesUsed),
+			Errors:    errorStats[d.DevID],
+		}
+		if rot, err := btrfs.IsRotational(d.Path); err == nil {
+			dev.Rotational = rot
+			dev.RotKnown = true
+		}
+		facts.Devices = append(facts.Devices, dev)
+	}
+
+	if scrub, err := mgr.GetScrubStatus(path); err == nil {
+		facts.Scrub = scrub
+	}
+
+	if stale, err := btrfs.DetectStaleProfiles(mgr.Backend(), path); err == nil {
+		facts.Stale = stale
+	}
+
+	return facts, nil
+}
+
+// Run evaluates every rule against the facts
+func Run(facts *Facts, thresholds Thresholds) *Report {
+	thresholds = thresholds.withDefaults()
+
+	report := &Report{
+		Path:      facts.Path,
+		UUID:      facts.UUID,
+		CheckedAt: facts.Now,
+		Score:     100,
+	}
+
+	for _, rule := range Rules {
+		report.Findings = append(report.Findings, rule.Check(facts, thresholds)...)
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return report.Findings[i].Severity.rank() < report.Findings[j].Severity.rank()
+	})
+
+	for _, f := range report.Findings {
+		switch f.Severity {
+		case SeverityCritical:
+			report.Score -= 30
+		case SeverityWarning:
+			report.Score -= 10
+		case SeverityInfo:
+			report.Score -= 2
+		}
+	}
+	if report.Score < 0 {
+		report.Score = 0
+	}
+
+	return report
+}
+
+// Diagnose gathers facts and runs every rule
+func Diagnose(mgr *btrfs.Manager, path string, thresholds Thresholds) (*Report, error) {
+	facts, err := Gather(mgr, path)
+	if err != nil {
+		return nil, err
+	}
+	return Run(facts, thresholds), nil
+}
@@ -0,0 +1,321 @@
+package doctor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/elee1766/gobtr/pkg/forecast"
+)
+
+// Rule checks one aspect of filesystem health
+type Rule struct {
+	ID          string
+	Description string
+	Check       func(f *Facts, t Thresholds) []Finding
+}
+
+// Rules is the rule set run by Run, in report order
+var Rules = []Rule{
+	{ID: "device_errors", Description: "Device error counters are non-zero", Check: checkDeviceErrors},
+	{ID: "scrub_errors", Description: "The last scrub found errors", Check: checkScrubErrors},
+	{ID: "enospc_risk", Description: "Allocation state that can lead to ENOSPC", Check: checkEnospc},
+	{ID: "mixed_profiles", Description: "Block groups of one type use more than one profile", Check: checkMixedProfiles},
+	{ID: "single_metadata_multi_device", Description: "Metadata is not redundant on a multi-device filesystem", Check: checkSingleMetadata},
+	{ID: "dup_data_on_ssd", Description: "DUP data on solid state storage", Check: checkDupDataOnSSD},
+	{ID: "scrub_overdue", Description: "No recent successful scrub", Check: checkScrubOverdue},
+	{ID: "data_slack", Description: "Too much unused space inside data chunks", Check: checkSlack},
+	{ID: "unbalanced_allocation", Description: "Devices are allocated unevenly", Check: checkImbalance},
+}
+
+// profilesByType maps block group type ("data", "metadata", "system") to bytes per profile
+func profilesByType(f *Facts) map[string]map[string]int64 {
+	out := make(map[string]map[string]int64)
+	for _, ag := range f.Usage.Allocations {
+		t := strings.ToLower(ag.Type)
+		if t != "data" && t != "metadata" && t != "system" {
+			continue
+		}
+		if out[t] == nil {
+			out[t] = make(map[string]int64)
+		}
+		out[t][ag.Profile] += ag.Size
+	}
+	return out
+}
+
+// dominantProfile returns the profile holding the most bytes
+func dominantProfile(profiles map[string]int64) string {
+	var best string
+	var bestSize int64 = -1
+	for p, size := range profiles {
+		if size > bestSize || (size == bestSize && p < best) {
+			best, bestSize = p, size
+		}
+	}
+	return best
+}
+
+func checkDeviceErrors(f *Facts, _ Thresholds) []Finding {
+	var findings []Finding
+	for _, dev := range f.Devices {
+		e := dev.Errors
+		if e == nil {
+			continue
+		}
+		total := e.WriteErrors + e.ReadErrors + e.FlushErrors + e.CorruptionErrors + e.GenerationErrors
+		if total == 0 {
+			continue
+		}
+		findings = append(findings, Finding{
+			Rule:     "device_errors",
+			Severity: SeverityCritical,
+			Title:    fmt.Sprintf("Device %d (%s) has recorded errors", dev.DevID, dev.Path),
+			Detail: fmt.Sprintf("write %d, read %d, flush %d, corruption %d, generation %d",
+				e.WriteErrors, e.ReadErrors, e.FlushErrors, e.CorruptionErrors, e.GenerationErrors),
+			Action: fmt.Sprintf("Check the kernel log and SMART data for %s, run a scrub, and replace the device if errors keep growing. Reset the counters with 'btrfs device stats -z %s' once resolved.", dev.Path, f.Path),
+		})
+	}
+	return findings
+}
+
+func checkScrubErrors(f *Facts, _ Thresholds) []Finding {
+	s := f.Scrub
+	if s == nil || s.Status == "never_run" {
+		return nil
+	}
+
+	switch {
+	case s.UncorrectableErrors > 0:
+		return []Finding{{
+			Rule:     "scrub_errors",
+			Severity: SeverityCritical,
+			Title:    "Last scrub found uncorrectable errors",
+			Detail:   fmt.Sprintf("%d uncorrectable, %d corrected", s.UncorrectableErrors, s.CorrectedErrors),
+			Action:   "Find the affected files in the kernel log and restore them from backup. Uncorrectable errors mean no good copy was available.",
+		}}
+	case s.CorrectedErrors > 0:
+		return []Finding{{
+			Rule:     "scrub_errors",
+			Severity: SeverityWarning,
+			Title:    "Last scrub corrected errors",
+			Detail:   fmt.Sprintf("%d errors were repaired from a redundant copy", s.CorrectedErrors),
+			Action:   "Check device error counters and SMART data; repeated corrections point at a failing device.",
+		}}
+	}
+	return nil
+}
+
+func checkEnospc(f *Facts, _ Thresholds) []Finding {
+	u := f.Usage
+	sample := forecast.Sample{
+		Time:              f.Now,
+		DeviceSize:        u.DeviceSize,
+		Unallocated:       u.DeviceUnallocated,
+		GlobalReserve:     u.GlobalReserve,
+		GlobalReserveUsed: u.GlobalReserveUsed,
+	}
+	for _, ag := range u.Allocations {
+		switch strings.ToLower(ag.Type) {
+		case "data":
+			sample.DataTotal += ag.Size
+			sample.DataUsed += ag.Used
+		case "metadata":
+			sample.MetadataTotal += ag.Size
+			sample.MetadataUsed += ag.Used
+		}
+	}
+
+	profiles := profilesByType(f)
+	fc := forecast.Compute([]forecast.Sample{sample}, forecast.Options{
+		DataProfile:     dominantProfile(profiles["data"]),
+		MetadataProfile: dominantProfile(profiles["metadata"]),
+	})
+
+	var findings []Finding
+	for _, r := range fc.Risks {
+		sev := SeverityWarning
+		if r.Severity == forecast.SeverityCritical {
+			sev = SeverityCritical
+		}
+		findings = append(findings, Finding{
+			Rule:     "enospc_risk",
+			Severity: sev,
+			Title:    "ENOSPC risk: " + strings.ReplaceAll(r.Code, "_", " "),
+			Detail:   r.Message,
+			Action:   fmt.Sprintf("Free unallocated space by compacting data chunks, e.g. 'btrfs balance start -dusage=10 %s' and raising the usage filter step by step, or add a device.", f.Path),
+		})
+	}
+	return findings
+}
+
+func checkMixedProfiles(f *Facts, _ Thresholds) []Finding {
+	var findings []Finding
+	for _, sp := range f.Stale {
+		t := strings.ToLower(sp.Type)
+
+		var stale []string
+		var staleChunks int
+		for _, u := range sp.Stale {
+			stale = append(stale, fmt.Sprintf("%s (%d chunks, %s)", u.Profile, u.Chunks, humanize.IBytes(u.Bytes)))
+			staleChunks += u.Chunks
+		}
+		sort.Strings(stale)
+
+		detail := fmt.Sprintf("%d chunks not yet in %s: %s.", staleChunks, sp.Target, strings.Join(stale, ", "))
+		if sp.TargetSource == "balance" {
+			detail += " A paused balance is converting to " + sp.Target + "."
+		} else {
+			detail += " This is usually left over from an interrupted conversion."
+		}
+
+		flag := map[string]string{"data": "-d", "metadata": "-m", "system": "-s"}[t]
+		force := ""
+		if t == "system" {
+			force = " -f"
+		}
+
+		findings = append(findings, Finding{
+			Rule:     "mixed_profiles",
+			Severity: SeverityWarning,
+			Title:    fmt.Sprintf("%s uses more than one profile", sp.Type),
+			Detail:   detail,
+			Action:   fmt.Sprintf("Finish the conversion with 'btrfs balance start %sconvert=%s,soft%s %s'.", flag, strings.ToLower(sp.Target), force, f.Path),
+		})
+	}
+	return findings
+}
+
+func checkSingleMetadata(f *Facts, _ Thresholds) []Finding {
+	if len(f.Devices) < 2 {
+		return nil
+	}
+	for p := range profilesByType(f)["metadata"] {
+		if strings.EqualFold(p, "single") || strings.EqualFold(p, "RAID0") {
+			return []Finding{{
+				Rule:     "single_metadata_multi_device",
+				Severity: SeverityWarning,
+				Title:    fmt.Sprintf("Metadata is %s on a %d-device filesystem", p, len(f.Devices)),
+				Detail:   "Losing any one device makes the whole filesystem unreadable.",
+				Action:   fmt.Sprintf("Convert metadata to a redundant profile with 'btrfs balance start -mconvert=raid1 %s'.", f.Path),
+			}}
+		}
+	}
+	return nil
+}
+
+func checkDupDataOnSSD(f *Facts, _ Thresholds) []Finding {
+	if _, ok := profilesByType(f)["data"]["DUP"]; !ok {
+		return nil
+	}
+	for _, dev := range f.Devices {
+		if !dev.RotKnown || dev.Rotational {
+			return nil
+		}
+	}
+	return []Finding{{
+		Rule:     "dup_data_on_ssd",
+		Severity: SeverityInfo,
+		Title:    "DUP data on solid state storage",
+		Detail:   "Both copies are written to the same device, doubling writes. Some SSD controllers deduplicate internally, so the second copy may not protect against media errors.",
+		Action:   fmt.Sprintf("If the redundancy is not needed, convert with 'btrfs balance start -dconvert=single %s'.", f.Path),
+	}}
+}
+
+func checkScrubOverdue(f *Facts, t Thresholds) []Finding {
+	s := f.Scrub
+	if s == nil || s.IsRunning {
+		return nil
+	}
+
+	action := fmt.Sprintf("Run 'btrfs scrub start %s' and schedule scrubs regularly.", f.Path)
+
+	switch {
+	case s.Status == "never_run":
+		return []Finding{{
+			Rule:     "scrub_overdue",
+			Severity: SeverityWarning,
+			Title:    "Filesystem has never been scrubbed",
+			Action:   action,
+		}}
+	case s.Status == "aborted":
+		return []Finding{{
+			Rule:     "scrub_overdue",
+			Severity: SeverityWarning,
+			Title:    "Last scrub did not finish",
+			Detail:   fmt.Sprintf("Started %s", s.StartedAt.Format("2006-01-02 15:04")),
+			Action:   action,
+		}}
+	case !s.FinishedAt.IsZero() && f.Now.Sub(s.FinishedAt) > t.ScrubMaxAge:
+		return []Finding{{
+			Rule:     "scrub_overdue",
+			Severity: SeverityWarning,
+			Title:    fmt.Sprintf("No scrub in over %d days", int(t.ScrubMaxAge.Hours()/24)),
+			Detail:   fmt.Sprintf("Last scrub finished %s", humanize.Time(s.FinishedAt)),
+			Action:   action,
+		}}
+	}
+	return nil
+}
+
+func checkSlack(f *Facts, t Thresholds) []Finding {
+	var allocated, used int64
+	for _, ag := range f.Usage.Allocations {
+		if strings.EqualFold(ag.Type, "data") {
+			allocated += ag.Size
+			used += ag.Used
+		}
+	}
+	slack := allocated - used
+	if allocated == 0 || slack < t.MinSlackBytes {
+		return nil
+	}
+
+	pct := float64(slack) / float64(allocated) * 100
+	if pct <= t.SlackPercent {
+		return nil
+	}
+
+	return []Finding{{
+		Rule:     "data_slack",
+		Severity: SeverityInfo,
+		Title:    fmt.Sprintf("%.0f%% of allocated data space is unused", pct),
+		Detail:   fmt.Sprintf("%s allocated to data chunks, %s used", humanize.IBytes(uint64(allocated)), humanize.IBytes(uint64(used))),
+		Action:   fmt.Sprintf("Return partially filled chunks to unallocated space with 'btrfs balance start -dusage=%d %s'.", min(int(100-pct)+10, 90), f.Path),
+	}}
+}
+
+func checkImbalance(f *Facts, t Thresholds) []Finding {
+	if len(f.Devices) < 2 {
+		return nil
+	}
+
+	var minDev, maxDev *Device
+	var minPct, maxPct float64
+	for i := range f.Devices {
+		dev := &f.Devices[i]
+		if dev.Size == 0 {
+			continue
+		}
+		pct := float64(dev.Allocated) / float64(dev.Size) * 100
+		if minDev == nil || pct < minPct {
+			minDev, minPct = dev, pct
+		}
+		if maxDev == nil || pct > maxPct {
+			maxDev, maxPct = dev, pct
+		}
+	}
+	if minDev == nil || maxPct-minPct <= t.ImbalancePercent {
+		return nil
+	}
+
+	return []Finding{{
+		Rule:     "unbalanced_allocation",
+		Severity: SeverityWarning,
+		Title:    "Devices are allocated unevenly",
+		Detail: fmt.Sprintf("device %d (%s) is %.0f%% allocated, device %d (%s) is %.0f%% allocated",
+			maxDev.DevID, maxDev.Path, maxPct, minDev.DevID, minDev.Path, minPct),
+		Action: fmt.Sprintf("Spread chunks across devices with 'btrfs balance start -ddevid=%d %s'; use a full balance after adding a device.", maxDev.DevID, f.Path),
+	}}
+}
@@ -0,0 +1,297 @@
+package forecast
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	day = 24 * time.Hour
+
+	// maxHorizon caps predictions; anything further out is reported as "never"
+	maxHorizon = 10 * 365 * day
+
+	// Chunk sizes used by the kernel allocator for regular (non-zoned) filesystems
+	dataChunkSize          = 1 << 30
+	metadataChunkSizeSmall = 256 << 20
+	metadataChunkSizeLarge = 1 << 30
+	largeFilesystemSize    = 50 << 30
+
+	// metadataNearlyFull is the used+reserve fraction at which metadata is considered nearly full
+	metadataNearlyFull = 0.90
+	// dataNearlyFull is the used fraction at which data chunks are considered nearly full
+	dataNearlyFull = 0.98
+)
+
+// Severity of a risk flag
+type Severity string
+
+const (
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Sample is one usage observation of a filesystem
+type Sample struct {
+	Time              time.Time
+	DeviceSize        int64
+	Unallocated       int64
+	DataTotal         int64
+	DataUsed          int64
+	MetadataTotal     int64
+	MetadataUsed      int64
+	GlobalReserve     int64
+	GlobalReserveUsed int64
+}
+
+// Trend is a least-squares linear fit of a value over time
+type Trend struct {
+	BytesPerDay float64
+	RSquared    float64 // Goodness of fit (0-1)
+}
+
+// Risk is a condition that may lead to ENOSPC
+type Risk struct {
+	Code     string
+	Severity Severity
+	Message  string
+}
+
+// Forecast holds fitted growth trends and predicted exhaustion dates
+type Forecast struct {
+	Samples     int
+	WindowStart time.Time
+	WindowEnd   time.Time
+
+	Unallocated       Trend
+	DataAllocated     Trend
+	DataUsed          Trend
+	MetadataAllocated Trend
+	MetadataUsed      Trend
+
+	// Zero when the trend does not reach exhaustion within the horizon.
+	// MetadataChunkExhaustedAt is when a new metadata chunk can no longer be
+	// allocated, MetadataExhaustedAt when the existing ones are full too.
+	UnallocatedExhaustedAt   time.Time
+	MetadataChunkExhaustedAt time.Time
+	MetadataExhaustedAt      time.Time
+
+	// Raw device bytes needed to allocate one more chunk
+	DataChunkBytes     int64
+	MetadataChunkBytes int64
+
+	Risks []Risk
+}
+
+// Options describe the filesystem the samples belong to
+type Options struct {
+	DataProfile     string // e.g. "single", "RAID1"
+	MetadataProfile string // e.g. "DUP", "RAID1C3"
+}
+
+// Compute fits trends to the samples (oldest first) and evaluates ENOSPC risk
+// against the latest sample.
+//
+// Chunk allocation is modelled against total unallocated space; on multi-device
+// filesystems with mirrored profiles the real limit can be reached earlier if
+// the unallocated space is concentrated on too few devices.
+func Compute(samples []Sample, opts Options) *Forecast {
+	f := &Forecast{Samples: len(samples)}
+	if len(samples) == 0 {
+		return f
+	}
+
+	first, latest := samples[0], samples[len(samples)-1]
+	f.WindowStart = first.Time
+	f.WindowEnd = latest.Time
+
+	f.DataChunkBytes = dataChunkSize * copies(opts.DataProfile)
+	metadataChunk := int64(metadataChunkSizeSmall)
+	if latest.DeviceSize > largeFilesystemSize {
+		metadataChunk = metadataChunkSizeLarge
+	}
+	f.MetadataChunkBytes = metadataChunk * copies(opts.MetadataProfile)
+
+	f.Unallocated = fit(samples, func(s Sample) int64 { return s.Unallocated })
+	f.DataAllocated = fit(samples, func(s Sample) int64 { return s.DataTotal })
+	f.DataUsed = fit(samples, func(s Sample) int64 { return s.DataUsed })
+	f.MetadataAllocated = fit(samples, func(s Sample) int64 { return s.MetadataTotal })
+	f.MetadataUsed = fit(samples, func(s Sample) int64 { return s.MetadataUsed })
+
+	// Unallocated space running out entirely
+	if days, ok := daysUntil(float64(latest.Unallocated), 0, f.Unallocated.BytesPerDay); ok {
+		f.UnallocatedExhaustedAt = latest.Time.Add(time.Duration(days * float64(day)))
+	}
+
+	// Metadata running out: first the allocator can no longer create a
+	// metadata chunk, then the existing metadata chunks fill up
+	if daysNoChunk, ok := daysUntil(float64(latest.Unallocated), float64(f.MetadataChunkBytes), f.Unallocated.BytesPerDay); ok {
+		f.MetadataChunkExhaustedAt = latest.Time.Add(time.Duration(daysNoChunk * float64(day)))
+
+		capacity := float64(latest.MetadataTotal) + math.Max(0, f.MetadataAllocated.BytesPerDay)*daysNoChunk
+		usable := capacity - float64(latest.GlobalReserve)
+
+		daysFull := 0.0
+		switch {
+		case float64(latest.MetadataUsed) >= usable:
+		case f.MetadataUsed.BytesPerDay > 0:
+			daysFull = (usable - float64(latest.MetadataUsed)) / f.MetadataUsed.BytesPerDay
+		default:
+			daysFull = math.Inf(1)
+		}
+
+		if days := math.Max(daysNoChunk, daysFull); days*float64(day) <= float64(maxHorizon) {
+			f.MetadataExhaustedAt = latest.Time.Add(time.Duration(days * float64(day)))
+		}
+	}
+
+	f.Risks = assessRisks(f, latest)
+	return f
+}
+
+func assessRisks(f *Forecast, s Sample) []Risk {
+	var risks []Risk
+
+	canAllocateMetadata := s.Unallocated >= f.MetadataChunkBytes
+	canAllocateData := s.Unallocated >= f.DataChunkBytes
+
+	if s.GlobalReserveUsed > 0 {
+		risks = append(risks, Risk{
+			Code:     "global_reserve_in_use",
+			Severity: SeverityCritical,
+			Message:  fmt.Sprintf("global reserve is in use (%s of %s); metadata space is exhausted", humanize.IBytes(uint64(s.GlobalReserveUsed)), humanize.IBytes(uint64(s.GlobalReserve))),
+		})
+	}
+
+	if s.MetadataTotal > 0 {
+		metaPct := float64(s.MetadataUsed+s.GlobalReserve) / float64(s.MetadataTotal)
+		if metaPct >= metadataNearlyFull && !canAllocateMetadata {
+			risks = append(risks, Risk{
+				Code:     "metadata_full_no_unallocated",
+				Severity: SeverityCritical,
+				Message:  fmt.Sprintf("metadata is %.0f%% full (including global reserve) and there is not enough unallocated space for a new metadata chunk", metaPct*100),
+			})
+		}
+	}
+
+	if !canAllocateMetadata {
+		risks = append(risks, Risk{
+			Code:     "no_unallocated_for_metadata",
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("only %s unallocated; a new metadata chunk needs %s", humanize.IBytes(uint64(max(s.Unallocated, 0))), humanize.IBytes(uint64(f.MetadataChunkBytes))),
+		})
+	}
+
+	if s.DataTotal > 0 && !canAllocateData {
+		dataPct := float64(s.DataUsed) / float64(s.DataTotal)
+		if dataPct >= dataNearlyFull {
+			risks = append(risks, Risk{
+				Code:     "data_full_no_unallocated",
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("data chunks are %.0f%% full and there is not enough unallocated space for a new data chunk", dataPct*100),
+			})
+		}
+	}
+
+	risks = appendSoonRisk(risks, "unallocated_exhausted_soon", "unallocated space", f.UnallocatedExhaustedAt, s.Time)
+	// Already flagged above when there is no room for a chunk today
+	if canAllocateMetadata {
+		risks = appendSoonRisk(risks, "metadata_chunk_exhausted_soon", "unallocated space for a new metadata chunk", f.MetadataChunkExhaustedAt, s.Time)
+	}
+	risks = appendSoonRisk(risks, "metadata_exhausted_soon", "metadata space", f.MetadataExhaustedAt, s.Time)
+
+	return risks
+}
+
+// appendSoonRisk flags a predicted exhaustion date within the next 30 days
+func appendSoonRisk(risks []Risk, code, what string, at, now time.Time) []Risk {
+	if at.IsZero() {
+		return risks
+	}
+	remaining := at.Sub(now)
+
+	var severity Severity
+	switch {
+	case remaining <= 7*day:
+		severity = SeverityCritical
+	case remaining <= 30*day:
+		severity = SeverityWarning
+	default:
+		return risks
+	}
+
+	return append(risks, Risk{
+		Code:     code,
+		Severity: severity,
+		Message:  fmt.Sprintf("%s is predicted to run out %s (%s)", what, humanize.Time(at), at.Format("2006-01-02")),
+	})
+}
+
+// fit performs an ordinary least-squares fit of value against time in days
+func fit(samples []Sample, value func(Sample) int64) Trend {
+	if len(samples) < 2 {
+		return Trend{}
+	}
+
+	t0 := samples[0].Time
+	n := float64(len(samples))
+	var sumX, sumY float64
+	for _, s := range samples {
+		sumX += s.Time.Sub(t0).Hours() / 24
+		sumY += float64(value(s))
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var sxx, sxy, syy float64
+	for _, s := range samples {
+		dx := s.Time.Sub(t0).Hours()/24 - meanX
+		dy := float64(value(s)) - meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	if sxx == 0 {
+		return Trend{}
+	}
+
+	t := Trend{BytesPerDay: sxy / sxx, RSquared: 1}
+	if syy > 0 {
+		t.RSquared = (sxy * sxy) / (sxx * syy)
+	}
+	return t
+}
+
+// daysUntil returns how many days until a value falls to the target at the
+// given rate. It reports false if that never happens within the horizon.
+func daysUntil(current, target, perDay float64) (float64, bool) {
+	if current <= target {
+		return 0, true
+	}
+	if perDay >= 0 {
+		return 0, false
+	}
+	days := (current - target) / -perDay
+	if days*float64(day) > float64(maxHorizon) {
+		return 0, false
+	}
+	return days, true
+}
+
+// copies returns how many raw bytes a profile consumes per logical byte,
+// rounded to the number of full copies it keeps
+func copies(profile string) int64 {
+	switch strings.ToUpper(profile) {
+	case "DUP", "RAID1", "RAID10":
+		return 2
+	case "RAID1C3":
+		return 3
+	case "RAID1C4":
+		return 4
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,212 @@
+package forecast
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+const gib = 1 << 30
+
+var epoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// daily returns one sample a day, with unallocated space changing by
+// perDay from start
+func daily(n int, start, perDay int64, base Sample) []Sample {
+	samples := make([]Sample, n)
+	for i := range samples {
+		s := base
+		s.Time = epoch.Add(time.Duration(i) * day)
+		s.Unallocated = start + perDay*int64(i)
+		samples[i] = s
+	}
+	return samples
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []int64
+		perDay   float64
+		rSquared float64
+	}{
+		{name: "empty"},
+		{name: "single point", values: []int64{100}},
+		{name: "flat", values: []int64{100, 100, 100}, rSquared: 1},
+		{name: "growing", values: []int64{0, 10, 20, 30}, perDay: 10, rSquared: 1},
+		{name: "shrinking", values: []int64{30, 20, 10, 0}, perDay: -10, rSquared: 1},
+		{name: "noisy", values: []int64{0, 20, 0, 20}, perDay: 4, rSquared: 0.2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples := make([]Sample, len(tt.values))
+			for i, v := range tt.values {
+				samples[i] = Sample{Time: epoch.Add(time.Duration(i) * day), Unallocated: v}
+			}
+			got := fit(samples, func(s Sample) int64 { return s.Unallocated })
+			if math.Abs(got.BytesPerDay-tt.perDay) > 1e-9 || math.Abs(got.RSquared-tt.rSquared) > 1e-9 {
+				t.Errorf("fit = %+v, want %v/day, r² %v", got, tt.perDay, tt.rSquared)
+			}
+		})
+	}
+
+	// Samples at the same time have no slope
+	same := []Sample{{Time: epoch, Unallocated: 1}, {Time: epoch, Unallocated: 2}}
+	if got := fit(same, func(s Sample) int64 { return s.Unallocated }); got != (Trend{}) {
+		t.Errorf("fit of simultaneous samples = %+v", got)
+	}
+}
+
+func TestDaysUntil(t *testing.T) {
+	tests := []struct {
+		name                    string
+		current, target, perDay float64
+		days                    float64
+		ok                      bool
+	}{
+		{name: "already there", current: 5, target: 10, perDay: 1, ok: true},
+		{name: "exactly there", current: 10, target: 10, perDay: -1, ok: true},
+		{name: "flat", current: 20, target: 10, perDay: 0},
+		{name: "growing", current: 20, target: 10, perDay: 1},
+		{name: "shrinking", current: 20, target: 10, perDay: -2, days: 5, ok: true},
+		{name: "past the horizon", current: 1e12, target: 0, perDay: -1},
+	}
+	for _, tt := range tests {
+		days, ok := daysUntil(tt.current, tt.target, tt.perDay)
+		if days != tt.days || ok != tt.ok {
+			t.Errorf("%s: daysUntil = %v, %v, want %v, %v", tt.name, days, ok, tt.days, tt.ok)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	// 20GiB device, so metadata chunks are 256MiB, 512MiB raw as DUP
+	base := Sample{
+		DeviceSize:    20 * gib,
+		DataTotal:     8 * gib,
+		DataUsed:      4 * gib,
+		MetadataTotal: gib,
+		MetadataUsed:  gib / 4,
+		GlobalReserve: 16 << 20,
+	}
+	opts := Options{DataProfile: "single", MetadataProfile: "DUP"}
+
+	tests := []struct {
+		name    string
+		samples []Sample
+		// Days from the latest sample, -1 for not predicted
+		unallocated, metadataChunk, metadata float64
+		risks                                []string
+	}{
+		{name: "no samples", unallocated: -1, metadataChunk: -1, metadata: -1},
+		{
+			name:          "single point",
+			samples:       daily(1, 10*gib, 0, base),
+			unallocated:   -1,
+			metadataChunk: -1,
+			metadata:      -1,
+		},
+		{
+			name:          "steady",
+			samples:       daily(10, 10*gib, 0, base),
+			unallocated:   -1,
+			metadataChunk: -1,
+			metadata:      -1,
+		},
+		{
+			name:          "growing",
+			samples:       daily(10, 10*gib, gib/10, base),
+			unallocated:   -1,
+			metadataChunk: -1,
+			metadata:      -1,
+		},
+		{
+			// 10GiB left, losing 1GiB a day, while metadata use stays put
+			name:          "shrinking",
+			samples:       daily(10, 19*gib, -gib, base),
+			unallocated:   10,
+			metadataChunk: 9.5,
+			metadata:      -1,
+			risks:         []string{"unallocated_exhausted_soon", "metadata_chunk_exhausted_soon"},
+		},
+		{
+			// Metadata already full, so it runs out when chunks do
+			name:          "shrinking with full metadata",
+			samples:       daily(10, 19*gib, -gib, Sample{DeviceSize: 20 * gib, MetadataTotal: gib, MetadataUsed: gib}),
+			unallocated:   10,
+			metadataChunk: 9.5,
+			metadata:      9.5,
+			risks:         []string{"unallocated_exhausted_soon", "metadata_chunk_exhausted_soon", "metadata_exhausted_soon"},
+		},
+		{
+			// No room for another chunk of either kind, today
+			name:          "full",
+			samples:       daily(3, 0, 0, base),
+			unallocated:   0,
+			metadataChunk: 0,
+			metadata:      -1,
+			risks:         []string{"no_unallocated_for_metadata", "unallocated_exhausted_soon"},
+		},
+		{
+			name:          "full with full metadata",
+			samples:       daily(3, 0, 0, Sample{DeviceSize: 20 * gib, MetadataTotal: gib, MetadataUsed: gib, GlobalReserve: 16 << 20, GlobalReserveUsed: 1 << 20}),
+			unallocated:   0,
+			metadataChunk: 0,
+			metadata:      0,
+			risks:         []string{"global_reserve_in_use", "metadata_full_no_unallocated", "no_unallocated_for_metadata", "unallocated_exhausted_soon", "metadata_exhausted_soon"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Compute(tt.samples, opts)
+			if f.Samples != len(tt.samples) {
+				t.Errorf("Samples = %d, want %d", f.Samples, len(tt.samples))
+			}
+			if len(tt.samples) == 0 {
+				if f.Risks != nil || !f.UnallocatedExhaustedAt.IsZero() {
+					t.Errorf("forecast without samples: %+v", f)
+				}
+				return
+			}
+			if f.MetadataChunkBytes != 512<<20 || f.DataChunkBytes != gib {
+				t.Errorf("chunk bytes: data %d, metadata %d", f.DataChunkBytes, f.MetadataChunkBytes)
+			}
+
+			latest := tt.samples[len(tt.samples)-1].Time
+			checkAt(t, "UnallocatedExhaustedAt", f.UnallocatedExhaustedAt, latest, tt.unallocated)
+			checkAt(t, "MetadataChunkExhaustedAt", f.MetadataChunkExhaustedAt, latest, tt.metadataChunk)
+			checkAt(t, "MetadataExhaustedAt", f.MetadataExhaustedAt, latest, tt.metadata)
+
+			var codes []string
+			for _, r := range f.Risks {
+				codes = append(codes, r.Code)
+			}
+			if !slices.Equal(codes, tt.risks) {
+				t.Errorf("risks %v, want %v", codes, tt.risks)
+			}
+		})
+	}
+}
+
+func TestCopies(t *testing.T) {
+	for profile, want := range map[string]int64{"": 1, "single": 1, "dup": 2, "RAID1": 2, "RAID10": 2, "RAID1C3": 3, "raid1c4": 4, "RAID5": 1} {
+		if got := copies(profile); got != want {
+			t.Errorf("copies(%q) = %d, want %d", profile, got, want)
+		}
+	}
+}
+
+func checkAt(t *testing.T, name string, at, from time.Time, days float64) {
+	t.Helper()
+	if days < 0 {
+		if !at.IsZero() {
+			t.Errorf("%s = %v, want not predicted", name, at)
+		}
+		return
+	}
+	if want := from.Add(time.Duration(days * float64(day))); !at.Equal(want) {
+		t.Errorf("%s = %v, want %v", name, at, want)
+	}
+}
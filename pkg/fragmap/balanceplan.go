@@ -0,0 +1,202 @@
+package fragmap
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultBalanceThresholds are the usage= filters PlanBalance tries. usage=0
+// is left out because the kernel already frees empty block groups itself,
+// and 0 disables the filter in BalanceOptions.
+var DefaultBalanceThresholds = []int{1, 5, 10, 15, 20, 25, 30, 40, 50, 60, 70, 80, 90, 100}
+
+// BalancePlanOptions controls PlanBalance
+type BalancePlanOptions struct {
+	// Block group type to balance (default data)
+	Type BlockType
+	// usage= filters to simulate (default DefaultBalanceThresholds)
+	Thresholds []int
+	// Unallocated raw device bytes to end up with. 0 means 10% of the raw
+	// device size.
+	GoalUnallocated uint64
+}
+
+// BalanceRun is the simulated outcome of one usage= balance
+type BalanceRun struct {
+	Usage int // The usage= filter
+	// Chunks the filter selects and their logical size
+	Chunks     int
+	ChunkBytes uint64
+	// Used bytes rewritten by the relocation
+	MovedBytes uint64
+	// New chunks needed for moved data that doesn't fit into the slack of
+	// the chunks that stay
+	NewChunks int
+	// Raw device bytes returned to unallocated, net of new chunks
+	FreedBytes       uint64
+	UnallocatedAfter uint64
+	ReachesGoal      bool
+}
+
+// BalancePlan compares usage= balances of one block group type
+type BalancePlan struct {
+	Type BlockType
+	// All chunks of the type
+	Chunks int
+	Length uint64
+	Used   uint64
+	// Logical size of a new chunk, assumed for moved data that doesn't fit
+	ChunkSize uint64
+	// Raw device bytes currently unallocated, and the goal
+	Unallocated uint64
+	Goal        uint64
+	Runs        []BalanceRun
+	// Index into Runs of the cheapest run that reaches the goal, or failing
+	// that the one that frees the most. -1 if no run frees anything or the
+	// goal is already met.
+	Recommended int
+}
+
+// PlanBalance simulates usage= balances against the block group usage in a
+// scan. The kernel relocates chunks with used < length*usage/100, and moved
+// extents first fill free space in the chunks that are not relocated. This
+// is an estimate: the kernel checks the filter as it reaches each chunk, by
+// which time earlier relocations may have filled it past the threshold.
+func PlanBalance(fm *FragMap, opts BalancePlanOptions) *BalancePlan {
+	if opts.Type == 0 {
+		opts.Type = BlockTypeData
+	}
+	thresholds := opts.Thresholds
+	if len(thresholds) == 0 {
+		thresholds = DefaultBalanceThresholds
+	}
+
+	// Raw size of each chunk, from its device extents, so every profile is
+	// counted right
+	raw := make(map[uint64]uint64, len(fm.Chunks))
+	var deviceSize, allocated uint64
+	for _, dev := range fm.Devices {
+		deviceSize += dev.TotalSize
+	}
+	for _, extents := range fm.DeviceExtents {
+		for _, ext := range extents {
+			raw[ext.ChunkOffset] += ext.Length
+			allocated += ext.Length
+		}
+	}
+
+	plan := &BalancePlan{
+		Type:        opts.Type,
+		Goal:        opts.GoalUnallocated,
+		Recommended: -1,
+	}
+	if deviceSize > allocated {
+		plan.Unallocated = deviceSize - allocated
+	}
+	if plan.Goal == 0 {
+		plan.Goal = deviceSize / 10
+	}
+
+	var chunks []Chunk
+	sizes := make(map[uint64]int)
+	for _, c := range fm.Chunks {
+		if c.Type&opts.Type == 0 {
+			continue
+		}
+		chunks = append(chunks, c)
+		plan.Chunks++
+		plan.Length += c.Length
+		plan.Used += c.Used
+		sizes[c.Length]++
+	}
+	// New chunks are usually the size most existing ones have
+	for size, n := range sizes {
+		if n > sizes[plan.ChunkSize] || (n == sizes[plan.ChunkSize] && size > plan.ChunkSize) {
+			plan.ChunkSize = size
+		}
+	}
+
+	for _, usage := range thresholds {
+		run := BalanceRun{Usage: usage}
+		var freedRaw, slack uint64
+		for _, c := range chunks {
+			if !balanceUsageMatches(c, usage) {
+				slack += c.Length - min(c.Used, c.Length)
+				continue
+			}
+			run.Chunks++
+			run.ChunkBytes += c.Length
+			run.MovedBytes += c.Used
+			freedRaw += raw[c.LogicalOffset]
+		}
+
+		if run.MovedBytes > slack && plan.ChunkSize > 0 {
+			overflow := run.MovedBytes - slack
+			run.NewChunks = int((overflow + plan.ChunkSize - 1) / plan.ChunkSize)
+		}
+		// New chunks take the same raw to logical ratio as the ones freed
+		var newRaw uint64
+		if run.ChunkBytes > 0 {
+			ratio := float64(freedRaw) / float64(run.ChunkBytes)
+			newRaw = uint64(math.Round(float64(uint64(run.NewChunks)*plan.ChunkSize) * ratio))
+		}
+		if freedRaw > newRaw {
+			run.FreedBytes = freedRaw - newRaw
+		}
+		run.UnallocatedAfter = plan.Unallocated + run.FreedBytes
+		run.ReachesGoal = run.UnallocatedAfter >= plan.Goal
+		plan.Runs = append(plan.Runs, run)
+	}
+
+	plan.Recommended = recommendBalanceRun(plan)
+	return plan
+}
+
+// balanceUsageMatches mirrors the kernel's chunk_usage_filter
+func balanceUsageMatches(c Chunk, usage int) bool {
+	thresh := c.Length
+	if usage <= 0 {
+		thresh = 1
+	} else if usage < 100 {
+		thresh = c.Length * uint64(usage) / 100
+	}
+	return c.Used < thresh
+}
+
+func recommendBalanceRun(plan *BalancePlan) int {
+	if plan.Unallocated >= plan.Goal {
+		return -1
+	}
+
+	idx := make([]int, 0, len(plan.Runs))
+	for i, r := range plan.Runs {
+		if r.Chunks > 0 && r.FreedBytes > 0 {
+			idx = append(idx, i)
+		}
+	}
+	if len(idx) == 0 {
+		return -1
+	}
+
+	// Cheapest first: fewest bytes moved, then fewest chunks
+	sort.SliceStable(idx, func(a, b int) bool {
+		ra, rb := plan.Runs[idx[a]], plan.Runs[idx[b]]
+		if ra.MovedBytes != rb.MovedBytes {
+			return ra.MovedBytes < rb.MovedBytes
+		}
+		return ra.Chunks < rb.Chunks
+	})
+	for _, i := range idx {
+		if plan.Runs[i].ReachesGoal {
+			return i
+		}
+	}
+
+	best := idx[0]
+	for _, i := range idx {
+		if plan.Runs[i].FreedBytes > plan.Runs[best].FreedBytes {
+			best = i
+		}
+	}
+	return best
+}
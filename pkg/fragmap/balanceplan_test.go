@@ -0,0 +1,203 @@
+package fragmap
+
+import "testing"
+
+const gib = 1 << 30
+
+// planChunk is a chunk for a test FragMap: length and used bytes, and how
+// many devices it is mirrored over
+type planChunk struct {
+	typ          BlockType
+	length, used uint64
+	copies       int
+}
+
+// planFragMap lays chunks out back to back on devices of size each
+func planFragMap(devices int, size uint64, chunks []planChunk) *FragMap {
+	fm := &FragMap{DeviceExtents: map[uint64][]DeviceExtent{}}
+	for i := range devices {
+		fm.Devices = append(fm.Devices, Device{ID: uint64(i + 1), TotalSize: size})
+	}
+	next := make([]uint64, devices+1)
+	for i, pc := range chunks {
+		logical := uint64(i+1) * 16 * gib
+		fm.Chunks = append(fm.Chunks, Chunk{LogicalOffset: logical, Length: pc.length, Type: pc.typ, Used: pc.used})
+		for dev := 1; dev <= max(pc.copies, 1); dev++ {
+			id := uint64(dev)
+			fm.DeviceExtents[id] = append(fm.DeviceExtents[id], DeviceExtent{DeviceID: id, PhysicalOffset: next[dev], Length: pc.length, ChunkOffset: logical})
+			next[dev] += pc.length
+		}
+	}
+	return fm
+}
+
+func repeatChunk(n int, pc planChunk) []planChunk {
+	chunks := make([]planChunk, n)
+	for i := range chunks {
+		chunks[i] = pc
+	}
+	return chunks
+}
+
+func TestPlanBalance(t *testing.T) {
+	low := planChunk{typ: BlockTypeData, length: gib, used: 50 << 20}
+	high := planChunk{typ: BlockTypeData, length: gib, used: gib * 9 / 10}
+	full := append(repeatChunk(5, low), repeatChunk(5, high)...)
+
+	tests := []struct {
+		name    string
+		fm      *FragMap
+		opts    BalancePlanOptions
+		chunks  int
+		unalloc uint64
+		goal    uint64
+		// Recommended threshold, 0 for none
+		recommended int
+		// usage= filter to check in detail
+		usage        int
+		runChunks    int
+		moved, freed uint64
+		newChunks    int
+		reachesGoal  bool
+	}{
+		{
+			name: "empty",
+			fm:   planFragMap(0, 0, nil),
+		},
+		{
+			name:    "one chunk, goal already met",
+			fm:      planFragMap(1, 10*gib, []planChunk{low}),
+			chunks:  1,
+			unalloc: 9 * gib,
+			goal:    gib,
+			// Its data needs a new chunk, so nothing is freed
+			usage: 5, runChunks: 1, moved: 50 << 20, newChunks: 1, reachesGoal: true,
+		},
+		{
+			// The nearly empty chunks fit into the slack of the others,
+			// so usage=5 frees them without new chunks
+			name:        "full",
+			fm:          planFragMap(1, 10*gib, full),
+			chunks:      10,
+			goal:        gib,
+			recommended: 5,
+			usage:       5, runChunks: 5, moved: 5 * (50 << 20), freed: 5 * gib, reachesGoal: true,
+		},
+		{
+			// Relocating everything needs new chunks for what doesn't fit
+			name:        "full, usage=100",
+			fm:          planFragMap(1, 10*gib, full),
+			chunks:      10,
+			goal:        gib,
+			recommended: 5,
+			usage:       100, runChunks: 10, moved: 5*(50<<20) + 5*(gib*9/10), freed: 5 * gib, newChunks: 5, reachesGoal: true,
+		},
+		{
+			name:        "goal out of reach",
+			fm:          planFragMap(1, 10*gib, full),
+			opts:        BalancePlanOptions{GoalUnallocated: 100 * gib},
+			chunks:      10,
+			goal:        100 * gib,
+			recommended: 5,
+			usage:       5, runChunks: 5, moved: 5 * (50 << 20), freed: 5 * gib,
+		},
+		{
+			name:   "nothing under the threshold",
+			fm:     planFragMap(1, 10*gib, repeatChunk(10, high)),
+			opts:   BalancePlanOptions{Thresholds: []int{5, 50}},
+			chunks: 10,
+			goal:   gib,
+			usage:  50,
+		},
+		{
+			name:        "raid1 frees both copies",
+			fm:          planFragMap(2, 5*gib, append(repeatChunk(2, planChunk{typ: BlockTypeData, length: gib, used: 0, copies: 2}), repeatChunk(3, planChunk{typ: BlockTypeData, length: gib, used: gib / 2, copies: 2})...)),
+			chunks:      5,
+			goal:        gib,
+			recommended: 1,
+			usage:       1, runChunks: 2, freed: 4 * gib, reachesGoal: true,
+		},
+		{
+			name:   "metadata is left alone",
+			fm:     planFragMap(1, 10*gib, append(repeatChunk(10, planChunk{typ: BlockTypeMetadata, length: gib, used: 0}), high)),
+			chunks: 1,
+			goal:   gib,
+			usage:  100, runChunks: 1, moved: gib * 9 / 10, newChunks: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := PlanBalance(tt.fm, tt.opts)
+			if plan.Type != BlockTypeData {
+				t.Errorf("Type = %v, want data", plan.Type)
+			}
+			if plan.Chunks != tt.chunks || plan.Unallocated != tt.unalloc || plan.Goal != tt.goal {
+				t.Errorf("chunks %d, unallocated %d, goal %d, want %d, %d, %d", plan.Chunks, plan.Unallocated, plan.Goal, tt.chunks, tt.unalloc, tt.goal)
+			}
+
+			thresholds := tt.opts.Thresholds
+			if len(thresholds) == 0 {
+				thresholds = DefaultBalanceThresholds
+			}
+			if len(plan.Runs) != len(thresholds) {
+				t.Fatalf("got %d runs, want %d", len(plan.Runs), len(thresholds))
+			}
+
+			if tt.recommended == 0 {
+				if plan.Recommended != -1 {
+					t.Errorf("recommended usage=%d, want none", plan.Runs[plan.Recommended].Usage)
+				}
+			} else if plan.Recommended < 0 {
+				t.Errorf("recommended none, want usage=%d", tt.recommended)
+			} else if got := plan.Runs[plan.Recommended].Usage; got != tt.recommended {
+				t.Errorf("recommended usage=%d, want usage=%d", got, tt.recommended)
+			}
+
+			if tt.usage == 0 {
+				return
+			}
+			var run *BalanceRun
+			for i := range plan.Runs {
+				if plan.Runs[i].Usage == tt.usage {
+					run = &plan.Runs[i]
+				}
+			}
+			if run == nil {
+				t.Fatalf("no usage=%d run", tt.usage)
+			}
+			if run.Chunks != tt.runChunks || run.MovedBytes != tt.moved || run.FreedBytes != tt.freed || run.NewChunks != tt.newChunks {
+				t.Errorf("usage=%d: %d chunks, moved %d, freed %d, %d new chunks, want %d, %d, %d, %d",
+					tt.usage, run.Chunks, run.MovedBytes, run.FreedBytes, run.NewChunks, tt.runChunks, tt.moved, tt.freed, tt.newChunks)
+			}
+			if run.UnallocatedAfter != plan.Unallocated+run.FreedBytes {
+				t.Errorf("usage=%d: unallocated after %d, want %d", tt.usage, run.UnallocatedAfter, plan.Unallocated+run.FreedBytes)
+			}
+			if run.ReachesGoal != tt.reachesGoal {
+				t.Errorf("usage=%d: reaches goal %v, want %v", tt.usage, run.ReachesGoal, tt.reachesGoal)
+			}
+		})
+	}
+}
+
+func TestBalanceUsageMatches(t *testing.T) {
+	tests := []struct {
+		used  uint64
+		usage int
+		want  bool
+	}{
+		{0, 0, true},
+		{1, 0, false},
+		{0, 1, true},
+		{gib/2 - 1, 50, true},
+		{gib / 2, 50, false},
+		{gib - 1, 100, true},
+		{gib, 100, false},
+		{gib + 1, 100, false},
+	}
+	for _, tt := range tests {
+		if got := balanceUsageMatches(Chunk{Length: gib, Used: tt.used}, tt.usage); got != tt.want {
+			t.Errorf("used %d, usage=%d: %v, want %v", tt.used, tt.usage, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package fragmap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/elee1766/gobtr/pkg/privsep"
+)
+
+// unknownGenerationTTL is how long a scan is reused when the kernel can't
+// report the filesystem generation
+const unknownGenerationTTL = 5 * time.Second
+
+// Cache keeps the latest scan of each filesystem, keyed by UUID, and only
+// rescans once the filesystem generation has moved. Returned maps are shared
+// and must not be modified.
+type Cache struct {
+	backend privsep.Backend
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	onScan  func(fm *FragMap)
+}
+
+type cacheEntry struct {
+	mu sync.Mutex // Held while scanning, so concurrent callers share one scan
+	fm *FragMap
+}
+
+// NewCache returns an empty Cache that scans through b. onScan, if set, is
+// called with every new scan before it is returned.
+func NewCache(b privsep.Backend, onScan func(fm *FragMap)) *Cache {
+	return &Cache{
+		backend: b,
+		entries: make(map[string]*cacheEntry),
+		onScan:  onScan,
+	}
+}
+
+// Get returns the fragmentation map of the filesystem at fsPath, scanning it
+// if the cached scan is stale. fresh reports whether a new scan was taken.
+func (c *Cache) Get(fsPath string) (fm *FragMap, fresh bool, err error) {
+	f, err := os.OpenFile(fsPath, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, false, fmt.Errorf("open filesystem: %w", err)
+	}
+	fsUUID, gen, err := FSInfo(privsep.Direct{}, f)
+	f.Close()
+	if err != nil {
+		return nil, false, err
+	}
+
+	c.mu.Lock()
+	e := c.entries[fsUUID]
+	if e == nil {
+		e = &cacheEntry{}
+		c.entries[fsUUID] = e
+	}
+	c.mu.Unlock()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.fm != nil {
+		if gen != 0 && e.fm.Generation == gen {
+			return e.fm, false, nil
+		}
+		if gen == 0 && time.Since(e.fm.ScannedAt) < unknownGenerationTTL {
+			return e.fm, false, nil
+		}
+	}
+
+	scanner, err := NewScanner(c.backend, fsPath)
+	if err != nil {
+		return nil, false, err
+	}
+	defer scanner.Close()
+
+	fm, err = scanner.Scan()
+	if err != nil {
+		return nil, false, err
+	}
+	slog.Debug("fragmap cache miss", "uuid", fsUUID, "generation", fm.Generation)
+	e.fm = fm
+	if c.onScan != nil {
+		c.onScan(fm)
+	}
+	return fm, true, nil
+}
+
+// LayoutHash hashes the chunk layout (addresses, types, profiles and
+// stripes), ignoring how full the chunks are
+func (fm *FragMap) LayoutHash() uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	put := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		h.Write(buf[:])
+	}
+	for _, c := range fm.Chunks {
+		put(c.LogicalOffset)
+		put(c.Length)
+		put(uint64(c.Type))
+		put(uint64(c.Profile))
+		for _, s := range c.Stripes {
+			put(s.DeviceID)
+			put(s.Offset)
+		}
+	}
+	return h.Sum64()
+}
+
+// EncodeFragMap serializes a scan for storage as gzipped JSON
+func EncodeFragMap(fm *FragMap) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(fm); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeFragMap reads a scan written by EncodeFragMap
+func DecodeFragMap(data []byte) (*FragMap, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var fm FragMap
+	if err := json.NewDecoder(zr).Decode(&fm); err != nil {
+		return nil, err
+	}
+	return &fm, nil
+}
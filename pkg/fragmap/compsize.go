@@ -0,0 +1,104 @@
+package fragmap
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/elee1766/gobtr/pkg/privsep"
+)
+
+// CompsizeOptions controls a compression report walk
+type CompsizeOptions struct {
+	// Don't descend into other filesystems or subvolumes, which on btrfs
+	// have their own device numbers
+	OneFileSystem bool
+
+	// Opens files and reads extent items (nil = in process)
+	Backend privsep.Backend
+}
+
+// CompsizeReport is the compression usage of every file under a path, with
+// extents shared between files (reflinks, snapshots) counted once
+type CompsizeReport struct {
+	Path    string
+	Files   int // Regular files read
+	Errors  int // Files that could not be read
+	Usage   *ExtentUsage
+	Elapsed time.Duration
+}
+
+// Compsize walks root and sums the extent items of every regular file, like
+// the compsize tool. It needs CAP_SYS_ADMIN, or a helper as opts.Backend,
+// to read extent items.
+func Compsize(ctx context.Context, root string, opts CompsizeOptions) (*CompsizeReport, error) {
+	start := time.Now()
+	if opts.Backend == nil {
+		opts.Backend = privsep.Direct{}
+	}
+
+	var rootDev uint64
+	if opts.OneFileSystem {
+		dev, err := deviceOf(root)
+		if err != nil {
+			return nil, err
+		}
+		rootDev = dev
+	}
+
+	report := &CompsizeReport{
+		Path:  root,
+		Usage: NewExtentUsage(),
+	}
+	seen := make(map[uint64]struct{})
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err != nil {
+			report.Errors++
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			if opts.OneFileSystem && path != root {
+				if dev, err := deviceOf(path); err == nil && dev != rootDev {
+					return fs.SkipDir
+				}
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		f, err := opts.Backend.Open(path)
+		if err != nil {
+			report.Errors++
+			return nil
+		}
+		items, err := GetFileExtentItems(opts.Backend, f)
+		f.Close()
+		if err != nil {
+			report.Errors++
+			return nil
+		}
+
+		report.Usage.Add(items, seen)
+		report.Files++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Elapsed = time.Since(start)
+	slog.Debug("compsize walk", "path", root, "files", report.Files, "errors", report.Errors, "duration", report.Elapsed)
+	return report, nil
+}
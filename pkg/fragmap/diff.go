@@ -0,0 +1,130 @@
+package fragmap
+
+import (
+	"slices"
+	"sort"
+)
+
+// ChunkChangeKind is how a chunk differs between two scans
+type ChunkChangeKind int
+
+const (
+	ChunkAllocated ChunkChangeKind = iota + 1 // Only in the newer scan
+	ChunkFreed                                // Only in the older scan
+	ChunkRelocated                            // Same logical address, stripes moved (device replace/remove)
+)
+
+// String returns a lowercase name for the change kind
+func (k ChunkChangeKind) String() string {
+	switch k {
+	case ChunkAllocated:
+		return "allocated"
+	case ChunkFreed:
+		return "freed"
+	case ChunkRelocated:
+		return "relocated"
+	default:
+		return "unknown"
+	}
+}
+
+// ChunkChange is one chunk that differs between two scans
+type ChunkChange struct {
+	Kind ChunkChangeKind
+	// The chunk as in the newer scan (the older one for freed chunks)
+	Chunk Chunk
+	// Stripes in the older scan, for relocated chunks
+	PreviousStripes []Stripe
+	// Used bytes in the older scan (0 for allocated chunks)
+	PreviousUsed uint64
+}
+
+// TypeDiff sums the changes of one block group type
+type TypeDiff struct {
+	Type            BlockType
+	AllocatedChunks int
+	AllocatedBytes  uint64
+	FreedChunks     int
+	FreedBytes      uint64
+	RelocatedChunks int
+	RelocatedBytes  uint64
+	// Chunks in both scans whose used bytes changed
+	ResizedChunks int
+	UsedBefore    uint64 // Over all chunks of this type
+	UsedAfter     uint64
+}
+
+// FragMapDiff is the difference between two scans of one filesystem. Balance
+// moves chunks to new logical addresses, so balanced chunks show up as freed
+// and allocated rather than relocated.
+type FragMapDiff struct {
+	Changes []ChunkChange // Sorted by logical address
+	ByType  []TypeDiff    // Data, metadata, system
+}
+
+// DiffFragMaps compares an older scan with a newer one. Chunks are matched by
+// logical address.
+func DiffFragMaps(from, to *FragMap) *FragMapDiff {
+	old := make(map[uint64]*Chunk, len(from.Chunks))
+	for i := range from.Chunks {
+		old[from.Chunks[i].LogicalOffset] = &from.Chunks[i]
+	}
+
+	types := []BlockType{BlockTypeData, BlockTypeMetadata, BlockTypeSystem}
+	byType := make([]TypeDiff, len(types))
+	for i, t := range types {
+		byType[i].Type = t
+	}
+	sum := func(t BlockType) *TypeDiff {
+		for i := range byType {
+			if t&byType[i].Type != 0 {
+				return &byType[i]
+			}
+		}
+		return &TypeDiff{}
+	}
+
+	diff := &FragMapDiff{}
+	seen := make(map[uint64]struct{}, len(to.Chunks))
+	for _, c := range to.Chunks {
+		seen[c.LogicalOffset] = struct{}{}
+		td := sum(c.Type)
+		td.UsedAfter += c.Used
+
+		prev, ok := old[c.LogicalOffset]
+		switch {
+		case !ok:
+			diff.Changes = append(diff.Changes, ChunkChange{Kind: ChunkAllocated, Chunk: c})
+			td.AllocatedChunks++
+			td.AllocatedBytes += c.Length
+		case !slices.Equal(prev.Stripes, c.Stripes):
+			diff.Changes = append(diff.Changes, ChunkChange{
+				Kind:            ChunkRelocated,
+				Chunk:           c,
+				PreviousStripes: prev.Stripes,
+				PreviousUsed:    prev.Used,
+			})
+			td.RelocatedChunks++
+			td.RelocatedBytes += c.Length
+		case prev.Used != c.Used:
+			td.ResizedChunks++
+		}
+	}
+
+	for _, c := range from.Chunks {
+		td := sum(c.Type)
+		td.UsedBefore += c.Used
+		if _, ok := seen[c.LogicalOffset]; ok {
+			continue
+		}
+		diff.Changes = append(diff.Changes, ChunkChange{Kind: ChunkFreed, Chunk: c, PreviousUsed: c.Used})
+		td.FreedChunks++
+		td.FreedBytes += c.Length
+	}
+
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		return diff.Changes[i].Chunk.LogicalOffset < diff.Changes[j].Chunk.LogicalOffset
+	})
+	diff.ByType = byType
+	return diff
+}
@@ -0,0 +1,506 @@
+package fragmap
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/elee1766/gobtr/pkg/privsep"
+)
+
+// maxScanErrorSamples is how many file errors a directory scan keeps
+const maxScanErrorSamples = 100
+
+// checkpointVersion is bumped when the checkpoint format changes
+const checkpointVersion = 1
+
+// DirScanOptions controls a directory fragmentation scan
+type DirScanOptions struct {
+	Workers  int // Files analyzed in parallel (0 = number of CPUs)
+	MaxDepth int // 0 = unlimited, 1 = only files directly in the root
+
+	// Skip files and directories matching any of these globs. A pattern
+	// matches against the base name and the path relative to the root.
+	Exclude []string
+
+	// Don't descend into other filesystems or subvolumes, which on btrfs
+	// have their own device numbers
+	OneFileSystem bool
+
+	Top int // Most fragmented files to keep (0 = 20)
+
+	// Save progress to this file and resume from it if it exists. It is
+	// removed once the scan completes.
+	Checkpoint         string
+	CheckpointInterval time.Duration // 0 = 30s
+
+	// Called with progress at most once per ProgressInterval (0 = 1s)
+	Progress         func(DirScanProgress)
+	ProgressInterval time.Duration
+
+	// Opens files and reads extent items (nil = in process)
+	Backend privsep.Backend
+}
+
+// DirScanProgress is reported while a directory scan runs
+type DirScanProgress struct {
+	FilesScanned int
+	BytesScanned int64
+	Errors       int
+	CurrentPath  string
+	Elapsed      time.Duration
+	Resumed      bool // Started from a checkpoint
+}
+
+// DirScanError is a file that couldn't be analyzed
+type DirScanError struct {
+	Path string
+	Err  string
+}
+
+// DirScanResult is the outcome of a directory scan
+type DirScanResult struct {
+	Root  string
+	Stats *AggregateFragStats
+
+	// Most fragmented files (DoF > 1), worst first, without their extent lists
+	Top []*FileFragInfo
+
+	Errors       int
+	ErrorSamples []DirScanError // The first maxScanErrorSamples errors
+	Resumed      bool
+	Elapsed      time.Duration // Including time spent before resuming
+}
+
+// dirScanCheckpoint is the saved state of a partial scan. Results are
+// applied in walk order, so every file up to LastPath is accounted for.
+type dirScanCheckpoint struct {
+	Version       int
+	Root          string
+	Exclude       []string
+	OneFileSystem bool
+	MaxDepth      int
+
+	LastPath     string // Relative to Root
+	Acc          *FragStatsAccumulator
+	Top          []*FileFragInfo
+	Errors       int
+	ErrorSamples []DirScanError
+	Elapsed      time.Duration
+}
+
+type scanJob struct {
+	seq  int
+	path string
+	rel  string
+}
+
+type scanResult struct {
+	scanJob
+	info *FileFragInfo
+	err  error
+
+	// Never analyzed because the scan was cancelled. Nothing from here on
+	// is applied, so the checkpoint stays a prefix of the walk.
+	abandoned bool
+}
+
+// ScanDirectory analyzes the fragmentation of every regular file under root.
+// Files are analyzed by a pool of workers and aggregated as they finish, so
+// memory use doesn't grow with the number of files. When ctx is cancelled the
+// checkpoint (if any) is saved and ctx's error is returned.
+func ScanDirectory(ctx context.Context, root string, opts DirScanOptions) (*DirScanResult, error) {
+	start := time.Now()
+
+	root = filepath.Clean(root)
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.Top <= 0 {
+		opts.Top = 20
+	}
+	if opts.CheckpointInterval <= 0 {
+		opts.CheckpointInterval = 30 * time.Second
+	}
+	if opts.ProgressInterval <= 0 {
+		opts.ProgressInterval = time.Second
+	}
+	if opts.Backend == nil {
+		opts.Backend = privsep.Direct{}
+	}
+	for _, pattern := range opts.Exclude {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("bad exclude pattern %q: %w", pattern, err)
+		}
+	}
+
+	var rootDev uint64
+	if opts.OneFileSystem {
+		dev, err := deviceOf(root)
+		if err != nil {
+			return nil, err
+		}
+		rootDev = dev
+	}
+
+	cp := &dirScanCheckpoint{
+		Version:       checkpointVersion,
+		Root:          root,
+		Exclude:       opts.Exclude,
+		OneFileSystem: opts.OneFileSystem,
+		MaxDepth:      opts.MaxDepth,
+		Acc:           NewFragStatsAccumulator(),
+	}
+	resumed := false
+	if opts.Checkpoint != "" {
+		saved, err := loadCheckpoint(opts.Checkpoint)
+		if err != nil {
+			return nil, err
+		}
+		if saved != nil {
+			if saved.Root != cp.Root || !slices.Equal(saved.Exclude, cp.Exclude) ||
+				saved.OneFileSystem != cp.OneFileSystem || saved.MaxDepth != cp.MaxDepth {
+				return nil, fmt.Errorf("checkpoint %s is for a different scan (root %s)", opts.Checkpoint, saved.Root)
+			}
+			cp = saved
+			resumed = true
+		}
+	}
+	prevElapsed := cp.Elapsed
+
+	top := &fragHeap{files: cp.Top}
+	heap.Init(top)
+
+	excluded := func(name, rel string) bool {
+		for _, pattern := range opts.Exclude {
+			if ok, _ := filepath.Match(pattern, name); ok {
+				return true
+			}
+			if ok, _ := filepath.Match(pattern, rel); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	// The window bounds how far workers can run ahead of the oldest
+	// unfinished file, which bounds the results buffered for reordering
+	window := make(chan struct{}, opts.Workers*64)
+	jobs := make(chan scanJob, opts.Workers)
+	results := make(chan scanResult, opts.Workers)
+
+	walkCtx, cancelWalk := context.WithCancel(ctx)
+	defer cancelWalk()
+
+	// enqueue hands a file to the workers, or an error straight to the
+	// aggregator, in walk order
+	seq := 0
+	enqueue := func(job scanJob, err error) error {
+		select {
+		case window <- struct{}{}:
+		case <-walkCtx.Done():
+			return walkCtx.Err()
+		}
+		job.seq = seq
+		seq++
+		if err != nil {
+			results <- scanResult{scanJob: job, err: err}
+			return nil
+		}
+		select {
+		case jobs <- job:
+			return nil
+		case <-walkCtx.Done():
+			// Hand the job back so the aggregator doesn't wait for it
+			results <- scanResult{scanJob: job, abandoned: true}
+			return walkCtx.Err()
+		}
+	}
+
+	// The aggregator moves cp.LastPath on while the walker runs
+	resumeAfter := cp.LastPath
+
+	var walkErr error
+	go func() {
+		defer close(jobs)
+		walkErr = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err := walkCtx.Err(); err != nil {
+				return err
+			}
+			if path == root {
+				return err
+			}
+			rel, _ := filepath.Rel(root, path)
+
+			// Already accounted for by the checkpoint
+			done := resumeAfter != "" && !walkBefore(resumeAfter, rel)
+
+			if err != nil {
+				// Unreadable directory (WalkDir has already been called
+				// for it without an error) or a file that went away
+				if !done {
+					if err := enqueue(scanJob{path: path, rel: rel}, err); err != nil {
+						return err
+					}
+				}
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+
+			if d.IsDir() {
+				if resumeAfter != "" && walkBefore(rel, resumeAfter) && !isAncestor(rel, resumeAfter) {
+					return fs.SkipDir
+				}
+				depth := strings.Count(rel, string(filepath.Separator)) + 1
+				if excluded(d.Name(), rel) || (opts.MaxDepth > 0 && depth >= opts.MaxDepth) {
+					return fs.SkipDir
+				}
+				if opts.OneFileSystem {
+					if dev, err := deviceOf(path); err == nil && dev != rootDev {
+						return fs.SkipDir
+					}
+				}
+				return nil
+			}
+
+			if done || !d.Type().IsRegular() || excluded(d.Name(), rel) {
+				return nil
+			}
+			return enqueue(scanJob{path: path, rel: rel}, nil)
+		})
+	}()
+
+	var wg sync.WaitGroup
+	for range opts.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				info, err := AnalyzeFileFragmentation(opts.Backend, job.path)
+				results <- scanResult{scanJob: job, info: info, err: err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		// Errors for unreadable directories are sent by the walker, which
+		// has returned once jobs is closed and every worker is done
+		close(results)
+	}()
+
+	report := func() {
+		if opts.Progress == nil {
+			return
+		}
+		opts.Progress(DirScanProgress{
+			FilesScanned: cp.Acc.Totals.TotalFiles + cp.Errors,
+			BytesScanned: cp.Acc.Totals.TotalBytes,
+			Errors:       cp.Errors,
+			CurrentPath:  filepath.Join(root, cp.LastPath),
+			Elapsed:      prevElapsed + time.Since(start),
+			Resumed:      resumed,
+		})
+	}
+	save := func() error {
+		if opts.Checkpoint == "" {
+			return nil
+		}
+		cp.Top = top.files
+		cp.Elapsed = prevElapsed + time.Since(start)
+		return saveCheckpoint(opts.Checkpoint, cp)
+	}
+
+	pending := make(map[int]scanResult)
+	next := 0
+	lastProgress, lastSave := time.Now(), time.Now()
+	var saveErr error
+	stopped := false
+
+	for r := range results {
+		pending[r.seq] = r
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			<-window
+
+			if r.abandoned || stopped {
+				stopped = true
+				continue
+			}
+			if r.err != nil {
+				cp.Errors++
+				if len(cp.ErrorSamples) < maxScanErrorSamples {
+					cp.ErrorSamples = append(cp.ErrorSamples, DirScanError{Path: r.path, Err: r.err.Error()})
+				}
+			} else {
+				cp.Acc.Add(r.info)
+				if r.info.DoF > 1.0 {
+					top.offer(r.info, opts.Top)
+				}
+			}
+			cp.LastPath = r.rel
+		}
+
+		if time.Since(lastProgress) >= opts.ProgressInterval {
+			report()
+			lastProgress = time.Now()
+		}
+		if time.Since(lastSave) >= opts.CheckpointInterval {
+			if err := save(); err != nil && saveErr == nil {
+				saveErr = err
+				cancelWalk()
+			}
+			lastSave = time.Now()
+		}
+	}
+
+	if saveErr != nil {
+		return nil, fmt.Errorf("save checkpoint: %w", saveErr)
+	}
+	if ctx.Err() != nil {
+		if err := save(); err != nil {
+			return nil, fmt.Errorf("save checkpoint: %w", err)
+		}
+		return nil, ctx.Err()
+	}
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	if opts.Checkpoint != "" {
+		if err := os.Remove(opts.Checkpoint); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("remove checkpoint: %w", err)
+		}
+	}
+	report()
+
+	files := top.files
+	slices.SortFunc(files, func(a, b *FileFragInfo) int {
+		return cmpDoF(b, a)
+	})
+	return &DirScanResult{
+		Root:         root,
+		Stats:        cp.Acc.Stats(),
+		Top:          files,
+		Errors:       cp.Errors,
+		ErrorSamples: cp.ErrorSamples,
+		Resumed:      resumed,
+		Elapsed:      prevElapsed + time.Since(start),
+	}, nil
+}
+
+// walkBefore reports whether rel a is visited before rel b by WalkDir,
+// which walks each directory's entries in name order
+func walkBefore(a, b string) bool {
+	as := strings.Split(a, string(filepath.Separator))
+	bs := strings.Split(b, string(filepath.Separator))
+	return slices.Compare(as, bs) < 0
+}
+
+// isAncestor reports whether directory dir contains path
+func isAncestor(dir, path string) bool {
+	return strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// deviceOf returns the device number of path without following symlinks
+func deviceOf(path string) (uint64, error) {
+	var st syscall.Stat_t
+	if err := syscall.Lstat(path, &st); err != nil {
+		return 0, err
+	}
+	return st.Dev, nil
+}
+
+func loadCheckpoint(path string) (*dirScanCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoint: %w", err)
+	}
+	var cp dirScanCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("parse checkpoint %s: %w", path, err)
+	}
+	if cp.Version != checkpointVersion {
+		return nil, fmt.Errorf("checkpoint %s has unsupported version %d", path, cp.Version)
+	}
+	if cp.Acc == nil {
+		cp.Acc = NewFragStatsAccumulator()
+	}
+	return &cp, nil
+}
+
+// saveCheckpoint writes the checkpoint atomically
+func saveCheckpoint(path string, cp *dirScanCheckpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// cmpDoF orders files by DoF, then extent count
+func cmpDoF(a, b *FileFragInfo) int {
+	if a.DoF != b.DoF {
+		if a.DoF < b.DoF {
+			return -1
+		}
+		return 1
+	}
+	return a.ExtentCount - b.ExtentCount
+}
+
+// fragHeap is a min-heap of the most fragmented files seen so far
+type fragHeap struct {
+	files []*FileFragInfo
+}
+
+func (h *fragHeap) Len() int           { return len(h.files) }
+func (h *fragHeap) Less(i, j int) bool { return cmpDoF(h.files[i], h.files[j]) < 0 }
+func (h *fragHeap) Swap(i, j int)      { h.files[i], h.files[j] = h.files[j], h.files[i] }
+func (h *fragHeap) Push(x any)         { h.files = append(h.files, x.(*FileFragInfo)) }
+func (h *fragHeap) Pop() any {
+	old := h.files
+	f := old[len(old)-1]
+	h.files = old[:len(old)-1]
+	return f
+}
+
+// offer keeps f if it is among the n most fragmented files. The extent list
+// is dropped to keep memory bounded.
+func (h *fragHeap) offer(f *FileFragInfo, n int) {
+	if h.Len() >= n {
+		if cmpDoF(f, h.files[0]) <= 0 {
+			return
+		}
+		heap.Pop(h)
+	}
+	kept := *f
+	kept.Extents = nil
+	heap.Push(h, &kept)
+}
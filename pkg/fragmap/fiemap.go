@@ -2,10 +2,12 @@ package fragmap
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"sort"
-	"syscall"
 	"unsafe"
+
+	"github.com/elee1766/gobtr/pkg/privsep"
 )
 
 // FIEMAP ioctl constants
@@ -57,6 +59,8 @@ type FileExtent struct {
 	IsShared       bool // FIEMAP_EXTENT_SHARED
 	IsInline       bool // FIEMAP_EXTENT_DATA_INLINE
 	IsDelalloc     bool // FIEMAP_EXTENT_DELALLOC (not yet written)
+	IsCompressed   bool // FIEMAP_EXTENT_ENCODED
+	IsUnwritten    bool // FIEMAP_EXTENT_UNWRITTEN (preallocated)
 }
 
 // FileFragInfo contains fragmentation information for a single file
@@ -74,6 +78,19 @@ type FileFragInfo struct {
 	FragmentationPoints   int     // Number of discontinuities
 	IdealExtents          int     // Minimum extents needed for contiguous storage
 	ContiguousExtentBytes int64   // Total bytes in physically contiguous runs
+
+	// Extent kinds
+	CompressedExtents int
+	InlineExtents     int
+	SharedExtents     int   // Reflinked or snapshotted
+	UnwrittenExtents  int   // Preallocated
+	CompressedBytes   int64 // File bytes stored in compressed extents
+	SharedBytes       int64
+
+	// On-disk usage from the file's extent items. Only set when
+	// HasExtentItems is true, since reading them needs CAP_SYS_ADMIN.
+	HasExtentItems bool
+	Usage          *ExtentUsage
 }
 
 // GetFileExtents retrieves all extents for a file using FIEMAP
@@ -84,6 +101,10 @@ func GetFileExtents(path string) ([]FileExtent, int64, error) {
 	}
 	defer f.Close()
 
+	return getFileExtents(privsep.Direct{}, f)
+}
+
+func getFileExtents(b privsep.Backend, f *os.File) ([]FileExtent, int64, error) {
 	// Get file size
 	stat, err := f.Stat()
 	if err != nil {
@@ -113,9 +134,8 @@ func GetFileExtents(path string) ([]FileExtent, int64, error) {
 		fm.ExtentCount = maxExtents
 
 		// Call ioctl
-		_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), FS_IOC_FIEMAP, uintptr(unsafe.Pointer(fm)))
-		if errno != 0 {
-			return nil, fileSize, fmt.Errorf("FIEMAP ioctl failed: %w", errno)
+		if err := b.Fiemap(f, buf); err != nil {
+			return nil, fileSize, fmt.Errorf("FIEMAP ioctl failed: %w", err)
 		}
 
 		if fm.MappedExtents == 0 {
@@ -134,6 +154,8 @@ func GetFileExtents(path string) ([]FileExtent, int64, error) {
 				IsShared:       ext.Flags&FIEMAP_EXTENT_SHARED != 0,
 				IsInline:       ext.Flags&FIEMAP_EXTENT_DATA_INLINE != 0,
 				IsDelalloc:     ext.Flags&FIEMAP_EXTENT_DELALLOC != 0,
+				IsCompressed:   ext.Flags&FIEMAP_EXTENT_ENCODED != 0,
+				IsUnwritten:    ext.Flags&FIEMAP_EXTENT_UNWRITTEN != 0,
 			})
 
 			// Check if this is the last extent
@@ -154,9 +176,16 @@ func GetFileExtents(path string) ([]FileExtent, int64, error) {
 	return extents, fileSize, nil
 }
 
-// AnalyzeFileFragmentation calculates fragmentation metrics for a file
-func AnalyzeFileFragmentation(path string) (*FileFragInfo, error) {
-	extents, fileSize, err := GetFileExtents(path)
+// AnalyzeFileFragmentation calculates fragmentation metrics for a file,
+// opening it and reading its extent items through b
+func AnalyzeFileFragmentation(b privsep.Backend, path string) (*FileFragInfo, error) {
+	f, err := b.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	extents, fileSize, err := getFileExtents(b, f)
 	if err != nil {
 		return nil, err
 	}
@@ -173,11 +202,42 @@ func AnalyzeFileFragmentation(path string) (*FileFragInfo, error) {
 		return info, nil
 	}
 
-	// Calculate ideal extents
-	// For btrfs, max extent size is 128MB (compressed) or 1GB (uncompressed)
-	// We'll use 128MB as a conservative estimate
-	const maxExtentSize = 128 * 1024 * 1024
-	info.IdealExtents = int((fileSize + maxExtentSize - 1) / maxExtentSize)
+	// On-disk sizes of the extents, keyed by physical start. FIEMAP reports
+	// the uncompressed length, which overstates where a compressed extent ends.
+	diskLen := make(map[uint64]uint64)
+	if items, err := GetFileExtentItems(b, f); err == nil {
+		info.HasExtentItems = true
+		info.Usage = NewExtentUsage()
+		info.Usage.Add(items, make(map[uint64]struct{}))
+		for _, item := range items {
+			if item.Compression != CompressNone && item.DiskBytenr != 0 {
+				diskLen[item.DiskBytenr] = item.DiskNumBytes
+			}
+		}
+	}
+
+	for _, ext := range extents {
+		switch {
+		case ext.IsInline:
+			info.InlineExtents++
+		case ext.IsCompressed:
+			info.CompressedExtents++
+			info.CompressedBytes += int64(ext.Length)
+		}
+		if ext.IsUnwritten {
+			info.UnwrittenExtents++
+		}
+		if ext.IsShared {
+			info.SharedExtents++
+			info.SharedBytes += int64(ext.Length)
+		}
+	}
+
+	// Calculate ideal extents. Uncompressed extents can be up to 128MiB, but
+	// compressed extents hold at most 128KiB each.
+	uncompressedBytes := max(fileSize-info.CompressedBytes, 0)
+	info.IdealExtents = int((info.CompressedBytes+MaxCompressedExtentSize-1)/MaxCompressedExtentSize) +
+		int((uncompressedBytes+MaxExtentSize-1)/MaxExtentSize)
 	if info.IdealExtents < 1 {
 		info.IdealExtents = 1
 	}
@@ -198,8 +258,18 @@ func AnalyzeFileFragmentation(path string) (*FileFragInfo, error) {
 			curr := extents[i]
 
 			// Check if physically contiguous
-			prevPhysEnd := prev.PhysicalOffset + prev.Length
-			isContiguous := curr.PhysicalOffset == prevPhysEnd
+			isContiguous := false
+			switch {
+			case !prev.IsCompressed:
+				isContiguous = curr.PhysicalOffset == prev.PhysicalOffset+prev.Length
+			case diskLen[prev.PhysicalOffset] > 0:
+				isContiguous = curr.PhysicalOffset == prev.PhysicalOffset+diskLen[prev.PhysicalOffset]
+			default:
+				// Without the on-disk size, a compressed extent ends somewhere
+				// within its uncompressed length
+				isContiguous = curr.PhysicalOffset > prev.PhysicalOffset &&
+					curr.PhysicalOffset <= prev.PhysicalOffset+prev.Length
+			}
 
 			if !isContiguous {
 				actualFragPoints++
@@ -246,74 +316,128 @@ type AggregateFragStats struct {
 	MaxDoF           float64 // Maximum DoF seen
 	MaxExtents       int     // Maximum extents in a single file
 
+	// Extent kinds
+	CompressedFiles   int // Files with at least one compressed extent
+	CompressedExtents int
+	InlineExtents     int
+	SharedExtents     int
+	UnwrittenExtents  int
+	CompressedBytes   int64
+	SharedBytes       int64
+
+	// On-disk usage summed over files with extent items. Extents shared
+	// between files are counted once per file.
+	Usage *ExtentUsage
+
 	// Distribution
 	DoFHistogram map[string]int // Buckets: "1", "1-2", "2-5", "5-10", "10+"
 }
 
 // AggregateFileFragmentation aggregates fragmentation stats for multiple files
 func AggregateFileFragmentation(files []*FileFragInfo) *AggregateFragStats {
-	stats := &AggregateFragStats{
-		DoFHistogram: map[string]int{
-			"1":    0,
-			"1-2":  0,
-			"2-5":  0,
-			"5-10": 0,
-			"10+":  0,
-		},
+	acc := NewFragStatsAccumulator()
+	for _, f := range files {
+		acc.Add(f)
 	}
+	return acc.Stats()
+}
 
-	if len(files) == 0 {
-		return stats
-	}
+// FragStatsAccumulator builds AggregateFragStats one file at a time, so
+// callers don't need to keep every file around. It is JSON-serializable so a
+// partial aggregate can be saved and resumed.
+type FragStatsAccumulator struct {
+	Totals AggregateFragStats // Averages are only filled in by Stats
 
-	var totalDoF, totalFragPct, totalOutOfOrder float64
-	var filesWithFragPoints int
+	SumDoF              float64
+	SumFragPct          float64
+	SumOutOfOrderPct    float64
+	FilesWithFragPoints int
+}
 
-	for _, f := range files {
-		stats.TotalFiles++
-		stats.TotalExtents += f.ExtentCount
-		stats.TotalBytes += f.Size
-		totalDoF += f.DoF
+// NewFragStatsAccumulator returns an empty accumulator
+func NewFragStatsAccumulator() *FragStatsAccumulator {
+	return &FragStatsAccumulator{
+		Totals: AggregateFragStats{
+			DoFHistogram: map[string]int{
+				"1":    0,
+				"1-2":  0,
+				"2-5":  0,
+				"5-10": 0,
+				"10+":  0,
+			},
+			Usage: NewExtentUsage(),
+		},
+	}
+}
 
-		if f.DoF > 1.0 {
-			stats.FragmentedFiles++
-		}
+// Add accounts one file
+func (a *FragStatsAccumulator) Add(f *FileFragInfo) {
+	stats := &a.Totals
+	stats.TotalFiles++
+	stats.TotalExtents += f.ExtentCount
+	stats.TotalBytes += f.Size
+	a.SumDoF += f.DoF
 
-		if f.FragmentationPoints > 0 {
-			totalFragPct += f.FragmentationPct
-			totalOutOfOrder += f.OutOfOrderPct
-			filesWithFragPoints++
-		}
+	if f.DoF > 1.0 {
+		stats.FragmentedFiles++
+	}
 
-		if f.DoF > stats.MaxDoF {
-			stats.MaxDoF = f.DoF
-		}
-		if f.ExtentCount > stats.MaxExtents {
-			stats.MaxExtents = f.ExtentCount
-		}
+	if f.CompressedExtents > 0 {
+		stats.CompressedFiles++
+	}
+	stats.CompressedExtents += f.CompressedExtents
+	stats.InlineExtents += f.InlineExtents
+	stats.SharedExtents += f.SharedExtents
+	stats.UnwrittenExtents += f.UnwrittenExtents
+	stats.CompressedBytes += f.CompressedBytes
+	stats.SharedBytes += f.SharedBytes
+	if f.Usage != nil {
+		stats.Usage.Merge(f.Usage)
+	}
 
-		// Histogram
-		switch {
-		case f.DoF <= 1.0:
-			stats.DoFHistogram["1"]++
-		case f.DoF <= 2.0:
-			stats.DoFHistogram["1-2"]++
-		case f.DoF <= 5.0:
-			stats.DoFHistogram["2-5"]++
-		case f.DoF <= 10.0:
-			stats.DoFHistogram["5-10"]++
-		default:
-			stats.DoFHistogram["10+"]++
-		}
+	if f.FragmentationPoints > 0 {
+		a.SumFragPct += f.FragmentationPct
+		a.SumOutOfOrderPct += f.OutOfOrderPct
+		a.FilesWithFragPoints++
 	}
 
-	stats.AvgDoF = totalDoF / float64(stats.TotalFiles)
-	if filesWithFragPoints > 0 {
-		stats.AvgFragPct = totalFragPct / float64(filesWithFragPoints)
-		stats.AvgOutOfOrderPct = totalOutOfOrder / float64(filesWithFragPoints)
+	if f.DoF > stats.MaxDoF {
+		stats.MaxDoF = f.DoF
+	}
+	if f.ExtentCount > stats.MaxExtents {
+		stats.MaxExtents = f.ExtentCount
 	}
 
-	return stats
+	// Histogram
+	switch {
+	case f.DoF <= 1.0:
+		stats.DoFHistogram["1"]++
+	case f.DoF <= 2.0:
+		stats.DoFHistogram["1-2"]++
+	case f.DoF <= 5.0:
+		stats.DoFHistogram["2-5"]++
+	case f.DoF <= 10.0:
+		stats.DoFHistogram["5-10"]++
+	default:
+		stats.DoFHistogram["10+"]++
+	}
+}
+
+// Stats returns the aggregate of every file added so far
+func (a *FragStatsAccumulator) Stats() *AggregateFragStats {
+	stats := a.Totals
+	stats.DoFHistogram = maps.Clone(a.Totals.DoFHistogram)
+	stats.Usage = NewExtentUsage()
+	stats.Usage.Merge(a.Totals.Usage)
+
+	if stats.TotalFiles > 0 {
+		stats.AvgDoF = a.SumDoF / float64(stats.TotalFiles)
+	}
+	if a.FilesWithFragPoints > 0 {
+		stats.AvgFragPct = a.SumFragPct / float64(a.FilesWithFragPoints)
+		stats.AvgOutOfOrderPct = a.SumOutOfOrderPct / float64(a.FilesWithFragPoints)
+	}
+	return &stats
 }
 
 // SortFilesByDoF sorts files by Degree of Fragmentation (descending)
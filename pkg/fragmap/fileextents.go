@@ -0,0 +1,220 @@
+package fragmap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/elee1766/gobtr/pkg/privsep"
+)
+
+// File extent types (btrfs_file_extent_item.type)
+const (
+	FileExtentInline   = 0
+	FileExtentRegular  = 1
+	FileExtentPrealloc = 2
+)
+
+// Compression types (btrfs_file_extent_item.compression)
+const (
+	CompressNone = 0
+	CompressZlib = 1
+	CompressLZO  = 2
+	CompressZstd = 3
+)
+
+// Size limits of a single extent. Compressed extents hold at most 128KiB of
+// uncompressed data, so a compressed file is never less fragmented than that.
+const (
+	MaxExtentSize           = 128 * 1024 * 1024
+	MaxCompressedExtentSize = 128 * 1024
+)
+
+// fileExtentInlineDataStart is the offset of inline data in btrfs_file_extent_item
+const fileExtentInlineDataStart = 21
+
+// FileExtentItem is one EXTENT_DATA item of a file, read from its subvolume tree
+type FileExtentItem struct {
+	FileOffset   uint64 // Offset within the file
+	Type         uint8  // FileExtentInline, FileExtentRegular or FileExtentPrealloc
+	Compression  uint8  // CompressNone, CompressZlib, CompressLZO or CompressZstd
+	RAMBytes     uint64 // Uncompressed size of the whole extent
+	DiskBytenr   uint64 // Logical address of the extent on disk (0 for holes and inline)
+	DiskNumBytes uint64 // On-disk size of the whole extent
+	Offset       uint64 // Offset into the uncompressed extent where this reference starts
+	NumBytes     uint64 // Bytes of the file this item covers
+}
+
+// IsHole reports whether the item is a sparse hole
+func (e *FileExtentItem) IsHole() bool {
+	return e.Type != FileExtentInline && e.DiskBytenr == 0
+}
+
+// CompressionName returns a human-readable name for a compression type
+func CompressionName(c uint8) string {
+	switch c {
+	case CompressNone:
+		return "none"
+	case CompressZlib:
+		return "zlib"
+	case CompressLZO:
+		return "lzo"
+	case CompressZstd:
+		return "zstd"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseFileExtentItem parses an EXTENT_DATA item
+func ParseFileExtentItem(fileOffset uint64, data []byte) (*FileExtentItem, error) {
+	if len(data) < fileExtentInlineDataStart {
+		return nil, fmt.Errorf("file extent item too short: %d bytes", len(data))
+	}
+
+	item := &FileExtentItem{
+		FileOffset:  fileOffset,
+		RAMBytes:    binary.LittleEndian.Uint64(data[8:]),
+		Compression: data[16],
+		Type:        data[20],
+	}
+
+	if item.Type == FileExtentInline {
+		// The data follows the header, compressed if Compression is set
+		item.DiskNumBytes = uint64(len(data) - fileExtentInlineDataStart)
+		item.NumBytes = item.RAMBytes
+		return item, nil
+	}
+
+	if len(data) < 53 {
+		return nil, fmt.Errorf("file extent item too short: %d bytes", len(data))
+	}
+	item.DiskBytenr = binary.LittleEndian.Uint64(data[21:])
+	item.DiskNumBytes = binary.LittleEndian.Uint64(data[29:])
+	item.Offset = binary.LittleEndian.Uint64(data[37:])
+	item.NumBytes = binary.LittleEndian.Uint64(data[45:])
+	return item, nil
+}
+
+// GetFileExtentItems reads the EXTENT_DATA items of an open file. This uses
+// the tree search ioctl, which needs CAP_SYS_ADMIN or the helper behind b.
+func GetFileExtentItems(b privsep.Backend, f *os.File) ([]FileExtentItem, error) {
+	rootID, err := RootID(b, f)
+	if err != nil {
+		return nil, err
+	}
+
+	var st syscall.Stat_t
+	if err := syscall.Fstat(int(f.Fd()), &st); err != nil {
+		return nil, fmt.Errorf("stat: %w", err)
+	}
+
+	results, err := TreeSearch(b, f, rootID, st.Ino, st.Ino, ExtentDataKey, ExtentDataKey, 0, ^uint64(0))
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]FileExtentItem, 0, len(results))
+	for _, r := range results {
+		if r.Header.Type != ExtentDataKey || r.Header.ObjectID != st.Ino {
+			continue
+		}
+		item, err := ParseFileExtentItem(r.Header.Offset, r.Data)
+		if err != nil {
+			continue
+		}
+		items = append(items, *item)
+	}
+	return items, nil
+}
+
+// ExtentUsage sums the space used by a set of file extent items, counting
+// each on-disk extent once however many times it is referenced
+type ExtentUsage struct {
+	DiskBytes         uint64 // On-disk bytes of the referenced extents
+	UncompressedBytes uint64 // Uncompressed bytes of the referenced extents
+	ReferencedBytes   uint64 // File bytes that point at those extents
+
+	// Subset of the above for compressed extents
+	CompressedDiskBytes         uint64
+	CompressedUncompressedBytes uint64
+
+	ByCompression map[uint8]*CompressionUsage
+}
+
+// CompressionUsage is the part of ExtentUsage using one compression type
+type CompressionUsage struct {
+	DiskBytes         uint64
+	UncompressedBytes uint64
+	ReferencedBytes   uint64
+}
+
+// NewExtentUsage returns an empty ExtentUsage
+func NewExtentUsage() *ExtentUsage {
+	return &ExtentUsage{ByCompression: make(map[uint8]*CompressionUsage)}
+}
+
+// Add accounts items. seen tracks on-disk extents already counted and may be
+// shared between calls to deduplicate extents across files.
+func (u *ExtentUsage) Add(items []FileExtentItem, seen map[uint64]struct{}) {
+	for i := range items {
+		item := &items[i]
+		if item.IsHole() {
+			continue
+		}
+
+		cu := u.ByCompression[item.Compression]
+		if cu == nil {
+			cu = &CompressionUsage{}
+			u.ByCompression[item.Compression] = cu
+		}
+
+		u.ReferencedBytes += item.NumBytes
+		cu.ReferencedBytes += item.NumBytes
+
+		if item.Type != FileExtentInline {
+			if _, ok := seen[item.DiskBytenr]; ok {
+				continue
+			}
+			seen[item.DiskBytenr] = struct{}{}
+		}
+
+		u.DiskBytes += item.DiskNumBytes
+		u.UncompressedBytes += item.RAMBytes
+		cu.DiskBytes += item.DiskNumBytes
+		cu.UncompressedBytes += item.RAMBytes
+		if item.Compression != CompressNone {
+			u.CompressedDiskBytes += item.DiskNumBytes
+			u.CompressedUncompressedBytes += item.RAMBytes
+		}
+	}
+}
+
+// Merge adds o to u
+func (u *ExtentUsage) Merge(o *ExtentUsage) {
+	u.DiskBytes += o.DiskBytes
+	u.UncompressedBytes += o.UncompressedBytes
+	u.ReferencedBytes += o.ReferencedBytes
+	u.CompressedDiskBytes += o.CompressedDiskBytes
+	u.CompressedUncompressedBytes += o.CompressedUncompressedBytes
+	for c, ocu := range o.ByCompression {
+		cu := u.ByCompression[c]
+		if cu == nil {
+			cu = &CompressionUsage{}
+			u.ByCompression[c] = cu
+		}
+		cu.DiskBytes += ocu.DiskBytes
+		cu.UncompressedBytes += ocu.UncompressedBytes
+		cu.ReferencedBytes += ocu.ReferencedBytes
+	}
+}
+
+// CompressionRatio returns uncompressed/on-disk bytes for the compressed
+// extents, or 0 if there are none
+func (u *ExtentUsage) CompressionRatio() float64 {
+	if u.CompressedDiskBytes == 0 {
+		return 0
+	}
+	return float64(u.CompressedUncompressedBytes) / float64(u.CompressedDiskBytes)
+}
@@ -8,25 +8,28 @@ import (
 	"sort"
 	"time"
 
-	"github.com/dennwc/btrfs"
+	"github.com/elee1766/gobtr/pkg/privsep"
 )
 
 // Scanner reads fragmentation data from a btrfs filesystem
 type Scanner struct {
-	fsPath string
-	file   *os.File
+	fsPath  string
+	file    *os.File
+	backend privsep.Backend
 }
 
-// NewScanner creates a new fragmap scanner for the given filesystem path
-func NewScanner(fsPath string) (*Scanner, error) {
-	f, err := os.OpenFile(fsPath, os.O_RDONLY, 0)
+// NewScanner creates a new fragmap scanner for the given filesystem path,
+// doing its privileged reads through b
+func NewScanner(b privsep.Backend, fsPath string) (*Scanner, error) {
+	f, err := b.Open(fsPath)
 	if err != nil {
 		return nil, fmt.Errorf("open filesystem: %w", err)
 	}
 
 	return &Scanner{
-		fsPath: fsPath,
-		file:   f,
+		fsPath:  fsPath,
+		file:    f,
+		backend: b,
 	}, nil
 }
 
@@ -43,8 +46,18 @@ func (s *Scanner) Scan() (*FragMap, error) {
 	totalStart := time.Now()
 	fm := &FragMap{
 		DeviceExtents: make(map[uint64][]DeviceExtent),
+		ScannedAt:     totalStart,
 	}
 
+	// Read the generation first, so changes made during the scan make the
+	// next generation check see a newer one
+	fsUUID, gen, err := FSInfo(s.backend, s.file)
+	if err != nil {
+		return nil, err
+	}
+	fm.UUID = fsUUID
+	fm.Generation = gen
+
 	// Get devices
 	start := time.Now()
 	devices, err := s.scanDevices()
@@ -90,21 +103,12 @@ func (s *Scanner) scanDevices() ([]Device, error) {
 	// Search the chunk tree for device items
 	// Device items are stored with objectid = device id, type = DEV_ITEM_KEY
 	start := time.Now()
-	results, err := TreeSearch(s.file, ChunkTreeObjectID, 1, ^uint64(0), DevItemKey, DevItemKey, 0, ^uint64(0))
+	results, err := TreeSearch(s.backend, s.file, ChunkTreeObjectID, 1, ^uint64(0), DevItemKey, DevItemKey, 0, ^uint64(0))
 	if err != nil {
 		return nil, err
 	}
 	slog.Debug("fragmap scan timing", "phase", "scanDevices.TreeSearch", "duration", time.Since(start))
 
-	// Open btrfs handle to get device info including path
-	start = time.Now()
-	fs, err := btrfs.Open(s.fsPath, true)
-	if err != nil {
-		return nil, fmt.Errorf("open btrfs: %w", err)
-	}
-	defer fs.Close()
-	slog.Debug("fragmap scan timing", "phase", "scanDevices.btrfs.Open", "duration", time.Since(start))
-
 	var devices []Device
 	start = time.Now()
 	for _, r := range results {
@@ -119,9 +123,8 @@ func (s *Scanner) scanDevices() ([]Device, error) {
 
 		// Get device path from btrfs
 		devInfoStart := time.Now()
-		devInfo, err := fs.GetDevInfo(dev.ID)
-		if err == nil {
-			dev.Path = devInfo.Path
+		if path, err := devPath(s.backend, s.file, dev.ID); err == nil {
+			dev.Path = path
 		}
 		slog.Debug("fragmap scan timing", "phase", "scanDevices.GetDevInfo", "deviceID", dev.ID, "duration", time.Since(devInfoStart))
 
@@ -137,7 +140,7 @@ func (s *Scanner) scanChunks() ([]Chunk, error) {
 	// Search the chunk tree for chunk items
 	// Use FirstChunkTreeObjectID as min, but max should be unlimited to get all chunks
 	start := time.Now()
-	results, err := TreeSearch(s.file, ChunkTreeObjectID, FirstChunkTreeObjectID, ^uint64(0), ChunkItemKey, ChunkItemKey, 0, ^uint64(0))
+	results, err := TreeSearch(s.backend, s.file, ChunkTreeObjectID, FirstChunkTreeObjectID, ^uint64(0), ChunkItemKey, ChunkItemKey, 0, ^uint64(0))
 	if err != nil {
 		return nil, err
 	}
@@ -195,7 +198,7 @@ func (s *Scanner) scanBlockGroups() ([]BlockGroupItem, error) {
 	// Single query to get ALL block group items from the extent tree
 	// Block groups use objectid = logical offset, type = BLOCK_GROUP_ITEM_KEY
 	// We search for all objectids (0 to max) with type = BlockGroupItemKey
-	results, err := TreeSearch(s.file, ExtentTreeObjectID,
+	results, err := TreeSearch(s.backend, s.file, ExtentTreeObjectID,
 		0, ^uint64(0),
 		BlockGroupItemKey, BlockGroupItemKey,
 		0, ^uint64(0))
@@ -225,7 +228,7 @@ func (s *Scanner) scanBlockGroups() ([]BlockGroupItem, error) {
 // scanDeviceExtents scans for all extents on a specific device
 func (s *Scanner) scanDeviceExtents(deviceID uint64) ([]DeviceExtent, error) {
 	// Search the device tree for device extent items
-	results, err := TreeSearch(s.file, DevTreeObjectID, deviceID, deviceID, DevExtentKey, DevExtentKey, 0, ^uint64(0))
+	results, err := TreeSearch(s.backend, s.file, DevTreeObjectID, deviceID, deviceID, DevExtentKey, DevExtentKey, 0, ^uint64(0))
 	if err != nil {
 		return nil, err
 	}
@@ -540,92 +543,3 @@ func (fm *FragMap) CalculateChunkUtilization() ChunkUtilizationStats {
 
 	return stats
 }
-
-// HeatMapData generates data suitable for a 2D heat map visualization
-// Resolution determines how many blocks to divide the device into
-func (bm *DeviceBlockMap) HeatMapData(resolution int) []HeatMapCell {
-	if resolution <= 0 {
-		resolution = 256
-	}
-
-	blockSize := bm.TotalSize / uint64(resolution)
-	if blockSize == 0 {
-		blockSize = 1
-	}
-
-	cells := make([]HeatMapCell, resolution)
-	for i := range cells {
-		cells[i].Index = i
-		cells[i].StartOffset = uint64(i) * blockSize
-		cells[i].EndOffset = uint64(i+1) * blockSize
-	}
-
-	// Fill in cell data based on entries
-	for _, entry := range bm.Entries {
-		startCell := int(entry.Offset / blockSize)
-		endCell := int((entry.Offset + entry.Length) / blockSize)
-
-		if startCell >= resolution {
-			startCell = resolution - 1
-		}
-		if endCell >= resolution {
-			endCell = resolution - 1
-		}
-
-		for c := startCell; c <= endCell; c++ {
-			// Calculate how much of this entry overlaps with this cell
-			cellStart := uint64(c) * blockSize
-			cellEnd := uint64(c+1) * blockSize
-
-			overlapStart := entry.Offset
-			if overlapStart < cellStart {
-				overlapStart = cellStart
-			}
-			overlapEnd := entry.Offset + entry.Length
-			if overlapEnd > cellEnd {
-				overlapEnd = cellEnd
-			}
-
-			if overlapEnd > overlapStart {
-				overlapLen := overlapEnd - overlapStart
-				if entry.Allocated {
-					cells[c].AllocatedBytes += overlapLen
-					cells[c].ExtentCount++
-					if entry.Type&BlockTypeData != 0 {
-						cells[c].DataBytes += overlapLen
-					} else if entry.Type&BlockTypeMetadata != 0 {
-						cells[c].MetadataBytes += overlapLen
-					} else if entry.Type&BlockTypeSystem != 0 {
-						cells[c].SystemBytes += overlapLen
-					}
-				} else {
-					cells[c].FreeBytes += overlapLen
-				}
-			}
-		}
-	}
-
-	// Calculate utilization percentage for each cell
-	for i := range cells {
-		cellSize := cells[i].EndOffset - cells[i].StartOffset
-		if cellSize > 0 {
-			cells[i].Utilization = float64(cells[i].AllocatedBytes) / float64(cellSize)
-		}
-	}
-
-	return cells
-}
-
-// HeatMapCell represents a single cell in the heat map
-type HeatMapCell struct {
-	Index          int
-	StartOffset    uint64
-	EndOffset      uint64
-	AllocatedBytes uint64
-	FreeBytes      uint64
-	DataBytes      uint64
-	MetadataBytes  uint64
-	SystemBytes    uint64
-	ExtentCount    int
-	Utilization    float64 // 0.0 to 1.0
-}
@@ -0,0 +1,349 @@
+package fragmap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Where free space inside block groups was read from
+const (
+	FreeSpaceSourceTree   = "free_space_tree"
+	FreeSpaceSourceExtent = "extent_tree"
+)
+
+// FragRunThreshold is the free run size below which free space counts as
+// fragmented. Runs this small can't hold a full-sized extent, so writes into
+// them get split.
+const FragRunThreshold = 1 << 20
+
+// FreeSpaceBucketBounds are the upper bounds (exclusive) of the histogram
+// buckets. The last bucket holds every run at least as large as the last bound.
+var FreeSpaceBucketBounds = [...]uint64{
+	64 << 10,
+	256 << 10,
+	1 << 20,
+	4 << 20,
+	16 << 20,
+	64 << 20,
+	256 << 20,
+}
+
+const numFreeSpaceBuckets = len(FreeSpaceBucketBounds) + 1
+
+// FreeSpaceHistogram counts free runs by size
+type FreeSpaceHistogram struct {
+	Counts [numFreeSpaceBuckets]uint64
+	Bytes  [numFreeSpaceBuckets]uint64
+}
+
+// Add records one free run
+func (h *FreeSpaceHistogram) Add(size uint64) {
+	i := 0
+	for i < len(FreeSpaceBucketBounds) && size >= FreeSpaceBucketBounds[i] {
+		i++
+	}
+	h.Counts[i]++
+	h.Bytes[i] += size
+}
+
+// Merge adds every run of o to h
+func (h *FreeSpaceHistogram) Merge(o *FreeSpaceHistogram) {
+	for i := range h.Counts {
+		h.Counts[i] += o.Counts[i]
+		h.Bytes[i] += o.Bytes[i]
+	}
+}
+
+// fragmentedBytes returns the free bytes in runs smaller than FragRunThreshold
+func (h *FreeSpaceHistogram) fragmentedBytes() uint64 {
+	var total uint64
+	for i, bound := range FreeSpaceBucketBounds {
+		if bound > FragRunThreshold {
+			break
+		}
+		total += h.Bytes[i]
+	}
+	return total
+}
+
+// BlockGroupFreeSpace describes the free space inside one block group
+type BlockGroupFreeSpace struct {
+	LogicalOffset uint64
+	Length        uint64
+	Type          BlockType
+	Profile       BlockProfile
+	FreeBytes     uint64
+	FreeRuns      int
+	LargestFree   uint64
+	Histogram     FreeSpaceHistogram
+
+	// Percentage of free bytes in runs smaller than FragRunThreshold
+	// (0-100, higher = more fragmented)
+	FragScore float64
+}
+
+// addRun records one free run
+func (bg *BlockGroupFreeSpace) addRun(size uint64) {
+	if size == 0 {
+		return
+	}
+	bg.FreeBytes += size
+	bg.FreeRuns++
+	bg.LargestFree = max(bg.LargestFree, size)
+	bg.Histogram.Add(size)
+}
+
+func (bg *BlockGroupFreeSpace) finish() {
+	if bg.FreeBytes > 0 {
+		bg.FragScore = float64(bg.Histogram.fragmentedBytes()) / float64(bg.FreeBytes) * 100
+	}
+}
+
+// FreeSpaceSummary aggregates free space over the block groups of one type
+type FreeSpaceSummary struct {
+	Type        BlockType
+	BlockGroups int
+	Length      uint64
+	FreeBytes   uint64
+	FreeRuns    int
+	LargestFree uint64
+	Histogram   FreeSpaceHistogram
+	FragScore   float64 // Same as BlockGroupFreeSpace.FragScore, over all block groups
+}
+
+// SummarizeFreeSpace aggregates the block groups of type t
+func SummarizeFreeSpace(bgs []BlockGroupFreeSpace, t BlockType) FreeSpaceSummary {
+	sum := FreeSpaceSummary{Type: t}
+	for i := range bgs {
+		bg := &bgs[i]
+		if bg.Type&t == 0 {
+			continue
+		}
+		sum.BlockGroups++
+		sum.Length += bg.Length
+		sum.FreeBytes += bg.FreeBytes
+		sum.FreeRuns += bg.FreeRuns
+		sum.LargestFree = max(sum.LargestFree, bg.LargestFree)
+		sum.Histogram.Merge(&bg.Histogram)
+	}
+	if sum.FreeBytes > 0 {
+		sum.FragScore = float64(sum.Histogram.fragmentedBytes()) / float64(sum.FreeBytes) * 100
+	}
+	return sum
+}
+
+// ScanFreeSpace reads the free space inside every block group in chunks. It
+// uses the free space tree when the filesystem has one, and otherwise derives
+// free runs from the gaps between allocated extents in the extent tree, which
+// is much slower on large filesystems. It returns which source was used.
+func (s *Scanner) ScanFreeSpace(chunks []Chunk) ([]BlockGroupFreeSpace, string, error) {
+	start := time.Now()
+
+	info, err := fsInfo(s.backend, s.file)
+	if err != nil {
+		return nil, "", fmt.Errorf("get filesystem info: %w", err)
+	}
+
+	source := FreeSpaceSourceTree
+	bgs := make([]BlockGroupFreeSpace, 0, len(chunks))
+	for _, chunk := range chunks {
+		bg := BlockGroupFreeSpace{
+			LogicalOffset: chunk.LogicalOffset,
+			Length:        chunk.Length,
+			Type:          chunk.Type,
+			Profile:       chunk.Profile,
+		}
+
+		if source == FreeSpaceSourceTree {
+			err := s.scanFreeSpaceTree(&bg, uint64(info.SectorSize))
+			if err != nil {
+				// No free space tree (or it can't be searched), fall back for every block group
+				slog.Debug("free space tree unavailable, using extent tree", "error", err)
+				source = FreeSpaceSourceExtent
+				bg = BlockGroupFreeSpace{
+					LogicalOffset: chunk.LogicalOffset,
+					Length:        chunk.Length,
+					Type:          chunk.Type,
+					Profile:       chunk.Profile,
+				}
+			}
+		}
+		if source == FreeSpaceSourceExtent {
+			if err := s.scanExtentTreeGaps(&bg, uint64(info.NodeSize)); err != nil {
+				return nil, "", fmt.Errorf("scan extents of block group %d: %w", chunk.LogicalOffset, err)
+			}
+		}
+
+		bg.finish()
+		bgs = append(bgs, bg)
+	}
+
+	slog.Debug("fragmap scan timing", "phase", "scanFreeSpace", "source", source, "duration", time.Since(start), "count", len(bgs))
+	return bgs, source, nil
+}
+
+// scanFreeSpaceTree reads the free space entries of one block group.
+// Entries are either extents or bitmaps with one bit per sector.
+func (s *Scanner) scanFreeSpaceTree(bg *BlockGroupFreeSpace, sectorSize uint64) error {
+	end := bg.LogicalOffset + bg.Length
+	results, err := TreeSearch(s.backend, s.file, FreeSpaceTreeObjectID,
+		bg.LogicalOffset, end-1,
+		FreeSpaceInfoKey, FreeSpaceBitmapKey,
+		0, ^uint64(0))
+	if err != nil {
+		return err
+	}
+
+	foundInfo := false
+
+	// Adjacent runs can be split across bitmap items, so merge them
+	var runStart, runEnd uint64
+	flush := func() {
+		bg.addRun(runEnd - runStart)
+		runStart, runEnd = 0, 0
+	}
+	add := func(start, length uint64) {
+		if runEnd != 0 && start == runEnd {
+			runEnd += length
+			return
+		}
+		flush()
+		runStart, runEnd = start, start+length
+	}
+
+	for _, r := range results {
+		switch r.Header.Type {
+		case FreeSpaceInfoKey:
+			if r.Header.ObjectID == bg.LogicalOffset {
+				foundInfo = true
+			}
+		case FreeSpaceExtentKey:
+			add(r.Header.ObjectID, r.Header.Offset)
+		case FreeSpaceBitmapKey:
+			walkBitmap(r.Data, r.Header.ObjectID, sectorSize, add)
+		}
+	}
+	flush()
+
+	if !foundInfo {
+		return fmt.Errorf("no free space info for block group %d", bg.LogicalOffset)
+	}
+	return nil
+}
+
+// walkBitmap calls add for every run of set bits, one bit per sector from base
+func walkBitmap(bitmap []byte, base, sectorSize uint64, add func(start, length uint64)) {
+	nbits := uint64(len(bitmap)) * 8
+	var i uint64
+	for i < nbits {
+		// Skip whole zero words quickly
+		if i%64 == 0 && i+64 <= nbits && binary.LittleEndian.Uint64(bitmap[i/8:]) == 0 {
+			i += 64
+			continue
+		}
+		if bitmap[i/8]&(1<<(i%8)) == 0 {
+			i++
+			continue
+		}
+		start := i
+		for i < nbits && bitmap[i/8]&(1<<(i%8)) != 0 {
+			if i%64 == 0 && i+64 <= nbits && binary.LittleEndian.Uint64(bitmap[i/8:]) == ^uint64(0) {
+				i += 64
+				continue
+			}
+			i++
+		}
+		add(base+start*sectorSize, (i-start)*sectorSize)
+	}
+}
+
+// scanExtentTreeGaps derives the free runs of one block group from the gaps
+// between its allocated extents
+func (s *Scanner) scanExtentTreeGaps(bg *BlockGroupFreeSpace, nodeSize uint64) error {
+	end := bg.LogicalOffset + bg.Length
+	results, err := TreeSearch(s.backend, s.file, ExtentTreeObjectID,
+		bg.LogicalOffset, end-1,
+		ExtentItemKey, MetadataItemKey,
+		0, ^uint64(0))
+	if err != nil {
+		return err
+	}
+
+	// Results come back in key order, so extents are sorted by address
+	pos := bg.LogicalOffset
+	for _, r := range results {
+		start := r.Header.ObjectID
+		var length uint64
+		switch r.Header.Type {
+		case ExtentItemKey:
+			length = r.Header.Offset
+		case MetadataItemKey:
+			// Skinny metadata items store the tree level in the offset
+			length = nodeSize
+		default:
+			continue
+		}
+		if start > pos {
+			bg.addRun(start - pos)
+		}
+		pos = max(pos, start+length)
+	}
+	if end > pos {
+		bg.addRun(end - pos)
+	}
+
+	return nil
+}
+
+// FreeSpaceByOffset indexes block groups by logical offset
+func FreeSpaceByOffset(bgs []BlockGroupFreeSpace) map[uint64]*BlockGroupFreeSpace {
+	m := make(map[uint64]*BlockGroupFreeSpace, len(bgs))
+	for i := range bgs {
+		m[bgs[i].LogicalOffset] = &bgs[i]
+	}
+	return m
+}
+
+// ApplyFreeSpace fills the intra-chunk free space of heat map cells. Free
+// bytes are spread evenly over a chunk's device extent, since the logical to
+// physical mapping within a stripe isn't tracked.
+func (bm *DeviceBlockMap) ApplyFreeSpace(cells []HeatMapCell, free map[uint64]*BlockGroupFreeSpace) {
+	if len(cells) == 0 {
+		return
+	}
+
+	weights := make([]float64, len(cells))
+	scores := make([]float64, len(cells))
+
+	for _, entry := range bm.Entries {
+		if !entry.Allocated || entry.Length == 0 {
+			continue
+		}
+		bg, ok := free[entry.ChunkOffset]
+		if !ok || bg.Length == 0 {
+			continue
+		}
+		freeRatio := float64(bg.FreeBytes) / float64(bg.Length)
+
+		lo, hi := cellsOverlapping(cells, entry.Offset, entry.Offset+entry.Length)
+		for c := lo; c < hi; c++ {
+			overlapStart := max(entry.Offset, cells[c].StartOffset)
+			overlapEnd := min(entry.Offset+entry.Length, cells[c].EndOffset)
+			if overlapEnd <= overlapStart {
+				continue
+			}
+			overlap := float64(overlapEnd - overlapStart)
+			cells[c].ChunkFreeBytes += uint64(overlap * freeRatio)
+			weights[c] += overlap
+			scores[c] += overlap * bg.FragScore
+		}
+	}
+
+	for c := range cells {
+		if weights[c] > 0 {
+			cells[c].FreeSpaceFragScore = scores[c] / weights[c]
+		}
+	}
+}
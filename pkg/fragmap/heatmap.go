@@ -0,0 +1,241 @@
+package fragmap
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// HeatMapCell represents a single cell in the heat map
+type HeatMapCell struct {
+	Index          int
+	StartOffset    uint64
+	EndOffset      uint64
+	AllocatedBytes uint64
+	FreeBytes      uint64
+	DataBytes      uint64
+	MetadataBytes  uint64
+	SystemBytes    uint64
+	ExtentCount    int
+	Utilization    float64 // 0.0 to 1.0
+
+	// Profile covering the most allocated bytes (only meaningful if
+	// AllocatedBytes > 0, since single is 0)
+	DominantProfile BlockProfile
+	// Used bytes of the chunks in this cell, assuming usage is spread evenly
+	// over each chunk
+	ChunkUsedBytes uint64
+	// ChunkUsedBytes / AllocatedBytes: how full the block groups here are
+	BlockGroupUsage float64
+
+	// Position in a 2-D grid, filled by HeatMapGrid
+	X, Y int
+
+	// Filled by ApplyFreeSpace
+	ChunkFreeBytes     uint64  // Free bytes inside the chunks in this cell
+	FreeSpaceFragScore float64 // Average FragScore of the chunks, weighted by overlap
+}
+
+// HeatMapData generates data suitable for a 2D heat map visualization
+// Resolution determines how many blocks to divide the device into
+func (bm *DeviceBlockMap) HeatMapData(resolution int) []HeatMapCell {
+	return bm.HeatMapRange(0, bm.TotalSize, resolution)
+}
+
+// HeatMapRange divides [start, end) of the device into resolution cells, for
+// zooming into part of a large device. end is clamped to the device size.
+func (bm *DeviceBlockMap) HeatMapRange(start, end uint64, resolution int) []HeatMapCell {
+	if resolution <= 0 {
+		resolution = 256
+	}
+	end = min(end, bm.TotalSize)
+	if end <= start {
+		return nil
+	}
+	span := end - start
+	// No point in cells smaller than a byte
+	if uint64(resolution) > span {
+		resolution = int(span)
+	}
+
+	// Cell boundaries are start + i*span/resolution, so cells differ in size
+	// by at most a byte and the last one ends exactly at end
+	cells := make([]HeatMapCell, resolution)
+	for i := range cells {
+		cells[i].Index = i
+		cells[i].StartOffset = start + mulDiv(uint64(i), span, uint64(resolution))
+		cells[i].EndOffset = start + mulDiv(uint64(i+1), span, uint64(resolution))
+	}
+
+	profileBytes := make([]map[BlockProfile]uint64, resolution)
+
+	// Entries are sorted by offset, so skip straight to the first one that
+	// reaches the range
+	first := sort.Search(len(bm.Entries), func(i int) bool {
+		e := bm.Entries[i]
+		return e.Offset+e.Length > start
+	})
+	for _, entry := range bm.Entries[first:] {
+		if entry.Offset >= end {
+			break
+		}
+		lo, hi := cellsOverlapping(cells, entry.Offset, entry.Offset+entry.Length)
+		for c := lo; c < hi; c++ {
+			overlapStart := max(entry.Offset, cells[c].StartOffset)
+			overlapEnd := min(entry.Offset+entry.Length, cells[c].EndOffset)
+			if overlapEnd <= overlapStart {
+				continue
+			}
+			overlapLen := overlapEnd - overlapStart
+
+			if !entry.Allocated {
+				cells[c].FreeBytes += overlapLen
+				continue
+			}
+			cells[c].AllocatedBytes += overlapLen
+			cells[c].ExtentCount++
+			if entry.Type&BlockTypeData != 0 {
+				cells[c].DataBytes += overlapLen
+			} else if entry.Type&BlockTypeMetadata != 0 {
+				cells[c].MetadataBytes += overlapLen
+			} else if entry.Type&BlockTypeSystem != 0 {
+				cells[c].SystemBytes += overlapLen
+			}
+			if entry.ChunkLength > 0 {
+				used := float64(overlapLen) * float64(entry.ChunkUsed) / float64(entry.ChunkLength)
+				cells[c].ChunkUsedBytes += uint64(used)
+			}
+			if profileBytes[c] == nil {
+				profileBytes[c] = make(map[BlockProfile]uint64, 1)
+			}
+			profileBytes[c][entry.Profile] += overlapLen
+		}
+	}
+
+	for i := range cells {
+		cellSize := cells[i].EndOffset - cells[i].StartOffset
+		if cellSize > 0 {
+			cells[i].Utilization = float64(cells[i].AllocatedBytes) / float64(cellSize)
+		}
+		if cells[i].AllocatedBytes > 0 {
+			cells[i].BlockGroupUsage = min(float64(cells[i].ChunkUsedBytes)/float64(cells[i].AllocatedBytes), 1)
+		}
+		var most uint64
+		for p, n := range profileBytes[i] {
+			// Ties go to the lower profile so the result is stable
+			if n > most || (n == most && p < cells[i].DominantProfile) {
+				cells[i].DominantProfile = p
+				most = n
+			}
+		}
+	}
+
+	return cells
+}
+
+// cellsOverlapping returns the index range [lo, hi) of the cells that overlap
+// [start, end). Cells must be sorted and contiguous.
+func cellsOverlapping(cells []HeatMapCell, start, end uint64) (lo, hi int) {
+	lo = sort.Search(len(cells), func(i int) bool { return cells[i].EndOffset > start })
+	hi = sort.Search(len(cells), func(i int) bool { return cells[i].StartOffset >= end })
+	return lo, hi
+}
+
+// mulDiv returns a*b/c without overflowing, for a <= c
+func mulDiv(a, b, c uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	q, _ := bits.Div64(hi, lo, c)
+	return q
+}
+
+// HeatMapLayout is how heat map cells are arranged in two dimensions
+type HeatMapLayout string
+
+const (
+	// LayoutLinear is a single strip: X is the index, Y is 0
+	LayoutLinear HeatMapLayout = "linear"
+	// LayoutRowMajor wraps the strip into rows of a square-ish grid
+	LayoutRowMajor HeatMapLayout = "row_major"
+	// LayoutHilbert orders cells along a Hilbert curve, so cells close on the
+	// device stay close on screen in both directions
+	LayoutHilbert HeatMapLayout = "hilbert"
+)
+
+// ParseHeatMapLayout parses a layout name. The empty string is LayoutLinear.
+func ParseHeatMapLayout(s string) (HeatMapLayout, error) {
+	switch HeatMapLayout(s) {
+	case "", LayoutLinear:
+		return LayoutLinear, nil
+	case LayoutRowMajor, LayoutHilbert:
+		return HeatMapLayout(s), nil
+	default:
+		return "", fmt.Errorf("unknown heat map layout %q", s)
+	}
+}
+
+// GridResolution adjusts a resolution to fit the layout. A Hilbert curve
+// fills a square with a power-of-two side, so the resolution is rounded up
+// to the next power of four.
+func (l HeatMapLayout) GridResolution(resolution int) int {
+	if l != LayoutHilbert || resolution <= 1 {
+		return resolution
+	}
+	side := 1
+	for side*side < resolution {
+		side *= 2
+	}
+	return side * side
+}
+
+// HeatMapGrid sets the X and Y of every cell for the layout and returns the
+// grid size. Use GridResolution first so Hilbert grids are fully covered.
+func HeatMapGrid(cells []HeatMapCell, layout HeatMapLayout) (width, height int) {
+	n := len(cells)
+	if n == 0 {
+		return 0, 0
+	}
+
+	switch layout {
+	case LayoutRowMajor:
+		width = int(math.Ceil(math.Sqrt(float64(n))))
+		height = (n + width - 1) / width
+		for i := range cells {
+			cells[i].X, cells[i].Y = i%width, i/width
+		}
+	case LayoutHilbert:
+		side := 1
+		for side*side < n {
+			side *= 2
+		}
+		width, height = side, side
+		for i := range cells {
+			cells[i].X, cells[i].Y = hilbertXY(side, i)
+		}
+	default:
+		width, height = n, 1
+		for i := range cells {
+			cells[i].X, cells[i].Y = i, 0
+		}
+	}
+	return width, height
+}
+
+// hilbertXY maps distance d along the Hilbert curve filling a side×side
+// square (side a power of two) to grid coordinates
+func hilbertXY(side, d int) (x, y int) {
+	for s := 1; s < side; s *= 2 {
+		rx := 1 & (d / 2)
+		ry := 1 & (d ^ rx)
+		if ry == 0 {
+			if rx == 1 {
+				x, y = s-1-x, s-1-y
+			}
+			x, y = y, x
+		}
+		x += s * rx
+		y += s * ry
+		d /= 4
+	}
+	return x, y
+}
@@ -0,0 +1,99 @@
+package fragmap
+
+import "testing"
+
+func TestHilbertXY(t *testing.T) {
+	for _, side := range []int{1, 2, 4, 8, 16, 64} {
+		seen := make(map[[2]int]bool, side*side)
+		var px, py int
+		for d := 0; d < side*side; d++ {
+			x, y := hilbertXY(side, d)
+			if x < 0 || y < 0 || x >= side || y >= side {
+				t.Fatalf("side %d: d %d maps outside the grid to (%d, %d)", side, d, x, y)
+			}
+			if seen[[2]int{x, y}] {
+				t.Fatalf("side %d: (%d, %d) visited twice", side, x, y)
+			}
+			seen[[2]int{x, y}] = true
+			// Each step moves to a neighbouring cell
+			if d > 0 && abs(x-px)+abs(y-py) != 1 {
+				t.Fatalf("side %d: d %d jumps from (%d, %d) to (%d, %d)", side, d, px, py, x, y)
+			}
+			px, py = x, y
+		}
+	}
+}
+
+func TestGridResolution(t *testing.T) {
+	tests := []struct {
+		layout     HeatMapLayout
+		resolution int
+		want       int
+	}{
+		{LayoutHilbert, 0, 0},
+		{LayoutHilbert, 1, 1},
+		{LayoutHilbert, 2, 4},
+		{LayoutHilbert, 4, 4},
+		{LayoutHilbert, 5, 16},
+		{LayoutHilbert, 256, 256},
+		{LayoutHilbert, 257, 1024},
+		{LayoutRowMajor, 5, 5},
+		{LayoutLinear, 5, 5},
+	}
+	for _, tt := range tests {
+		if got := tt.layout.GridResolution(tt.resolution); got != tt.want {
+			t.Errorf("%s.GridResolution(%d) = %d, want %d", tt.layout, tt.resolution, got, tt.want)
+		}
+	}
+}
+
+func TestHeatMapGrid(t *testing.T) {
+	tests := []struct {
+		name          string
+		layout        HeatMapLayout
+		cells         int
+		width, height int
+		xy            [][2]int
+	}{
+		{name: "empty", layout: LayoutHilbert},
+		{name: "single hilbert", layout: LayoutHilbert, cells: 1, width: 1, height: 1, xy: [][2]int{{0, 0}}},
+		{name: "single row major", layout: LayoutRowMajor, cells: 1, width: 1, height: 1, xy: [][2]int{{0, 0}}},
+		{name: "linear", layout: LayoutLinear, cells: 3, width: 3, height: 1, xy: [][2]int{{0, 0}, {1, 0}, {2, 0}}},
+		{name: "unknown is linear", layout: "", cells: 2, width: 2, height: 1, xy: [][2]int{{0, 0}, {1, 0}}},
+		{name: "row major", layout: LayoutRowMajor, cells: 5, width: 3, height: 2, xy: [][2]int{{0, 0}, {1, 0}, {2, 0}, {0, 1}, {1, 1}}},
+		{name: "hilbert", layout: LayoutHilbert, cells: 4, width: 2, height: 2, xy: [][2]int{{0, 0}, {0, 1}, {1, 1}, {1, 0}}},
+		{name: "partial hilbert", layout: LayoutHilbert, cells: 3, width: 2, height: 2, xy: [][2]int{{0, 0}, {0, 1}, {1, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cells := make([]HeatMapCell, tt.cells)
+			width, height := HeatMapGrid(cells, tt.layout)
+			if width != tt.width || height != tt.height {
+				t.Errorf("grid %dx%d, want %dx%d", width, height, tt.width, tt.height)
+			}
+			for i, c := range cells {
+				if got := [2]int{c.X, c.Y}; got != tt.xy[i] {
+					t.Errorf("cell %d at %v, want %v", i, got, tt.xy[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseHeatMapLayout(t *testing.T) {
+	for in, want := range map[string]HeatMapLayout{"": LayoutLinear, "linear": LayoutLinear, "row_major": LayoutRowMajor, "hilbert": LayoutHilbert} {
+		if got, err := ParseHeatMapLayout(in); err != nil || got != want {
+			t.Errorf("ParseHeatMapLayout(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseHeatMapLayout("spiral"); err == nil {
+		t.Error("ParseHeatMapLayout(spiral) succeeded")
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
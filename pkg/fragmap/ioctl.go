@@ -1,6 +1,7 @@
 package fragmap
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"log/slog"
@@ -9,6 +10,8 @@ import (
 	"unsafe"
 
 	"github.com/dennwc/ioctl"
+	"github.com/elee1766/gobtr/pkg/privsep"
+	"github.com/google/uuid"
 )
 
 // btrfs ioctl magic number
@@ -19,6 +22,7 @@ const (
 	ExtentTreeObjectID = 2
 	ChunkTreeObjectID  = 3
 	DevTreeObjectID    = 4
+	FreeSpaceTreeObjectID = 10
 )
 
 // Item key types
@@ -27,6 +31,12 @@ const (
 	ChunkItemKey     = 228
 	DevExtentKey     = 204
 	BlockGroupItemKey = 192
+	ExtentDataKey     = 108
+	ExtentItemKey     = 168
+	MetadataItemKey   = 169
+	FreeSpaceInfoKey   = 198
+	FreeSpaceExtentKey = 199
+	FreeSpaceBitmapKey = 200
 )
 
 // First chunk tree object ID
@@ -120,10 +130,102 @@ type btrfsDevItem struct {
 	FSID         [16]byte
 }
 
+// btrfsIoctlInoLookupArgs is the structure for BTRFS_IOC_INO_LOOKUP
+type btrfsIoctlInoLookupArgs struct {
+	TreeID   uint64
+	ObjectID uint64
+	Name     [4080]byte
+}
+
+// btrfsIoctlFsInfoArgs is the structure for BTRFS_IOC_FS_INFO
+type btrfsIoctlFsInfoArgs struct {
+	MaxID          uint64
+	NumDevices     uint64
+	FSID           [16]byte
+	NodeSize       uint32
+	SectorSize     uint32
+	CloneAlignment uint32
+	CsumType       uint16
+	CsumSize       uint16
+	Flags          uint64
+	Generation     uint64
+	MetadataUUID   [16]byte
+	Reserved       [944]byte
+}
+
+// fsInfoFlagGeneration asks BTRFS_IOC_FS_INFO to fill in the generation
+const fsInfoFlagGeneration = 1 << 1
+
 var ioctlTreeSearch = ioctl.IOWR(btrfsIoctlMagic, 17, unsafe.Sizeof(btrfsIoctlSearchArgs{}))
+var ioctlInoLookup = ioctl.IOWR(btrfsIoctlMagic, 18, unsafe.Sizeof(btrfsIoctlInoLookupArgs{}))
+var ioctlFsInfo = ioctl.IOR(btrfsIoctlMagic, 31, unsafe.Sizeof(btrfsIoctlFsInfoArgs{}))
+var ioctlDevInfo = ioctl.IOWR(btrfsIoctlMagic, 30, unsafe.Sizeof(btrfsIoctlDevInfoArgs{}))
+
+// btrfsIoctlDevInfoArgs is the structure for BTRFS_IOC_DEV_INFO
+type btrfsIoctlDevInfoArgs struct {
+	DevID      uint64
+	UUID       [16]byte
+	BytesUsed  uint64
+	TotalBytes uint64
+	FSID       [16]byte
+	Unused     [377]uint64
+	Path       [1024]byte
+}
+
+// logicalInoIgnoreOffset is BTRFS_LOGICAL_INO_ARGS_IGNORE_OFFSET: return
+// every reference to the extent, not only those covering the exact address
+const logicalInoIgnoreOffset = 1 << 0
+
+// FSInfo returns the filesystem UUID and its current transaction generation.
+// The generation is 0 on kernels that can't report it (before 5.10).
+func FSInfo(b privsep.Backend, f *os.File) (string, uint64, error) {
+	args := btrfsIoctlFsInfoArgs{Flags: fsInfoFlagGeneration}
+	if err := b.Ioctl(f, ioctlFsInfo, &args); err != nil {
+		return "", 0, fmt.Errorf("fs info ioctl: %w", err)
+	}
+	var gen uint64
+	if args.Flags&fsInfoFlagGeneration != 0 {
+		gen = args.Generation
+	}
+	return uuid.UUID(args.FSID).String(), gen, nil
+}
+
+// fsInfo returns the raw BTRFS_IOC_FS_INFO result
+func fsInfo(b privsep.Backend, f *os.File) (*btrfsIoctlFsInfoArgs, error) {
+	var args btrfsIoctlFsInfoArgs
+	if err := b.Ioctl(f, ioctlFsInfo, &args); err != nil {
+		return nil, fmt.Errorf("fs info ioctl: %w", err)
+	}
+	return &args, nil
+}
+
+// devPath returns the path of device devID, or "" if it's missing
+func devPath(b privsep.Backend, f *os.File, devID uint64) (string, error) {
+	args := btrfsIoctlDevInfoArgs{DevID: devID}
+	if err := b.Ioctl(f, ioctlDevInfo, &args); err != nil {
+		return "", fmt.Errorf("dev info ioctl: %w", err)
+	}
+	name := args.Path[:]
+	if n := bytes.IndexByte(name, 0); n >= 0 {
+		name = name[:n]
+	}
+	return string(name), nil
+}
+
+// firstFreeObjectID is BTRFS_FIRST_FREE_OBJECTID, the root directory of a subvolume
+const firstFreeObjectID = 256
+
+// RootID returns the ID of the subvolume tree that holds f
+func RootID(b privsep.Backend, f *os.File) (uint64, error) {
+	args := btrfsIoctlInoLookupArgs{ObjectID: firstFreeObjectID}
+	if err := b.Ioctl(f, ioctlInoLookup, &args); err != nil {
+		return 0, fmt.Errorf("ino lookup ioctl: %w", err)
+	}
+	return args.TreeID, nil
+}
 
 // TreeSearch performs a tree search ioctl
-func TreeSearch(f *os.File, treeID uint64, minObjID, maxObjID uint64, minType, maxType uint32, minOffset, maxOffset uint64) ([]SearchResult, error) {
+func TreeSearch(b privsep.Backend, f *os.File, treeID uint64, minObjID, maxObjID uint64, minType, maxType uint32, minOffset, maxOffset uint64) ([]SearchResult, error) {
 	var results []SearchResult
 	ioctlCount := 0
 	totalIoctlTime := time.Duration(0)
@@ -145,7 +247,7 @@ func TreeSearch(f *os.File, treeID uint64, minObjID, maxObjID uint64, minType, m
 
 	for {
 		ioctlStart := time.Now()
-		err := ioctl.Do(f, ioctlTreeSearch, &args)
+		err := b.Ioctl(f, ioctlTreeSearch, &args)
 		totalIoctlTime += time.Since(ioctlStart)
 		ioctlCount++
 		if err != nil {
@@ -0,0 +1,285 @@
+package fragmap
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/elee1766/gobtr/pkg/btrfs"
+	"github.com/elee1766/gobtr/pkg/privsep"
+)
+
+// extentFlagData is BTRFS_EXTENT_FLAG_DATA, set on data extent items
+const extentFlagData = 1 << 0
+
+// logicalInoBufSize is the result buffer for LOGICAL_INO_V2. Each reference
+// takes 24 bytes, so this holds about 2700 references per extent.
+const logicalInoBufSize = 64 << 10
+
+// ExtentOwner is one file reference to a data extent
+type ExtentOwner struct {
+	Root   uint64 // Subvolume tree ID
+	Inode  uint64
+	Offset uint64 // Offset in the file where the reference starts
+	// Path relative to the top-level subvolume, or empty if the inode could
+	// not be resolved (deleted, or in a subvolume being dropped)
+	Path string
+}
+
+// LogicalExtent is one data extent in a logical range
+type LogicalExtent struct {
+	Logical uint64
+	Length  uint64
+	Refs    uint64
+	Owners  []ExtentOwner
+	// More references than fit in the LOGICAL_INO buffer
+	OwnersTruncated bool
+}
+
+// FileUsage sums the extents of one file in a logical range
+type FileUsage struct {
+	Path    string
+	Root    uint64
+	Inode   uint64
+	Extents int
+	// Length of the extents it references, whole extents even if it only
+	// references part of one
+	Bytes uint64
+	// Extents also referenced by other files or snapshots
+	SharedExtents int
+}
+
+// LogicalRange is what occupies a range of logical addresses
+type LogicalRange struct {
+	Start uint64
+	End   uint64
+
+	DataExtents     int
+	DataBytes       uint64
+	MetadataExtents int
+	MetadataBytes   uint64
+
+	// Data extents with their owners, in address order
+	Extents []LogicalExtent
+	// Files in the range, most bytes first
+	Files []FileUsage
+	// Only the first MaxExtents data extents were resolved to files
+	Truncated bool
+}
+
+// LogicalRangeOptions controls ResolveLogicalRange
+type LogicalRangeOptions struct {
+	// Data extents to resolve to files (default 1000). Every extent is still
+	// counted.
+	MaxExtents int
+}
+
+// ResolveLogicalRange lists the extents allocated in [start, end) and the
+// files that reference them, using the extent tree and LOGICAL_INO. This is
+// what keeps a block group from being freed: a balance moves exactly these
+// extents. Needs CAP_SYS_ADMIN.
+func (s *Scanner) ResolveLogicalRange(ctx context.Context, start, end uint64, opts LogicalRangeOptions) (*LogicalRange, error) {
+	if end <= start {
+		return nil, fmt.Errorf("empty range")
+	}
+	if opts.MaxExtents <= 0 {
+		opts.MaxExtents = 1000
+	}
+
+	items, err := TreeSearch(s.backend, s.file, ExtentTreeObjectID,
+		start, end-1,
+		ExtentItemKey, MetadataItemKey,
+		0, ^uint64(0))
+	if err != nil {
+		return nil, err
+	}
+
+	var nodeSize uint64
+	r := &LogicalRange{Start: start, End: end}
+	for _, item := range items {
+		var length, refs, flags uint64
+		if len(item.Data) >= 24 {
+			refs = binary.LittleEndian.Uint64(item.Data[0:])
+			flags = binary.LittleEndian.Uint64(item.Data[16:])
+		}
+		switch item.Header.Type {
+		case ExtentItemKey:
+			length = item.Header.Offset
+		case MetadataItemKey:
+			// Skinny metadata items store the tree level in the offset
+			if nodeSize == 0 {
+				if nodeSize, err = s.nodeSize(); err != nil {
+					return nil, err
+				}
+			}
+			length = nodeSize
+		default:
+			continue
+		}
+
+		if flags&extentFlagData == 0 {
+			r.MetadataExtents++
+			r.MetadataBytes += length
+			continue
+		}
+		r.DataExtents++
+		r.DataBytes += length
+		if len(r.Extents) >= opts.MaxExtents {
+			r.Truncated = true
+			continue
+		}
+		r.Extents = append(r.Extents, LogicalExtent{
+			Logical: item.Header.ObjectID,
+			Length:  length,
+			Refs:    refs,
+		})
+	}
+
+	resolver := &pathResolver{s: s, paths: make(map[[2]uint64]string)}
+	files := make(map[[2]uint64]*FileUsage)
+	for i := range r.Extents {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		ext := &r.Extents[i]
+		owners, truncated, err := logicalIno(s.backend, s.file, ext.Logical)
+		if err != nil {
+			// Extents freed since the tree search are gone, not an error
+			continue
+		}
+		ext.OwnersTruncated = truncated
+
+		for _, o := range owners {
+			o.Path = resolver.path(o.Root, o.Inode)
+			ext.Owners = append(ext.Owners, o)
+
+			key := [2]uint64{o.Root, o.Inode}
+			fu := files[key]
+			if fu == nil {
+				fu = &FileUsage{Path: o.Path, Root: o.Root, Inode: o.Inode}
+				files[key] = fu
+			}
+			fu.Extents++
+			fu.Bytes += ext.Length
+			if len(owners) > 1 || truncated {
+				fu.SharedExtents++
+			}
+		}
+	}
+
+	for _, fu := range files {
+		r.Files = append(r.Files, *fu)
+	}
+	sort.Slice(r.Files, func(i, j int) bool {
+		if r.Files[i].Bytes != r.Files[j].Bytes {
+			return r.Files[i].Bytes > r.Files[j].Bytes
+		}
+		return r.Files[i].Path < r.Files[j].Path
+	})
+
+	return r, nil
+}
+
+// DeviceOffsetToChunk finds the chunk with a device extent covering a
+// physical offset on a device
+func (fm *FragMap) DeviceOffsetToChunk(deviceID, physical uint64) (*Chunk, error) {
+	for _, ext := range fm.DeviceExtents[deviceID] {
+		if physical < ext.PhysicalOffset || physical >= ext.PhysicalOffset+ext.Length {
+			continue
+		}
+		for i := range fm.Chunks {
+			if fm.Chunks[i].LogicalOffset == ext.ChunkOffset {
+				return &fm.Chunks[i], nil
+			}
+		}
+		return nil, fmt.Errorf("chunk %d not found", ext.ChunkOffset)
+	}
+	return nil, fmt.Errorf("offset %d on device %d is not allocated", physical, deviceID)
+}
+
+// LogicalToChunk finds the chunk containing a logical address
+func (fm *FragMap) LogicalToChunk(logical uint64) (*Chunk, error) {
+	for i := range fm.Chunks {
+		c := &fm.Chunks[i]
+		if logical >= c.LogicalOffset && logical < c.LogicalOffset+c.Length {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("logical address %d is not in any chunk", logical)
+}
+
+// nodeSize reads the metadata node size, which skinny metadata items leave out
+func (s *Scanner) nodeSize() (uint64, error) {
+	info, err := fsInfo(s.backend, s.file)
+	if err != nil {
+		return 0, fmt.Errorf("get filesystem info: %w", err)
+	}
+	return uint64(info.NodeSize), nil
+}
+
+// logicalIno returns every file reference to the extent at logical
+func logicalIno(b privsep.Backend, f *os.File, logical uint64) ([]ExtentOwner, bool, error) {
+	buf := make([]byte, logicalInoBufSize)
+	if err := b.LogicalIno(f, logical, logicalInoIgnoreOffset, buf); err != nil {
+		return nil, false, fmt.Errorf("logical_ino ioctl: %w", err)
+	}
+
+	// struct btrfs_data_container: bytes_left, bytes_missing, elem_cnt,
+	// elem_missed, then (inum, offset, root) triples
+	elemCnt := binary.LittleEndian.Uint32(buf[8:])
+	elemMissed := binary.LittleEndian.Uint32(buf[12:])
+
+	var owners []ExtentOwner
+	for off := 16; elemCnt >= 3 && off+24 <= len(buf); off += 24 {
+		owners = append(owners, ExtentOwner{
+			Inode:  binary.LittleEndian.Uint64(buf[off:]),
+			Offset: binary.LittleEndian.Uint64(buf[off+8:]),
+			Root:   binary.LittleEndian.Uint64(buf[off+16:]),
+		})
+		elemCnt -= 3
+	}
+	return owners, elemMissed > 0, nil
+}
+
+// pathResolver turns (root, inode) pairs into paths relative to the
+// top-level subvolume, caching subvolume paths and lookups
+type pathResolver struct {
+	s       *Scanner
+	subvols map[uint64]string
+	loaded  bool
+	paths   map[[2]uint64]string
+}
+
+func (r *pathResolver) path(root, inode uint64) string {
+	key := [2]uint64{root, inode}
+	if p, ok := r.paths[key]; ok {
+		return p
+	}
+
+	var p string
+	args := btrfsIoctlInoLookupArgs{TreeID: root, ObjectID: inode}
+	if err := r.s.backend.Ioctl(r.s.file, ioctlInoLookup, &args); err == nil {
+		name := args.Name[:]
+		if n := strings.IndexByte(string(name), 0); n >= 0 {
+			name = name[:n]
+		}
+		p = strings.TrimSuffix(string(name), "/")
+		if sv := r.subvolPath(root); sv != "" {
+			p = sv + "/" + p
+		}
+		p = "/" + p
+	}
+	r.paths[key] = p
+	return p
+}
+
+func (r *pathResolver) subvolPath(root uint64) string {
+	if !r.loaded {
+		r.loaded = true
+		r.subvols, _ = btrfs.SubvolumePaths(r.s.backend, r.s.fsPath)
+	}
+	return r.subvols[root]
+}
@@ -0,0 +1,367 @@
+package fragmap
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+// RenderKind is what a rendered image shows
+type RenderKind string
+
+const (
+	// RenderLayout draws every device extent along the device, colored by type
+	RenderLayout RenderKind = "layout"
+	// RenderHeatMap draws HeatMapData cells, shaded by how much is allocated
+	RenderHeatMap RenderKind = "heatmap"
+)
+
+// RenderFormat is the image format of a rendered image
+type RenderFormat string
+
+const (
+	RenderPNG RenderFormat = "png"
+	RenderSVG RenderFormat = "svg"
+)
+
+// ContentType returns the MIME type of the format
+func (f RenderFormat) ContentType() string {
+	if f == RenderSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// RenderFormatFromPath picks the format from a file extension
+func RenderFormatFromPath(path string) (RenderFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		return RenderPNG, nil
+	case ".svg":
+		return RenderSVG, nil
+	default:
+		return "", fmt.Errorf("unknown image format %q, use .png or .svg", filepath.Ext(path))
+	}
+}
+
+// RenderOptions controls Render
+type RenderOptions struct {
+	Kind   RenderKind   // Default RenderLayout
+	Format RenderFormat // Default RenderPNG
+	// Image width in pixels (default 1024)
+	Width int
+	// Heat map cells per device (default 1024)
+	Resolution int
+	// Heat map cell arrangement. The default, linear, wraps the cells into
+	// rows about four times wider than tall.
+	Layout HeatMapLayout
+	// Only render this device (0 = all devices)
+	DeviceID uint64
+}
+
+const (
+	renderMargin      = 8
+	renderLabelHeight = 18
+	renderRowHeight   = 4
+)
+
+var (
+	renderBackground = color.RGBA{255, 255, 255, 255}
+	renderFree       = color.RGBA{229, 231, 235, 255}
+	renderData       = color.RGBA{59, 130, 246, 255}
+	renderMetadata   = color.RGBA{168, 85, 247, 255}
+	renderSystem     = color.RGBA{249, 115, 22, 255}
+)
+
+// renderRect is a filled rectangle, optionally with a tooltip in SVG output
+type renderRect struct {
+	x, y, w, h int
+	c          color.RGBA
+	title      string
+}
+
+type renderLabel struct {
+	x, y int
+	text string
+}
+
+// renderCanvas is a format independent drawing, so PNG and SVG output match
+type renderCanvas struct {
+	width, height int
+	rects         []renderRect
+	labels        []renderLabel
+}
+
+// Render draws the device layout or heat map of every device (or just
+// opts.DeviceID) stacked vertically. Labels and the legend are only drawn in
+// SVG output, since the standard library has no fonts to draw them into a PNG.
+func Render(w io.Writer, fm *FragMap, opts RenderOptions) error {
+	if opts.Kind == "" {
+		opts.Kind = RenderLayout
+	}
+	if opts.Format == "" {
+		opts.Format = RenderPNG
+	}
+	if opts.Width <= 0 {
+		opts.Width = 1024
+	}
+	if opts.Width < 64 {
+		opts.Width = 64
+	}
+	if opts.Resolution <= 0 {
+		opts.Resolution = 1024
+	}
+
+	var maps []*DeviceBlockMap
+	var paths []string
+	for _, dev := range fm.Devices {
+		if opts.DeviceID != 0 && dev.ID != opts.DeviceID {
+			continue
+		}
+		bm, err := fm.BuildDeviceBlockMap(dev.ID)
+		if err != nil {
+			continue
+		}
+		maps = append(maps, bm)
+		paths = append(paths, dev.Path)
+	}
+	if len(maps) == 0 {
+		if opts.DeviceID != 0 {
+			return fmt.Errorf("device %d not found", opts.DeviceID)
+		}
+		return fmt.Errorf("no devices to render")
+	}
+
+	cv := &renderCanvas{width: opts.Width}
+	y := renderMargin
+	for i, bm := range maps {
+		cv.labels = append(cv.labels, renderLabel{
+			x:    renderMargin,
+			y:    y + renderLabelHeight - 5,
+			text: fmt.Sprintf("devid %d %s (%s)", bm.DeviceID, paths[i], humanize.IBytes(bm.TotalSize)),
+		})
+		y += renderLabelHeight
+
+		switch opts.Kind {
+		case RenderLayout:
+			y = cv.drawLayout(bm, y)
+		case RenderHeatMap:
+			cells := bm.HeatMapData(opts.Layout.GridResolution(opts.Resolution))
+			y = cv.drawHeatMap(cells, opts.Layout, y)
+		default:
+			return fmt.Errorf("unknown render kind %q", opts.Kind)
+		}
+		y += renderMargin
+	}
+	if opts.Format == RenderSVG {
+		y = cv.drawLegend(y)
+	}
+	cv.height = y + renderMargin
+
+	switch opts.Format {
+	case RenderPNG:
+		return cv.writePNG(w)
+	case RenderSVG:
+		return cv.writeSVG(w)
+	default:
+		return fmt.Errorf("unknown render format %q", opts.Format)
+	}
+}
+
+// drawLayout maps the device address space onto rows of pixels, left to
+// right and top to bottom, and returns the y below it
+func (cv *renderCanvas) drawLayout(bm *DeviceBlockMap, y int) int {
+	cols := cv.width - 2*renderMargin
+	rows := max(cols/4/renderRowHeight, 1)
+	pixels := uint64(cols * rows)
+	bytesPerPixel := float64(bm.TotalSize) / float64(pixels)
+	if bytesPerPixel <= 0 {
+		bytesPerPixel = 1
+	}
+
+	// Merge neighbouring entries of the same color so tiny extents don't
+	// turn into thousands of SVG elements
+	var curColor color.RGBA
+	var curStart, curEnd int
+	flush := func() {
+		if curEnd > curStart {
+			cv.fillSpan(curStart, curEnd, cols, renderMargin, y, curColor)
+		}
+	}
+	for _, e := range bm.Entries {
+		start := int(math.Floor(float64(e.Offset) / bytesPerPixel))
+		end := int(math.Ceil(float64(e.Offset+e.Length) / bytesPerPixel))
+		end = min(end, int(pixels))
+		if end <= start {
+			continue
+		}
+
+		c := renderFree
+		if e.Allocated {
+			c = typeColor(e.Type)
+		}
+		if c == curColor && start <= curEnd {
+			curEnd = max(curEnd, end)
+			continue
+		}
+		flush()
+		curColor = c
+		curStart = max(start, curEnd)
+		curEnd = end
+	}
+	flush()
+
+	return y + rows*renderRowHeight
+}
+
+// fillSpan fills pixels [start, end) of a row-major strip with at most three
+// rectangles: the partial first row, the full rows and the partial last row
+func (cv *renderCanvas) fillSpan(start, end, cols, x0, y0 int, c color.RGBA) {
+	for start < end {
+		row, col := start/cols, start%cols
+		if col != 0 || end-start < cols {
+			n := min(cols-col, end-start)
+			cv.rects = append(cv.rects, renderRect{x0 + col, y0 + row*renderRowHeight, n, renderRowHeight, c, ""})
+			start += n
+			continue
+		}
+		full := (end - start) / cols
+		cv.rects = append(cv.rects, renderRect{x0, y0 + row*renderRowHeight, cols, full * renderRowHeight, c, ""})
+		start += full * cols
+	}
+}
+
+// drawHeatMap lays the cells out in a grid and returns the y below it
+func (cv *renderCanvas) drawHeatMap(cells []HeatMapCell, layout HeatMapLayout, y int) int {
+	inner := cv.width - 2*renderMargin
+
+	var cols, rows int
+	switch layout {
+	case LayoutRowMajor, LayoutHilbert:
+		cols, rows = HeatMapGrid(cells, layout)
+	default:
+		cols = min(int(math.Ceil(math.Sqrt(float64(len(cells))*4))), inner)
+		rows = (len(cells) + cols - 1) / cols
+		for i := range cells {
+			cells[i].X, cells[i].Y = i%cols, i/cols
+		}
+	}
+	// Square grids would be as tall as the image is wide, so keep them to
+	// half of that
+	size := max(inner/cols, 1)
+	if rows > cols/4 {
+		size = max(min(size, inner/2/rows), 1)
+	}
+
+	for _, cell := range cells {
+		cv.rects = append(cv.rects, renderRect{
+			x: renderMargin + cell.X*size,
+			y: y + cell.Y*size,
+			w: size,
+			h: size,
+			c: heatColor(&cell),
+			title: fmt.Sprintf("%s-%s: %.0f%% allocated",
+				humanize.IBytes(cell.StartOffset), humanize.IBytes(cell.EndOffset), cell.Utilization*100),
+		})
+	}
+
+	return y + rows*size
+}
+
+func (cv *renderCanvas) drawLegend(y int) int {
+	x := renderMargin
+	for _, item := range []struct {
+		name string
+		c    color.RGBA
+	}{
+		{"data", renderData},
+		{"metadata", renderMetadata},
+		{"system", renderSystem},
+		{"free", renderFree},
+	} {
+		cv.rects = append(cv.rects, renderRect{x, y + 3, 10, 10, item.c, ""})
+		cv.labels = append(cv.labels, renderLabel{x + 14, y + 12, item.name})
+		x += 14 + 8*len(item.name) + 12
+	}
+	return y + renderLabelHeight
+}
+
+func typeColor(t BlockType) color.RGBA {
+	switch {
+	case t&BlockTypeData != 0:
+		return renderData
+	case t&BlockTypeMetadata != 0:
+		return renderMetadata
+	case t&BlockTypeSystem != 0:
+		return renderSystem
+	default:
+		return renderFree
+	}
+}
+
+// heatColor shades the cell's dominant type from light to full color by
+// how much of the cell is allocated
+func heatColor(cell *HeatMapCell) color.RGBA {
+	if cell.AllocatedBytes == 0 {
+		return renderFree
+	}
+	base := renderData
+	if cell.MetadataBytes > cell.DataBytes && cell.MetadataBytes >= cell.SystemBytes {
+		base = renderMetadata
+	} else if cell.SystemBytes > cell.DataBytes && cell.SystemBytes > cell.MetadataBytes {
+		base = renderSystem
+	}
+
+	mix := func(b uint8) uint8 {
+		light := float64(b) + (245-float64(b))*0.7
+		return uint8(light + (float64(b)-light)*min(cell.Utilization, 1))
+	}
+	return color.RGBA{mix(base.R), mix(base.G), mix(base.B), 255}
+}
+
+func (cv *renderCanvas) writePNG(w io.Writer) error {
+	img := image.NewRGBA(image.Rect(0, 0, cv.width, cv.height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(renderBackground), image.Point{}, draw.Src)
+	for _, r := range cv.rects {
+		draw.Draw(img, image.Rect(r.x, r.y, r.x+r.w, r.y+r.h), image.NewUniform(r.c), image.Point{}, draw.Src)
+	}
+	return png.Encode(w, img)
+}
+
+func (cv *renderCanvas) writeSVG(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n",
+		cv.width, cv.height, cv.width, cv.height)
+	fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hexColor(renderBackground))
+	for _, r := range cv.rects {
+		fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"`, r.x, r.y, r.w, r.h, hexColor(r.c))
+		if r.title == "" {
+			bw.WriteString("/>\n")
+			continue
+		}
+		bw.WriteString("><title>")
+		xml.EscapeText(bw, []byte(r.title))
+		bw.WriteString("</title></rect>\n")
+	}
+	for _, l := range cv.labels {
+		fmt.Fprintf(bw, `<text x="%d" y="%d" font-family="monospace" font-size="12" fill="#374151">`, l.x, l.y)
+		xml.EscapeText(bw, []byte(l.text))
+		bw.WriteString("</text>\n")
+	}
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
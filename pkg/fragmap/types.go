@@ -1,5 +1,7 @@
 package fragmap
 
+import "time"
+
 // BlockType represents the type of data in a block/chunk
 type BlockType uint64
 
@@ -68,6 +70,12 @@ type Device struct {
 
 // FragMap represents the complete fragmentation map of a filesystem
 type FragMap struct {
+	// Filesystem UUID
+	UUID string
+	// Transaction generation when the scan started (0 if unknown)
+	Generation uint64
+	// When the scan was taken
+	ScannedAt time.Time
 	// Total filesystem size (logical)
 	TotalSize uint64
 	// Devices in the filesystem
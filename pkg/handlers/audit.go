@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"connectrpc.com/connect"
+	apiv1 "github.com/elee1766/gobtr/gen/api/v1"
+	"github.com/elee1766/gobtr/pkg/audit"
+	"github.com/elee1766/gobtr/pkg/db/queries"
+)
+
+type AuditHandler struct {
+	logger *slog.Logger
+	audit  *audit.Log
+}
+
+func NewAuditHandler(logger *slog.Logger, auditLog *audit.Log) *AuditHandler {
+	return &AuditHandler{
+		logger: logger.With("handler", "audit"),
+		audit:  auditLog,
+	}
+}
+
+func (h *AuditHandler) ListAuditEvents(
+	ctx context.Context,
+	req *connect.Request[apiv1.ListAuditEventsRequest],
+) (*connect.Response[apiv1.ListAuditEventsResponse], error) {
+	h.logger.Debug("list audit events", "filesystem", req.Msg.Filesystem, "actor", req.Msg.Actor)
+
+	limit := int(req.Msg.Limit)
+	switch {
+	case limit < 0:
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("limit must not be negative"))
+	case limit == 0:
+		limit = 100
+	case limit > 1000:
+		limit = 1000
+	}
+
+	f := queries.AuditFilter{
+		Filesystem: req.Msg.Filesystem,
+		Actor:      req.Msg.Actor,
+		Procedure:  req.Msg.Procedure,
+		BeforeID:   req.Msg.BeforeId,
+		Limit:      limit,
+	}
+	if req.Msg.Since > 0 {
+		f.Since = time.Unix(req.Msg.Since, 0)
+	}
+	if req.Msg.Until > 0 {
+		f.Until = time.Unix(req.Msg.Until, 0)
+	}
+
+	events, err := h.audit.List(f)
+	if err != nil {
+		h.logger.Error("failed to list audit events", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	resp := &apiv1.ListAuditEventsResponse{}
+	for _, e := range events {
+		resp.Events = append(resp.Events, &apiv1.AuditEvent{
+			Id:          e.ID,
+			Timestamp:   e.Timestamp.Unix(),
+			Actor:       e.Actor,
+			AuthMethod:  e.AuthMethod,
+			Role:        e.Role,
+			Peer:        e.Peer,
+			Procedure:   e.Procedure,
+			Filesystem:  e.Filesystem,
+			RequestJson: e.Request,
+			Outcome:     e.Outcome,
+			Error:       e.Error,
+			DurationMs:  e.Duration.Milliseconds(),
+		})
+	}
+	if len(events) == limit {
+		resp.NextBeforeId = events[len(events)-1].ID
+	}
+	return connect.NewResponse(resp), nil
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	apiv1 "github.com/elee1766/gobtr/gen/api/v1"
+	"github.com/elee1766/gobtr/pkg/auth"
+	"github.com/elee1766/gobtr/pkg/db/queries"
+)
+
+type AuthHandler struct {
+	logger *slog.Logger
+	auth   *auth.Authenticator
+}
+
+func NewAuthHandler(logger *slog.Logger, authenticator *auth.Authenticator) *AuthHandler {
+	return &AuthHandler{
+		logger: logger.With("handler", "auth"),
+		auth:   authenticator,
+	}
+}
+
+func (h *AuthHandler) GetSession(
+	ctx context.Context,
+	req *connect.Request[apiv1.GetSessionRequest],
+) (*connect.Response[apiv1.GetSessionResponse], error) {
+	resp := &apiv1.GetSessionResponse{AuthEnabled: h.auth.Enabled()}
+	if id := auth.FromContext(ctx); id != nil {
+		resp.Session = sessionToProto(id)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *AuthHandler) Login(
+	ctx context.Context,
+	req *connect.Request[apiv1.LoginRequest],
+) (*connect.Response[apiv1.LoginResponse], error) {
+	if !h.auth.Enabled() {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("auth is disabled"))
+	}
+
+	token, id, expires, err := h.auth.Login(req.Msg.Username, req.Msg.Password)
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		h.logger.Warn("failed login", "user", req.Msg.Username, "peer", req.Peer().Addr)
+		return nil, connect.NewError(connect.CodeUnauthenticated, err)
+	}
+	if err != nil {
+		h.logger.Error("login failed", "user", req.Msg.Username, "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	resp := connect.NewResponse(&apiv1.LoginResponse{
+		Session:   sessionToProto(id),
+		ExpiresAt: expires.Unix(),
+	})
+	resp.Header().Add("Set-Cookie", auth.NewSessionCookie(token, expires, auth.IsSecure(ctx)).String())
+	return resp, nil
+}
+
+func (h *AuthHandler) Logout(
+	ctx context.Context,
+	req *connect.Request[apiv1.LogoutRequest],
+) (*connect.Response[apiv1.LogoutResponse], error) {
+	r := &http.Request{Header: req.Header()}
+	if cookie, err := r.Cookie(auth.SessionCookie); err == nil && cookie.Value != "" {
+		if err := h.auth.Logout(cookie.Value); err != nil {
+			h.logger.Error("failed to end session", "error", err)
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+	}
+
+	resp := connect.NewResponse(&apiv1.LogoutResponse{})
+	resp.Header().Add("Set-Cookie", auth.NewSessionCookie("", time.Time{}, auth.IsSecure(ctx)).String())
+	return resp, nil
+}
+
+func (h *AuthHandler) ListUsers(
+	ctx context.Context,
+	req *connect.Request[apiv1.ListUsersRequest],
+) (*connect.Response[apiv1.ListUsersResponse], error) {
+	users, err := h.auth.ListUsers()
+	if err != nil {
+		h.logger.Error("failed to list users", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	resp := &apiv1.ListUsersResponse{}
+	for _, u := range users {
+		resp.Users = append(resp.Users, userToProto(u))
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *AuthHandler) CreateUser(
+	ctx context.Context,
+	req *connect.Request[apiv1.CreateUserRequest],
+) (*connect.Response[apiv1.CreateUserResponse], error) {
+	role, err := auth.ParseRole(req.Msg.Role)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	user, err := h.auth.CreateUser(req.Msg.Username, req.Msg.Password, role)
+	if err != nil {
+		return nil, userError(err)
+	}
+	return connect.NewResponse(&apiv1.CreateUserResponse{User: userToProto(user)}), nil
+}
+
+func (h *AuthHandler) UpdateUser(
+	ctx context.Context,
+	req *connect.Request[apiv1.UpdateUserRequest],
+) (*connect.Response[apiv1.UpdateUserResponse], error) {
+	if req.Msg.Role != "" {
+		role, err := auth.ParseRole(req.Msg.Role)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		if err := h.auth.SetRole(req.Msg.Username, role); err != nil {
+			return nil, userError(err)
+		}
+	}
+	if req.Msg.Password != "" {
+		if err := h.auth.SetPassword(req.Msg.Username, req.Msg.Password); err != nil {
+			return nil, userError(err)
+		}
+	}
+	return connect.NewResponse(&apiv1.UpdateUserResponse{}), nil
+}
+
+func (h *AuthHandler) DeleteUser(
+	ctx context.Context,
+	req *connect.Request[apiv1.DeleteUserRequest],
+) (*connect.Response[apiv1.DeleteUserResponse], error) {
+	if err := h.auth.DeleteUser(req.Msg.Username); err != nil {
+		return nil, userError(err)
+	}
+	return connect.NewResponse(&apiv1.DeleteUserResponse{}), nil
+}
+
+func userError(err error) error {
+	switch {
+	case errors.Is(err, auth.ErrInvalid):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	case errors.Is(err, auth.ErrNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	default:
+		return connect.NewError(connect.CodeInternal, err)
+	}
+}
+
+func sessionToProto(id *auth.Identity) *apiv1.Session {
+	return &apiv1.Session{
+		Username: id.Name,
+		Role:     id.Role.String(),
+		Method:   id.Method,
+	}
+}
+
+func userToProto(u *queries.User) *apiv1.User {
+	return &apiv1.User{
+		Username:  u.Username,
+		Role:      u.Role,
+		CreatedAt: u.CreatedAt.Unix(),
+		UpdatedAt: u.UpdatedAt.Unix(),
+	}
+}
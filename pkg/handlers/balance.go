@@ -1,31 +1,52 @@
 package handlers
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
 
 	"connectrpc.com/connect"
 	apiv1 "github.com/elee1766/gobtr/gen/api/v1"
+	"github.com/elee1766/gobtr/pkg/allocsim"
+	"github.com/elee1766/gobtr/pkg/auth"
 	"github.com/elee1766/gobtr/pkg/btrfs"
 	"github.com/elee1766/gobtr/pkg/db"
 	"github.com/elee1766/gobtr/pkg/db/queries"
+	"github.com/elee1766/gobtr/pkg/fragmap"
+	"go.uber.org/fx"
 )
 
 type BalanceHandler struct {
 	logger       *slog.Logger
 	db           *db.DB
 	btrfsManager *btrfs.Manager
+	fragmapCache *fragmap.Cache
+
+	// ctx outlives the requests that start balances, so a balance keeps
+	// running after its RPC returns. It is cancelled when the server stops.
+	ctx context.Context
 }
 
-func NewBalanceHandler(logger *slog.Logger, db *db.DB, btrfsManager *btrfs.Manager) *BalanceHandler {
+func NewBalanceHandler(lc fx.Lifecycle, logger *slog.Logger, db *db.DB, btrfsManager *btrfs.Manager, fragmapCache *fragmap.Cache) *BalanceHandler {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
 	return &BalanceHandler{
 		logger:       logger.With("handler", "balance"),
 		db:           db,
 		btrfsManager: btrfsManager,
+		fragmapCache: fragmapCache,
+		ctx:          ctx,
 	}
 }
 
@@ -68,6 +89,13 @@ func (h *BalanceHandler) recordBalanceToHistory(devicePath string, balanceID str
 		balanceHistory.FlagBackground = opts.Background
 		balanceHistory.FlagDryRun = opts.DryRun
 		balanceHistory.FlagForce = opts.Force
+		balanceHistory.FlagDataConvert = opts.DataConvert
+		balanceHistory.FlagMetadataConvert = opts.MetadataConvert
+		balanceHistory.FlagSystemConvert = opts.SystemConvert
+		balanceHistory.FlagSoft = opts.Soft
+		balanceHistory.FlagDevid = int64(opts.Devid)
+		balanceHistory.FlagDrangeStart = int64(opts.DrangeStart)
+		balanceHistory.FlagDrangeEnd = int64(opts.DrangeEnd)
 		// Clean up flags when balance is done
 		if status.Status != "running" && status.Status != "starting" && status.Status != "paused" {
 			delete(currentBalanceFlags, devicePath)
@@ -84,6 +112,32 @@ func (h *BalanceHandler) recordBalanceToHistory(devicePath string, balanceID str
 	}
 }
 
+// watchBalance waits for a balance to finish and records its final status
+func (h *BalanceHandler) watchBalance(devicePath string, balanceID string) {
+	err := h.btrfsManager.WaitBalance(context.Background(), balanceID)
+
+	status := &btrfs.BalanceStatus{
+		Status:     "completed",
+		FinishedAt: time.Now(),
+	}
+	if errors.Is(err, context.Canceled) {
+		status.Status = "cancelled"
+	} else if err != nil {
+		status.Status = "failed"
+	}
+
+	// Keep the progress counters recorded while the balance was running
+	if prev, err := queries.GetBalance(h.db.Conn(), balanceID); err == nil {
+		status.StartedAt = prev.StartedAt
+		status.Considered = prev.ChunksConsidered
+		status.Relocated = prev.ChunksRelocated
+		status.SizeRelocated = prev.SizeRelocated
+		status.SoftErrors = prev.SoftErrors
+	}
+
+	h.recordBalanceToHistory(devicePath, balanceID, status)
+}
+
 // statusToProto converts btrfs.BalanceStatus to apiv1.BalanceProgress
 func balanceStatusToProto(status *btrfs.BalanceStatus) *apiv1.BalanceProgress {
 	progress := &apiv1.BalanceProgress{
@@ -140,34 +194,69 @@ func (h *BalanceHandler) StartBalance(
 		opts.System = req.Msg.Filters.System
 		opts.UsagePercent = req.Msg.Filters.UsagePercent
 		opts.LimitChunks = req.Msg.Filters.LimitChunks
+		opts.DataConvert = req.Msg.Filters.DataConvert
+		opts.MetadataConvert = req.Msg.Filters.MetadataConvert
+		opts.SystemConvert = req.Msg.Filters.SystemConvert
+		opts.Soft = req.Msg.Filters.Soft
+		opts.Devid = req.Msg.Filters.Devid
+		opts.DrangeStart = req.Msg.Filters.DrangeStart
+		opts.DrangeEnd = req.Msg.Filters.DrangeEnd
+	}
+
+	if opts.DrangeEnd > 0 && opts.Devid == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("drange requires devid"))
+	}
+
+	balanceID, err := h.startBalance(ctx, req.Msg.DevicePath, opts)
+	if err != nil {
+		h.logger.Error("failed to start balance", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&apiv1.StartBalanceResponse{
+		BalanceId: balanceID,
+		Started:   true,
+	}), nil
+}
+
+// StartScheduled starts a balance for the scheduler, tracked in the history
+// like one started over the API
+func (h *BalanceHandler) StartScheduled(ctx context.Context, devicePath string, opts btrfs.BalanceOptions) (string, error) {
+	return h.startBalance(ctx, devicePath, opts)
+}
+
+// startBalance starts a balance and tracks it in the history. ctx only
+// bounds starting it; the balance itself runs until it finishes, is
+// cancelled or the server stops.
+func (h *BalanceHandler) startBalance(ctx context.Context, devicePath string, opts btrfs.BalanceOptions) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
 	}
 
 	// Store flags for recording to history later
 	balanceFlagsMutex.Lock()
-	currentBalanceFlags[req.Msg.DevicePath] = &opts
+	currentBalanceFlags[devicePath] = &opts
 	balanceFlagsMutex.Unlock()
 
 	// Start the balance
-	balanceID, err := h.btrfsManager.StartBalance(ctx, req.Msg.DevicePath, opts)
+	balanceID, err := h.btrfsManager.StartBalance(h.ctx, devicePath, opts)
 	if err != nil {
-		h.logger.Error("failed to start balance", "error", err)
 		// Clean up flags on error
 		balanceFlagsMutex.Lock()
-		delete(currentBalanceFlags, req.Msg.DevicePath)
+		delete(currentBalanceFlags, devicePath)
 		balanceFlagsMutex.Unlock()
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return "", err
 	}
 
 	// Record initial history entry
-	h.recordBalanceToHistory(req.Msg.DevicePath, balanceID, &btrfs.BalanceStatus{
+	h.recordBalanceToHistory(devicePath, balanceID, &btrfs.BalanceStatus{
 		Status:    "starting",
 		StartedAt: time.Now(),
 	})
 
-	return connect.NewResponse(&apiv1.StartBalanceResponse{
-		BalanceId: balanceID,
-		Started:   true,
-	}), nil
+	go h.watchBalance(devicePath, balanceID)
+
+	return balanceID, nil
 }
 
 func (h *BalanceHandler) CancelBalance(
@@ -268,11 +357,18 @@ func (h *BalanceHandler) ListBalanceHistory(
 			SoftErrors:      b.SoftErrors,
 			Flags: &apiv1.BalanceFlags{
 				Filters: &apiv1.BalanceFilters{
-					Data:         b.FlagData,
-					Metadata:     b.FlagMetadata,
-					System:       b.FlagSystem,
-					UsagePercent: b.FlagUsagePercent,
-					LimitChunks:  b.FlagLimitChunks,
+					Data:            b.FlagData,
+					Metadata:        b.FlagMetadata,
+					System:          b.FlagSystem,
+					UsagePercent:    b.FlagUsagePercent,
+					LimitChunks:     b.FlagLimitChunks,
+					DataConvert:     b.FlagDataConvert,
+					MetadataConvert: b.FlagMetadataConvert,
+					SystemConvert:   b.FlagSystemConvert,
+					Soft:            b.FlagSoft,
+					Devid:           uint64(b.FlagDevid),
+					DrangeStart:     uint64(b.FlagDrangeStart),
+					DrangeEnd:       uint64(b.FlagDrangeEnd),
 				},
 				LimitPercent: b.FlagLimitPercent,
 				Background:   b.FlagBackground,
@@ -337,3 +433,322 @@ func (h *BalanceHandler) GetAllBalanceStatus(
 		Filesystems: results,
 	}), nil
 }
+
+func chunkProfileUsageToProto(u *btrfs.ChunkProfileUsage) *apiv1.ChunkProfileUsage {
+	if u == nil {
+		return nil
+	}
+	return &apiv1.ChunkProfileUsage{
+		Type:    u.Type,
+		Profile: u.Profile,
+		Chunks:  int64(u.Chunks),
+		Bytes:   int64(u.Bytes),
+	}
+}
+
+func (h *BalanceHandler) GetProfileStatus(
+	ctx context.Context,
+	req *connect.Request[apiv1.GetProfileStatusRequest],
+) (*connect.Response[apiv1.GetProfileStatusResponse], error) {
+	h.logger.Debug("get profile status", "device", req.Msg.DevicePath)
+
+	if req.Msg.DevicePath == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("device_path is required"))
+	}
+
+	usages, err := btrfs.GetChunkProfileUsage(h.btrfsManager.Backend(), req.Msg.DevicePath)
+	if err != nil {
+		h.logger.Error("failed to get chunk profile usage", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	stale, err := btrfs.DetectStaleProfiles(h.btrfsManager.Backend(), req.Msg.DevicePath)
+	if err != nil {
+		h.logger.Error("failed to detect stale profiles", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	resp := &apiv1.GetProfileStatusResponse{}
+	for _, u := range usages {
+		resp.Profiles = append(resp.Profiles, chunkProfileUsageToProto(u))
+	}
+	for _, sp := range stale {
+		s := &apiv1.StaleProfiles{
+			Type:         sp.Type,
+			Target:       sp.Target,
+			TargetSource: sp.TargetSource,
+			Current:      chunkProfileUsageToProto(sp.Current),
+		}
+		for _, u := range sp.Stale {
+			s.Stale = append(s.Stale, chunkProfileUsageToProto(u))
+		}
+		resp.Stale = append(resp.Stale, s)
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+func (h *BalanceHandler) FinishConversion(
+	ctx context.Context,
+	req *connect.Request[apiv1.FinishConversionRequest],
+) (*connect.Response[apiv1.FinishConversionResponse], error) {
+	h.logger.Info("finish conversion", "device", req.Msg.DevicePath,
+		"data_target", req.Msg.DataTarget, "metadata_target", req.Msg.MetadataTarget, "system_target", req.Msg.SystemTarget)
+
+	if req.Msg.DevicePath == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("device_path is required"))
+	}
+
+	if h.btrfsManager.IsBalanceRunning(req.Msg.DevicePath) {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("balance already running on %s", req.Msg.DevicePath))
+	}
+
+	progress, err := btrfs.GetBalanceProgress(h.btrfsManager.Backend(), req.Msg.DevicePath)
+	if err != nil {
+		h.logger.Error("failed to get balance progress", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if progress.IsRunning {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("balance already running on %s", req.Msg.DevicePath))
+	}
+
+	// Detect before cancelling anything, so targets from a paused balance are kept
+	stale, err := btrfs.DetectStaleProfiles(h.btrfsManager.Backend(), req.Msg.DevicePath)
+	if err != nil {
+		h.logger.Error("failed to detect stale profiles", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if len(stale) == 0 {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("no stale profiles on %s", req.Msg.DevicePath))
+	}
+
+	opts := btrfs.BalanceOptions{Soft: true}
+	var guessed []string
+	for _, sp := range stale {
+		var explicit string
+		switch sp.Type {
+		case "Data":
+			explicit = req.Msg.DataTarget
+			opts.DataConvert = cmp.Or(explicit, sp.Target)
+		case "Metadata":
+			explicit = req.Msg.MetadataTarget
+			opts.MetadataConvert = cmp.Or(explicit, sp.Target)
+		case "System":
+			// Converting system chunks explicitly requires -f
+			explicit = req.Msg.SystemTarget
+			opts.SystemConvert = cmp.Or(explicit, sp.Target)
+			opts.Force = true
+		}
+		// Without a paused balance the target is only the most redundant
+		// profile present, which would undo an interrupted downgrade
+		if explicit == "" && sp.TargetSource == "kernel" {
+			guessed = append(guessed, strings.ToLower(sp.Type))
+		}
+	}
+	if len(guessed) > 0 {
+		return nil, connect.NewError(connect.CodeFailedPrecondition,
+			fmt.Errorf("no paused balance says what %s was being converted to; pass the target explicitly", strings.Join(guessed, " and ")))
+	}
+
+	// The kernel refuses to start a new balance while a paused one is registered
+	if progress.IsPaused {
+		if err := h.btrfsManager.CancelPausedBalance(req.Msg.DevicePath); err != nil {
+			h.logger.Error("failed to cancel paused balance", "error", err)
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+	}
+
+	balanceID, err := h.startBalance(ctx, req.Msg.DevicePath, opts)
+	if err != nil {
+		h.logger.Error("failed to start conversion balance", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&apiv1.FinishConversionResponse{
+		BalanceId: balanceID,
+		Started:   true,
+		Filters: &apiv1.BalanceFilters{
+			DataConvert:     opts.DataConvert,
+			MetadataConvert: opts.MetadataConvert,
+			SystemConvert:   opts.SystemConvert,
+			Soft:            opts.Soft,
+		},
+	}), nil
+}
+
+func (h *BalanceHandler) AnalyzeAllocation(
+	ctx context.Context,
+	req *connect.Request[apiv1.AnalyzeAllocationRequest],
+) (*connect.Response[apiv1.AnalyzeAllocationResponse], error) {
+	h.logger.Debug("analyze allocation", "device", req.Msg.DevicePath, "type", req.Msg.Type, "profile", req.Msg.Profile)
+
+	if req.Msg.DevicePath == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("device_path is required"))
+	}
+
+	typ := cmp.Or(req.Msg.Type, "data")
+	if req.Msg.Profile != "" {
+		if _, ok := allocsim.LookupProfile(req.Msg.Profile); !ok {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown profile %q", req.Msg.Profile))
+		}
+	}
+
+	in, err := allocsim.Gather(h.btrfsManager.Backend(), req.Msg.DevicePath, typ, req.Msg.Profile)
+	if err != nil {
+		h.logger.Error("failed to gather allocation info", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	res := allocsim.Analyze(in)
+
+	resp := &apiv1.AnalyzeAllocationResponse{
+		Type:             res.Type,
+		Profile:          res.Profile,
+		Unallocated:      int64(res.Unallocated),
+		Usable:           int64(res.Usable),
+		Stranded:         int64(res.Stranded),
+		BalancedUsable:   int64(res.BalancedUsable),
+		BalancedStranded: int64(res.BalancedStranded),
+		Recoverable:      int64(res.Recoverable),
+	}
+	for _, d := range res.Devices {
+		resp.Devices = append(resp.Devices, &apiv1.DeviceAllocationAnalysis{
+			Devid:            d.DevID,
+			Path:             d.Path,
+			Size:             int64(d.Size),
+			Allocated:        int64(d.Allocated),
+			Unallocated:      int64(d.Unallocated),
+			Stranded:         int64(d.Stranded),
+			BalancedStranded: int64(d.BalancedStranded),
+		})
+	}
+	for _, sg := range res.Suggestions {
+		filters := &apiv1.BalanceFilters{
+			Data:        res.Type == "Data",
+			Metadata:    res.Type == "Metadata",
+			Devid:       sg.DevID,
+			LimitChunks: int64(sg.Limit),
+		}
+		flag := "-d"
+		if filters.Metadata {
+			flag = "-m"
+		}
+		resp.Suggestions = append(resp.Suggestions, &apiv1.AllocationSuggestion{
+			Devid:        sg.DevID,
+			Limit:        int64(sg.Limit),
+			Usable:       int64(sg.Usable),
+			Recovers:     int64(sg.Recovers),
+			DrangeStart:  sg.DrangeStart,
+			DrangeEnd:    sg.DrangeEnd,
+			DrangeChunks: int64(sg.DrangeChunks),
+			Filters:      filters,
+			Command:      fmt.Sprintf("btrfs balance start %sdevid=%d,limit=%d %s", flag, sg.DevID, sg.Limit, req.Msg.DevicePath),
+		})
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+func (h *BalanceHandler) PlanBalance(
+	ctx context.Context,
+	req *connect.Request[apiv1.PlanBalanceRequest],
+) (*connect.Response[apiv1.PlanBalanceResponse], error) {
+	h.logger.Debug("plan balance", "device", req.Msg.DevicePath, "type", req.Msg.Type,
+		"goal", req.Msg.GoalUnallocated, "start", req.Msg.Start)
+
+	if req.Msg.DevicePath == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("device_path is required"))
+	}
+	// Planning is a read, but starting the plan is a balance
+	if req.Msg.Start {
+		if err := auth.RequireRole(ctx, auth.RoleOperator, "starting a planned balance"); err != nil {
+			return nil, err
+		}
+	}
+
+	opts := fragmap.BalancePlanOptions{}
+	switch cmp.Or(req.Msg.Type, "data") {
+	case "data":
+		opts.Type = fragmap.BlockTypeData
+	case "metadata":
+		opts.Type = fragmap.BlockTypeMetadata
+	default:
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("type must be data or metadata"))
+	}
+	if req.Msg.GoalUnallocated < 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("goal_unallocated must not be negative"))
+	}
+	opts.GoalUnallocated = uint64(req.Msg.GoalUnallocated)
+	for _, t := range req.Msg.Thresholds {
+		if t < 1 || t > 100 {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("thresholds must be between 1 and 100"))
+		}
+		opts.Thresholds = append(opts.Thresholds, int(t))
+	}
+
+	fm, _, err := h.fragmapCache.Get(req.Msg.DevicePath)
+	if err != nil {
+		h.logger.Error("failed to scan chunks", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	plan := fragmap.PlanBalance(fm, opts)
+
+	resp := &apiv1.PlanBalanceResponse{
+		Type:        plan.Type.TypeName(),
+		Chunks:      int64(plan.Chunks),
+		Length:      int64(plan.Length),
+		Used:        int64(plan.Used),
+		ChunkSize:   int64(plan.ChunkSize),
+		Unallocated: int64(plan.Unallocated),
+		Goal:        int64(plan.Goal),
+		Recommended: int32(plan.Recommended),
+	}
+	flag := "-d"
+	if opts.Type == fragmap.BlockTypeMetadata {
+		flag = "-m"
+	}
+	for _, run := range plan.Runs {
+		resp.Runs = append(resp.Runs, &apiv1.BalancePlanRun{
+			Usage:            int32(run.Usage),
+			Chunks:           int64(run.Chunks),
+			ChunkBytes:       int64(run.ChunkBytes),
+			MovedBytes:       int64(run.MovedBytes),
+			NewChunks:        int64(run.NewChunks),
+			FreedBytes:       int64(run.FreedBytes),
+			UnallocatedAfter: int64(run.UnallocatedAfter),
+			ReachesGoal:      run.ReachesGoal,
+			Filters: &apiv1.BalanceFilters{
+				Data:         opts.Type == fragmap.BlockTypeData,
+				Metadata:     opts.Type == fragmap.BlockTypeMetadata,
+				UsagePercent: int32(run.Usage),
+			},
+			Command: fmt.Sprintf("btrfs balance start %susage=%d %s", flag, run.Usage, req.Msg.DevicePath),
+		})
+	}
+
+	if req.Msg.Start {
+		if plan.Recommended < 0 {
+			return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("no balance recommended: goal already met or no run frees space"))
+		}
+		if h.btrfsManager.IsBalanceRunning(req.Msg.DevicePath) {
+			return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("balance already running on %s", req.Msg.DevicePath))
+		}
+
+		filters := resp.Runs[plan.Recommended].Filters
+		balanceID, err := h.startBalance(ctx, req.Msg.DevicePath, btrfs.BalanceOptions{
+			Data:         filters.Data,
+			Metadata:     filters.Metadata,
+			UsagePercent: filters.UsagePercent,
+			LimitPercent: req.Msg.LimitPercent,
+		})
+		if err != nil {
+			h.logger.Error("failed to start planned balance", "error", err)
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		resp.BalanceId = balanceID
+		resp.Started = true
+	}
+
+	return connect.NewResponse(resp), nil
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"connectrpc.com/connect"
+	apiv1 "github.com/elee1766/gobtr/gen/api/v1"
+	"github.com/elee1766/gobtr/pkg/defrag"
+)
+
+type DefragHandler struct {
+	logger *slog.Logger
+	mgr    *defrag.Manager
+}
+
+func NewDefragHandler(logger *slog.Logger, mgr *defrag.Manager) *DefragHandler {
+	return &DefragHandler{
+		logger: logger.With("handler", "defrag"),
+		mgr:    mgr,
+	}
+}
+
+func defragOptionsToProto(opts *defrag.Options) *apiv1.DefragOptions {
+	return &apiv1.DefragOptions{
+		Paths:            opts.Paths,
+		MinDof:           opts.MinDoF,
+		MinExtents:       int32(opts.MinExtents),
+		ExtentThreshold:  opts.ExtentThreshold,
+		Compress:         opts.Compress,
+		LimitBytesPerSec: opts.LimitBytesPerSec,
+		AllowShared:      opts.AllowShared,
+		DryRun:           opts.DryRun,
+	}
+}
+
+// defragProgressToProto converts defrag.Progress to apiv1.DefragProgress
+func defragProgressToProto(p *defrag.Progress) *apiv1.DefragProgress {
+	out := &apiv1.DefragProgress{
+		JobId:              p.ID,
+		Options:            defragOptionsToProto(&p.Options),
+		Status:             p.Status,
+		Error:              p.Error,
+		StartedAt:          p.StartedAt.Unix(),
+		FilesScanned:       int32(p.FilesScanned),
+		FilesSelected:      int32(p.FilesSelected),
+		FilesDone:          int32(p.FilesDone),
+		FilesFailed:        int32(p.FilesFailed),
+		FilesSkippedShared: int32(p.FilesSkippedShared),
+		BytesTotal:         p.BytesTotal,
+		BytesDone:          p.BytesDone,
+		ExtentsBefore:      int32(p.ExtentsBefore),
+		ExtentsAfter:       int32(p.ExtentsAfter),
+		CurrentFile:        p.CurrentFile,
+		IsRunning:          p.IsRunning(),
+	}
+	if !p.FinishedAt.IsZero() {
+		out.FinishedAt = p.FinishedAt.Unix()
+	}
+	if p.BytesTotal > 0 {
+		out.ProgressPercent = float64(p.BytesDone) / float64(p.BytesTotal) * 100
+	}
+	return out
+}
+
+func (h *DefragHandler) StartDefrag(
+	ctx context.Context,
+	req *connect.Request[apiv1.StartDefragRequest],
+) (*connect.Response[apiv1.StartDefragResponse], error) {
+	o := req.Msg.Options
+	if o == nil || len(o.Paths) == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("options.paths is required"))
+	}
+	h.logger.Info("start defrag", "paths", o.Paths, "compress", o.Compress, "allow_shared", o.AllowShared, "dry_run", o.DryRun)
+
+	id, err := h.mgr.Start(defrag.Options{
+		Paths:            o.Paths,
+		MinDoF:           o.MinDof,
+		MinExtents:       int(o.MinExtents),
+		ExtentThreshold:  o.ExtentThreshold,
+		Compress:         o.Compress,
+		LimitBytesPerSec: o.LimitBytesPerSec,
+		AllowShared:      o.AllowShared,
+		DryRun:           o.DryRun,
+	})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+	}
+
+	return connect.NewResponse(&apiv1.StartDefragResponse{JobId: id}), nil
+}
+
+func (h *DefragHandler) CancelDefrag(
+	ctx context.Context,
+	req *connect.Request[apiv1.CancelDefragRequest],
+) (*connect.Response[apiv1.CancelDefragResponse], error) {
+	if req.Msg.JobId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("job_id is required"))
+	}
+	h.logger.Info("cancel defrag", "job", req.Msg.JobId)
+
+	if err := h.mgr.Cancel(req.Msg.JobId); err != nil {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+
+	return connect.NewResponse(&apiv1.CancelDefragResponse{Success: true}), nil
+}
+
+func (h *DefragHandler) GetDefragStatus(
+	ctx context.Context,
+	req *connect.Request[apiv1.GetDefragStatusRequest],
+) (*connect.Response[apiv1.GetDefragStatusResponse], error) {
+	p, err := h.mgr.Get(req.Msg.JobId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+
+	return connect.NewResponse(&apiv1.GetDefragStatusResponse{
+		Progress: defragProgressToProto(p),
+	}), nil
+}
+
+func (h *DefragHandler) ListDefragJobs(
+	ctx context.Context,
+	req *connect.Request[apiv1.ListDefragJobsRequest],
+) (*connect.Response[apiv1.ListDefragJobsResponse], error) {
+	jobs := h.mgr.List()
+	resp := &apiv1.ListDefragJobsResponse{
+		Jobs: make([]*apiv1.DefragProgress, len(jobs)),
+	}
+	for i := range jobs {
+		resp.Jobs[i] = defragProgressToProto(&jobs[i])
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *DefragHandler) StreamDefragProgress(
+	ctx context.Context,
+	req *connect.Request[apiv1.StreamDefragProgressRequest],
+	stream *connect.ServerStream[apiv1.DefragProgress],
+) error {
+	h.logger.Debug("stream defrag progress", "job", req.Msg.JobId)
+
+	if req.Msg.JobId == "" {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("job_id is required"))
+	}
+
+	// Poll for status updates
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		p, err := h.mgr.Get(req.Msg.JobId)
+		if err != nil {
+			return connect.NewError(connect.CodeNotFound, err)
+		}
+
+		if err := stream.Send(defragProgressToProto(p)); err != nil {
+			return err
+		}
+
+		// Stop streaming once the job is finished
+		if !p.IsRunning() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	apiv1 "github.com/elee1766/gobtr/gen/api/v1"
+	"github.com/elee1766/gobtr/pkg/btrfs"
+	"github.com/elee1766/gobtr/pkg/db"
+	"github.com/elee1766/gobtr/pkg/doctor"
+	"github.com/elee1766/gobtr/pkg/settings"
+)
+
+type DiagnosticsHandler struct {
+	logger       *slog.Logger
+	db           *db.DB
+	btrfsManager *btrfs.Manager
+	settings     *settings.Store
+}
+
+func NewDiagnosticsHandler(logger *slog.Logger, db *db.DB, btrfsManager *btrfs.Manager, settingsStore *settings.Store) *DiagnosticsHandler {
+	return &DiagnosticsHandler{
+		logger:       logger.With("handler", "diagnostics"),
+		db:           db,
+		btrfsManager: btrfsManager,
+		settings:     settingsStore,
+	}
+}
+
+func (h *DiagnosticsHandler) RunDiagnostics(
+	ctx context.Context,
+	req *connect.Request[apiv1.RunDiagnosticsRequest],
+) (*connect.Response[apiv1.RunDiagnosticsResponse], error) {
+	h.logger.Debug("run diagnostics", "device", req.Msg.DevicePath)
+
+	if req.Msg.DevicePath == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("device_path is required"))
+	}
+
+	report, err := doctor.Diagnose(h.btrfsManager, req.Msg.DevicePath, thresholdsFromProto(req.Msg.Thresholds, h.settings.Get().Diagnostics))
+	if err != nil {
+		h.logger.Error("failed to run diagnostics", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&apiv1.RunDiagnosticsResponse{
+		Report: reportToProto(report),
+	}), nil
+}
+
+// RunAllDiagnostics runs diagnostics for all tracked filesystems in parallel
+func (h *DiagnosticsHandler) RunAllDiagnostics(
+	ctx context.Context,
+	req *connect.Request[apiv1.RunAllDiagnosticsRequest],
+) (*connect.Response[apiv1.RunAllDiagnosticsResponse], error) {
+	h.logger.Debug("running diagnostics for all tracked filesystems")
+
+	filesystems, err := h.db.ListFilesystems()
+	if err != nil {
+		h.logger.Error("failed to list filesystems", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	thresholds := thresholdsFromProto(req.Msg.Thresholds, h.settings.Get().Diagnostics)
+
+	var wg sync.WaitGroup
+	results := make([]*apiv1.FilesystemDiagnostics, len(filesystems))
+
+	for i, fs := range filesystems {
+		wg.Add(1)
+		go func(idx int, fsPath string) {
+			defer wg.Done()
+
+			result := &apiv1.FilesystemDiagnostics{
+				Path: fsPath,
+			}
+
+			report, err := doctor.Diagnose(h.btrfsManager, fsPath, thresholds)
+			if err != nil {
+				result.ErrorMessage = err.Error()
+			} else {
+				result.Report = reportToProto(report)
+			}
+			results[idx] = result
+		}(i, fs.Path)
+	}
+
+	wg.Wait()
+
+	return connect.NewResponse(&apiv1.RunAllDiagnosticsResponse{
+		Filesystems: results,
+	}), nil
+}
+
+// thresholdsFromProto fills the thresholds a request leaves unset from the
+// server settings
+func thresholdsFromProto(t *apiv1.DiagnosticThresholds, defaults doctor.Thresholds) doctor.Thresholds {
+	if t == nil {
+		return defaults
+	}
+	thresholds := defaults
+	if t.ScrubMaxAgeDays > 0 {
+		thresholds.ScrubMaxAge = time.Duration(t.ScrubMaxAgeDays) * 24 * time.Hour
+	}
+	if t.SlackPercent > 0 {
+		thresholds.SlackPercent = t.SlackPercent
+	}
+	if t.MinSlackBytes > 0 {
+		thresholds.MinSlackBytes = t.MinSlackBytes
+	}
+	if t.ImbalancePercent > 0 {
+		thresholds.ImbalancePercent = t.ImbalancePercent
+	}
+	return thresholds
+}
+
+func reportToProto(r *doctor.Report) *apiv1.DiagnosticsReport {
+	report := &apiv1.DiagnosticsReport{
+		Path:      r.Path,
+		Uuid:      r.UUID,
+		CheckedAt: r.CheckedAt.Unix(),
+		Score:     int32(r.Score),
+	}
+
+	for _, f := range r.Findings {
+		report.Findings = append(report.Findings, &apiv1.DiagnosticFinding{
+			Rule:     f.Rule,
+			Severity: string(f.Severity),
+			Title:    f.Title,
+			Detail:   f.Detail,
+			Action:   f.Action,
+		})
+	}
+
+	return report
+}
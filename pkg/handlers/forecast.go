@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	apiv1 "github.com/elee1766/gobtr/gen/api/v1"
+	"github.com/elee1766/gobtr/pkg/btrfs"
+	"github.com/elee1766/gobtr/pkg/collector"
+	"github.com/elee1766/gobtr/pkg/db"
+	"github.com/elee1766/gobtr/pkg/db/queries"
+	"github.com/elee1766/gobtr/pkg/forecast"
+)
+
+// defaultForecastWindowDays is how much history is fitted when the request doesn't say
+const defaultForecastWindowDays = 30
+
+type ForecastHandler struct {
+	logger       *slog.Logger
+	db           *db.DB
+	btrfsManager *btrfs.Manager
+}
+
+func NewForecastHandler(logger *slog.Logger, db *db.DB, btrfsManager *btrfs.Manager) *ForecastHandler {
+	return &ForecastHandler{
+		logger:       logger.With("handler", "forecast"),
+		db:           db,
+		btrfsManager: btrfsManager,
+	}
+}
+
+func (h *ForecastHandler) GetUsageHistory(
+	ctx context.Context,
+	req *connect.Request[apiv1.GetUsageHistoryRequest],
+) (*connect.Response[apiv1.GetUsageHistoryResponse], error) {
+	h.logger.Debug("get usage history", "device", req.Msg.DevicePath, "since", req.Msg.Since, "limit", req.Msg.Limit)
+
+	if req.Msg.DevicePath == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("device_path is required"))
+	}
+
+	fsInfo, err := btrfs.GetFilesystemInfo(req.Msg.DevicePath)
+	if err != nil {
+		h.logger.Error("failed to get filesystem info", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	var since time.Time
+	if req.Msg.Since > 0 {
+		since = time.Unix(req.Msg.Since, 0)
+	}
+
+	history, err := queries.ListUsageHistory(h.db.Conn(), fsInfo.UUID, since, int(req.Msg.Limit))
+	if err != nil {
+		h.logger.Error("failed to list usage history", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	samples := make([]*apiv1.UsageSample, 0, len(history))
+	for _, s := range history {
+		samples = append(samples, usageSampleToProto(s))
+	}
+
+	return connect.NewResponse(&apiv1.GetUsageHistoryResponse{
+		Samples: samples,
+	}), nil
+}
+
+func (h *ForecastHandler) GetUsageForecast(
+	ctx context.Context,
+	req *connect.Request[apiv1.GetUsageForecastRequest],
+) (*connect.Response[apiv1.GetUsageForecastResponse], error) {
+	h.logger.Debug("get usage forecast", "device", req.Msg.DevicePath, "window_days", req.Msg.WindowDays)
+
+	if req.Msg.DevicePath == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("device_path is required"))
+	}
+
+	fc, err := h.buildForecast(req.Msg.DevicePath, req.Msg.WindowDays)
+	if err != nil {
+		h.logger.Error("failed to build forecast", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&apiv1.GetUsageForecastResponse{
+		Forecast: fc,
+	}), nil
+}
+
+// GetAllUsageForecasts builds forecasts for all tracked filesystems in parallel
+func (h *ForecastHandler) GetAllUsageForecasts(
+	ctx context.Context,
+	req *connect.Request[apiv1.GetAllUsageForecastsRequest],
+) (*connect.Response[apiv1.GetAllUsageForecastsResponse], error) {
+	h.logger.Debug("getting usage forecasts for all tracked filesystems")
+
+	filesystems, err := h.db.ListFilesystems()
+	if err != nil {
+		h.logger.Error("failed to list filesystems", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*apiv1.FilesystemUsageForecast, len(filesystems))
+
+	for i, fs := range filesystems {
+		wg.Add(1)
+		go func(idx int, fsPath string) {
+			defer wg.Done()
+
+			result := &apiv1.FilesystemUsageForecast{
+				Path: fsPath,
+			}
+
+			fc, err := h.buildForecast(fsPath, req.Msg.WindowDays)
+			if err != nil {
+				result.ErrorMessage = err.Error()
+			} else {
+				result.Forecast = fc
+			}
+			results[idx] = result
+		}(i, fs.Path)
+	}
+
+	wg.Wait()
+
+	return connect.NewResponse(&apiv1.GetAllUsageForecastsResponse{
+		Filesystems: results,
+	}), nil
+}
+
+// buildForecast fits the recorded history plus a live reading of the filesystem
+func (h *ForecastHandler) buildForecast(path string, windowDays int32) (*apiv1.UsageForecast, error) {
+	if windowDays <= 0 {
+		windowDays = defaultForecastWindowDays
+	}
+
+	fsInfo, err := btrfs.GetFilesystemInfo(path)
+	if err != nil {
+		return nil, fmt.Errorf("get filesystem info: %w", err)
+	}
+
+	now := time.Now()
+	since := now.Add(-time.Duration(windowDays) * 24 * time.Hour)
+	history, err := queries.ListUsageHistory(h.db.Conn(), fsInfo.UUID, since, 0)
+	if err != nil {
+		return nil, fmt.Errorf("list usage history: %w", err)
+	}
+
+	// Always evaluate risk against the current state, not the last recorded sample
+	usage, err := h.btrfsManager.GetFilesystemUsage(path)
+	if err != nil {
+		h.logger.Warn("failed to get live usage, forecasting from history only", "path", path, "error", err)
+	} else {
+		history = append(history, collector.NewUsageSample(fsInfo.UUID, usage, now))
+	}
+
+	if len(history) == 0 {
+		return nil, fmt.Errorf("no usage data for %s", path)
+	}
+
+	latest := history[len(history)-1]
+	samples := make([]forecast.Sample, 0, len(history))
+	for _, s := range history {
+		samples = append(samples, forecast.Sample{
+			Time:              s.Timestamp,
+			DeviceSize:        s.DeviceSize,
+			Unallocated:       s.DeviceUnallocated,
+			DataTotal:         s.DataTotal,
+			DataUsed:          s.DataUsed,
+			MetadataTotal:     s.MetadataTotal,
+			MetadataUsed:      s.MetadataUsed,
+			GlobalReserve:     s.GlobalReserve,
+			GlobalReserveUsed: s.GlobalReserveUsed,
+		})
+	}
+
+	f := forecast.Compute(samples, forecast.Options{
+		DataProfile:     latest.DataProfile,
+		MetadataProfile: latest.MetadataProfile,
+	})
+
+	return forecastToProto(f, latest), nil
+}
+
+func usageSampleToProto(s *queries.UsageSample) *apiv1.UsageSample {
+	return &apiv1.UsageSample{
+		Timestamp:         s.Timestamp.Unix(),
+		DeviceSize:        s.DeviceSize,
+		DeviceAllocated:   s.DeviceAllocated,
+		DeviceUnallocated: s.DeviceUnallocated,
+		DataTotal:         s.DataTotal,
+		DataUsed:          s.DataUsed,
+		DataProfile:       s.DataProfile,
+		MetadataTotal:     s.MetadataTotal,
+		MetadataUsed:      s.MetadataUsed,
+		MetadataProfile:   s.MetadataProfile,
+		SystemTotal:       s.SystemTotal,
+		SystemUsed:        s.SystemUsed,
+		GlobalReserve:     s.GlobalReserve,
+		GlobalReserveUsed: s.GlobalReserveUsed,
+		FreeEstimated:     s.FreeEstimated,
+	}
+}
+
+func trendToProto(t forecast.Trend) *apiv1.UsageTrend {
+	return &apiv1.UsageTrend{
+		BytesPerDay: t.BytesPerDay,
+		RSquared:    t.RSquared,
+	}
+}
+
+func forecastToProto(f *forecast.Forecast, latest *queries.UsageSample) *apiv1.UsageForecast {
+	fc := &apiv1.UsageForecast{
+		Samples:            int32(f.Samples),
+		WindowStart:        f.WindowStart.Unix(),
+		WindowEnd:          f.WindowEnd.Unix(),
+		Latest:             usageSampleToProto(latest),
+		Unallocated:        trendToProto(f.Unallocated),
+		DataAllocated:      trendToProto(f.DataAllocated),
+		DataUsed:           trendToProto(f.DataUsed),
+		MetadataAllocated:  trendToProto(f.MetadataAllocated),
+		MetadataUsed:       trendToProto(f.MetadataUsed),
+		DataChunkBytes:     f.DataChunkBytes,
+		MetadataChunkBytes: f.MetadataChunkBytes,
+	}
+
+	if !f.UnallocatedExhaustedAt.IsZero() {
+		fc.UnallocatedExhaustedAt = f.UnallocatedExhaustedAt.Unix()
+	}
+	if !f.MetadataChunkExhaustedAt.IsZero() {
+		fc.MetadataChunkExhaustedAt = f.MetadataChunkExhaustedAt.Unix()
+	}
+	if !f.MetadataExhaustedAt.IsZero() {
+		fc.MetadataExhaustedAt = f.MetadataExhaustedAt.Unix()
+	}
+
+	for _, r := range f.Risks {
+		fc.Risks = append(fc.Risks, &apiv1.EnospcRisk{
+			Code:     r.Code,
+			Severity: string(r.Severity),
+			Message:  r.Message,
+		})
+	}
+
+	return fc
+}
@@ -2,32 +2,118 @@ package handlers
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"sync"
+	"time"
 
 	"connectrpc.com/connect"
 	apiv1 "github.com/elee1766/gobtr/gen/api/v1"
+	"github.com/elee1766/gobtr/pkg/config"
+	"github.com/elee1766/gobtr/pkg/db"
+	"github.com/elee1766/gobtr/pkg/db/queries"
 	"github.com/elee1766/gobtr/pkg/fragmap"
+	"github.com/elee1766/gobtr/pkg/privsep"
+)
+
+const (
+	// fragMapScansKept is how many saved scans are kept per filesystem
+	fragMapScansKept = 100
+	// maxHeatMapResolution caps heat map cells per request
+	maxHeatMapResolution = 1 << 20
+	// maxResolveExtents caps the extents ResolveLogicalRange looks up, each
+	// of which is an ioctl plus path lookups
+	maxResolveExtents = 100000
 )
 
 type FragMapHandler struct {
-	logger *slog.Logger
+	logger  *slog.Logger
+	cfg     *config.Config
+	db      *db.DB
+	cache   *fragmap.Cache
+	backend privsep.Backend
 }
 
-func NewFragMapHandler(logger *slog.Logger) *FragMapHandler {
+func NewFragMapHandler(logger *slog.Logger, cfg *config.Config, db *db.DB, cache *fragmap.Cache, backend privsep.Backend) *FragMapHandler {
 	return &FragMapHandler{
-		logger: logger.With("handler", "fragmap"),
+		logger:  logger.With("handler", "fragmap"),
+		cfg:     cfg,
+		db:      db,
+		cache:   cache,
+		backend: backend,
 	}
 }
 
-func (h *FragMapHandler) GetFragMap(ctx context.Context, req *connect.Request[apiv1.GetFragMapRequest]) (*connect.Response[apiv1.GetFragMapResponse], error) {
-	scanner, err := fragmap.NewScanner(req.Msg.FsPath)
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
-	}
-	defer scanner.Close()
+// NewFragMapCache returns the fragmap cache shared by the handlers and the
+// metrics collector. Every new scan whose chunk layout differs from the last
+// saved one is saved to the database, so scans can be diffed later.
+func NewFragMapCache(logger *slog.Logger, database *db.DB, backend privsep.Backend) *fragmap.Cache {
+	logger = logger.With("component", "fragmap_cache")
+
+	var mu sync.Mutex
+	lastHash := make(map[string]string)
 
-	fm, err := scanner.Scan()
+	return fragmap.NewCache(backend, func(fm *fragmap.FragMap) {
+		if fm.UUID == "" {
+			return
+		}
+		hash := strconv.FormatUint(fm.LayoutHash(), 16)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		last, ok := lastHash[fm.UUID]
+		if !ok {
+			scans, err := queries.ListFragMapScans(database.Conn(), fm.UUID, 1)
+			if err != nil {
+				logger.Warn("failed to list saved scans", "uuid", fm.UUID, "error", err)
+			} else if len(scans) > 0 {
+				last = scans[0].LayoutHash
+			}
+		}
+		if last == hash {
+			lastHash[fm.UUID] = hash
+			return
+		}
+
+		data, err := fragmap.EncodeFragMap(fm)
+		if err != nil {
+			logger.Warn("failed to encode scan", "uuid", fm.UUID, "error", err)
+			return
+		}
+		id, err := queries.InsertFragMapScan(database.Conn(), &queries.FragMapScan{
+			FsUUID:     fm.UUID,
+			Generation: int64(fm.Generation),
+			ScannedAt:  fm.ScannedAt,
+			TotalSize:  int64(fm.TotalSize),
+			ChunkCount: int64(len(fm.Chunks)),
+			LayoutHash: hash,
+			Data:       data,
+		})
+		if err != nil {
+			logger.Warn("failed to save scan", "uuid", fm.UUID, "error", err)
+			return
+		}
+		lastHash[fm.UUID] = hash
+		logger.Debug("saved scan", "uuid", fm.UUID, "id", id, "generation", fm.Generation)
+
+		if _, err := queries.PruneFragMapScans(database.Conn(), fm.UUID, fragMapScansKept); err != nil {
+			logger.Warn("failed to prune saved scans", "uuid", fm.UUID, "error", err)
+		}
+	})
+}
+
+func (h *FragMapHandler) GetFragMap(ctx context.Context, req *connect.Request[apiv1.GetFragMapRequest]) (*connect.Response[apiv1.GetFragMapResponse], error) {
+	fm, _, err := h.cache.Get(req.Msg.FsPath)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
@@ -49,22 +135,8 @@ func (h *FragMapHandler) GetFragMap(ctx context.Context, req *connect.Request[ap
 	}
 
 	// Convert chunks
-	for i, chunk := range fm.Chunks {
-		stripes := make([]*apiv1.Stripe, len(chunk.Stripes))
-		for j, s := range chunk.Stripes {
-			stripes[j] = &apiv1.Stripe{
-				DeviceId: s.DeviceID,
-				Offset:   s.Offset,
-			}
-		}
-		resp.Chunks[i] = &apiv1.Chunk{
-			LogicalOffset: chunk.LogicalOffset,
-			Length:        chunk.Length,
-			Type:          uint64(chunk.Type),
-			Profile:       uint64(chunk.Profile),
-			Stripes:       stripes,
-			Used:          chunk.Used,
-		}
+	for i := range fm.Chunks {
+		resp.Chunks[i] = chunkToProto(&fm.Chunks[i])
 	}
 
 	// Convert device extents (flattened)
@@ -83,13 +155,7 @@ func (h *FragMapHandler) GetFragMap(ctx context.Context, req *connect.Request[ap
 }
 
 func (h *FragMapHandler) GetDeviceBlockMap(ctx context.Context, req *connect.Request[apiv1.GetDeviceBlockMapRequest]) (*connect.Response[apiv1.GetDeviceBlockMapResponse], error) {
-	scanner, err := fragmap.NewScanner(req.Msg.FsPath)
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
-	}
-	defer scanner.Close()
-
-	fm, err := scanner.Scan()
+	fm, _, err := h.cache.Get(req.Msg.FsPath)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
@@ -122,13 +188,7 @@ func (h *FragMapHandler) GetDeviceBlockMap(ctx context.Context, req *connect.Req
 }
 
 func (h *FragMapHandler) GetDeviceBlockMaps(ctx context.Context, req *connect.Request[apiv1.GetDeviceBlockMapsRequest]) (*connect.Response[apiv1.GetDeviceBlockMapsResponse], error) {
-	scanner, err := fragmap.NewScanner(req.Msg.FsPath)
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
-	}
-	defer scanner.Close()
-
-	fm, err := scanner.Scan()
+	fm, _, err := h.cache.Get(req.Msg.FsPath)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
@@ -244,34 +304,88 @@ func (h *FragMapHandler) GetDeviceBlockMaps(ctx context.Context, req *connect.Re
 }
 
 func (h *FragMapHandler) GetHeatMap(ctx context.Context, req *connect.Request[apiv1.GetHeatMapRequest]) (*connect.Response[apiv1.GetHeatMapResponse], error) {
-	scanner, err := fragmap.NewScanner(req.Msg.FsPath)
+	fm, _, err := h.cache.Get(req.Msg.FsPath)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
-	defer scanner.Close()
 
-	fm, err := scanner.Scan()
+	blockMap, err := fm.BuildDeviceBlockMap(req.Msg.DeviceId)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, connect.NewError(connect.CodeNotFound, err)
 	}
 
-	blockMap, err := fm.BuildDeviceBlockMap(req.Msg.DeviceId)
+	layout, err := fragmap.ParseHeatMapLayout(req.Msg.Layout)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeNotFound, err)
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
 
 	resolution := int(req.Msg.Resolution)
 	if resolution <= 0 {
 		resolution = 256
 	}
+	if resolution > maxHeatMapResolution {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("resolution must be at most %d", maxHeatMapResolution))
+	}
+	resolution = layout.GridResolution(resolution)
+
+	start, end := req.Msg.StartOffset, req.Msg.EndOffset
+	if end == 0 || end > blockMap.TotalSize {
+		end = blockMap.TotalSize
+	}
+	if start >= end {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("start_offset must be below end_offset and the device size"))
+	}
 
-	cells := blockMap.HeatMapData(resolution)
+	cells := blockMap.HeatMapRange(start, end, resolution)
+	width, height := fragmap.HeatMapGrid(cells, layout)
+
+	var blockGroups []fragmap.BlockGroupFreeSpace
+	var freeSpaceSource string
+	if req.Msg.FreeSpace {
+		// Only scan the chunks with a device extent in the range
+		inRange := make(map[uint64]bool)
+		for _, entry := range blockMap.Entries {
+			if entry.Allocated && entry.Offset < end && entry.Offset+entry.Length > start {
+				inRange[entry.ChunkOffset] = true
+			}
+		}
+		var chunks []fragmap.Chunk
+		for _, chunk := range fm.Chunks {
+			if inRange[chunk.LogicalOffset] {
+				chunks = append(chunks, chunk)
+			}
+		}
+
+		scanner, err := fragmap.NewScanner(h.backend, req.Msg.FsPath)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		defer scanner.Close()
+
+		blockGroups, freeSpaceSource, err = scanner.ScanFreeSpace(chunks)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		blockMap.ApplyFreeSpace(cells, fragmap.FreeSpaceByOffset(blockGroups))
+	}
 
 	resp := &apiv1.GetHeatMapResponse{
 		DeviceId:   blockMap.DeviceID,
 		TotalSize:  blockMap.TotalSize,
-		Resolution: int32(resolution),
+		Resolution: int32(len(cells)),
 		Cells:      make([]*apiv1.HeatMapCell, len(cells)),
+
+		FreeSpaceSource: freeSpaceSource,
+
+		Layout:      string(layout),
+		Width:       int32(width),
+		Height:      int32(height),
+		StartOffset: start,
+		EndOffset:   end,
+	}
+
+	for i := range blockGroups {
+		resp.BlockGroups = append(resp.BlockGroups, blockGroupFreeSpaceToProto(&blockGroups[i]))
 	}
 
 	for i, cell := range cells {
@@ -286,6 +400,15 @@ func (h *FragMapHandler) GetHeatMap(ctx context.Context, req *connect.Request[ap
 			SystemBytes:    cell.SystemBytes,
 			ExtentCount:    int32(cell.ExtentCount),
 			Utilization:    cell.Utilization,
+
+			ChunkFreeBytes:     cell.ChunkFreeBytes,
+			FreeSpaceFragScore: cell.FreeSpaceFragScore,
+
+			DominantProfile: uint64(cell.DominantProfile),
+			ChunkUsedBytes:  cell.ChunkUsedBytes,
+			BlockGroupUsage: cell.BlockGroupUsage,
+			X:               int32(cell.X),
+			Y:               int32(cell.Y),
 		}
 	}
 
@@ -293,13 +416,7 @@ func (h *FragMapHandler) GetHeatMap(ctx context.Context, req *connect.Request[ap
 }
 
 func (h *FragMapHandler) GetFragStats(ctx context.Context, req *connect.Request[apiv1.GetFragStatsRequest]) (*connect.Response[apiv1.GetFragStatsResponse], error) {
-	scanner, err := fragmap.NewScanner(req.Msg.FsPath)
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
-	}
-	defer scanner.Close()
-
-	fm, err := scanner.Scan()
+	fm, _, err := h.cache.Get(req.Msg.FsPath)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
@@ -344,3 +461,489 @@ func (h *FragMapHandler) GetFragStats(ctx context.Context, req *connect.Request[
 
 	return connect.NewResponse(resp), nil
 }
+
+func (h *FragMapHandler) GetFreeSpaceStats(ctx context.Context, req *connect.Request[apiv1.GetFreeSpaceStatsRequest]) (*connect.Response[apiv1.GetFreeSpaceStatsResponse], error) {
+	fm, _, err := h.cache.Get(req.Msg.FsPath)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	scanner, err := fragmap.NewScanner(h.backend, req.Msg.FsPath)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	defer scanner.Close()
+
+	blockGroups, source, err := scanner.ScanFreeSpace(fm.Chunks)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	resp := &apiv1.GetFreeSpaceStatsResponse{
+		Source: source,
+	}
+
+	for _, t := range []fragmap.BlockType{fragmap.BlockTypeData, fragmap.BlockTypeMetadata, fragmap.BlockTypeSystem} {
+		sum := fragmap.SummarizeFreeSpace(blockGroups, t)
+		if sum.BlockGroups == 0 {
+			continue
+		}
+		resp.Summaries = append(resp.Summaries, &apiv1.FreeSpaceSummary{
+			Type:        uint64(sum.Type),
+			BlockGroups: int32(sum.BlockGroups),
+			Length:      sum.Length,
+			FreeBytes:   sum.FreeBytes,
+			FreeRuns:    int32(sum.FreeRuns),
+			LargestFree: sum.LargestFree,
+			Histogram:   freeSpaceHistogramToProto(&sum.Histogram),
+			FragScore:   sum.FragScore,
+		})
+	}
+
+	if req.Msg.IncludeBlockGroups {
+		for i := range blockGroups {
+			resp.BlockGroups = append(resp.BlockGroups, blockGroupFreeSpaceToProto(&blockGroups[i]))
+		}
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+func (h *FragMapHandler) GetCompressionStats(ctx context.Context, req *connect.Request[apiv1.GetCompressionStatsRequest]) (*connect.Response[apiv1.GetCompressionStatsResponse], error) {
+	if req.Msg.Path == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("path is required"))
+	}
+
+	report, err := fragmap.Compsize(ctx, req.Msg.Path, fragmap.CompsizeOptions{
+		OneFileSystem: req.Msg.OneFileSystem,
+		Backend:       h.backend,
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, connect.NewError(connect.CodeCanceled, err)
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	u := report.Usage
+	resp := &apiv1.GetCompressionStatsResponse{
+		Files:             int32(report.Files),
+		Errors:            int32(report.Errors),
+		DiskBytes:         u.DiskBytes,
+		UncompressedBytes: u.UncompressedBytes,
+		ReferencedBytes:   u.ReferencedBytes,
+		CompressionRatio:  u.CompressionRatio(),
+		DurationMs:        report.Elapsed.Milliseconds(),
+	}
+	for _, c := range slices.Sorted(maps.Keys(u.ByCompression)) {
+		cu := u.ByCompression[c]
+		resp.ByCompression = append(resp.ByCompression, &apiv1.CompressionUsage{
+			Compression:       fragmap.CompressionName(c),
+			DiskBytes:         cu.DiskBytes,
+			UncompressedBytes: cu.UncompressedBytes,
+			ReferencedBytes:   cu.ReferencedBytes,
+		})
+	}
+
+	h.logger.Debug("compression stats", "path", req.Msg.Path, "files", report.Files, "errors", report.Errors)
+	return connect.NewResponse(resp), nil
+}
+
+func (h *FragMapHandler) ScanFiles(
+	ctx context.Context,
+	req *connect.Request[apiv1.ScanFilesRequest],
+	stream *connect.ServerStream[apiv1.ScanFilesUpdate],
+) error {
+	if req.Msg.Path == "" {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("path is required"))
+	}
+	h.logger.Info("scan files", "path", req.Msg.Path, "resumable", req.Msg.Resumable)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var sendErr error
+	opts := fragmap.DirScanOptions{
+		Workers:       int(req.Msg.Workers),
+		MaxDepth:      int(req.Msg.MaxDepth),
+		Exclude:       req.Msg.Exclude,
+		OneFileSystem: req.Msg.OneFileSystem,
+		Top:           int(req.Msg.Top),
+		Progress: func(p fragmap.DirScanProgress) {
+			if sendErr != nil {
+				return
+			}
+			sendErr = stream.Send(&apiv1.ScanFilesUpdate{
+				Progress: &apiv1.ScanFilesProgress{
+					FilesScanned: int32(p.FilesScanned),
+					BytesScanned: p.BytesScanned,
+					Errors:       int32(p.Errors),
+					CurrentPath:  p.CurrentPath,
+					ElapsedMs:    p.Elapsed.Milliseconds(),
+					Resumed:      p.Resumed,
+				},
+			})
+			if sendErr != nil {
+				cancel()
+			}
+		},
+	}
+	if req.Msg.Resumable {
+		opts.Checkpoint = h.scanCheckpointPath(req.Msg)
+	}
+	opts.Backend = h.backend
+
+	res, err := fragmap.ScanDirectory(ctx, req.Msg.Path, opts)
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return connect.NewError(connect.CodeCanceled, err)
+		}
+		return connect.NewError(connect.CodeInternal, err)
+	}
+
+	result := &apiv1.ScanFilesResult{
+		Stats:     fileFragStatsToProto(res.Stats),
+		Errors:    int32(res.Errors),
+		Resumed:   res.Resumed,
+		ElapsedMs: res.Elapsed.Milliseconds(),
+	}
+	for _, f := range res.Top {
+		result.Top = append(result.Top, &apiv1.FileFragSummary{
+			Path:              f.Path,
+			Size:              f.Size,
+			ExtentCount:       int32(f.ExtentCount),
+			IdealExtents:      int32(f.IdealExtents),
+			Dof:               f.DoF,
+			FragmentationPct:  f.FragmentationPct,
+			OutOfOrderPct:     f.OutOfOrderPct,
+			CompressedExtents: int32(f.CompressedExtents),
+			SharedExtents:     int32(f.SharedExtents),
+		})
+	}
+	for _, e := range res.ErrorSamples {
+		result.ErrorSamples = append(result.ErrorSamples, &apiv1.ScanFileError{Path: e.Path, Error: e.Err})
+	}
+
+	h.logger.Info("scan files done", "path", req.Msg.Path, "files", res.Stats.TotalFiles,
+		"errors", res.Errors, "duration", res.Elapsed.Round(time.Millisecond))
+	return stream.Send(&apiv1.ScanFilesUpdate{Result: result})
+}
+
+func (h *FragMapHandler) ListFragMapScans(ctx context.Context, req *connect.Request[apiv1.ListFragMapScansRequest]) (*connect.Response[apiv1.ListFragMapScansResponse], error) {
+	if req.Msg.FsPath == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("fs_path is required"))
+	}
+
+	// Scanning also saves the current layout if it changed
+	fm, _, err := h.cache.Get(req.Msg.FsPath)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	scans, err := queries.ListFragMapScans(h.db.Conn(), fm.UUID, int(req.Msg.Limit))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	resp := &apiv1.ListFragMapScansResponse{
+		Scans: make([]*apiv1.FragMapScanInfo, len(scans)),
+	}
+	for i, scan := range scans {
+		resp.Scans[i] = fragMapScanToProto(scan)
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+func (h *FragMapHandler) DiffFragMaps(ctx context.Context, req *connect.Request[apiv1.DiffFragMapsRequest]) (*connect.Response[apiv1.DiffFragMapsResponse], error) {
+	if req.Msg.FsPath == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("fs_path is required"))
+	}
+
+	current, _, err := h.cache.Get(req.Msg.FsPath)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	resp := &apiv1.DiffFragMapsResponse{}
+
+	var to *fragmap.FragMap
+	if req.Msg.ToScanId == 0 {
+		to = current
+		resp.To = &apiv1.FragMapScanInfo{
+			FsUuid:     current.UUID,
+			Generation: current.Generation,
+			ScannedAt:  current.ScannedAt.Unix(),
+			TotalSize:  current.TotalSize,
+			ChunkCount: int32(len(current.Chunks)),
+		}
+	} else {
+		scan, fm, err := h.loadFragMapScan(req.Msg.ToScanId, current.UUID)
+		if err != nil {
+			return nil, err
+		}
+		to = fm
+		resp.To = fragMapScanToProto(scan)
+	}
+
+	fromID := req.Msg.FromScanId
+	if fromID == 0 {
+		// The newest saved scan older than the target. The current state is
+		// usually saved too, so skip scans with the same layout.
+		scans, err := queries.ListFragMapScans(h.db.Conn(), current.UUID, 0)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		toHash := strconv.FormatUint(to.LayoutHash(), 16)
+		for _, scan := range scans {
+			if req.Msg.ToScanId != 0 && scan.ID >= req.Msg.ToScanId {
+				continue
+			}
+			if scan.LayoutHash == toHash {
+				continue
+			}
+			fromID = scan.ID
+			break
+		}
+		if fromID == 0 {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no earlier scan with a different layout"))
+		}
+	}
+
+	scan, from, err := h.loadFragMapScan(fromID, current.UUID)
+	if err != nil {
+		return nil, err
+	}
+	resp.From = fragMapScanToProto(scan)
+
+	diff := fragmap.DiffFragMaps(from, to)
+	for _, c := range diff.Changes {
+		change := &apiv1.ChunkChange{
+			Kind:         c.Kind.String(),
+			Chunk:        chunkToProto(&c.Chunk),
+			PreviousUsed: c.PreviousUsed,
+		}
+		for _, s := range c.PreviousStripes {
+			change.PreviousStripes = append(change.PreviousStripes, &apiv1.Stripe{
+				DeviceId: s.DeviceID,
+				Offset:   s.Offset,
+			})
+		}
+		resp.Changes = append(resp.Changes, change)
+	}
+	for _, td := range diff.ByType {
+		resp.ByType = append(resp.ByType, &apiv1.TypeDiff{
+			Type:            uint64(td.Type),
+			AllocatedChunks: int32(td.AllocatedChunks),
+			AllocatedBytes:  td.AllocatedBytes,
+			FreedChunks:     int32(td.FreedChunks),
+			FreedBytes:      td.FreedBytes,
+			RelocatedChunks: int32(td.RelocatedChunks),
+			RelocatedBytes:  td.RelocatedBytes,
+			ResizedChunks:   int32(td.ResizedChunks),
+			UsedBefore:      td.UsedBefore,
+			UsedAfter:       td.UsedAfter,
+		})
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+func (h *FragMapHandler) ResolveLogicalRange(ctx context.Context, req *connect.Request[apiv1.ResolveLogicalRangeRequest]) (*connect.Response[apiv1.ResolveLogicalRangeResponse], error) {
+	msg := req.Msg
+	if msg.FsPath == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("fs_path is required"))
+	}
+	if msg.MaxExtents < 0 || msg.MaxExtents > maxResolveExtents {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("max_extents must be at most %d", maxResolveExtents))
+	}
+
+	resp := &apiv1.ResolveLogicalRangeResponse{}
+	start, end := msg.LogicalStart, msg.LogicalEnd
+	if msg.DeviceId != 0 || end == 0 {
+		fm, _, err := h.cache.Get(msg.FsPath)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+
+		var chunk *fragmap.Chunk
+		if msg.DeviceId != 0 {
+			chunk, err = fm.DeviceOffsetToChunk(msg.DeviceId, msg.DeviceOffset)
+		} else {
+			chunk, err = fm.LogicalToChunk(start)
+		}
+		if err != nil {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+		start, end = chunk.LogicalOffset, chunk.LogicalOffset+chunk.Length
+		resp.Chunk = chunkToProto(chunk)
+	}
+	if end <= start {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("logical_end must be above logical_start"))
+	}
+
+	scanner, err := fragmap.NewScanner(h.backend, msg.FsPath)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	defer scanner.Close()
+
+	r, err := scanner.ResolveLogicalRange(ctx, start, end, fragmap.LogicalRangeOptions{
+		MaxExtents: int(msg.MaxExtents),
+	})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	resp.LogicalStart = r.Start
+	resp.LogicalEnd = r.End
+	resp.DataExtents = int32(r.DataExtents)
+	resp.DataBytes = r.DataBytes
+	resp.MetadataExtents = int32(r.MetadataExtents)
+	resp.MetadataBytes = r.MetadataBytes
+	resp.Truncated = r.Truncated
+	for _, ext := range r.Extents {
+		e := &apiv1.LogicalExtent{
+			Logical:         ext.Logical,
+			Length:          ext.Length,
+			Refs:            ext.Refs,
+			OwnersTruncated: ext.OwnersTruncated,
+		}
+		for _, o := range ext.Owners {
+			e.Owners = append(e.Owners, &apiv1.ExtentOwner{
+				Root:   o.Root,
+				Inode:  o.Inode,
+				Offset: o.Offset,
+				Path:   o.Path,
+			})
+		}
+		resp.Extents = append(resp.Extents, e)
+	}
+	for _, f := range r.Files {
+		resp.Files = append(resp.Files, &apiv1.LogicalFileUsage{
+			Path:          f.Path,
+			Root:          f.Root,
+			Inode:         f.Inode,
+			Extents:       int32(f.Extents),
+			Bytes:         f.Bytes,
+			SharedExtents: int32(f.SharedExtents),
+		})
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+// loadFragMapScan reads a saved scan, checking it belongs to the filesystem
+func (h *FragMapHandler) loadFragMapScan(id int64, fsUUID string) (*queries.FragMapScan, *fragmap.FragMap, error) {
+	scan, err := queries.GetFragMapScan(h.db.Conn(), id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && scan.FsUUID != fsUUID) {
+		return nil, nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("scan %d not found for this filesystem", id))
+	}
+	if err != nil {
+		return nil, nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	fm, err := fragmap.DecodeFragMap(scan.Data)
+	if err != nil {
+		return nil, nil, connect.NewError(connect.CodeInternal, fmt.Errorf("decode scan %d: %w", id, err))
+	}
+	return scan, fm, nil
+}
+
+// scanCheckpointPath names the checkpoint of a resumable scan after its path
+// and the options that change which files are scanned
+func (h *FragMapHandler) scanCheckpointPath(req *apiv1.ScanFilesRequest) string {
+	key, _ := json.Marshal([]any{filepath.Clean(req.Path), req.MaxDepth, req.Exclude, req.OneFileSystem})
+	sum := sha256.Sum256(key)
+	return filepath.Join(h.cfg.ScanStateDir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// doFBuckets is the display order of AggregateFragStats.DoFHistogram
+var doFBuckets = []string{"1", "1-2", "2-5", "5-10", "10+"}
+
+func chunkToProto(chunk *fragmap.Chunk) *apiv1.Chunk {
+	stripes := make([]*apiv1.Stripe, len(chunk.Stripes))
+	for j, s := range chunk.Stripes {
+		stripes[j] = &apiv1.Stripe{
+			DeviceId: s.DeviceID,
+			Offset:   s.Offset,
+		}
+	}
+	return &apiv1.Chunk{
+		LogicalOffset: chunk.LogicalOffset,
+		Length:        chunk.Length,
+		Type:          uint64(chunk.Type),
+		Profile:       uint64(chunk.Profile),
+		Stripes:       stripes,
+		Used:          chunk.Used,
+	}
+}
+
+func fragMapScanToProto(s *queries.FragMapScan) *apiv1.FragMapScanInfo {
+	return &apiv1.FragMapScanInfo{
+		Id:         s.ID,
+		FsUuid:     s.FsUUID,
+		Generation: uint64(s.Generation),
+		ScannedAt:  s.ScannedAt.Unix(),
+		TotalSize:  uint64(s.TotalSize),
+		ChunkCount: int32(s.ChunkCount),
+	}
+}
+
+func fileFragStatsToProto(s *fragmap.AggregateFragStats) *apiv1.FileFragStats {
+	out := &apiv1.FileFragStats{
+		TotalFiles:        int32(s.TotalFiles),
+		TotalExtents:      int32(s.TotalExtents),
+		TotalBytes:        s.TotalBytes,
+		FragmentedFiles:   int32(s.FragmentedFiles),
+		AvgDof:            s.AvgDoF,
+		AvgFragPct:        s.AvgFragPct,
+		AvgOutOfOrderPct:  s.AvgOutOfOrderPct,
+		MaxDof:            s.MaxDoF,
+		MaxExtents:        int32(s.MaxExtents),
+		CompressedFiles:   int32(s.CompressedFiles),
+		CompressedExtents: int32(s.CompressedExtents),
+		InlineExtents:     int32(s.InlineExtents),
+		SharedExtents:     int32(s.SharedExtents),
+		UnwrittenExtents:  int32(s.UnwrittenExtents),
+		CompressedBytes:   s.CompressedBytes,
+		SharedBytes:       s.SharedBytes,
+	}
+	for _, b := range doFBuckets {
+		out.DofHistogram = append(out.DofHistogram, &apiv1.DoFBucket{Range: b, Files: int32(s.DoFHistogram[b])})
+	}
+	return out
+}
+
+func freeSpaceHistogramToProto(h *fragmap.FreeSpaceHistogram) []*apiv1.FreeSpaceBucket {
+	buckets := make([]*apiv1.FreeSpaceBucket, len(h.Counts))
+	for i := range h.Counts {
+		var maxSize uint64
+		if i < len(fragmap.FreeSpaceBucketBounds) {
+			maxSize = fragmap.FreeSpaceBucketBounds[i]
+		}
+		buckets[i] = &apiv1.FreeSpaceBucket{
+			MaxSize: maxSize,
+			Count:   h.Counts[i],
+			Bytes:   h.Bytes[i],
+		}
+	}
+	return buckets
+}
+
+func blockGroupFreeSpaceToProto(bg *fragmap.BlockGroupFreeSpace) *apiv1.BlockGroupFreeSpace {
+	return &apiv1.BlockGroupFreeSpace{
+		LogicalOffset: bg.LogicalOffset,
+		Length:        bg.Length,
+		Type:          uint64(bg.Type),
+		Profile:       uint64(bg.Profile),
+		FreeBytes:     bg.FreeBytes,
+		FreeRuns:      int32(bg.FreeRuns),
+		LargestFree:   bg.LargestFree,
+		Histogram:     freeSpaceHistogramToProto(&bg.Histogram),
+		FragScore:     bg.FragScore,
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	"github.com/elee1766/gobtr/pkg/fragmap"
+)
+
+// RenderHandler serves fragmap images over plain HTTP so they can be linked
+// from tickets and reports:
+//
+//	GET /render/fragmap?fs=/mnt/pool&kind=layout|heatmap&format=png|svg&width=1024&resolution=1024&device=1&layout=hilbert
+func (h *FragMapHandler) RenderHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		fsPath := q.Get("fs")
+		if fsPath == "" {
+			http.Error(w, "fs is required", http.StatusBadRequest)
+			return
+		}
+
+		opts := fragmap.RenderOptions{
+			Kind:   fragmap.RenderKind(q.Get("kind")),
+			Format: fragmap.RenderFormat(q.Get("format")),
+		}
+		layout, err := fragmap.ParseHeatMapLayout(q.Get("layout"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		opts.Layout = layout
+		for name, dst := range map[string]*int{"width": &opts.Width, "resolution": &opts.Resolution} {
+			if v := q.Get(name); v != "" {
+				n, err := strconv.Atoi(v)
+				if err != nil || n < 0 || n > 16384 {
+					http.Error(w, "invalid "+name, http.StatusBadRequest)
+					return
+				}
+				*dst = n
+			}
+		}
+		if v := q.Get("device"); v != "" {
+			id, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				http.Error(w, "invalid device", http.StatusBadRequest)
+				return
+			}
+			opts.DeviceID = id
+		}
+		if opts.Format == "" {
+			opts.Format = fragmap.RenderPNG
+		}
+
+		fm, _, err := h.cache.Get(fsPath)
+		if err != nil {
+			h.logger.Warn("render: scan failed", "path", fsPath, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Render into a buffer so errors can still be reported with a status
+		var buf bytes.Buffer
+		if err := fragmap.Render(&buf, fm, opts); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", opts.Format.ContentType())
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(buf.Bytes())
+	})
+}
@@ -6,18 +6,21 @@ import (
 
 	"connectrpc.com/connect"
 	apiv1 "github.com/elee1766/gobtr/gen/api/v1"
+	"github.com/elee1766/gobtr/pkg/config"
 	"github.com/elee1766/gobtr/pkg/db"
 )
 
 type HealthHandler struct {
-	logger *slog.Logger
-	db     *db.DB
+	logger   *slog.Logger
+	db       *db.DB
+	readOnly bool
 }
 
-func NewHealthHandler(logger *slog.Logger, db *db.DB) *HealthHandler {
+func NewHealthHandler(logger *slog.Logger, cfg *config.Config, db *db.DB) *HealthHandler {
 	return &HealthHandler{
-		logger: logger.With("handler", "health"),
-		db:     db,
+		logger:   logger.With("handler", "health"),
+		db:       db,
+		readOnly: cfg.ReadOnly,
 	}
 }
 
@@ -29,7 +32,8 @@ func (h *HealthHandler) Check(
 
 	// TODO: Add actual health checks
 	return connect.NewResponse(&apiv1.HealthCheckResponse{
-		Status:  apiv1.HealthCheckResponse_SERVING,
-		Message: "service is healthy",
+		Status:   apiv1.HealthCheckResponse_SERVING,
+		Message:  "service is healthy",
+		ReadOnly: h.readOnly,
 	}), nil
 }
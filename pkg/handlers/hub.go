@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"connectrpc.com/connect"
+	apiv1 "github.com/elee1766/gobtr/gen/api/v1"
+	"github.com/elee1766/gobtr/pkg/hub"
+)
+
+type HubHandler struct {
+	logger      *slog.Logger
+	hub         *hub.Hub
+	filesystem  *FilesystemHandler
+	scrub       *ScrubHandler
+	balance     *BalanceHandler
+	diagnostics *DiagnosticsHandler
+	hostname    string
+}
+
+func NewHubHandler(
+	logger *slog.Logger,
+	hub *hub.Hub,
+	filesystem *FilesystemHandler,
+	scrub *ScrubHandler,
+	balance *BalanceHandler,
+	diagnostics *DiagnosticsHandler,
+) *HubHandler {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "local"
+	}
+	return &HubHandler{
+		logger:      logger.With("handler", "hub"),
+		hub:         hub,
+		filesystem:  filesystem,
+		scrub:       scrub,
+		balance:     balance,
+		diagnostics: diagnostics,
+		hostname:    hostname,
+	}
+}
+
+func (h *HubHandler) ListAgents(
+	ctx context.Context,
+	req *connect.Request[apiv1.ListAgentsRequest],
+) (*connect.Response[apiv1.ListAgentsResponse], error) {
+	agents := h.hub.Agents()
+	resp := &apiv1.ListAgentsResponse{Agents: make([]*apiv1.Agent, 0, len(agents))}
+	for _, a := range agents {
+		s := a.Status()
+		agent := &apiv1.Agent{
+			Name:      a.Name,
+			Url:       a.URL,
+			Healthy:   s.Healthy(),
+			LastError: s.LastError,
+			LatencyMs: s.Latency.Milliseconds(),
+			ReadOnly:  s.ReadOnly,
+		}
+		if !s.LastSeen.IsZero() {
+			agent.LastSeen = s.LastSeen.Unix()
+		}
+		if !s.LastChecked.IsZero() {
+			agent.LastChecked = s.LastChecked.Unix()
+		}
+		resp.Agents = append(resp.Agents, agent)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *HubHandler) ListHostFilesystems(
+	ctx context.Context,
+	req *connect.Request[apiv1.ListHostFilesystemsRequest],
+) (*connect.Response[apiv1.ListHostFilesystemsResponse], error) {
+	hosts, values := fromHosts(ctx, h,
+		func(ctx context.Context) ([]*apiv1.TrackedFilesystem, error) {
+			resp, err := h.filesystem.ListTrackedFilesystems(ctx, connect.NewRequest(&apiv1.ListTrackedFilesystemsRequest{}))
+			if err != nil {
+				return nil, err
+			}
+			return resp.Msg.Filesystems, nil
+		},
+		func(ctx context.Context, a *hub.Agent) ([]*apiv1.TrackedFilesystem, error) {
+			resp, err := a.Filesystem.ListTrackedFilesystems(ctx, connect.NewRequest(&apiv1.ListTrackedFilesystemsRequest{}))
+			if err != nil {
+				return nil, err
+			}
+			return resp.Msg.Filesystems, nil
+		},
+	)
+
+	resp := &apiv1.ListHostFilesystemsResponse{}
+	for i, host := range hosts {
+		resp.Hosts = append(resp.Hosts, &apiv1.HostFilesystems{Host: host, Filesystems: values[i]})
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *HubHandler) GetHostScrubStatus(
+	ctx context.Context,
+	req *connect.Request[apiv1.GetHostScrubStatusRequest],
+) (*connect.Response[apiv1.GetHostScrubStatusResponse], error) {
+	hosts, values := fromHosts(ctx, h,
+		func(ctx context.Context) ([]*apiv1.FilesystemScrubStatus, error) {
+			resp, err := h.scrub.GetAllScrubStatus(ctx, connect.NewRequest(&apiv1.GetAllScrubStatusRequest{}))
+			if err != nil {
+				return nil, err
+			}
+			return resp.Msg.Filesystems, nil
+		},
+		func(ctx context.Context, a *hub.Agent) ([]*apiv1.FilesystemScrubStatus, error) {
+			resp, err := a.Scrub.GetAllScrubStatus(ctx, connect.NewRequest(&apiv1.GetAllScrubStatusRequest{}))
+			if err != nil {
+				return nil, err
+			}
+			return resp.Msg.Filesystems, nil
+		},
+	)
+
+	resp := &apiv1.GetHostScrubStatusResponse{}
+	for i, host := range hosts {
+		resp.Hosts = append(resp.Hosts, &apiv1.HostScrubStatus{Host: host, Filesystems: values[i]})
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *HubHandler) GetHostBalanceStatus(
+	ctx context.Context,
+	req *connect.Request[apiv1.GetHostBalanceStatusRequest],
+) (*connect.Response[apiv1.GetHostBalanceStatusResponse], error) {
+	hosts, values := fromHosts(ctx, h,
+		func(ctx context.Context) ([]*apiv1.FilesystemBalanceStatus, error) {
+			resp, err := h.balance.GetAllBalanceStatus(ctx, connect.NewRequest(&apiv1.GetAllBalanceStatusRequest{}))
+			if err != nil {
+				return nil, err
+			}
+			return resp.Msg.Filesystems, nil
+		},
+		func(ctx context.Context, a *hub.Agent) ([]*apiv1.FilesystemBalanceStatus, error) {
+			resp, err := a.Balance.GetAllBalanceStatus(ctx, connect.NewRequest(&apiv1.GetAllBalanceStatusRequest{}))
+			if err != nil {
+				return nil, err
+			}
+			return resp.Msg.Filesystems, nil
+		},
+	)
+
+	resp := &apiv1.GetHostBalanceStatusResponse{}
+	for i, host := range hosts {
+		resp.Hosts = append(resp.Hosts, &apiv1.HostBalanceStatus{Host: host, Filesystems: values[i]})
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *HubHandler) GetHostFilesystemUsage(
+	ctx context.Context,
+	req *connect.Request[apiv1.GetHostFilesystemUsageRequest],
+) (*connect.Response[apiv1.GetHostFilesystemUsageResponse], error) {
+	hosts, values := fromHosts(ctx, h,
+		func(ctx context.Context) ([]*apiv1.FilesystemUsageInfo, error) {
+			resp, err := h.filesystem.GetAllFilesystemUsage(ctx, connect.NewRequest(&apiv1.GetAllFilesystemUsageRequest{}))
+			if err != nil {
+				return nil, err
+			}
+			return resp.Msg.Filesystems, nil
+		},
+		func(ctx context.Context, a *hub.Agent) ([]*apiv1.FilesystemUsageInfo, error) {
+			resp, err := a.Filesystem.GetAllFilesystemUsage(ctx, connect.NewRequest(&apiv1.GetAllFilesystemUsageRequest{}))
+			if err != nil {
+				return nil, err
+			}
+			return resp.Msg.Filesystems, nil
+		},
+	)
+
+	resp := &apiv1.GetHostFilesystemUsageResponse{}
+	for i, host := range hosts {
+		resp.Hosts = append(resp.Hosts, &apiv1.HostFilesystemUsage{Host: host, Filesystems: values[i]})
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *HubHandler) GetHostDiagnostics(
+	ctx context.Context,
+	req *connect.Request[apiv1.GetHostDiagnosticsRequest],
+) (*connect.Response[apiv1.GetHostDiagnosticsResponse], error) {
+	hosts, values := fromHosts(ctx, h,
+		func(ctx context.Context) ([]*apiv1.FilesystemDiagnostics, error) {
+			resp, err := h.diagnostics.RunAllDiagnostics(ctx, connect.NewRequest(&apiv1.RunAllDiagnosticsRequest{}))
+			if err != nil {
+				return nil, err
+			}
+			return resp.Msg.Filesystems, nil
+		},
+		func(ctx context.Context, a *hub.Agent) ([]*apiv1.FilesystemDiagnostics, error) {
+			resp, err := a.Diagnostics.RunAllDiagnostics(ctx, connect.NewRequest(&apiv1.RunAllDiagnosticsRequest{}))
+			if err != nil {
+				return nil, err
+			}
+			return resp.Msg.Filesystems, nil
+		},
+	)
+
+	resp := &apiv1.GetHostDiagnosticsResponse{}
+	for i, host := range hosts {
+		resp.Hosts = append(resp.Hosts, &apiv1.HostDiagnostics{Host: host, Filesystems: values[i]})
+	}
+	return connect.NewResponse(resp), nil
+}
+
+// fromHosts calls local on this server while the hub calls remote on every
+// agent, and returns one host and value per server, this one first. A host
+// that fails gets its error and no value rather than failing the whole call.
+func fromHosts[T any](
+	ctx context.Context,
+	h *HubHandler,
+	local func(ctx context.Context) (T, error),
+	remote func(ctx context.Context, a *hub.Agent) (T, error),
+) ([]*apiv1.Host, []T) {
+	localHost := &apiv1.Host{Name: h.hostname, Local: true}
+	var localValue T
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		v, err := local(ctx)
+		if err != nil {
+			h.logger.Error("local host failed", "error", err)
+			localHost.ErrorMessage = err.Error()
+			return
+		}
+		localValue = v
+	}()
+
+	results := hub.Collect(ctx, h.hub, remote)
+	<-done
+
+	hosts := []*apiv1.Host{localHost}
+	values := []T{localValue}
+	for _, r := range results {
+		host := &apiv1.Host{Name: r.Agent.Name}
+		if r.Err != nil {
+			h.logger.Warn("agent failed", "agent", r.Agent.Name, "error", r.Err)
+			host.ErrorMessage = r.Err.Error()
+		}
+		hosts = append(hosts, host)
+		values = append(values, r.Value)
+	}
+	return hosts, values
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"connectrpc.com/connect"
+	apiv1 "github.com/elee1766/gobtr/gen/api/v1"
+	"github.com/elee1766/gobtr/pkg/settings"
+)
+
+type SettingsHandler struct {
+	logger   *slog.Logger
+	settings *settings.Store
+}
+
+func NewSettingsHandler(logger *slog.Logger, settingsStore *settings.Store) *SettingsHandler {
+	return &SettingsHandler{
+		logger:   logger.With("handler", "settings"),
+		settings: settingsStore,
+	}
+}
+
+func (h *SettingsHandler) GetSettings(
+	ctx context.Context,
+	req *connect.Request[apiv1.GetSettingsRequest],
+) (*connect.Response[apiv1.GetSettingsResponse], error) {
+	h.logger.Debug("get settings")
+
+	return connect.NewResponse(&apiv1.GetSettingsResponse{
+		Settings:   settingsToProto(h.settings.Get()),
+		Defaults:   settingsToProto(h.settings.Defaults()),
+		Overridden: h.settings.Overridden(),
+	}), nil
+}
+
+func (h *SettingsHandler) UpdateSettings(
+	ctx context.Context,
+	req *connect.Request[apiv1.UpdateSettingsRequest],
+) (*connect.Response[apiv1.UpdateSettingsResponse], error) {
+	h.logger.Info("update settings", "reset", req.Msg.ResetFields)
+
+	next := h.settings.Get()
+	if s := req.Msg.Settings; s != nil {
+		if s.DefaultSampleTarget > 0 {
+			next.SampleTarget = s.DefaultSampleTarget
+		}
+		if s.CollectIntervalSeconds > 0 {
+			next.CollectInterval = time.Duration(s.CollectIntervalSeconds) * time.Second
+		}
+		if s.ScrubMaxAgeDays > 0 {
+			next.Diagnostics.ScrubMaxAge = time.Duration(s.ScrubMaxAgeDays) * 24 * time.Hour
+		}
+		if s.SlackPercent > 0 {
+			next.Diagnostics.SlackPercent = s.SlackPercent
+		}
+		if s.MinSlackBytes > 0 {
+			next.Diagnostics.MinSlackBytes = s.MinSlackBytes
+		}
+		if s.ImbalancePercent > 0 {
+			next.Diagnostics.ImbalancePercent = s.ImbalancePercent
+		}
+	}
+
+	updated, err := h.settings.Update(next, req.Msg.ResetFields)
+	if errors.Is(err, settings.ErrInvalid) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err != nil {
+		h.logger.Error("failed to update settings", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&apiv1.UpdateSettingsResponse{
+		Settings:   settingsToProto(updated),
+		Overridden: h.settings.Overridden(),
+	}), nil
+}
+
+func settingsToProto(s settings.Settings) *apiv1.ServerSettings {
+	return &apiv1.ServerSettings{
+		DefaultSampleTarget:    s.SampleTarget,
+		CollectIntervalSeconds: uint64(s.CollectInterval / time.Second),
+		ScrubMaxAgeDays:        int32(s.Diagnostics.ScrubMaxAge / (24 * time.Hour)),
+		SlackPercent:           s.Diagnostics.SlackPercent,
+		MinSlackBytes:          s.Diagnostics.MinSlackBytes,
+		ImbalancePercent:       s.Diagnostics.ImbalancePercent,
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+	apiv1 "github.com/elee1766/gobtr/gen/api/v1"
+	"github.com/elee1766/gobtr/pkg/reconcile"
+	"github.com/elee1766/gobtr/pkg/support"
+)
+
+type SupportHandler struct {
+	logger     *slog.Logger
+	bundler    *support.Bundler
+	reconciler *reconcile.Reconciler
+}
+
+func NewSupportHandler(logger *slog.Logger, bundler *support.Bundler, reconciler *reconcile.Reconciler) *SupportHandler {
+	return &SupportHandler{
+		logger:     logger.With("handler", "support"),
+		bundler:    bundler,
+		reconciler: reconciler,
+	}
+}
+
+func (h *SupportHandler) CreateSupportBundle(
+	ctx context.Context,
+	req *connect.Request[apiv1.CreateSupportBundleRequest],
+) (*connect.Response[apiv1.CreateSupportBundleResponse], error) {
+	h.logger.Info("creating support bundle", "history", req.Msg.History)
+
+	if req.Msg.History < 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("history must not be negative"))
+	}
+
+	var buf bytes.Buffer
+	if err := h.bundler.Write(&buf, h.options(int(req.Msg.History))); err != nil {
+		h.logger.Error("failed to create support bundle", "error", err)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&apiv1.CreateSupportBundleResponse{
+		Filename: support.Filename(time.Now()),
+		Bundle:   buf.Bytes(),
+	}), nil
+}
+
+// BundleHandler serves a support bundle as a download, with ?history=N like
+// CreateSupportBundle
+func (h *SupportHandler) BundleHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var history int
+		if s := r.URL.Query().Get("history"); s != "" {
+			var err error
+			if history, err = strconv.Atoi(s); err != nil || history < 0 {
+				http.Error(w, "history must be a non-negative integer", http.StatusBadRequest)
+				return
+			}
+		}
+		h.logger.Info("creating support bundle", "history", history)
+
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", support.Filename(time.Now())))
+		if err := h.bundler.Write(w, h.options(history)); err != nil {
+			h.logger.Error("failed to write support bundle", "error", err)
+		}
+	})
+}
+
+func (h *SupportHandler) options(history int) support.Options {
+	return support.Options{
+		File:       h.reconciler.File(),
+		History:    history,
+		Goroutines: true,
+	}
+}
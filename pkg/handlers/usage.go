@@ -13,17 +13,24 @@ import (
 	"github.com/elee1766/gobtr/pkg/btdu"
 	"github.com/elee1766/gobtr/pkg/config"
 	"github.com/elee1766/gobtr/pkg/db"
+	"github.com/elee1766/gobtr/pkg/privsep"
+	"github.com/elee1766/gobtr/pkg/settings"
 )
 
 type UsageHandler struct {
 	logger   *slog.Logger
 	db       *db.DB
 	store    *btdu.PebbleStore
+	settings *settings.Store
+	backend  privsep.Backend
 	samplers map[string]*btdu.PebbleSampler
-	mu       sync.RWMutex
+	// Samplers not using the default sample target, which a change to it
+	// leaves alone
+	explicitTarget map[string]bool
+	mu             sync.RWMutex
 }
 
-func NewUsageHandler(logger *slog.Logger, db *db.DB, cfg *config.Config) (*UsageHandler, error) {
+func NewUsageHandler(logger *slog.Logger, db *db.DB, cfg *config.Config, settingsStore *settings.Store, backend privsep.Backend) (*UsageHandler, error) {
 	store, err := btdu.NewPebbleStore(cfg.BTDUStoreDir)
 	if err != nil {
 		return nil, fmt.Errorf("create btdu pebble store: %w", err)
@@ -31,12 +38,28 @@ func NewUsageHandler(logger *slog.Logger, db *db.DB, cfg *config.Config) (*Usage
 
 	logger.Info("using PebbleDB backend for btdu storage", "dir", cfg.BTDUStoreDir)
 
-	return &UsageHandler{
-		logger:   logger.With("handler", "usage"),
-		db:       db,
-		store:    store,
-		samplers: make(map[string]*btdu.PebbleSampler),
-	}, nil
+	h := &UsageHandler{
+		logger:         logger.With("handler", "usage"),
+		db:             db,
+		store:          store,
+		settings:       settingsStore,
+		backend:        backend,
+		samplers:       make(map[string]*btdu.PebbleSampler),
+		explicitTarget: make(map[string]bool),
+	}
+	settingsStore.Subscribe(h.applySettings)
+	return h, nil
+}
+
+// applySettings moves samplers using the default sample target to the new one
+func (h *UsageHandler) applySettings(s settings.Settings) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for fsPath, sampler := range h.samplers {
+		if !h.explicitTarget[fsPath] {
+			sampler.SetTarget(s.SampleTarget)
+		}
+	}
 }
 
 func (h *UsageHandler) getSampler(fsPath string) (*btdu.PebbleSampler, error) {
@@ -47,7 +70,7 @@ func (h *UsageHandler) getSampler(fsPath string) (*btdu.PebbleSampler, error) {
 		return sampler, nil
 	}
 
-	sampler, err := btdu.NewPebbleSampler(fsPath, h.store, true)
+	sampler, err := btdu.NewPebbleSampler(h.backend, fsPath, h.store, true)
 	if err != nil {
 		return nil, err
 	}
@@ -56,6 +79,31 @@ func (h *UsageHandler) getSampler(fsPath string) (*btdu.PebbleSampler, error) {
 	return sampler, nil
 }
 
+// SamplerStats describes one btdu sampler for metrics reporting.
+type SamplerStats struct {
+	FsPath           string
+	Running          bool
+	SamplesPerSecond float64
+	TotalSamples     uint64
+}
+
+// SamplerStats returns the state of every sampler created so far.
+func (h *UsageHandler) SamplerStats() []SamplerStats {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	stats := make([]SamplerStats, 0, len(h.samplers))
+	for fsPath, sampler := range h.samplers {
+		stats = append(stats, SamplerStats{
+			FsPath:           fsPath,
+			Running:          sampler.IsRunning(),
+			SamplesPerSecond: sampler.SamplesPerSecond(),
+			TotalSamples:     sampler.Session().SampleCount(),
+		})
+	}
+	return stats
+}
+
 func (h *UsageHandler) StartSampling(
 	ctx context.Context,
 	req *connect.Request[apiv1.StartSamplingRequest],
@@ -76,6 +124,21 @@ func (h *UsageHandler) StartSampling(
 		sampler.Clear()
 	}
 
+	target := req.Msg.TargetSamples
+	explicit := target > 0
+	if !explicit {
+		target = h.settings.Get().SampleTarget
+	}
+	// A resumed session already past its target samples until stopped
+	if sampler.Session().SampleCount() >= target {
+		target = 0
+		explicit = true
+	}
+	h.mu.Lock()
+	h.explicitTarget[req.Msg.FsPath] = explicit
+	h.mu.Unlock()
+	sampler.SetTarget(target)
+
 	resumed, err := sampler.Start(context.Background())
 	if err != nil {
 		h.logger.Error("failed to start sampling", "error", err)
@@ -139,6 +202,7 @@ func (h *UsageHandler) GetSamplingStatus(
 
 	if ok && sampler != nil {
 		progress.IsRunning = sampler.IsRunning()
+		progress.TargetSamples = sampler.Target()
 		progress.CurrentPath = sampler.CurrentPath()
 		progress.SamplesPerSecond = sampler.SamplesPerSecond()
 		progress.RecentPaths = sampler.RecentPaths(16)
@@ -391,6 +455,7 @@ func (h *UsageHandler) StreamSamplingProgress(
 
 			if ok && sampler != nil {
 				progress.IsRunning = sampler.IsRunning()
+				progress.TargetSamples = sampler.Target()
 				progress.CurrentPath = sampler.CurrentPath()
 				progress.SamplesPerSecond = sampler.SamplesPerSecond()
 				progress.RecentPaths = sampler.RecentPaths(16)
@@ -0,0 +1,358 @@
+// Package hub lets one gobtr server show the filesystems of many. The
+// agents are ordinary gobtr servers listed in the config file's [hub]
+// section; the hub calls their Connect services with a bearer token each
+// agent was given, health checks them in the background and remembers when
+// each was last seen.
+package hub
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	apiv1 "github.com/elee1766/gobtr/gen/api/v1"
+	"github.com/elee1766/gobtr/gen/api/v1/apiv1connect"
+	"github.com/elee1766/gobtr/pkg/config"
+	"github.com/elee1766/gobtr/pkg/db"
+	"github.com/elee1766/gobtr/pkg/db/queries"
+	"github.com/elee1766/gobtr/pkg/reconcile"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module("hub",
+	fx.Provide(New),
+	fx.Invoke(registerHooks),
+)
+
+const (
+	defaultPollInterval = 30 * time.Second
+	defaultTimeout      = 10 * time.Second
+)
+
+// Hub holds the configured agents
+type Hub struct {
+	logger *slog.Logger
+	db     *db.DB
+
+	mu       sync.RWMutex
+	agents   []*Agent
+	interval time.Duration
+	timeout  time.Duration
+}
+
+// Agent is one agent and clients for the services the hub collects from
+type Agent struct {
+	Name string
+	URL  string
+
+	Filesystem  apiv1connect.FilesystemServiceClient
+	Scrub       apiv1connect.ScrubServiceClient
+	Balance     apiv1connect.BalanceServiceClient
+	Diagnostics apiv1connect.DiagnosticsServiceClient
+	health      apiv1connect.HealthServiceClient
+
+	client *http.Client
+
+	mu     sync.Mutex
+	status Status
+}
+
+// Status is what the hub last saw of an agent
+type Status struct {
+	LastSeen    time.Time // Zero if never reached
+	LastChecked time.Time // Zero if not checked yet
+	LastError   string
+	Latency     time.Duration // Of the last health check
+	ReadOnly    bool
+}
+
+// Healthy reports whether the last check or call worked
+func (s Status) Healthy() bool {
+	return !s.LastChecked.IsZero() && s.LastError == ""
+}
+
+func New(logger *slog.Logger, cfg *config.Config, db *db.DB) (*Hub, error) {
+	h := &Hub{
+		logger: logger.With("component", "hub"),
+		db:     db,
+	}
+	if err := h.configure(cfg.Hub); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// configure replaces the agents with those in c. Agents that stay keep
+// their status; the status of new ones comes from the database.
+func (h *Hub) configure(c config.HubFile) error {
+	interval := c.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	h.mu.RLock()
+	prev := make(map[string]*Agent, len(h.agents))
+	for _, a := range h.agents {
+		prev[a.Name] = a
+	}
+	h.mu.RUnlock()
+
+	var stored map[string]*queries.AgentState
+	if len(c.Agents) > 0 {
+		var err error
+		if stored, err = queries.ListAgentStates(h.db.Conn()); err != nil {
+			return fmt.Errorf("load agent states: %w", err)
+		}
+	}
+
+	agents := make([]*Agent, 0, len(c.Agents))
+	for _, ac := range c.Agents {
+		a, err := newAgent(ac, timeout)
+		if err != nil {
+			return fmt.Errorf("hub agent %q: %w", ac.Name, err)
+		}
+		if p, ok := prev[a.Name]; ok {
+			a.status = p.Status()
+		} else if s, ok := stored[a.Name]; ok {
+			a.status = Status{LastSeen: s.LastSeen, LastChecked: s.LastChecked, LastError: s.LastError}
+		}
+		agents = append(agents, a)
+	}
+
+	h.mu.Lock()
+	old := h.agents
+	h.agents = agents
+	h.interval = interval
+	h.timeout = timeout
+	h.mu.Unlock()
+
+	for _, a := range old {
+		a.client.CloseIdleConnections()
+	}
+	if len(agents) > 0 {
+		h.logger.Info("hub configured", "agents", len(agents), "poll_interval", interval)
+	}
+	return nil
+}
+
+func newAgent(c config.AgentFile, timeout time.Duration) (*Agent, error) {
+	token := c.Token
+	if c.TokenFile != "" {
+		b, err := os.ReadFile(c.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("read token_file: %w", err)
+		}
+		token = strings.TrimSpace(string(b))
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.CA != "" {
+		pem, err := os.ReadFile(c.CA)
+		if err != nil {
+			return nil, fmt.Errorf("read ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca %s has no certificates", c.CA)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	client := &http.Client{
+		Transport: &bearerTransport{token: token, next: transport},
+		Timeout:   timeout,
+	}
+
+	url := strings.TrimSuffix(c.URL, "/")
+	return &Agent{
+		Name:        c.Name,
+		URL:         url,
+		Filesystem:  apiv1connect.NewFilesystemServiceClient(client, url),
+		Scrub:       apiv1connect.NewScrubServiceClient(client, url),
+		Balance:     apiv1connect.NewBalanceServiceClient(client, url),
+		Diagnostics: apiv1connect.NewDiagnosticsServiceClient(client, url),
+		health:      apiv1connect.NewHealthServiceClient(client, url),
+		client:      client,
+	}, nil
+}
+
+// bearerTransport authenticates every request to an agent with its token
+type bearerTransport struct {
+	token string
+	next  http.RoundTripper
+}
+
+func (t *bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+t.token)
+	return t.next.RoundTrip(r)
+}
+
+// Agents returns the configured agents, in config file order
+func (h *Hub) Agents() []*Agent {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.agents
+}
+
+func (a *Agent) Status() Status {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.status
+}
+
+// observe records the outcome of a call to the agent
+func (a *Agent) observe(err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	a.status.LastChecked = now
+	if err != nil {
+		a.status.LastError = err.Error()
+		return
+	}
+	a.status.LastSeen = now
+	a.status.LastError = ""
+}
+
+// Result is what one agent returned to Collect
+type Result[T any] struct {
+	Agent *Agent
+	Value T
+	Err   error
+}
+
+// Collect calls fn on every agent at once, each with the hub's timeout, and
+// returns the results in agent order. Failures count against the agent's
+// health like a failed check.
+func Collect[T any](ctx context.Context, h *Hub, fn func(ctx context.Context, a *Agent) (T, error)) []Result[T] {
+	h.mu.RLock()
+	agents, timeout := h.agents, h.timeout
+	h.mu.RUnlock()
+
+	results := make([]Result[T], len(agents))
+	var wg sync.WaitGroup
+	for i, a := range agents {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			callCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			v, err := fn(callCtx, a)
+			// The caller giving up says nothing about the agent, but running
+			// out of the hub's timeout does
+			if ctx.Err() == nil || err == nil {
+				a.observe(err)
+			}
+			results[i] = Result[T]{Agent: a, Value: v, Err: err}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// checkAll health checks every agent and stores what it saw. Collect
+// records each outcome, so this only compares and stores the status.
+func (h *Hub) checkAll(ctx context.Context) {
+	results := Collect(ctx, h, func(ctx context.Context, a *Agent) (Status, error) {
+		before := a.Status()
+		return before, h.check(ctx, a)
+	})
+	for _, r := range results {
+		a, before, after := r.Agent, r.Value, r.Agent.Status()
+		if after.LastChecked.Equal(before.LastChecked) {
+			// Not recorded, the check was cut short
+			continue
+		}
+
+		switch {
+		case r.Err != nil && (before.Healthy() || before.LastChecked.IsZero()):
+			h.logger.Warn("agent is down", "agent", a.Name, "error", r.Err)
+		case r.Err == nil && !before.Healthy():
+			h.logger.Info("agent is up", "agent", a.Name)
+		}
+
+		if err := queries.UpsertAgentState(h.db.Conn(), &queries.AgentState{
+			Name:        a.Name,
+			LastSeen:    after.LastSeen,
+			LastChecked: after.LastChecked,
+			LastError:   after.LastError,
+		}); err != nil {
+			h.logger.Warn("failed to store agent state", "agent", a.Name, "error", err)
+		}
+	}
+}
+
+// check asks the agent for its health, then lists its filesystems, which
+// unlike the health check needs the token to be accepted
+func (h *Hub) check(ctx context.Context, a *Agent) error {
+	start := time.Now()
+	health, err := a.health.Check(ctx, connect.NewRequest(&apiv1.HealthCheckRequest{}))
+	if err != nil {
+		return err
+	}
+	latency := time.Since(start)
+	if health.Msg.Status != apiv1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("agent is %s: %s", health.Msg.Status, health.Msg.Message)
+	}
+	if _, err := a.Filesystem.ListTrackedFilesystems(ctx, connect.NewRequest(&apiv1.ListTrackedFilesystemsRequest{})); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.status.Latency = latency
+	a.status.ReadOnly = health.Msg.ReadOnly
+	a.mu.Unlock()
+	return nil
+}
+
+func registerHooks(lc fx.Lifecycle, h *Hub, reconciler *reconcile.Reconciler) {
+	reconciler.Subscribe(func(file *config.File) {
+		if err := h.configure(file.Hub); err != nil {
+			h.logger.Error("failed to apply hub config", "error", err)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				for {
+					h.checkAll(ctx)
+
+					h.mu.RLock()
+					interval := h.interval
+					h.mu.RUnlock()
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(interval):
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
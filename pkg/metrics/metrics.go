@@ -0,0 +1,339 @@
+package metrics
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/elee1766/gobtr/pkg/btrfs"
+	"github.com/elee1766/gobtr/pkg/db"
+	"github.com/elee1766/gobtr/pkg/db/queries"
+	"github.com/elee1766/gobtr/pkg/fragmap"
+	"github.com/elee1766/gobtr/pkg/handlers"
+	"github.com/elee1766/gobtr/pkg/hub"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/fx"
+)
+
+const namespace = "gobtr"
+
+// Collector is a prometheus.Collector that reads btrfs state for every tracked
+// filesystem at scrape time. Only the fragmap scan is cached between scrapes,
+// until the filesystem generation changes.
+type Collector struct {
+	logger       *slog.Logger
+	db           *db.DB
+	btrfsManager *btrfs.Manager
+	usage        *handlers.UsageHandler
+	fragmapCache *fragmap.Cache
+	hub          *hub.Hub
+	registry     *prometheus.Registry
+}
+
+type CollectorParams struct {
+	fx.In
+
+	Logger       *slog.Logger
+	DB           *db.DB
+	BtrfsManager *btrfs.Manager
+	Usage        *handlers.UsageHandler
+	FragMapCache *fragmap.Cache
+	Hub          *hub.Hub
+}
+
+func NewCollector(p CollectorParams) (*Collector, error) {
+	c := &Collector{
+		logger:       p.Logger.With("component", "metrics"),
+		db:           p.DB,
+		btrfsManager: p.BtrfsManager,
+		usage:        p.Usage,
+		fragmapCache: p.FragMapCache,
+		hub:          p.Hub,
+		registry:     prometheus.NewRegistry(),
+	}
+
+	if err := c.registry.Register(c); err != nil {
+		return nil, err
+	}
+	if err := c.registry.Register(collectors.NewGoCollector()); err != nil {
+		return nil, err
+	}
+	if err := c.registry.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Handler returns the HTTP handler serving the metrics in the Prometheus and
+// OpenMetrics exposition formats.
+func (c *Collector) Handler() http.Handler {
+	return promhttp.HandlerFor(c.registry, promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+		ErrorLog:          slog.NewLogLogger(c.logger.Handler(), slog.LevelError),
+	})
+}
+
+func newDesc(name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, labels, nil)
+}
+
+var (
+	fsLabels     = []string{"uuid", "path"}
+	allocLabels  = []string{"uuid", "path", "type", "profile"}
+	deviceLabels = []string{"uuid", "path", "devid", "device"}
+
+	descFilesystemInfo = newDesc("filesystem_info", "Tracked btrfs filesystem.", "uuid", "path", "label")
+	descFilesystemUp   = newDesc("filesystem_up", "Whether the filesystem could be read during the last scrape.", fsLabels...)
+
+	descAllocTotal    = newDesc("allocation_total_bytes", "Logical bytes allocated to chunks of this type and profile.", allocLabels...)
+	descAllocUsed     = newDesc("allocation_used_bytes", "Logical bytes used within allocated chunks.", allocLabels...)
+	descAllocDisk     = newDesc("allocation_disk_total_bytes", "Raw device bytes allocated to chunks of this type and profile.", allocLabels...)
+	descAllocDiskUsed = newDesc("allocation_disk_used_bytes", "Raw device bytes used within allocated chunks.", allocLabels...)
+	descAllocReserved = newDesc("allocation_reserved_bytes", "Bytes reserved for pending allocations.", allocLabels...)
+	descAllocPinned   = newDesc("allocation_pinned_bytes", "Bytes pinned until the next transaction commit.", allocLabels...)
+	descAllocReadonly = newDesc("allocation_readonly_bytes", "Bytes in read-only block groups.", allocLabels...)
+	descAllocMayUse   = newDesc("allocation_may_use_bytes", "Bytes reserved for delayed allocations.", allocLabels...)
+
+	descGlobalReserve     = newDesc("global_reserve_bytes", "Size of the global metadata reserve.", fsLabels...)
+	descGlobalReserveUsed = newDesc("global_reserve_used_bytes", "Bytes currently taken from the global metadata reserve.", fsLabels...)
+
+	descDeviceSize      = newDesc("device_size_bytes", "Size of the device as seen by btrfs.", deviceLabels...)
+	descDeviceAllocated = newDesc("device_allocated_bytes", "Bytes of the device allocated to chunks.", deviceLabels...)
+	descDeviceErrors    = newDesc("device_errors_total", "Device error counters from the btrfs device stats.", append(deviceLabels, "type")...)
+
+	descFreeSpaceFrag = newDesc("fragmap_free_space_frag_score", "Free-space fragmentation score (0-100, higher is more fragmented).", deviceLabels...)
+	descScatter       = newDesc("fragmap_scatter_score", "Chunk layout scatter score (0-100, higher is more scattered).", deviceLabels...)
+	descFreeRegions   = newDesc("fragmap_free_regions", "Number of unallocated regions on the device.", deviceLabels...)
+	descLargestFree   = newDesc("fragmap_largest_free_bytes", "Largest contiguous unallocated region on the device.", deviceLabels...)
+
+	descScrubRunning       = newDesc("scrub_running", "Whether a scrub is currently running.", fsLabels...)
+	descScrubProgress      = newDesc("scrub_progress_ratio", "Fraction of the filesystem scrubbed by the current or last scrub.", fsLabels...)
+	descScrubBytes         = newDesc("scrub_scrubbed_bytes", "Bytes scrubbed by the current or last scrub.", fsLabels...)
+	descScrubErrors        = newDesc("scrub_errors", "Errors found by the current or last scrub.", append(fsLabels, "type")...)
+	descScrubLastStart     = newDesc("scrub_last_start_timestamp_seconds", "When the current or last scrub started.", fsLabels...)
+	descScrubLastSuccess   = newDesc("scrub_last_success_timestamp_seconds", "When the last scrub finished without being interrupted.", fsLabels...)
+	descBalanceRunning     = newDesc("balance_running", "Whether a balance is currently running.", fsLabels...)
+	descBalancePaused      = newDesc("balance_paused", "Whether a balance is currently paused.", fsLabels...)
+	descBalanceExpected    = newDesc("balance_expected_chunks", "Chunks the current balance expects to relocate.", fsLabels...)
+	descBalanceRelocated   = newDesc("balance_relocated_chunks", "Chunks relocated by the current balance.", fsLabels...)
+	descBalanceLastSuccess = newDesc("balance_last_success_timestamp_seconds", "When the last balance started by gobtr completed.", fsLabels...)
+
+	descSamplerRunning = newDesc("btdu_sampler_running", "Whether the btdu sampler is running.", "path")
+	descSamplerRate    = newDesc("btdu_samples_per_second", "Current btdu sampling rate.", "path")
+	descSamplerTotal   = newDesc("btdu_samples_total", "Samples collected in the current btdu session.", "path")
+
+	descAgentUp       = newDesc("agent_up", "Whether the last health check of or call to a hub agent worked.", "agent")
+	descAgentLastSeen = newDesc("agent_last_seen_timestamp_seconds", "When a hub agent last answered.", "agent")
+)
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		descFilesystemInfo, descFilesystemUp,
+		descAllocTotal, descAllocUsed, descAllocDisk, descAllocDiskUsed,
+		descAllocReserved, descAllocPinned, descAllocReadonly, descAllocMayUse,
+		descGlobalReserve, descGlobalReserveUsed,
+		descDeviceSize, descDeviceAllocated, descDeviceErrors,
+		descFreeSpaceFrag, descScatter, descFreeRegions, descLargestFree,
+		descScrubRunning, descScrubProgress, descScrubBytes, descScrubErrors,
+		descScrubLastStart, descScrubLastSuccess,
+		descBalanceRunning, descBalancePaused, descBalanceExpected, descBalanceRelocated,
+		descBalanceLastSuccess,
+		descSamplerRunning, descSamplerRate, descSamplerTotal,
+		descAgentUp, descAgentLastSeen,
+	} {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	filesystems, err := c.db.ListFilesystems()
+	if err != nil {
+		c.logger.Error("failed to list filesystems", "error", err)
+		ch <- prometheus.NewInvalidMetric(descFilesystemUp, err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, fs := range filesystems {
+		ch <- prometheus.MustNewConstMetric(descFilesystemInfo, prometheus.GaugeValue, 1, fs.UUID, fs.Path, fs.Label)
+
+		wg.Add(1)
+		go func(uuid, path string) {
+			defer wg.Done()
+			c.collectFilesystem(ch, uuid, path)
+		}(fs.UUID, fs.Path)
+	}
+
+	for _, s := range c.usage.SamplerStats() {
+		ch <- prometheus.MustNewConstMetric(descSamplerRunning, prometheus.GaugeValue, boolToFloat(s.Running), s.FsPath)
+		ch <- prometheus.MustNewConstMetric(descSamplerRate, prometheus.GaugeValue, s.SamplesPerSecond, s.FsPath)
+		ch <- prometheus.MustNewConstMetric(descSamplerTotal, prometheus.CounterValue, float64(s.TotalSamples), s.FsPath)
+	}
+
+	for _, a := range c.hub.Agents() {
+		s := a.Status()
+		ch <- prometheus.MustNewConstMetric(descAgentUp, prometheus.GaugeValue, boolToFloat(s.Healthy()), a.Name)
+		if !s.LastSeen.IsZero() {
+			ch <- prometheus.MustNewConstMetric(descAgentLastSeen, prometheus.GaugeValue, float64(s.LastSeen.Unix()), a.Name)
+		}
+	}
+
+	wg.Wait()
+}
+
+func (c *Collector) collectFilesystem(ch chan<- prometheus.Metric, uuid, path string) {
+	fsInfo, devices, err := btrfs.GetFilesystemAndDeviceInfo(path)
+	if err != nil {
+		c.logger.Warn("failed to read filesystem", "path", path, "error", err)
+		ch <- prometheus.MustNewConstMetric(descFilesystemUp, prometheus.GaugeValue, 0, uuid, path)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(descFilesystemUp, prometheus.GaugeValue, 1, uuid, path)
+
+	// Allocation per type/profile and the global reserve
+	allocs, reserve, err := btrfs.GetAllocationInfoSysfs(fsInfo.UUID)
+	if err != nil {
+		c.logger.Warn("failed to read allocation info", "path", path, "error", err)
+	}
+	for _, a := range allocs {
+		labels := []string{uuid, path, a.Type, a.Profile}
+		ch <- prometheus.MustNewConstMetric(descAllocTotal, prometheus.GaugeValue, float64(a.TotalBytes), labels...)
+		ch <- prometheus.MustNewConstMetric(descAllocUsed, prometheus.GaugeValue, float64(a.UsedBytes), labels...)
+		ch <- prometheus.MustNewConstMetric(descAllocDisk, prometheus.GaugeValue, float64(a.DiskTotal), labels...)
+		ch <- prometheus.MustNewConstMetric(descAllocDiskUsed, prometheus.GaugeValue, float64(a.DiskUsed), labels...)
+		ch <- prometheus.MustNewConstMetric(descAllocReserved, prometheus.GaugeValue, float64(a.BytesReserved), labels...)
+		ch <- prometheus.MustNewConstMetric(descAllocPinned, prometheus.GaugeValue, float64(a.BytesPinned), labels...)
+		ch <- prometheus.MustNewConstMetric(descAllocReadonly, prometheus.GaugeValue, float64(a.BytesReadonly), labels...)
+		ch <- prometheus.MustNewConstMetric(descAllocMayUse, prometheus.GaugeValue, float64(a.BytesMayUse), labels...)
+	}
+	if reserve != nil {
+		ch <- prometheus.MustNewConstMetric(descGlobalReserve, prometheus.GaugeValue, float64(reserve.Size), uuid, path)
+		ch <- prometheus.MustNewConstMetric(descGlobalReserveUsed, prometheus.GaugeValue, float64(reserve.Reserved), uuid, path)
+	}
+
+	// Per-device sizes and error counters
+	errorStats, err := btrfs.GetDeviceErrorStats(fsInfo.UUID)
+	if err != nil {
+		c.logger.Warn("failed to read device error stats", "path", path, "error", err)
+	}
+	devPaths := make(map[uint64]string, len(devices))
+	for _, dev := range devices {
+		devPaths[dev.DevID] = dev.Path
+		labels := []string{uuid, path, strconv.FormatUint(dev.DevID, 10), dev.Path}
+		ch <- prometheus.MustNewConstMetric(descDeviceSize, prometheus.GaugeValue, float64(dev.TotalBytes), labels...)
+		ch <- prometheus.MustNewConstMetric(descDeviceAllocated, prometheus.GaugeValue, float64(dev.BytesUsed), labels...)
+
+		if es, ok := errorStats[dev.DevID]; ok {
+			for _, e := range []struct {
+				kind  string
+				count int64
+			}{
+				{"write", es.WriteErrors},
+				{"read", es.ReadErrors},
+				{"flush", es.FlushErrors},
+				{"corruption", es.CorruptionErrors},
+				{"generation", es.GenerationErrors},
+			} {
+				ch <- prometheus.MustNewConstMetric(descDeviceErrors, prometheus.CounterValue, float64(e.count), append(labels, e.kind)...)
+			}
+		}
+	}
+
+	c.collectFragmap(ch, uuid, path, devPaths)
+	c.collectScrub(ch, uuid, path)
+	c.collectBalance(ch, uuid, path)
+}
+
+func (c *Collector) collectFragmap(ch chan<- prometheus.Metric, uuid, path string, devPaths map[uint64]string) {
+	fm, _, err := c.fragmapCache.Get(path)
+	if err != nil {
+		c.logger.Warn("failed to scan fragmap", "path", path, "error", err)
+		return
+	}
+
+	for _, dev := range fm.Devices {
+		bm, err := fm.BuildDeviceBlockMap(dev.ID)
+		if err != nil {
+			continue
+		}
+		stats := bm.CalculateStats()
+		labels := []string{uuid, path, strconv.FormatUint(dev.ID, 10), devPaths[dev.ID]}
+		ch <- prometheus.MustNewConstMetric(descFreeSpaceFrag, prometheus.GaugeValue, stats.FreeSpaceFragScore, labels...)
+		ch <- prometheus.MustNewConstMetric(descScatter, prometheus.GaugeValue, stats.ScatterScore, labels...)
+		ch <- prometheus.MustNewConstMetric(descFreeRegions, prometheus.GaugeValue, float64(stats.NumFreeRegions), labels...)
+		ch <- prometheus.MustNewConstMetric(descLargestFree, prometheus.GaugeValue, float64(stats.LargestFree), labels...)
+	}
+}
+
+func (c *Collector) collectScrub(ch chan<- prometheus.Metric, uuid, path string) {
+	status, err := c.btrfsManager.GetScrubStatus(path)
+	if err != nil {
+		c.logger.Warn("failed to get scrub status", "path", path, "error", err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(descScrubRunning, prometheus.GaugeValue, boolToFloat(status.IsRunning), uuid, path)
+	if status.Status == "never_run" {
+		return
+	}
+
+	if status.TotalBytes > 0 {
+		ratio := float64(status.BytesScrubbed) / float64(status.TotalBytes)
+		if ratio > 1 {
+			ratio = 1
+		}
+		ch <- prometheus.MustNewConstMetric(descScrubProgress, prometheus.GaugeValue, ratio, uuid, path)
+	}
+	ch <- prometheus.MustNewConstMetric(descScrubBytes, prometheus.GaugeValue, float64(status.BytesScrubbed), uuid, path)
+	for _, e := range []struct {
+		kind  string
+		count int32
+	}{
+		{"read", status.ReadErrors},
+		{"csum", status.CsumErrors},
+		{"verify", status.VerifyErrors},
+		{"super", status.SuperErrors},
+		{"corrected", status.CorrectedErrors},
+		{"uncorrectable", status.UncorrectableErrors},
+	} {
+		ch <- prometheus.MustNewConstMetric(descScrubErrors, prometheus.GaugeValue, float64(e.count), uuid, path, e.kind)
+	}
+
+	if !status.StartedAt.IsZero() {
+		ch <- prometheus.MustNewConstMetric(descScrubLastStart, prometheus.GaugeValue, float64(status.StartedAt.Unix()), uuid, path)
+	}
+	if status.Status == "finished" && !status.FinishedAt.IsZero() {
+		ch <- prometheus.MustNewConstMetric(descScrubLastSuccess, prometheus.GaugeValue, float64(status.FinishedAt.Unix()), uuid, path)
+	}
+}
+
+func (c *Collector) collectBalance(ch chan<- prometheus.Metric, uuid, path string) {
+	status, err := c.btrfsManager.GetBalanceStatus(path)
+	if err != nil {
+		c.logger.Warn("failed to get balance status", "path", path, "error", err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(descBalanceRunning, prometheus.GaugeValue, boolToFloat(status.IsRunning), uuid, path)
+		ch <- prometheus.MustNewConstMetric(descBalancePaused, prometheus.GaugeValue, boolToFloat(status.IsPaused), uuid, path)
+		if status.IsRunning || status.IsPaused {
+			ch <- prometheus.MustNewConstMetric(descBalanceExpected, prometheus.GaugeValue, float64(status.TotalChunks), uuid, path)
+			ch <- prometheus.MustNewConstMetric(descBalanceRelocated, prometheus.GaugeValue, float64(status.Relocated), uuid, path)
+		}
+	}
+
+	if finishedAt, err := queries.GetLastBalanceFinish(c.db.Conn(), path, "completed"); err == nil {
+		ch <- prometheus.MustNewConstMetric(descBalanceLastSuccess, prometheus.GaugeValue, float64(finishedAt.Unix()), uuid, path)
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
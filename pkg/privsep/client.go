@@ -0,0 +1,206 @@
+package privsep
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// maxIdle is how many connections the client keeps open between calls
+const maxIdle = 4
+
+// Client asks a `gobtr helper` to perform operations. It is safe for
+// concurrent use; concurrent calls use separate connections.
+type Client struct {
+	path string
+
+	mu   sync.Mutex
+	idle []*net.UnixConn
+}
+
+func NewClient(path string) *Client {
+	return &Client{path: path}
+}
+
+// Path returns the helper's socket
+func (c *Client) Path() string {
+	return c.path
+}
+
+// Close closes the idle connections
+func (c *Client) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, conn := range c.idle {
+		conn.Close()
+	}
+	c.idle = nil
+}
+
+// Ping checks that the helper answers
+func (c *Client) Ping() error {
+	_, _, err := c.call(&request{Op: opPing}, nil)
+	return err
+}
+
+func (c *Client) Open(path string) (*os.File, error) {
+	_, f, err := c.call(&request{Op: opOpen, Path: path}, nil)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	if f == nil {
+		return nil, fmt.Errorf("open %s: helper sent no file", path)
+	}
+	return f, nil
+}
+
+func (c *Client) Ioctl(f *os.File, req uintptr, arg any) error {
+	v := reflect.ValueOf(arg)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("ioctl argument must be a pointer, got %T", arg)
+	}
+	size := int(v.Elem().Type().Size())
+	if size != ioctlSize(req) {
+		return fmt.Errorf("ioctl %#x takes %d bytes, got %T (%d bytes)", req, ioctlSize(req), arg, size)
+	}
+	buf := unsafe.Slice((*byte)(v.UnsafePointer()), size)
+
+	resp, _, err := c.call(&request{Op: opIoctl, Ioctl: uint64(req), Arg: buf}, f)
+	if err != nil {
+		return err
+	}
+	if len(resp.Arg) != size {
+		return fmt.Errorf("ioctl %#x: helper returned %d bytes, want %d", req, len(resp.Arg), size)
+	}
+	copy(buf, resp.Arg)
+	return nil
+}
+
+func (c *Client) LogicalIno(f *os.File, logical, flags uint64, buf []byte) error {
+	resp, _, err := c.call(&request{Op: opLogicalIno, Logical: logical, Flags: flags, Size: len(buf)}, f)
+	if err != nil {
+		return err
+	}
+	copy(buf, resp.Buf)
+	return nil
+}
+
+func (c *Client) Fiemap(f *os.File, buf []byte) error {
+	resp, _, err := c.call(&request{Op: opFiemap, Arg: buf}, f)
+	if err != nil {
+		return err
+	}
+	if len(resp.Arg) != len(buf) {
+		return fmt.Errorf("fiemap: helper returned %d bytes, want %d", len(resp.Arg), len(buf))
+	}
+	copy(buf, resp.Arg)
+	return nil
+}
+
+func (c *Client) Run(ctx context.Context, args ...string) ([]byte, error) {
+	// A run holds its connection until the command exits, and closing it
+	// is how the helper learns to kill the command
+	conn, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if err := send(conn, &request{Op: opRun, Args: args}, nil); err != nil {
+		return nil, c.transportErr(ctx, err)
+	}
+	var resp response
+	if _, err := receive(conn, &resp, ""); err != nil {
+		return nil, c.transportErr(ctx, err)
+	}
+	return resp.Output, resp.err()
+}
+
+func (c *Client) Spawn(args ...string) error {
+	_, _, err := c.call(&request{Op: opSpawn, Args: args}, nil)
+	return err
+}
+
+func (c *Client) transportErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return fmt.Errorf("privileged helper at %s: %w", c.path, err)
+}
+
+// call sends req, with f's descriptor if f isn't nil, and waits for the
+// response. It returns the file the helper sent back, if any.
+func (c *Client) call(req *request, f *os.File) (*response, *os.File, error) {
+	conn, pooled, err := c.get()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = send(conn, req, f)
+	if err != nil && pooled {
+		// The helper may have restarted since the connection was last used
+		conn.Close()
+		if conn, err = c.dial(); err != nil {
+			return nil, nil, err
+		}
+		err = send(conn, req, f)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, nil, c.transportErr(context.Background(), err)
+	}
+	var resp response
+	got, err := receive(conn, &resp, req.Path)
+	if err != nil {
+		conn.Close()
+		return nil, nil, c.transportErr(context.Background(), err)
+	}
+	c.put(conn)
+
+	if err := resp.err(); err != nil {
+		if got != nil {
+			got.Close()
+		}
+		return nil, nil, err
+	}
+	return &resp, got, nil
+}
+
+func (c *Client) dial() (*net.UnixConn, error) {
+	conn, err := net.DialUnix("unixpacket", nil, &net.UnixAddr{Name: c.path, Net: "unixpacket"})
+	if err != nil {
+		return nil, fmt.Errorf("privileged helper: %w", err)
+	}
+	return conn, nil
+}
+
+// get returns an idle connection, or a new one. pooled reports which.
+func (c *Client) get() (conn *net.UnixConn, pooled bool, err error) {
+	c.mu.Lock()
+	if n := len(c.idle); n > 0 {
+		conn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return conn, true, nil
+	}
+	c.mu.Unlock()
+	conn, err = c.dial()
+	return conn, false, err
+}
+
+func (c *Client) put(conn *net.UnixConn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.idle) >= maxIdle {
+		conn.Close()
+		return
+	}
+	c.idle = append(c.idle, conn)
+}
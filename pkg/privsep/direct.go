@@ -0,0 +1,76 @@
+package privsep
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"github.com/dennwc/ioctl"
+)
+
+// Direct performs every operation in process, so the process needs the
+// capabilities itself
+type Direct struct{}
+
+func (Direct) Open(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NOATIME, 0)
+	if err != nil {
+		// O_NOATIME is only allowed on files we own or with CAP_FOWNER
+		f, err = os.Open(path)
+	}
+	return f, err
+}
+
+func (Direct) Ioctl(f *os.File, req uintptr, arg any) error {
+	return ioctl.Do(f, req, arg)
+}
+
+func (Direct) LogicalIno(f *os.File, logical, flags uint64, buf []byte) error {
+	args := logicalInoArgs{
+		Logical: logical,
+		Size:    uint64(len(buf)),
+		Flags:   flags,
+		Inodes:  uint64(uintptr(unsafe.Pointer(&buf[0]))),
+	}
+	err := ioctl.Do(f, logicalInoRequest(flags), &args)
+	runtime.KeepAlive(buf)
+	return err
+}
+
+func (Direct) Fiemap(f *os.File, buf []byte) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), fiemapRequest, uintptr(unsafe.Pointer(&buf[0])))
+	runtime.KeepAlive(buf)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func (Direct) Run(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "btrfs", args...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	return out.Bytes(), err
+}
+
+func (Direct) Spawn(args ...string) error {
+	return spawn(args)
+}
+
+// spawn starts btrfs in a new session with no stdio, so it is independent
+// of this process, and reaps it when it exits
+func spawn(args []string) error {
+	cmd := exec.Command("btrfs", args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
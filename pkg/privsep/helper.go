@@ -0,0 +1,480 @@
+package privsep
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/dennwc/ioctl"
+	"golang.org/x/sys/unix"
+)
+
+// btrfsSuperMagic is BTRFS_SUPER_MAGIC, the f_type of btrfs in statfs
+const btrfsSuperMagic = 0x9123683e
+
+// mountsTTL is how long the helper trusts its list of tracked filesystems
+// before asking for it again
+const mountsTTL = 5 * time.Second
+
+// fsInfoArgs matches struct btrfs_ioctl_fs_info_args, up to the fsid
+type fsInfoArgs struct {
+	MaxID      uint64
+	NumDevices uint64
+	FSID       [16]byte
+	Rest       [992]byte
+}
+
+var ioctlFsInfo = ioctl.IOR(btrfsIoctlMagic, 31, unsafe.Sizeof(fsInfoArgs{}))
+
+// Helper serves the privileged side. It only opens files and directories
+// beneath the mounts of tracked filesystems, never hands back a descriptor
+// that can be read, only runs the ioctls and commands in its allowlists,
+// and only talks to root and the one user it serves.
+type Helper struct {
+	logger *slog.Logger
+	uid    int
+	// filesystems returns the mount paths of the tracked filesystems
+	filesystems func() ([]string, error)
+
+	mu      sync.Mutex
+	mounts  []mount
+	checked time.Time
+}
+
+// mount is a tracked filesystem's mount and the fsid of the filesystem
+type mount struct {
+	path string
+	fsid [16]byte
+}
+
+// NewHelper returns a helper serving uid, on the filesystems listed by
+// filesystems
+func NewHelper(logger *slog.Logger, uid int, filesystems func() ([]string, error)) *Helper {
+	return &Helper{
+		logger:      logger.With("component", "helper"),
+		uid:         uid,
+		filesystems: filesystems,
+	}
+}
+
+// Listen creates the helper's socket at path, owned by the served user and
+// only usable by them. A stale socket from a previous run is removed
+// first; any other file at the path is an error.
+func (h *Helper) Listen(path string) (*net.UnixListener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode().Type() != fs.ModeSocket {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	l, err := net.ListenUnix("unixpacket", &net.UnixAddr{Name: path, Net: "unixpacket"})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	if err := os.Chown(path, h.uid, -1); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("chown %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Serve handles connections on l until ctx is done
+func (h *Helper) Serve(ctx context.Context, l *net.UnixListener) error {
+	stop := context.AfterFunc(ctx, func() { l.Close() })
+	defer stop()
+
+	for {
+		conn, err := l.AcceptUnix()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go h.serveConn(conn)
+	}
+}
+
+func (h *Helper) serveConn(conn *net.UnixConn) {
+	defer conn.Close()
+
+	uid, err := peerUID(conn)
+	if err != nil {
+		h.logger.Warn("can't identify peer, closing", "error", err)
+		return
+	}
+	if uid != 0 && uid != h.uid {
+		h.logger.Warn("refused connection from another user", "uid", uid)
+		return
+	}
+
+	for {
+		var req request
+		f, err := receive(conn, &req, "")
+		if err != nil {
+			return
+		}
+
+		resp, out := h.handle(conn, &req, f)
+		if f != nil {
+			f.Close()
+		}
+		err = send(conn, resp, out)
+		if out != nil {
+			out.Close()
+		}
+		// A run's connection was watched for the client going away, so
+		// it can't carry another request
+		if err != nil || req.Op == opRun {
+			return
+		}
+	}
+}
+
+// handle performs req on f, returning the response and the file to send
+// back with it
+func (h *Helper) handle(conn *net.UnixConn, req *request, f *os.File) (*response, *os.File) {
+	resp := &response{}
+	var out *os.File
+	var err error
+
+	switch req.Op {
+	case opPing:
+	case opOpen:
+		out, err = h.open(req.Path)
+	case opIoctl:
+		resp.Arg, err = h.ioctl(f, uintptr(req.Ioctl), req.Arg)
+	case opLogicalIno:
+		resp.Buf, err = h.logicalIno(f, req.Logical, req.Flags, req.Size)
+	case opFiemap:
+		resp.Arg, err = h.fiemap(f, req.Arg)
+	case opRun:
+		resp.Output, err = h.run(conn, req.Args)
+	case opSpawn:
+		if err = h.checkCommand(opSpawn, req.Args); err == nil {
+			if err = spawn(req.Args); err == nil {
+				h.logger.Info("started btrfs", "args", req.Args)
+			}
+		}
+	default:
+		err = fmt.Errorf("unknown op %q", req.Op)
+	}
+
+	if err != nil {
+		var errno syscall.Errno
+		if !errors.As(err, &errno) {
+			h.logger.Warn("refused request", "op", req.Op, "error", err)
+		}
+		resp.setErr(err)
+	}
+	return resp, out
+}
+
+// open opens path beneath the mount of the tracked filesystem holding it,
+// refusing symlinks and other mounts on the way. The descriptor is O_PATH,
+// so the client can only stat it and send it back.
+func (h *Helper) open(path string) (*os.File, error) {
+	if !filepath.IsAbs(path) || filepath.Clean(path) != path {
+		return nil, fmt.Errorf("path %q must be absolute and clean", path)
+	}
+	mounts, err := h.tracked()
+	if err != nil {
+		return nil, err
+	}
+	var root string
+	for _, m := range mounts {
+		if beneath(path, m.path) && len(m.path) > len(root) {
+			root = m.path
+		}
+	}
+	if root == "" {
+		return nil, fmt.Errorf("%s is not on a tracked filesystem", path)
+	}
+
+	dir, err := unix.Open(root, unix.O_PATH|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer unix.Close(dir)
+	rel := strings.TrimPrefix(strings.TrimPrefix(path, root), "/")
+	if rel == "" {
+		rel = "."
+	}
+	fd, err := unix.Openat2(dir, rel, &unix.OpenHow{
+		Flags:   unix.O_PATH | unix.O_CLOEXEC,
+		Resolve: unix.RESOLVE_BENEATH | unix.RESOLVE_NO_SYMLINKS | unix.RESOLVE_NO_MAGICLINKS | unix.RESOLVE_NO_XDEV,
+	})
+	if err != nil {
+		return nil, err
+	}
+	f := os.NewFile(uintptr(fd), path)
+	if err := checkType(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func (h *Helper) ioctl(f *os.File, req uintptr, arg []byte) ([]byte, error) {
+	name, ok := ioctls[req]
+	if !ok {
+		return nil, fmt.Errorf("ioctl %#x is not allowed", req)
+	}
+	if len(arg) != ioctlSize(req) {
+		return nil, fmt.Errorf("%s takes %d bytes, got %d", name, ioctlSize(req), len(arg))
+	}
+	rf, err := h.reopen(f)
+	if err != nil {
+		return nil, err
+	}
+	defer rf.Close()
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, rf.Fd(), req, uintptr(unsafe.Pointer(&arg[0])))
+	runtime.KeepAlive(arg)
+	if errno != 0 {
+		return nil, errno
+	}
+	return arg, nil
+}
+
+func (h *Helper) logicalIno(f *os.File, logical, flags uint64, size int) ([]byte, error) {
+	if size <= 0 || size > maxLogicalInoSize {
+		return nil, fmt.Errorf("logical_ino buffer must be 1 to %d bytes, got %d", maxLogicalInoSize, size)
+	}
+	rf, err := h.reopen(f)
+	if err != nil {
+		return nil, err
+	}
+	defer rf.Close()
+	buf := make([]byte, size)
+	if err := (Direct{}).LogicalIno(rf, logical, flags, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (h *Helper) fiemap(f *os.File, buf []byte) ([]byte, error) {
+	if len(buf) > maxFiemapSize || fiemapSize(buf) != len(buf) {
+		return nil, fmt.Errorf("fiemap buffer must be a struct fiemap and its extents, at most %d bytes", maxFiemapSize)
+	}
+	rf, err := h.reopen(f)
+	if err != nil {
+		return nil, err
+	}
+	defer rf.Close()
+	if err := (Direct{}).Fiemap(rf, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// reopen opens a readable copy of f, a file or directory on a tracked
+// filesystem, for an ioctl. f is usually an O_PATH descriptor from open.
+func (h *Helper) reopen(f *os.File) (*os.File, error) {
+	if f == nil {
+		return nil, errors.New("no file sent")
+	}
+	if err := checkBtrfs(f); err != nil {
+		return nil, err
+	}
+	if err := checkType(f); err != nil {
+		return nil, err
+	}
+	rf, err := os.OpenFile(fmt.Sprintf("/proc/self/fd/%d", f.Fd()), os.O_RDONLY|syscall.O_NOATIME, 0)
+	if err != nil {
+		return nil, err
+	}
+	fsid, err := fsidOf(rf)
+	if err == nil {
+		err = h.checkTracked(fsid)
+	}
+	if err != nil {
+		rf.Close()
+		return nil, err
+	}
+	return rf, nil
+}
+
+// checkCommand is checkCommand, also requiring the path to be the mount of
+// a tracked filesystem
+func (h *Helper) checkCommand(o op, args []string) error {
+	if err := checkCommand(o, args); err != nil {
+		return err
+	}
+	mounts, err := h.tracked()
+	if err != nil {
+		return err
+	}
+	path := args[len(args)-1]
+	for _, m := range mounts {
+		if m.path == path {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not a tracked filesystem", path)
+}
+
+// tracked returns the mounts of the tracked filesystems, asking for them
+// again once they are mountsTTL old
+func (h *Helper) tracked() ([]mount, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.mounts != nil && time.Since(h.checked) < mountsTTL {
+		return h.mounts, nil
+	}
+
+	paths, err := h.filesystems()
+	if err != nil {
+		return nil, fmt.Errorf("list tracked filesystems: %w", err)
+	}
+	mounts := []mount{}
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			continue
+		}
+		f, err := os.OpenFile(p, os.O_RDONLY|syscall.O_DIRECTORY, 0)
+		if err != nil {
+			continue
+		}
+		fsid, err := fsidOf(f)
+		f.Close()
+		if err != nil {
+			continue
+		}
+		mounts = append(mounts, mount{path: filepath.Clean(p), fsid: fsid})
+	}
+	h.mounts, h.checked = mounts, time.Now()
+	return mounts, nil
+}
+
+func (h *Helper) checkTracked(fsid [16]byte) error {
+	mounts, err := h.tracked()
+	if err != nil {
+		return err
+	}
+	for _, m := range mounts {
+		if m.fsid == fsid {
+			return nil
+		}
+	}
+	return errors.New("not on a tracked filesystem")
+}
+
+// beneath reports whether path is root or inside it
+func beneath(path, root string) bool {
+	return path == root || root == "/" || strings.HasPrefix(path, root+"/")
+}
+
+// fsidOf returns the fsid of the btrfs filesystem holding f
+func fsidOf(f *os.File) ([16]byte, error) {
+	var args fsInfoArgs
+	if err := ioctl.Do(f, ioctlFsInfo, &args); err != nil {
+		return [16]byte{}, fmt.Errorf("not on a btrfs filesystem: %w", err)
+	}
+	return args.FSID, nil
+}
+
+// run runs an allowed command, killing it if the client hangs up first
+func (h *Helper) run(conn *net.UnixConn, args []string) ([]byte, error) {
+	if err := h.checkCommand(opRun, args); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		// Nothing else is sent on a run's connection, so any read
+		// returning means the client is gone
+		conn.Read(make([]byte, 1))
+		cancel()
+	}()
+
+	h.logger.Info("running btrfs", "args", args)
+	cmd := exec.CommandContext(ctx, "btrfs", args...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		h.logger.Info("client went away, killed btrfs", "args", args)
+	}
+
+	output := out.Bytes()
+	if len(output) > maxOutput {
+		output = output[len(output)-maxOutput:]
+	}
+	if err != nil {
+		return output, fmt.Errorf("btrfs %s %s: %w", args[0], args[1], err)
+	}
+	return output, nil
+}
+
+// checkType refuses anything but files and directories, so reopening
+// can't open a device or block on a fifo
+func checkType(f *os.File) error {
+	var st syscall.Stat_t
+	if err := syscall.Fstat(int(f.Fd()), &st); err != nil {
+		return err
+	}
+	if t := st.Mode & syscall.S_IFMT; t != syscall.S_IFREG && t != syscall.S_IFDIR {
+		return errors.New("not a file or directory")
+	}
+	return nil
+}
+
+// checkBtrfs refuses files that aren't on btrfs
+func checkBtrfs(f *os.File) error {
+	if f == nil {
+		return errors.New("no file sent")
+	}
+	var st syscall.Statfs_t
+	if err := syscall.Fstatfs(int(f.Fd()), &st); err != nil {
+		return err
+	}
+	if uint32(st.Type) != btrfsSuperMagic {
+		return errors.New("not on a btrfs filesystem")
+	}
+	return nil
+}
+
+func peerUID(conn *net.UnixConn) (int, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var cred *syscall.Ucred
+	var credErr error
+	err = raw.Control(func(fd uintptr) {
+		cred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if credErr != nil {
+		return 0, credErr
+	}
+	return int(cred.Uid), nil
+}
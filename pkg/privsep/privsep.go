@@ -0,0 +1,84 @@
+// Package privsep keeps the operations that need root out of the web server.
+// Tree searches, inode lookups, balance, scrub and defrag need CAP_SYS_ADMIN,
+// and reading other users' files needs CAP_DAC_READ_SEARCH. Everything that
+// does those goes through a Backend, which either performs them in process
+// (Direct) or asks a privileged `gobtr helper` over a unix socket (Client),
+// so the server itself can run as an ordinary user.
+package privsep
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/elee1766/gobtr/pkg/config"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module("privsep",
+	fx.Provide(New),
+	fx.Invoke(registerHooks),
+)
+
+// DefaultSocket is where `gobtr helper` listens unless told otherwise
+const DefaultSocket = "/run/gobtr/helper.sock"
+
+// Backend performs the privileged operations
+type Backend interface {
+	// Open opens a file or directory on a btrfs filesystem for the other
+	// operations. Files opened through the helper can't be read.
+	Open(path string) (*os.File, error)
+
+	// Ioctl runs a btrfs ioctl whose argument is the fixed size struct arg
+	// points to. Only the ioctls in the helper's allowlist work brokered.
+	Ioctl(f *os.File, req uintptr, arg any) error
+
+	// LogicalIno runs LOGICAL_INO for the logical address, filling buf with
+	// the kernel's btrfs_data_container
+	LogicalIno(f *os.File, logical, flags uint64, buf []byte) error
+
+	// Fiemap runs FS_IOC_FIEMAP, where buf is a struct fiemap followed by
+	// room for the ExtentCount extents it asks for
+	Fiemap(f *os.File, buf []byte) error
+
+	// Run runs a btrfs command and returns its combined output once it
+	// exits. Canceling ctx kills it.
+	Run(ctx context.Context, args ...string) ([]byte, error)
+
+	// Spawn starts a btrfs command in its own session and doesn't wait for
+	// it, so it outlives the server
+	Spawn(args ...string) error
+}
+
+// New returns the Client for the configured helper, or Direct if there
+// isn't one
+func New(cfg *config.Config) Backend {
+	if cfg.Helper == "" {
+		return Direct{}
+	}
+	return NewClient(cfg.Helper)
+}
+
+func registerHooks(lc fx.Lifecycle, logger *slog.Logger, backend Backend) {
+	logger = logger.With("component", "privsep")
+	c, ok := backend.(*Client)
+	if !ok {
+		return
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			// The helper may come up after us, so this only warns
+			if err := c.Ping(); err != nil {
+				logger.Warn("privileged helper isn't answering, btrfs operations will fail until it does", "socket", c.Path(), "error", err)
+				return nil
+			}
+			logger.Info("using privileged helper", "socket", c.Path())
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			c.Close()
+			return nil
+		},
+	})
+}
@@ -0,0 +1,333 @@
+package privsep
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/dennwc/ioctl"
+)
+
+// The helper speaks JSON over a SOCK_SEQPACKET unix socket, one request or
+// response per packet. The file a request acts on, and the file an open
+// returns, travel alongside as SCM_RIGHTS. Files the helper opens are sent
+// as O_PATH descriptors, which can be stat'ed and sent back but not read.
+
+type op string
+
+const (
+	opPing       op = "ping"
+	opOpen       op = "open"
+	opIoctl      op = "ioctl"
+	opLogicalIno op = "logical_ino"
+	opFiemap     op = "fiemap"
+	opRun        op = "run"
+	opSpawn      op = "spawn"
+)
+
+type request struct {
+	Op      op       `json:"op"`
+	Path    string   `json:"path,omitempty"`    // open
+	Ioctl   uint64   `json:"ioctl,omitempty"`   // ioctl
+	Arg     []byte   `json:"arg,omitempty"`     // ioctl, fiemap
+	Logical uint64   `json:"logical,omitempty"` // logical_ino
+	Flags   uint64   `json:"flags,omitempty"`   // logical_ino
+	Size    int      `json:"size,omitempty"`    // logical_ino
+	Args    []string `json:"args,omitempty"`    // run, spawn
+}
+
+type response struct {
+	Arg    []byte `json:"arg,omitempty"`    // ioctl, fiemap: the argument as the kernel left it
+	Buf    []byte `json:"buf,omitempty"`    // logical_ino
+	Output []byte `json:"output,omitempty"` // run
+	Errno  int    `json:"errno,omitempty"`  // the syscall failed with this
+	Error  string `json:"error,omitempty"`  // the helper refused or failed
+}
+
+// err turns a response's failure back into an error. Errnos stay
+// syscall.Errno so callers can still match them.
+func (r *response) err() error {
+	switch {
+	case r.Errno != 0:
+		return syscall.Errno(r.Errno)
+	case r.Error != "":
+		return errors.New(r.Error)
+	}
+	return nil
+}
+
+func (r *response) setErr(err error) {
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		r.Errno = int(errno)
+		return
+	}
+	r.Error = err.Error()
+}
+
+const (
+	// maxPacket bounds one packet. The largest is a logical_ino response.
+	maxPacket = 256 << 10
+
+	// maxLogicalInoSize bounds a logical_ino buffer, the most LOGICAL_INO
+	// (v1) returns anyway
+	maxLogicalInoSize = 64 << 10
+
+	// maxFiemapSize bounds a fiemap buffer, a struct fiemap and its extents
+	maxFiemapSize = 64 << 10
+
+	// maxOutput is how much of a command's output is sent back; the end is
+	// kept, since that's where btrfs puts its errors
+	maxOutput = 64 << 10
+)
+
+const btrfsIoctlMagic = 0x94
+
+// logicalInoArgs matches struct btrfs_ioctl_logical_ino_args
+type logicalInoArgs struct {
+	Logical  uint64
+	Size     uint64
+	Reserved [3]uint64
+	Flags    uint64
+	Inodes   uint64
+}
+
+var (
+	ioctlLogicalIno   = ioctl.IOWR(btrfsIoctlMagic, 36, unsafe.Sizeof(logicalInoArgs{}))
+	ioctlLogicalInoV2 = ioctl.IOWR(btrfsIoctlMagic, 59, unsafe.Sizeof(logicalInoArgs{}))
+)
+
+// logicalInoRequest picks LOGICAL_INO_V2 when flags need it, and v1 for
+// kernels older than 4.15 otherwise
+func logicalInoRequest(flags uint64) uintptr {
+	if flags != 0 {
+		return ioctlLogicalInoV2
+	}
+	return ioctlLogicalIno
+}
+
+// FS_IOC_FIEMAP, and the sizes of struct fiemap and struct fiemap_extent.
+// Its argument is a struct fiemap followed by its extents.
+const (
+	fiemapRequest    = 0xc020660b
+	fiemapHeaderSize = 32
+	fiemapExtentSize = 56
+)
+
+// fiemapSize is the size of a fiemap buffer, from the extent count in its
+// header
+func fiemapSize(buf []byte) int {
+	if len(buf) < fiemapHeaderSize {
+		return -1
+	}
+	return fiemapHeaderSize + int(binary.NativeEndian.Uint32(buf[24:28]))*fiemapExtentSize
+}
+
+// ioctls the helper runs for anyone allowed to connect. All of them take a
+// fixed size argument with no pointers in it, and only defrag changes
+// anything on disk.
+var ioctls = map[uintptr]string{
+	ioctl.IOW(btrfsIoctlMagic, 16, 48):    "defrag_range",
+	ioctl.IOWR(btrfsIoctlMagic, 17, 4096): "tree_search",
+	ioctl.IOWR(btrfsIoctlMagic, 18, 4096): "ino_lookup",
+	ioctl.IOWR(btrfsIoctlMagic, 30, 4096): "dev_info",
+	ioctl.IOR(btrfsIoctlMagic, 31, 1024):  "fs_info",
+	ioctl.IOR(btrfsIoctlMagic, 34, 1024):  "balance_progress",
+}
+
+// ioctlSize is the argument size encoded in an ioctl request number
+func ioctlSize(req uintptr) int {
+	return int(req>>16) & (1<<14 - 1)
+}
+
+// command is a btrfs subcommand the helper runs, how, and which options it
+// takes
+type command struct {
+	op      op
+	options func(opts []string) error
+}
+
+// commands are the btrfs subcommands the helper runs. Scrub start is
+// spawned since it outlives the request.
+var commands = map[string]command{
+	"scrub start":    {opSpawn, scrubStartOptions},
+	"scrub cancel":   {opRun, noOptions},
+	"balance start":  {opRun, balanceStartOptions},
+	"balance cancel": {opRun, noOptions},
+	"balance pause":  {opRun, noOptions},
+	"balance resume": {opRun, noOptions},
+}
+
+// checkCommand allows args if they name an allowed subcommand, followed by
+// options it takes and the path of the filesystem
+func checkCommand(o op, args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("btrfs %s is not allowed", strings.Join(args, " "))
+	}
+	cmd, ok := commands[args[0]+" "+args[1]]
+	if !ok || cmd.op != o {
+		return fmt.Errorf("btrfs %s %s is not allowed", args[0], args[1])
+	}
+	path := args[len(args)-1]
+	if !filepath.IsAbs(path) || filepath.Clean(path) != path {
+		return fmt.Errorf("btrfs %s %s must end with an absolute, clean path", args[0], args[1])
+	}
+	if err := cmd.options(args[2 : len(args)-1]); err != nil {
+		return fmt.Errorf("btrfs %s %s: %w", args[0], args[1], err)
+	}
+	return nil
+}
+
+func noOptions(opts []string) error {
+	if len(opts) > 0 {
+		return fmt.Errorf("option %q is not allowed", opts[0])
+	}
+	return nil
+}
+
+// scrubStartOptions allows -B, -r, -f and --limit <bytes per second>
+func scrubStartOptions(opts []string) error {
+	for i := 0; i < len(opts); i++ {
+		switch opts[i] {
+		case "-B", "-r", "-f":
+		case "--limit":
+			i++
+			if i == len(opts) || !isNumber(opts[i]) {
+				return errors.New("--limit takes a number")
+			}
+		default:
+			return fmt.Errorf("option %q is not allowed", opts[i])
+		}
+	}
+	return nil
+}
+
+// balanceProfiles are the profiles a balance may convert to
+var balanceProfiles = map[string]bool{
+	"single": true, "dup": true, "raid0": true, "raid1": true, "raid1c3": true,
+	"raid1c4": true, "raid10": true, "raid5": true, "raid6": true,
+}
+
+// balanceStartOptions allows -d, -m and -s with the filters gobtr builds,
+// and -f only where btrfs wants it: converting, or touching system chunks
+func balanceStartOptions(opts []string) error {
+	force, needsForce := false, false
+	for _, opt := range opts {
+		if opt == "-f" {
+			force = true
+			continue
+		}
+		if len(opt) < 2 || !strings.Contains("dms", opt[1:2]) || opt[0] != '-' {
+			return fmt.Errorf("option %q is not allowed", opt)
+		}
+		if opt[1] == 's' {
+			needsForce = true
+		}
+		if len(opt) == 2 {
+			continue
+		}
+		for _, filter := range strings.Split(opt[2:], ",") {
+			name, value, _ := strings.Cut(filter, "=")
+			var ok bool
+			switch name {
+			case "convert":
+				ok = balanceProfiles[value]
+				needsForce = true
+			case "soft":
+				ok = value == "" && !strings.Contains(filter, "=")
+			case "usage", "devid", "limit":
+				ok = isNumber(value)
+			case "drange":
+				lo, hi, found := strings.Cut(value, "..")
+				ok = found && isNumber(lo) && isNumber(hi)
+			}
+			if !ok {
+				return fmt.Errorf("balance filter %q is not allowed", filter)
+			}
+		}
+	}
+	if force && !needsForce {
+		return errors.New("-f is only allowed with convert or system chunk filters")
+	}
+	return nil
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil
+}
+
+// send writes v as one packet, with f's descriptor if f isn't nil
+func send(conn *net.UnixConn, v any, f *os.File) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(b) > maxPacket {
+		return fmt.Errorf("message is %d bytes, more than %d", len(b), maxPacket)
+	}
+	var oob []byte
+	if f != nil {
+		oob = syscall.UnixRights(int(f.Fd()))
+	}
+	_, _, err = conn.WriteMsgUnix(b, oob, nil)
+	return err
+}
+
+// receive reads one packet into v, returning the descriptor that came with
+// it as a file, if any. name names the file.
+func receive(conn *net.UnixConn, v any, name string) (*os.File, error) {
+	buf := make([]byte, maxPacket)
+	oob := make([]byte, syscall.CmsgSpace(4))
+	n, oobn, flags, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		return nil, err
+	}
+
+	var f *os.File
+	if oobn > 0 {
+		msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+		if err != nil {
+			return nil, fmt.Errorf("parse control message: %w", err)
+		}
+		for _, msg := range msgs {
+			fds, err := syscall.ParseUnixRights(&msg)
+			if err != nil {
+				continue
+			}
+			for _, fd := range fds {
+				if f == nil {
+					f = os.NewFile(uintptr(fd), name)
+				} else {
+					syscall.Close(fd)
+				}
+			}
+		}
+	}
+	fail := func(err error) (*os.File, error) {
+		if f != nil {
+			f.Close()
+		}
+		return nil, err
+	}
+
+	if flags&(syscall.MSG_TRUNC|syscall.MSG_CTRUNC) != 0 {
+		return fail(errors.New("message truncated"))
+	}
+	if n == 0 {
+		return fail(io.EOF)
+	}
+	if err := json.Unmarshal(buf[:n], v); err != nil {
+		return fail(fmt.Errorf("decode message: %w", err))
+	}
+	return f, nil
+}
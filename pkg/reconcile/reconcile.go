@@ -0,0 +1,214 @@
+package reconcile
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sync"
+	"syscall"
+
+	"github.com/elee1766/gobtr/pkg/btrfs"
+	"github.com/elee1766/gobtr/pkg/config"
+	"github.com/elee1766/gobtr/pkg/db"
+	"github.com/elee1766/gobtr/pkg/settings"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module("reconcile",
+	fx.Provide(New),
+	fx.Invoke(registerHooks),
+)
+
+// Reconciler applies the config file: it tracks the declared filesystems and
+// feeds the file's settings to the settings store as defaults. It runs once
+// at startup and again on SIGHUP.
+type Reconciler struct {
+	logger   *slog.Logger
+	db       *db.DB
+	settings *settings.Store
+
+	mu   sync.RWMutex
+	cfg  *config.Config
+	subs []func(*config.File)
+}
+
+func New(logger *slog.Logger, cfg *config.Config, db *db.DB, settingsStore *settings.Store) *Reconciler {
+	return &Reconciler{
+		logger:   logger.With("component", "reconcile"),
+		db:       db,
+		settings: settingsStore,
+		cfg:      cfg,
+	}
+}
+
+// File returns the config file currently applied
+func (r *Reconciler) File() *config.File {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cfg.File
+}
+
+// Subscribe calls fn with the config file after every reload
+func (r *Reconciler) Subscribe(fn func(*config.File)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subs = append(r.subs, fn)
+}
+
+// Reload reads the config file again and applies it. An invalid file is
+// rejected as a whole and the previous one stays in effect; a declared
+// filesystem that can't be opened is only logged.
+func (r *Reconciler) Reload() error {
+	r.mu.RLock()
+	prev := r.cfg
+	r.mu.RUnlock()
+
+	next, err := prev.Reload()
+	if err != nil {
+		return err
+	}
+	if next.File.Listen != "" && next.File.Listen != prev.APIAddress && os.Getenv("GOBTR_API_ADDRESS") == "" {
+		r.logger.Warn("listen address changed, restart to apply", "listen", next.File.Listen)
+	}
+	if next.File.TLS != prev.File.TLS || next.File.Socket != prev.File.Socket {
+		r.logger.Warn("tls or socket settings changed, restart to apply")
+	}
+	if next.File.Helper != prev.File.Helper {
+		r.logger.Warn("helper changed, restart to apply", "helper", next.File.Helper)
+	}
+	if next.File.ReadOnly != prev.File.ReadOnly {
+		r.logger.Warn("read_only changed, restart to apply", "read_only", next.File.ReadOnly)
+	}
+	if next.File.Headless != prev.File.Headless {
+		r.logger.Warn("headless changed, restart to apply", "headless", next.File.Headless)
+	}
+	if next.File.Pprof != prev.File.Pprof {
+		r.logger.Warn("pprof changed, restart to apply", "pprof", next.File.Pprof)
+	}
+
+	r.mu.Lock()
+	r.cfg = next
+	subs := slices.Clone(r.subs)
+	r.mu.Unlock()
+
+	r.settings.SetDefaults(settings.Defaults(next))
+	if err := r.Apply(next.File); err != nil {
+		r.logger.Warn("config file not fully applied", "error", err)
+	}
+	for _, fn := range subs {
+		fn(next.File)
+	}
+	r.logger.Info("config reloaded", "file", next.ConfigFile)
+	return nil
+}
+
+// Apply tracks the filesystems declared in file, matching them to tracked
+// ones by UUID so a changed mount point is followed rather than duplicated.
+// Filesystems that can't be opened (not mounted yet) are skipped with an
+// error and picked up by a later reload.
+func (r *Reconciler) Apply(file *config.File) error {
+	var errs []error
+	declared := make(map[string]bool)
+
+	for _, fs := range file.Filesystems {
+		path := filepath.Clean(fs.Path)
+		info, err := btrfs.GetFilesystemInfo(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("filesystem %s: %w", path, err))
+			continue
+		}
+		declared[info.UUID] = true
+
+		tracked, err := r.db.GetFilesystemByUUID(info.UUID)
+		if errors.Is(err, sql.ErrNoRows) {
+			if _, err := r.db.AddFilesystem(info.UUID, path, fs.Label, fs.BtrbkDir); err != nil {
+				errs = append(errs, fmt.Errorf("filesystem %s: %w", path, err))
+				continue
+			}
+			r.logger.Info("tracking declared filesystem", "path", path, "uuid", info.UUID)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("filesystem %s: %w", path, err))
+			continue
+		}
+
+		if tracked.Path != path {
+			if err := r.db.UpdateFilesystemPath(tracked.ID, path); err != nil {
+				errs = append(errs, fmt.Errorf("filesystem %s: %w", path, err))
+				continue
+			}
+			r.logger.Info("declared filesystem moved", "uuid", info.UUID, "from", tracked.Path, "to", path)
+		}
+		if tracked.Label != fs.Label || tracked.BtrbkSnapshotDir != fs.BtrbkDir {
+			if err := r.db.UpdateFilesystem(tracked.ID, fs.Label, fs.BtrbkDir); err != nil {
+				errs = append(errs, fmt.Errorf("filesystem %s: %w", path, err))
+				continue
+			}
+			r.logger.Info("updated declared filesystem", "path", path, "label", fs.Label, "btrbk_dir", fs.BtrbkDir)
+		}
+	}
+
+	// Only prune when every declared filesystem was found, so an unmounted
+	// disk doesn't lose its history
+	if file.PruneFilesystems && len(errs) == 0 {
+		tracked, err := r.db.ListFilesystems()
+		if err != nil {
+			return fmt.Errorf("list filesystems: %w", err)
+		}
+		for _, fs := range tracked {
+			if declared[fs.UUID] {
+				continue
+			}
+			if err := r.db.RemoveFilesystem(fs.ID); err != nil {
+				errs = append(errs, fmt.Errorf("untrack %s: %w", fs.Path, err))
+				continue
+			}
+			r.logger.Info("untracked undeclared filesystem", "path", fs.Path, "uuid", fs.UUID)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func registerHooks(lc fx.Lifecycle, r *Reconciler) {
+	// Reconcile before anything starts, so the collector and scheduler see
+	// the declared filesystems on their first run
+	if r.cfg.ConfigFile != "" {
+		r.logger.Info("applying config file", "file", r.cfg.ConfigFile, "filesystems", len(r.cfg.File.Filesystems))
+		if err := r.Apply(r.cfg.File); err != nil {
+			r.logger.Warn("config file not fully applied", "error", err)
+		}
+	}
+
+	sighup := make(chan os.Signal, 1)
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			signal.Notify(sighup, syscall.SIGHUP)
+			go func() {
+				defer close(done)
+				for range sighup {
+					r.logger.Info("SIGHUP received, reloading config")
+					if err := r.Reload(); err != nil {
+						r.logger.Error("failed to reload config", "error", err)
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			signal.Stop(sighup)
+			close(sighup)
+			<-done
+			return nil
+		},
+	})
+}
@@ -0,0 +1,192 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/elee1766/gobtr/pkg/btrfs"
+	"github.com/elee1766/gobtr/pkg/config"
+	"github.com/elee1766/gobtr/pkg/db"
+	"github.com/elee1766/gobtr/pkg/db/queries"
+	"github.com/elee1766/gobtr/pkg/handlers"
+	"github.com/elee1766/gobtr/pkg/reconcile"
+	"go.uber.org/fx"
+)
+
+// checkInterval is how often schedules are checked. Schedules are at least an
+// hour apart, so this only decides how late a run can start.
+const checkInterval = 10 * time.Minute
+
+var Module = fx.Module("scheduler",
+	fx.Provide(New),
+	fx.Invoke(registerHooks),
+)
+
+// Scheduler starts the scrubs and balances declared in the config file when
+// they are due. A run is due when the last one started longer ago than its
+// interval, so a filesystem that was never scrubbed is scrubbed right away.
+type Scheduler struct {
+	logger       *slog.Logger
+	db           *db.DB
+	btrfsManager *btrfs.Manager
+	reconciler   *reconcile.Reconciler
+	balance      *handlers.BalanceHandler
+	// Schedules are only logged in read-only mode
+	readOnly bool
+	// Signalled on config reload so new schedules are checked right away
+	wake chan struct{}
+}
+
+func New(logger *slog.Logger, cfg *config.Config, db *db.DB, btrfsManager *btrfs.Manager, reconciler *reconcile.Reconciler, balance *handlers.BalanceHandler) *Scheduler {
+	s := &Scheduler{
+		logger:       logger.With("component", "scheduler"),
+		readOnly:     cfg.ReadOnly,
+		db:           db,
+		btrfsManager: btrfsManager,
+		reconciler:   reconciler,
+		balance:      balance,
+		wake:         make(chan struct{}, 1),
+	}
+	reconciler.Subscribe(func(*config.File) {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	})
+	return s
+}
+
+// RunOnce starts every scheduled run that is due
+func (s *Scheduler) RunOnce(ctx context.Context) {
+	now := time.Now()
+	for _, fs := range s.reconciler.File().Filesystems {
+		if ctx.Err() != nil {
+			return
+		}
+		path := filepath.Clean(fs.Path)
+		if fs.ScrubEvery == 0 && fs.BalanceEvery == 0 {
+			continue
+		}
+		// Declared filesystems may not be mounted yet
+		if _, err := btrfs.GetFilesystemInfo(path); err != nil {
+			s.logger.Debug("skipping unavailable filesystem", "path", path, "error", err)
+			continue
+		}
+		// Never scrub and balance the same filesystem at once
+		if s.btrfsManager.IsScrubRunning(path) || s.btrfsManager.IsBalanceRunning(path) {
+			continue
+		}
+		if fs.ScrubEvery > 0 && s.scrubDue(path, fs.ScrubEvery, now) {
+			if s.readOnly {
+				s.logger.Info("scheduled scrub due, not started in read-only mode", "path", path)
+				continue
+			}
+			s.startScrub(ctx, path, fs)
+			continue
+		}
+		if fs.BalanceEvery > 0 && s.balanceDue(path, fs.BalanceEvery, now) {
+			if s.readOnly {
+				s.logger.Info("scheduled balance due, not started in read-only mode", "path", path)
+				continue
+			}
+			s.startBalance(ctx, path, fs)
+		}
+	}
+}
+
+func (s *Scheduler) scrubDue(path string, every time.Duration, now time.Time) bool {
+	// A status that can't be read isn't a scrub that never ran; treating
+	// it as one would start a scrub every check until it can be read
+	status, err := s.btrfsManager.GetScrubStatus(path)
+	if err != nil {
+		s.logger.Warn("failed to read scrub status", "path", path, "error", err)
+		return false
+	}
+	if status.StartedAt.IsZero() {
+		// Never scrubbed
+		return true
+	}
+	return now.Sub(status.StartedAt) >= every
+}
+
+func (s *Scheduler) balanceDue(path string, every time.Duration, now time.Time) bool {
+	// Failed balances count too, so one that keeps failing isn't retried
+	// every check
+	history, err := queries.ListBalanceHistory(s.db.Conn(), path, 1)
+	if err != nil {
+		s.logger.Warn("failed to read balance history", "path", path, "error", err)
+		return false
+	}
+	if len(history) == 0 {
+		return true
+	}
+	return now.Sub(history[0].StartedAt) >= every
+}
+
+func (s *Scheduler) startScrub(ctx context.Context, path string, fs config.FilesystemFile) {
+	opts := btrfs.ScrubOptions{
+		Readonly:         fs.ScrubReadonly,
+		LimitBytesPerSec: fs.ScrubLimitBytes(),
+	}
+	scrubID, err := s.btrfsManager.StartScrubWithOptions(ctx, path, opts)
+	if err != nil {
+		s.logger.Error("failed to start scheduled scrub", "path", path, "error", err)
+		return
+	}
+	s.logger.Info("started scheduled scrub", "path", path, "scrub_id", scrubID, "every", fs.ScrubEvery)
+}
+
+func (s *Scheduler) startBalance(ctx context.Context, path string, fs config.FilesystemFile) {
+	opts := btrfs.BalanceOptions{
+		Data:         true,
+		UsagePercent: fs.BalanceUsagePercent(),
+	}
+	balanceID, err := s.balance.StartScheduled(ctx, path, opts)
+	if err != nil {
+		s.logger.Error("failed to start scheduled balance", "path", path, "error", err)
+		return
+	}
+	s.logger.Info("started scheduled balance", "path", path, "balance_id", balanceID,
+		"usage", opts.UsagePercent, "every", fs.BalanceEvery)
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	s.RunOnce(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		s.RunOnce(ctx)
+	}
+}
+
+func registerHooks(lc fx.Lifecycle, s *Scheduler) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				s.run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,378 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/elee1766/gobtr/pkg/config"
+	"github.com/elee1766/gobtr/pkg/db"
+	"github.com/elee1766/gobtr/pkg/db/queries"
+	"github.com/elee1766/gobtr/pkg/doctor"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module("settings",
+	fx.Provide(New),
+)
+
+// ErrInvalid is returned by Update for unknown or out of range settings
+var ErrInvalid = errors.New("invalid setting")
+
+// Settings are the server settings that can be changed at runtime
+type Settings struct {
+	// Samples a usage scan takes before stopping, unless the request sets one
+	SampleTarget uint64
+	// How often the collector records usage history
+	CollectInterval time.Duration
+	// Diagnostic thresholds used when a request leaves them unset
+	Diagnostics doctor.Thresholds
+}
+
+// field describes how one setting is stored and validated. Values are
+// stored as text in the units the API uses.
+type field struct {
+	key      string
+	format   func(s *Settings) string
+	parse    func(s *Settings, value string) error
+	validate func(s *Settings) error
+}
+
+var fields = []field{
+	{
+		key:    "default_sample_target",
+		format: func(s *Settings) string { return strconv.FormatUint(s.SampleTarget, 10) },
+		parse: func(s *Settings, value string) error {
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return err
+			}
+			s.SampleTarget = n
+			return nil
+		},
+		validate: func(s *Settings) error {
+			if s.SampleTarget < 1000 || s.SampleTarget > 1_000_000_000 {
+				return fmt.Errorf("default_sample_target must be between 1000 and 1000000000")
+			}
+			return nil
+		},
+	},
+	{
+		key:    "collect_interval_seconds",
+		format: func(s *Settings) string { return strconv.FormatInt(int64(s.CollectInterval/time.Second), 10) },
+		parse: func(s *Settings, value string) error {
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return err
+			}
+			s.CollectInterval = time.Duration(n) * time.Second
+			return nil
+		},
+		validate: func(s *Settings) error {
+			if s.CollectInterval < time.Minute || s.CollectInterval > 24*time.Hour {
+				return fmt.Errorf("collect_interval_seconds must be between 60 and 86400")
+			}
+			return nil
+		},
+	},
+	{
+		key: "scrub_max_age_days",
+		format: func(s *Settings) string {
+			return strconv.FormatInt(int64(s.Diagnostics.ScrubMaxAge/(24*time.Hour)), 10)
+		},
+		parse: func(s *Settings, value string) error {
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return err
+			}
+			s.Diagnostics.ScrubMaxAge = time.Duration(n) * 24 * time.Hour
+			return nil
+		},
+		validate: func(s *Settings) error {
+			if s.Diagnostics.ScrubMaxAge < 24*time.Hour {
+				return fmt.Errorf("scrub_max_age_days must be at least 1")
+			}
+			return nil
+		},
+	},
+	{
+		key:    "slack_percent",
+		format: func(s *Settings) string { return strconv.FormatFloat(s.Diagnostics.SlackPercent, 'g', -1, 64) },
+		parse: func(s *Settings, value string) error {
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return err
+			}
+			s.Diagnostics.SlackPercent = f
+			return nil
+		},
+		validate: func(s *Settings) error {
+			if s.Diagnostics.SlackPercent <= 0 || s.Diagnostics.SlackPercent > 100 {
+				return fmt.Errorf("slack_percent must be above 0 and at most 100")
+			}
+			return nil
+		},
+	},
+	{
+		key:    "min_slack_bytes",
+		format: func(s *Settings) string { return strconv.FormatInt(s.Diagnostics.MinSlackBytes, 10) },
+		parse: func(s *Settings, value string) error {
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return err
+			}
+			s.Diagnostics.MinSlackBytes = n
+			return nil
+		},
+		validate: func(s *Settings) error {
+			if s.Diagnostics.MinSlackBytes <= 0 {
+				return fmt.Errorf("min_slack_bytes must be positive")
+			}
+			return nil
+		},
+	},
+	{
+		key:    "imbalance_percent",
+		format: func(s *Settings) string { return strconv.FormatFloat(s.Diagnostics.ImbalancePercent, 'g', -1, 64) },
+		parse: func(s *Settings, value string) error {
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return err
+			}
+			s.Diagnostics.ImbalancePercent = f
+			return nil
+		},
+		validate: func(s *Settings) error {
+			if s.Diagnostics.ImbalancePercent <= 0 || s.Diagnostics.ImbalancePercent > 100 {
+				return fmt.Errorf("imbalance_percent must be above 0 and at most 100")
+			}
+			return nil
+		},
+	},
+}
+
+// Keys returns the keys of every setting
+func Keys() []string {
+	keys := make([]string, len(fields))
+	for i, f := range fields {
+		keys[i] = f.key
+	}
+	return keys
+}
+
+func lookupField(key string) (field, bool) {
+	for _, f := range fields {
+		if f.key == key {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// values returns every setting formatted for storage
+func (s Settings) values() map[string]string {
+	m := make(map[string]string, len(fields))
+	for _, f := range fields {
+		m[f.key] = f.format(&s)
+	}
+	return m
+}
+
+// Store holds the current settings: the stored overrides on top of the
+// defaults from env and config. Subsystems subscribe to be told when they
+// change.
+type Store struct {
+	logger   *slog.Logger
+	db       *db.DB
+	defaults Settings
+
+	mu        sync.RWMutex
+	current   Settings
+	overrides map[string]string
+	subs      []func(Settings)
+}
+
+// Defaults returns the settings used before anything is stored
+func Defaults(cfg *config.Config) Settings {
+	thresholds := doctor.DefaultThresholds()
+	if cfg.Alerts.ScrubMaxAgeDays > 0 {
+		thresholds.ScrubMaxAge = time.Duration(cfg.Alerts.ScrubMaxAgeDays) * 24 * time.Hour
+	}
+	if cfg.Alerts.SlackPercent > 0 {
+		thresholds.SlackPercent = cfg.Alerts.SlackPercent
+	}
+	if n := cfg.Alerts.MinSlackBytes(); n > 0 {
+		thresholds.MinSlackBytes = n
+	}
+	if cfg.Alerts.ImbalancePercent > 0 {
+		thresholds.ImbalancePercent = cfg.Alerts.ImbalancePercent
+	}
+
+	return Settings{
+		SampleTarget:    cfg.SampleTarget,
+		CollectInterval: cfg.CollectInterval,
+		Diagnostics:     thresholds,
+	}
+}
+
+func New(logger *slog.Logger, cfg *config.Config, database *db.DB) (*Store, error) {
+	s := &Store{
+		logger:    logger.With("component", "settings"),
+		db:        database,
+		defaults:  Defaults(cfg),
+		overrides: make(map[string]string),
+	}
+
+	stored, err := queries.ListSettings(database.Conn())
+	if err != nil {
+		return nil, fmt.Errorf("load settings: %w", err)
+	}
+
+	// A bad stored value falls back to its default rather than failing startup
+	for _, f := range fields {
+		value, ok := stored[f.key]
+		if !ok {
+			continue
+		}
+		next := s.defaults
+		if err := f.parse(&next, value); err != nil {
+			s.logger.Warn("ignoring invalid stored setting", "key", f.key, "value", value, "error", err)
+			continue
+		}
+		if err := f.validate(&next); err != nil {
+			s.logger.Warn("ignoring invalid stored setting", "key", f.key, "value", value, "error", err)
+			continue
+		}
+		s.overrides[f.key] = value
+	}
+	s.current = s.withOverrides(s.defaults)
+
+	return s, nil
+}
+
+// withOverrides applies the stored overrides to defaults. Overrides were
+// parsed when stored, so they can't fail here.
+func (s *Store) withOverrides(defaults Settings) Settings {
+	current := defaults
+	for _, f := range fields {
+		if value, ok := s.overrides[f.key]; ok {
+			f.parse(&current, value)
+		}
+	}
+	return current
+}
+
+// Get returns the current settings
+func (s *Store) Get() Settings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// Defaults returns the settings from env and config, without overrides
+func (s *Store) Defaults() Settings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.defaults
+}
+
+// SetDefaults replaces the defaults, e.g. after the config file is reloaded.
+// Stored overrides still win, and subscribers are told if that changes the
+// current settings.
+func (s *Store) SetDefaults(defaults Settings) {
+	s.mu.Lock()
+	s.defaults = defaults
+	prev := s.current
+	s.current = s.withOverrides(defaults)
+	current := s.current
+	subs := slices.Clone(s.subs)
+	s.mu.Unlock()
+
+	if current == prev {
+		return
+	}
+	s.logger.Info("settings defaults changed")
+	for _, fn := range subs {
+		fn(current)
+	}
+}
+
+// Overridden returns the keys of settings that differ from their defaults
+func (s *Store) Overridden() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Sorted(maps.Keys(s.overrides))
+}
+
+// Subscribe calls fn with the new settings after every change
+func (s *Store) Subscribe(fn func(Settings)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subs = append(s.subs, fn)
+}
+
+// Update validates and stores next, first resetting the keys in reset to
+// their defaults. Settings equal to their defaults are not stored, so they
+// follow later changes to env and config.
+func (s *Store) Update(next Settings, reset []string) (Settings, error) {
+	defaults := s.Defaults().values()
+	for _, key := range reset {
+		f, ok := lookupField(key)
+		if !ok {
+			return Settings{}, fmt.Errorf("%w: unknown setting %q", ErrInvalid, key)
+		}
+		if err := f.parse(&next, defaults[key]); err != nil {
+			return Settings{}, err
+		}
+	}
+
+	// Defaults are not checked, so env and config can go outside the ranges
+	// the API allows
+	values := next.values()
+	set := make(map[string]string)
+	var del []string
+	s.mu.Lock()
+	for _, f := range fields {
+		value := values[f.key]
+		if value == defaults[f.key] {
+			if _, ok := s.overrides[f.key]; ok {
+				del = append(del, f.key)
+			}
+			continue
+		}
+		if err := f.validate(&next); err != nil {
+			s.mu.Unlock()
+			return Settings{}, fmt.Errorf("%w: %w", ErrInvalid, err)
+		}
+		if s.overrides[f.key] != value {
+			set[f.key] = value
+		}
+	}
+	if len(set) == 0 && len(del) == 0 {
+		s.mu.Unlock()
+		return next, nil
+	}
+
+	if err := queries.SetSettings(s.db.Conn(), set, del, time.Now()); err != nil {
+		s.mu.Unlock()
+		return Settings{}, fmt.Errorf("store settings: %w", err)
+	}
+	maps.Copy(s.overrides, set)
+	for _, key := range del {
+		delete(s.overrides, key)
+	}
+	s.current = next
+	subs := slices.Clone(s.subs)
+	s.mu.Unlock()
+
+	s.logger.Info("settings updated", "changed", slices.Sorted(maps.Keys(set)), "reset", del)
+	for _, fn := range subs {
+		fn(next)
+	}
+	return next, nil
+}
@@ -0,0 +1,301 @@
+// Package support builds support bundles: a tar.gz of what a bug report
+// usually ends up asking for, so it can be attached in one go instead of
+// over a dozen comments.
+package support
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/elee1766/gobtr/pkg/btrfs"
+	"github.com/elee1766/gobtr/pkg/config"
+	"github.com/elee1766/gobtr/pkg/db"
+	"github.com/elee1766/gobtr/pkg/db/queries"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module("support",
+	fx.Provide(New),
+)
+
+// DefaultHistory is how many balance records and errors a bundle holds
+// unless asked for another number
+const DefaultHistory = 20
+
+// maxSysfsFile caps each sysfs file copied, in case one is not what it seems
+const maxSysfsFile = 64 << 10
+
+type Bundler struct {
+	logger *slog.Logger
+	cfg    *config.Config
+	db     *db.DB
+	btrfs  *btrfs.Manager
+}
+
+func New(logger *slog.Logger, cfg *config.Config, db *db.DB, btrfsManager *btrfs.Manager) *Bundler {
+	return &Bundler{
+		logger: logger.With("component", "support"),
+		cfg:    cfg,
+		db:     db,
+		btrfs:  btrfsManager,
+	}
+}
+
+type Options struct {
+	// Config file to include, redacted (default the one read at startup)
+	File *config.File
+	// Balance records and errors to include (default DefaultHistory)
+	History int
+	// Include a dump of this process's goroutines, which only helps when
+	// this process is the server
+	Goroutines bool
+}
+
+// Filename is what to call a bundle made now
+func Filename(now time.Time) string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("gobtr-support-%s-%s.tar.gz", host, now.UTC().Format("20060102-150405"))
+}
+
+// Write writes a bundle to w. Parts that can't be collected are listed in
+// problems.txt rather than failing the bundle; only writing to w can fail.
+func (b *Bundler) Write(w io.Writer, opts Options) error {
+	if opts.File == nil {
+		opts.File = b.cfg.File
+	}
+	if opts.History <= 0 {
+		opts.History = DefaultHistory
+	}
+
+	now := time.Now()
+	bw := &bundleWriter{
+		dir: strings.TrimSuffix(Filename(now), ".tar.gz"),
+		now: now,
+	}
+	gz := gzip.NewWriter(w)
+	bw.tw = tar.NewWriter(gz)
+
+	bw.add("info.txt", b.info(now))
+	bw.add("config.toml", b.config(opts.File))
+
+	filesystems, err := b.db.ListFilesystems()
+	if err != nil {
+		bw.problem("list filesystems: %v", err)
+	}
+	bw.addJSON("filesystems.json", filesystems)
+
+	errs, err := queries.ListErrors(b.db.Conn(), "", time.Time{}, opts.History)
+	if err != nil {
+		bw.problem("list errors: %v", err)
+	}
+	bw.addJSON("errors.json", errs)
+
+	for _, fs := range filesystems {
+		b.addFilesystem(bw, fs, opts.History)
+	}
+
+	if opts.Goroutines {
+		var buf bytes.Buffer
+		if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+			bw.problem("goroutine dump: %v", err)
+		}
+		bw.add("goroutines.txt", buf.Bytes())
+	}
+
+	if len(bw.problems) > 0 {
+		bw.add("problems.txt", []byte(strings.Join(bw.problems, "\n")+"\n"))
+	}
+
+	if bw.err != nil {
+		return bw.err
+	}
+	if err := bw.tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// info describes the build, the host and how the server is set up
+func (b *Bundler) info(now time.Time) []byte {
+	var buf bytes.Buffer
+	line := func(k string, v any) { fmt.Fprintf(&buf, "%-14s %v\n", k+":", v) }
+
+	line("created", now.UTC().Format(time.RFC3339))
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		line("version", bi.Main.Version)
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision", "vcs.time", "vcs.modified", "-tags":
+				line(s.Key, s.Value)
+			}
+		}
+	}
+	line("go", runtime.Version())
+	line("platform", runtime.GOOS+"/"+runtime.GOARCH)
+	if release, err := os.ReadFile("/proc/sys/kernel/osrelease"); err == nil {
+		line("kernel", strings.TrimSpace(string(release)))
+	}
+	if out, err := exec.Command("btrfs", "--version").Output(); err == nil {
+		line("btrfs-progs", strings.TrimSpace(string(out)))
+	} else {
+		line("btrfs-progs", err)
+	}
+	line("uid", os.Getuid())
+
+	configFile := b.cfg.ConfigFile
+	if configFile == "" {
+		configFile = "none"
+	}
+	line("config file", configFile)
+	line("data dir", b.cfg.DataDir)
+	line("helper", b.cfg.Helper)
+	line("read only", b.cfg.ReadOnly)
+	line("headless", b.cfg.Headless)
+	line("pprof", b.cfg.Pprof)
+	return buf.Bytes()
+}
+
+// config is the file as TOML with its secrets redacted
+func (b *Bundler) config(f *config.File) []byte {
+	var buf bytes.Buffer
+	if b.cfg.ConfigFile == "" {
+		buf.WriteString("# no config file, these are the defaults\n")
+	}
+	if err := toml.NewEncoder(&buf).Encode(f.Redacted()); err != nil {
+		fmt.Fprintf(&buf, "# encode: %v\n", err)
+	}
+	return buf.Bytes()
+}
+
+func (b *Bundler) addFilesystem(bw *bundleWriter, fs *db.TrackedFilesystem, history int) {
+	dir := path.Join("fs", fs.UUID)
+
+	info, err := btrfs.GetFilesystemInfo(fs.Path)
+	if err != nil {
+		bw.problem("%s: filesystem info: %v", fs.Path, err)
+	}
+	bw.addJSON(path.Join(dir, "info.json"), info)
+
+	b.addSysfs(bw, dir, fs.UUID)
+
+	stats, err := b.btrfs.GetDeviceStats(fs.Path)
+	if err != nil {
+		bw.problem("%s: device stats: %v", fs.Path, err)
+	}
+	bw.addJSON(path.Join(dir, "device_stats.json"), stats)
+
+	// btrfs keeps only the last scrub, so that's all there is
+	scrub, err := b.btrfs.GetScrubStatusByUUID(fs.UUID)
+	if err != nil {
+		bw.problem("%s: scrub status: %v", fs.Path, err)
+	}
+	bw.addJSON(path.Join(dir, "scrub.json"), scrub)
+	if raw, err := os.ReadFile(filepath.Join(btrfs.ScrubStatusDir, "scrub.status."+fs.UUID)); err == nil {
+		bw.add(path.Join(dir, "scrub.status"), raw)
+	} else if !os.IsNotExist(err) {
+		bw.problem("%s: scrub status file: %v", fs.Path, err)
+	}
+
+	balances, err := queries.ListBalanceHistory(b.db.Conn(), fs.Path, history)
+	if err != nil {
+		bw.problem("%s: balance history: %v", fs.Path, err)
+	}
+	bw.addJSON(path.Join(dir, "balance.json"), balances)
+}
+
+// addSysfs copies the filesystem's allocation and device info from sysfs
+func (b *Bundler) addSysfs(bw *bundleWriter, dir, uuid string) {
+	for _, sub := range []string{"allocation", "devinfo"} {
+		root := filepath.Join("/sys/fs/btrfs", uuid, sub)
+		err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// Device links point back into the device tree
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(filepath.Dir(root), p)
+			if err != nil {
+				return err
+			}
+			data, err := readLimited(p, maxSysfsFile)
+			if err != nil {
+				// Some attributes are write only
+				return nil
+			}
+			bw.add(path.Join(dir, "sysfs", filepath.ToSlash(rel)), data)
+			return nil
+		})
+		if err != nil {
+			bw.problem("sysfs: %v", err)
+		}
+	}
+}
+
+func readLimited(name string, limit int64) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, limit))
+}
+
+// bundleWriter adds files under one directory of a tar, keeping the first
+// write error
+type bundleWriter struct {
+	tw       *tar.Writer
+	dir      string
+	now      time.Time
+	problems []string
+	err      error
+}
+
+func (w *bundleWriter) add(name string, data []byte) {
+	if w.err != nil {
+		return
+	}
+	hdr := &tar.Header{
+		Name:    path.Join(w.dir, name),
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: w.now,
+	}
+	if w.err = w.tw.WriteHeader(hdr); w.err != nil {
+		return
+	}
+	_, w.err = w.tw.Write(data)
+}
+
+func (w *bundleWriter) addJSON(name string, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		w.problem("%s: %v", name, err)
+		return
+	}
+	w.add(name, append(data, '\n'))
+}
+
+func (w *bundleWriter) problem(format string, args ...any) {
+	w.problems = append(w.problems, fmt.Sprintf(format, args...))
+}
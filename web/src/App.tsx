@@ -1,8 +1,11 @@
-import { type ParentComponent, createMemo, createEffect, onCleanup, Show, Suspense } from "solid-js";
+import { type ParentComponent, createMemo, createEffect, createResource, onCleanup, onMount, Show, Suspense } from "solid-js";
 import { A, useLocation } from "@solidjs/router";
 import { Toaster } from "solid-toast";
 import { getCachedFs } from "@/stores/filesystems";
 import { uiSettings } from "@/stores/ui";
+import { authEnabled, session, sessionChecked, refreshSession, logout, readOnly } from "@/stores/auth";
+import Login from "@/pages/Login";
+import { hubClient } from "@/api/client";
 
 const App: ParentComponent = (props) => {
   const location = useLocation();
@@ -12,6 +15,17 @@ const App: ParentComponent = (props) => {
     window.dispatchEvent(new CustomEvent("refresh"));
   };
 
+  onMount(() => {
+    refreshSession();
+  });
+  const signedOut = createMemo(() => authEnabled() && !session());
+
+  // The hosts page only matters on a hub
+  const [hasAgents] = createResource(
+    () => sessionChecked() && !signedOut(),
+    () => hubClient.listAgents({}).then((r) => r.agents.length > 0).catch(() => false)
+  );
+
   // Auto-refresh timer (controlled via settings only)
   createEffect(() => {
     const settings = uiSettings();
@@ -33,6 +47,35 @@ const App: ParentComponent = (props) => {
           <div class="flex items-center h-8 text-xs">
             <Breadcrumb />
             <div class="flex-1" />
+            <Show when={readOnly()}>
+              <span class="px-2 py-1 text-warning" title="the server refuses every change">read-only</span>
+            </Show>
+            <Show when={authEnabled() && session()}>
+              {(s) => (
+                <>
+                  <span class="px-2 py-1 text-text-muted">
+                    {s().username} ({s().role})
+                  </span>
+                  <Show when={s().method === "session"}>
+                    <button
+                      class="px-2 py-1 text-text-tertiary hover:bg-bg-surface-raised cursor-pointer"
+                      onClick={() => logout()}
+                    >
+                      sign out
+                    </button>
+                  </Show>
+                </>
+              )}
+            </Show>
+            <Show when={hasAgents() && location.pathname !== "/hosts"}>
+              <A
+                href="/hosts"
+                class="px-2 py-1 text-text-tertiary hover:bg-bg-surface-raised"
+                draggable={false}
+              >
+                hosts
+              </A>
+            </Show>
             {/* Refresh button (hidden on settings page) */}
             <Show when={!isSettings()}>
               <button
@@ -66,9 +109,13 @@ const App: ParentComponent = (props) => {
       </header>
       <main class="container mx-auto px-3 py-4">
         <div class="max-w-7xl mx-auto">
-          <Suspense fallback={<div class="text-xs text-text-tertiary p-2">loading...</div>}>
-            {props.children}
-          </Suspense>
+          <Show when={sessionChecked()} fallback={<div class="text-xs text-text-tertiary p-2">loading...</div>}>
+            <Show when={!signedOut()} fallback={<Login />}>
+              <Suspense fallback={<div class="text-xs text-text-tertiary p-2">loading...</div>}>
+                {props.children}
+              </Suspense>
+            </Show>
+          </Show>
         </div>
       </main>
       <Toaster position="bottom-right" gutter={8} />
@@ -89,6 +136,7 @@ function Breadcrumb() {
 
   const isHome = createMemo(() => path() === "/" || path() === "");
   const isSettings = createMemo(() => path() === "/settings");
+  const isHosts = createMemo(() => path() === "/hosts");
 
   return (
     <div class="flex items-baseline">
@@ -100,6 +148,10 @@ function Breadcrumb() {
       </Show>
       <Show when={!isHome() && !isSettings()}>
         <A href="/" class="text-text-muted hover:text-text-secondary" draggable={false}>filesystems</A>
+        <Show when={isHosts()}>
+          <span class="text-text-faint mx-1">/</span>
+          <span class="text-text-secondary">hosts</span>
+        </Show>
         <Show when={fsInfo()}>
           {(info) => (
             <>
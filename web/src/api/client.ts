@@ -1,4 +1,4 @@
-import { createClient } from "@connectrpc/connect";
+import { Code, ConnectError, createClient, type Interceptor } from "@connectrpc/connect";
 import { createConnectTransport } from "@connectrpc/connect-web";
 import { FilesystemService } from "%/v1/filesystem_pb";
 import { SubvolumeService } from "%/v1/subvolume_pb";
@@ -7,9 +7,30 @@ import { BalanceService } from "%/v1/balance_pb";
 import { HealthService } from "%/v1/health_pb";
 import { UsageService } from "%/v1/usage_pb";
 import { FragMapService } from "%/v1/fragmap_pb";
+import { ForecastService } from "%/v1/forecast_pb";
+import { DiagnosticsService } from "%/v1/diagnostics_pb";
+import { DefragService } from "%/v1/defrag_pb";
+import { SettingsService } from "%/v1/settings_pb";
+import { AuthService } from "%/v1/auth_pb";
+import { AuditService } from "%/v1/audit_pb";
+import { HubService } from "%/v1/hub_pb";
+import { SupportService } from "%/v1/support_pb";
+
+// An expired or revoked session shows the login form again
+const authInterceptor: Interceptor = (next) => async (req) => {
+  try {
+    return await next(req);
+  } catch (e) {
+    if (e instanceof ConnectError && e.code === Code.Unauthenticated && req.service.typeName !== AuthService.typeName) {
+      window.dispatchEvent(new CustomEvent("unauthenticated"));
+    }
+    throw e;
+  }
+};
 
 const transport = createConnectTransport({
   baseUrl: window.location.origin,
+  interceptors: [authInterceptor],
 });
 
 export const filesystemClient = createClient(FilesystemService, transport);
@@ -19,3 +40,11 @@ export const balanceClient = createClient(BalanceService, transport);
 export const healthClient = createClient(HealthService, transport);
 export const usageClient = createClient(UsageService, transport);
 export const fragmapClient = createClient(FragMapService, transport);
+export const forecastClient = createClient(ForecastService, transport);
+export const diagnosticsClient = createClient(DiagnosticsService, transport);
+export const defragClient = createClient(DefragService, transport);
+export const settingsClient = createClient(SettingsService, transport);
+export const authClient = createClient(AuthService, transport);
+export const auditClient = createClient(AuditService, transport);
+export const hubClient = createClient(HubService, transport);
+export const supportClient = createClient(SupportService, transport);
@@ -0,0 +1,76 @@
+import { createResource, Show, For } from "solid-js";
+import { fragmapClient } from "@/api/client";
+import { formatBytes, formatNumber } from "@/lib/utils";
+import { Alert } from "@/components/ui";
+
+interface Props {
+  fsPath: string;
+  deviceId: bigint;
+  deviceOffset: bigint;
+  showRawBytes: boolean;
+}
+
+// Lists the files stored in the chunk at a device offset, i.e. what keeps a
+// nearly empty chunk pinned and what a balance of it would move.
+export function ChunkContents(props: Props) {
+  const [contents] = createResource(
+    () => ({ fsPath: props.fsPath, deviceId: props.deviceId, deviceOffset: props.deviceOffset }),
+    (req) => fragmapClient.resolveLogicalRange({ ...req, maxExtents: 2000 }),
+  );
+
+  return (
+    <div class="mt-2 text-xs">
+      <Show when={contents.error}>
+        <Alert type="error">{String(contents.error)}</Alert>
+      </Show>
+      <Show when={contents.loading}>
+        <div class="text-text-muted">looking up files in this chunk...</div>
+      </Show>
+      <Show when={!contents.loading && contents()}>
+        {(r) => (
+          <div class="space-y-1">
+            <div class="text-text-secondary">
+              chunk {formatBytes(r().logicalStart, props.showRawBytes)}: {formatNumber(r().dataExtents)} data extents
+              ({formatBytes(r().dataBytes, props.showRawBytes)})
+              <Show when={r().metadataExtents > 0}>
+                , {formatNumber(r().metadataExtents)} tree blocks ({formatBytes(r().metadataBytes, props.showRawBytes)})
+              </Show>
+              , {formatNumber(r().files.length)} files
+              <Show when={r().truncated}>
+                <span class="text-warning"> (only the first {formatNumber(r().extents.length)} extents resolved)</span>
+              </Show>
+            </div>
+            <Show when={r().files.length > 0}>
+              <div class="max-h-64 overflow-y-auto">
+                <table class="w-full">
+                  <thead>
+                    <tr class="text-text-tertiary text-left">
+                      <th class="px-2 py-1 text-right">bytes</th>
+                      <th class="px-2 py-1 text-right">extents</th>
+                      <th class="px-2 py-1 text-right">shared</th>
+                      <th class="px-2 py-1">path</th>
+                    </tr>
+                  </thead>
+                  <tbody>
+                    <For each={r().files}>
+                      {(f) => (
+                        <tr class="border-t border-border-subtle">
+                          <td class="px-2 py-1 text-right">{formatBytes(f.bytes, props.showRawBytes)}</td>
+                          <td class="px-2 py-1 text-right">{formatNumber(f.extents)}</td>
+                          <td class="px-2 py-1 text-right">{f.sharedExtents > 0 ? formatNumber(f.sharedExtents) : ""}</td>
+                          <td class="px-2 py-1 font-mono truncate">
+                            {f.path || `<root ${f.root} inode ${f.inode}>`}
+                          </td>
+                        </tr>
+                      )}
+                    </For>
+                  </tbody>
+                </table>
+              </div>
+            </Show>
+          </div>
+        )}
+      </Show>
+    </div>
+  );
+}
@@ -0,0 +1,134 @@
+import { createSignal, Show, For, onCleanup } from "solid-js";
+import { fragmapClient } from "@/api/client";
+import { formatBytes, formatNumber } from "@/lib/utils";
+import { Card, Button, Alert, LabeledInput } from "@/components/ui";
+import type { ScanFilesProgress, ScanFilesResult } from "%/v1/fragmap_pb";
+
+interface Props {
+  fsPath: string;
+}
+
+// Scans file fragmentation under a directory, streaming progress as it goes.
+// Scans are resumable, so stopping and starting again picks up where it left off.
+export function FileFragScan(props: Props) {
+  const [path, setPath] = createSignal(props.fsPath);
+  const [exclude, setExclude] = createSignal("");
+  const [progress, setProgress] = createSignal<ScanFilesProgress | null>(null);
+  const [result, setResult] = createSignal<ScanFilesResult | null>(null);
+  const [error, setError] = createSignal<string | null>(null);
+  const [abort, setAbort] = createSignal<AbortController | null>(null);
+
+  onCleanup(() => abort()?.abort());
+
+  const start = async () => {
+    const controller = new AbortController();
+    setAbort(controller);
+    setError(null);
+    setResult(null);
+    setProgress(null);
+    try {
+      const stream = fragmapClient.scanFiles(
+        {
+          path: path(),
+          exclude: exclude().split(",").map((s) => s.trim()).filter((s) => s),
+          oneFileSystem: true,
+          resumable: true,
+        },
+        { signal: controller.signal },
+      );
+      for await (const update of stream) {
+        if (update.progress) setProgress(update.progress);
+        if (update.result) setResult(update.result);
+      }
+    } catch (e) {
+      if (!controller.signal.aborted) setError(String(e));
+    } finally {
+      setAbort(null);
+    }
+  };
+
+  const stop = () => abort()?.abort();
+
+  return (
+    <Card
+      header="file fragmentation"
+      headerRight={
+        <Show
+          when={abort()}
+          fallback={<Button variant="soft" onClick={start}>scan</Button>}
+        >
+          <Button variant="ghost" onClick={stop}>stop</Button>
+        </Show>
+      }
+    >
+      <div class="p-2 space-y-2">
+        <div class="grid grid-cols-2 gap-2">
+          <LabeledInput label="directory" value={path()} onChange={setPath} mono disabled={!!abort()} />
+          <LabeledInput
+            label="exclude (comma separated globs)"
+            value={exclude()}
+            onChange={setExclude}
+            placeholder="*.log, node_modules"
+            mono
+            disabled={!!abort()}
+          />
+        </div>
+
+        <Show when={error()}>
+          <Alert type="error" onDismiss={() => setError(null)}>{error()}</Alert>
+        </Show>
+
+        <Show when={abort() && progress()}>
+          {(p) => (
+            <div class="text-xs text-text-tertiary">
+              {p().resumed ? "resumed, " : ""}
+              {formatNumber(p().filesScanned)} files, {formatBytes(p().bytesScanned)}
+              <Show when={p().errors > 0}>, {formatNumber(p().errors)} errors</Show>
+              <div class="font-mono truncate">{p().currentPath}</div>
+            </div>
+          )}
+        </Show>
+
+        <Show when={result()}>
+          {(r) => (
+            <div class="space-y-2">
+              <div class="text-xs text-text-default">
+                {formatNumber(r().stats?.totalFiles ?? 0)} files,{" "}
+                {formatBytes(r().stats?.totalBytes ?? 0n)},{" "}
+                {formatNumber(r().stats?.fragmentedFiles ?? 0)} fragmented,
+                avg DoF {(r().stats?.avgDof ?? 0).toFixed(2)}
+                <Show when={r().errors > 0}>
+                  <span class="text-warning">, {formatNumber(r().errors)} unreadable</span>
+                </Show>
+              </div>
+              <Show when={r().top.length > 0}>
+                <table class="w-full text-xs">
+                  <thead>
+                    <tr class="text-text-tertiary text-left">
+                      <th class="px-2 py-1 text-right">DoF</th>
+                      <th class="px-2 py-1 text-right">extents</th>
+                      <th class="px-2 py-1 text-right">size</th>
+                      <th class="px-2 py-1">path</th>
+                    </tr>
+                  </thead>
+                  <tbody>
+                    <For each={r().top}>
+                      {(f) => (
+                        <tr class="border-t border-border-subtle">
+                          <td class="px-2 py-1 text-right">{f.dof.toFixed(1)}</td>
+                          <td class="px-2 py-1 text-right">{formatNumber(f.extentCount)}</td>
+                          <td class="px-2 py-1 text-right">{formatBytes(f.size)}</td>
+                          <td class="px-2 py-1 font-mono truncate">{f.path}</td>
+                        </tr>
+                      )}
+                    </For>
+                  </tbody>
+                </table>
+              </Show>
+            </div>
+          )}
+        </Show>
+      </div>
+    </Card>
+  );
+}
@@ -2,6 +2,7 @@ import { createSignal, createResource, Show, For, createMemo, onMount, onCleanup
 import { fragmapClient } from "@/api/client";
 import { formatBytes, formatNumber } from "@/lib/utils";
 import { uiSettings } from "@/stores/ui";
+import { ChunkContents } from "@/components/ChunkContents";
 import type { BlockMapEntry, FragStats, Device } from "%/v1/fragmap_pb";
 import { Poline, positionFunctions } from "poline";
 
@@ -242,6 +243,7 @@ export function FragMapViz(props: Props) {
                   <BaseGridView
                     maps={maps()}
                     showRawBytes={uiSettings().showRawBytes}
+                    fsPath={props.fsPath}
                     colorMode="type"
                     selectByType={legendFilter()}
                   />
@@ -274,6 +276,7 @@ export function FragMapViz(props: Props) {
                   <BaseGridView
                     maps={maps()}
                     showRawBytes={uiSettings().showRawBytes}
+                    fsPath={props.fsPath}
                     colorMode="utilization"
                   />
                 </div>
@@ -318,6 +321,8 @@ function BaseGridView(props: {
   showRawBytes: boolean;
   colorMode: ColorMode;
   selectByType?: { type: BlockType; index: number } | null;
+  // Set to list the files in a clicked chunk
+  fsPath?: string;
 }) {
   const [hoveredExtent, setHoveredExtent] = createSignal<ExtentCell | null>(null);
   const [lockedExtent, setLockedExtent] = createSignal<ExtentCell | null>(null);
@@ -850,6 +855,17 @@ function BaseGridView(props: {
           )}
         </Show>
       </div>
+
+      <Show when={props.fsPath && lockedExtent()?.allocated && lockedExtent()}>
+        {(ext) => (
+          <ChunkContents
+            fsPath={props.fsPath!}
+            deviceId={ext().device.id}
+            deviceOffset={ext().offset}
+            showRawBytes={props.showRawBytes}
+          />
+        )}
+      </Show>
     </div>
   );
 }
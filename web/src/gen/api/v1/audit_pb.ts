@@ -0,0 +1,197 @@
+// @generated by protoc-gen-es v2.10.1 with parameter "target=ts"
+// @generated from file api/v1/audit.proto (package api.v1, syntax proto3)
+/* eslint-disable */
+
+import type { GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv2";
+import { fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf/codegenv2";
+import type { Message } from "@bufbuild/protobuf";
+
+/**
+ * Describes the file api/v1/audit.proto.
+ */
+export const file_api_v1_audit: GenFile = /*@__PURE__*/
+  fileDesc("ChJhcGkvdjEvYXVkaXQucHJvdG8SBmFwaS52MSLdAQoKQXVkaXRFdmVudBIKCgJpZBgBIAEoAxIRCgl0aW1lc3RhbXAYAiABKAMSDQoFYWN0b3IYAyABKAkSEwoLYXV0aF9tZXRob2QYBCABKAkSDAoEcm9sZRgFIAEoCRIMCgRwZWVyGAYgASgJEhEKCXByb2NlZHVyZRgHIAEoCRISCgpmaWxlc3lzdGVtGAggASgJEhQKDHJlcXVlc3RfanNvbhgJIAEoCRIPCgdvdXRjb21lGAogASgJEg0KBWVycm9yGAsgASgJEhMKC2R1cmF0aW9uX21zGAwgASgDIo4BChZMaXN0QXVkaXRFdmVudHNSZXF1ZXN0EhIKCmZpbGVzeXN0ZW0YASABKAkSDQoFYWN0b3IYAiABKAkSEQoJcHJvY2VkdXJlGAMgASgJEg0KBXNpbmNlGAQgASgDEg0KBXVudGlsGAUgASgDEhEKCWJlZm9yZV9pZBgGIAEoAxINCgVsaW1pdBgHIAEoBSJVChdMaXN0QXVkaXRFdmVudHNSZXNwb25zZRIiCgZldmVudHMYASADKAsyEi5hcGkudjEuQXVkaXRFdmVudBIWCg5uZXh0X2JlZm9yZV9pZBgCIAEoAzJkCgxBdWRpdFNlcnZpY2USVAoPTGlzdEF1ZGl0RXZlbnRzEh4uYXBpLnYxLkxpc3RBdWRpdEV2ZW50c1JlcXVlc3QaHy5hcGkudjEuTGlzdEF1ZGl0RXZlbnRzUmVzcG9uc2UiAEKBAQoKY29tLmFwaS52MUIKQXVkaXRQcm90b1ABWi5naXRodWIuY29tL2VsZWUxNzY2L2J0cmZzZ3VpZC9nZW4vYXBpL3YxO2FwaXYxogIDQVhYqgIGQXBpLlYxygIGQXBpXFYx4gISQXBpXFYxXEdQQk1ldGFkYXRh6gIHQXBpOjpWMWIGcHJvdG8z");
+
+/**
+ * @generated from message api.v1.AuditEvent
+ */
+export type AuditEvent = Message<"api.v1.AuditEvent"> & {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id: bigint;
+
+  /**
+   * @generated from field: int64 timestamp = 2;
+   */
+  timestamp: bigint;
+
+  /**
+   * Username, "token:<name>", "cert:<cn>", "unix:<user>" or "anonymous"
+   *
+   * @generated from field: string actor = 3;
+   */
+  actor: string;
+
+  /**
+   * @generated from field: string auth_method = 4;
+   */
+  authMethod: string;
+
+  /**
+   * @generated from field: string role = 5;
+   */
+  role: string;
+
+  /**
+   * Remote address
+   *
+   * @generated from field: string peer = 6;
+   */
+  peer: string;
+
+  /**
+   * e.g. /api.v1.BalanceService/StartBalance
+   *
+   * @generated from field: string procedure = 7;
+   */
+  procedure: string;
+
+  /**
+   * Path the call acted on, if any
+   *
+   * @generated from field: string filesystem = 8;
+   */
+  filesystem: string;
+
+  /**
+   * Request parameters, secrets redacted
+   *
+   * @generated from field: string request_json = 9;
+   */
+  requestJson: string;
+
+  /**
+   * "ok" or the error code, e.g. permission_denied
+   *
+   * @generated from field: string outcome = 10;
+   */
+  outcome: string;
+
+  /**
+   * @generated from field: string error = 11;
+   */
+  error: string;
+
+  /**
+   * @generated from field: int64 duration_ms = 12;
+   */
+  durationMs: bigint;
+};
+
+/**
+ * Describes the message api.v1.AuditEvent.
+ * Use `create(AuditEventSchema)` to create a new message.
+ */
+export const AuditEventSchema: GenMessage<AuditEvent> = /*@__PURE__*/
+  messageDesc(file_api_v1_audit, 0);
+
+/**
+ * @generated from message api.v1.ListAuditEventsRequest
+ */
+export type ListAuditEventsRequest = Message<"api.v1.ListAuditEventsRequest"> & {
+  /**
+   * Mount path; paths under it match too
+   *
+   * @generated from field: string filesystem = 1;
+   */
+  filesystem: string;
+
+  /**
+   * @generated from field: string actor = 2;
+   */
+  actor: string;
+
+  /**
+   * @generated from field: string procedure = 3;
+   */
+  procedure: string;
+
+  /**
+   * Unix seconds, inclusive (0 = no bound)
+   *
+   * @generated from field: int64 since = 4;
+   */
+  since: bigint;
+
+  /**
+   * Unix seconds, exclusive (0 = no bound)
+   *
+   * @generated from field: int64 until = 5;
+   */
+  until: bigint;
+
+  /**
+   * Page back from next_before_id of a previous call
+   *
+   * @generated from field: int64 before_id = 6;
+   */
+  beforeId: bigint;
+
+  /**
+   * Default 100, at most 1000
+   *
+   * @generated from field: int32 limit = 7;
+   */
+  limit: number;
+};
+
+/**
+ * Describes the message api.v1.ListAuditEventsRequest.
+ * Use `create(ListAuditEventsRequestSchema)` to create a new message.
+ */
+export const ListAuditEventsRequestSchema: GenMessage<ListAuditEventsRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_audit, 1);
+
+/**
+ * @generated from message api.v1.ListAuditEventsResponse
+ */
+export type ListAuditEventsResponse = Message<"api.v1.ListAuditEventsResponse"> & {
+  /**
+   * @generated from field: repeated api.v1.AuditEvent events = 1;
+   */
+  events: AuditEvent[];
+
+  /**
+   * 0 when there are no more
+   *
+   * @generated from field: int64 next_before_id = 2;
+   */
+  nextBeforeId: bigint;
+};
+
+/**
+ * Describes the message api.v1.ListAuditEventsResponse.
+ * Use `create(ListAuditEventsResponseSchema)` to create a new message.
+ */
+export const ListAuditEventsResponseSchema: GenMessage<ListAuditEventsResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_audit, 2);
+
+/**
+ * @generated from service api.v1.AuditService
+ */
+export const AuditService: GenService<{
+  /**
+   * ListAuditEvents returns recorded mutating calls, newest first. The same
+   * filters work on /audit/export, which streams JSON lines.
+   *
+   * @generated from rpc api.v1.AuditService.ListAuditEvents
+   */
+  listAuditEvents: {
+    methodKind: "unary";
+    input: typeof ListAuditEventsRequestSchema;
+    output: typeof ListAuditEventsResponseSchema;
+  },
+}> = /*@__PURE__*/
+  serviceDesc(file_api_v1_audit, 0);
+
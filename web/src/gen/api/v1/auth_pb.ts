@@ -0,0 +1,404 @@
+// @generated by protoc-gen-es v2.10.1 with parameter "target=ts"
+// @generated from file api/v1/auth.proto (package api.v1, syntax proto3)
+/* eslint-disable */
+
+import type { GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv2";
+import { fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf/codegenv2";
+import type { Message } from "@bufbuild/protobuf";
+
+/**
+ * Describes the file api/v1/auth.proto.
+ */
+export const file_api_v1_auth: GenFile = /*@__PURE__*/
+  fileDesc("ChFhcGkvdjEvYXV0aC5wcm90bxIGYXBpLnYxIjkKB1Nlc3Npb24SEAoIdXNlcm5hbWUYASABKAkSDAoEcm9sZRgCIAEoCRIOCgZtZXRob2QYAyABKAkiEwoRR2V0U2Vzc2lvblJlcXVlc3QiTAoSR2V0U2Vzc2lvblJlc3BvbnNlEhQKDGF1dGhfZW5hYmxlZBgBIAEoCBIgCgdzZXNzaW9uGAIgASgLMg8uYXBpLnYxLlNlc3Npb24iMgoMTG9naW5SZXF1ZXN0EhAKCHVzZXJuYW1lGAEgASgJEhAKCHBhc3N3b3JkGAIgASgJIkUKDUxvZ2luUmVzcG9uc2USIAoHc2Vzc2lvbhgBIAEoCzIPLmFwaS52MS5TZXNzaW9uEhIKCmV4cGlyZXNfYXQYAiABKAMiDwoNTG9nb3V0UmVxdWVzdCIQCg5Mb2dvdXRSZXNwb25zZSJOCgRVc2VyEhAKCHVzZXJuYW1lGAEgASgJEgwKBHJvbGUYAiABKAkSEgoKY3JlYXRlZF9hdBgDIAEoAxISCgp1cGRhdGVkX2F0GAQgASgDIhIKEExpc3RVc2Vyc1JlcXVlc3QiMAoRTGlzdFVzZXJzUmVzcG9uc2USGwoFdXNlcnMYASADKAsyDC5hcGkudjEuVXNlciJFChFDcmVhdGVVc2VyUmVxdWVzdBIQCgh1c2VybmFtZRgBIAEoCRIQCghwYXNzd29yZBgCIAEoCRIMCgRyb2xlGAMgASgJIjAKEkNyZWF0ZVVzZXJSZXNwb25zZRIaCgR1c2VyGAEgASgLMgwuYXBpLnYxLlVzZXIiRQoRVXBkYXRlVXNlclJlcXVlc3QSEAoIdXNlcm5hbWUYASABKAkSDAoEcm9sZRgCIAEoCRIQCghwYXNzd29yZBgDIAEoCSIUChJVcGRhdGVVc2VyUmVzcG9uc2UiJQoRRGVsZXRlVXNlclJlcXVlc3QSEAoIdXNlcm5hbWUYASABKAkiFAoSRGVsZXRlVXNlclJlc3BvbnNlMuADCgtBdXRoU2VydmljZRJFCgpHZXRTZXNzaW9uEhkuYXBpLnYxLkdldFNlc3Npb25SZXF1ZXN0GhouYXBpLnYxLkdldFNlc3Npb25SZXNwb25zZSIAEjYKBUxvZ2luEhQuYXBpLnYxLkxvZ2luUmVxdWVzdBoVLmFwaS52MS5Mb2dpblJlc3BvbnNlIgASOQoGTG9nb3V0EhUuYXBpLnYxLkxvZ291dFJlcXVlc3QaFi5hcGkudjEuTG9nb3V0UmVzcG9uc2UiABJCCglMaXN0VXNlcnMSGC5hcGkudjEuTGlzdFVzZXJzUmVxdWVzdBoZLmFwaS52MS5MaXN0VXNlcnNSZXNwb25zZSIAEkUKCkNyZWF0ZVVzZXISGS5hcGkudjEuQ3JlYXRlVXNlclJlcXVlc3QaGi5hcGkudjEuQ3JlYXRlVXNlclJlc3BvbnNlIgASRQoKVXBkYXRlVXNlchIZLmFwaS52MS5VcGRhdGVVc2VyUmVxdWVzdBoaLmFwaS52MS5VcGRhdGVVc2VyUmVzcG9uc2UiABJFCgpEZWxldGVVc2VyEhkuYXBpLnYxLkRlbGV0ZVVzZXJSZXF1ZXN0GhouYXBpLnYxLkRlbGV0ZVVzZXJSZXNwb25zZSIAQoABCgpjb20uYXBpLnYxQglBdXRoUHJvdG9QAVouZ2l0aHViLmNvbS9lbGVlMTc2Ni9idHJmc2d1aWQvZ2VuL2FwaS92MTthcGl2MaICA0FYWKoCBkFwaS5WMcoCBkFwaVxWMeICEkFwaVxWMVxHUEJNZXRhZGF0YeoCB0FwaTo6VjFiBnByb3RvMw");
+
+/**
+ * @generated from message api.v1.Session
+ */
+export type Session = Message<"api.v1.Session"> & {
+  /**
+   * @generated from field: string username = 1;
+   */
+  username: string;
+
+  /**
+   * viewer, operator or admin
+   *
+   * @generated from field: string role = 2;
+   */
+  role: string;
+
+  /**
+   * session, token, proxy, or none when auth is disabled
+   *
+   * @generated from field: string method = 3;
+   */
+  method: string;
+};
+
+/**
+ * Describes the message api.v1.Session.
+ * Use `create(SessionSchema)` to create a new message.
+ */
+export const SessionSchema: GenMessage<Session> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 0);
+
+/**
+ * @generated from message api.v1.GetSessionRequest
+ */
+export type GetSessionRequest = Message<"api.v1.GetSessionRequest"> & {
+};
+
+/**
+ * Describes the message api.v1.GetSessionRequest.
+ * Use `create(GetSessionRequestSchema)` to create a new message.
+ */
+export const GetSessionRequestSchema: GenMessage<GetSessionRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 1);
+
+/**
+ * @generated from message api.v1.GetSessionResponse
+ */
+export type GetSessionResponse = Message<"api.v1.GetSessionResponse"> & {
+  /**
+   * @generated from field: bool auth_enabled = 1;
+   */
+  authEnabled: boolean;
+
+  /**
+   * Unset if the caller is not signed in
+   *
+   * @generated from field: api.v1.Session session = 2;
+   */
+  session?: Session;
+};
+
+/**
+ * Describes the message api.v1.GetSessionResponse.
+ * Use `create(GetSessionResponseSchema)` to create a new message.
+ */
+export const GetSessionResponseSchema: GenMessage<GetSessionResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 2);
+
+/**
+ * @generated from message api.v1.LoginRequest
+ */
+export type LoginRequest = Message<"api.v1.LoginRequest"> & {
+  /**
+   * @generated from field: string username = 1;
+   */
+  username: string;
+
+  /**
+   * @generated from field: string password = 2;
+   */
+  password: string;
+};
+
+/**
+ * Describes the message api.v1.LoginRequest.
+ * Use `create(LoginRequestSchema)` to create a new message.
+ */
+export const LoginRequestSchema: GenMessage<LoginRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 3);
+
+/**
+ * @generated from message api.v1.LoginResponse
+ */
+export type LoginResponse = Message<"api.v1.LoginResponse"> & {
+  /**
+   * @generated from field: api.v1.Session session = 1;
+   */
+  session?: Session;
+
+  /**
+   * @generated from field: int64 expires_at = 2;
+   */
+  expiresAt: bigint;
+};
+
+/**
+ * Describes the message api.v1.LoginResponse.
+ * Use `create(LoginResponseSchema)` to create a new message.
+ */
+export const LoginResponseSchema: GenMessage<LoginResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 4);
+
+/**
+ * @generated from message api.v1.LogoutRequest
+ */
+export type LogoutRequest = Message<"api.v1.LogoutRequest"> & {
+};
+
+/**
+ * Describes the message api.v1.LogoutRequest.
+ * Use `create(LogoutRequestSchema)` to create a new message.
+ */
+export const LogoutRequestSchema: GenMessage<LogoutRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 5);
+
+/**
+ * @generated from message api.v1.LogoutResponse
+ */
+export type LogoutResponse = Message<"api.v1.LogoutResponse"> & {
+};
+
+/**
+ * Describes the message api.v1.LogoutResponse.
+ * Use `create(LogoutResponseSchema)` to create a new message.
+ */
+export const LogoutResponseSchema: GenMessage<LogoutResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 6);
+
+/**
+ * @generated from message api.v1.User
+ */
+export type User = Message<"api.v1.User"> & {
+  /**
+   * @generated from field: string username = 1;
+   */
+  username: string;
+
+  /**
+   * @generated from field: string role = 2;
+   */
+  role: string;
+
+  /**
+   * @generated from field: int64 created_at = 3;
+   */
+  createdAt: bigint;
+
+  /**
+   * @generated from field: int64 updated_at = 4;
+   */
+  updatedAt: bigint;
+};
+
+/**
+ * Describes the message api.v1.User.
+ * Use `create(UserSchema)` to create a new message.
+ */
+export const UserSchema: GenMessage<User> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 7);
+
+/**
+ * @generated from message api.v1.ListUsersRequest
+ */
+export type ListUsersRequest = Message<"api.v1.ListUsersRequest"> & {
+};
+
+/**
+ * Describes the message api.v1.ListUsersRequest.
+ * Use `create(ListUsersRequestSchema)` to create a new message.
+ */
+export const ListUsersRequestSchema: GenMessage<ListUsersRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 8);
+
+/**
+ * @generated from message api.v1.ListUsersResponse
+ */
+export type ListUsersResponse = Message<"api.v1.ListUsersResponse"> & {
+  /**
+   * @generated from field: repeated api.v1.User users = 1;
+   */
+  users: User[];
+};
+
+/**
+ * Describes the message api.v1.ListUsersResponse.
+ * Use `create(ListUsersResponseSchema)` to create a new message.
+ */
+export const ListUsersResponseSchema: GenMessage<ListUsersResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 9);
+
+/**
+ * @generated from message api.v1.CreateUserRequest
+ */
+export type CreateUserRequest = Message<"api.v1.CreateUserRequest"> & {
+  /**
+   * @generated from field: string username = 1;
+   */
+  username: string;
+
+  /**
+   * @generated from field: string password = 2;
+   */
+  password: string;
+
+  /**
+   * @generated from field: string role = 3;
+   */
+  role: string;
+};
+
+/**
+ * Describes the message api.v1.CreateUserRequest.
+ * Use `create(CreateUserRequestSchema)` to create a new message.
+ */
+export const CreateUserRequestSchema: GenMessage<CreateUserRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 10);
+
+/**
+ * @generated from message api.v1.CreateUserResponse
+ */
+export type CreateUserResponse = Message<"api.v1.CreateUserResponse"> & {
+  /**
+   * @generated from field: api.v1.User user = 1;
+   */
+  user?: User;
+};
+
+/**
+ * Describes the message api.v1.CreateUserResponse.
+ * Use `create(CreateUserResponseSchema)` to create a new message.
+ */
+export const CreateUserResponseSchema: GenMessage<CreateUserResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 11);
+
+/**
+ * @generated from message api.v1.UpdateUserRequest
+ */
+export type UpdateUserRequest = Message<"api.v1.UpdateUserRequest"> & {
+  /**
+   * @generated from field: string username = 1;
+   */
+  username: string;
+
+  /**
+   * Empty leaves it unchanged
+   *
+   * @generated from field: string role = 2;
+   */
+  role: string;
+
+  /**
+   * Empty leaves it unchanged; changing it signs the user out
+   *
+   * @generated from field: string password = 3;
+   */
+  password: string;
+};
+
+/**
+ * Describes the message api.v1.UpdateUserRequest.
+ * Use `create(UpdateUserRequestSchema)` to create a new message.
+ */
+export const UpdateUserRequestSchema: GenMessage<UpdateUserRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 12);
+
+/**
+ * @generated from message api.v1.UpdateUserResponse
+ */
+export type UpdateUserResponse = Message<"api.v1.UpdateUserResponse"> & {
+};
+
+/**
+ * Describes the message api.v1.UpdateUserResponse.
+ * Use `create(UpdateUserResponseSchema)` to create a new message.
+ */
+export const UpdateUserResponseSchema: GenMessage<UpdateUserResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 13);
+
+/**
+ * @generated from message api.v1.DeleteUserRequest
+ */
+export type DeleteUserRequest = Message<"api.v1.DeleteUserRequest"> & {
+  /**
+   * @generated from field: string username = 1;
+   */
+  username: string;
+};
+
+/**
+ * Describes the message api.v1.DeleteUserRequest.
+ * Use `create(DeleteUserRequestSchema)` to create a new message.
+ */
+export const DeleteUserRequestSchema: GenMessage<DeleteUserRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 14);
+
+/**
+ * @generated from message api.v1.DeleteUserResponse
+ */
+export type DeleteUserResponse = Message<"api.v1.DeleteUserResponse"> & {
+};
+
+/**
+ * Describes the message api.v1.DeleteUserResponse.
+ * Use `create(DeleteUserResponseSchema)` to create a new message.
+ */
+export const DeleteUserResponseSchema: GenMessage<DeleteUserResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 15);
+
+/**
+ * @generated from service api.v1.AuthService
+ */
+export const AuthService: GenService<{
+  /**
+   * GetSession returns who the caller is, and whether auth is enabled at all.
+   * Anyone can call it.
+   *
+   * @generated from rpc api.v1.AuthService.GetSession
+   */
+  getSession: {
+    methodKind: "unary";
+    input: typeof GetSessionRequestSchema;
+    output: typeof GetSessionResponseSchema;
+  },
+  /**
+   * Login checks a local user's password and sets the session cookie
+   *
+   * @generated from rpc api.v1.AuthService.Login
+   */
+  login: {
+    methodKind: "unary";
+    input: typeof LoginRequestSchema;
+    output: typeof LoginResponseSchema;
+  },
+  /**
+   * Logout ends the caller's session and clears the cookie
+   *
+   * @generated from rpc api.v1.AuthService.Logout
+   */
+  logout: {
+    methodKind: "unary";
+    input: typeof LogoutRequestSchema;
+    output: typeof LogoutResponseSchema;
+  },
+  /**
+   * User management (admin only)
+   *
+   * @generated from rpc api.v1.AuthService.ListUsers
+   */
+  listUsers: {
+    methodKind: "unary";
+    input: typeof ListUsersRequestSchema;
+    output: typeof ListUsersResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.AuthService.CreateUser
+   */
+  createUser: {
+    methodKind: "unary";
+    input: typeof CreateUserRequestSchema;
+    output: typeof CreateUserResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.AuthService.UpdateUser
+   */
+  updateUser: {
+    methodKind: "unary";
+    input: typeof UpdateUserRequestSchema;
+    output: typeof UpdateUserResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.AuthService.DeleteUser
+   */
+  deleteUser: {
+    methodKind: "unary";
+    input: typeof DeleteUserRequestSchema;
+    output: typeof DeleteUserResponseSchema;
+  },
+}> = /*@__PURE__*/
+  serviceDesc(file_api_v1_auth, 0);
+
@@ -10,7 +10,7 @@ import type { Message } from "@bufbuild/protobuf";
  * Describes the file api/v1/balance.proto.
  */
 export const file_api_v1_balance: GenFile = /*@__PURE__*/
-  fileDesc("ChRhcGkvdjEvYmFsYW5jZS5wcm90bxIGYXBpLnYxIp4BChNTdGFydEJhbGFuY2VSZXF1ZXN0EhMKC2RldmljZV9wYXRoGAEgASgJEicKB2ZpbHRlcnMYAiABKAsyFi5hcGkudjEuQmFsYW5jZUZpbHRlcnMSFQoNbGltaXRfcGVyY2VudBgDIAEoBRISCgpiYWNrZ3JvdW5kGAQgASgIEg8KB2RyeV9ydW4YBSABKAgSDQoFZm9yY2UYBiABKAgibQoOQmFsYW5jZUZpbHRlcnMSDAoEZGF0YRgBIAEoCBIQCghtZXRhZGF0YRgCIAEoCBIOCgZzeXN0ZW0YAyABKAgSFQoNdXNhZ2VfcGVyY2VudBgEIAEoBRIUCgxsaW1pdF9jaHVua3MYBSABKAMiggEKDEJhbGFuY2VGbGFncxInCgdmaWx0ZXJzGAEgASgLMhYuYXBpLnYxLkJhbGFuY2VGaWx0ZXJzEhUKDWxpbWl0X3BlcmNlbnQYAiABKAUSEgoKYmFja2dyb3VuZBgDIAEoCBIPCgdkcnlfcnVuGAQgASgIEg0KBWZvcmNlGAUgASgIIjsKFFN0YXJ0QmFsYW5jZVJlc3BvbnNlEhIKCmJhbGFuY2VfaWQYASABKAkSDwoHc3RhcnRlZBgCIAEoCCIrChRDYW5jZWxCYWxhbmNlUmVxdWVzdBITCgtkZXZpY2VfcGF0aBgBIAEoCSIoChVDYW5jZWxCYWxhbmNlUmVzcG9uc2USDwoHc3VjY2VzcxgBIAEoCCIuChdHZXRCYWxhbmNlU3RhdHVzUmVxdWVzdBITCgtkZXZpY2VfcGF0aBgBIAEoCSKwAgoPQmFsYW5jZVByb2dyZXNzEg4KBnN0YXR1cxgBIAEoCRISCgppc19ydW5uaW5nGAIgASgIEhQKDHRvdGFsX2NodW5rcxgDIAEoAxISCgpjb25zaWRlcmVkGAQgASgDEhEKCXJlbG9jYXRlZBgFIAEoAxIMCgRsZWZ0GAYgASgDEhIKCnNpemVfdG90YWwYByABKAMSFgoOc2l6ZV9yZWxvY2F0ZWQYCCABKAMSEgoKc3RhcnRlZF9hdBgJIAEoAxITCgtmaW5pc2hlZF9hdBgKIAEoAxIQCghkdXJhdGlvbhgLIAEoCRIYChBkdXJhdGlvbl9zZWNvbmRzGAwgASgDEhMKC3NvZnRfZXJyb3JzGA0gASgFEhgKEHByb2dyZXNzX3BlcmNlbnQYDiABKAEiWQoYR2V0QmFsYW5jZVN0YXR1c1Jlc3BvbnNlEikKCHByb2dyZXNzGAEgASgLMhcuYXBpLnYxLkJhbGFuY2VQcm9ncmVzcxISCgppc19ydW5uaW5nGAIgASgIIhwKGkdldEFsbEJhbGFuY2VTdGF0dXNSZXF1ZXN0In0KF0ZpbGVzeXN0ZW1CYWxhbmNlU3RhdHVzEgwKBHBhdGgYASABKAkSKQoIcHJvZ3Jlc3MYAiABKAsyFy5hcGkudjEuQmFsYW5jZVByb2dyZXNzEhIKCmlzX3J1bm5pbmcYAyABKAgSFQoNZXJyb3JfbWVzc2FnZRgEIAEoCSJTChtHZXRBbGxCYWxhbmNlU3RhdHVzUmVzcG9uc2USNAoLZmlsZXN5c3RlbXMYASADKAsyHy5hcGkudjEuRmlsZXN5c3RlbUJhbGFuY2VTdGF0dXMi4wEKE0JhbGFuY2VIaXN0b3J5RW50cnkSEgoKYmFsYW5jZV9pZBgBIAEoCRITCgtkZXZpY2VfcGF0aBgCIAEoCRISCgpzdGFydGVkX2F0GAMgASgDEhMKC2ZpbmlzaGVkX2F0GAQgASgDEg4KBnN0YXR1cxgFIAEoCRIYChBjaHVua3NfcmVsb2NhdGVkGAYgASgDEhYKDnNpemVfcmVsb2NhdGVkGAcgASgDEhMKC3NvZnRfZXJyb3JzGAggASgFEiMKBWZsYWdzGAkgASgLMhQuYXBpLnYxLkJhbGFuY2VGbGFncyI/ChlMaXN0QmFsYW5jZUhpc3RvcnlSZXF1ZXN0EhMKC2RldmljZV9wYXRoGAEgASgJEg0KBWxpbWl0GAIgASgFIkoKGkxpc3RCYWxhbmNlSGlzdG9yeVJlc3BvbnNlEiwKB2VudHJpZXMYASADKAsyGy5hcGkudjEuQmFsYW5jZUhpc3RvcnlFbnRyeTLHAwoOQmFsYW5jZVNlcnZpY2USSwoMU3RhcnRCYWxhbmNlEhsuYXBpLnYxLlN0YXJ0QmFsYW5jZVJlcXVlc3QaHC5hcGkudjEuU3RhcnRCYWxhbmNlUmVzcG9uc2UiABJOCg1DYW5jZWxCYWxhbmNlEhwuYXBpLnYxLkNhbmNlbEJhbGFuY2VSZXF1ZXN0Gh0uYXBpLnYxLkNhbmNlbEJhbGFuY2VSZXNwb25zZSIAElcKEEdldEJhbGFuY2VTdGF0dXMSHy5hcGkudjEuR2V0QmFsYW5jZVN0YXR1c1JlcXVlc3QaIC5hcGkudjEuR2V0QmFsYW5jZVN0YXR1c1Jlc3BvbnNlIgASYAoTR2V0QWxsQmFsYW5jZVN0YXR1cxIiLmFwaS52MS5HZXRBbGxCYWxhbmNlU3RhdHVzUmVxdWVzdBojLmFwaS52MS5HZXRBbGxCYWxhbmNlU3RhdHVzUmVzcG9uc2UiABJdChJMaXN0QmFsYW5jZUhpc3RvcnkSIS5hcGkudjEuTGlzdEJhbGFuY2VIaXN0b3J5UmVxdWVzdBoiLmFwaS52MS5MaXN0QmFsYW5jZUhpc3RvcnlSZXNwb25zZSIAQoMBCgpjb20uYXBpLnYxQgxCYWxhbmNlUHJvdG9QAVouZ2l0aHViLmNvbS9lbGVlMTc2Ni9idHJmc2d1aWQvZ2VuL2FwaS92MTthcGl2MaICA0FYWKoCBkFwaS5WMcoCBkFwaVxWMeICEkFwaVxWMVxHUEJNZXRhZGF0YeoCB0FwaTo6VjFiBnByb3RvMw");
+  fileDesc("ChRhcGkvdjEvYmFsYW5jZS5wcm90bxIGYXBpLnYxIp4BChNTdGFydEJhbGFuY2VSZXF1ZXN0EhMKC2RldmljZV9wYXRoGAEgASgJEicKB2ZpbHRlcnMYAiABKAsyFi5hcGkudjEuQmFsYW5jZUZpbHRlcnMSFQoNbGltaXRfcGVyY2VudBgDIAEoBRISCgpiYWNrZ3JvdW5kGAQgASgIEg8KB2RyeV9ydW4YBSABKAgSDQoFZm9yY2UYBiABKAgi/AEKDkJhbGFuY2VGaWx0ZXJzEgwKBGRhdGEYASABKAgSEAoIbWV0YWRhdGEYAiABKAgSDgoGc3lzdGVtGAMgASgIEhUKDXVzYWdlX3BlcmNlbnQYBCABKAUSFAoMbGltaXRfY2h1bmtzGAUgASgDEhQKDGRhdGFfY29udmVydBgGIAEoCRIYChBtZXRhZGF0YV9jb252ZXJ0GAcgASgJEhYKDnN5c3RlbV9jb252ZXJ0GAggASgJEgwKBHNvZnQYCSABKAgSDQoFZGV2aWQYCiABKAQSFAoMZHJhbmdlX3N0YXJ0GAsgASgEEhIKCmRyYW5nZV9lbmQYDCABKAQiggEKDEJhbGFuY2VGbGFncxInCgdmaWx0ZXJzGAEgASgLMhYuYXBpLnYxLkJhbGFuY2VGaWx0ZXJzEhUKDWxpbWl0X3BlcmNlbnQYAiABKAUSEgoKYmFja2dyb3VuZBgDIAEoCBIPCgdkcnlfcnVuGAQgASgIEg0KBWZvcmNlGAUgASgIIjsKFFN0YXJ0QmFsYW5jZVJlc3BvbnNlEhIKCmJhbGFuY2VfaWQYASABKAkSDwoHc3RhcnRlZBgCIAEoCCIrChRDYW5jZWxCYWxhbmNlUmVxdWVzdBITCgtkZXZpY2VfcGF0aBgBIAEoCSIoChVDYW5jZWxCYWxhbmNlUmVzcG9uc2USDwoHc3VjY2VzcxgBIAEoCCIuChdHZXRCYWxhbmNlU3RhdHVzUmVxdWVzdBITCgtkZXZpY2VfcGF0aBgBIAEoCSKwAgoPQmFsYW5jZVByb2dyZXNzEg4KBnN0YXR1cxgBIAEoCRISCgppc19ydW5uaW5nGAIgASgIEhQKDHRvdGFsX2NodW5rcxgDIAEoAxISCgpjb25zaWRlcmVkGAQgASgDEhEKCXJlbG9jYXRlZBgFIAEoAxIMCgRsZWZ0GAYgASgDEhIKCnNpemVfdG90YWwYByABKAMSFgoOc2l6ZV9yZWxvY2F0ZWQYCCABKAMSEgoKc3RhcnRlZF9hdBgJIAEoAxITCgtmaW5pc2hlZF9hdBgKIAEoAxIQCghkdXJhdGlvbhgLIAEoCRIYChBkdXJhdGlvbl9zZWNvbmRzGAwgASgDEhMKC3NvZnRfZXJyb3JzGA0gASgFEhgKEHByb2dyZXNzX3BlcmNlbnQYDiABKAEiWQoYR2V0QmFsYW5jZVN0YXR1c1Jlc3BvbnNlEikKCHByb2dyZXNzGAEgASgLMhcuYXBpLnYxLkJhbGFuY2VQcm9ncmVzcxISCgppc19ydW5uaW5nGAIgASgIIhwKGkdldEFsbEJhbGFuY2VTdGF0dXNSZXF1ZXN0In0KF0ZpbGVzeXN0ZW1CYWxhbmNlU3RhdHVzEgwKBHBhdGgYASABKAkSKQoIcHJvZ3Jlc3MYAiABKAsyFy5hcGkudjEuQmFsYW5jZVByb2dyZXNzEhIKCmlzX3J1bm5pbmcYAyABKAgSFQoNZXJyb3JfbWVzc2FnZRgEIAEoCSJTChtHZXRBbGxCYWxhbmNlU3RhdHVzUmVzcG9uc2USNAoLZmlsZXN5c3RlbXMYASADKAsyHy5hcGkudjEuRmlsZXN5c3RlbUJhbGFuY2VTdGF0dXMi4wEKE0JhbGFuY2VIaXN0b3J5RW50cnkSEgoKYmFsYW5jZV9pZBgBIAEoCRITCgtkZXZpY2VfcGF0aBgCIAEoCRISCgpzdGFydGVkX2F0GAMgASgDEhMKC2ZpbmlzaGVkX2F0GAQgASgDEg4KBnN0YXR1cxgFIAEoCRIYChBjaHVua3NfcmVsb2NhdGVkGAYgASgDEhYKDnNpemVfcmVsb2NhdGVkGAcgASgDEhMKC3NvZnRfZXJyb3JzGAggASgFEiMKBWZsYWdzGAkgASgLMhQuYXBpLnYxLkJhbGFuY2VGbGFncyI/ChlMaXN0QmFsYW5jZUhpc3RvcnlSZXF1ZXN0EhMKC2RldmljZV9wYXRoGAEgASgJEg0KBWxpbWl0GAIgASgFIkoKGkxpc3RCYWxhbmNlSGlzdG9yeVJlc3BvbnNlEiwKB2VudHJpZXMYASADKAsyGy5hcGkudjEuQmFsYW5jZUhpc3RvcnlFbnRyeSJRChFDaHVua1Byb2ZpbGVVc2FnZRIMCgR0eXBlGAEgASgJEg8KB3Byb2ZpbGUYAiABKAkSDgoGY2h1bmtzGAMgASgDEg0KBWJ5dGVzGAQgASgDIpoBCg1TdGFsZVByb2ZpbGVzEgwKBHR5cGUYASABKAkSDgoGdGFyZ2V0GAIgASgJEhUKDXRhcmdldF9zb3VyY2UYAyABKAkSKgoHY3VycmVudBgEIAEoCzIZLmFwaS52MS5DaHVua1Byb2ZpbGVVc2FnZRIoCgVzdGFsZRgFIAMoCzIZLmFwaS52MS5DaHVua1Byb2ZpbGVVc2FnZSIuChdHZXRQcm9maWxlU3RhdHVzUmVxdWVzdBITCgtkZXZpY2VfcGF0aBgBIAEoCSJtChhHZXRQcm9maWxlU3RhdHVzUmVzcG9uc2USKwoIcHJvZmlsZXMYASADKAsyGS5hcGkudjEuQ2h1bmtQcm9maWxlVXNhZ2USJAoFc3RhbGUYAiADKAsyFS5hcGkudjEuU3RhbGVQcm9maWxlcyJzChdGaW5pc2hDb252ZXJzaW9uUmVxdWVzdBITCgtkZXZpY2VfcGF0aBgBIAEoCRITCgtkYXRhX3RhcmdldBgCIAEoCRIXCg9tZXRhZGF0YV90YXJnZXQYAyABKAkSFQoNc3lzdGVtX3RhcmdldBgEIAEoCSJoChhGaW5pc2hDb252ZXJzaW9uUmVzcG9uc2USEgoKYmFsYW5jZV9pZBgBIAEoCRIPCgdzdGFydGVkGAIgASgIEicKB2ZpbHRlcnMYAyABKAsyFi5hcGkudjEuQmFsYW5jZUZpbHRlcnMiTgoYQW5hbHl6ZUFsbG9jYXRpb25SZXF1ZXN0EhMKC2RldmljZV9wYXRoGAEgASgJEgwKBHR5cGUYAiABKAkSDwoHcHJvZmlsZRgDIAEoCSKaAQoYRGV2aWNlQWxsb2NhdGlvbkFuYWx5c2lzEg0KBWRldmlkGAEgASgEEgwKBHBhdGgYAiABKAkSDAoEc2l6ZRgDIAEoAxIRCglhbGxvY2F0ZWQYBCABKAMSEwoLdW5hbGxvY2F0ZWQYBSABKAMSEAoIc3RyYW5kZWQYBiABKAMSGQoRYmFsYW5jZWRfc3RyYW5kZWQYByABKAMi0QEKFEFsbG9jYXRpb25TdWdnZXN0aW9uEg0KBWRldmlkGAEgASgEEg0KBWxpbWl0GAIgASgDEg4KBnVzYWJsZRgDIAEoAxIQCghyZWNvdmVycxgEIAEoAxIUCgxkcmFuZ2Vfc3RhcnQYBSABKAQSEgoKZHJhbmdlX2VuZBgGIAEoBBIVCg1kcmFuZ2VfY2h1bmtzGAcgASgDEicKB2ZpbHRlcnMYCCABKAsyFi5hcGkudjEuQmFsYW5jZUZpbHRlcnMSDwoHY29tbWFuZBgJIAEoCSKgAgoZQW5hbHl6ZUFsbG9jYXRpb25SZXNwb25zZRIMCgR0eXBlGAEgASgJEg8KB3Byb2ZpbGUYAiABKAkSMQoHZGV2aWNlcxgDIAMoCzIgLmFwaS52MS5EZXZpY2VBbGxvY2F0aW9uQW5hbHlzaXMSEwoLdW5hbGxvY2F0ZWQYBCABKAMSDgoGdXNhYmxlGAUgASgDEhAKCHN0cmFuZGVkGAYgASgDEhcKD2JhbGFuY2VkX3VzYWJsZRgHIAEoAxIZChFiYWxhbmNlZF9zdHJhbmRlZBgIIAEoAxITCgtyZWNvdmVyYWJsZRgJIAEoAxIxCgtzdWdnZXN0aW9ucxgKIAMoCzIcLmFwaS52MS5BbGxvY2F0aW9uU3VnZ2VzdGlvbiKLAQoSUGxhbkJhbGFuY2VSZXF1ZXN0EhMKC2RldmljZV9wYXRoGAEgASgJEgwKBHR5cGUYAiABKAkSGAoQZ29hbF91bmFsbG9jYXRlZBgDIAEoAxISCgp0aHJlc2hvbGRzGAQgAygFEg0KBXN0YXJ0GAUgASgIEhUKDWxpbWl0X3BlcmNlbnQYBiABKAUi7QEKDkJhbGFuY2VQbGFuUnVuEg0KBXVzYWdlGAEgASgFEg4KBmNodW5rcxgCIAEoAxITCgtjaHVua19ieXRlcxgDIAEoAxITCgttb3ZlZF9ieXRlcxgEIAEoAxISCgpuZXdfY2h1bmtzGAUgASgDEhMKC2ZyZWVkX2J5dGVzGAYgASgDEhkKEXVuYWxsb2NhdGVkX2FmdGVyGAcgASgDEhQKDHJlYWNoZXNfZ29hbBgIIAEoCBInCgdmaWx0ZXJzGAkgASgLMhYuYXBpLnYxLkJhbGFuY2VGaWx0ZXJzEg8KB2NvbW1hbmQYCiABKAki6AEKE1BsYW5CYWxhbmNlUmVzcG9uc2USDAoEdHlwZRgBIAEoCRIOCgZjaHVua3MYAiABKAMSDgoGbGVuZ3RoGAMgASgDEgwKBHVzZWQYBCABKAMSEgoKY2h1bmtfc2l6ZRgFIAEoAxITCgt1bmFsbG9jYXRlZBgGIAEoAxIMCgRnb2FsGAcgASgDEiQKBHJ1bnMYCCADKAsyFi5hcGkudjEuQmFsYW5jZVBsYW5SdW4SEwoLcmVjb21tZW5kZWQYCSABKAUSEgoKYmFsYW5jZV9pZBgKIAEoCRIPCgdzdGFydGVkGAsgASgIMp8GCg5CYWxhbmNlU2VydmljZRJLCgxTdGFydEJhbGFuY2USGy5hcGkudjEuU3RhcnRCYWxhbmNlUmVxdWVzdBocLmFwaS52MS5TdGFydEJhbGFuY2VSZXNwb25zZSIAEk4KDUNhbmNlbEJhbGFuY2USHC5hcGkudjEuQ2FuY2VsQmFsYW5jZVJlcXVlc3QaHS5hcGkudjEuQ2FuY2VsQmFsYW5jZVJlc3BvbnNlIgASVwoQR2V0QmFsYW5jZVN0YXR1cxIfLmFwaS52MS5HZXRCYWxhbmNlU3RhdHVzUmVxdWVzdBogLmFwaS52MS5HZXRCYWxhbmNlU3RhdHVzUmVzcG9uc2UiABJgChNHZXRBbGxCYWxhbmNlU3RhdHVzEiIuYXBpLnYxLkdldEFsbEJhbGFuY2VTdGF0dXNSZXF1ZXN0GiMuYXBpLnYxLkdldEFsbEJhbGFuY2VTdGF0dXNSZXNwb25zZSIAEl0KEkxpc3RCYWxhbmNlSGlzdG9yeRIhLmFwaS52MS5MaXN0QmFsYW5jZUhpc3RvcnlSZXF1ZXN0GiIuYXBpLnYxLkxpc3RCYWxhbmNlSGlzdG9yeVJlc3BvbnNlIgASVwoQR2V0UHJvZmlsZVN0YXR1cxIfLmFwaS52MS5HZXRQcm9maWxlU3RhdHVzUmVxdWVzdBogLmFwaS52MS5HZXRQcm9maWxlU3RhdHVzUmVzcG9uc2UiABJXChBGaW5pc2hDb252ZXJzaW9uEh8uYXBpLnYxLkZpbmlzaENvbnZlcnNpb25SZXF1ZXN0GiAuYXBpLnYxLkZpbmlzaENvbnZlcnNpb25SZXNwb25zZSIAEloKEUFuYWx5emVBbGxvY2F0aW9uEiAuYXBpLnYxLkFuYWx5emVBbGxvY2F0aW9uUmVxdWVzdBohLmFwaS52MS5BbmFseXplQWxsb2NhdGlvblJlc3BvbnNlIgASSAoLUGxhbkJhbGFuY2USGi5hcGkudjEuUGxhbkJhbGFuY2VSZXF1ZXN0GhsuYXBpLnYxLlBsYW5CYWxhbmNlUmVzcG9uc2UiAEKDAQoKY29tLmFwaS52MUIMQmFsYW5jZVByb3RvUAFaLmdpdGh1Yi5jb20vZWxlZTE3NjYvYnRyZnNndWlkL2dlbi9hcGkvdjE7YXBpdjGiAgNBWFiqAgZBcGkuVjHKAgZBcGlcVjHiAhJBcGlcVjFcR1BCTWV0YWRhdGHqAgdBcGk6OlYxYgZwcm90bzM");
 
 /**
  * @generated from message api.v1.StartBalanceRequest
@@ -104,6 +104,49 @@ export type BalanceFilters = Message<"api.v1.BalanceFilters"> & {
    * @generated from field: int64 limit_chunks = 5;
    */
   limitChunks: bigint;
+
+  /**
+   * Profile conversion targets, e.g. "raid1" (empty = no conversion)
+   *
+   * @generated from field: string data_convert = 6;
+   */
+  dataConvert: string;
+
+  /**
+   * @generated from field: string metadata_convert = 7;
+   */
+  metadataConvert: string;
+
+  /**
+   * @generated from field: string system_convert = 8;
+   */
+  systemConvert: string;
+
+  /**
+   * Skip chunks that already have the target profile
+   *
+   * @generated from field: bool soft = 9;
+   */
+  soft: boolean;
+
+  /**
+   * Only chunks with a stripe on this device (0 = any device)
+   *
+   * @generated from field: uint64 devid = 10;
+   */
+  devid: bigint;
+
+  /**
+   * Only chunks overlapping this physical byte range on devid (end 0 = no range)
+   *
+   * @generated from field: uint64 drange_start = 11;
+   */
+  drangeStart: bigint;
+
+  /**
+   * @generated from field: uint64 drange_end = 12;
+   */
+  drangeEnd: bigint;
 };
 
 /**
@@ -513,6 +556,634 @@ export type ListBalanceHistoryResponse = Message<"api.v1.ListBalanceHistoryRespo
 export const ListBalanceHistoryResponseSchema: GenMessage<ListBalanceHistoryResponse> = /*@__PURE__*/
   messageDesc(file_api_v1_balance, 14);
 
+/**
+ * Chunks of one block group type and profile
+ *
+ * @generated from message api.v1.ChunkProfileUsage
+ */
+export type ChunkProfileUsage = Message<"api.v1.ChunkProfileUsage"> & {
+  /**
+   * "Data", "Metadata", "System"
+   *
+   * @generated from field: string type = 1;
+   */
+  type: string;
+
+  /**
+   * "single", "DUP", "RAID1", etc.
+   *
+   * @generated from field: string profile = 2;
+   */
+  profile: string;
+
+  /**
+   * @generated from field: int64 chunks = 3;
+   */
+  chunks: bigint;
+
+  /**
+   * Logical size of the chunks
+   *
+   * @generated from field: int64 bytes = 4;
+   */
+  bytes: bigint;
+};
+
+/**
+ * Describes the message api.v1.ChunkProfileUsage.
+ * Use `create(ChunkProfileUsageSchema)` to create a new message.
+ */
+export const ChunkProfileUsageSchema: GenMessage<ChunkProfileUsage> = /*@__PURE__*/
+  messageDesc(file_api_v1_balance, 15);
+
+/**
+ * A block group type with chunks in more than one profile
+ *
+ * @generated from message api.v1.StaleProfiles
+ */
+export type StaleProfiles = Message<"api.v1.StaleProfiles"> & {
+  /**
+   * @generated from field: string type = 1;
+   */
+  type: string;
+
+  /**
+   * Profile the remaining chunks should be converted to
+   *
+   * @generated from field: string target = 2;
+   */
+  target: string;
+
+  /**
+   * "balance" (from a paused balance) or "kernel" (allocation preference, a guess)
+   *
+   * @generated from field: string target_source = 3;
+   */
+  targetSource: string;
+
+  /**
+   * Chunks already in the target profile
+   *
+   * @generated from field: api.v1.ChunkProfileUsage current = 4;
+   */
+  current?: ChunkProfileUsage;
+
+  /**
+   * Chunks in any other profile
+   *
+   * @generated from field: repeated api.v1.ChunkProfileUsage stale = 5;
+   */
+  stale: ChunkProfileUsage[];
+};
+
+/**
+ * Describes the message api.v1.StaleProfiles.
+ * Use `create(StaleProfilesSchema)` to create a new message.
+ */
+export const StaleProfilesSchema: GenMessage<StaleProfiles> = /*@__PURE__*/
+  messageDesc(file_api_v1_balance, 16);
+
+/**
+ * @generated from message api.v1.GetProfileStatusRequest
+ */
+export type GetProfileStatusRequest = Message<"api.v1.GetProfileStatusRequest"> & {
+  /**
+   * @generated from field: string device_path = 1;
+   */
+  devicePath: string;
+};
+
+/**
+ * Describes the message api.v1.GetProfileStatusRequest.
+ * Use `create(GetProfileStatusRequestSchema)` to create a new message.
+ */
+export const GetProfileStatusRequestSchema: GenMessage<GetProfileStatusRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_balance, 17);
+
+/**
+ * @generated from message api.v1.GetProfileStatusResponse
+ */
+export type GetProfileStatusResponse = Message<"api.v1.GetProfileStatusResponse"> & {
+  /**
+   * @generated from field: repeated api.v1.ChunkProfileUsage profiles = 1;
+   */
+  profiles: ChunkProfileUsage[];
+
+  /**
+   * @generated from field: repeated api.v1.StaleProfiles stale = 2;
+   */
+  stale: StaleProfiles[];
+};
+
+/**
+ * Describes the message api.v1.GetProfileStatusResponse.
+ * Use `create(GetProfileStatusResponseSchema)` to create a new message.
+ */
+export const GetProfileStatusResponseSchema: GenMessage<GetProfileStatusResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_balance, 18);
+
+/**
+ * @generated from message api.v1.FinishConversionRequest
+ */
+export type FinishConversionRequest = Message<"api.v1.FinishConversionRequest"> & {
+  /**
+   * @generated from field: string device_path = 1;
+   */
+  devicePath: string;
+
+  /**
+   * Override the detected targets (empty = use detected target). Required
+   * for types whose target_source is "kernel", since that is only a guess
+   *
+   * @generated from field: string data_target = 2;
+   */
+  dataTarget: string;
+
+  /**
+   * @generated from field: string metadata_target = 3;
+   */
+  metadataTarget: string;
+
+  /**
+   * @generated from field: string system_target = 4;
+   */
+  systemTarget: string;
+};
+
+/**
+ * Describes the message api.v1.FinishConversionRequest.
+ * Use `create(FinishConversionRequestSchema)` to create a new message.
+ */
+export const FinishConversionRequestSchema: GenMessage<FinishConversionRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_balance, 19);
+
+/**
+ * @generated from message api.v1.FinishConversionResponse
+ */
+export type FinishConversionResponse = Message<"api.v1.FinishConversionResponse"> & {
+  /**
+   * @generated from field: string balance_id = 1;
+   */
+  balanceId: string;
+
+  /**
+   * @generated from field: bool started = 2;
+   */
+  started: boolean;
+
+  /**
+   * Filters the balance was started with
+   *
+   * @generated from field: api.v1.BalanceFilters filters = 3;
+   */
+  filters?: BalanceFilters;
+};
+
+/**
+ * Describes the message api.v1.FinishConversionResponse.
+ * Use `create(FinishConversionResponseSchema)` to create a new message.
+ */
+export const FinishConversionResponseSchema: GenMessage<FinishConversionResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_balance, 20);
+
+/**
+ * @generated from message api.v1.AnalyzeAllocationRequest
+ */
+export type AnalyzeAllocationRequest = Message<"api.v1.AnalyzeAllocationRequest"> & {
+  /**
+   * @generated from field: string device_path = 1;
+   */
+  devicePath: string;
+
+  /**
+   * "data" (default) or "metadata"
+   *
+   * @generated from field: string type = 2;
+   */
+  type: string;
+
+  /**
+   * Simulate this profile instead of the current one, e.g. "raid1c3"
+   *
+   * @generated from field: string profile = 3;
+   */
+  profile: string;
+};
+
+/**
+ * Describes the message api.v1.AnalyzeAllocationRequest.
+ * Use `create(AnalyzeAllocationRequestSchema)` to create a new message.
+ */
+export const AnalyzeAllocationRequestSchema: GenMessage<AnalyzeAllocationRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_balance, 21);
+
+/**
+ * Simulated outcome for one device
+ *
+ * @generated from message api.v1.DeviceAllocationAnalysis
+ */
+export type DeviceAllocationAnalysis = Message<"api.v1.DeviceAllocationAnalysis"> & {
+  /**
+   * @generated from field: uint64 devid = 1;
+   */
+  devid: bigint;
+
+  /**
+   * @generated from field: string path = 2;
+   */
+  path: string;
+
+  /**
+   * @generated from field: int64 size = 3;
+   */
+  size: bigint;
+
+  /**
+   * @generated from field: int64 allocated = 4;
+   */
+  allocated: bigint;
+
+  /**
+   * @generated from field: int64 unallocated = 5;
+   */
+  unallocated: bigint;
+
+  /**
+   * Unallocated bytes no chunk can use with the current layout
+   *
+   * @generated from field: int64 stranded = 6;
+   */
+  stranded: bigint;
+
+  /**
+   * Unallocated bytes no chunk could use after a full balance
+   *
+   * @generated from field: int64 balanced_stranded = 7;
+   */
+  balancedStranded: bigint;
+};
+
+/**
+ * Describes the message api.v1.DeviceAllocationAnalysis.
+ * Use `create(DeviceAllocationAnalysisSchema)` to create a new message.
+ */
+export const DeviceAllocationAnalysisSchema: GenMessage<DeviceAllocationAnalysis> = /*@__PURE__*/
+  messageDesc(file_api_v1_balance, 22);
+
+/**
+ * A devid balance that recovers stranded space
+ *
+ * @generated from message api.v1.AllocationSuggestion
+ */
+export type AllocationSuggestion = Message<"api.v1.AllocationSuggestion"> & {
+  /**
+   * @generated from field: uint64 devid = 1;
+   */
+  devid: bigint;
+
+  /**
+   * Chunks to relocate with devid=N,limit=N
+   *
+   * @generated from field: int64 limit = 2;
+   */
+  limit: bigint;
+
+  /**
+   * Logical bytes allocatable afterwards
+   *
+   * @generated from field: int64 usable = 3;
+   */
+  usable: bigint;
+
+  /**
+   * Additional logical bytes made allocatable
+   *
+   * @generated from field: int64 recovers = 4;
+   */
+  recovers: bigint;
+
+  /**
+   * Physical range on devid covering the same chunks
+   *
+   * @generated from field: uint64 drange_start = 5;
+   */
+  drangeStart: bigint;
+
+  /**
+   * @generated from field: uint64 drange_end = 6;
+   */
+  drangeEnd: bigint;
+
+  /**
+   * Chunks matched by devid=N,drange=... alone
+   *
+   * @generated from field: int64 drange_chunks = 7;
+   */
+  drangeChunks: bigint;
+
+  /**
+   * Ready to pass to StartBalance
+   *
+   * @generated from field: api.v1.BalanceFilters filters = 8;
+   */
+  filters?: BalanceFilters;
+
+  /**
+   * Equivalent btrfs-progs command
+   *
+   * @generated from field: string command = 9;
+   */
+  command: string;
+};
+
+/**
+ * Describes the message api.v1.AllocationSuggestion.
+ * Use `create(AllocationSuggestionSchema)` to create a new message.
+ */
+export const AllocationSuggestionSchema: GenMessage<AllocationSuggestion> = /*@__PURE__*/
+  messageDesc(file_api_v1_balance, 23);
+
+/**
+ * @generated from message api.v1.AnalyzeAllocationResponse
+ */
+export type AnalyzeAllocationResponse = Message<"api.v1.AnalyzeAllocationResponse"> & {
+  /**
+   * @generated from field: string type = 1;
+   */
+  type: string;
+
+  /**
+   * @generated from field: string profile = 2;
+   */
+  profile: string;
+
+  /**
+   * @generated from field: repeated api.v1.DeviceAllocationAnalysis devices = 3;
+   */
+  devices: DeviceAllocationAnalysis[];
+
+  /**
+   * Raw unallocated bytes over all devices
+   *
+   * @generated from field: int64 unallocated = 4;
+   */
+  unallocated: bigint;
+
+  /**
+   * Logical bytes that can still be allocated
+   *
+   * @generated from field: int64 usable = 5;
+   */
+  usable: bigint;
+
+  /**
+   * Raw bytes left unallocatable with the current layout
+   *
+   * @generated from field: int64 stranded = 6;
+   */
+  stranded: bigint;
+
+  /**
+   * Logical bytes allocatable after a full balance
+   *
+   * @generated from field: int64 balanced_usable = 7;
+   */
+  balancedUsable: bigint;
+
+  /**
+   * Raw bytes unallocatable even after a full balance
+   *
+   * @generated from field: int64 balanced_stranded = 8;
+   */
+  balancedStranded: bigint;
+
+  /**
+   * Logical space a balance would make allocatable
+   *
+   * @generated from field: int64 recoverable = 9;
+   */
+  recoverable: bigint;
+
+  /**
+   * @generated from field: repeated api.v1.AllocationSuggestion suggestions = 10;
+   */
+  suggestions: AllocationSuggestion[];
+};
+
+/**
+ * Describes the message api.v1.AnalyzeAllocationResponse.
+ * Use `create(AnalyzeAllocationResponseSchema)` to create a new message.
+ */
+export const AnalyzeAllocationResponseSchema: GenMessage<AnalyzeAllocationResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_balance, 24);
+
+/**
+ * @generated from message api.v1.PlanBalanceRequest
+ */
+export type PlanBalanceRequest = Message<"api.v1.PlanBalanceRequest"> & {
+  /**
+   * @generated from field: string device_path = 1;
+   */
+  devicePath: string;
+
+  /**
+   * "data" (default) or "metadata"
+   *
+   * @generated from field: string type = 2;
+   */
+  type: string;
+
+  /**
+   * Raw unallocated bytes to end up with (0 = 10% of the device size)
+   *
+   * @generated from field: int64 goal_unallocated = 3;
+   */
+  goalUnallocated: bigint;
+
+  /**
+   * usage= filters to simulate (empty = 1, 5, 10, 15, ... 100)
+   *
+   * @generated from field: repeated int32 thresholds = 4;
+   */
+  thresholds: number[];
+
+  /**
+   * Start the recommended balance
+   *
+   * @generated from field: bool start = 5;
+   */
+  start: boolean;
+
+  /**
+   * Passed on to the balance when starting
+   *
+   * @generated from field: int32 limit_percent = 6;
+   */
+  limitPercent: number;
+};
+
+/**
+ * Describes the message api.v1.PlanBalanceRequest.
+ * Use `create(PlanBalanceRequestSchema)` to create a new message.
+ */
+export const PlanBalanceRequestSchema: GenMessage<PlanBalanceRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_balance, 25);
+
+/**
+ * Simulated outcome of one usage= balance
+ *
+ * @generated from message api.v1.BalancePlanRun
+ */
+export type BalancePlanRun = Message<"api.v1.BalancePlanRun"> & {
+  /**
+   * @generated from field: int32 usage = 1;
+   */
+  usage: number;
+
+  /**
+   * Chunks the filter selects
+   *
+   * @generated from field: int64 chunks = 2;
+   */
+  chunks: bigint;
+
+  /**
+   * Their logical size
+   *
+   * @generated from field: int64 chunk_bytes = 3;
+   */
+  chunkBytes: bigint;
+
+  /**
+   * Used bytes rewritten
+   *
+   * @generated from field: int64 moved_bytes = 4;
+   */
+  movedBytes: bigint;
+
+  /**
+   * Chunks allocated for data that doesn't fit elsewhere
+   *
+   * @generated from field: int64 new_chunks = 5;
+   */
+  newChunks: bigint;
+
+  /**
+   * Raw bytes returned to unallocated
+   *
+   * @generated from field: int64 freed_bytes = 6;
+   */
+  freedBytes: bigint;
+
+  /**
+   * @generated from field: int64 unallocated_after = 7;
+   */
+  unallocatedAfter: bigint;
+
+  /**
+   * @generated from field: bool reaches_goal = 8;
+   */
+  reachesGoal: boolean;
+
+  /**
+   * Ready to pass to StartBalance
+   *
+   * @generated from field: api.v1.BalanceFilters filters = 9;
+   */
+  filters?: BalanceFilters;
+
+  /**
+   * Equivalent btrfs-progs command
+   *
+   * @generated from field: string command = 10;
+   */
+  command: string;
+};
+
+/**
+ * Describes the message api.v1.BalancePlanRun.
+ * Use `create(BalancePlanRunSchema)` to create a new message.
+ */
+export const BalancePlanRunSchema: GenMessage<BalancePlanRun> = /*@__PURE__*/
+  messageDesc(file_api_v1_balance, 26);
+
+/**
+ * @generated from message api.v1.PlanBalanceResponse
+ */
+export type PlanBalanceResponse = Message<"api.v1.PlanBalanceResponse"> & {
+  /**
+   * @generated from field: string type = 1;
+   */
+  type: string;
+
+  /**
+   * All chunks of the type
+   *
+   * @generated from field: int64 chunks = 2;
+   */
+  chunks: bigint;
+
+  /**
+   * @generated from field: int64 length = 3;
+   */
+  length: bigint;
+
+  /**
+   * @generated from field: int64 used = 4;
+   */
+  used: bigint;
+
+  /**
+   * Assumed size of new chunks
+   *
+   * @generated from field: int64 chunk_size = 5;
+   */
+  chunkSize: bigint;
+
+  /**
+   * Raw unallocated bytes now
+   *
+   * @generated from field: int64 unallocated = 6;
+   */
+  unallocated: bigint;
+
+  /**
+   * @generated from field: int64 goal = 7;
+   */
+  goal: bigint;
+
+  /**
+   * @generated from field: repeated api.v1.BalancePlanRun runs = 8;
+   */
+  runs: BalancePlanRun[];
+
+  /**
+   * Index into runs, -1 if no balance is needed or helps
+   *
+   * @generated from field: int32 recommended = 9;
+   */
+  recommended: number;
+
+  /**
+   * Set when start was requested
+   *
+   * @generated from field: string balance_id = 10;
+   */
+  balanceId: string;
+
+  /**
+   * @generated from field: bool started = 11;
+   */
+  started: boolean;
+};
+
+/**
+ * Describes the message api.v1.PlanBalanceResponse.
+ * Use `create(PlanBalanceResponseSchema)` to create a new message.
+ */
+export const PlanBalanceResponseSchema: GenMessage<PlanBalanceResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_balance, 27);
+
 /**
  * @generated from service api.v1.BalanceService
  */
@@ -557,6 +1228,50 @@ export const BalanceService: GenService<{
     input: typeof ListBalanceHistoryRequestSchema;
     output: typeof ListBalanceHistoryResponseSchema;
   },
+  /**
+   * GetProfileStatus reports chunk counts per profile and any stale profiles
+   * left over from an interrupted conversion
+   *
+   * @generated from rpc api.v1.BalanceService.GetProfileStatus
+   */
+  getProfileStatus: {
+    methodKind: "unary";
+    input: typeof GetProfileStatusRequestSchema;
+    output: typeof GetProfileStatusResponseSchema;
+  },
+  /**
+   * FinishConversion starts a soft convert balance that relocates only the stale chunks
+   *
+   * @generated from rpc api.v1.BalanceService.FinishConversion
+   */
+  finishConversion: {
+    methodKind: "unary";
+    input: typeof FinishConversionRequestSchema;
+    output: typeof FinishConversionResponseSchema;
+  },
+  /**
+   * AnalyzeAllocation simulates the chunk allocator over the current device
+   * sizes and allocations to find stranded space and the balances that free it
+   *
+   * @generated from rpc api.v1.BalanceService.AnalyzeAllocation
+   */
+  analyzeAllocation: {
+    methodKind: "unary";
+    input: typeof AnalyzeAllocationRequestSchema;
+    output: typeof AnalyzeAllocationResponseSchema;
+  },
+  /**
+   * PlanBalance simulates usage= balances from block group usage and
+   * recommends the cheapest one that reaches an unallocated space goal.
+   * With start set, the recommended balance is started right away.
+   *
+   * @generated from rpc api.v1.BalanceService.PlanBalance
+   */
+  planBalance: {
+    methodKind: "unary";
+    input: typeof PlanBalanceRequestSchema;
+    output: typeof PlanBalanceResponseSchema;
+  },
 }> = /*@__PURE__*/
   serviceDesc(file_api_v1_balance, 0);
 
@@ -0,0 +1,386 @@
+// @generated by protoc-gen-es v2.10.1 with parameter "target=ts"
+// @generated from file api/v1/defrag.proto (package api.v1, syntax proto3)
+/* eslint-disable */
+
+import type { GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv2";
+import { fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf/codegenv2";
+import type { Message } from "@bufbuild/protobuf";
+
+/**
+ * Describes the file api/v1/defrag.proto.
+ */
+export const file_api_v1_defrag: GenFile = /*@__PURE__*/
+  fileDesc("ChNhcGkvdjEvZGVmcmFnLnByb3RvEgZhcGkudjEitAEKDURlZnJhZ09wdGlvbnMSDQoFcGF0aHMYASADKAkSDwoHbWluX2RvZhgCIAEoARITCgttaW5fZXh0ZW50cxgDIAEoBRIYChBleHRlbnRfdGhyZXNob2xkGAQgASgNEhAKCGNvbXByZXNzGAUgASgJEhsKE2xpbWl0X2J5dGVzX3Blcl9zZWMYBiABKAMSFAoMYWxsb3dfc2hhcmVkGAcgASgIEg8KB2RyeV9ydW4YCCABKAgiPAoSU3RhcnREZWZyYWdSZXF1ZXN0EiYKB29wdGlvbnMYASABKAsyFS5hcGkudjEuRGVmcmFnT3B0aW9ucyIlChNTdGFydERlZnJhZ1Jlc3BvbnNlEg4KBmpvYl9pZBgBIAEoCSIlChNDYW5jZWxEZWZyYWdSZXF1ZXN0Eg4KBmpvYl9pZBgBIAEoCSInChRDYW5jZWxEZWZyYWdSZXNwb25zZRIPCgdzdWNjZXNzGAEgASgIIqMDCg5EZWZyYWdQcm9ncmVzcxIOCgZqb2JfaWQYASABKAkSJgoHb3B0aW9ucxgCIAEoCzIVLmFwaS52MS5EZWZyYWdPcHRpb25zEg4KBnN0YXR1cxgDIAEoCRINCgVlcnJvchgEIAEoCRISCgpzdGFydGVkX2F0GAUgASgDEhMKC2ZpbmlzaGVkX2F0GAYgASgDEhUKDWZpbGVzX3NjYW5uZWQYByABKAUSFgoOZmlsZXNfc2VsZWN0ZWQYCCABKAUSEgoKZmlsZXNfZG9uZRgJIAEoBRIUCgxmaWxlc19mYWlsZWQYCiABKAUSHAoUZmlsZXNfc2tpcHBlZF9zaGFyZWQYCyABKAUSEwoLYnl0ZXNfdG90YWwYDCABKAMSEgoKYnl0ZXNfZG9uZRgNIAEoAxIWCg5leHRlbnRzX2JlZm9yZRgOIAEoBRIVCg1leHRlbnRzX2FmdGVyGA8gASgFEhQKDGN1cnJlbnRfZmlsZRgQIAEoCRIYChBwcm9ncmVzc19wZXJjZW50GBEgASgBEhIKCmlzX3J1bm5pbmcYEiABKAgiKAoWR2V0RGVmcmFnU3RhdHVzUmVxdWVzdBIOCgZqb2JfaWQYASABKAkiQwoXR2V0RGVmcmFnU3RhdHVzUmVzcG9uc2USKAoIcHJvZ3Jlc3MYASABKAsyFi5hcGkudjEuRGVmcmFnUHJvZ3Jlc3MiFwoVTGlzdERlZnJhZ0pvYnNSZXF1ZXN0Ij4KFkxpc3REZWZyYWdKb2JzUmVzcG9uc2USJAoEam9icxgBIAMoCzIWLmFwaS52MS5EZWZyYWdQcm9ncmVzcyItChtTdHJlYW1EZWZyYWdQcm9ncmVzc1JlcXVlc3QSDgoGam9iX2lkGAEgASgJMqgDCg1EZWZyYWdTZXJ2aWNlEkgKC1N0YXJ0RGVmcmFnEhouYXBpLnYxLlN0YXJ0RGVmcmFnUmVxdWVzdBobLmFwaS52MS5TdGFydERlZnJhZ1Jlc3BvbnNlIgASSwoMQ2FuY2VsRGVmcmFnEhsuYXBpLnYxLkNhbmNlbERlZnJhZ1JlcXVlc3QaHC5hcGkudjEuQ2FuY2VsRGVmcmFnUmVzcG9uc2UiABJUCg9HZXREZWZyYWdTdGF0dXMSHi5hcGkudjEuR2V0RGVmcmFnU3RhdHVzUmVxdWVzdBofLmFwaS52MS5HZXREZWZyYWdTdGF0dXNSZXNwb25zZSIAElEKDkxpc3REZWZyYWdKb2JzEh0uYXBpLnYxLkxpc3REZWZyYWdKb2JzUmVxdWVzdBoeLmFwaS52MS5MaXN0RGVmcmFnSm9ic1Jlc3BvbnNlIgASVwoUU3RyZWFtRGVmcmFnUHJvZ3Jlc3MSIy5hcGkudjEuU3RyZWFtRGVmcmFnUHJvZ3Jlc3NSZXF1ZXN0GhYuYXBpLnYxLkRlZnJhZ1Byb2dyZXNzIgAwAUKCAQoKY29tLmFwaS52MUILRGVmcmFnUHJvdG9QAVouZ2l0aHViLmNvbS9lbGVlMTc2Ni9idHJmc2d1aWQvZ2VuL2FwaS92MTthcGl2MaICA0FYWKoCBkFwaS5WMcoCBkFwaVxWMeICEkFwaVxWMVxHUEJNZXRhZGF0YeoCB0FwaTo6VjFiBnByb3RvMw");
+
+/**
+ * Which files a defrag job picks and how they are defragmented
+ *
+ * @generated from message api.v1.DefragOptions
+ */
+export type DefragOptions = Message<"api.v1.DefragOptions"> & {
+  /**
+   * Files, or directories searched recursively. Paths from fragmentation
+   * analysis can be passed as-is.
+   *
+   * @generated from field: repeated string paths = 1;
+   */
+  paths: string[];
+
+  /**
+   * Only files with at least this degree of fragmentation / extent count.
+   * With neither set, any file with more extents than ideal is picked.
+   *
+   * @generated from field: double min_dof = 2;
+   */
+  minDof: number;
+
+  /**
+   * @generated from field: int32 min_extents = 3;
+   */
+  minExtents: number;
+
+  /**
+   * Extents at least this long are left alone (bytes, 0 = kernel default)
+   *
+   * @generated from field: uint32 extent_threshold = 4;
+   */
+  extentThreshold: number;
+
+  /**
+   * Recompress with "zlib", "lzo" or "zstd" (empty = mount setting)
+   *
+   * @generated from field: string compress = 5;
+   */
+  compress: string;
+
+  /**
+   * Throughput limit (bytes/sec), 0 = unlimited
+   *
+   * @generated from field: int64 limit_bytes_per_sec = 6;
+   */
+  limitBytesPerSec: bigint;
+
+  /**
+   * Defragment files with shared extents, unsharing them from snapshots
+   *
+   * @generated from field: bool allow_shared = 7;
+   */
+  allowShared: boolean;
+
+  /**
+   * Only select files, don't defragment them
+   *
+   * @generated from field: bool dry_run = 8;
+   */
+  dryRun: boolean;
+};
+
+/**
+ * Describes the message api.v1.DefragOptions.
+ * Use `create(DefragOptionsSchema)` to create a new message.
+ */
+export const DefragOptionsSchema: GenMessage<DefragOptions> = /*@__PURE__*/
+  messageDesc(file_api_v1_defrag, 0);
+
+/**
+ * @generated from message api.v1.StartDefragRequest
+ */
+export type StartDefragRequest = Message<"api.v1.StartDefragRequest"> & {
+  /**
+   * @generated from field: api.v1.DefragOptions options = 1;
+   */
+  options?: DefragOptions;
+};
+
+/**
+ * Describes the message api.v1.StartDefragRequest.
+ * Use `create(StartDefragRequestSchema)` to create a new message.
+ */
+export const StartDefragRequestSchema: GenMessage<StartDefragRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_defrag, 1);
+
+/**
+ * @generated from message api.v1.StartDefragResponse
+ */
+export type StartDefragResponse = Message<"api.v1.StartDefragResponse"> & {
+  /**
+   * @generated from field: string job_id = 1;
+   */
+  jobId: string;
+};
+
+/**
+ * Describes the message api.v1.StartDefragResponse.
+ * Use `create(StartDefragResponseSchema)` to create a new message.
+ */
+export const StartDefragResponseSchema: GenMessage<StartDefragResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_defrag, 2);
+
+/**
+ * @generated from message api.v1.CancelDefragRequest
+ */
+export type CancelDefragRequest = Message<"api.v1.CancelDefragRequest"> & {
+  /**
+   * @generated from field: string job_id = 1;
+   */
+  jobId: string;
+};
+
+/**
+ * Describes the message api.v1.CancelDefragRequest.
+ * Use `create(CancelDefragRequestSchema)` to create a new message.
+ */
+export const CancelDefragRequestSchema: GenMessage<CancelDefragRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_defrag, 3);
+
+/**
+ * @generated from message api.v1.CancelDefragResponse
+ */
+export type CancelDefragResponse = Message<"api.v1.CancelDefragResponse"> & {
+  /**
+   * @generated from field: bool success = 1;
+   */
+  success: boolean;
+};
+
+/**
+ * Describes the message api.v1.CancelDefragResponse.
+ * Use `create(CancelDefragResponseSchema)` to create a new message.
+ */
+export const CancelDefragResponseSchema: GenMessage<CancelDefragResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_defrag, 4);
+
+/**
+ * @generated from message api.v1.DefragProgress
+ */
+export type DefragProgress = Message<"api.v1.DefragProgress"> & {
+  /**
+   * @generated from field: string job_id = 1;
+   */
+  jobId: string;
+
+  /**
+   * @generated from field: api.v1.DefragOptions options = 2;
+   */
+  options?: DefragOptions;
+
+  /**
+   * scanning, running, finished, cancelled, failed
+   *
+   * @generated from field: string status = 3;
+   */
+  status: string;
+
+  /**
+   * @generated from field: string error = 4;
+   */
+  error: string;
+
+  /**
+   * @generated from field: int64 started_at = 5;
+   */
+  startedAt: bigint;
+
+  /**
+   * @generated from field: int64 finished_at = 6;
+   */
+  finishedAt: bigint;
+
+  /**
+   * @generated from field: int32 files_scanned = 7;
+   */
+  filesScanned: number;
+
+  /**
+   * @generated from field: int32 files_selected = 8;
+   */
+  filesSelected: number;
+
+  /**
+   * @generated from field: int32 files_done = 9;
+   */
+  filesDone: number;
+
+  /**
+   * @generated from field: int32 files_failed = 10;
+   */
+  filesFailed: number;
+
+  /**
+   * @generated from field: int32 files_skipped_shared = 11;
+   */
+  filesSkippedShared: number;
+
+  /**
+   * @generated from field: int64 bytes_total = 12;
+   */
+  bytesTotal: bigint;
+
+  /**
+   * @generated from field: int64 bytes_done = 13;
+   */
+  bytesDone: bigint;
+
+  /**
+   * @generated from field: int32 extents_before = 14;
+   */
+  extentsBefore: number;
+
+  /**
+   * @generated from field: int32 extents_after = 15;
+   */
+  extentsAfter: number;
+
+  /**
+   * @generated from field: string current_file = 16;
+   */
+  currentFile: string;
+
+  /**
+   * @generated from field: double progress_percent = 17;
+   */
+  progressPercent: number;
+
+  /**
+   * @generated from field: bool is_running = 18;
+   */
+  isRunning: boolean;
+};
+
+/**
+ * Describes the message api.v1.DefragProgress.
+ * Use `create(DefragProgressSchema)` to create a new message.
+ */
+export const DefragProgressSchema: GenMessage<DefragProgress> = /*@__PURE__*/
+  messageDesc(file_api_v1_defrag, 5);
+
+/**
+ * @generated from message api.v1.GetDefragStatusRequest
+ */
+export type GetDefragStatusRequest = Message<"api.v1.GetDefragStatusRequest"> & {
+  /**
+   * @generated from field: string job_id = 1;
+   */
+  jobId: string;
+};
+
+/**
+ * Describes the message api.v1.GetDefragStatusRequest.
+ * Use `create(GetDefragStatusRequestSchema)` to create a new message.
+ */
+export const GetDefragStatusRequestSchema: GenMessage<GetDefragStatusRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_defrag, 6);
+
+/**
+ * @generated from message api.v1.GetDefragStatusResponse
+ */
+export type GetDefragStatusResponse = Message<"api.v1.GetDefragStatusResponse"> & {
+  /**
+   * @generated from field: api.v1.DefragProgress progress = 1;
+   */
+  progress?: DefragProgress;
+};
+
+/**
+ * Describes the message api.v1.GetDefragStatusResponse.
+ * Use `create(GetDefragStatusResponseSchema)` to create a new message.
+ */
+export const GetDefragStatusResponseSchema: GenMessage<GetDefragStatusResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_defrag, 7);
+
+/**
+ * @generated from message api.v1.ListDefragJobsRequest
+ */
+export type ListDefragJobsRequest = Message<"api.v1.ListDefragJobsRequest"> & {
+};
+
+/**
+ * Describes the message api.v1.ListDefragJobsRequest.
+ * Use `create(ListDefragJobsRequestSchema)` to create a new message.
+ */
+export const ListDefragJobsRequestSchema: GenMessage<ListDefragJobsRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_defrag, 8);
+
+/**
+ * @generated from message api.v1.ListDefragJobsResponse
+ */
+export type ListDefragJobsResponse = Message<"api.v1.ListDefragJobsResponse"> & {
+  /**
+   * Running job first, then finished jobs newest first
+   *
+   * @generated from field: repeated api.v1.DefragProgress jobs = 1;
+   */
+  jobs: DefragProgress[];
+};
+
+/**
+ * Describes the message api.v1.ListDefragJobsResponse.
+ * Use `create(ListDefragJobsResponseSchema)` to create a new message.
+ */
+export const ListDefragJobsResponseSchema: GenMessage<ListDefragJobsResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_defrag, 9);
+
+/**
+ * @generated from message api.v1.StreamDefragProgressRequest
+ */
+export type StreamDefragProgressRequest = Message<"api.v1.StreamDefragProgressRequest"> & {
+  /**
+   * @generated from field: string job_id = 1;
+   */
+  jobId: string;
+};
+
+/**
+ * Describes the message api.v1.StreamDefragProgressRequest.
+ * Use `create(StreamDefragProgressRequestSchema)` to create a new message.
+ */
+export const StreamDefragProgressRequestSchema: GenMessage<StreamDefragProgressRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_defrag, 10);
+
+/**
+ * @generated from service api.v1.DefragService
+ */
+export const DefragService: GenService<{
+  /**
+   * @generated from rpc api.v1.DefragService.StartDefrag
+   */
+  startDefrag: {
+    methodKind: "unary";
+    input: typeof StartDefragRequestSchema;
+    output: typeof StartDefragResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.DefragService.CancelDefrag
+   */
+  cancelDefrag: {
+    methodKind: "unary";
+    input: typeof CancelDefragRequestSchema;
+    output: typeof CancelDefragResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.DefragService.GetDefragStatus
+   */
+  getDefragStatus: {
+    methodKind: "unary";
+    input: typeof GetDefragStatusRequestSchema;
+    output: typeof GetDefragStatusResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.DefragService.ListDefragJobs
+   */
+  listDefragJobs: {
+    methodKind: "unary";
+    input: typeof ListDefragJobsRequestSchema;
+    output: typeof ListDefragJobsResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.DefragService.StreamDefragProgress
+   */
+  streamDefragProgress: {
+    methodKind: "server_streaming";
+    input: typeof StreamDefragProgressRequestSchema;
+    output: typeof DefragProgressSchema;
+  },
+}> = /*@__PURE__*/
+  serviceDesc(file_api_v1_defrag, 0);
+
@@ -0,0 +1,267 @@
+// @generated by protoc-gen-es v2.10.1 with parameter "target=ts"
+// @generated from file api/v1/diagnostics.proto (package api.v1, syntax proto3)
+/* eslint-disable */
+
+import type { GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv2";
+import { fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf/codegenv2";
+import type { Message } from "@bufbuild/protobuf";
+
+/**
+ * Describes the file api/v1/diagnostics.proto.
+ */
+export const file_api_v1_diagnostics: GenFile = /*@__PURE__*/
+  fileDesc("ChhhcGkvdjEvZGlhZ25vc3RpY3MucHJvdG8SBmFwaS52MSJiChFEaWFnbm9zdGljRmluZGluZxIMCgRydWxlGAEgASgJEhAKCHNldmVyaXR5GAIgASgJEg0KBXRpdGxlGAMgASgJEg4KBmRldGFpbBgEIAEoCRIOCgZhY3Rpb24YBSABKAkifQoURGlhZ25vc3RpY1RocmVzaG9sZHMSGgoSc2NydWJfbWF4X2FnZV9kYXlzGAEgASgFEhUKDXNsYWNrX3BlcmNlbnQYAiABKAESFwoPbWluX3NsYWNrX2J5dGVzGAMgASgDEhkKEWltYmFsYW5jZV9wZXJjZW50GAQgASgBIn8KEURpYWdub3N0aWNzUmVwb3J0EgwKBHBhdGgYASABKAkSDAoEdXVpZBgCIAEoCRISCgpjaGVja2VkX2F0GAMgASgDEg0KBXNjb3JlGAQgASgFEisKCGZpbmRpbmdzGAUgAygLMhkuYXBpLnYxLkRpYWdub3N0aWNGaW5kaW5nIl4KFVJ1bkRpYWdub3N0aWNzUmVxdWVzdBITCgtkZXZpY2VfcGF0aBgBIAEoCRIwCgp0aHJlc2hvbGRzGAIgASgLMhwuYXBpLnYxLkRpYWdub3N0aWNUaHJlc2hvbGRzIkMKFlJ1bkRpYWdub3N0aWNzUmVzcG9uc2USKQoGcmVwb3J0GAEgASgLMhkuYXBpLnYxLkRpYWdub3N0aWNzUmVwb3J0IkwKGFJ1bkFsbERpYWdub3N0aWNzUmVxdWVzdBIwCgp0aHJlc2hvbGRzGAEgASgLMhwuYXBpLnYxLkRpYWdub3N0aWNUaHJlc2hvbGRzImcKFUZpbGVzeXN0ZW1EaWFnbm9zdGljcxIMCgRwYXRoGAEgASgJEikKBnJlcG9ydBgCIAEoCzIZLmFwaS52MS5EaWFnbm9zdGljc1JlcG9ydBIVCg1lcnJvcl9tZXNzYWdlGAMgASgJIk8KGVJ1bkFsbERpYWdub3N0aWNzUmVzcG9uc2USMgoLZmlsZXN5c3RlbXMYASADKAsyHS5hcGkudjEuRmlsZXN5c3RlbURpYWdub3N0aWNzMsMBChJEaWFnbm9zdGljc1NlcnZpY2USUQoOUnVuRGlhZ25vc3RpY3MSHS5hcGkudjEuUnVuRGlhZ25vc3RpY3NSZXF1ZXN0Gh4uYXBpLnYxLlJ1bkRpYWdub3N0aWNzUmVzcG9uc2UiABJaChFSdW5BbGxEaWFnbm9zdGljcxIgLmFwaS52MS5SdW5BbGxEaWFnbm9zdGljc1JlcXVlc3QaIS5hcGkudjEuUnVuQWxsRGlhZ25vc3RpY3NSZXNwb25zZSIAQocBCgpjb20uYXBpLnYxQhBEaWFnbm9zdGljc1Byb3RvUAFaLmdpdGh1Yi5jb20vZWxlZTE3NjYvYnRyZnNndWlkL2dlbi9hcGkvdjE7YXBpdjGiAgNBWFiqAgZBcGkuVjHKAgZBcGlcVjHiAhJBcGlcVjFcR1BCTWV0YWRhdGHqAgdBcGk6OlYxYgZwcm90bzM");
+
+/**
+ * A problem detected by a diagnostics rule
+ *
+ * @generated from message api.v1.DiagnosticFinding
+ */
+export type DiagnosticFinding = Message<"api.v1.DiagnosticFinding"> & {
+  /**
+   * Rule ID, e.g. "mixed_profiles"
+   *
+   * @generated from field: string rule = 1;
+   */
+  rule: string;
+
+  /**
+   * "info", "warning", "critical"
+   *
+   * @generated from field: string severity = 2;
+   */
+  severity: string;
+
+  /**
+   * @generated from field: string title = 3;
+   */
+  title: string;
+
+  /**
+   * @generated from field: string detail = 4;
+   */
+  detail: string;
+
+  /**
+   * Recommended action
+   *
+   * @generated from field: string action = 5;
+   */
+  action: string;
+};
+
+/**
+ * Describes the message api.v1.DiagnosticFinding.
+ * Use `create(DiagnosticFindingSchema)` to create a new message.
+ */
+export const DiagnosticFindingSchema: GenMessage<DiagnosticFinding> = /*@__PURE__*/
+  messageDesc(file_api_v1_diagnostics, 0);
+
+/**
+ * Tunable rule thresholds (0 = default)
+ *
+ * @generated from message api.v1.DiagnosticThresholds
+ */
+export type DiagnosticThresholds = Message<"api.v1.DiagnosticThresholds"> & {
+  /**
+   * Default 30
+   *
+   * @generated from field: int32 scrub_max_age_days = 1;
+   */
+  scrubMaxAgeDays: number;
+
+  /**
+   * Default 25
+   *
+   * @generated from field: double slack_percent = 2;
+   */
+  slackPercent: number;
+
+  /**
+   * Default 10 GiB
+   *
+   * @generated from field: int64 min_slack_bytes = 3;
+   */
+  minSlackBytes: bigint;
+
+  /**
+   * Default 25
+   *
+   * @generated from field: double imbalance_percent = 4;
+   */
+  imbalancePercent: number;
+};
+
+/**
+ * Describes the message api.v1.DiagnosticThresholds.
+ * Use `create(DiagnosticThresholdsSchema)` to create a new message.
+ */
+export const DiagnosticThresholdsSchema: GenMessage<DiagnosticThresholds> = /*@__PURE__*/
+  messageDesc(file_api_v1_diagnostics, 1);
+
+/**
+ * @generated from message api.v1.DiagnosticsReport
+ */
+export type DiagnosticsReport = Message<"api.v1.DiagnosticsReport"> & {
+  /**
+   * @generated from field: string path = 1;
+   */
+  path: string;
+
+  /**
+   * @generated from field: string uuid = 2;
+   */
+  uuid: string;
+
+  /**
+   * @generated from field: int64 checked_at = 3;
+   */
+  checkedAt: bigint;
+
+  /**
+   * 0-100, higher is healthier
+   *
+   * @generated from field: int32 score = 4;
+   */
+  score: number;
+
+  /**
+   * @generated from field: repeated api.v1.DiagnosticFinding findings = 5;
+   */
+  findings: DiagnosticFinding[];
+};
+
+/**
+ * Describes the message api.v1.DiagnosticsReport.
+ * Use `create(DiagnosticsReportSchema)` to create a new message.
+ */
+export const DiagnosticsReportSchema: GenMessage<DiagnosticsReport> = /*@__PURE__*/
+  messageDesc(file_api_v1_diagnostics, 2);
+
+/**
+ * @generated from message api.v1.RunDiagnosticsRequest
+ */
+export type RunDiagnosticsRequest = Message<"api.v1.RunDiagnosticsRequest"> & {
+  /**
+   * @generated from field: string device_path = 1;
+   */
+  devicePath: string;
+
+  /**
+   * @generated from field: api.v1.DiagnosticThresholds thresholds = 2;
+   */
+  thresholds?: DiagnosticThresholds;
+};
+
+/**
+ * Describes the message api.v1.RunDiagnosticsRequest.
+ * Use `create(RunDiagnosticsRequestSchema)` to create a new message.
+ */
+export const RunDiagnosticsRequestSchema: GenMessage<RunDiagnosticsRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_diagnostics, 3);
+
+/**
+ * @generated from message api.v1.RunDiagnosticsResponse
+ */
+export type RunDiagnosticsResponse = Message<"api.v1.RunDiagnosticsResponse"> & {
+  /**
+   * @generated from field: api.v1.DiagnosticsReport report = 1;
+   */
+  report?: DiagnosticsReport;
+};
+
+/**
+ * Describes the message api.v1.RunDiagnosticsResponse.
+ * Use `create(RunDiagnosticsResponseSchema)` to create a new message.
+ */
+export const RunDiagnosticsResponseSchema: GenMessage<RunDiagnosticsResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_diagnostics, 4);
+
+/**
+ * @generated from message api.v1.RunAllDiagnosticsRequest
+ */
+export type RunAllDiagnosticsRequest = Message<"api.v1.RunAllDiagnosticsRequest"> & {
+  /**
+   * @generated from field: api.v1.DiagnosticThresholds thresholds = 1;
+   */
+  thresholds?: DiagnosticThresholds;
+};
+
+/**
+ * Describes the message api.v1.RunAllDiagnosticsRequest.
+ * Use `create(RunAllDiagnosticsRequestSchema)` to create a new message.
+ */
+export const RunAllDiagnosticsRequestSchema: GenMessage<RunAllDiagnosticsRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_diagnostics, 5);
+
+/**
+ * @generated from message api.v1.FilesystemDiagnostics
+ */
+export type FilesystemDiagnostics = Message<"api.v1.FilesystemDiagnostics"> & {
+  /**
+   * @generated from field: string path = 1;
+   */
+  path: string;
+
+  /**
+   * @generated from field: api.v1.DiagnosticsReport report = 2;
+   */
+  report?: DiagnosticsReport;
+
+  /**
+   * If there was an error fetching
+   *
+   * @generated from field: string error_message = 3;
+   */
+  errorMessage: string;
+};
+
+/**
+ * Describes the message api.v1.FilesystemDiagnostics.
+ * Use `create(FilesystemDiagnosticsSchema)` to create a new message.
+ */
+export const FilesystemDiagnosticsSchema: GenMessage<FilesystemDiagnostics> = /*@__PURE__*/
+  messageDesc(file_api_v1_diagnostics, 6);
+
+/**
+ * @generated from message api.v1.RunAllDiagnosticsResponse
+ */
+export type RunAllDiagnosticsResponse = Message<"api.v1.RunAllDiagnosticsResponse"> & {
+  /**
+   * @generated from field: repeated api.v1.FilesystemDiagnostics filesystems = 1;
+   */
+  filesystems: FilesystemDiagnostics[];
+};
+
+/**
+ * Describes the message api.v1.RunAllDiagnosticsResponse.
+ * Use `create(RunAllDiagnosticsResponseSchema)` to create a new message.
+ */
+export const RunAllDiagnosticsResponseSchema: GenMessage<RunAllDiagnosticsResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_diagnostics, 7);
+
+/**
+ * @generated from service api.v1.DiagnosticsService
+ */
+export const DiagnosticsService: GenService<{
+  /**
+   * RunDiagnostics runs the doctor rule set against a filesystem
+   *
+   * @generated from rpc api.v1.DiagnosticsService.RunDiagnostics
+   */
+  runDiagnostics: {
+    methodKind: "unary";
+    input: typeof RunDiagnosticsRequestSchema;
+    output: typeof RunDiagnosticsResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.DiagnosticsService.RunAllDiagnostics
+   */
+  runAllDiagnostics: {
+    methodKind: "unary";
+    input: typeof RunAllDiagnosticsRequestSchema;
+    output: typeof RunAllDiagnosticsResponseSchema;
+  },
+}> = /*@__PURE__*/
+  serviceDesc(file_api_v1_diagnostics, 0);
+
@@ -0,0 +1,456 @@
+// @generated by protoc-gen-es v2.10.1 with parameter "target=ts"
+// @generated from file api/v1/forecast.proto (package api.v1, syntax proto3)
+/* eslint-disable */
+
+import type { GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv2";
+import { fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf/codegenv2";
+import type { Message } from "@bufbuild/protobuf";
+
+/**
+ * Describes the file api/v1/forecast.proto.
+ */
+export const file_api_v1_forecast: GenFile = /*@__PURE__*/
+  fileDesc("ChVhcGkvdjEvZm9yZWNhc3QucHJvdG8SBmFwaS52MSLpAgoLVXNhZ2VTYW1wbGUSEQoJdGltZXN0YW1wGAEgASgDEhMKC2RldmljZV9zaXplGAIgASgDEhgKEGRldmljZV9hbGxvY2F0ZWQYAyABKAMSGgoSZGV2aWNlX3VuYWxsb2NhdGVkGAQgASgDEhIKCmRhdGFfdG90YWwYBSABKAMSEQoJZGF0YV91c2VkGAYgASgDEhQKDGRhdGFfcHJvZmlsZRgHIAEoCRIWCg5tZXRhZGF0YV90b3RhbBgIIAEoAxIVCg1tZXRhZGF0YV91c2VkGAkgASgDEhgKEG1ldGFkYXRhX3Byb2ZpbGUYCiABKAkSFAoMc3lzdGVtX3RvdGFsGAsgASgDEhMKC3N5c3RlbV91c2VkGAwgASgDEhYKDmdsb2JhbF9yZXNlcnZlGA0gASgDEhsKE2dsb2JhbF9yZXNlcnZlX3VzZWQYDiABKAMSFgoOZnJlZV9lc3RpbWF0ZWQYDyABKAMiSwoWR2V0VXNhZ2VIaXN0b3J5UmVxdWVzdBITCgtkZXZpY2VfcGF0aBgBIAEoCRINCgVzaW5jZRgCIAEoAxINCgVsaW1pdBgDIAEoBSI/ChdHZXRVc2FnZUhpc3RvcnlSZXNwb25zZRIkCgdzYW1wbGVzGAEgAygLMhMuYXBpLnYxLlVzYWdlU2FtcGxlIjYKClVzYWdlVHJlbmQSFQoNYnl0ZXNfcGVyX2RheRgBIAEoARIRCglyX3NxdWFyZWQYAiABKAEiPQoKRW5vc3BjUmlzaxIMCgRjb2RlGAEgASgJEhAKCHNldmVyaXR5GAIgASgJEg8KB21lc3NhZ2UYAyABKAkihwQKDVVzYWdlRm9yZWNhc3QSDwoHc2FtcGxlcxgBIAEoBRIUCgx3aW5kb3dfc3RhcnQYAiABKAMSEgoKd2luZG93X2VuZBgDIAEoAxIjCgZsYXRlc3QYBCABKAsyEy5hcGkudjEuVXNhZ2VTYW1wbGUSJwoLdW5hbGxvY2F0ZWQYBSABKAsyEi5hcGkudjEuVXNhZ2VUcmVuZBIqCg5kYXRhX2FsbG9jYXRlZBgGIAEoCzISLmFwaS52MS5Vc2FnZVRyZW5kEiUKCWRhdGFfdXNlZBgHIAEoCzISLmFwaS52MS5Vc2FnZVRyZW5kEi4KEm1ldGFkYXRhX2FsbG9jYXRlZBgIIAEoCzISLmFwaS52MS5Vc2FnZVRyZW5kEikKDW1ldGFkYXRhX3VzZWQYCSABKAsyEi5hcGkudjEuVXNhZ2VUcmVuZBIgChh1bmFsbG9jYXRlZF9leGhhdXN0ZWRfYXQYCiABKAMSHQoVbWV0YWRhdGFfZXhoYXVzdGVkX2F0GAsgASgDEiMKG21ldGFkYXRhX2NodW5rX2V4aGF1c3RlZF9hdBgPIAEoAxIYChBkYXRhX2NodW5rX2J5dGVzGAwgASgDEhwKFG1ldGFkYXRhX2NodW5rX2J5dGVzGA0gASgDEiEKBXJpc2tzGA4gAygLMhIuYXBpLnYxLkVub3NwY1Jpc2siQwoXR2V0VXNhZ2VGb3JlY2FzdFJlcXVlc3QSEwoLZGV2aWNlX3BhdGgYASABKAkSEwoLd2luZG93X2RheXMYAiABKAUiQwoYR2V0VXNhZ2VGb3JlY2FzdFJlc3BvbnNlEicKCGZvcmVjYXN0GAEgASgLMhUuYXBpLnYxLlVzYWdlRm9yZWNhc3QiMgobR2V0QWxsVXNhZ2VGb3JlY2FzdHNSZXF1ZXN0EhMKC3dpbmRvd19kYXlzGAEgASgFImcKF0ZpbGVzeXN0ZW1Vc2FnZUZvcmVjYXN0EgwKBHBhdGgYASABKAkSJwoIZm9yZWNhc3QYAiABKAsyFS5hcGkudjEuVXNhZ2VGb3JlY2FzdBIVCg1lcnJvcl9tZXNzYWdlGAMgASgJIlQKHEdldEFsbFVzYWdlRm9yZWNhc3RzUmVzcG9uc2USNAoLZmlsZXN5c3RlbXMYASADKAsyHy5hcGkudjEuRmlsZXN5c3RlbVVzYWdlRm9yZWNhc3QypQIKD0ZvcmVjYXN0U2VydmljZRJUCg9HZXRVc2FnZUhpc3RvcnkSHi5hcGkudjEuR2V0VXNhZ2VIaXN0b3J5UmVxdWVzdBofLmFwaS52MS5HZXRVc2FnZUhpc3RvcnlSZXNwb25zZSIAElcKEEdldFVzYWdlRm9yZWNhc3QSHy5hcGkudjEuR2V0VXNhZ2VGb3JlY2FzdFJlcXVlc3QaIC5hcGkudjEuR2V0VXNhZ2VGb3JlY2FzdFJlc3BvbnNlIgASYwoUR2V0QWxsVXNhZ2VGb3JlY2FzdHMSIy5hcGkudjEuR2V0QWxsVXNhZ2VGb3JlY2FzdHNSZXF1ZXN0GiQuYXBpLnYxLkdldEFsbFVzYWdlRm9yZWNhc3RzUmVzcG9uc2UiAEKEAQoKY29tLmFwaS52MUINRm9yZWNhc3RQcm90b1ABWi5naXRodWIuY29tL2VsZWUxNzY2L2J0cmZzZ3VpZC9nZW4vYXBpL3YxO2FwaXYxogIDQVhYqgIGQXBpLlYxygIGQXBpXFYx4gISQXBpXFYxXEdQQk1ldGFkYXRh6gIHQXBpOjpWMWIGcHJvdG8z");
+
+/**
+ * One recorded usage sample
+ *
+ * @generated from message api.v1.UsageSample
+ */
+export type UsageSample = Message<"api.v1.UsageSample"> & {
+  /**
+   * @generated from field: int64 timestamp = 1;
+   */
+  timestamp: bigint;
+
+  /**
+   * @generated from field: int64 device_size = 2;
+   */
+  deviceSize: bigint;
+
+  /**
+   * @generated from field: int64 device_allocated = 3;
+   */
+  deviceAllocated: bigint;
+
+  /**
+   * @generated from field: int64 device_unallocated = 4;
+   */
+  deviceUnallocated: bigint;
+
+  /**
+   * @generated from field: int64 data_total = 5;
+   */
+  dataTotal: bigint;
+
+  /**
+   * @generated from field: int64 data_used = 6;
+   */
+  dataUsed: bigint;
+
+  /**
+   * @generated from field: string data_profile = 7;
+   */
+  dataProfile: string;
+
+  /**
+   * @generated from field: int64 metadata_total = 8;
+   */
+  metadataTotal: bigint;
+
+  /**
+   * @generated from field: int64 metadata_used = 9;
+   */
+  metadataUsed: bigint;
+
+  /**
+   * @generated from field: string metadata_profile = 10;
+   */
+  metadataProfile: string;
+
+  /**
+   * @generated from field: int64 system_total = 11;
+   */
+  systemTotal: bigint;
+
+  /**
+   * @generated from field: int64 system_used = 12;
+   */
+  systemUsed: bigint;
+
+  /**
+   * @generated from field: int64 global_reserve = 13;
+   */
+  globalReserve: bigint;
+
+  /**
+   * @generated from field: int64 global_reserve_used = 14;
+   */
+  globalReserveUsed: bigint;
+
+  /**
+   * @generated from field: int64 free_estimated = 15;
+   */
+  freeEstimated: bigint;
+};
+
+/**
+ * Describes the message api.v1.UsageSample.
+ * Use `create(UsageSampleSchema)` to create a new message.
+ */
+export const UsageSampleSchema: GenMessage<UsageSample> = /*@__PURE__*/
+  messageDesc(file_api_v1_forecast, 0);
+
+/**
+ * @generated from message api.v1.GetUsageHistoryRequest
+ */
+export type GetUsageHistoryRequest = Message<"api.v1.GetUsageHistoryRequest"> & {
+  /**
+   * @generated from field: string device_path = 1;
+   */
+  devicePath: string;
+
+  /**
+   * Unix timestamp, 0 = all
+   *
+   * @generated from field: int64 since = 2;
+   */
+  since: bigint;
+
+  /**
+   * Most recent N samples, 0 = no limit
+   *
+   * @generated from field: int32 limit = 3;
+   */
+  limit: number;
+};
+
+/**
+ * Describes the message api.v1.GetUsageHistoryRequest.
+ * Use `create(GetUsageHistoryRequestSchema)` to create a new message.
+ */
+export const GetUsageHistoryRequestSchema: GenMessage<GetUsageHistoryRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_forecast, 1);
+
+/**
+ * @generated from message api.v1.GetUsageHistoryResponse
+ */
+export type GetUsageHistoryResponse = Message<"api.v1.GetUsageHistoryResponse"> & {
+  /**
+   * Oldest first
+   *
+   * @generated from field: repeated api.v1.UsageSample samples = 1;
+   */
+  samples: UsageSample[];
+};
+
+/**
+ * Describes the message api.v1.GetUsageHistoryResponse.
+ * Use `create(GetUsageHistoryResponseSchema)` to create a new message.
+ */
+export const GetUsageHistoryResponseSchema: GenMessage<GetUsageHistoryResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_forecast, 2);
+
+/**
+ * Linear growth trend
+ *
+ * @generated from message api.v1.UsageTrend
+ */
+export type UsageTrend = Message<"api.v1.UsageTrend"> & {
+  /**
+   * Negative when shrinking
+   *
+   * @generated from field: double bytes_per_day = 1;
+   */
+  bytesPerDay: number;
+
+  /**
+   * Goodness of fit (0-1)
+   *
+   * @generated from field: double r_squared = 2;
+   */
+  rSquared: number;
+};
+
+/**
+ * Describes the message api.v1.UsageTrend.
+ * Use `create(UsageTrendSchema)` to create a new message.
+ */
+export const UsageTrendSchema: GenMessage<UsageTrend> = /*@__PURE__*/
+  messageDesc(file_api_v1_forecast, 3);
+
+/**
+ * Condition that may lead to ENOSPC
+ *
+ * @generated from message api.v1.EnospcRisk
+ */
+export type EnospcRisk = Message<"api.v1.EnospcRisk"> & {
+  /**
+   * e.g. "metadata_full_no_unallocated"
+   *
+   * @generated from field: string code = 1;
+   */
+  code: string;
+
+  /**
+   * "warning", "critical"
+   *
+   * @generated from field: string severity = 2;
+   */
+  severity: string;
+
+  /**
+   * @generated from field: string message = 3;
+   */
+  message: string;
+};
+
+/**
+ * Describes the message api.v1.EnospcRisk.
+ * Use `create(EnospcRiskSchema)` to create a new message.
+ */
+export const EnospcRiskSchema: GenMessage<EnospcRisk> = /*@__PURE__*/
+  messageDesc(file_api_v1_forecast, 4);
+
+/**
+ * @generated from message api.v1.UsageForecast
+ */
+export type UsageForecast = Message<"api.v1.UsageForecast"> & {
+  /**
+   * Number of samples the trends were fitted to
+   *
+   * @generated from field: int32 samples = 1;
+   */
+  samples: number;
+
+  /**
+   * Unix timestamp of the oldest sample
+   *
+   * @generated from field: int64 window_start = 2;
+   */
+  windowStart: bigint;
+
+  /**
+   * Unix timestamp of the newest sample
+   *
+   * @generated from field: int64 window_end = 3;
+   */
+  windowEnd: bigint;
+
+  /**
+   * @generated from field: api.v1.UsageSample latest = 4;
+   */
+  latest?: UsageSample;
+
+  /**
+   * @generated from field: api.v1.UsageTrend unallocated = 5;
+   */
+  unallocated?: UsageTrend;
+
+  /**
+   * @generated from field: api.v1.UsageTrend data_allocated = 6;
+   */
+  dataAllocated?: UsageTrend;
+
+  /**
+   * @generated from field: api.v1.UsageTrend data_used = 7;
+   */
+  dataUsed?: UsageTrend;
+
+  /**
+   * @generated from field: api.v1.UsageTrend metadata_allocated = 8;
+   */
+  metadataAllocated?: UsageTrend;
+
+  /**
+   * @generated from field: api.v1.UsageTrend metadata_used = 9;
+   */
+  metadataUsed?: UsageTrend;
+
+  /**
+   * Unix timestamp, 0 = not predicted
+   *
+   * @generated from field: int64 unallocated_exhausted_at = 10;
+   */
+  unallocatedExhaustedAt: bigint;
+
+  /**
+   * Unix timestamp, 0 = not predicted
+   *
+   * @generated from field: int64 metadata_exhausted_at = 11;
+   */
+  metadataExhaustedAt: bigint;
+
+  /**
+   * Unix timestamp a new metadata chunk no longer fits, 0 = not predicted
+   *
+   * @generated from field: int64 metadata_chunk_exhausted_at = 15;
+   */
+  metadataChunkExhaustedAt: bigint;
+
+  /**
+   * Raw bytes needed for a new data chunk
+   *
+   * @generated from field: int64 data_chunk_bytes = 12;
+   */
+  dataChunkBytes: bigint;
+
+  /**
+   * Raw bytes needed for a new metadata chunk
+   *
+   * @generated from field: int64 metadata_chunk_bytes = 13;
+   */
+  metadataChunkBytes: bigint;
+
+  /**
+   * @generated from field: repeated api.v1.EnospcRisk risks = 14;
+   */
+  risks: EnospcRisk[];
+};
+
+/**
+ * Describes the message api.v1.UsageForecast.
+ * Use `create(UsageForecastSchema)` to create a new message.
+ */
+export const UsageForecastSchema: GenMessage<UsageForecast> = /*@__PURE__*/
+  messageDesc(file_api_v1_forecast, 5);
+
+/**
+ * @generated from message api.v1.GetUsageForecastRequest
+ */
+export type GetUsageForecastRequest = Message<"api.v1.GetUsageForecastRequest"> & {
+  /**
+   * @generated from field: string device_path = 1;
+   */
+  devicePath: string;
+
+  /**
+   * How much history to fit (default 30)
+   *
+   * @generated from field: int32 window_days = 2;
+   */
+  windowDays: number;
+};
+
+/**
+ * Describes the message api.v1.GetUsageForecastRequest.
+ * Use `create(GetUsageForecastRequestSchema)` to create a new message.
+ */
+export const GetUsageForecastRequestSchema: GenMessage<GetUsageForecastRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_forecast, 6);
+
+/**
+ * @generated from message api.v1.GetUsageForecastResponse
+ */
+export type GetUsageForecastResponse = Message<"api.v1.GetUsageForecastResponse"> & {
+  /**
+   * @generated from field: api.v1.UsageForecast forecast = 1;
+   */
+  forecast?: UsageForecast;
+};
+
+/**
+ * Describes the message api.v1.GetUsageForecastResponse.
+ * Use `create(GetUsageForecastResponseSchema)` to create a new message.
+ */
+export const GetUsageForecastResponseSchema: GenMessage<GetUsageForecastResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_forecast, 7);
+
+/**
+ * @generated from message api.v1.GetAllUsageForecastsRequest
+ */
+export type GetAllUsageForecastsRequest = Message<"api.v1.GetAllUsageForecastsRequest"> & {
+  /**
+   * @generated from field: int32 window_days = 1;
+   */
+  windowDays: number;
+};
+
+/**
+ * Describes the message api.v1.GetAllUsageForecastsRequest.
+ * Use `create(GetAllUsageForecastsRequestSchema)` to create a new message.
+ */
+export const GetAllUsageForecastsRequestSchema: GenMessage<GetAllUsageForecastsRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_forecast, 8);
+
+/**
+ * @generated from message api.v1.FilesystemUsageForecast
+ */
+export type FilesystemUsageForecast = Message<"api.v1.FilesystemUsageForecast"> & {
+  /**
+   * @generated from field: string path = 1;
+   */
+  path: string;
+
+  /**
+   * @generated from field: api.v1.UsageForecast forecast = 2;
+   */
+  forecast?: UsageForecast;
+
+  /**
+   * If there was an error fetching
+   *
+   * @generated from field: string error_message = 3;
+   */
+  errorMessage: string;
+};
+
+/**
+ * Describes the message api.v1.FilesystemUsageForecast.
+ * Use `create(FilesystemUsageForecastSchema)` to create a new message.
+ */
+export const FilesystemUsageForecastSchema: GenMessage<FilesystemUsageForecast> = /*@__PURE__*/
+  messageDesc(file_api_v1_forecast, 9);
+
+/**
+ * @generated from message api.v1.GetAllUsageForecastsResponse
+ */
+export type GetAllUsageForecastsResponse = Message<"api.v1.GetAllUsageForecastsResponse"> & {
+  /**
+   * @generated from field: repeated api.v1.FilesystemUsageForecast filesystems = 1;
+   */
+  filesystems: FilesystemUsageForecast[];
+};
+
+/**
+ * Describes the message api.v1.GetAllUsageForecastsResponse.
+ * Use `create(GetAllUsageForecastsResponseSchema)` to create a new message.
+ */
+export const GetAllUsageForecastsResponseSchema: GenMessage<GetAllUsageForecastsResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_forecast, 10);
+
+/**
+ * @generated from service api.v1.ForecastService
+ */
+export const ForecastService: GenService<{
+  /**
+   * GetUsageHistory returns the periodically recorded usage samples for a filesystem
+   *
+   * @generated from rpc api.v1.ForecastService.GetUsageHistory
+   */
+  getUsageHistory: {
+    methodKind: "unary";
+    input: typeof GetUsageHistoryRequestSchema;
+    output: typeof GetUsageHistoryResponseSchema;
+  },
+  /**
+   * GetUsageForecast fits allocation growth and predicts when space runs out
+   *
+   * @generated from rpc api.v1.ForecastService.GetUsageForecast
+   */
+  getUsageForecast: {
+    methodKind: "unary";
+    input: typeof GetUsageForecastRequestSchema;
+    output: typeof GetUsageForecastResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.ForecastService.GetAllUsageForecasts
+   */
+  getAllUsageForecasts: {
+    methodKind: "unary";
+    input: typeof GetAllUsageForecastsRequestSchema;
+    output: typeof GetAllUsageForecastsResponseSchema;
+  },
+}> = /*@__PURE__*/
+  serviceDesc(file_api_v1_forecast, 0);
+
@@ -10,7 +10,7 @@ import type { Message } from "@bufbuild/protobuf";
  * Describes the file api/v1/fragmap.proto.
  */
 export const file_api_v1_fragmap: GenFile = /*@__PURE__*/
-  fileDesc("ChRhcGkvdjEvZnJhZ21hcC5wcm90bxIGYXBpLnYxIiQKEUdldEZyYWdNYXBSZXF1ZXN0Eg8KB2ZzX3BhdGgYASABKAkiRAoGRGV2aWNlEgoKAmlkGAEgASgEEgwKBHV1aWQYAiABKAwSEgoKdG90YWxfc2l6ZRgDIAEoBBIMCgRwYXRoGAQgASgJIisKBlN0cmlwZRIRCglkZXZpY2VfaWQYASABKAQSDgoGb2Zmc2V0GAIgASgEIn0KBUNodW5rEhYKDmxvZ2ljYWxfb2Zmc2V0GAEgASgEEg4KBmxlbmd0aBgCIAEoBBIMCgR0eXBlGAMgASgEEg8KB3Byb2ZpbGUYBCABKAQSHwoHc3RyaXBlcxgFIAMoCzIOLmFwaS52MS5TdHJpcGUSDAoEdXNlZBgGIAEoBCJgCgxEZXZpY2VFeHRlbnQSEQoJZGV2aWNlX2lkGAEgASgEEhcKD3BoeXNpY2FsX29mZnNldBgCIAEoBBIOCgZsZW5ndGgYAyABKAQSFAoMY2h1bmtfb2Zmc2V0GAQgASgEIpYBChJHZXRGcmFnTWFwUmVzcG9uc2USEgoKdG90YWxfc2l6ZRgBIAEoBBIfCgdkZXZpY2VzGAIgAygLMg4uYXBpLnYxLkRldmljZRIdCgZjaHVua3MYAyADKAsyDS5hcGkudjEuQ2h1bmsSLAoOZGV2aWNlX2V4dGVudHMYBCADKAsyFC5hcGkudjEuRGV2aWNlRXh0ZW50Ij4KGEdldERldmljZUJsb2NrTWFwUmVxdWVzdBIPCgdmc19wYXRoGAEgASgJEhEKCWRldmljZV9pZBgCIAEoBCKhAQoNQmxvY2tNYXBFbnRyeRIOCgZvZmZzZXQYASABKAQSDgoGbGVuZ3RoGAIgASgEEgwKBHR5cGUYAyABKAQSDwoHcHJvZmlsZRgEIAEoBBIRCglhbGxvY2F0ZWQYBSABKAgSFAoMY2h1bmtfb2Zmc2V0GAYgASgEEhIKCmNodW5rX3VzZWQYByABKAQSFAoMY2h1bmtfbGVuZ3RoGAggASgEImoKGUdldERldmljZUJsb2NrTWFwUmVzcG9uc2USEQoJZGV2aWNlX2lkGAEgASgEEhIKCnRvdGFsX3NpemUYAiABKAQSJgoHZW50cmllcxgDIAMoCzIVLmFwaS52MS5CbG9ja01hcEVudHJ5IksKEUdldEhlYXRNYXBSZXF1ZXN0Eg8KB2ZzX3BhdGgYASABKAkSEQoJZGV2aWNlX2lkGAIgASgEEhIKCnJlc29sdXRpb24YAyABKAUi4AEKC0hlYXRNYXBDZWxsEg0KBWluZGV4GAEgASgFEhQKDHN0YXJ0X29mZnNldBgCIAEoBBISCgplbmRfb2Zmc2V0GAMgASgEEhcKD2FsbG9jYXRlZF9ieXRlcxgEIAEoBBISCgpmcmVlX2J5dGVzGAUgASgEEhIKCmRhdGFfYnl0ZXMYBiABKAQSFgoObWV0YWRhdGFfYnl0ZXMYByABKAQSFAoMc3lzdGVtX2J5dGVzGAggASgEEhQKDGV4dGVudF9jb3VudBgJIAEoBRITCgt1dGlsaXphdGlvbhgKIAEoASJzChJHZXRIZWF0TWFwUmVzcG9uc2USEQoJZGV2aWNlX2lkGAEgASgEEhIKCnRvdGFsX3NpemUYAiABKAQSEgoKcmVzb2x1dGlvbhgDIAEoBRIiCgVjZWxscxgEIAMoCzITLmFwaS52MS5IZWF0TWFwQ2VsbCI5ChNHZXRGcmFnU3RhdHNSZXF1ZXN0Eg8KB2ZzX3BhdGgYASABKAkSEQoJZGV2aWNlX2lkGAIgASgEIqgCCglGcmFnU3RhdHMSEQoJZGV2aWNlX2lkGAEgASgEEhIKCnRvdGFsX3NpemUYAiABKAQSFgoOYWxsb2NhdGVkX3NpemUYAyABKAQSEQoJZnJlZV9zaXplGAQgASgEEhEKCWRhdGFfc2l6ZRgFIAEoBBIVCg1tZXRhZGF0YV9zaXplGAYgASgEEhMKC3N5c3RlbV9zaXplGAcgASgEEhMKC251bV9leHRlbnRzGAggASgFEhgKEG51bV9mcmVlX3JlZ2lvbnMYCSABKAUSFAoMbGFyZ2VzdF9mcmVlGAogASgEEhUKDXNtYWxsZXN0X2ZyZWUYCyABKAQSFwoPYXZnX2V4dGVudF9zaXplGAwgASgEEhUKDWF2Z19mcmVlX3NpemUYDSABKAQiOAoUR2V0RnJhZ1N0YXRzUmVzcG9uc2USIAoFc3RhdHMYASADKAsyES5hcGkudjEuRnJhZ1N0YXRzIkAKGUdldERldmljZUJsb2NrTWFwc1JlcXVlc3QSDwoHZnNfcGF0aBgBIAEoCRISCgpkZXZpY2VfaWRzGAIgAygEIo4BCg5EZXZpY2VCbG9ja01hcBIeCgZkZXZpY2UYASABKAsyDi5hcGkudjEuRGV2aWNlEhIKCnRvdGFsX3NpemUYAiABKAQSJgoHZW50cmllcxgDIAMoCzIVLmFwaS52MS5CbG9ja01hcEVudHJ5EiAKBXN0YXRzGAQgASgLMhEuYXBpLnYxLkZyYWdTdGF0cyJCChpHZXREZXZpY2VCbG9ja01hcHNSZXNwb25zZRIkCgRtYXBzGAEgAygLMhYuYXBpLnYxLkRldmljZUJsb2NrTWFwMqYDCg5GcmFnTWFwU2VydmljZRJFCgpHZXRGcmFnTWFwEhkuYXBpLnYxLkdldEZyYWdNYXBSZXF1ZXN0GhouYXBpLnYxLkdldEZyYWdNYXBSZXNwb25zZSIAEloKEUdldERldmljZUJsb2NrTWFwEiAuYXBpLnYxLkdldERldmljZUJsb2NrTWFwUmVxdWVzdBohLmFwaS52MS5HZXREZXZpY2VCbG9ja01hcFJlc3BvbnNlIgASXQoSR2V0RGV2aWNlQmxvY2tNYXBzEiEuYXBpLnYxLkdldERldmljZUJsb2NrTWFwc1JlcXVlc3QaIi5hcGkudjEuR2V0RGV2aWNlQmxvY2tNYXBzUmVzcG9uc2UiABJFCgpHZXRIZWF0TWFwEhkuYXBpLnYxLkdldEhlYXRNYXBSZXF1ZXN0GhouYXBpLnYxLkdldEhlYXRNYXBSZXNwb25zZSIAEksKDEdldEZyYWdTdGF0cxIbLmFwaS52MS5HZXRGcmFnU3RhdHNSZXF1ZXN0GhwuYXBpLnYxLkdldEZyYWdTdGF0c1Jlc3BvbnNlIgBCgwEKCmNvbS5hcGkudjFCDEZyYWdtYXBQcm90b1ABWi5naXRodWIuY29tL2VsZWUxNzY2L2J0cmZzZ3VpZC9nZW4vYXBpL3YxO2FwaXYxogIDQVhYqgIGQXBpLlYxygIGQXBpXFYx4gISQXBpXFYxXEdQQk1ldGFkYXRh6gIHQXBpOjpWMWIGcHJvdG8z");
+  fileDesc("ChRhcGkvdjEvZnJhZ21hcC5wcm90bxIGYXBpLnYxIiQKEUdldEZyYWdNYXBSZXF1ZXN0Eg8KB2ZzX3BhdGgYASABKAkiRAoGRGV2aWNlEgoKAmlkGAEgASgEEgwKBHV1aWQYAiABKAwSEgoKdG90YWxfc2l6ZRgDIAEoBBIMCgRwYXRoGAQgASgJIisKBlN0cmlwZRIRCglkZXZpY2VfaWQYASABKAQSDgoGb2Zmc2V0GAIgASgEIn0KBUNodW5rEhYKDmxvZ2ljYWxfb2Zmc2V0GAEgASgEEg4KBmxlbmd0aBgCIAEoBBIMCgR0eXBlGAMgASgEEg8KB3Byb2ZpbGUYBCABKAQSHwoHc3RyaXBlcxgFIAMoCzIOLmFwaS52MS5TdHJpcGUSDAoEdXNlZBgGIAEoBCJgCgxEZXZpY2VFeHRlbnQSEQoJZGV2aWNlX2lkGAEgASgEEhcKD3BoeXNpY2FsX29mZnNldBgCIAEoBBIOCgZsZW5ndGgYAyABKAQSFAoMY2h1bmtfb2Zmc2V0GAQgASgEIpYBChJHZXRGcmFnTWFwUmVzcG9uc2USEgoKdG90YWxfc2l6ZRgBIAEoBBIfCgdkZXZpY2VzGAIgAygLMg4uYXBpLnYxLkRldmljZRIdCgZjaHVua3MYAyADKAsyDS5hcGkudjEuQ2h1bmsSLAoOZGV2aWNlX2V4dGVudHMYBCADKAsyFC5hcGkudjEuRGV2aWNlRXh0ZW50Ij4KGEdldERldmljZUJsb2NrTWFwUmVxdWVzdBIPCgdmc19wYXRoGAEgASgJEhEKCWRldmljZV9pZBgCIAEoBCKhAQoNQmxvY2tNYXBFbnRyeRIOCgZvZmZzZXQYASABKAQSDgoGbGVuZ3RoGAIgASgEEgwKBHR5cGUYAyABKAQSDwoHcHJvZmlsZRgEIAEoBBIRCglhbGxvY2F0ZWQYBSABKAgSFAoMY2h1bmtfb2Zmc2V0GAYgASgEEhIKCmNodW5rX3VzZWQYByABKAQSFAoMY2h1bmtfbGVuZ3RoGAggASgEImoKGUdldERldmljZUJsb2NrTWFwUmVzcG9uc2USEQoJZGV2aWNlX2lkGAEgASgEEhIKCnRvdGFsX3NpemUYAiABKAQSJgoHZW50cmllcxgDIAMoCzIVLmFwaS52MS5CbG9ja01hcEVudHJ5IpkBChFHZXRIZWF0TWFwUmVxdWVzdBIPCgdmc19wYXRoGAEgASgJEhEKCWRldmljZV9pZBgCIAEoBBISCgpyZXNvbHV0aW9uGAMgASgFEhIKCmZyZWVfc3BhY2UYBCABKAgSDgoGbGF5b3V0GAUgASgJEhQKDHN0YXJ0X29mZnNldBgGIAEoBBISCgplbmRfb2Zmc2V0GAcgASgEIv4CCgtIZWF0TWFwQ2VsbBINCgVpbmRleBgBIAEoBRIUCgxzdGFydF9vZmZzZXQYAiABKAQSEgoKZW5kX29mZnNldBgDIAEoBBIXCg9hbGxvY2F0ZWRfYnl0ZXMYBCABKAQSEgoKZnJlZV9ieXRlcxgFIAEoBBISCgpkYXRhX2J5dGVzGAYgASgEEhYKDm1ldGFkYXRhX2J5dGVzGAcgASgEEhQKDHN5c3RlbV9ieXRlcxgIIAEoBBIUCgxleHRlbnRfY291bnQYCSABKAUSEwoLdXRpbGl6YXRpb24YCiABKAESGAoQY2h1bmtfZnJlZV9ieXRlcxgLIAEoBBIdChVmcmVlX3NwYWNlX2ZyYWdfc2NvcmUYDCABKAESGAoQZG9taW5hbnRfcHJvZmlsZRgNIAEoBBIYChBjaHVua191c2VkX2J5dGVzGA4gASgEEhkKEWJsb2NrX2dyb3VwX3VzYWdlGA8gASgBEgkKAXgYECABKAUSCQoBeRgRIAEoBSKaAgoSR2V0SGVhdE1hcFJlc3BvbnNlEhEKCWRldmljZV9pZBgBIAEoBBISCgp0b3RhbF9zaXplGAIgASgEEhIKCnJlc29sdXRpb24YAyABKAUSIgoFY2VsbHMYBCADKAsyEy5hcGkudjEuSGVhdE1hcENlbGwSGQoRZnJlZV9zcGFjZV9zb3VyY2UYBSABKAkSMQoMYmxvY2tfZ3JvdXBzGAYgAygLMhsuYXBpLnYxLkJsb2NrR3JvdXBGcmVlU3BhY2USDgoGbGF5b3V0GAcgASgJEg0KBXdpZHRoGAggASgFEg4KBmhlaWdodBgJIAEoBRIUCgxzdGFydF9vZmZzZXQYCiABKAQSEgoKZW5kX29mZnNldBgLIAEoBCI5ChNHZXRGcmFnU3RhdHNSZXF1ZXN0Eg8KB2ZzX3BhdGgYASABKAkSEQoJZGV2aWNlX2lkGAIgASgEIqgCCglGcmFnU3RhdHMSEQoJZGV2aWNlX2lkGAEgASgEEhIKCnRvdGFsX3NpemUYAiABKAQSFgoOYWxsb2NhdGVkX3NpemUYAyABKAQSEQoJZnJlZV9zaXplGAQgASgEEhEKCWRhdGFfc2l6ZRgFIAEoBBIVCg1tZXRhZGF0YV9zaXplGAYgASgEEhMKC3N5c3RlbV9zaXplGAcgASgEEhMKC251bV9leHRlbnRzGAggASgFEhgKEG51bV9mcmVlX3JlZ2lvbnMYCSABKAUSFAoMbGFyZ2VzdF9mcmVlGAogASgEEhUKDXNtYWxsZXN0X2ZyZWUYCyABKAQSFwoPYXZnX2V4dGVudF9zaXplGAwgASgEEhUKDWF2Z19mcmVlX3NpemUYDSABKAQiOAoUR2V0RnJhZ1N0YXRzUmVzcG9uc2USIAoFc3RhdHMYASADKAsyES5hcGkudjEuRnJhZ1N0YXRzIkAKGUdldERldmljZUJsb2NrTWFwc1JlcXVlc3QSDwoHZnNfcGF0aBgBIAEoCRISCgpkZXZpY2VfaWRzGAIgAygEIo4BCg5EZXZpY2VCbG9ja01hcBIeCgZkZXZpY2UYASABKAsyDi5hcGkudjEuRGV2aWNlEhIKCnRvdGFsX3NpemUYAiABKAQSJgoHZW50cmllcxgDIAMoCzIVLmFwaS52MS5CbG9ja01hcEVudHJ5EiAKBXN0YXRzGAQgASgLMhEuYXBpLnYxLkZyYWdTdGF0cyJCChpHZXREZXZpY2VCbG9ja01hcHNSZXNwb25zZRIkCgRtYXBzGAEgAygLMhYuYXBpLnYxLkRldmljZUJsb2NrTWFwIkEKD0ZyZWVTcGFjZUJ1Y2tldBIQCghtYXhfc2l6ZRgBIAEoBBINCgVjb3VudBgCIAEoBBINCgVieXRlcxgDIAEoBCLZAQoTQmxvY2tHcm91cEZyZWVTcGFjZRIWCg5sb2dpY2FsX29mZnNldBgBIAEoBBIOCgZsZW5ndGgYAiABKAQSDAoEdHlwZRgDIAEoBBIPCgdwcm9maWxlGAQgASgEEhIKCmZyZWVfYnl0ZXMYBSABKAQSEQoJZnJlZV9ydW5zGAYgASgFEhQKDGxhcmdlc3RfZnJlZRgHIAEoBBIqCgloaXN0b2dyYW0YCCADKAsyFy5hcGkudjEuRnJlZVNwYWNlQnVja2V0EhIKCmZyYWdfc2NvcmUYCSABKAEiwwEKEEZyZWVTcGFjZVN1bW1hcnkSDAoEdHlwZRgBIAEoBBIUCgxibG9ja19ncm91cHMYAiABKAUSDgoGbGVuZ3RoGAMgASgEEhIKCmZyZWVfYnl0ZXMYBCABKAQSEQoJZnJlZV9ydW5zGAUgASgFEhQKDGxhcmdlc3RfZnJlZRgGIAEoBBIqCgloaXN0b2dyYW0YByADKAsyFy5hcGkudjEuRnJlZVNwYWNlQnVja2V0EhIKCmZyYWdfc2NvcmUYCCABKAEiSQoYR2V0RnJlZVNwYWNlU3RhdHNSZXF1ZXN0Eg8KB2ZzX3BhdGgYASABKAkSHAoUaW5jbHVkZV9ibG9ja19ncm91cHMYAiABKAgiiwEKGUdldEZyZWVTcGFjZVN0YXRzUmVzcG9uc2USDgoGc291cmNlGAEgASgJEisKCXN1bW1hcmllcxgCIAMoCzIYLmFwaS52MS5GcmVlU3BhY2VTdW1tYXJ5EjEKDGJsb2NrX2dyb3VwcxgDIAMoCzIbLmFwaS52MS5CbG9ja0dyb3VwRnJlZVNwYWNlIkMKGkdldENvbXByZXNzaW9uU3RhdHNSZXF1ZXN0EgwKBHBhdGgYASABKAkSFwoPb25lX2ZpbGVfc3lzdGVtGAIgASgIInEKEENvbXByZXNzaW9uVXNhZ2USEwoLY29tcHJlc3Npb24YASABKAkSEgoKZGlza19ieXRlcxgCIAEoBBIaChJ1bmNvbXByZXNzZWRfYnl0ZXMYAyABKAQSGAoQcmVmZXJlbmNlZF9ieXRlcxgEIAEoBCLoAQobR2V0Q29tcHJlc3Npb25TdGF0c1Jlc3BvbnNlEg0KBWZpbGVzGAEgASgFEg4KBmVycm9ycxgCIAEoBRISCgpkaXNrX2J5dGVzGAMgASgEEhoKEnVuY29tcHJlc3NlZF9ieXRlcxgEIAEoBBIYChByZWZlcmVuY2VkX2J5dGVzGAUgASgEEhkKEWNvbXByZXNzaW9uX3JhdGlvGAYgASgBEjAKDmJ5X2NvbXByZXNzaW9uGAcgAygLMhguYXBpLnYxLkNvbXByZXNzaW9uVXNhZ2USEwoLZHVyYXRpb25fbXMYCCABKAMijgEKEFNjYW5GaWxlc1JlcXVlc3QSDAoEcGF0aBgBIAEoCRIPCgd3b3JrZXJzGAIgASgFEhEKCW1heF9kZXB0aBgDIAEoBRIPCgdleGNsdWRlGAQgAygJEhcKD29uZV9maWxlX3N5c3RlbRgFIAEoCBILCgN0b3AYBiABKAUSEQoJcmVzdW1hYmxlGAcgASgIIowBChFTY2FuRmlsZXNQcm9ncmVzcxIVCg1maWxlc19zY2FubmVkGAEgASgFEhUKDWJ5dGVzX3NjYW5uZWQYAiABKAMSDgoGZXJyb3JzGAMgASgFEhQKDGN1cnJlbnRfcGF0aBgEIAEoCRISCgplbGFwc2VkX21zGAUgASgDEg8KB3Jlc3VtZWQYBiABKAgi0AEKD0ZpbGVGcmFnU3VtbWFyeRIMCgRwYXRoGAEgASgJEgwKBHNpemUYAiABKAMSFAoMZXh0ZW50X2NvdW50GAMgASgFEhUKDWlkZWFsX2V4dGVudHMYBCABKAUSCwoDZG9mGAUgASgBEhkKEWZyYWdtZW50YXRpb25fcGN0GAYgASgBEhgKEG91dF9vZl9vcmRlcl9wY3QYByABKAESGgoSY29tcHJlc3NlZF9leHRlbnRzGAggASgFEhYKDnNoYXJlZF9leHRlbnRzGAkgASgFIikKCURvRkJ1Y2tldBINCgVyYW5nZRgBIAEoCRINCgVmaWxlcxgCIAEoBSKwAwoNRmlsZUZyYWdTdGF0cxITCgt0b3RhbF9maWxlcxgBIAEoBRIVCg10b3RhbF9leHRlbnRzGAIgASgFEhMKC3RvdGFsX2J5dGVzGAMgASgDEhgKEGZyYWdtZW50ZWRfZmlsZXMYBCABKAUSDwoHYXZnX2RvZhgFIAEoARIUCgxhdmdfZnJhZ19wY3QYBiABKAESHAoUYXZnX291dF9vZl9vcmRlcl9wY3QYByABKAESDwoHbWF4X2RvZhgIIAEoARITCgttYXhfZXh0ZW50cxgJIAEoBRIYChBjb21wcmVzc2VkX2ZpbGVzGAogASgFEhoKEmNvbXByZXNzZWRfZXh0ZW50cxgLIAEoBRIWCg5pbmxpbmVfZXh0ZW50cxgMIAEoBRIWCg5zaGFyZWRfZXh0ZW50cxgNIAEoBRIZChF1bndyaXR0ZW5fZXh0ZW50cxgOIAEoBRIYChBjb21wcmVzc2VkX2J5dGVzGA8gASgDEhQKDHNoYXJlZF9ieXRlcxgQIAEoAxIoCg1kb2ZfaGlzdG9ncmFtGBEgAygLMhEuYXBpLnYxLkRvRkJ1Y2tldCIsCg1TY2FuRmlsZUVycm9yEgwKBHBhdGgYASABKAkSDQoFZXJyb3IYAiABKAkiwAEKD1NjYW5GaWxlc1Jlc3VsdBIkCgVzdGF0cxgBIAEoCzIVLmFwaS52MS5GaWxlRnJhZ1N0YXRzEiQKA3RvcBgCIAMoCzIXLmFwaS52MS5GaWxlRnJhZ1N1bW1hcnkSDgoGZXJyb3JzGAMgASgFEiwKDWVycm9yX3NhbXBsZXMYBCADKAsyFS5hcGkudjEuU2NhbkZpbGVFcnJvchIPCgdyZXN1bWVkGAUgASgIEhIKCmVsYXBzZWRfbXMYBiABKAMiZwoPU2NhbkZpbGVzVXBkYXRlEisKCHByb2dyZXNzGAEgASgLMhkuYXBpLnYxLlNjYW5GaWxlc1Byb2dyZXNzEicKBnJlc3VsdBgCIAEoCzIXLmFwaS52MS5TY2FuRmlsZXNSZXN1bHQiOQoXTGlzdEZyYWdNYXBTY2Fuc1JlcXVlc3QSDwoHZnNfcGF0aBgBIAEoCRINCgVsaW1pdBgCIAEoBSJ/Cg9GcmFnTWFwU2NhbkluZm8SCgoCaWQYASABKAMSDwoHZnNfdXVpZBgCIAEoCRISCgpnZW5lcmF0aW9uGAMgASgEEhIKCnNjYW5uZWRfYXQYBCABKAMSEgoKdG90YWxfc2l6ZRgFIAEoBBITCgtjaHVua19jb3VudBgGIAEoBSJCChhMaXN0RnJhZ01hcFNjYW5zUmVzcG9uc2USJgoFc2NhbnMYASADKAsyFy5hcGkudjEuRnJhZ01hcFNjYW5JbmZvIlAKE0RpZmZGcmFnTWFwc1JlcXVlc3QSDwoHZnNfcGF0aBgBIAEoCRIUCgxmcm9tX3NjYW5faWQYAiABKAMSEgoKdG9fc2Nhbl9pZBgDIAEoAyJ6CgtDaHVua0NoYW5nZRIMCgRraW5kGAEgASgJEhwKBWNodW5rGAIgASgLMg0uYXBpLnYxLkNodW5rEigKEHByZXZpb3VzX3N0cmlwZXMYAyADKAsyDi5hcGkudjEuU3RyaXBlEhUKDXByZXZpb3VzX3VzZWQYBCABKAQi6gEKCFR5cGVEaWZmEgwKBHR5cGUYASABKAQSGAoQYWxsb2NhdGVkX2NodW5rcxgCIAEoBRIXCg9hbGxvY2F0ZWRfYnl0ZXMYAyABKAQSFAoMZnJlZWRfY2h1bmtzGAQgASgFEhMKC2ZyZWVkX2J5dGVzGAUgASgEEhgKEHJlbG9jYXRlZF9jaHVua3MYBiABKAUSFwoPcmVsb2NhdGVkX2J5dGVzGAcgASgEEhYKDnJlc2l6ZWRfY2h1bmtzGAggASgFEhMKC3VzZWRfYmVmb3JlGAkgASgEEhIKCnVzZWRfYWZ0ZXIYCiABKAQiqwEKFERpZmZGcmFnTWFwc1Jlc3BvbnNlEiUKBGZyb20YASABKAsyFy5hcGkudjEuRnJhZ01hcFNjYW5JbmZvEiMKAnRvGAIgASgLMhcuYXBpLnYxLkZyYWdNYXBTY2FuSW5mbxIkCgdjaGFuZ2VzGAMgAygLMhMuYXBpLnYxLkNodW5rQ2hhbmdlEiEKB2J5X3R5cGUYBCADKAsyEC5hcGkudjEuVHlwZURpZmYimAEKGlJlc29sdmVMb2dpY2FsUmFuZ2VSZXF1ZXN0Eg8KB2ZzX3BhdGgYASABKAkSFQoNbG9naWNhbF9zdGFydBgCIAEoBBITCgtsb2dpY2FsX2VuZBgDIAEoBBIRCglkZXZpY2VfaWQYBCABKAQSFQoNZGV2aWNlX29mZnNldBgFIAEoBBITCgttYXhfZXh0ZW50cxgGIAEoBSJICgtFeHRlbnRPd25lchIMCgRyb290GAEgASgEEg0KBWlub2RlGAIgASgEEg4KBm9mZnNldBgDIAEoBBIMCgRwYXRoGAQgASgJIn0KDUxvZ2ljYWxFeHRlbnQSDwoHbG9naWNhbBgBIAEoBBIOCgZsZW5ndGgYAiABKAQSDAoEcmVmcxgDIAEoBBIjCgZvd25lcnMYBCADKAsyEy5hcGkudjEuRXh0ZW50T3duZXISGAoQb3duZXJzX3RydW5jYXRlZBgFIAEoCCJ1ChBMb2dpY2FsRmlsZVVzYWdlEgwKBHBhdGgYASABKAkSDAoEcm9vdBgCIAEoBBINCgVpbm9kZRgDIAEoBBIPCgdleHRlbnRzGAQgASgFEg0KBWJ5dGVzGAUgASgEEhYKDnNoYXJlZF9leHRlbnRzGAYgASgFIqcCChtSZXNvbHZlTG9naWNhbFJhbmdlUmVzcG9uc2USFQoNbG9naWNhbF9zdGFydBgBIAEoBBITCgtsb2dpY2FsX2VuZBgCIAEoBBIcCgVjaHVuaxgDIAEoCzINLmFwaS52MS5DaHVuaxIUCgxkYXRhX2V4dGVudHMYBCABKAUSEgoKZGF0YV9ieXRlcxgFIAEoBBIYChBtZXRhZGF0YV9leHRlbnRzGAYgASgFEhYKDm1ldGFkYXRhX2J5dGVzGAcgASgEEiYKB2V4dGVudHMYCCADKAsyFS5hcGkudjEuTG9naWNhbEV4dGVudBInCgVmaWxlcxgJIAMoCzIYLmFwaS52MS5Mb2dpY2FsRmlsZVVzYWdlEhEKCXRydW5jYXRlZBgKIAEoCDKwBwoORnJhZ01hcFNlcnZpY2USRQoKR2V0RnJhZ01hcBIZLmFwaS52MS5HZXRGcmFnTWFwUmVxdWVzdBoaLmFwaS52MS5HZXRGcmFnTWFwUmVzcG9uc2UiABJaChFHZXREZXZpY2VCbG9ja01hcBIgLmFwaS52MS5HZXREZXZpY2VCbG9ja01hcFJlcXVlc3QaIS5hcGkudjEuR2V0RGV2aWNlQmxvY2tNYXBSZXNwb25zZSIAEl0KEkdldERldmljZUJsb2NrTWFwcxIhLmFwaS52MS5HZXREZXZpY2VCbG9ja01hcHNSZXF1ZXN0GiIuYXBpLnYxLkdldERldmljZUJsb2NrTWFwc1Jlc3BvbnNlIgASRQoKR2V0SGVhdE1hcBIZLmFwaS52MS5HZXRIZWF0TWFwUmVxdWVzdBoaLmFwaS52MS5HZXRIZWF0TWFwUmVzcG9uc2UiABJLCgxHZXRGcmFnU3RhdHMSGy5hcGkudjEuR2V0RnJhZ1N0YXRzUmVxdWVzdBocLmFwaS52MS5HZXRGcmFnU3RhdHNSZXNwb25zZSIAEloKEUdldEZyZWVTcGFjZVN0YXRzEiAuYXBpLnYxLkdldEZyZWVTcGFjZVN0YXRzUmVxdWVzdBohLmFwaS52MS5HZXRGcmVlU3BhY2VTdGF0c1Jlc3BvbnNlIgASYAoTR2V0Q29tcHJlc3Npb25TdGF0cxIiLmFwaS52MS5HZXRDb21wcmVzc2lvblN0YXRzUmVxdWVzdBojLmFwaS52MS5HZXRDb21wcmVzc2lvblN0YXRzUmVzcG9uc2UiABJCCglTY2FuRmlsZXMSGC5hcGkudjEuU2NhbkZpbGVzUmVxdWVzdBoXLmFwaS52MS5TY2FuRmlsZXNVcGRhdGUiADABElcKEExpc3RGcmFnTWFwU2NhbnMSHy5hcGkudjEuTGlzdEZyYWdNYXBTY2Fuc1JlcXVlc3QaIC5hcGkudjEuTGlzdEZyYWdNYXBTY2Fuc1Jlc3BvbnNlIgASSwoMRGlmZkZyYWdNYXBzEhsuYXBpLnYxLkRpZmZGcmFnTWFwc1JlcXVlc3QaHC5hcGkudjEuRGlmZkZyYWdNYXBzUmVzcG9uc2UiABJgChNSZXNvbHZlTG9naWNhbFJhbmdlEiIuYXBpLnYxLlJlc29sdmVMb2dpY2FsUmFuZ2VSZXF1ZXN0GiMuYXBpLnYxLlJlc29sdmVMb2dpY2FsUmFuZ2VSZXNwb25zZSIAQoMBCgpjb20uYXBpLnYxQgxGcmFnbWFwUHJvdG9QAVouZ2l0aHViLmNvbS9lbGVlMTc2Ni9idHJmc2d1aWQvZ2VuL2FwaS92MTthcGl2MaICA0FYWKoCBkFwaS5WMcoCBkFwaVxWMeICEkFwaVxWMVxHUEJNZXRhZGF0YeoCB0FwaTo6VjFiBnByb3RvMw");
 
 /**
  * @generated from message api.v1.GetFragMapRequest
@@ -323,6 +323,34 @@ export type GetHeatMapRequest = Message<"api.v1.GetHeatMapRequest"> & {
    * @generated from field: int32 resolution = 3;
    */
   resolution: number;
+
+  /**
+   * Also scan free space inside block groups (slow without a free space tree)
+   *
+   * @generated from field: bool free_space = 4;
+   */
+  freeSpace: boolean;
+
+  /**
+   * "linear" (default), "row_major" or "hilbert". Hilbert rounds resolution
+   * up to a power of four.
+   *
+   * @generated from field: string layout = 5;
+   */
+  layout: string;
+
+  /**
+   * Zoom into [start_offset, end_offset) of the device. end_offset 0 means
+   * the end of the device.
+   *
+   * @generated from field: uint64 start_offset = 6;
+   */
+  startOffset: bigint;
+
+  /**
+   * @generated from field: uint64 end_offset = 7;
+   */
+  endOffset: bigint;
 };
 
 /**
@@ -387,6 +415,55 @@ export type HeatMapCell = Message<"api.v1.HeatMapCell"> & {
    * @generated from field: double utilization = 10;
    */
   utilization: number;
+
+  /**
+   * Only set when free_space was requested
+   *
+   * Free bytes inside the chunks in this cell
+   *
+   * @generated from field: uint64 chunk_free_bytes = 11;
+   */
+  chunkFreeBytes: bigint;
+
+  /**
+   * Weighted block group frag score (0-100)
+   *
+   * @generated from field: double free_space_frag_score = 12;
+   */
+  freeSpaceFragScore: number;
+
+  /**
+   * Profile with the most allocated bytes
+   *
+   * @generated from field: uint64 dominant_profile = 13;
+   */
+  dominantProfile: bigint;
+
+  /**
+   * Used bytes of the chunks here, spread evenly per chunk
+   *
+   * @generated from field: uint64 chunk_used_bytes = 14;
+   */
+  chunkUsedBytes: bigint;
+
+  /**
+   * chunk_used_bytes / allocated_bytes (0.0 to 1.0)
+   *
+   * @generated from field: double block_group_usage = 15;
+   */
+  blockGroupUsage: number;
+
+  /**
+   * Grid position for the requested layout
+   *
+   * @generated from field: int32 x = 16;
+   */
+  x: number;
+
+  /**
+   * @generated from field: int32 y = 17;
+   */
+  y: number;
 };
 
 /**
@@ -419,6 +496,51 @@ export type GetHeatMapResponse = Message<"api.v1.GetHeatMapResponse"> & {
    * @generated from field: repeated api.v1.HeatMapCell cells = 4;
    */
   cells: HeatMapCell[];
+
+  /**
+   * Only set when free_space was requested
+   *
+   * "free_space_tree" or "extent_tree"
+   *
+   * @generated from field: string free_space_source = 5;
+   */
+  freeSpaceSource: string;
+
+  /**
+   * Block groups with a stripe on this device
+   *
+   * @generated from field: repeated api.v1.BlockGroupFreeSpace block_groups = 6;
+   */
+  blockGroups: BlockGroupFreeSpace[];
+
+  /**
+   * @generated from field: string layout = 7;
+   */
+  layout: string;
+
+  /**
+   * Grid size in cells
+   *
+   * @generated from field: int32 width = 8;
+   */
+  width: number;
+
+  /**
+   * @generated from field: int32 height = 9;
+   */
+  height: number;
+
+  /**
+   * Range covered by the cells
+   *
+   * @generated from field: uint64 start_offset = 10;
+   */
+  startOffset: bigint;
+
+  /**
+   * @generated from field: uint64 end_offset = 11;
+   */
+  endOffset: bigint;
 };
 
 /**
@@ -620,58 +742,1298 @@ export const GetDeviceBlockMapsResponseSchema: GenMessage<GetDeviceBlockMapsResp
   messageDesc(file_api_v1_fragmap, 17);
 
 /**
- * @generated from service api.v1.FragMapService
+ * Free runs by size. Bucket i holds runs smaller than max_size; the last
+ * bucket has max_size 0 and holds everything larger.
+ *
+ * @generated from message api.v1.FreeSpaceBucket
  */
-export const FragMapService: GenService<{
+export type FreeSpaceBucket = Message<"api.v1.FreeSpaceBucket"> & {
   /**
-   * Get the complete fragmentation map for a filesystem
+   * @generated from field: uint64 max_size = 1;
+   */
+  maxSize: bigint;
+
+  /**
+   * @generated from field: uint64 count = 2;
+   */
+  count: bigint;
+
+  /**
+   * @generated from field: uint64 bytes = 3;
+   */
+  bytes: bigint;
+};
+
+/**
+ * Describes the message api.v1.FreeSpaceBucket.
+ * Use `create(FreeSpaceBucketSchema)` to create a new message.
+ */
+export const FreeSpaceBucketSchema: GenMessage<FreeSpaceBucket> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 18);
+
+/**
+ * @generated from message api.v1.BlockGroupFreeSpace
+ */
+export type BlockGroupFreeSpace = Message<"api.v1.BlockGroupFreeSpace"> & {
+  /**
+   * @generated from field: uint64 logical_offset = 1;
+   */
+  logicalOffset: bigint;
+
+  /**
+   * @generated from field: uint64 length = 2;
+   */
+  length: bigint;
+
+  /**
+   * @generated from field: uint64 type = 3;
+   */
+  type: bigint;
+
+  /**
+   * @generated from field: uint64 profile = 4;
+   */
+  profile: bigint;
+
+  /**
+   * @generated from field: uint64 free_bytes = 5;
+   */
+  freeBytes: bigint;
+
+  /**
+   * @generated from field: int32 free_runs = 6;
+   */
+  freeRuns: number;
+
+  /**
+   * @generated from field: uint64 largest_free = 7;
+   */
+  largestFree: bigint;
+
+  /**
+   * @generated from field: repeated api.v1.FreeSpaceBucket histogram = 8;
+   */
+  histogram: FreeSpaceBucket[];
+
+  /**
+   * Percent of free bytes in runs under 1MiB (0-100)
    *
-   * @generated from rpc api.v1.FragMapService.GetFragMap
+   * @generated from field: double frag_score = 9;
    */
-  getFragMap: {
-    methodKind: "unary";
-    input: typeof GetFragMapRequestSchema;
-    output: typeof GetFragMapResponseSchema;
-  },
+  fragScore: number;
+};
+
+/**
+ * Describes the message api.v1.BlockGroupFreeSpace.
+ * Use `create(BlockGroupFreeSpaceSchema)` to create a new message.
+ */
+export const BlockGroupFreeSpaceSchema: GenMessage<BlockGroupFreeSpace> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 19);
+
+/**
+ * @generated from message api.v1.FreeSpaceSummary
+ */
+export type FreeSpaceSummary = Message<"api.v1.FreeSpaceSummary"> & {
   /**
-   * Get block map data for a specific device
+   * @generated from field: uint64 type = 1;
+   */
+  type: bigint;
+
+  /**
+   * @generated from field: int32 block_groups = 2;
+   */
+  blockGroups: number;
+
+  /**
+   * @generated from field: uint64 length = 3;
+   */
+  length: bigint;
+
+  /**
+   * @generated from field: uint64 free_bytes = 4;
+   */
+  freeBytes: bigint;
+
+  /**
+   * @generated from field: int32 free_runs = 5;
+   */
+  freeRuns: number;
+
+  /**
+   * @generated from field: uint64 largest_free = 6;
+   */
+  largestFree: bigint;
+
+  /**
+   * @generated from field: repeated api.v1.FreeSpaceBucket histogram = 7;
+   */
+  histogram: FreeSpaceBucket[];
+
+  /**
+   * @generated from field: double frag_score = 8;
+   */
+  fragScore: number;
+};
+
+/**
+ * Describes the message api.v1.FreeSpaceSummary.
+ * Use `create(FreeSpaceSummarySchema)` to create a new message.
+ */
+export const FreeSpaceSummarySchema: GenMessage<FreeSpaceSummary> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 20);
+
+/**
+ * @generated from message api.v1.GetFreeSpaceStatsRequest
+ */
+export type GetFreeSpaceStatsRequest = Message<"api.v1.GetFreeSpaceStatsRequest"> & {
+  /**
+   * @generated from field: string fs_path = 1;
+   */
+  fsPath: string;
+
+  /**
+   * Also return every block group, not just the summaries
    *
-   * @generated from rpc api.v1.FragMapService.GetDeviceBlockMap
+   * @generated from field: bool include_block_groups = 2;
    */
-  getDeviceBlockMap: {
-    methodKind: "unary";
-    input: typeof GetDeviceBlockMapRequestSchema;
-    output: typeof GetDeviceBlockMapResponseSchema;
-  },
+  includeBlockGroups: boolean;
+};
+
+/**
+ * Describes the message api.v1.GetFreeSpaceStatsRequest.
+ * Use `create(GetFreeSpaceStatsRequestSchema)` to create a new message.
+ */
+export const GetFreeSpaceStatsRequestSchema: GenMessage<GetFreeSpaceStatsRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 21);
+
+/**
+ * @generated from message api.v1.GetFreeSpaceStatsResponse
+ */
+export type GetFreeSpaceStatsResponse = Message<"api.v1.GetFreeSpaceStatsResponse"> & {
   /**
-   * Get block maps for multiple devices in parallel
+   * "free_space_tree" or "extent_tree"
    *
-   * @generated from rpc api.v1.FragMapService.GetDeviceBlockMaps
+   * @generated from field: string source = 1;
    */
-  getDeviceBlockMaps: {
-    methodKind: "unary";
-    input: typeof GetDeviceBlockMapsRequestSchema;
-    output: typeof GetDeviceBlockMapsResponseSchema;
-  },
+  source: string;
+
   /**
-   * Get heat map data for visualization
+   * Data, metadata, system
    *
-   * @generated from rpc api.v1.FragMapService.GetHeatMap
+   * @generated from field: repeated api.v1.FreeSpaceSummary summaries = 2;
    */
-  getHeatMap: {
-    methodKind: "unary";
-    input: typeof GetHeatMapRequestSchema;
-    output: typeof GetHeatMapResponseSchema;
-  },
+  summaries: FreeSpaceSummary[];
+
   /**
-   * Get fragmentation statistics
+   * @generated from field: repeated api.v1.BlockGroupFreeSpace block_groups = 3;
+   */
+  blockGroups: BlockGroupFreeSpace[];
+};
+
+/**
+ * Describes the message api.v1.GetFreeSpaceStatsResponse.
+ * Use `create(GetFreeSpaceStatsResponseSchema)` to create a new message.
+ */
+export const GetFreeSpaceStatsResponseSchema: GenMessage<GetFreeSpaceStatsResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 22);
+
+/**
+ * @generated from message api.v1.GetCompressionStatsRequest
+ */
+export type GetCompressionStatsRequest = Message<"api.v1.GetCompressionStatsRequest"> & {
+  /**
+   * @generated from field: string path = 1;
+   */
+  path: string;
+
+  /**
+   * Don't descend into other filesystems or subvolumes
    *
-   * @generated from rpc api.v1.FragMapService.GetFragStats
+   * @generated from field: bool one_file_system = 2;
    */
-  getFragStats: {
-    methodKind: "unary";
-    input: typeof GetFragStatsRequestSchema;
-    output: typeof GetFragStatsResponseSchema;
+  oneFileSystem: boolean;
+};
+
+/**
+ * Describes the message api.v1.GetCompressionStatsRequest.
+ * Use `create(GetCompressionStatsRequestSchema)` to create a new message.
+ */
+export const GetCompressionStatsRequestSchema: GenMessage<GetCompressionStatsRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 23);
+
+/**
+ * @generated from message api.v1.CompressionUsage
+ */
+export type CompressionUsage = Message<"api.v1.CompressionUsage"> & {
+  /**
+   * "none", "zlib", "lzo" or "zstd"
+   *
+   * @generated from field: string compression = 1;
+   */
+  compression: string;
+
+  /**
+   * On-disk bytes, shared extents counted once
+   *
+   * @generated from field: uint64 disk_bytes = 2;
+   */
+  diskBytes: bigint;
+
+  /**
+   * @generated from field: uint64 uncompressed_bytes = 3;
+   */
+  uncompressedBytes: bigint;
+
+  /**
+   * File bytes pointing at the extents
+   *
+   * @generated from field: uint64 referenced_bytes = 4;
+   */
+  referencedBytes: bigint;
+};
+
+/**
+ * Describes the message api.v1.CompressionUsage.
+ * Use `create(CompressionUsageSchema)` to create a new message.
+ */
+export const CompressionUsageSchema: GenMessage<CompressionUsage> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 24);
+
+/**
+ * @generated from message api.v1.GetCompressionStatsResponse
+ */
+export type GetCompressionStatsResponse = Message<"api.v1.GetCompressionStatsResponse"> & {
+  /**
+   * @generated from field: int32 files = 1;
+   */
+  files: number;
+
+  /**
+   * Files that could not be read
+   *
+   * @generated from field: int32 errors = 2;
+   */
+  errors: number;
+
+  /**
+   * @generated from field: uint64 disk_bytes = 3;
+   */
+  diskBytes: bigint;
+
+  /**
+   * @generated from field: uint64 uncompressed_bytes = 4;
+   */
+  uncompressedBytes: bigint;
+
+  /**
+   * @generated from field: uint64 referenced_bytes = 5;
+   */
+  referencedBytes: bigint;
+
+  /**
+   * Uncompressed/on-disk bytes of compressed extents
+   *
+   * @generated from field: double compression_ratio = 6;
+   */
+  compressionRatio: number;
+
+  /**
+   * @generated from field: repeated api.v1.CompressionUsage by_compression = 7;
+   */
+  byCompression: CompressionUsage[];
+
+  /**
+   * @generated from field: int64 duration_ms = 8;
+   */
+  durationMs: bigint;
+};
+
+/**
+ * Describes the message api.v1.GetCompressionStatsResponse.
+ * Use `create(GetCompressionStatsResponseSchema)` to create a new message.
+ */
+export const GetCompressionStatsResponseSchema: GenMessage<GetCompressionStatsResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 25);
+
+/**
+ * @generated from message api.v1.ScanFilesRequest
+ */
+export type ScanFilesRequest = Message<"api.v1.ScanFilesRequest"> & {
+  /**
+   * @generated from field: string path = 1;
+   */
+  path: string;
+
+  /**
+   * 0 = number of CPUs
+   *
+   * @generated from field: int32 workers = 2;
+   */
+  workers: number;
+
+  /**
+   * 0 = unlimited, 1 = only files directly in path
+   *
+   * @generated from field: int32 max_depth = 3;
+   */
+  maxDepth: number;
+
+  /**
+   * Globs matched against base names and relative paths
+   *
+   * @generated from field: repeated string exclude = 4;
+   */
+  exclude: string[];
+
+  /**
+   * Don't descend into other filesystems or subvolumes
+   *
+   * @generated from field: bool one_file_system = 5;
+   */
+  oneFileSystem: boolean;
+
+  /**
+   * Most fragmented files to return (0 = 20)
+   *
+   * @generated from field: int32 top = 6;
+   */
+  top: number;
+
+  /**
+   * Checkpoint on the server so an interrupted scan of the same path and
+   * options picks up where it left off
+   *
+   * @generated from field: bool resumable = 7;
+   */
+  resumable: boolean;
+};
+
+/**
+ * Describes the message api.v1.ScanFilesRequest.
+ * Use `create(ScanFilesRequestSchema)` to create a new message.
+ */
+export const ScanFilesRequestSchema: GenMessage<ScanFilesRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 26);
+
+/**
+ * @generated from message api.v1.ScanFilesProgress
+ */
+export type ScanFilesProgress = Message<"api.v1.ScanFilesProgress"> & {
+  /**
+   * @generated from field: int32 files_scanned = 1;
+   */
+  filesScanned: number;
+
+  /**
+   * @generated from field: int64 bytes_scanned = 2;
+   */
+  bytesScanned: bigint;
+
+  /**
+   * @generated from field: int32 errors = 3;
+   */
+  errors: number;
+
+  /**
+   * @generated from field: string current_path = 4;
+   */
+  currentPath: string;
+
+  /**
+   * @generated from field: int64 elapsed_ms = 5;
+   */
+  elapsedMs: bigint;
+
+  /**
+   * @generated from field: bool resumed = 6;
+   */
+  resumed: boolean;
+};
+
+/**
+ * Describes the message api.v1.ScanFilesProgress.
+ * Use `create(ScanFilesProgressSchema)` to create a new message.
+ */
+export const ScanFilesProgressSchema: GenMessage<ScanFilesProgress> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 27);
+
+/**
+ * @generated from message api.v1.FileFragSummary
+ */
+export type FileFragSummary = Message<"api.v1.FileFragSummary"> & {
+  /**
+   * @generated from field: string path = 1;
+   */
+  path: string;
+
+  /**
+   * @generated from field: int64 size = 2;
+   */
+  size: bigint;
+
+  /**
+   * @generated from field: int32 extent_count = 3;
+   */
+  extentCount: number;
+
+  /**
+   * @generated from field: int32 ideal_extents = 4;
+   */
+  idealExtents: number;
+
+  /**
+   * @generated from field: double dof = 5;
+   */
+  dof: number;
+
+  /**
+   * @generated from field: double fragmentation_pct = 6;
+   */
+  fragmentationPct: number;
+
+  /**
+   * @generated from field: double out_of_order_pct = 7;
+   */
+  outOfOrderPct: number;
+
+  /**
+   * @generated from field: int32 compressed_extents = 8;
+   */
+  compressedExtents: number;
+
+  /**
+   * @generated from field: int32 shared_extents = 9;
+   */
+  sharedExtents: number;
+};
+
+/**
+ * Describes the message api.v1.FileFragSummary.
+ * Use `create(FileFragSummarySchema)` to create a new message.
+ */
+export const FileFragSummarySchema: GenMessage<FileFragSummary> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 28);
+
+/**
+ * @generated from message api.v1.DoFBucket
+ */
+export type DoFBucket = Message<"api.v1.DoFBucket"> & {
+  /**
+   * "1", "1-2", "2-5", "5-10", "10+"
+   *
+   * @generated from field: string range = 1;
+   */
+  range: string;
+
+  /**
+   * @generated from field: int32 files = 2;
+   */
+  files: number;
+};
+
+/**
+ * Describes the message api.v1.DoFBucket.
+ * Use `create(DoFBucketSchema)` to create a new message.
+ */
+export const DoFBucketSchema: GenMessage<DoFBucket> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 29);
+
+/**
+ * @generated from message api.v1.FileFragStats
+ */
+export type FileFragStats = Message<"api.v1.FileFragStats"> & {
+  /**
+   * @generated from field: int32 total_files = 1;
+   */
+  totalFiles: number;
+
+  /**
+   * @generated from field: int32 total_extents = 2;
+   */
+  totalExtents: number;
+
+  /**
+   * @generated from field: int64 total_bytes = 3;
+   */
+  totalBytes: bigint;
+
+  /**
+   * @generated from field: int32 fragmented_files = 4;
+   */
+  fragmentedFiles: number;
+
+  /**
+   * @generated from field: double avg_dof = 5;
+   */
+  avgDof: number;
+
+  /**
+   * @generated from field: double avg_frag_pct = 6;
+   */
+  avgFragPct: number;
+
+  /**
+   * @generated from field: double avg_out_of_order_pct = 7;
+   */
+  avgOutOfOrderPct: number;
+
+  /**
+   * @generated from field: double max_dof = 8;
+   */
+  maxDof: number;
+
+  /**
+   * @generated from field: int32 max_extents = 9;
+   */
+  maxExtents: number;
+
+  /**
+   * @generated from field: int32 compressed_files = 10;
+   */
+  compressedFiles: number;
+
+  /**
+   * @generated from field: int32 compressed_extents = 11;
+   */
+  compressedExtents: number;
+
+  /**
+   * @generated from field: int32 inline_extents = 12;
+   */
+  inlineExtents: number;
+
+  /**
+   * @generated from field: int32 shared_extents = 13;
+   */
+  sharedExtents: number;
+
+  /**
+   * @generated from field: int32 unwritten_extents = 14;
+   */
+  unwrittenExtents: number;
+
+  /**
+   * @generated from field: int64 compressed_bytes = 15;
+   */
+  compressedBytes: bigint;
+
+  /**
+   * @generated from field: int64 shared_bytes = 16;
+   */
+  sharedBytes: bigint;
+
+  /**
+   * @generated from field: repeated api.v1.DoFBucket dof_histogram = 17;
+   */
+  dofHistogram: DoFBucket[];
+};
+
+/**
+ * Describes the message api.v1.FileFragStats.
+ * Use `create(FileFragStatsSchema)` to create a new message.
+ */
+export const FileFragStatsSchema: GenMessage<FileFragStats> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 30);
+
+/**
+ * @generated from message api.v1.ScanFileError
+ */
+export type ScanFileError = Message<"api.v1.ScanFileError"> & {
+  /**
+   * @generated from field: string path = 1;
+   */
+  path: string;
+
+  /**
+   * @generated from field: string error = 2;
+   */
+  error: string;
+};
+
+/**
+ * Describes the message api.v1.ScanFileError.
+ * Use `create(ScanFileErrorSchema)` to create a new message.
+ */
+export const ScanFileErrorSchema: GenMessage<ScanFileError> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 31);
+
+/**
+ * @generated from message api.v1.ScanFilesResult
+ */
+export type ScanFilesResult = Message<"api.v1.ScanFilesResult"> & {
+  /**
+   * @generated from field: api.v1.FileFragStats stats = 1;
+   */
+  stats?: FileFragStats;
+
+  /**
+   * Most fragmented first
+   *
+   * @generated from field: repeated api.v1.FileFragSummary top = 2;
+   */
+  top: FileFragSummary[];
+
+  /**
+   * @generated from field: int32 errors = 3;
+   */
+  errors: number;
+
+  /**
+   * @generated from field: repeated api.v1.ScanFileError error_samples = 4;
+   */
+  errorSamples: ScanFileError[];
+
+  /**
+   * @generated from field: bool resumed = 5;
+   */
+  resumed: boolean;
+
+  /**
+   * @generated from field: int64 elapsed_ms = 6;
+   */
+  elapsedMs: bigint;
+};
+
+/**
+ * Describes the message api.v1.ScanFilesResult.
+ * Use `create(ScanFilesResultSchema)` to create a new message.
+ */
+export const ScanFilesResultSchema: GenMessage<ScanFilesResult> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 32);
+
+/**
+ * Progress updates are sent while scanning; the last message carries the result
+ *
+ * @generated from message api.v1.ScanFilesUpdate
+ */
+export type ScanFilesUpdate = Message<"api.v1.ScanFilesUpdate"> & {
+  /**
+   * @generated from field: api.v1.ScanFilesProgress progress = 1;
+   */
+  progress?: ScanFilesProgress;
+
+  /**
+   * @generated from field: api.v1.ScanFilesResult result = 2;
+   */
+  result?: ScanFilesResult;
+};
+
+/**
+ * Describes the message api.v1.ScanFilesUpdate.
+ * Use `create(ScanFilesUpdateSchema)` to create a new message.
+ */
+export const ScanFilesUpdateSchema: GenMessage<ScanFilesUpdate> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 33);
+
+/**
+ * @generated from message api.v1.ListFragMapScansRequest
+ */
+export type ListFragMapScansRequest = Message<"api.v1.ListFragMapScansRequest"> & {
+  /**
+   * @generated from field: string fs_path = 1;
+   */
+  fsPath: string;
+
+  /**
+   * 0 = all
+   *
+   * @generated from field: int32 limit = 2;
+   */
+  limit: number;
+};
+
+/**
+ * Describes the message api.v1.ListFragMapScansRequest.
+ * Use `create(ListFragMapScansRequestSchema)` to create a new message.
+ */
+export const ListFragMapScansRequestSchema: GenMessage<ListFragMapScansRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 34);
+
+/**
+ * @generated from message api.v1.FragMapScanInfo
+ */
+export type FragMapScanInfo = Message<"api.v1.FragMapScanInfo"> & {
+  /**
+   * @generated from field: int64 id = 1;
+   */
+  id: bigint;
+
+  /**
+   * @generated from field: string fs_uuid = 2;
+   */
+  fsUuid: string;
+
+  /**
+   * @generated from field: uint64 generation = 3;
+   */
+  generation: bigint;
+
+  /**
+   * @generated from field: int64 scanned_at = 4;
+   */
+  scannedAt: bigint;
+
+  /**
+   * @generated from field: uint64 total_size = 5;
+   */
+  totalSize: bigint;
+
+  /**
+   * @generated from field: int32 chunk_count = 6;
+   */
+  chunkCount: number;
+};
+
+/**
+ * Describes the message api.v1.FragMapScanInfo.
+ * Use `create(FragMapScanInfoSchema)` to create a new message.
+ */
+export const FragMapScanInfoSchema: GenMessage<FragMapScanInfo> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 35);
+
+/**
+ * @generated from message api.v1.ListFragMapScansResponse
+ */
+export type ListFragMapScansResponse = Message<"api.v1.ListFragMapScansResponse"> & {
+  /**
+   * Newest first
+   *
+   * @generated from field: repeated api.v1.FragMapScanInfo scans = 1;
+   */
+  scans: FragMapScanInfo[];
+};
+
+/**
+ * Describes the message api.v1.ListFragMapScansResponse.
+ * Use `create(ListFragMapScansResponseSchema)` to create a new message.
+ */
+export const ListFragMapScansResponseSchema: GenMessage<ListFragMapScansResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 36);
+
+/**
+ * @generated from message api.v1.DiffFragMapsRequest
+ */
+export type DiffFragMapsRequest = Message<"api.v1.DiffFragMapsRequest"> & {
+  /**
+   * @generated from field: string fs_path = 1;
+   */
+  fsPath: string;
+
+  /**
+   * 0 = the latest saved scan before to_scan_id
+   *
+   * @generated from field: int64 from_scan_id = 2;
+   */
+  fromScanId: bigint;
+
+  /**
+   * 0 = the current state of the filesystem
+   *
+   * @generated from field: int64 to_scan_id = 3;
+   */
+  toScanId: bigint;
+};
+
+/**
+ * Describes the message api.v1.DiffFragMapsRequest.
+ * Use `create(DiffFragMapsRequestSchema)` to create a new message.
+ */
+export const DiffFragMapsRequestSchema: GenMessage<DiffFragMapsRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 37);
+
+/**
+ * @generated from message api.v1.ChunkChange
+ */
+export type ChunkChange = Message<"api.v1.ChunkChange"> & {
+  /**
+   * "allocated", "freed" or "relocated"
+   *
+   * @generated from field: string kind = 1;
+   */
+  kind: string;
+
+  /**
+   * As in the newer scan (the older one for freed chunks)
+   *
+   * @generated from field: api.v1.Chunk chunk = 2;
+   */
+  chunk?: Chunk;
+
+  /**
+   * Relocated chunks only
+   *
+   * @generated from field: repeated api.v1.Stripe previous_stripes = 3;
+   */
+  previousStripes: Stripe[];
+
+  /**
+   * @generated from field: uint64 previous_used = 4;
+   */
+  previousUsed: bigint;
+};
+
+/**
+ * Describes the message api.v1.ChunkChange.
+ * Use `create(ChunkChangeSchema)` to create a new message.
+ */
+export const ChunkChangeSchema: GenMessage<ChunkChange> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 38);
+
+/**
+ * @generated from message api.v1.TypeDiff
+ */
+export type TypeDiff = Message<"api.v1.TypeDiff"> & {
+  /**
+   * data=1, system=2, metadata=4
+   *
+   * @generated from field: uint64 type = 1;
+   */
+  type: bigint;
+
+  /**
+   * @generated from field: int32 allocated_chunks = 2;
+   */
+  allocatedChunks: number;
+
+  /**
+   * @generated from field: uint64 allocated_bytes = 3;
+   */
+  allocatedBytes: bigint;
+
+  /**
+   * @generated from field: int32 freed_chunks = 4;
+   */
+  freedChunks: number;
+
+  /**
+   * @generated from field: uint64 freed_bytes = 5;
+   */
+  freedBytes: bigint;
+
+  /**
+   * @generated from field: int32 relocated_chunks = 6;
+   */
+  relocatedChunks: number;
+
+  /**
+   * @generated from field: uint64 relocated_bytes = 7;
+   */
+  relocatedBytes: bigint;
+
+  /**
+   * Chunks in both scans whose used bytes changed
+   *
+   * @generated from field: int32 resized_chunks = 8;
+   */
+  resizedChunks: number;
+
+  /**
+   * @generated from field: uint64 used_before = 9;
+   */
+  usedBefore: bigint;
+
+  /**
+   * @generated from field: uint64 used_after = 10;
+   */
+  usedAfter: bigint;
+};
+
+/**
+ * Describes the message api.v1.TypeDiff.
+ * Use `create(TypeDiffSchema)` to create a new message.
+ */
+export const TypeDiffSchema: GenMessage<TypeDiff> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 39);
+
+/**
+ * @generated from message api.v1.DiffFragMapsResponse
+ */
+export type DiffFragMapsResponse = Message<"api.v1.DiffFragMapsResponse"> & {
+  /**
+   * @generated from field: api.v1.FragMapScanInfo from = 1;
+   */
+  from?: FragMapScanInfo;
+
+  /**
+   * id 0 when diffing against the current state
+   *
+   * @generated from field: api.v1.FragMapScanInfo to = 2;
+   */
+  to?: FragMapScanInfo;
+
+  /**
+   * Sorted by logical address
+   *
+   * @generated from field: repeated api.v1.ChunkChange changes = 3;
+   */
+  changes: ChunkChange[];
+
+  /**
+   * @generated from field: repeated api.v1.TypeDiff by_type = 4;
+   */
+  byType: TypeDiff[];
+};
+
+/**
+ * Describes the message api.v1.DiffFragMapsResponse.
+ * Use `create(DiffFragMapsResponseSchema)` to create a new message.
+ */
+export const DiffFragMapsResponseSchema: GenMessage<DiffFragMapsResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 40);
+
+/**
+ * @generated from message api.v1.ResolveLogicalRangeRequest
+ */
+export type ResolveLogicalRangeRequest = Message<"api.v1.ResolveLogicalRangeRequest"> & {
+  /**
+   * @generated from field: string fs_path = 1;
+   */
+  fsPath: string;
+
+  /**
+   * A logical range. With logical_end 0, the chunk containing logical_start.
+   *
+   * @generated from field: uint64 logical_start = 2;
+   */
+  logicalStart: bigint;
+
+  /**
+   * @generated from field: uint64 logical_end = 3;
+   */
+  logicalEnd: bigint;
+
+  /**
+   * Or, when device_id is set, the chunk stored at this device offset
+   *
+   * @generated from field: uint64 device_id = 4;
+   */
+  deviceId: bigint;
+
+  /**
+   * @generated from field: uint64 device_offset = 5;
+   */
+  deviceOffset: bigint;
+
+  /**
+   * Data extents to resolve to files (default 1000)
+   *
+   * @generated from field: int32 max_extents = 6;
+   */
+  maxExtents: number;
+};
+
+/**
+ * Describes the message api.v1.ResolveLogicalRangeRequest.
+ * Use `create(ResolveLogicalRangeRequestSchema)` to create a new message.
+ */
+export const ResolveLogicalRangeRequestSchema: GenMessage<ResolveLogicalRangeRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 41);
+
+/**
+ * @generated from message api.v1.ExtentOwner
+ */
+export type ExtentOwner = Message<"api.v1.ExtentOwner"> & {
+  /**
+   * Subvolume ID
+   *
+   * @generated from field: uint64 root = 1;
+   */
+  root: bigint;
+
+  /**
+   * @generated from field: uint64 inode = 2;
+   */
+  inode: bigint;
+
+  /**
+   * File offset where the reference starts
+   *
+   * @generated from field: uint64 offset = 3;
+   */
+  offset: bigint;
+
+  /**
+   * Relative to the top-level subvolume, empty if unresolved
+   *
+   * @generated from field: string path = 4;
+   */
+  path: string;
+};
+
+/**
+ * Describes the message api.v1.ExtentOwner.
+ * Use `create(ExtentOwnerSchema)` to create a new message.
+ */
+export const ExtentOwnerSchema: GenMessage<ExtentOwner> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 42);
+
+/**
+ * @generated from message api.v1.LogicalExtent
+ */
+export type LogicalExtent = Message<"api.v1.LogicalExtent"> & {
+  /**
+   * @generated from field: uint64 logical = 1;
+   */
+  logical: bigint;
+
+  /**
+   * @generated from field: uint64 length = 2;
+   */
+  length: bigint;
+
+  /**
+   * @generated from field: uint64 refs = 3;
+   */
+  refs: bigint;
+
+  /**
+   * @generated from field: repeated api.v1.ExtentOwner owners = 4;
+   */
+  owners: ExtentOwner[];
+
+  /**
+   * @generated from field: bool owners_truncated = 5;
+   */
+  ownersTruncated: boolean;
+};
+
+/**
+ * Describes the message api.v1.LogicalExtent.
+ * Use `create(LogicalExtentSchema)` to create a new message.
+ */
+export const LogicalExtentSchema: GenMessage<LogicalExtent> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 43);
+
+/**
+ * @generated from message api.v1.LogicalFileUsage
+ */
+export type LogicalFileUsage = Message<"api.v1.LogicalFileUsage"> & {
+  /**
+   * @generated from field: string path = 1;
+   */
+  path: string;
+
+  /**
+   * @generated from field: uint64 root = 2;
+   */
+  root: bigint;
+
+  /**
+   * @generated from field: uint64 inode = 3;
+   */
+  inode: bigint;
+
+  /**
+   * @generated from field: int32 extents = 4;
+   */
+  extents: number;
+
+  /**
+   * Length of the extents it references
+   *
+   * @generated from field: uint64 bytes = 5;
+   */
+  bytes: bigint;
+
+  /**
+   * Extents also referenced by other files or snapshots
+   *
+   * @generated from field: int32 shared_extents = 6;
+   */
+  sharedExtents: number;
+};
+
+/**
+ * Describes the message api.v1.LogicalFileUsage.
+ * Use `create(LogicalFileUsageSchema)` to create a new message.
+ */
+export const LogicalFileUsageSchema: GenMessage<LogicalFileUsage> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 44);
+
+/**
+ * @generated from message api.v1.ResolveLogicalRangeResponse
+ */
+export type ResolveLogicalRangeResponse = Message<"api.v1.ResolveLogicalRangeResponse"> & {
+  /**
+   * @generated from field: uint64 logical_start = 1;
+   */
+  logicalStart: bigint;
+
+  /**
+   * @generated from field: uint64 logical_end = 2;
+   */
+  logicalEnd: bigint;
+
+  /**
+   * Set when a chunk was looked up
+   *
+   * @generated from field: api.v1.Chunk chunk = 3;
+   */
+  chunk?: Chunk;
+
+  /**
+   * @generated from field: int32 data_extents = 4;
+   */
+  dataExtents: number;
+
+  /**
+   * @generated from field: uint64 data_bytes = 5;
+   */
+  dataBytes: bigint;
+
+  /**
+   * @generated from field: int32 metadata_extents = 6;
+   */
+  metadataExtents: number;
+
+  /**
+   * @generated from field: uint64 metadata_bytes = 7;
+   */
+  metadataBytes: bigint;
+
+  /**
+   * Address order
+   *
+   * @generated from field: repeated api.v1.LogicalExtent extents = 8;
+   */
+  extents: LogicalExtent[];
+
+  /**
+   * Most bytes first
+   *
+   * @generated from field: repeated api.v1.LogicalFileUsage files = 9;
+   */
+  files: LogicalFileUsage[];
+
+  /**
+   * Only max_extents data extents were resolved
+   *
+   * @generated from field: bool truncated = 10;
+   */
+  truncated: boolean;
+};
+
+/**
+ * Describes the message api.v1.ResolveLogicalRangeResponse.
+ * Use `create(ResolveLogicalRangeResponseSchema)` to create a new message.
+ */
+export const ResolveLogicalRangeResponseSchema: GenMessage<ResolveLogicalRangeResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_fragmap, 45);
+
+/**
+ * @generated from service api.v1.FragMapService
+ */
+export const FragMapService: GenService<{
+  /**
+   * Get the complete fragmentation map for a filesystem
+   *
+   * @generated from rpc api.v1.FragMapService.GetFragMap
+   */
+  getFragMap: {
+    methodKind: "unary";
+    input: typeof GetFragMapRequestSchema;
+    output: typeof GetFragMapResponseSchema;
+  },
+  /**
+   * Get block map data for a specific device
+   *
+   * @generated from rpc api.v1.FragMapService.GetDeviceBlockMap
+   */
+  getDeviceBlockMap: {
+    methodKind: "unary";
+    input: typeof GetDeviceBlockMapRequestSchema;
+    output: typeof GetDeviceBlockMapResponseSchema;
+  },
+  /**
+   * Get block maps for multiple devices in parallel
+   *
+   * @generated from rpc api.v1.FragMapService.GetDeviceBlockMaps
+   */
+  getDeviceBlockMaps: {
+    methodKind: "unary";
+    input: typeof GetDeviceBlockMapsRequestSchema;
+    output: typeof GetDeviceBlockMapsResponseSchema;
+  },
+  /**
+   * Get heat map data for visualization
+   *
+   * @generated from rpc api.v1.FragMapService.GetHeatMap
+   */
+  getHeatMap: {
+    methodKind: "unary";
+    input: typeof GetHeatMapRequestSchema;
+    output: typeof GetHeatMapResponseSchema;
+  },
+  /**
+   * Get fragmentation statistics
+   *
+   * @generated from rpc api.v1.FragMapService.GetFragStats
+   */
+  getFragStats: {
+    methodKind: "unary";
+    input: typeof GetFragStatsRequestSchema;
+    output: typeof GetFragStatsResponseSchema;
+  },
+  /**
+   * Get free space fragmentation inside block groups
+   *
+   * @generated from rpc api.v1.FragMapService.GetFreeSpaceStats
+   */
+  getFreeSpaceStats: {
+    methodKind: "unary";
+    input: typeof GetFreeSpaceStatsRequestSchema;
+    output: typeof GetFreeSpaceStatsResponseSchema;
+  },
+  /**
+   * Get disk usage per compression algorithm under a path (like compsize)
+   *
+   * @generated from rpc api.v1.FragMapService.GetCompressionStats
+   */
+  getCompressionStats: {
+    methodKind: "unary";
+    input: typeof GetCompressionStatsRequestSchema;
+    output: typeof GetCompressionStatsResponseSchema;
+  },
+  /**
+   * Scan file fragmentation under a directory, streaming progress and then the result
+   *
+   * @generated from rpc api.v1.FragMapService.ScanFiles
+   */
+  scanFiles: {
+    methodKind: "server_streaming";
+    input: typeof ScanFilesRequestSchema;
+    output: typeof ScanFilesUpdateSchema;
+  },
+  /**
+   * List saved scans of a filesystem. A scan is saved whenever the chunk layout changes.
+   *
+   * @generated from rpc api.v1.FragMapService.ListFragMapScans
+   */
+  listFragMapScans: {
+    methodKind: "unary";
+    input: typeof ListFragMapScansRequestSchema;
+    output: typeof ListFragMapScansResponseSchema;
+  },
+  /**
+   * Show which chunks were allocated, freed or relocated between two scans
+   *
+   * @generated from rpc api.v1.FragMapService.DiffFragMaps
+   */
+  diffFragMaps: {
+    methodKind: "unary";
+    input: typeof DiffFragMapsRequestSchema;
+    output: typeof DiffFragMapsResponseSchema;
+  },
+  /**
+   * List the extents in a logical range or chunk and the files that reference
+   * them, i.e. what a balance of that chunk would move
+   *
+   * @generated from rpc api.v1.FragMapService.ResolveLogicalRange
+   */
+  resolveLogicalRange: {
+    methodKind: "unary";
+    input: typeof ResolveLogicalRangeRequestSchema;
+    output: typeof ResolveLogicalRangeResponseSchema;
   },
 }> = /*@__PURE__*/
   serviceDesc(file_api_v1_fragmap, 0);
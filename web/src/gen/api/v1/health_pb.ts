@@ -10,7 +10,7 @@ import type { Message } from "@bufbuild/protobuf";
  * Describes the file api/v1/health.proto.
  */
 export const file_api_v1_health: GenFile = /*@__PURE__*/
-  fileDesc("ChNhcGkvdjEvaGVhbHRoLnByb3RvEgZhcGkudjEiJQoSSGVhbHRoQ2hlY2tSZXF1ZXN0Eg8KB3NlcnZpY2UYASABKAkijwEKE0hlYWx0aENoZWNrUmVzcG9uc2USMgoGc3RhdHVzGAEgASgOMiIuYXBpLnYxLkhlYWx0aENoZWNrUmVzcG9uc2UuU3RhdHVzEg8KB21lc3NhZ2UYAiABKAkiMwoGU3RhdHVzEgsKB1VOS05PV04QABILCgdTRVJWSU5HEAESDwoLTk9UX1NFUlZJTkcQAjJTCg1IZWFsdGhTZXJ2aWNlEkIKBUNoZWNrEhouYXBpLnYxLkhlYWx0aENoZWNrUmVxdWVzdBobLmFwaS52MS5IZWFsdGhDaGVja1Jlc3BvbnNlIgBCggEKCmNvbS5hcGkudjFCC0hlYWx0aFByb3RvUAFaLmdpdGh1Yi5jb20vZWxlZTE3NjYvYnRyZnNndWlkL2dlbi9hcGkvdjE7YXBpdjGiAgNBWFiqAgZBcGkuVjHKAgZBcGlcVjHiAhJBcGlcVjFcR1BCTWV0YWRhdGHqAgdBcGk6OlYxYgZwcm90bzM");
+  fileDesc("ChNhcGkvdjEvaGVhbHRoLnByb3RvEgZhcGkudjEiJQoSSGVhbHRoQ2hlY2tSZXF1ZXN0Eg8KB3NlcnZpY2UYASABKAkiogEKE0hlYWx0aENoZWNrUmVzcG9uc2USMgoGc3RhdHVzGAEgASgOMiIuYXBpLnYxLkhlYWx0aENoZWNrUmVzcG9uc2UuU3RhdHVzEg8KB21lc3NhZ2UYAiABKAkSEQoJcmVhZF9vbmx5GAMgASgIIjMKBlN0YXR1cxILCgdVTktOT1dOEAASCwoHU0VSVklORxABEg8KC05PVF9TRVJWSU5HEAIyUwoNSGVhbHRoU2VydmljZRJCCgVDaGVjaxIaLmFwaS52MS5IZWFsdGhDaGVja1JlcXVlc3QaGy5hcGkudjEuSGVhbHRoQ2hlY2tSZXNwb25zZSIAQoIBCgpjb20uYXBpLnYxQgtIZWFsdGhQcm90b1ABWi5naXRodWIuY29tL2VsZWUxNzY2L2J0cmZzZ3VpZC9nZW4vYXBpL3YxO2FwaXYxogIDQVhYqgIGQXBpLlYxygIGQXBpXFYx4gISQXBpXFYxXEdQQk1ldGFkYXRh6gIHQXBpOjpWMWIGcHJvdG8z");
 
 /**
  * @generated from message api.v1.HealthCheckRequest
@@ -42,6 +42,13 @@ export type HealthCheckResponse = Message<"api.v1.HealthCheckResponse"> & {
    * @generated from field: string message = 2;
    */
   message: string;
+
+  /**
+   * The server refuses every mutating RPC (--read-only)
+   *
+   * @generated from field: bool read_only = 3;
+   */
+  readOnly: boolean;
 };
 
 /**
@@ -0,0 +1,465 @@
+// @generated by protoc-gen-es v2.10.1 with parameter "target=ts"
+// @generated from file api/v1/hub.proto (package api.v1, syntax proto3)
+/* eslint-disable */
+
+import type { GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv2";
+import { fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf/codegenv2";
+import { file_api_v1_balance } from "./balance_pb";
+import { file_api_v1_diagnostics } from "./diagnostics_pb";
+import { file_api_v1_filesystem } from "./filesystem_pb";
+import { file_api_v1_scrub } from "./scrub_pb";
+import type { Message } from "@bufbuild/protobuf";
+import type { FilesystemUsageInfo, TrackedFilesystem } from "./filesystem_pb";
+import type { FilesystemScrubStatus } from "./scrub_pb";
+import type { FilesystemBalanceStatus } from "./balance_pb";
+import type { FilesystemDiagnostics } from "./diagnostics_pb";
+
+/**
+ * Describes the file api/v1/hub.proto.
+ */
+export const file_api_v1_hub: GenFile = /*@__PURE__*/
+  fileDesc("ChBhcGkvdjEvaHViLnByb3RvEgZhcGkudjEaFGFwaS92MS9iYWxhbmNlLnByb3RvGhhhcGkvdjEvZGlhZ25vc3RpY3MucHJvdG8aF2FwaS92MS9maWxlc3lzdGVtLnByb3RvGhJhcGkvdjEvc2NydWIucHJvdG8ilwEKBUFnZW50EgwKBG5hbWUYASABKAkSCwoDdXJsGAIgASgJEg8KB2hlYWx0aHkYAyABKAgSEQoJbGFzdF9zZWVuGAQgASgDEhQKDGxhc3RfY2hlY2tlZBgFIAEoAxISCgpsYXN0X2Vycm9yGAYgASgJEhIKCmxhdGVuY3lfbXMYByABKAMSEQoJcmVhZF9vbmx5GAggASgIIjoKBEhvc3QSDAoEbmFtZRgBIAEoCRINCgVsb2NhbBgCIAEoCBIVCg1lcnJvcl9tZXNzYWdlGAMgASgJIhMKEUxpc3RBZ2VudHNSZXF1ZXN0IjMKEkxpc3RBZ2VudHNSZXNwb25zZRIdCgZhZ2VudHMYASADKAsyDS5hcGkudjEuQWdlbnQiHAoaTGlzdEhvc3RGaWxlc3lzdGVtc1JlcXVlc3QiXQoPSG9zdEZpbGVzeXN0ZW1zEhoKBGhvc3QYASABKAsyDC5hcGkudjEuSG9zdBIuCgtmaWxlc3lzdGVtcxgCIAMoCzIZLmFwaS52MS5UcmFja2VkRmlsZXN5c3RlbSJFChtMaXN0SG9zdEZpbGVzeXN0ZW1zUmVzcG9uc2USJgoFaG9zdHMYASADKAsyFy5hcGkudjEuSG9zdEZpbGVzeXN0ZW1zIhsKGUdldEhvc3RTY3J1YlN0YXR1c1JlcXVlc3QiYQoPSG9zdFNjcnViU3RhdHVzEhoKBGhvc3QYASABKAsyDC5hcGkudjEuSG9zdBIyCgtmaWxlc3lzdGVtcxgCIAMoCzIdLmFwaS52MS5GaWxlc3lzdGVtU2NydWJTdGF0dXMiRAoaR2V0SG9zdFNjcnViU3RhdHVzUmVzcG9uc2USJgoFaG9zdHMYASADKAsyFy5hcGkudjEuSG9zdFNjcnViU3RhdHVzIh0KG0dldEhvc3RCYWxhbmNlU3RhdHVzUmVxdWVzdCJlChFIb3N0QmFsYW5jZVN0YXR1cxIaCgRob3N0GAEgASgLMgwuYXBpLnYxLkhvc3QSNAoLZmlsZXN5c3RlbXMYAiADKAsyHy5hcGkudjEuRmlsZXN5c3RlbUJhbGFuY2VTdGF0dXMiSAocR2V0SG9zdEJhbGFuY2VTdGF0dXNSZXNwb25zZRIoCgVob3N0cxgBIAMoCzIZLmFwaS52MS5Ib3N0QmFsYW5jZVN0YXR1cyIfCh1HZXRIb3N0RmlsZXN5c3RlbVVzYWdlUmVxdWVzdCJjChNIb3N0RmlsZXN5c3RlbVVzYWdlEhoKBGhvc3QYASABKAsyDC5hcGkudjEuSG9zdBIwCgtmaWxlc3lzdGVtcxgCIAMoCzIbLmFwaS52MS5GaWxlc3lzdGVtVXNhZ2VJbmZvIkwKHkdldEhvc3RGaWxlc3lzdGVtVXNhZ2VSZXNwb25zZRIqCgVob3N0cxgBIAMoCzIbLmFwaS52MS5Ib3N0RmlsZXN5c3RlbVVzYWdlIhsKGUdldEhvc3REaWFnbm9zdGljc1JlcXVlc3QiYQoPSG9zdERpYWdub3N0aWNzEhoKBGhvc3QYASABKAsyDC5hcGkudjEuSG9zdBIyCgtmaWxlc3lzdGVtcxgCIAMoCzIdLmFwaS52MS5GaWxlc3lzdGVtRGlhZ25vc3RpY3MiRAoaR2V0SG9zdERpYWdub3N0aWNzUmVzcG9uc2USJgoFaG9zdHMYASADKAsyFy5hcGkudjEuSG9zdERpYWdub3N0aWNzMsMECgpIdWJTZXJ2aWNlEkUKCkxpc3RBZ2VudHMSGS5hcGkudjEuTGlzdEFnZW50c1JlcXVlc3QaGi5hcGkudjEuTGlzdEFnZW50c1Jlc3BvbnNlIgASYAoTTGlzdEhvc3RGaWxlc3lzdGVtcxIiLmFwaS52MS5MaXN0SG9zdEZpbGVzeXN0ZW1zUmVxdWVzdBojLmFwaS52MS5MaXN0SG9zdEZpbGVzeXN0ZW1zUmVzcG9uc2UiABJdChJHZXRIb3N0U2NydWJTdGF0dXMSIS5hcGkudjEuR2V0SG9zdFNjcnViU3RhdHVzUmVxdWVzdBoiLmFwaS52MS5HZXRIb3N0U2NydWJTdGF0dXNSZXNwb25zZSIAEmMKFEdldEhvc3RCYWxhbmNlU3RhdHVzEiMuYXBpLnYxLkdldEhvc3RCYWxhbmNlU3RhdHVzUmVxdWVzdBokLmFwaS52MS5HZXRIb3N0QmFsYW5jZVN0YXR1c1Jlc3BvbnNlIgASaQoWR2V0SG9zdEZpbGVzeXN0ZW1Vc2FnZRIlLmFwaS52MS5HZXRIb3N0RmlsZXN5c3RlbVVzYWdlUmVxdWVzdBomLmFwaS52MS5HZXRIb3N0RmlsZXN5c3RlbVVzYWdlUmVzcG9uc2UiABJdChJHZXRIb3N0RGlhZ25vc3RpY3MSIS5hcGkudjEuR2V0SG9zdERpYWdub3N0aWNzUmVxdWVzdBoiLmFwaS52MS5HZXRIb3N0RGlhZ25vc3RpY3NSZXNwb25zZSIAQn8KCmNvbS5hcGkudjFCCEh1YlByb3RvUAFaLmdpdGh1Yi5jb20vZWxlZTE3NjYvYnRyZnNndWlkL2dlbi9hcGkvdjE7YXBpdjGiAgNBWFiqAgZBcGkuVjHKAgZBcGlcVjHiAhJBcGlcVjFcR1BCTWV0YWRhdGHqAgdBcGk6OlYxYgZwcm90bzM", [file_api_v1_balance, file_api_v1_diagnostics, file_api_v1_filesystem, file_api_v1_scrub]);
+
+/**
+ * @generated from message api.v1.Agent
+ */
+export type Agent = Message<"api.v1.Agent"> & {
+  /**
+   * @generated from field: string name = 1;
+   */
+  name: string;
+
+  /**
+   * @generated from field: string url = 2;
+   */
+  url: string;
+
+  /**
+   * The last check or call worked
+   *
+   * @generated from field: bool healthy = 3;
+   */
+  healthy: boolean;
+
+  /**
+   * Unix seconds of the last success (0 = never)
+   *
+   * @generated from field: int64 last_seen = 4;
+   */
+  lastSeen: bigint;
+
+  /**
+   * @generated from field: int64 last_checked = 5;
+   */
+  lastChecked: bigint;
+
+  /**
+   * @generated from field: string last_error = 6;
+   */
+  lastError: string;
+
+  /**
+   * Of the last health check
+   *
+   * @generated from field: int64 latency_ms = 7;
+   */
+  latencyMs: bigint;
+
+  /**
+   * The agent refuses mutating RPCs
+   *
+   * @generated from field: bool read_only = 8;
+   */
+  readOnly: boolean;
+};
+
+/**
+ * Describes the message api.v1.Agent.
+ * Use `create(AgentSchema)` to create a new message.
+ */
+export const AgentSchema: GenMessage<Agent> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 0);
+
+/**
+ * Which host an entry is from. This server is local, with its hostname.
+ *
+ * @generated from message api.v1.Host
+ */
+export type Host = Message<"api.v1.Host"> & {
+  /**
+   * @generated from field: string name = 1;
+   */
+  name: string;
+
+  /**
+   * @generated from field: bool local = 2;
+   */
+  local: boolean;
+
+  /**
+   * If the host couldn't be reached
+   *
+   * @generated from field: string error_message = 3;
+   */
+  errorMessage: string;
+};
+
+/**
+ * Describes the message api.v1.Host.
+ * Use `create(HostSchema)` to create a new message.
+ */
+export const HostSchema: GenMessage<Host> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 1);
+
+/**
+ * @generated from message api.v1.ListAgentsRequest
+ */
+export type ListAgentsRequest = Message<"api.v1.ListAgentsRequest"> & {
+};
+
+/**
+ * Describes the message api.v1.ListAgentsRequest.
+ * Use `create(ListAgentsRequestSchema)` to create a new message.
+ */
+export const ListAgentsRequestSchema: GenMessage<ListAgentsRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 2);
+
+/**
+ * @generated from message api.v1.ListAgentsResponse
+ */
+export type ListAgentsResponse = Message<"api.v1.ListAgentsResponse"> & {
+  /**
+   * @generated from field: repeated api.v1.Agent agents = 1;
+   */
+  agents: Agent[];
+};
+
+/**
+ * Describes the message api.v1.ListAgentsResponse.
+ * Use `create(ListAgentsResponseSchema)` to create a new message.
+ */
+export const ListAgentsResponseSchema: GenMessage<ListAgentsResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 3);
+
+/**
+ * @generated from message api.v1.ListHostFilesystemsRequest
+ */
+export type ListHostFilesystemsRequest = Message<"api.v1.ListHostFilesystemsRequest"> & {
+};
+
+/**
+ * Describes the message api.v1.ListHostFilesystemsRequest.
+ * Use `create(ListHostFilesystemsRequestSchema)` to create a new message.
+ */
+export const ListHostFilesystemsRequestSchema: GenMessage<ListHostFilesystemsRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 4);
+
+/**
+ * @generated from message api.v1.HostFilesystems
+ */
+export type HostFilesystems = Message<"api.v1.HostFilesystems"> & {
+  /**
+   * @generated from field: api.v1.Host host = 1;
+   */
+  host?: Host;
+
+  /**
+   * @generated from field: repeated api.v1.TrackedFilesystem filesystems = 2;
+   */
+  filesystems: TrackedFilesystem[];
+};
+
+/**
+ * Describes the message api.v1.HostFilesystems.
+ * Use `create(HostFilesystemsSchema)` to create a new message.
+ */
+export const HostFilesystemsSchema: GenMessage<HostFilesystems> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 5);
+
+/**
+ * @generated from message api.v1.ListHostFilesystemsResponse
+ */
+export type ListHostFilesystemsResponse = Message<"api.v1.ListHostFilesystemsResponse"> & {
+  /**
+   * @generated from field: repeated api.v1.HostFilesystems hosts = 1;
+   */
+  hosts: HostFilesystems[];
+};
+
+/**
+ * Describes the message api.v1.ListHostFilesystemsResponse.
+ * Use `create(ListHostFilesystemsResponseSchema)` to create a new message.
+ */
+export const ListHostFilesystemsResponseSchema: GenMessage<ListHostFilesystemsResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 6);
+
+/**
+ * @generated from message api.v1.GetHostScrubStatusRequest
+ */
+export type GetHostScrubStatusRequest = Message<"api.v1.GetHostScrubStatusRequest"> & {
+};
+
+/**
+ * Describes the message api.v1.GetHostScrubStatusRequest.
+ * Use `create(GetHostScrubStatusRequestSchema)` to create a new message.
+ */
+export const GetHostScrubStatusRequestSchema: GenMessage<GetHostScrubStatusRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 7);
+
+/**
+ * @generated from message api.v1.HostScrubStatus
+ */
+export type HostScrubStatus = Message<"api.v1.HostScrubStatus"> & {
+  /**
+   * @generated from field: api.v1.Host host = 1;
+   */
+  host?: Host;
+
+  /**
+   * @generated from field: repeated api.v1.FilesystemScrubStatus filesystems = 2;
+   */
+  filesystems: FilesystemScrubStatus[];
+};
+
+/**
+ * Describes the message api.v1.HostScrubStatus.
+ * Use `create(HostScrubStatusSchema)` to create a new message.
+ */
+export const HostScrubStatusSchema: GenMessage<HostScrubStatus> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 8);
+
+/**
+ * @generated from message api.v1.GetHostScrubStatusResponse
+ */
+export type GetHostScrubStatusResponse = Message<"api.v1.GetHostScrubStatusResponse"> & {
+  /**
+   * @generated from field: repeated api.v1.HostScrubStatus hosts = 1;
+   */
+  hosts: HostScrubStatus[];
+};
+
+/**
+ * Describes the message api.v1.GetHostScrubStatusResponse.
+ * Use `create(GetHostScrubStatusResponseSchema)` to create a new message.
+ */
+export const GetHostScrubStatusResponseSchema: GenMessage<GetHostScrubStatusResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 9);
+
+/**
+ * @generated from message api.v1.GetHostBalanceStatusRequest
+ */
+export type GetHostBalanceStatusRequest = Message<"api.v1.GetHostBalanceStatusRequest"> & {
+};
+
+/**
+ * Describes the message api.v1.GetHostBalanceStatusRequest.
+ * Use `create(GetHostBalanceStatusRequestSchema)` to create a new message.
+ */
+export const GetHostBalanceStatusRequestSchema: GenMessage<GetHostBalanceStatusRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 10);
+
+/**
+ * @generated from message api.v1.HostBalanceStatus
+ */
+export type HostBalanceStatus = Message<"api.v1.HostBalanceStatus"> & {
+  /**
+   * @generated from field: api.v1.Host host = 1;
+   */
+  host?: Host;
+
+  /**
+   * @generated from field: repeated api.v1.FilesystemBalanceStatus filesystems = 2;
+   */
+  filesystems: FilesystemBalanceStatus[];
+};
+
+/**
+ * Describes the message api.v1.HostBalanceStatus.
+ * Use `create(HostBalanceStatusSchema)` to create a new message.
+ */
+export const HostBalanceStatusSchema: GenMessage<HostBalanceStatus> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 11);
+
+/**
+ * @generated from message api.v1.GetHostBalanceStatusResponse
+ */
+export type GetHostBalanceStatusResponse = Message<"api.v1.GetHostBalanceStatusResponse"> & {
+  /**
+   * @generated from field: repeated api.v1.HostBalanceStatus hosts = 1;
+   */
+  hosts: HostBalanceStatus[];
+};
+
+/**
+ * Describes the message api.v1.GetHostBalanceStatusResponse.
+ * Use `create(GetHostBalanceStatusResponseSchema)` to create a new message.
+ */
+export const GetHostBalanceStatusResponseSchema: GenMessage<GetHostBalanceStatusResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 12);
+
+/**
+ * @generated from message api.v1.GetHostFilesystemUsageRequest
+ */
+export type GetHostFilesystemUsageRequest = Message<"api.v1.GetHostFilesystemUsageRequest"> & {
+};
+
+/**
+ * Describes the message api.v1.GetHostFilesystemUsageRequest.
+ * Use `create(GetHostFilesystemUsageRequestSchema)` to create a new message.
+ */
+export const GetHostFilesystemUsageRequestSchema: GenMessage<GetHostFilesystemUsageRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 13);
+
+/**
+ * @generated from message api.v1.HostFilesystemUsage
+ */
+export type HostFilesystemUsage = Message<"api.v1.HostFilesystemUsage"> & {
+  /**
+   * @generated from field: api.v1.Host host = 1;
+   */
+  host?: Host;
+
+  /**
+   * @generated from field: repeated api.v1.FilesystemUsageInfo filesystems = 2;
+   */
+  filesystems: FilesystemUsageInfo[];
+};
+
+/**
+ * Describes the message api.v1.HostFilesystemUsage.
+ * Use `create(HostFilesystemUsageSchema)` to create a new message.
+ */
+export const HostFilesystemUsageSchema: GenMessage<HostFilesystemUsage> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 14);
+
+/**
+ * @generated from message api.v1.GetHostFilesystemUsageResponse
+ */
+export type GetHostFilesystemUsageResponse = Message<"api.v1.GetHostFilesystemUsageResponse"> & {
+  /**
+   * @generated from field: repeated api.v1.HostFilesystemUsage hosts = 1;
+   */
+  hosts: HostFilesystemUsage[];
+};
+
+/**
+ * Describes the message api.v1.GetHostFilesystemUsageResponse.
+ * Use `create(GetHostFilesystemUsageResponseSchema)` to create a new message.
+ */
+export const GetHostFilesystemUsageResponseSchema: GenMessage<GetHostFilesystemUsageResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 15);
+
+/**
+ * @generated from message api.v1.GetHostDiagnosticsRequest
+ */
+export type GetHostDiagnosticsRequest = Message<"api.v1.GetHostDiagnosticsRequest"> & {
+};
+
+/**
+ * Describes the message api.v1.GetHostDiagnosticsRequest.
+ * Use `create(GetHostDiagnosticsRequestSchema)` to create a new message.
+ */
+export const GetHostDiagnosticsRequestSchema: GenMessage<GetHostDiagnosticsRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 16);
+
+/**
+ * @generated from message api.v1.HostDiagnostics
+ */
+export type HostDiagnostics = Message<"api.v1.HostDiagnostics"> & {
+  /**
+   * @generated from field: api.v1.Host host = 1;
+   */
+  host?: Host;
+
+  /**
+   * @generated from field: repeated api.v1.FilesystemDiagnostics filesystems = 2;
+   */
+  filesystems: FilesystemDiagnostics[];
+};
+
+/**
+ * Describes the message api.v1.HostDiagnostics.
+ * Use `create(HostDiagnosticsSchema)` to create a new message.
+ */
+export const HostDiagnosticsSchema: GenMessage<HostDiagnostics> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 17);
+
+/**
+ * @generated from message api.v1.GetHostDiagnosticsResponse
+ */
+export type GetHostDiagnosticsResponse = Message<"api.v1.GetHostDiagnosticsResponse"> & {
+  /**
+   * @generated from field: repeated api.v1.HostDiagnostics hosts = 1;
+   */
+  hosts: HostDiagnostics[];
+};
+
+/**
+ * Describes the message api.v1.GetHostDiagnosticsResponse.
+ * Use `create(GetHostDiagnosticsResponseSchema)` to create a new message.
+ */
+export const GetHostDiagnosticsResponseSchema: GenMessage<GetHostDiagnosticsResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_hub, 18);
+
+/**
+ * HubService collects from the agents in the config file's [hub] section.
+ * The Host* RPCs call the matching RPC on this server and on every agent at
+ * once, and return one entry per host; an agent that can't be reached gets
+ * an entry with its error instead of failing the call.
+ *
+ * @generated from service api.v1.HubService
+ */
+export const HubService: GenService<{
+  /**
+   * @generated from rpc api.v1.HubService.ListAgents
+   */
+  listAgents: {
+    methodKind: "unary";
+    input: typeof ListAgentsRequestSchema;
+    output: typeof ListAgentsResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.HubService.ListHostFilesystems
+   */
+  listHostFilesystems: {
+    methodKind: "unary";
+    input: typeof ListHostFilesystemsRequestSchema;
+    output: typeof ListHostFilesystemsResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.HubService.GetHostScrubStatus
+   */
+  getHostScrubStatus: {
+    methodKind: "unary";
+    input: typeof GetHostScrubStatusRequestSchema;
+    output: typeof GetHostScrubStatusResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.HubService.GetHostBalanceStatus
+   */
+  getHostBalanceStatus: {
+    methodKind: "unary";
+    input: typeof GetHostBalanceStatusRequestSchema;
+    output: typeof GetHostBalanceStatusResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.HubService.GetHostFilesystemUsage
+   */
+  getHostFilesystemUsage: {
+    methodKind: "unary";
+    input: typeof GetHostFilesystemUsageRequestSchema;
+    output: typeof GetHostFilesystemUsageResponseSchema;
+  },
+  /**
+   * GetHostDiagnostics runs each host's diagnostics with its own thresholds
+   *
+   * @generated from rpc api.v1.HubService.GetHostDiagnostics
+   */
+  getHostDiagnostics: {
+    methodKind: "unary";
+    input: typeof GetHostDiagnosticsRequestSchema;
+    output: typeof GetHostDiagnosticsResponseSchema;
+  },
+}> = /*@__PURE__*/
+  serviceDesc(file_api_v1_hub, 0);
+
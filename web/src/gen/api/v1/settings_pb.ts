@@ -10,7 +10,7 @@ import type { Message } from "@bufbuild/protobuf";
  * Describes the file api/v1/settings.proto.
  */
 export const file_api_v1_settings: GenFile = /*@__PURE__*/
-  fileDesc("ChVhcGkvdjEvc2V0dGluZ3MucHJvdG8SBmFwaS52MSIvCg5TZXJ2ZXJTZXR0aW5ncxIdChVkZWZhdWx0X3NhbXBsZV90YXJnZXQYASABKAQiFAoSR2V0U2V0dGluZ3NSZXF1ZXN0Ij8KE0dldFNldHRpbmdzUmVzcG9uc2USKAoIc2V0dGluZ3MYASABKAsyFi5hcGkudjEuU2VydmVyU2V0dGluZ3MiQQoVVXBkYXRlU2V0dGluZ3NSZXF1ZXN0EigKCHNldHRpbmdzGAEgASgLMhYuYXBpLnYxLlNlcnZlclNldHRpbmdzIkIKFlVwZGF0ZVNldHRpbmdzUmVzcG9uc2USKAoIc2V0dGluZ3MYASABKAsyFi5hcGkudjEuU2VydmVyU2V0dGluZ3MyrgEKD1NldHRpbmdzU2VydmljZRJICgtHZXRTZXR0aW5ncxIaLmFwaS52MS5HZXRTZXR0aW5nc1JlcXVlc3QaGy5hcGkudjEuR2V0U2V0dGluZ3NSZXNwb25zZSIAElEKDlVwZGF0ZVNldHRpbmdzEh0uYXBpLnYxLlVwZGF0ZVNldHRpbmdzUmVxdWVzdBoeLmFwaS52MS5VcGRhdGVTZXR0aW5nc1Jlc3BvbnNlIgBChAEKCmNvbS5hcGkudjFCDVNldHRpbmdzUHJvdG9QAVouZ2l0aHViLmNvbS9lbGVlMTc2Ni9idHJmc2d1aWQvZ2VuL2FwaS92MTthcGl2MaICA0FYWKoCBkFwaS5WMcoCBkFwaVxWMeICEkFwaVxWMVxHUEJNZXRhZGF0YeoCB0FwaTo6VjFiBnByb3RvMw");
+  fileDesc("ChVhcGkvdjEvc2V0dGluZ3MucHJvdG8SBmFwaS52MSK4AQoOU2VydmVyU2V0dGluZ3MSHQoVZGVmYXVsdF9zYW1wbGVfdGFyZ2V0GAEgASgEEiAKGGNvbGxlY3RfaW50ZXJ2YWxfc2Vjb25kcxgCIAEoBBIaChJzY3J1Yl9tYXhfYWdlX2RheXMYAyABKAUSFQoNc2xhY2tfcGVyY2VudBgEIAEoARIXCg9taW5fc2xhY2tfYnl0ZXMYBSABKAMSGQoRaW1iYWxhbmNlX3BlcmNlbnQYBiABKAEiFAoSR2V0U2V0dGluZ3NSZXF1ZXN0In0KE0dldFNldHRpbmdzUmVzcG9uc2USKAoIc2V0dGluZ3MYASABKAsyFi5hcGkudjEuU2VydmVyU2V0dGluZ3MSKAoIZGVmYXVsdHMYAiABKAsyFi5hcGkudjEuU2VydmVyU2V0dGluZ3MSEgoKb3ZlcnJpZGRlbhgDIAMoCSJXChVVcGRhdGVTZXR0aW5nc1JlcXVlc3QSKAoIc2V0dGluZ3MYASABKAsyFi5hcGkudjEuU2VydmVyU2V0dGluZ3MSFAoMcmVzZXRfZmllbGRzGAIgAygJIlYKFlVwZGF0ZVNldHRpbmdzUmVzcG9uc2USKAoIc2V0dGluZ3MYASABKAsyFi5hcGkudjEuU2VydmVyU2V0dGluZ3MSEgoKb3ZlcnJpZGRlbhgCIAMoCTKuAQoPU2V0dGluZ3NTZXJ2aWNlEkgKC0dldFNldHRpbmdzEhouYXBpLnYxLkdldFNldHRpbmdzUmVxdWVzdBobLmFwaS52MS5HZXRTZXR0aW5nc1Jlc3BvbnNlIgASUQoOVXBkYXRlU2V0dGluZ3MSHS5hcGkudjEuVXBkYXRlU2V0dGluZ3NSZXF1ZXN0Gh4uYXBpLnYxLlVwZGF0ZVNldHRpbmdzUmVzcG9uc2UiAEKEAQoKY29tLmFwaS52MUINU2V0dGluZ3NQcm90b1ABWi5naXRodWIuY29tL2VsZWUxNzY2L2J0cmZzZ3VpZC9nZW4vYXBpL3YxO2FwaXYxogIDQVhYqgIGQXBpLlYxygIGQXBpXFYx4gISQXBpXFYxXEdQQk1ldGFkYXRh6gIHQXBpOjpWMWIGcHJvdG8z");
 
 /**
  * @generated from message api.v1.ServerSettings
@@ -22,6 +22,43 @@ export type ServerSettings = Message<"api.v1.ServerSettings"> & {
    * @generated from field: uint64 default_sample_target = 1;
    */
   defaultSampleTarget: bigint;
+
+  /**
+   * How often usage history is recorded, in seconds (default: 900)
+   *
+   * @generated from field: uint64 collect_interval_seconds = 2;
+   */
+  collectIntervalSeconds: bigint;
+
+  /**
+   * Diagnostic thresholds used when a request leaves them unset
+   *
+   * Warn when the last scrub is older (default: 30)
+   *
+   * @generated from field: int32 scrub_max_age_days = 3;
+   */
+  scrubMaxAgeDays: number;
+
+  /**
+   * Warn when unused space inside data chunks exceeds this (default: 25)
+   *
+   * @generated from field: double slack_percent = 4;
+   */
+  slackPercent: number;
+
+  /**
+   * Ignore slack below this size (default: 10 GiB)
+   *
+   * @generated from field: int64 min_slack_bytes = 5;
+   */
+  minSlackBytes: bigint;
+
+  /**
+   * Warn when device allocation ratios differ by more (default: 25)
+   *
+   * @generated from field: double imbalance_percent = 6;
+   */
+  imbalancePercent: number;
 };
 
 /**
@@ -52,6 +89,20 @@ export type GetSettingsResponse = Message<"api.v1.GetSettingsResponse"> & {
    * @generated from field: api.v1.ServerSettings settings = 1;
    */
   settings?: ServerSettings;
+
+  /**
+   * Values from env and config, before any update
+   *
+   * @generated from field: api.v1.ServerSettings defaults = 2;
+   */
+  defaults?: ServerSettings;
+
+  /**
+   * Fields that differ from their defaults
+   *
+   * @generated from field: repeated string overridden = 3;
+   */
+  overridden: string[];
 };
 
 /**
@@ -69,6 +120,13 @@ export type UpdateSettingsRequest = Message<"api.v1.UpdateSettingsRequest"> & {
    * @generated from field: api.v1.ServerSettings settings = 1;
    */
   settings?: ServerSettings;
+
+  /**
+   * Fields to return to their defaults
+   *
+   * @generated from field: repeated string reset_fields = 2;
+   */
+  resetFields: string[];
 };
 
 /**
@@ -86,6 +144,11 @@ export type UpdateSettingsResponse = Message<"api.v1.UpdateSettingsResponse"> &
    * @generated from field: api.v1.ServerSettings settings = 1;
    */
   settings?: ServerSettings;
+
+  /**
+   * @generated from field: repeated string overridden = 2;
+   */
+  overridden: string[];
 };
 
 /**
@@ -110,7 +173,8 @@ export const SettingsService: GenService<{
     output: typeof GetSettingsResponseSchema;
   },
   /**
-   * UpdateSettings updates server settings
+   * UpdateSettings updates server settings. Unset (zero) fields are left
+   * unchanged.
    *
    * @generated from rpc api.v1.SettingsService.UpdateSettings
    */
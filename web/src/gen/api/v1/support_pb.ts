@@ -0,0 +1,79 @@
+// @generated by protoc-gen-es v2.10.1 with parameter "target=ts"
+// @generated from file api/v1/support.proto (package api.v1, syntax proto3)
+/* eslint-disable */
+
+import type { GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv2";
+import { fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf/codegenv2";
+import type { Message } from "@bufbuild/protobuf";
+
+/**
+ * Describes the file api/v1/support.proto.
+ */
+export const file_api_v1_support: GenFile = /*@__PURE__*/
+  fileDesc("ChRhcGkvdjEvc3VwcG9ydC5wcm90bxIGYXBpLnYxIi0KGkNyZWF0ZVN1cHBvcnRCdW5kbGVSZXF1ZXN0Eg8KB2hpc3RvcnkYASABKAUiPwobQ3JlYXRlU3VwcG9ydEJ1bmRsZVJlc3BvbnNlEhAKCGZpbGVuYW1lGAEgASgJEg4KBmJ1bmRsZRgCIAEoDDJyCg5TdXBwb3J0U2VydmljZRJgChNDcmVhdGVTdXBwb3J0QnVuZGxlEiIuYXBpLnYxLkNyZWF0ZVN1cHBvcnRCdW5kbGVSZXF1ZXN0GiMuYXBpLnYxLkNyZWF0ZVN1cHBvcnRCdW5kbGVSZXNwb25zZSIAQoMBCgpjb20uYXBpLnYxQgxTdXBwb3J0UHJvdG9QAVouZ2l0aHViLmNvbS9lbGVlMTc2Ni9idHJmc2d1aWQvZ2VuL2FwaS92MTthcGl2MaICA0FYWKoCBkFwaS5WMcoCBkFwaVxWMeICEkFwaVxWMVxHUEJNZXRhZGF0YeoCB0FwaTo6VjFiBnByb3RvMw");
+
+/**
+ * @generated from message api.v1.CreateSupportBundleRequest
+ */
+export type CreateSupportBundleRequest = Message<"api.v1.CreateSupportBundleRequest"> & {
+  /**
+   * Balance records and errors to include (0 = 20)
+   *
+   * @generated from field: int32 history = 1;
+   */
+  history: number;
+};
+
+/**
+ * Describes the message api.v1.CreateSupportBundleRequest.
+ * Use `create(CreateSupportBundleRequestSchema)` to create a new message.
+ */
+export const CreateSupportBundleRequestSchema: GenMessage<CreateSupportBundleRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_support, 0);
+
+/**
+ * @generated from message api.v1.CreateSupportBundleResponse
+ */
+export type CreateSupportBundleResponse = Message<"api.v1.CreateSupportBundleResponse"> & {
+  /**
+   * @generated from field: string filename = 1;
+   */
+  filename: string;
+
+  /**
+   * tar.gz
+   *
+   * @generated from field: bytes bundle = 2;
+   */
+  bundle: Uint8Array;
+};
+
+/**
+ * Describes the message api.v1.CreateSupportBundleResponse.
+ * Use `create(CreateSupportBundleResponseSchema)` to create a new message.
+ */
+export const CreateSupportBundleResponseSchema: GenMessage<CreateSupportBundleResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_support, 1);
+
+/**
+ * SupportService makes support bundles to attach to bug reports
+ *
+ * @generated from service api.v1.SupportService
+ */
+export const SupportService: GenService<{
+  /**
+   * CreateSupportBundle returns a tar.gz with the version, the config with
+   * its secrets redacted, the tracked filesystems with their sysfs
+   * allocation, device error counters, last scrub and recent balances, the
+   * recorded errors and a goroutine dump. Also at GET /support/bundle.
+   *
+   * @generated from rpc api.v1.SupportService.CreateSupportBundle
+   */
+  createSupportBundle: {
+    methodKind: "unary";
+    input: typeof CreateSupportBundleRequestSchema;
+    output: typeof CreateSupportBundleResponseSchema;
+  },
+}> = /*@__PURE__*/
+  serviceDesc(file_api_v1_support, 0);
+
@@ -10,7 +10,7 @@ import type { Message } from "@bufbuild/protobuf";
  * Describes the file api/v1/usage.proto.
  */
 export const file_api_v1_usage: GenFile = /*@__PURE__*/
-  fileDesc("ChJhcGkvdjEvdXNhZ2UucHJvdG8SBmFwaS52MSJPChRTdGFydFNhbXBsaW5nUmVxdWVzdBIPCgdmc19wYXRoGAEgASgJEg4KBnJlc3VtZRgCIAEoCBIWCg50YXJnZXRfc2FtcGxlcxgDIAEoBCJTChVTdGFydFNhbXBsaW5nUmVzcG9uc2USDwoHc3RhcnRlZBgBIAEoCBIPCgdyZXN1bWVkGAIgASgIEhgKEGV4aXN0aW5nX3NhbXBsZXMYAyABKAQiJgoTU3RvcFNhbXBsaW5nUmVxdWVzdBIPCgdmc19wYXRoGAEgASgJIj4KFFN0b3BTYW1wbGluZ1Jlc3BvbnNlEg8KB3N0b3BwZWQYASABKAgSFQoNdG90YWxfc2FtcGxlcxgCIAEoBCIrChhHZXRTYW1wbGluZ1N0YXR1c1JlcXVlc3QSDwoHZnNfcGF0aBgBIAEoCSLgAQoQU2FtcGxpbmdQcm9ncmVzcxISCgppc19ydW5uaW5nGAEgASgIEhQKDHNhbXBsZV9jb3VudBgCIAEoBBISCgp0b3RhbF9zaXplGAMgASgEEhIKCnN0YXJ0ZWRfYXQYBCABKAMSFAoMbGFzdF91cGRhdGVkGAUgASgDEhoKEnNhbXBsZXNfcGVyX3NlY29uZBgGIAEoARIUCgxjdXJyZW50X3BhdGgYByABKAkSFAoMcmVjZW50X3BhdGhzGAggAygJEhwKFHJ1bm5pbmdfdGltZV9zZWNvbmRzGAkgASgDInAKGUdldFNhbXBsaW5nU3RhdHVzUmVzcG9uc2USKgoIcHJvZ3Jlc3MYASABKAsyGC5hcGkudjEuU2FtcGxpbmdQcm9ncmVzcxITCgtoYXNfc2Vzc2lvbhgCIAEoCBISCgpzZXNzaW9uX2lkGAMgASgJIicKFENsZWFyU2FtcGxpbmdSZXF1ZXN0Eg8KB2ZzX3BhdGgYASABKAkiKAoVQ2xlYXJTYW1wbGluZ1Jlc3BvbnNlEg8KB2NsZWFyZWQYASABKAgiZwoTR2V0VXNhZ2VUcmVlUmVxdWVzdBIPCgdmc19wYXRoGAEgASgJEgwKBHBhdGgYAiABKAkSDwoHc29ydF9ieRgDIAEoCRIRCglzb3J0X2Rlc2MYBCABKAgSDQoFbGltaXQYBSABKAUiMAodU3RyZWFtU2FtcGxpbmdQcm9ncmVzc1JlcXVlc3QSDwoHZnNfcGF0aBgBIAEoCSKOAQoJVXNhZ2VOb2RlEgwKBG5hbWUYASABKAkSEQoJZnVsbF9wYXRoGAIgASgJEg4KBmlzX2RpchgDIAEoCBIPCgdzYW1wbGVzGAQgASgEEhYKDmVzdGltYXRlZF9zaXplGAUgASgEEhIKCnBlcmNlbnRhZ2UYBiABKAESEwoLY2hpbGRfY291bnQYByABKAUiigEKFEdldFVzYWdlVHJlZVJlc3BvbnNlEiMKCGNoaWxkcmVuGAEgAygLMhEuYXBpLnYxLlVzYWdlTm9kZRIiCgdjdXJyZW50GAIgASgLMhEuYXBpLnYxLlVzYWdlTm9kZRIVCg10b3RhbF9zYW1wbGVzGAMgASgEEhIKCnRvdGFsX3NpemUYBCABKAQygwQKDFVzYWdlU2VydmljZRJOCg1TdGFydFNhbXBsaW5nEhwuYXBpLnYxLlN0YXJ0U2FtcGxpbmdSZXF1ZXN0Gh0uYXBpLnYxLlN0YXJ0U2FtcGxpbmdSZXNwb25zZSIAEksKDFN0b3BTYW1wbGluZxIbLmFwaS52MS5TdG9wU2FtcGxpbmdSZXF1ZXN0GhwuYXBpLnYxLlN0b3BTYW1wbGluZ1Jlc3BvbnNlIgASWgoRR2V0U2FtcGxpbmdTdGF0dXMSIC5hcGkudjEuR2V0U2FtcGxpbmdTdGF0dXNSZXF1ZXN0GiEuYXBpLnYxLkdldFNhbXBsaW5nU3RhdHVzUmVzcG9uc2UiABJOCg1DbGVhclNhbXBsaW5nEhwuYXBpLnYxLkNsZWFyU2FtcGxpbmdSZXF1ZXN0Gh0uYXBpLnYxLkNsZWFyU2FtcGxpbmdSZXNwb25zZSIAEksKDEdldFVzYWdlVHJlZRIbLmFwaS52MS5HZXRVc2FnZVRyZWVSZXF1ZXN0GhwuYXBpLnYxLkdldFVzYWdlVHJlZVJlc3BvbnNlIgASXQoWU3RyZWFtU2FtcGxpbmdQcm9ncmVzcxIlLmFwaS52MS5TdHJlYW1TYW1wbGluZ1Byb2dyZXNzUmVxdWVzdBoYLmFwaS52MS5TYW1wbGluZ1Byb2dyZXNzIgAwAUKBAQoKY29tLmFwaS52MUIKVXNhZ2VQcm90b1ABWi5naXRodWIuY29tL2VsZWUxNzY2L2J0cmZzZ3VpZC9nZW4vYXBpL3YxO2FwaXYxogIDQVhYqgIGQXBpLlYxygIGQXBpXFYx4gISQXBpXFYxXEdQQk1ldGFkYXRh6gIHQXBpOjpWMWIGcHJvdG8z");
+  fileDesc("ChJhcGkvdjEvdXNhZ2UucHJvdG8SBmFwaS52MSJPChRTdGFydFNhbXBsaW5nUmVxdWVzdBIPCgdmc19wYXRoGAEgASgJEg4KBnJlc3VtZRgCIAEoCBIWCg50YXJnZXRfc2FtcGxlcxgDIAEoBCJTChVTdGFydFNhbXBsaW5nUmVzcG9uc2USDwoHc3RhcnRlZBgBIAEoCBIPCgdyZXN1bWVkGAIgASgIEhgKEGV4aXN0aW5nX3NhbXBsZXMYAyABKAQiJgoTU3RvcFNhbXBsaW5nUmVxdWVzdBIPCgdmc19wYXRoGAEgASgJIj4KFFN0b3BTYW1wbGluZ1Jlc3BvbnNlEg8KB3N0b3BwZWQYASABKAgSFQoNdG90YWxfc2FtcGxlcxgCIAEoBCIrChhHZXRTYW1wbGluZ1N0YXR1c1JlcXVlc3QSDwoHZnNfcGF0aBgBIAEoCSL4AQoQU2FtcGxpbmdQcm9ncmVzcxISCgppc19ydW5uaW5nGAEgASgIEhQKDHNhbXBsZV9jb3VudBgCIAEoBBISCgp0b3RhbF9zaXplGAMgASgEEhIKCnN0YXJ0ZWRfYXQYBCABKAMSFAoMbGFzdF91cGRhdGVkGAUgASgDEhoKEnNhbXBsZXNfcGVyX3NlY29uZBgGIAEoARIUCgxjdXJyZW50X3BhdGgYByABKAkSFAoMcmVjZW50X3BhdGhzGAggAygJEhwKFHJ1bm5pbmdfdGltZV9zZWNvbmRzGAkgASgDEhYKDnRhcmdldF9zYW1wbGVzGAogASgEInAKGUdldFNhbXBsaW5nU3RhdHVzUmVzcG9uc2USKgoIcHJvZ3Jlc3MYASABKAsyGC5hcGkudjEuU2FtcGxpbmdQcm9ncmVzcxITCgtoYXNfc2Vzc2lvbhgCIAEoCBISCgpzZXNzaW9uX2lkGAMgASgJIicKFENsZWFyU2FtcGxpbmdSZXF1ZXN0Eg8KB2ZzX3BhdGgYASABKAkiKAoVQ2xlYXJTYW1wbGluZ1Jlc3BvbnNlEg8KB2NsZWFyZWQYASABKAgiZwoTR2V0VXNhZ2VUcmVlUmVxdWVzdBIPCgdmc19wYXRoGAEgASgJEgwKBHBhdGgYAiABKAkSDwoHc29ydF9ieRgDIAEoCRIRCglzb3J0X2Rlc2MYBCABKAgSDQoFbGltaXQYBSABKAUiMAodU3RyZWFtU2FtcGxpbmdQcm9ncmVzc1JlcXVlc3QSDwoHZnNfcGF0aBgBIAEoCSKOAQoJVXNhZ2VOb2RlEgwKBG5hbWUYASABKAkSEQoJZnVsbF9wYXRoGAIgASgJEg4KBmlzX2RpchgDIAEoCBIPCgdzYW1wbGVzGAQgASgEEhYKDmVzdGltYXRlZF9zaXplGAUgASgEEhIKCnBlcmNlbnRhZ2UYBiABKAESEwoLY2hpbGRfY291bnQYByABKAUiigEKFEdldFVzYWdlVHJlZVJlc3BvbnNlEiMKCGNoaWxkcmVuGAEgAygLMhEuYXBpLnYxLlVzYWdlTm9kZRIiCgdjdXJyZW50GAIgASgLMhEuYXBpLnYxLlVzYWdlTm9kZRIVCg10b3RhbF9zYW1wbGVzGAMgASgEEhIKCnRvdGFsX3NpemUYBCABKAQygwQKDFVzYWdlU2VydmljZRJOCg1TdGFydFNhbXBsaW5nEhwuYXBpLnYxLlN0YXJ0U2FtcGxpbmdSZXF1ZXN0Gh0uYXBpLnYxLlN0YXJ0U2FtcGxpbmdSZXNwb25zZSIAEksKDFN0b3BTYW1wbGluZxIbLmFwaS52MS5TdG9wU2FtcGxpbmdSZXF1ZXN0GhwuYXBpLnYxLlN0b3BTYW1wbGluZ1Jlc3BvbnNlIgASWgoRR2V0U2FtcGxpbmdTdGF0dXMSIC5hcGkudjEuR2V0U2FtcGxpbmdTdGF0dXNSZXF1ZXN0GiEuYXBpLnYxLkdldFNhbXBsaW5nU3RhdHVzUmVzcG9uc2UiABJOCg1DbGVhclNhbXBsaW5nEhwuYXBpLnYxLkNsZWFyU2FtcGxpbmdSZXF1ZXN0Gh0uYXBpLnYxLkNsZWFyU2FtcGxpbmdSZXNwb25zZSIAEksKDEdldFVzYWdlVHJlZRIbLmFwaS52MS5HZXRVc2FnZVRyZWVSZXF1ZXN0GhwuYXBpLnYxLkdldFVzYWdlVHJlZVJlc3BvbnNlIgASXQoWU3RyZWFtU2FtcGxpbmdQcm9ncmVzcxIlLmFwaS52MS5TdHJlYW1TYW1wbGluZ1Byb2dyZXNzUmVxdWVzdBoYLmFwaS52MS5TYW1wbGluZ1Byb2dyZXNzIgAwAUKBAQoKY29tLmFwaS52MUIKVXNhZ2VQcm90b1ABWi5naXRodWIuY29tL2VsZWUxNzY2L2J0cmZzZ3VpZC9nZW4vYXBpL3YxO2FwaXYxogIDQVhYqgIGQXBpLlYxygIGQXBpXFYx4gISQXBpXFYxXEdQQk1ldGFkYXRh6gIHQXBpOjpWMWIGcHJvdG8z");
 
 /**
  * @generated from message api.v1.StartSamplingRequest
@@ -192,6 +192,13 @@ export type SamplingProgress = Message<"api.v1.SamplingProgress"> & {
    * @generated from field: int64 running_time_seconds = 9;
    */
   runningTimeSeconds: bigint;
+
+  /**
+   * Sampling stops at this many samples
+   *
+   * @generated from field: uint64 target_samples = 10;
+   */
+  targetSamples: bigint;
 };
 
 /**
@@ -9,12 +9,14 @@ import App from "./App";
 const Home = lazy(() => import(/* webpackChunkName: "home" */ "./pages/Home"));
 const FilesystemDetail = lazy(() => import(/* webpackChunkName: "filesystem" */ "./pages/FilesystemDetail"));
 const Settings = lazy(() => import(/* webpackChunkName: "settings" */ "./pages/Settings"));
+const Hosts = lazy(() => import(/* webpackChunkName: "hosts" */ "./pages/Hosts"));
 
 render(
   () => (
     <Router root={App}>
       <Route path="/" component={Home} />
       <Route path="/settings" component={Settings} />
+      <Route path="/hosts" component={Hosts} />
       <Route path="/fs/:id" component={FilesystemDetail} />
       <Route path="/fs/:id/:tab" component={FilesystemDetail} />
     </Router>
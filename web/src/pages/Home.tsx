@@ -2,6 +2,7 @@ import { createSignal, createResource, For, Show, onMount, onCleanup, batch, Sus
 import { A } from "@solidjs/router";
 import { filesystemClient, subvolumeClient, scrubClient } from "@/api/client";
 import { cacheFsData } from "@/stores/filesystems";
+import { canChange } from "@/stores/auth";
 import { cn, formatBytes, countBackups, formatNumber } from "@/lib/utils";
 import { Button, Alert, ProgressBar, ScrubStatus, LabeledInput } from "@/components/ui";
 
@@ -150,12 +151,14 @@ export default function Home() {
         <div class="border border-border-default bg-bg-surface">
           {/* Title bar */}
           <div class="flex items-center justify-end px-2 py-1 bg-bg-surface-raised border-b border-border-default">
-            <button
-              class="text-xs text-text-tertiary hover:text-text-default cursor-pointer"
-              onClick={() => setShowAddForm(!showAddForm())}
-            >
-              + add
-            </button>
+            <Show when={canChange()}>
+              <button
+                class="text-xs text-text-tertiary hover:text-text-default cursor-pointer"
+                onClick={() => setShowAddForm(!showAddForm())}
+              >
+                + add
+              </button>
+            </Show>
           </div>
 
           {/* Add form */}
@@ -276,13 +279,15 @@ export default function Home() {
                           <ScrubStatus running={fs.scrubRunning} status={fs.scrubStatus} />
                         </td>
                         <td class="px-2 py-2 text-right">
-                          <button
-                            class="text-error-soft hover:text-error-hover hover:bg-error-subtle px-1 cursor-pointer"
-                            title="Remove filesystem"
-                            onClick={() => removeFilesystem(fs.id)}
-                          >
-                            ×
-                          </button>
+                          <Show when={canChange()}>
+                            <button
+                              class="text-error-soft hover:text-error-hover hover:bg-error-subtle px-1 cursor-pointer"
+                              title="Remove filesystem"
+                              onClick={() => removeFilesystem(fs.id)}
+                            >
+                              ×
+                            </button>
+                          </Show>
                         </td>
                       </tr>
                     );
@@ -0,0 +1,224 @@
+import { createResource, For, Show, onMount, onCleanup, Suspense } from "solid-js";
+import { hubClient } from "@/api/client";
+import type { Agent } from "%/v1/hub_pb";
+import { cn, formatBytes, formatRelativeTime } from "@/lib/utils";
+import { Alert, ProgressBar, ScrubStatus } from "@/components/ui";
+
+interface HostFilesystem {
+  path: string;
+  usedBytes: bigint;
+  totalBytes: bigint;
+  scrubStatus: string;
+  scrubRunning: boolean;
+  balanceRunning: boolean;
+  score?: number;
+  error: string;
+}
+
+interface HostSummary {
+  name: string;
+  local: boolean;
+  error: string;
+  filesystems: HostFilesystem[];
+}
+
+interface HubData {
+  agents: Agent[];
+  hosts: HostSummary[];
+}
+
+async function loadHosts(): Promise<HubData> {
+  const [agentsResp, usageResp, scrubResp, balanceResp, diagResp] = await Promise.all([
+    hubClient.listAgents({}),
+    hubClient.getHostFilesystemUsage({}),
+    hubClient.getHostScrubStatus({}),
+    hubClient.getHostBalanceStatus({}),
+    hubClient.getHostDiagnostics({}),
+  ]);
+
+  // Every response lists the hosts in the same order, this server first
+  const hosts: HostSummary[] = usageResp.hosts.map((h, i) => {
+    const scrub = scrubResp.hosts[i]?.filesystems ?? [];
+    const balance = balanceResp.hosts[i]?.filesystems ?? [];
+    const diag = diagResp.hosts[i]?.filesystems ?? [];
+
+    return {
+      name: h.host?.name ?? "",
+      local: h.host?.local ?? false,
+      error: h.host?.errorMessage ?? "",
+      filesystems: h.filesystems.map((fs) => {
+        const s = scrub.find((x) => x.path === fs.path);
+        const b = balance.find((x) => x.path === fs.path);
+        const d = diag.find((x) => x.path === fs.path);
+        const used = fs.usage?.used ?? 0n;
+        return {
+          path: fs.path,
+          usedBytes: used,
+          totalBytes: used + (fs.usage?.freeEstimated ?? 0n),
+          scrubStatus: s?.progress?.status ?? "",
+          scrubRunning: s?.isRunning ?? false,
+          balanceRunning: b?.isRunning ?? false,
+          score: d?.report?.score,
+          error: fs.errorMessage,
+        };
+      }),
+    };
+  });
+
+  return { agents: agentsResp.agents, hosts };
+}
+
+function lastSeen(unix: bigint): string {
+  if (unix === 0n) return "never";
+  return formatRelativeTime(new Date(Number(unix) * 1000));
+}
+
+export default function Hosts() {
+  const [data, { refetch }] = createResource(loadHosts);
+
+  onMount(() => {
+    const handler = () => refetch();
+    window.addEventListener("refresh", handler);
+    onCleanup(() => window.removeEventListener("refresh", handler));
+  });
+
+  return (
+    <div class="space-y-2">
+      <Show when={data.error}>
+        <Alert type="error">{String(data.error)}</Alert>
+      </Show>
+
+      <Suspense fallback={<div class="text-xs text-text-tertiary p-2">loading...</div>}>
+        {/* Agents */}
+        <div class="border border-border-default bg-bg-surface">
+          <div class="px-2 py-1 bg-bg-surface-raised border-b border-border-default text-xs text-text-secondary">
+            agents
+          </div>
+          <Show
+            when={(data()?.agents.length ?? 0) > 0}
+            fallback={
+              <div class="text-xs text-text-tertiary p-4 text-center">
+                no agents. add [[hub.agent]] entries to the config file.
+              </div>
+            }
+          >
+            <table class="w-full text-xs">
+              <thead class="bg-bg-muted border-b border-border-subtle">
+                <tr>
+                  <th class="px-2 py-1 text-left text-text-tertiary font-normal">name</th>
+                  <th class="px-2 py-1 text-left text-text-tertiary font-normal">url</th>
+                  <th class="px-2 py-1 text-left text-text-tertiary font-normal">status</th>
+                  <th class="px-2 py-1 text-right text-text-tertiary font-normal">latency</th>
+                  <th class="px-2 py-1 text-right text-text-tertiary font-normal">last seen</th>
+                </tr>
+              </thead>
+              <tbody>
+                <For each={data()?.agents}>
+                  {(a) => (
+                    <tr class="border-b border-border-subtle">
+                      <td class="px-2 py-2 text-text-secondary">{a.name}</td>
+                      <td class="px-2 py-2 text-text-muted font-mono">{a.url}</td>
+                      <td class="px-2 py-2">
+                        <Show
+                          when={a.healthy}
+                          fallback={
+                            <span class="text-error" title={a.lastError}>
+                              {a.lastChecked === 0n ? "unchecked" : "down"}
+                            </span>
+                          }
+                        >
+                          <span class="text-success">up</span>
+                          <Show when={a.readOnly}>
+                            <span class="text-warning ml-1">read-only</span>
+                          </Show>
+                        </Show>
+                      </td>
+                      <td class="px-2 py-2 text-right text-text-tertiary font-mono">
+                        <Show when={a.healthy} fallback="-">{a.latencyMs.toString()}ms</Show>
+                      </td>
+                      <td class="px-2 py-2 text-right text-text-tertiary">{lastSeen(a.lastSeen)}</td>
+                    </tr>
+                  )}
+                </For>
+              </tbody>
+            </table>
+          </Show>
+        </div>
+
+        {/* Filesystems per host */}
+        <For each={data()?.hosts}>
+          {(host) => (
+            <div class="border border-border-default bg-bg-surface">
+              <div class="flex items-baseline px-2 py-1 bg-bg-surface-raised border-b border-border-default text-xs">
+                <span class="text-text-secondary">{host.name}</span>
+                <Show when={host.local}>
+                  <span class="text-text-muted ml-1">(this server)</span>
+                </Show>
+              </div>
+              <Show when={host.error}>
+                <div class="text-xs text-error p-2">{host.error}</div>
+              </Show>
+              <Show when={!host.error && host.filesystems.length === 0}>
+                <div class="text-xs text-text-tertiary p-4 text-center">no filesystems tracked</div>
+              </Show>
+              <Show when={host.filesystems.length > 0}>
+                <table class="w-full text-xs">
+                  <thead class="bg-bg-muted border-b border-border-subtle">
+                    <tr>
+                      <th class="px-2 py-1 text-left text-text-tertiary font-normal">path</th>
+                      <th class="px-2 py-1 text-left text-text-tertiary font-normal">usage</th>
+                      <th class="px-2 py-1 text-left text-text-tertiary font-normal">scrub</th>
+                      <th class="px-2 py-1 text-left text-text-tertiary font-normal">balance</th>
+                      <th class="px-2 py-1 text-right text-text-tertiary font-normal">health</th>
+                    </tr>
+                  </thead>
+                  <tbody>
+                    <For each={host.filesystems}>
+                      {(fs) => {
+                        const usedPct = () => fs.totalBytes > 0n ? Number((fs.usedBytes * 100n) / fs.totalBytes) : 0;
+                        return (
+                          <tr class="border-b border-border-subtle">
+                            <td class="px-2 py-2 text-text-secondary font-mono">{fs.path}</td>
+                            <td class="px-2 py-2">
+                              <Show
+                                when={!fs.error && fs.totalBytes > 0n}
+                                fallback={<span class="text-error" title={fs.error}>{fs.error ? "error" : "-"}</span>}
+                              >
+                                <div class="flex items-center space-x-2">
+                                  <ProgressBar value={usedPct()} thresholdColors size="md" class="w-16" />
+                                  <span class="text-text-secondary font-mono">{formatBytes(fs.usedBytes)}</span>
+                                  <span class="text-text-muted">/</span>
+                                  <span class="text-text-tertiary font-mono">{formatBytes(fs.totalBytes)}</span>
+                                </div>
+                              </Show>
+                            </td>
+                            <td class="px-2 py-2">
+                              <ScrubStatus running={fs.scrubRunning} status={fs.scrubStatus} />
+                            </td>
+                            <td class="px-2 py-2 text-text-tertiary">{fs.balanceRunning ? "running" : "-"}</td>
+                            <td class="px-2 py-2 text-right">
+                              <Show when={fs.score !== undefined} fallback={<span class="text-text-muted">-</span>}>
+                                <span
+                                  class={cn(
+                                    "font-mono",
+                                    fs.score! >= 80 ? "text-success" : fs.score! >= 50 ? "text-warning" : "text-error"
+                                  )}
+                                >
+                                  {fs.score}
+                                </span>
+                              </Show>
+                            </td>
+                          </tr>
+                        );
+                      }}
+                    </For>
+                  </tbody>
+                </table>
+              </Show>
+            </div>
+          )}
+        </For>
+      </Suspense>
+    </div>
+  );
+}
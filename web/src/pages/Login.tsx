@@ -0,0 +1,44 @@
+import { createSignal, Show } from "solid-js";
+import { login } from "@/stores/auth";
+import { Alert, Button, LabeledInput } from "@/components/ui";
+
+// Shown instead of the app when auth is enabled and nobody is signed in
+export default function Login() {
+  const [username, setUsername] = createSignal("");
+  const [password, setPassword] = createSignal("");
+  const [error, setError] = createSignal<string | null>(null);
+  const [busy, setBusy] = createSignal(false);
+
+  const submit = async (e: Event) => {
+    e.preventDefault();
+    setBusy(true);
+    setError(null);
+    try {
+      await login(username(), password());
+    } catch (err) {
+      setError(String(err));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  return (
+    <section class="max-w-xs mx-auto mt-16 bg-bg-surface border border-border-default">
+      <div class="px-3 py-2 bg-bg-surface-raised border-b border-border-subtle">
+        <h2 class="text-sm font-medium text-text-default">Sign in</h2>
+      </div>
+      <form class="p-3 space-y-3" onSubmit={submit}>
+        <Show when={error()}>
+          <Alert type="error">{error()}</Alert>
+        </Show>
+        <LabeledInput label="Username" value={username()} onChange={setUsername} autocomplete="username" />
+        <LabeledInput label="Password" type="password" value={password()} onChange={setPassword} autocomplete="current-password" />
+        <div class="flex justify-end">
+          <Button type="submit" disabled={busy() || !username() || !password()}>
+            {busy() ? "signing in..." : "sign in"}
+          </Button>
+        </div>
+      </form>
+    </section>
+  );
+}
@@ -1,4 +1,7 @@
-import { JSX } from "solid-js";
+import { JSX, createResource, createSignal, createEffect, For, Show } from "solid-js";
+import { auditClient, authClient, settingsClient } from "@/api/client";
+import { authEnabled, canChange, hasRole, session } from "@/stores/auth";
+import type { ServerSettings as ServerSettingsMsg } from "%/v1/settings_pb";
 import {
   uiSettings,
   setShowRawBytes,
@@ -9,7 +12,8 @@ import {
   type ByteUnit,
   type ByteBase,
 } from "@/stores/ui";
-import { ToggleGroup, NumberInput } from "@/components/ui";
+import { ToggleGroup, NumberInput, Button, Alert, LabeledInput } from "@/components/ui";
+import { formatRelativeTime } from "@/lib/utils";
 
 // Setting row with label, description, and control
 function SettingRow(props: { label: string; description: string; children: JSX.Element }) {
@@ -24,6 +28,313 @@ function SettingRow(props: { label: string; description: string; children: JSX.E
   );
 }
 
+// Server settings in the units the form edits them in
+interface ServerDraft {
+  sampleTargetK: number;
+  collectIntervalMin: number;
+  scrubMaxAgeDays: number;
+  slackPercent: number;
+  minSlackGiB: number;
+  imbalancePercent: number;
+}
+
+function toDraft(s: ServerSettingsMsg): ServerDraft {
+  return {
+    sampleTargetK: Number(s.defaultSampleTarget) / 1000,
+    collectIntervalMin: Math.round(Number(s.collectIntervalSeconds) / 60),
+    scrubMaxAgeDays: s.scrubMaxAgeDays,
+    slackPercent: s.slackPercent,
+    minSlackGiB: Math.round(Number(s.minSlackBytes) / 2 ** 30),
+    imbalancePercent: s.imbalancePercent,
+  };
+}
+
+// Stored on the server, so they apply to every client and to background work
+function ServerSettings() {
+  const [settings, { mutate }] = createResource(() => settingsClient.getSettings({}));
+  const [draft, setDraft] = createSignal<ServerDraft | null>(null);
+  const [saving, setSaving] = createSignal(false);
+  const [error, setError] = createSignal<string | null>(null);
+
+  createEffect(() => {
+    const s = settings()?.settings;
+    if (s) setDraft(toDraft(s));
+  });
+
+  const set = <K extends keyof ServerDraft>(key: K, value: ServerDraft[K]) => {
+    const d = draft();
+    if (d) setDraft({ ...d, [key]: value });
+  };
+
+  // Only changed fields are sent; zero leaves a setting as it is
+  const apply = async (resetFields: string[] = []) => {
+    const d = draft();
+    const cur = settings()?.settings;
+    if (!d || !cur) return;
+    const c = toDraft(cur);
+    const changed = (key: keyof ServerDraft) => d[key] !== c[key];
+    setSaving(true);
+    setError(null);
+    try {
+      const res = await settingsClient.updateSettings({
+        settings: {
+          defaultSampleTarget: changed("sampleTargetK") ? BigInt(Math.round(d.sampleTargetK * 1000)) : 0n,
+          collectIntervalSeconds: changed("collectIntervalMin") ? BigInt(d.collectIntervalMin * 60) : 0n,
+          scrubMaxAgeDays: changed("scrubMaxAgeDays") ? d.scrubMaxAgeDays : 0,
+          slackPercent: changed("slackPercent") ? d.slackPercent : 0,
+          minSlackBytes: changed("minSlackGiB") ? BigInt(d.minSlackGiB) * 2n ** 30n : 0n,
+          imbalancePercent: changed("imbalancePercent") ? d.imbalancePercent : 0,
+        },
+        resetFields,
+      });
+      const prev = settings();
+      if (prev && res.settings) {
+        mutate({ ...prev, settings: res.settings, overridden: res.overridden });
+      }
+    } catch (e) {
+      setError(String(e));
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const overridden = () => settings()?.overridden ?? [];
+  const defaults = () => {
+    const d = settings()?.defaults;
+    return d ? toDraft(d) : null;
+  };
+  const defaultNote = (key: keyof ServerDraft, suffix: string) => {
+    const d = defaults();
+    return d ? ` (default ${d[key]} ${suffix})` : "";
+  };
+
+  return (
+    <section class="bg-bg-surface border border-border-default">
+      <div class="px-3 py-2 bg-bg-surface-raised border-b border-border-subtle">
+        <h2 class="text-sm font-medium text-text-default">Server Settings</h2>
+        <p class="text-xs text-text-tertiary">Saved on the server and applied to background work</p>
+      </div>
+      <div class="p-3 space-y-4">
+        <Show when={settings.error}>
+          <Alert type="error">{String(settings.error)}</Alert>
+        </Show>
+        <Show when={error()}>
+          <Alert type="error">{error()}</Alert>
+        </Show>
+        <Show when={draft()}>
+          {(d) => (
+            <>
+              <SettingRow
+                label="Sample Target"
+                description={`Samples a usage scan takes before stopping${defaultNote("sampleTargetK", "k")}`}
+              >
+                <NumberInput value={d().sampleTargetK} onChange={(v) => set("sampleTargetK", v)} min={1} max={1000000} step={100} suffix="k" />
+              </SettingRow>
+              <SettingRow
+                label="Collect Interval"
+                description={`How often usage history is recorded${defaultNote("collectIntervalMin", "min")}`}
+              >
+                <NumberInput value={d().collectIntervalMin} onChange={(v) => set("collectIntervalMin", v)} min={1} max={1440} suffix="min" />
+              </SettingRow>
+              <SettingRow
+                label="Scrub Max Age"
+                description={`Diagnostics warn when the last scrub is older${defaultNote("scrubMaxAgeDays", "days")}`}
+              >
+                <NumberInput value={d().scrubMaxAgeDays} onChange={(v) => set("scrubMaxAgeDays", v)} min={1} max={3650} suffix="days" />
+              </SettingRow>
+              <SettingRow
+                label="Chunk Slack"
+                description={`Diagnostics warn when unused space inside data chunks exceeds this${defaultNote("slackPercent", "%")}`}
+              >
+                <NumberInput value={d().slackPercent} onChange={(v) => set("slackPercent", v)} min={1} max={100} suffix="%" />
+              </SettingRow>
+              <SettingRow
+                label="Min Slack"
+                description={`Slack below this size is ignored${defaultNote("minSlackGiB", "GiB")}`}
+              >
+                <NumberInput value={d().minSlackGiB} onChange={(v) => set("minSlackGiB", v)} min={1} max={1000000} suffix="GiB" />
+              </SettingRow>
+              <SettingRow
+                label="Device Imbalance"
+                description={`Diagnostics warn when device allocation ratios differ by more${defaultNote("imbalancePercent", "%")}`}
+              >
+                <NumberInput value={d().imbalancePercent} onChange={(v) => set("imbalancePercent", v)} min={1} max={100} suffix="%" />
+              </SettingRow>
+              <Show when={canChange("admin")}>
+              <div class="flex justify-end gap-2">
+                <Show when={overridden().length > 0}>
+                  <Button variant="ghost" disabled={saving()} onClick={() => apply(overridden())}>
+                    reset to defaults
+                  </Button>
+                </Show>
+                <Button disabled={saving()} onClick={() => apply()}>
+                  {saving() ? "saving..." : "save"}
+                </Button>
+              </div>
+              </Show>
+            </>
+          )}
+        </Show>
+      </div>
+    </section>
+  );
+}
+
+const ROLES = ["viewer", "operator", "admin"];
+
+// Local users who sign in to the web UI. Tokens and proxy users are set in
+// the config file instead.
+function Users() {
+  const [users, { refetch }] = createResource(() => authClient.listUsers({}));
+  const [username, setUsername] = createSignal("");
+  const [password, setPassword] = createSignal("");
+  const [role, setRole] = createSignal("viewer");
+  const [error, setError] = createSignal<string | null>(null);
+
+  const run = async (fn: () => Promise<unknown>) => {
+    setError(null);
+    try {
+      await fn();
+      refetch();
+    } catch (e) {
+      setError(String(e));
+    }
+  };
+
+  const add = () =>
+    run(async () => {
+      await authClient.createUser({ username: username(), password: password(), role: role() });
+      setUsername("");
+      setPassword("");
+    });
+
+  const resetPassword = (name: string) => {
+    const pw = prompt(`New password for ${name}`);
+    if (pw) run(() => authClient.updateUser({ username: name, password: pw }));
+  };
+
+  return (
+    <section class="bg-bg-surface border border-border-default">
+      <div class="px-3 py-2 bg-bg-surface-raised border-b border-border-subtle">
+        <h2 class="text-sm font-medium text-text-default">Users</h2>
+        <p class="text-xs text-text-tertiary">Who can sign in. Viewers read, operators run maintenance, admins change settings and users</p>
+      </div>
+      <div class="p-3 space-y-4">
+        <Show when={users.error}>
+          <Alert type="error">{String(users.error)}</Alert>
+        </Show>
+        <Show when={error()}>
+          <Alert type="error">{error()}</Alert>
+        </Show>
+        <table class="w-full text-xs">
+          <tbody>
+            <For each={users()?.users ?? []}>
+              {(u) => (
+                <tr class="border-b border-border-subtle">
+                  <td class="py-1 text-text-default">
+                    {u.username}
+                    <Show when={u.username === session()?.username}>
+                      <span class="text-text-muted"> (you)</span>
+                    </Show>
+                  </td>
+                  <td class="py-1">
+                    <ToggleGroup
+                      options={ROLES.map((r) => ({ label: r, value: r }))}
+                      value={u.role}
+                      onChange={(r) => run(() => authClient.updateUser({ username: u.username, role: r }))}
+                    />
+                  </td>
+                  <td class="py-1 text-right">
+                    <Button variant="ghost" onClick={() => resetPassword(u.username)}>
+                      set password
+                    </Button>
+                    <Button
+                      variant="ghost"
+                      onClick={() => confirm(`Remove ${u.username}?`) && run(() => authClient.deleteUser({ username: u.username }))}
+                    >
+                      remove
+                    </Button>
+                  </td>
+                </tr>
+              )}
+            </For>
+          </tbody>
+        </table>
+        <div class="flex items-end gap-2">
+          <LabeledInput label="Username" value={username()} onChange={setUsername} class="flex-1" />
+          <LabeledInput label="Password" type="password" value={password()} onChange={setPassword} class="flex-1" autocomplete="new-password" />
+          <ToggleGroup options={ROLES.map((r) => ({ label: r, value: r }))} value={role()} onChange={setRole} />
+          <Button disabled={!username() || !password()} onClick={add}>
+            add
+          </Button>
+        </div>
+      </div>
+    </section>
+  );
+}
+
+// Recent mutating calls: who started or cancelled what, and whether it worked
+function AuditLog() {
+  const [events] = createResource(() => auditClient.listAuditEvents({ limit: 50 }));
+
+  return (
+    <section class="bg-bg-surface border border-border-default">
+      <div class="px-3 py-2 bg-bg-surface-raised border-b border-border-subtle flex items-baseline">
+        <div class="flex-1">
+          <h2 class="text-sm font-medium text-text-default">Audit Log</h2>
+          <p class="text-xs text-text-tertiary">The last 50 changes made through the API</p>
+        </div>
+        <a href="/audit/export" class="text-xs text-text-tertiary hover:text-text-secondary" download>
+          export jsonl
+        </a>
+      </div>
+      <div class="p-3">
+        <Show when={events.error}>
+          <Alert type="error">{String(events.error)}</Alert>
+        </Show>
+        <table class="w-full text-xs">
+          <tbody>
+            <For each={events()?.events ?? []}>
+              {(e) => (
+                <tr class="border-b border-border-subtle align-top" title={e.requestJson}>
+                  <td class="py-1 pr-2 text-text-muted whitespace-nowrap">
+                    {formatRelativeTime(new Date(Number(e.timestamp) * 1000))}
+                  </td>
+                  <td class="py-1 pr-2 text-text-default">{e.actor}</td>
+                  <td class="py-1 pr-2 text-text-secondary">{e.procedure.split("/").pop()}</td>
+                  <td class="py-1 pr-2 text-text-tertiary font-mono">{e.filesystem}</td>
+                  <td class={e.outcome === "ok" ? "py-1 text-text-tertiary" : "py-1 text-error"}>
+                    {e.outcome === "ok" ? "ok" : `${e.outcome}: ${e.error}`}
+                  </td>
+                </tr>
+              )}
+            </For>
+          </tbody>
+        </table>
+      </div>
+    </section>
+  );
+}
+
+// A tar.gz to attach to bug reports, built by the server on download
+function SupportBundle() {
+  return (
+    <section class="bg-bg-surface border border-border-default">
+      <div class="px-3 py-2 bg-bg-surface-raised border-b border-border-subtle flex items-baseline">
+        <div class="flex-1">
+          <h2 class="text-sm font-medium text-text-default">Support Bundle</h2>
+          <p class="text-xs text-text-tertiary">
+            Version, redacted config, filesystems, allocation, recent scrubs, balances and errors, and a goroutine dump
+          </p>
+        </div>
+        <a href="/support/bundle" class="text-xs text-text-tertiary hover:text-text-secondary" download>
+          download tar.gz
+        </a>
+      </div>
+    </section>
+  );
+}
+
 export default function Settings() {
   return (
     <div class="space-y-4">
@@ -93,6 +404,20 @@ export default function Settings() {
           </SettingRow>
         </div>
       </section>
+
+      <ServerSettings />
+
+      <Show when={authEnabled() && canChange("admin")}>
+        <Users />
+      </Show>
+
+      <Show when={hasRole("operator")}>
+        <AuditLog />
+      </Show>
+
+      <Show when={hasRole("admin")}>
+        <SupportBundle />
+      </Show>
     </div>
   );
 }
@@ -1,10 +1,11 @@
 import { Show, createMemo, createSignal, createResource, createEffect, onMount, onCleanup, Suspense } from "solid-js";
 import type { TrackedFilesystem } from "%/v1/filesystem_pb";
 import type { ScrubProgress } from "%/v1/scrub_pb";
-import type { BalanceProgress } from "%/v1/balance_pb";
+import type { BalanceProgress, StaleProfiles } from "%/v1/balance_pb";
 import { scrubClient, balanceClient } from "@/api/client";
 import { formatNumber } from "@/lib/utils";
 import { uiSettings } from "@/stores/ui";
+import { canChange } from "@/stores/auth";
 import { Button, ProgressBar, ErrorSpan, WarnSpan, StatRow, FormattedBytes } from "@/components/ui";
 import { ScrubConfirmModal, type ScrubOptions } from "@/components/ScrubConfirmModal";
 import { BalanceConfirmModal, type BalanceOptions } from "@/components/BalanceConfirmModal";
@@ -23,12 +24,17 @@ export function MaintenanceTab(props: {
     () => props.fs.path,
     (path) => balanceClient.getBalanceStatus({ devicePath: path })
   );
+  const [profileData, { refetch: refetchProfiles }] = createResource(
+    () => props.fs.path,
+    (path) => balanceClient.getProfileStatus({ devicePath: path })
+  );
 
   // Listen for refresh events
   onMount(() => {
     const handler = () => {
       refetchScrub();
       refetchBalance();
+      refetchProfiles();
     };
     window.addEventListener("fs-refresh", handler);
     onCleanup(() => window.removeEventListener("fs-refresh", handler));
@@ -151,6 +157,21 @@ export function MaintenanceTab(props: {
     }
   };
 
+  const finishConversion = async (targets: Record<string, string>) => {
+    try {
+      await balanceClient.finishConversion({
+        devicePath: props.fs.path,
+        dataTarget: targets.Data,
+        metadataTarget: targets.Metadata,
+        systemTarget: targets.System,
+      });
+      startPolling();
+      refetchProfiles();
+    } catch (e) {
+      props.setError(String(e));
+    }
+  };
+
   const cancelBalance = async () => {
     try {
       await balanceClient.cancelBalance({ devicePath: props.fs.path });
@@ -178,6 +199,8 @@ export function MaintenanceTab(props: {
         balanceRunning={balanceRunning()}
         onStart={startBalance}
         onCancel={cancelBalance}
+        staleProfiles={profileData.latest?.stale ?? []}
+        onFinishConversion={finishConversion}
         isPolling={isPolling()}
         pollingCountdown={pollingCountdown()}
       />
@@ -270,10 +293,10 @@ function ScrubSection(props: {
           </Show>
         </div>
         <div class="flex space-x-1">
-          <Show when={props.scrubRunning}>
+          <Show when={props.scrubRunning && canChange()}>
             <Button variant="danger" onClick={props.onCancel}>cancel</Button>
           </Show>
-          <Show when={!props.scrubRunning}>
+          <Show when={!props.scrubRunning && canChange()}>
             <Button variant="primary" onClick={() => { setInitialReadonly(false); setScrubModalOpen(true); }}>scrub</Button>
             <Button variant="soft" title="read-only (no repair)" onClick={() => { setInitialReadonly(true); setScrubModalOpen(true); }}>r/o</Button>
           </Show>
@@ -418,6 +441,8 @@ function BalanceSection(props: {
   balanceRunning: boolean;
   onStart: (opts: BalanceOptions) => void;
   onCancel: () => void;
+  staleProfiles: StaleProfiles[];
+  onFinishConversion: (targets: Record<string, string>) => void;
   isPolling: boolean;
   pollingCountdown: number;
 }) {
@@ -460,10 +485,10 @@ function BalanceSection(props: {
           </Show>
         </div>
         <div class="flex space-x-1">
-          <Show when={props.balanceRunning}>
+          <Show when={props.balanceRunning && canChange()}>
             <Button variant="danger" onClick={props.onCancel}>cancel</Button>
           </Show>
-          <Show when={!props.balanceRunning}>
+          <Show when={!props.balanceRunning && canChange()}>
             <Button variant="primary" onClick={() => setBalanceModalOpen(true)}>balance</Button>
           </Show>
         </div>
@@ -486,6 +511,16 @@ function BalanceSection(props: {
         </Show>
       </Suspense>
 
+      {/* Leftover profiles from an interrupted conversion */}
+      <Show when={props.staleProfiles.length > 0}>
+        <StaleProfilesNotice
+          stale={props.staleProfiles}
+          balanceRunning={props.balanceRunning}
+          showRawBytes={showRawBytes()}
+          onFinish={props.onFinishConversion}
+        />
+      </Show>
+
       {/* Balance confirmation modal */}
       <BalanceConfirmModal
         open={balanceModalOpen()}
@@ -499,6 +534,67 @@ function BalanceSection(props: {
   );
 }
 
+function StaleProfilesNotice(props: {
+  stale: StaleProfiles[];
+  balanceRunning: boolean;
+  showRawBytes: boolean;
+  onFinish: (targets: Record<string, string>) => void;
+}) {
+  // Without a paused balance the target is only the most redundant profile
+  // present, which would undo an interrupted downgrade, so ask instead
+  const [picked, setPicked] = createSignal<Record<string, string>>({});
+  const guessed = () => props.stale.filter((sp) => sp.targetSource === "kernel");
+  const ready = () => guessed().every((sp) => picked()[sp.type]);
+  const profiles = (sp: StaleProfiles) => [
+    ...(sp.current ? [sp.current.profile] : []),
+    ...sp.stale.map((u) => u.profile),
+  ];
+
+  return (
+    <div class="mt-2 bg-warning-subtle p-2 text-xs">
+      <div class="flex items-center justify-between mb-1">
+        <span class="text-warning">mixed profiles (interrupted conversion?)</span>
+        <Show when={!props.balanceRunning && canChange()}>
+          <Button variant="primary" disabled={!ready()} onClick={() => props.onFinish(picked())}>
+            finish conversion
+          </Button>
+        </Show>
+      </div>
+      <table class="w-full">
+        <tbody>
+          {props.stale.map((sp) => (
+            <StatRow label={sp.type.toLowerCase()}>
+              <span class="font-mono">
+                {sp.stale.map((u) => (
+                  <span class="mr-2">
+                    {u.profile} <span class="text-text-muted">{formatNumber(u.chunks)} chunks,</span>{" "}
+                    <FormattedBytes bytes={u.bytes} showRaw={props.showRawBytes} />
+                  </span>
+                ))}
+                <Show
+                  when={sp.targetSource === "kernel"}
+                  fallback={<span class="text-text-muted">→ {sp.target}</span>}
+                >
+                  <span class="text-text-muted">→ convert to</span>
+                  {profiles(sp).map((p) => (
+                    <Button
+                      variant={picked()[sp.type] === p ? "soft" : "ghost"}
+                      class="ml-1"
+                      onClick={() => setPicked({ ...picked(), [sp.type]: p })}
+                    >
+                      {p}
+                    </Button>
+                  ))}
+                </Show>
+              </span>
+            </StatRow>
+          ))}
+        </tbody>
+      </table>
+    </div>
+  );
+}
+
 function BalanceStats(props: {
   balanceStatus: BalanceProgress | undefined;
   balanceRunning: boolean;
@@ -3,6 +3,7 @@ import type { TrackedFilesystem } from "%/v1/filesystem_pb";
 import { filesystemClient } from "@/api/client";
 import { Card, Button, Alert, LabeledInput } from "@/components/ui";
 import { formatNumber } from "@/lib/utils";
+import { canChange } from "@/stores/auth";
 
 export function SettingsTab(props: {
   fs: TrackedFilesystem;
@@ -85,7 +86,7 @@ export function SettingsTab(props: {
       <div>
         <Button
           variant="primary"
-          disabled={saving() || !hasChanges()}
+          disabled={saving() || !hasChanges() || !canChange()}
           onClick={handleSave}
         >
           {saving() ? "saving..." : "save"}
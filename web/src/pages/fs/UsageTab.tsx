@@ -3,6 +3,7 @@ import { createStore, reconcile, produce } from "solid-js/store";
 import type { TrackedFilesystem } from "%/v1/filesystem_pb";
 import { formatBytes } from "@/lib/utils";
 import { uiSettings } from "@/stores/ui";
+import { canChange } from "@/stores/auth";
 import { usageClient } from "@/api/client";
 
 // Circular countdown indicator
@@ -230,6 +231,7 @@ export function UsageTab(props: { fs: TrackedFilesystem }) {
     currentPath: string;
     sessionId: string;
     runningTimeSeconds: bigint;
+    targetSamples: number;
   }>({
     sampleCount: 0,
     totalSize: 0n,
@@ -239,6 +241,7 @@ export function UsageTab(props: { fs: TrackedFilesystem }) {
     currentPath: "",
     sessionId: "",
     runningTimeSeconds: 0n,
+    targetSamples: 0,
   });
 
   // Recent paths for animation
@@ -276,6 +279,7 @@ export function UsageTab(props: { fs: TrackedFilesystem }) {
         currentPath: s.progress?.currentPath ?? "",
         sessionId: s.sessionId ?? "",
         runningTimeSeconds: s.progress?.runningTimeSeconds ?? 0n,
+        targetSamples: Number(s.progress?.targetSamples ?? 0n),
       };
       // Only update if values actually changed
       const prev = cachedStatus();
@@ -285,7 +289,8 @@ export function UsageTab(props: { fs: TrackedFilesystem }) {
         newValues.hasSession !== prev.hasSession ||
         newValues.sessionId !== prev.sessionId ||
         newValues.totalSize !== prev.totalSize ||
-        newValues.runningTimeSeconds !== prev.runningTimeSeconds
+        newValues.runningTimeSeconds !== prev.runningTimeSeconds ||
+        newValues.targetSamples !== prev.targetSamples
       ) {
         setCachedStatus(newValues);
       }
@@ -305,6 +310,7 @@ export function UsageTab(props: { fs: TrackedFilesystem }) {
   const isRunning = () => cachedStatus().isRunning;
   const hasSession = () => cachedStatus().hasSession;
   const samplesPerSec = () => cachedStatus().samplesPerSec;
+  const targetSamples = () => cachedStatus().targetSamples;
 
   // Animated current path - cycles through recent paths
   const currentSamplingPath = () => {
@@ -522,6 +528,9 @@ export function UsageTab(props: { fs: TrackedFilesystem }) {
               <div>
                 <span class="text-text-tertiary">samples </span>
                 <span class="font-mono text-text-default">{sampleCount().toLocaleString()}</span>
+                <Show when={isRunning() && targetSamples() > 0}>
+                  <span class="text-text-tertiary"> / {targetSamples().toLocaleString()}</span>
+                </Show>
               </div>
               <div>
                 <span class="text-text-tertiary">size </span>
@@ -562,7 +571,7 @@ export function UsageTab(props: { fs: TrackedFilesystem }) {
             <Show when={initialLoading()}>
               <span class="text-xs text-text-muted animate-pulse">loading...</span>
             </Show>
-            <Show when={!initialLoading()}>
+            <Show when={!initialLoading() && canChange()}>
               <Show
                 when={isRunning()}
                 fallback={
@@ -1,5 +1,11 @@
 import { FragMapViz } from "@/components/FragMapViz";
+import { FileFragScan } from "@/components/FileFragScan";
 
 export function VisualizeTab(props: { fsPath: string }) {
-  return <FragMapViz fsPath={props.fsPath} />;
+  return (
+    <div class="space-y-2">
+      <FragMapViz fsPath={props.fsPath} />
+      <FileFragScan fsPath={props.fsPath} />
+    </div>
+  );
 }
@@ -0,0 +1,48 @@
+import { createSignal } from "solid-js";
+import { authClient, healthClient } from "@/api/client";
+import type { Session } from "%/v1/auth_pb";
+
+const ROLE_RANK: Record<string, number> = { viewer: 1, operator: 2, admin: 3 };
+
+const [authEnabled, setAuthEnabled] = createSignal(false);
+const [session, setSession] = createSignal<Session | null>(null);
+const [checked, setChecked] = createSignal(false);
+const [readOnly, setReadOnly] = createSignal(false);
+
+export { authEnabled, session, checked as sessionChecked, readOnly };
+
+// Ask the server who we are. With auth disabled everyone is an admin.
+export async function refreshSession() {
+  try {
+    const [res, health] = await Promise.all([authClient.getSession({}), healthClient.check({})]);
+    setAuthEnabled(res.authEnabled);
+    setSession(res.session ?? null);
+    setReadOnly(health.readOnly);
+  } finally {
+    setChecked(true);
+  }
+}
+
+export async function login(username: string, password: string) {
+  const res = await authClient.login({ username, password });
+  setSession(res.session ?? null);
+}
+
+export async function logout() {
+  await authClient.logout({});
+  setSession(null);
+}
+
+// Whether the signed in user has at least the given role
+export function hasRole(role: "viewer" | "operator" | "admin"): boolean {
+  const s = session();
+  return !!s && (ROLE_RANK[s.role] ?? 0) >= ROLE_RANK[role];
+}
+
+// Whether the signed in user may change things. A read-only server refuses
+// every change whatever the role.
+export function canChange(role: "operator" | "admin" = "operator"): boolean {
+  return !readOnly() && hasRole(role);
+}
+
+window.addEventListener("unauthenticated", () => setSession(null));